@@ -0,0 +1,123 @@
+package log
+
+import (
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// benchmarkCases là các dạng log entry điển hình được dùng để đo hiệu suất.
+var benchmarkCases = []struct {
+	name    string
+	level   handler.Level
+	message string
+	args    []interface{}
+}{
+	{"Short", handler.InfoLevel, "request completed", nil},
+	{"Formatted", handler.InfoLevel, "user %s logged in from %s after %d attempts", []interface{}{"john_doe", "192.168.1.100", 3}},
+	{"Fields", handler.InfoLevel, "request completed", []interface{}{Any("method", "GET"), Any("path", "/api/v1/users"), Any("status", 200), Any("bytes", 1024)}},
+	{"Long", handler.ErrorLevel, "failed to process payment for order ORD-2024-000123: upstream gateway returned 502 Bad Gateway after 3 retries with exponential backoff", nil},
+}
+
+// BenchmarkHandler đo độ trễ và số lần cấp phát bộ nhớ cho mỗi entry của một handler.
+//
+// Hàm này chạy một tập sub-benchmark với các dạng entry điển hình (ngắn, có định dạng,
+// có field, dài) và một sub-benchmark song song, giúp ứng dụng đo hiệu suất của chính
+// handler stack mình sử dụng để lập kế hoạch dung lượng. Kết quả ns/op là độ trễ cho mỗi entry.
+//
+// Tham số:
+//   - b: *testing.B - benchmark hiện tại
+//   - h: handler.Handler - handler cần đo
+//
+// Ví dụ:
+//
+//	func BenchmarkAppHandler(b *testing.B) {
+//	    fileHandler, _ := handler.NewFileHandler(b.TempDir()+"/bench.log", 0)
+//	    defer fileHandler.Close()
+//	    log.BenchmarkHandler(b, fileHandler)
+//	}
+func BenchmarkHandler(b *testing.B, h handler.Handler) {
+	b.Helper()
+
+	for _, bc := range benchmarkCases {
+		bc := bc
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := h.Log(bc.level, bc.message, bc.args...); err != nil {
+					b.Fatalf("handler.Log() returned error: %v", err)
+				}
+			}
+		})
+	}
+
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := h.Log(handler.InfoLevel, "request completed"); err != nil {
+					b.Errorf("handler.Log() returned error: %v", err)
+					return
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkLogger đo độ trễ và số lần cấp phát bộ nhớ cho mỗi entry của toàn bộ
+// pipeline logger, bao gồm lọc cấp độ, định dạng thông điệp và phân phối đến các handler.
+//
+// Tham số:
+//   - b: *testing.B - benchmark hiện tại
+//   - logger: Logger - logger cần đo (thường lấy từ Manager.GetLogger)
+//
+// Ví dụ:
+//
+//	func BenchmarkAppLogger(b *testing.B) {
+//	    manager := log.NewManager(productionConfig)
+//	    defer manager.Close()
+//	    log.BenchmarkLogger(b, manager.GetLogger("Bench"))
+//	}
+func BenchmarkLogger(b *testing.B, logger Logger) {
+	b.Helper()
+
+	logFuncs := map[handler.Level]func(string, ...interface{}){
+		handler.DebugLevel:   logger.Debug,
+		handler.InfoLevel:    logger.Info,
+		handler.WarningLevel: logger.Warning,
+		handler.ErrorLevel:   logger.Error,
+		handler.FatalLevel:   logger.Fatal,
+	}
+
+	for _, bc := range benchmarkCases {
+		bc := bc
+		logFunc := logFuncs[bc.level]
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logFunc(bc.message, bc.args...)
+			}
+		})
+	}
+
+	b.Run("Filtered", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Debug("debug details %d", i)
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				logger.Info("request completed")
+			}
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// discardHandler bỏ qua mọi log entry và đếm số lần được gọi, an toàn khi dùng đồng thời
+type discardHandler struct {
+	calls atomic.Int64
+}
+
+func (d *discardHandler) Log(level handler.Level, message string, args ...interface{}) error {
+	d.calls.Add(1)
+	return nil
+}
+
+func (d *discardHandler) Close() error {
+	return nil
+}
+
+func TestBenchmarkHandler(t *testing.T) {
+	h := &discardHandler{}
+	result := testing.Benchmark(func(b *testing.B) {
+		BenchmarkHandler(b, h)
+	})
+
+	if h.calls.Load() == 0 {
+		t.Error("BenchmarkHandler không gọi handler.Log()")
+	}
+	if result.N == 0 {
+		t.Error("BenchmarkHandler không chạy iteration nào")
+	}
+}
+
+func TestBenchmarkLogger(t *testing.T) {
+	h := &discardHandler{}
+	l := NewLogger("Bench")
+	l.AddHandler(TestHandlerType, h)
+
+	testing.Benchmark(func(b *testing.B) {
+		BenchmarkLogger(b, l)
+	})
+
+	if h.calls.Load() == 0 {
+		t.Error("BenchmarkLogger không ghi log qua handler")
+	}
+}
+
+func BenchmarkHandler_Discard(b *testing.B) {
+	BenchmarkHandler(b, &discardHandler{})
+}
+
+func BenchmarkLogger_AnyFields(b *testing.B) {
+	l := NewLogger("Bench")
+	l.AddHandler(TestHandlerType, &discardHandler{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("request", Any("method", "GET"), Any("status", 200), Any("latency", time.Millisecond))
+	}
+}
+
+func BenchmarkLogger_TypedFields(b *testing.B) {
+	l := NewLogger("Bench")
+	l.AddHandler(TestHandlerType, &discardHandler{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.LogFields(handler.InfoLevel, "request", String("method", "GET"), Int("status", 200), Duration("latency", time.Millisecond))
+	}
+}
+
+func BenchmarkLogger_Parallel(b *testing.B) {
+	l := NewLogger("Bench")
+	l.AddHandler(TestHandlerType, &discardHandler{})
+	l.AddHandler(HandlerTypeConsole, &discardHandler{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info("request handled")
+		}
+	})
+}
+
+func BenchmarkLogger_Filtered(b *testing.B) {
+	l := NewLogger("Bench")
+	l.AddHandler(TestHandlerType, &discardHandler{})
+	l.SetMinLevel(handler.InfoLevel)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Debug("cache miss")
+		}
+	})
+}
+
+func BenchmarkLogger_File(b *testing.B) {
+	h, err := handler.NewFileHandler(filepath.Join(b.TempDir(), "bench.log"), 0)
+	if err != nil {
+		b.Fatalf("NewFileHandler() error = %v", err)
+	}
+	l := NewLogger("Bench")
+	l.AddHandler(HandlerTypeFile, h)
+	defer l.Close()
+
+	BenchmarkLogger(b, l)
+}
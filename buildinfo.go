@@ -0,0 +1,91 @@
+package log
+
+import (
+	"runtime/debug"
+)
+
+// Các key của field được gắn bởi BuildInfoHook.
+const (
+	FieldVersion   = "version"    // Phiên bản của ứng dụng
+	FieldCommit    = "commit"     // Commit VCS của bản build
+	FieldBuildDate = "build_date" // Thời điểm của commit hoặc của bản build
+)
+
+// BuildInfo mô tả bản build của ứng dụng, dùng để liên kết log với bản phát hành.
+type BuildInfo struct {
+	Version   string // Phiên bản (VD: "v1.4.2")
+	Commit    string // Commit VCS (VD: "9f2c1e0")
+	BuildDate string // Thời điểm build, nên theo RFC 3339
+}
+
+// ReadBuildInfo đọc thông tin build được Go nhúng vào binary (debug.ReadBuildInfo): phiên bản của
+// main module, vcs.revision và vcs.time. Phiên bản "(devel)" của bản build cục bộ được bỏ qua.
+//
+// Trả về:
+//   - BuildInfo: thông tin build, các trường rỗng khi binary không có thông tin tương ứng
+//
+// Ví dụ:
+//
+//	info := log.ReadBuildInfo()
+//	if info.Version == "" {
+//	    info.Version = version // Giá trị được gán qua -ldflags "-X main.version=..."
+//	}
+func ReadBuildInfo() BuildInfo {
+	var info BuildInfo
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.BuildDate = setting.Value
+		}
+	}
+	return info
+}
+
+// Fields trả về các field version, commit và build_date, bỏ qua trường rỗng.
+//
+// Trả về:
+//   - []Field: các field của thông tin build
+func (b BuildInfo) Fields() []Field {
+	var fields []Field
+	for _, f := range []Field{String(FieldVersion, b.Version), String(FieldCommit, b.Commit), String(FieldBuildDate, b.BuildDate)} {
+		if f.Str != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// BuildInfoHook tạo một Hook gắn version, commit và build_date vào mọi entry, trừ entry đã có
+// field cùng key.
+//
+// Tham số:
+//   - info: BuildInfo - thông tin build, thường từ ReadBuildInfo hoặc được gán qua -ldflags
+//
+// Trả về:
+//   - Hook: hook gắn thông tin build, nil nếu info không có trường nào
+//
+// Ví dụ:
+//
+//	// Phiên bản được gán khi build: go build -ldflags "-X main.version=v1.4.2 -X main.commit=9f2c1e0"
+//	manager.AddHook(log.BuildInfoHook(log.BuildInfo{Version: version, Commit: commit}))
+//	logger.Info("Started") // [App] Started version=v1.4.2 commit=9f2c1e0
+func BuildInfoHook(info BuildInfo) Hook {
+	fields := info.Fields()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return func(entry *Entry) error {
+		entry.Fields = withStaticFields(entry.Fields, fields)
+		return nil
+	}
+}
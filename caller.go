@@ -0,0 +1,57 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// FieldCaller là key của field chứa vị trí gọi log khi thông tin caller được bật.
+const FieldCaller = "caller"
+
+// withCaller thêm field caller vào args nếu logger được bật thông tin caller.
+//
+// Tham số:
+//   - args: []interface{} - tham số của lời gọi log
+//   - depth: int - số stack frame của logger nằm giữa withCaller và nơi gọi log
+//
+// Trả về:
+//   - []interface{}: args kèm field caller (nếu được bật)
+func (l *logger) withCaller(args []interface{}, depth int) []interface{} {
+	// Đọc từ snapshot để đường ghi log không cần lock
+	snapshot := l.snapshot.Load().(*loggerSnapshot)
+	if !snapshot.caller {
+		return args
+	}
+	_, file, line, ok := runtime.Caller(depth + snapshot.callerSkip + 1)
+	if !ok {
+		return args
+	}
+	return append(args, Any(FieldCaller, shortCaller(file, line)))
+}
+
+// setCaller thay đổi thiết lập caller của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - enabled: bool - bật/tắt thông tin caller
+//   - skip: int - số stack frame bổ sung cần bỏ qua
+func (l *logger) setCaller(enabled bool, skip int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.caller = enabled
+	l.callerSkip = skip
+	l.publish()
+}
+
+// shortCaller rút gọn đường dẫn file thành thư mục cha và tên file, VD: service/user.go:42.
+//
+// runtime.Caller luôn trả về đường dẫn phân tách bằng "/" trên mọi hệ điều hành.
+func shortCaller(file string, line int) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(line)
+}
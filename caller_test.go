@@ -0,0 +1,104 @@
+package log
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// nextLine trả về vị trí của dòng ngay sau dòng gọi nextLine, dạng thư mục/file:dòng
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return shortCaller(file, line+1)
+}
+
+func TestLogger_WithCaller(t *testing.T) {
+	l := NewLogger("UserService", WithCaller())
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	want := nextLine()
+	l.Info("User created")
+	if !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("Info() nên ghi kèm vị trí gọi %q, got %q", want, h.entry.Message)
+	}
+
+	want = nextLine()
+	l.LogAt(time.Time{}, handler.WarningLevel, "late")
+	if !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("LogAt() nên ghi kèm vị trí gọi %q, got %q", want, h.entry.Message)
+	}
+}
+
+func TestLogger_WithCallerSkip(t *testing.T) {
+	l := NewLogger("App", WithCallerSkip(1))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	logError := func(msg string) { l.Error(msg) }
+	want := nextLine()
+	logError("failed")
+	if !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("WithCallerSkip(1) nên trỏ đến nơi gọi hàm bọc %q, got %q", want, h.entry.Message)
+	}
+}
+
+func TestLogger_CallerDisabledByDefault(t *testing.T) {
+	l := NewLogger("App")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("no caller")
+	if strings.Contains(h.entry.Message, "caller=") {
+		t.Errorf("Thông tin caller không nên được ghi khi chưa bật, got %q", h.entry.Message)
+	}
+}
+
+func TestShortCaller(t *testing.T) {
+	tests := map[string]string{
+		"/src/app/service/user.go": "service/user.go:42",
+		"/user.go":                 "/user.go:42",
+		"user.go":                  "user.go:42",
+	}
+	for file, want := range tests {
+		if got := shortCaller(file, 42); got != want {
+			t.Errorf("shortCaller(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestManager_EnableCaller(t *testing.T) {
+	config := createTestConfig()
+	config.EnableCaller = true
+	m := NewManager(config)
+	defer m.Close()
+
+	h := &entryHandler{}
+	lg := m.GetLogger("Orders")
+	lg.AddHandler(TestHandlerType, h)
+
+	want := nextLine()
+	lg.Info("placed")
+	if !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("Config.EnableCaller nên bật caller cho logger của Manager, got %q", h.entry.Message)
+	}
+
+	disabled := *config
+	disabled.EnableCaller = false
+	if _, err := m.ApplyConfig(&disabled, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	lg.Info("placed")
+	if strings.Contains(h.entry.Message, "caller=") {
+		t.Errorf("ApplyConfig nên tắt caller cho logger đã tồn tại, got %q", h.entry.Message)
+	}
+
+	invalid := *config
+	invalid.CallerSkip = -1
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "caller_skip") {
+		t.Errorf("Validate() nên từ chối caller_skip âm, got %v", err)
+	}
+}
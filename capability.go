@@ -0,0 +1,82 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.fork.vn/log/handler"
+)
+
+// FlushAll ghi hết bộ đệm của mọi handler đã đăng ký triển khai handler.Flusher (VD: hàng đợi
+// của async và delivery, sink gửi theo lô), kể cả khi Flusher nằm bên trong các wrapper. Handler
+// không triển khai Flusher được bỏ qua, nên FlushAll an toàn với mọi handler. Method này là
+// thread-safe.
+//
+// Trả về:
+//   - error: tổng hợp lỗi của các handler ghi thất bại
+//
+// Ví dụ:
+//
+//	logger.Error("Job failed", log.Err(err))
+//	_ = manager.FlushAll() // entry trên đã được ghi trước khi tiến trình thoát
+//	os.Exit(1)
+func (m *manager) FlushAll() error {
+	m.mu.RLock()
+	handlers := make(map[HandlerType]handler.Handler, len(m.handlers))
+	for handlerType, h := range m.handlers {
+		handlers[handlerType] = h
+	}
+	m.mu.RUnlock()
+
+	types := make([]HandlerType, 0, len(handlers))
+	for handlerType := range handlers {
+		// Các handler con của stack đã được đăng ký riêng với manager
+		if handlerType != HandlerTypeStack {
+			types = append(types, handlerType)
+		}
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for _, handlerType := range types {
+		if err := handler.Flush(handlers[handlerType]); err != nil {
+			errs = append(errs, fmt.Errorf("handler %s: %w", handlerType, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetHandlerLevel đặt cấp độ tối thiểu riêng của handler đã đăng ký triển khai handler.Leveler
+// (VD: handler.LevelHandler), kể cả khi Leveler nằm bên trong các wrapper. Cấp độ có hiệu lực
+// ngay với mọi logger, các handler khác không bị ảnh hưởng. Method này là thread-safe.
+//
+// Tham số:
+//   - handlerType: HandlerType - tên handler đã đăng ký
+//   - level: handler.Level - cấp độ tối thiểu mới
+//
+// Trả về:
+//   - error: lỗi nếu handler chưa được đăng ký hoặc không triển khai handler.Leveler
+//
+// Ví dụ:
+//
+//	manager.AddHandler("alerting", handler.NewLevelHandler(slackHandler, handler.ErrorLevel))
+//	// Tạm nhận cả cảnh báo khi điều tra sự cố
+//	if err := manager.SetHandlerLevel("alerting", handler.WarningLevel); err != nil {
+//	    return err
+//	}
+func (m *manager) SetHandlerLevel(handlerType HandlerType, level handler.Level) error {
+	m.mu.RLock()
+	h, ok := m.handlers[handlerType]
+	m.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("handler %q is not registered", handlerType)
+	}
+	leveler, ok := handler.LevelerOf(h)
+	if !ok {
+		return fmt.Errorf("handler %q does not support per-handler levels", handlerType)
+	}
+	leveler.SetLevel(level)
+	return nil
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// namedMemoryHandler là MemoryHandler tự đặt tên (handler.Namer).
+type namedMemoryHandler struct {
+	*handler.MemoryHandler
+}
+
+func (namedMemoryHandler) Name() string { return "memory" }
+
+func TestManager_FlushAll(t *testing.T) {
+	config := createTestConfig()
+	config.Async = map[string]AsyncConfig{"custom": {QueueSize: 100}}
+	m := NewManager(config)
+	defer m.Close()
+
+	mem := handler.NewMemoryHandler()
+	m.AddHandler("custom", mem)
+	logger := m.GetLogger("Flush")
+	for i := 0; i < 50; i++ {
+		logger.Info("entry")
+	}
+	if err := m.FlushAll(); err != nil {
+		t.Fatalf("FlushAll() error = %v", err)
+	}
+	if mem.Len() != 50 {
+		t.Errorf("FlushAll() nên chờ hàng đợi bất đồng bộ được ghi hết, got %d entry", mem.Len())
+	}
+}
+
+func TestManager_SetHandlerLevel(t *testing.T) {
+	m := NewManager(createTestConfig())
+	defer m.Close()
+
+	mem := handler.NewMemoryHandler()
+	m.AddHandler("alerting", handler.NewLevelHandler(mem, handler.ErrorLevel))
+	logger := m.GetLogger("Alert")
+	logger.Warning("disk almost full")
+	if mem.Len() != 0 {
+		t.Fatalf("Handler có cấp độ Error không nên nhận Warning, got %v", mem.Entries())
+	}
+
+	if err := m.SetHandlerLevel("alerting", handler.WarningLevel); err != nil {
+		t.Fatalf("SetHandlerLevel() error = %v", err)
+	}
+	logger.Warning("disk almost full")
+	if !mem.Contains("disk almost full") {
+		t.Error("SetHandlerLevel() nên có hiệu lực ngay với logger đã tạo")
+	}
+
+	if err := m.SetHandlerLevel("missing", handler.InfoLevel); err == nil {
+		t.Error("SetHandlerLevel() nên báo lỗi với handler chưa đăng ký")
+	}
+	if err := m.SetHandlerLevel(HandlerTypeConsole, handler.InfoLevel); err == nil || !strings.Contains(err.Error(), "does not support") {
+		t.Errorf("SetHandlerLevel() nên báo lỗi với handler không triển khai Leveler, got %v", err)
+	}
+}
+
+func TestManager_AddNamedHandler_Namer(t *testing.T) {
+	m := NewManager(createTestConfig())
+	defer m.Close()
+
+	if err := m.AddNamedHandler("", namedMemoryHandler{handler.NewMemoryHandler()}); err != nil {
+		t.Fatalf("AddNamedHandler() error = %v", err)
+	}
+	if m.GetHandler("memory") == nil {
+		t.Error("AddNamedHandler() với tên rỗng nên dùng tên của handler.Namer")
+	}
+	if err := m.AddNamedHandler("", handler.NewMemoryHandler()); err == nil {
+		t.Error("AddNamedHandler() nên báo lỗi khi tên rỗng và handler không tự đặt tên")
+	}
+}
@@ -0,0 +1,372 @@
+package log
+
+import (
+	"sort"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// Các channel được hỗ trợ sẵn.
+const (
+	// ChannelApp là channel mặc định của log ứng dụng, ghi theo cấu hình console, file và stack
+	ChannelApp = "app"
+
+	// ChannelAccess là channel của access log do HTTP middleware ghi
+	ChannelAccess = "access"
+
+	// ChannelAudit là channel của các bản ghi kiểm toán (VD: nâng cấp độ log tạm thời)
+	ChannelAudit = "audit"
+)
+
+// Context mặc định của các channel dựng sẵn.
+const (
+	// AccessContext là context của logger mà HTTP middleware dùng theo mặc định
+	AccessContext = "HTTP"
+
+	// AuditContext là context của logger nhận bản sao các bản ghi kiểm toán
+	AuditContext = "Audit"
+)
+
+// ChannelDriverSingle là driver của channel ghi vào file riêng tại ChannelConfig.Path, tương tự
+// driver "single" của Laravel.
+const ChannelDriverSingle = "single"
+
+// channelHandlerPrefix là tiền tố tên của file handler riêng do Manager tạo cho channel.
+const channelHandlerPrefix = "channel."
+
+// ChannelHandlerType trả về tên mà Manager đăng ký file handler riêng của channel.
+//
+// Tên này có thể được dùng với Manager.GetHandler, Config.Async hoặc Config.Delivery.
+//
+// Tham số:
+//   - channel: string - tên channel
+//
+// Trả về:
+//   - HandlerType: tên handler dạng "channel.<name>"
+//
+// Ví dụ:
+//
+//	accessFile := manager.GetHandler(log.ChannelHandlerType(log.ChannelAccess))
+func ChannelHandlerType(channel string) HandlerType {
+	return HandlerType(channelHandlerPrefix + channel)
+}
+
+// newChannelFile tạo file handler riêng của channel theo Path, MaxSize và Format.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập cảnh báo và nén dùng chung
+//   - channel: ChannelConfig - cấu hình của channel
+//
+// Trả về:
+//   - *handler.FileHandler: file handler đã được cấu hình
+//   - error: lỗi nếu không thể mở file
+func newChannelFile(config *Config, channel ChannelConfig) (*handler.FileHandler, error) {
+	channelFile, err := newFileHandler(config, channel.Path, channel.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	// Cấu hình đã được Validate nên định dạng hợp lệ
+	format, _ := handler.ParseFormat(channel.Format)
+	channelFile.SetFormat(format)
+	return channelFile, nil
+}
+
+// Channel trả về logger ghi vào channel đã cấu hình, tương tự Log::channel() của Laravel.
+//
+// Logger trả về là logger của context đầu tiên trong Contexts của channel, hoặc của context
+// trùng tên channel nếu channel không khai báo Contexts. Với channel không được cấu hình,
+// logger của context trùng tên được trả về và ghi như channel "app". Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên channel trong Config.Channels
+//
+// Trả về:
+//   - Logger: logger của channel
+//
+// Ví dụ:
+//
+//	manager.AddHandler("slack", slackHandler)
+//	// channels: {slack: {driver: slack, level: error}}
+//	manager.Channel("slack").Error("Payment gateway down")
+func (m *manager) Channel(name string) Logger {
+	m.mu.RLock()
+	context := m.config.Channels[name].context(name)
+	m.mu.RUnlock()
+
+	return m.GetLogger(context)
+}
+
+// channelOf trả về channel có đích ghi riêng chứa context.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa các channel
+//   - context: string - context của logger
+//
+// Trả về:
+//   - string: tên channel, hoặc ChannelApp nếu context không thuộc channel nào có đích ghi
+//   - ChannelConfig: cấu hình của channel
+func channelOf(config *Config, context string) (string, ChannelConfig) {
+	for name, channel := range config.Channels {
+		if !channel.HasSinks() {
+			continue
+		}
+		if channel.owns(name, context) {
+			return name, channel
+		}
+	}
+	return ChannelApp, ChannelConfig{}
+}
+
+// owns kiểm tra context có thuộc channel tên name hay không. Channel không khai báo Contexts
+// chứa context trùng tên channel.
+func (c ChannelConfig) owns(name, context string) bool {
+	if len(c.Contexts) == 0 {
+		return context == name
+	}
+	return containsString(c.Contexts, context)
+}
+
+// context trả về context của logger mà Manager.Channel trả về cho channel tên name.
+func (c ChannelConfig) context(name string) string {
+	if len(c.Contexts) == 0 {
+		return name
+	}
+	return c.Contexts[0]
+}
+
+// levelOf trả về cấp độ tối thiểu của logger theo context: Level trong Config.Contexts nếu có,
+// rồi Level của channel chứa context, cuối cùng là Config.Level.
+func levelOf(config *Config, context string) handler.Level {
+	if c, ok := contextConfig(config, context); ok && c.Level != "" {
+		if level, err := handler.ParseLevel(c.Level); err == nil {
+			return level
+		}
+	}
+	if name, channel := channelOf(config, context); name != ChannelApp && channel.Level != "" {
+		if level, err := handler.ParseLevel(channel.Level); err == nil {
+			return level
+		}
+	}
+	return config.Level
+}
+
+// channelTypes trả về các handler nhận log của channel theo thứ tự: file riêng, Driver, rồi Handlers.
+func channelTypes(name string, channel ChannelConfig) []HandlerType {
+	var types []HandlerType
+	if channel.Path != "" {
+		types = append(types, ChannelHandlerType(name))
+	}
+	if channel.Driver != "" && channel.Driver != ChannelDriverSingle {
+		types = append(types, HandlerType(channel.Driver))
+	}
+	for _, h := range channel.Handlers {
+		types = append(types, HandlerType(h))
+	}
+	return types
+}
+
+// loggerRoute trả về các handler do cấu hình quản lý mà logger của context sẽ được gắn vào.
+//
+// Logic này phải khớp với GetLogger để báo cáo route chính xác.
+//
+// Tham số:
+//   - config: *Config - cấu hình cần tính toán
+//   - context: string - context của logger
+//
+// Trả về:
+//   - []HandlerType: handler riêng của context (xem dedicatedRoute), hoặc routeTypes với channel "app"
+func loggerRoute(config *Config, context string) []HandlerType {
+	if types, ok := dedicatedRoute(config, context); ok {
+		return types
+	}
+	return routeTypes(config)
+}
+
+// channelOnly kiểm tra một handler tùy chỉnh có chỉ dành cho các channel hay không.
+//
+// Handler tùy chỉnh và file trong Config.Files được liệt kê trong Handlers của một channel có
+// đích ghi, của Config.Contexts hoặc là handler dự phòng trong Config.Fallback không được gắn
+// vào logger của channel "app"; console, file và stack vẫn được dùng chung.
+func channelOnly(config *Config, handlerType HandlerType) bool {
+	if strings.HasPrefix(string(handlerType), channelHandlerPrefix) {
+		return true
+	}
+	if !isCustomHandler(handlerType) && !isFileOutput(handlerType) {
+		return false
+	}
+	for _, channel := range config.Channels {
+		if !channel.HasSinks() {
+			continue
+		}
+		for _, h := range channel.Handlers {
+			if HandlerType(h) == handlerType {
+				return true
+			}
+		}
+		if channel.Driver != ChannelDriverSingle && HandlerType(channel.Driver) == handlerType {
+			return true
+		}
+	}
+	for _, context := range config.Contexts {
+		for _, h := range context.Handlers {
+			if HandlerType(h) == handlerType {
+				return true
+			}
+		}
+	}
+	// Handler dự phòng chỉ nhận entry mà handler nguồn ghi thất bại
+	for _, fallback := range config.Fallback {
+		if HandlerType(fallback.Handler) == handlerType {
+			return true
+		}
+	}
+	return false
+}
+
+// isCustomHandler kiểm tra handler có phải là handler tùy chỉnh thêm qua AddHandler hay không
+// (khác console, file, stack, file riêng của channel và các file trong Config.Files).
+func isCustomHandler(handlerType HandlerType) bool {
+	return handlerType != HandlerTypeConsole && handlerType != HandlerTypeFile && handlerType != HandlerTypeStack &&
+		!strings.HasPrefix(string(handlerType), channelHandlerPrefix) && !isFileOutput(handlerType)
+}
+
+// containsType kiểm tra types có chứa handlerType hay không.
+func containsType(types []HandlerType, handlerType HandlerType) bool {
+	for _, t := range types {
+		if t == handlerType {
+			return true
+		}
+	}
+	return false
+}
+
+// routesTo kiểm tra một handler đã đăng ký với Manager có được gắn vào logger của context
+// theo cấu hình hay không.
+func routesTo(config *Config, context string, handlerType HandlerType) bool {
+	if types, ok := dedicatedRoute(config, context); ok {
+		return containsType(types, handlerType)
+	}
+	return !channelOnly(config, handlerType)
+}
+
+// validateChannels kiểm tra cấu hình các channel.
+//
+// Trả về:
+//   - error: ConfigError nếu cấu hình không hợp lệ
+func (c *Config) validateChannels() error {
+	names := make([]string, 0, len(c.Channels))
+	for name := range c.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	owner := make(map[string]string)
+	for _, name := range names {
+		channel := c.Channels[name]
+		field := "channels." + name
+		if name == "" || name == ChannelApp {
+			return &ConfigError{
+				Field:   "channels",
+				Value:   name,
+				Message: "channel name must not be empty or app, app uses the console, file and stack configuration",
+			}
+		}
+		contexts := channel.Contexts
+		if len(contexts) == 0 {
+			contexts = []string{name}
+		}
+		for _, context := range contexts {
+			if other, ok := owner[context]; ok {
+				return &ConfigError{
+					Field:   field + ".contexts",
+					Value:   context,
+					Message: "context already belongs to channel " + other,
+				}
+			}
+			owner[context] = name
+		}
+		switch {
+		case channel.Driver == ChannelDriverSingle && channel.Path == "":
+			return &ConfigError{
+				Field:   field + ".path",
+				Message: "path is required for the single driver",
+			}
+		case strings.HasPrefix(channel.Driver, channelHandlerPrefix) || strings.ContainsAny(channel.Driver, " \t\r\n"):
+			return &ConfigError{
+				Field:   field + ".driver",
+				Value:   channel.Driver,
+				Message: "driver must be single or the name of a handler registered with the manager",
+			}
+		}
+		if channel.Level != "" {
+			if _, err := handler.ParseLevel(channel.Level); err != nil {
+				return &ConfigError{
+					Field:   field + ".level",
+					Value:   channel.Level,
+					Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+				}
+			}
+		}
+		if _, err := handler.ParseFormat(channel.Format); err != nil {
+			return &ConfigError{
+				Field:   field + ".format",
+				Value:   channel.Format,
+				Message: "invalid format, must be one of: text, json, common, combined, cef, leef",
+			}
+		}
+		for _, h := range channel.Handlers {
+			if h == "" || strings.HasPrefix(h, channelHandlerPrefix) {
+				return &ConfigError{
+					Field:   field + ".handlers",
+					Value:   h,
+					Message: "handlers must name handlers registered with the manager, use path for a channel file",
+				}
+			}
+		}
+		if channel.MaxSize < 0 {
+			return &ConfigError{
+				Field:   field + ".max_size",
+				Value:   channel.String(),
+				Message: "max_size must be non-negative (0 for unlimited)",
+			}
+		}
+	}
+	return nil
+}
+
+// audit ghi một bản ghi kiểm toán qua logger của context và sao chép bản ghi đến channel
+// "audit" khi channel này có đích ghi và context không thuộc channel đó.
+//
+// Không được gọi khi đang giữ m.mu vì logger của channel có thể được tạo qua GetLogger.
+//
+// Tham số:
+//   - context: string - context phát sinh bản ghi
+//   - l: *logger - logger của context
+//   - message: string - thông điệp kiểm toán
+//   - args: ...interface{} - các field của bản ghi
+func (m *manager) audit(context string, l *logger, message string, args ...interface{}) {
+	l.audit(message, args...)
+
+	m.mu.RLock()
+	channel := m.config.Channels[ChannelAudit]
+	mirror := channel.HasSinks() && !channel.owns(ChannelAudit, context)
+	m.mu.RUnlock()
+	if !mirror {
+		return
+	}
+
+	if a, ok := m.GetLogger(channel.context(ChannelAudit)).(*logger); ok {
+		a.audit(message, append(args, Any("context", context))...)
+	}
+}
+
+// containsString kiểm tra values có chứa s hay không.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// newChannelTestConfig tạo cấu hình chỉ ghi file app.log, với channel access ghi access.log.
+func newChannelTestConfig(t *testing.T) *Config {
+	dir := t.TempDir()
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(dir, "app.log")
+	config.Channels = map[string]ChannelConfig{
+		ChannelAccess: {Contexts: []string{AccessContext}, Path: filepath.Join(dir, "access.log")},
+	}
+	return config
+}
+
+// readLog đọc nội dung file log, trả về chuỗi rỗng nếu file chưa tồn tại.
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("Không thể đọc file log: %v", err)
+	}
+	return string(data)
+}
+
+func TestChannels_Routing(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	access := m.GetLogger(AccessContext)
+	app := m.GetLogger("Payment")
+	rec := &recordingHandler{}
+	m.AddHandler(TestHandlerType, rec)
+
+	access.Info("GET /health")
+	app.Info("charge created")
+
+	accessLog := readLog(t, config.Channels[ChannelAccess].Path)
+	appLog := readLog(t, config.File.Path)
+	if !strings.Contains(accessLog, "[HTTP] GET /health") {
+		t.Errorf("Access log nên được ghi vào file của channel, got %q", accessLog)
+	}
+	if strings.Contains(accessLog, "charge created") {
+		t.Errorf("Log ứng dụng không nên được ghi vào file của channel access, got %q", accessLog)
+	}
+	if strings.Contains(appLog, "GET /health") || !strings.Contains(appLog, "charge created") {
+		t.Errorf("File ứng dụng chỉ nên chứa log ứng dụng, got %q", appLog)
+	}
+	if rec.contains("GET /health") || !rec.contains("charge created") {
+		t.Errorf("Handler tùy chỉnh dùng chung chỉ nên nhận log ứng dụng, got %v", rec.messages)
+	}
+
+	if m.GetHandler(ChannelHandlerType(ChannelAccess)) == nil {
+		t.Error("File của channel nên được đăng ký với tên channel.access")
+	}
+}
+
+func TestChannels_CustomHandlers(t *testing.T) {
+	config := newChannelTestConfig(t)
+	config.Channels[ChannelAudit] = ChannelConfig{Contexts: []string{AuditContext}, Handlers: []string{"siem"}}
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	app := m.GetLogger("Payment")
+	audit := m.GetLogger(AuditContext)
+	siem := &recordingHandler{}
+	m.AddHandler("siem", siem)
+
+	app.Info("charge created")
+	audit.Info("role granted")
+	if siem.contains("charge created") || !siem.contains("[Audit] role granted") {
+		t.Errorf("Handler của channel audit chỉ nên nhận log của channel, got %v", siem.messages)
+	}
+	if strings.Contains(readLog(t, config.File.Path), "role granted") {
+		t.Error("Log của channel audit không nên được ghi vào file ứng dụng")
+	}
+}
+
+func TestChannels_DefaultConfigKeepsRouting(t *testing.T) {
+	config := DefaultConfig()
+	if len(config.Channels) == 0 {
+		t.Fatal("DefaultConfig nên khai báo các channel access và audit")
+	}
+	for name, channel := range config.Channels {
+		if channel.HasSinks() {
+			t.Errorf("Channel %s mặc định không nên có đích ghi", name)
+		}
+	}
+	if !equalTypes(loggerRoute(config, AccessContext), routeTypes(config)) {
+		t.Error("Channel không có đích ghi không nên thay đổi route của logger")
+	}
+}
+
+func TestChannels_ApplyConfig(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	access := m.GetLogger(AccessContext)
+	rec := &recordingHandler{}
+	m.AddHandler(TestHandlerType, rec)
+
+	// Bỏ đích ghi của channel access: logger HTTP quay về channel app
+	updated := newChannelTestConfig(t)
+	updated.File.Path = config.File.Path
+	delete(updated.Channels, ChannelAccess)
+	diff, err := m.ApplyConfig(updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "handler channel.access: remove") {
+		t.Errorf("Diff nên xóa file của channel access, got %q", diff.String())
+	}
+	if !strings.Contains(diff.String(), "channels.access") {
+		t.Errorf("Diff nên liệt kê thay đổi của channels.access, got %q", diff.String())
+	}
+	if m.GetHandler(ChannelHandlerType(ChannelAccess)) != nil {
+		t.Error("File của channel access nên bị gỡ khỏi manager")
+	}
+
+	access.Info("GET /orders")
+	if !strings.Contains(readLog(t, config.File.Path), "GET /orders") {
+		t.Error("Logger rời channel nên ghi vào file ứng dụng")
+	}
+	if !rec.contains("GET /orders") {
+		t.Error("Logger rời channel nên nhận lại handler tùy chỉnh dùng chung")
+	}
+
+	// Thêm lại channel: logger HTTP chỉ ghi vào file của channel
+	again := newChannelTestConfig(t)
+	again.File.Path = config.File.Path
+	diff, err = m.ApplyConfig(again, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "handler channel.access: create") {
+		t.Errorf("Diff nên tạo file của channel access, got %q", diff.String())
+	}
+	access.Info("GET /payments")
+	if strings.Contains(readLog(t, config.File.Path), "GET /payments") || rec.contains("GET /payments") {
+		t.Error("Logger thuộc channel không nên ghi đến handler của channel app")
+	}
+	if !strings.Contains(readLog(t, again.Channels[ChannelAccess].Path), "GET /payments") {
+		t.Error("Logger thuộc channel nên ghi vào file mới của channel")
+	}
+}
+
+func TestChannels_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels map[string]ChannelConfig
+		field    string
+	}{
+		{
+			name:     "app name",
+			channels: map[string]ChannelConfig{ChannelApp: {Contexts: []string{"Payment"}}},
+			field:    "channels",
+		},
+		{
+			name: "duplicate context",
+			channels: map[string]ChannelConfig{
+				ChannelAccess: {Contexts: []string{"HTTP"}},
+				ChannelAudit:  {Contexts: []string{"HTTP"}},
+			},
+			field: "channels.audit.contexts",
+		},
+		{
+			name:     "channel handler",
+			channels: map[string]ChannelConfig{ChannelAudit: {Handlers: []string{"channel.access"}}},
+			field:    "channels.audit.handlers",
+		},
+		{
+			name:     "negative max size",
+			channels: map[string]ChannelConfig{ChannelAccess: {MaxSize: -1}},
+			field:    "channels.access.max_size",
+		},
+		{
+			name:     "single driver without path",
+			channels: map[string]ChannelConfig{ChannelAccess: {Driver: ChannelDriverSingle}},
+			field:    "channels.access.path",
+		},
+		{
+			name:     "channel driver",
+			channels: map[string]ChannelConfig{ChannelAudit: {Driver: "channel.access"}},
+			field:    "channels.audit.driver",
+		},
+		{
+			name:     "invalid level",
+			channels: map[string]ChannelConfig{"slack": {Driver: "slack", Level: "loud"}},
+			field:    "channels.slack.level",
+		},
+		{
+			name:     "invalid format",
+			channels: map[string]ChannelConfig{ChannelAccess: {Format: "xml"}},
+			field:    "channels.access.format",
+		},
+		{
+			name: "context owned by channel name",
+			channels: map[string]ChannelConfig{
+				"slack":      {Driver: "slack"},
+				ChannelAudit: {Contexts: []string{"slack"}},
+			},
+			field: "channels.slack.contexts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.File.Path = filepath.Join(t.TempDir(), "app.log")
+			config.Channels = tt.channels
+
+			err := config.Validate()
+			configErr, ok := err.(*ConfigError)
+			if !ok {
+				t.Fatalf("Validate() nên trả về ConfigError, got %v", err)
+			}
+			if configErr.Field != tt.field {
+				t.Errorf("Field = %q, want %q", configErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestChannels_AuditMirror(t *testing.T) {
+	m, _, _ := newElevateTestManager(t)
+	audit := &recordingHandler{}
+	m.config.Channels = map[string]ChannelConfig{
+		ChannelAudit: {Contexts: []string{AuditContext}, Handlers: []string{"siem"}},
+	}
+	m.AddHandler("siem", audit)
+
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, time.Hour); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	if !audit.contains("[Audit] log level elevated level=DEBUG previous=ERROR duration=1h0m0s context=Payment") {
+		t.Errorf("Bản ghi kiểm toán nên được sao chép đến channel audit, got %v", audit.messages)
+	}
+	if audit.contains("[Payment]") {
+		t.Errorf("Logger của context không thuộc channel audit không nên ghi đến handler của channel, got %v", audit.messages)
+	}
+}
+
+func TestManager_Channel(t *testing.T) {
+	config := newChannelTestConfig(t)
+	dir := filepath.Dir(config.File.Path)
+	config.Channels["slack"] = ChannelConfig{Driver: "slack", Level: "error"}
+	config.Channels[ChannelAudit] = ChannelConfig{
+		Driver:   ChannelDriverSingle,
+		Contexts: []string{AuditContext},
+		Path:     filepath.Join(dir, "audit.log"),
+		Format:   "json",
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+	slack := &recordingHandler{}
+	m.AddHandler("slack", slack)
+
+	m.Channel("slack").Warning("disk almost full")
+	m.Channel("slack").Error("payment gateway down")
+	m.GetLogger("Payment").Error("charge failed")
+	if slack.contains("disk almost full") || !slack.contains("[slack] payment gateway down") {
+		t.Errorf("Channel slack chỉ nên nhận entry từ cấp độ của channel, got %v", slack.messages)
+	}
+	if slack.contains("charge failed") {
+		t.Error("Handler làm driver của channel không nên được gắn vào logger của channel app")
+	}
+
+	if m.Channel(ChannelAudit) != m.GetLogger(AuditContext) {
+		t.Error("Channel() nên trả về logger của context đầu tiên của channel")
+	}
+	m.Channel(ChannelAudit).Info("role granted")
+	if audit := readLog(t, filepath.Join(dir, "audit.log")); !strings.Contains(audit, `"context":"Audit","message":"role granted"`) {
+		t.Errorf("Driver single nên ghi vào file riêng theo định dạng của channel, got %q", audit)
+	}
+}
+
+func TestChannels_ApplyConfigLevel(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	access := m.GetLogger(AccessContext).(*logger)
+	m.GetLogger("Payment")
+
+	updated := newChannelTestConfig(t)
+	channel := updated.Channels[ChannelAccess]
+	channel.Level = "warning"
+	updated.Channels[ChannelAccess] = channel
+	diff, err := m.ApplyConfig(updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !containsString(diff.Levels, AccessContext) || containsString(diff.Levels, "Payment") {
+		t.Errorf("Diff chỉ nên báo đổi cấp độ của context thuộc channel, got %v", diff.Levels)
+	}
+	if access.getMinLevel() != handler.WarningLevel {
+		t.Errorf("Logger của channel nên dùng Level của channel, got %v", access.getMinLevel())
+	}
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// Clock cung cấp thời điểm của log entry (xem handler.Clock). Dùng handler.NewManualClock để cố
+// định thời gian trong test.
+type Clock = handler.Clock
+
+// WithClock thay thế nguồn thời điểm của các entry do logger ghi, để test so sánh output một cách
+// tất định. Thời điểm truyền vào LogAt không bị ảnh hưởng.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng handler.SystemClock
+//
+// Trả về:
+//   - LoggerOption: tùy chọn thay thế clock
+//
+// Ví dụ:
+//
+//	clock := handler.NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+//	logger := log.NewLogger("Test", log.WithClock(clock))
+func WithClock(clock Clock) LoggerOption {
+	return func(l *logger) {
+		l.clock = clock
+	}
+}
+
+// setClock thay thế nguồn thời điểm của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng handler.SystemClock
+func (l *logger) setClock(clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.clock = clock
+	l.publish()
+}
+
+// now trả về thời điểm hiện tại theo clock của snapshot.
+func (s *loggerSnapshot) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// SetClock thay thế nguồn thời điểm của các entry do mọi logger của manager ghi, kể cả logger
+// tạo sau, và của cửa sổ Dedup. Method này là thread-safe.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng handler.SystemClock
+//
+// Ví dụ:
+//
+//	clock := handler.NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+//	manager.SetClock(clock)
+//	logger.Info("Order created") // 2024/03/01 12:00:00 [INFO] [Order] Order created
+//	clock.Advance(time.Second)
+func (m *manager) SetClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = clock
+	for _, lg := range m.loggers {
+		if l, ok := lg.(*logger); ok {
+			l.setClock(clock)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestManager_SetClock(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := handler.NewManualClock(start)
+
+	m := NewManager(createTestConfig())
+	defer m.Close()
+	before := m.GetLogger("Before")
+	m.SetClock(clock)
+	after := m.GetLogger("After")
+
+	for _, l := range []Logger{before, after} {
+		h := &entryHandler{}
+		l.AddHandler(TestHandlerType, h)
+
+		l.Info("tick")
+		if !h.entry.Time.Equal(start) {
+			t.Errorf("Entry nên dùng thời điểm của clock, got %v", h.entry.Time)
+		}
+		clock.Advance(time.Minute)
+		l.Info("tock")
+		if want := clock.Now(); !h.entry.Time.Equal(want) {
+			t.Errorf("Entry nên dùng thời điểm sau Advance, got %v, want %v", h.entry.Time, want)
+		}
+		clock.Set(start)
+	}
+
+	m.SetClock(nil)
+	h := &entryHandler{}
+	before.AddHandler(TestHandlerType, h)
+	before.Info("real")
+	if h.entry.Time.Equal(start) {
+		t.Error("SetClock(nil) nên quay về thời điểm hệ thống")
+	}
+}
+
+func TestLogger_DedupWithClock(t *testing.T) {
+	clock := handler.NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	l := NewLogger("Metrics", WithClock(clock))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	dedup := l.Dedup(time.Minute)
+	dedup.Error("push failed")
+	h.entry = nil
+	clock.Advance(30 * time.Second)
+	dedup.Error("push failed")
+	if h.entry != nil {
+		t.Errorf("Entry lặp lại trong window nên bị chặn, got %q", h.entry.Message)
+	}
+	clock.Advance(time.Minute)
+	dedup.Error("push failed")
+	if h.entry == nil || h.entry.Message != "[Metrics] push failed suppressed=1" {
+		t.Errorf("Entry sau window nên được ghi kèm số entry bị chặn, got %+v", h.entry)
+	}
+}
+
+func TestManager_MetadataSequence(t *testing.T) {
+	config := createTestConfig()
+	config.Metadata = MetadataConfig{Sequence: true}
+	m := NewManager(config)
+	defer m.Close()
+
+	l := m.GetLogger("Worker")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("first")
+	l.Info("second")
+	if got := h.entry.Message; got != "[Worker] second seq=2" {
+		t.Errorf("Entry nên được gắn số thứ tự tăng dần, got %q", got)
+	}
+
+	updated := *config
+	updated.Metadata = MetadataConfig{GoroutineID: true, Sequence: true}
+	if _, err := m.ApplyConfig(&updated, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	l.Info("third")
+	if got := h.entry.Message; !strings.HasSuffix(got, " seq=3") {
+		t.Errorf("Số thứ tự nên tiếp tục tăng sau ApplyConfig, got %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+// Command logdecrypt giải mã file log do FileHandler ghi với mã hóa AES-GCM (xem
+// handler.FileHandler.SetEncryption) và ghi các dòng log gốc ra stdout.
+//
+// File sao lưu đã nén (VD: "app.log.20240101120000.gz") được giải nén theo codec có phần mở rộng
+// tương ứng. Khóa AES mã hóa base64 được đọc từ biến môi trường LOG_ENCRYPTION_KEY, hoặc biến
+// môi trường khác qua -key-env. Dòng không mã hóa là lỗi, trừ khi dùng -allow-plaintext.
+//
+// Cách dùng:
+//
+//	LOG_ENCRYPTION_KEY=... logdecrypt storage/logs/audit.log storage/logs/audit.log.*.gz | grep user=alice
+//	logdecrypt -key-env AUDIT_LOG_KEY < storage/logs/audit.log
+//	logdecrypt -allow-plaintext storage/logs/app.log
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.fork.vn/log/handler"
+)
+
+func main() {
+	keyEnv := flag.String("key-env", "LOG_ENCRYPTION_KEY", "environment variable holding the base64 AES key")
+	allowPlaintext := flag.Bool("allow-plaintext", false, "pass through lines that are not encrypted")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-key-env NAME] [-allow-plaintext] [file ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(*keyEnv))
+	if err != nil || len(key) == 0 {
+		fmt.Fprintf(os.Stderr, "logdecrypt: %s must hold a base64 AES key\n", *keyEnv)
+		os.Exit(2)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	for _, path := range paths {
+		if err := decryptFile(os.Stdout, path, key, handler.DecryptOptions{AllowPlaintext: *allowPlaintext}); err != nil {
+			fmt.Fprintf(os.Stderr, "logdecrypt: %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// decryptFile giải mã file path ("-" là stdin) theo opts và ghi các dòng log gốc vào w.
+func decryptFile(w io.Writer, path string, key []byte, opts handler.DecryptOptions) error {
+	var src io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		src = file
+
+		if codec := handler.CodecForPath(path); codec != nil {
+			decompressed, err := codec.NewReader(file)
+			if err != nil {
+				return err
+			}
+			defer decompressed.Close()
+			src = decompressed
+		}
+	}
+
+	plain, err := handler.NewDecryptReader(src, key, opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, plain)
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestDecryptFile(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "audit.log")
+	h, err := handler.NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetEncryption(key); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Log(handler.InfoLevel, "[Audit] role granted")
+	_ = h.Close()
+
+	var out bytes.Buffer
+	if err := decryptFile(&out, path, key, handler.DecryptOptions{}); err != nil {
+		t.Fatalf("decryptFile() error = %v", err)
+	}
+	if !bytes.HasSuffix(out.Bytes(), []byte("[INFO] [Audit] role granted\n")) {
+		t.Errorf("decryptFile() = %q", out.String())
+	}
+
+	if err := decryptFile(&out, filepath.Join(t.TempDir(), "missing.log"), key, handler.DecryptOptions{}); err == nil {
+		t.Error("decryptFile() nên trả về lỗi khi file không tồn tại")
+	}
+}
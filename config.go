@@ -1,9 +1,19 @@
 package log
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.fork.vn/log/handler"
 )
@@ -23,8 +33,166 @@ type Config struct {
 	// File cấu hình cho file handler
 	File FileConfig `mapstructure:"file" yaml:"file" json:"file"`
 
+	// Files các file log bổ sung theo tên (VD: "errors" chỉ chứa lỗi, "audit" dạng JSON), mỗi
+	// file có path, cấp độ, rotation và định dạng riêng. Manager đăng ký chúng với tên
+	// "file.<name>" (xem FileHandlerType) để tham chiếu từ Stack.Include, Routing và Channels
+	Files map[string]FileOutputConfig `mapstructure:"files" yaml:"files" json:"files"`
+
 	// Stack cấu hình cho stack handler
 	Stack StackConfig `mapstructure:"stack" yaml:"stack" json:"stack"`
+
+	// Async cấu hình ghi log bất đồng bộ theo tên handler (VD: "console", "file", hoặc handler
+	// tùy chỉnh như "loki" thêm qua AddHandler), mỗi handler có số worker và hàng đợi riêng.
+	// Handler không có trong map được ghi đồng bộ. Thay đổi cho handler tùy chỉnh có hiệu lực
+	// khi handler được thêm lại qua AddHandler
+	Async map[string]AsyncConfig `mapstructure:"async" yaml:"async" json:"async"`
+
+	// Delivery chọn cam kết giao nhận theo tên handler (best_effort, at_least_once, guaranteed);
+	// manager tự tạo các wrapper tương ứng. Số worker và hàng đợi lấy từ Async nếu có
+	Delivery map[string]DeliveryConfig `mapstructure:"delivery" yaml:"delivery" json:"delivery"`
+
+	// Fallback chọn handler dự phòng theo tên handler (VD: "loki" dự phòng sang "console") nhận
+	// các entry khi handler ghi thất bại liên tiếp, để log không bị mất khi sink từ xa hoặc ổ
+	// đĩa gặp sự cố; manager tự bọc handler tương ứng
+	Fallback map[string]FallbackConfig `mapstructure:"fallback" yaml:"fallback" json:"fallback"`
+
+	// Retry chọn chính sách thử lại với thời gian chờ tăng dần và ngắt mạch theo tên handler
+	// (VD: "loki" thử lại 3 lần, ngắt mạch sau 5 entry thất bại liên tiếp); manager tự bọc
+	// handler tương ứng
+	Retry map[string]RetryConfig `mapstructure:"retry" yaml:"retry" json:"retry"`
+
+	// FieldFilters chọn các field có cấu trúc mà mỗi handler ghi ra theo tên handler (VD: bỏ
+	// user_agent khỏi console nhưng vẫn ghi vào file); manager tự bọc handler tương ứng
+	FieldFilters map[string]FieldFilterConfig `mapstructure:"field_filters" yaml:"field_filters" json:"field_filters"`
+
+	// Filters chọn các entry mà mỗi handler ghi ra theo tên handler, dựa trên thông điệp,
+	// context và field (VD: bỏ log ồn ào của thư viện bên thứ ba khỏi console nhưng vẫn ghi vào
+	// file); manager tự bọc handler tương ứng
+	Filters map[string]RecordFilterConfig `mapstructure:"filters" yaml:"filters" json:"filters"`
+
+	// Middleware bọc handler theo tên handler trong các middleware đã đăng ký qua
+	// handler.RegisterMiddleware (VD: "loki": ["metrics", "throttle_remote"]), middleware đầu tiên
+	// bọc ngoài cùng. Middleware bọc sát handler, bên trong các wrapper do các mục khác tạo
+	Middleware map[string][]string `mapstructure:"middleware" yaml:"middleware" json:"middleware"`
+
+	// Fold gộp thông điệp nhiều dòng thành một bản ghi theo tên handler, chế độ "escape" (thoát
+	// xuống dòng) hoặc "field" (các dòng sau dòng đầu vào field mảng "lines"), để bộ thu thập đọc
+	// theo dòng không tách một sự kiện thành nhiều bản ghi; manager tự bọc handler tương ứng
+	Fold map[string]string `mapstructure:"fold" yaml:"fold" json:"fold"`
+
+	// Routing định tuyến entry đến handler theo cấp độ, context, thông điệp và field (VD: entry
+	// từ error trở lên đến "sentry", context "Audit*" đến "channel.audit"). Handler được nêu
+	// trong một luật chỉ nhận entry khớp các luật nêu nó; manager tự bọc handler tương ứng
+	Routing []RoutingRuleConfig `mapstructure:"routing" yaml:"routing" json:"routing"`
+
+	// EnableCaller ghi kèm vị trí gọi log dạng caller=service/user.go:42 cho mọi logger do Manager tạo
+	EnableCaller bool `mapstructure:"enable_caller" yaml:"enable_caller" json:"enable_caller"`
+
+	// CallerSkip số stack frame bổ sung cần bỏ qua khi xác định vị trí gọi,
+	// dùng khi logger được gọi qua một hàm bọc chung. 0 = vị trí gọi trực tiếp
+	CallerSkip int `mapstructure:"caller_skip" yaml:"caller_skip" json:"caller_skip"`
+
+	// RawMessages ghi thông điệp và key của field nguyên văn thay vì thoát '\n', '\r' và ký tự
+	// điều khiển (xem handler.Sanitize). Chỉ bật khi thông điệp không chứa dữ liệu không tin cậy,
+	// vì dữ liệu chứa xuống dòng có thể tạo dòng log giả
+	RawMessages bool `mapstructure:"raw_messages" yaml:"raw_messages" json:"raw_messages"`
+
+	// Watch áp dụng lại section "log" mỗi khi config manager báo cấu hình thay đổi (xem
+	// WatchConfig), để sửa cấp độ, handler, đường dẫn hoặc lấy mẫu mà không khởi động lại service.
+	// Chỉ được đọc khi ServiceProvider đăng ký manager
+	Watch bool `mapstructure:"watch" yaml:"watch" json:"watch"`
+
+	// Channels tách log theo mục đích (VD: "access" cho HTTP middleware, "audit" cho bản ghi
+	// kiểm toán) sang tập handler riêng. Logger có context thuộc một channel có đích ghi chỉ ghi
+	// đến các handler của channel đó; các context còn lại thuộc channel "app" mặc định
+	Channels map[string]ChannelConfig `mapstructure:"channels" yaml:"channels" json:"channels"`
+
+	// Contexts cấp độ và handler riêng theo context của logger, key hỗ trợ ký tự đại diện như
+	// "UserService*" (xem ContextConfig). Cấu hình của context được ưu tiên hơn channel
+	Contexts map[string]ContextConfig `mapstructure:"contexts" yaml:"contexts" json:"contexts"`
+
+	// Sampling giới hạn log lặp lại với tần suất cao cho mọi logger do Manager tạo: trong mỗi
+	// chu kỳ, Initial entry đầu tiên có cùng context, cấp độ và thông điệp được ghi, sau đó chỉ
+	// ghi mỗi entry thứ Thereafter. Initial = 0 để tắt
+	Sampling SamplingConfig `mapstructure:"sampling" yaml:"sampling" json:"sampling"`
+
+	// Retention gắn lớp lưu trữ (VD: "short", "long", "audit") vào field "retention" của mọi
+	// entry theo context, để hệ thống lưu trữ phía sau áp dụng thời gian lưu trữ khác nhau
+	Retention RetentionConfig `mapstructure:"retention" yaml:"retention" json:"retention"`
+
+	// Redaction che dữ liệu nhạy cảm (mật khẩu, token, số thẻ, email, JWT) trong thông điệp và
+	// field trước khi entry đến bất kỳ handler nào, trừ các handler trong Exclude
+	Redaction RedactionConfig `mapstructure:"redaction" yaml:"redaction" json:"redaction"`
+
+	// Pseudonymization thay giá trị của các field định danh (VD: user_id, email) bằng pseudonym
+	// tất định với khóa luân phiên; hủy khóa để "quên" dữ liệu người dùng mà không ghi lại log
+	Pseudonymization PseudonymizationConfig `mapstructure:"pseudonymization" yaml:"pseudonymization" json:"pseudonymization"`
+
+	// ContextFields các giá trị được lấy từ context.Context thành field khi ghi log qua các
+	// method *Context (VD: InfoContext), thay vì mỗi service tự viết hàm trích xuất
+	ContextFields []ContextFieldConfig `mapstructure:"context_fields" yaml:"context_fields" json:"context_fields"`
+
+	// Metadata gắn tên máy chủ, process ID và goroutine ID vào mọi entry của mọi logger do
+	// Manager tạo, để phân biệt nguồn gốc log khi triển khai nhiều instance
+	Metadata MetadataConfig `mapstructure:"metadata" yaml:"metadata" json:"metadata"`
+
+	// SIEM thông tin thiết bị trong header của các file ghi theo định dạng "cef" hoặc "leef"
+	SIEM SIEMConfig `mapstructure:"siem" yaml:"siem" json:"siem"`
+
+	// Fields các field cố định của toàn bộ triển khai (VD: {"service": "orders", "env": "prod"})
+	// được gắn vào mọi entry của mọi logger do Manager tạo, bổ sung bởi Manager.WithFields
+	Fields map[string]string `mapstructure:"fields" yaml:"fields" json:"fields"`
+
+	// Readiness cho phép lỗi ghi log (handler lỗi, hàng đợi quá tải) làm thất bại kiểm tra
+	// readiness qua Manager.Readiness
+	Readiness ReadinessConfig `mapstructure:"readiness" yaml:"readiness" json:"readiness"`
+
+	// MaxFieldDepth độ sâu lồng nhau tối đa khi ghi map, slice và struct trong field;
+	// phần sâu hơn được thay bằng "[truncated]". 0 = mặc định (handler.DefaultMaxFieldDepth)
+	MaxFieldDepth int `mapstructure:"max_field_depth" yaml:"max_field_depth" json:"max_field_depth"`
+
+	// MaxFieldElements số phần tử tối đa được ghi của mỗi map, slice hoặc struct trong field;
+	// phần còn lại được thay bằng "+N more". 0 = mặc định (handler.DefaultMaxFieldElements)
+	MaxFieldElements int `mapstructure:"max_field_elements" yaml:"max_field_elements" json:"max_field_elements"`
+
+	// MaxFields số field tối đa của mỗi entry; field vượt quá bị bỏ và được đếm trong
+	// fields_truncated. 0 = mặc định (handler.DefaultMaxFields)
+	MaxFields int `mapstructure:"max_fields" yaml:"max_fields" json:"max_fields"`
+}
+
+// fieldLimits trả về giới hạn field của các logger do Manager tạo.
+func (c *Config) fieldLimits() handler.Limits {
+	return handler.Limits{MaxDepth: c.MaxFieldDepth, MaxElements: c.MaxFieldElements, MaxFields: c.MaxFields}
+}
+
+// SamplingConfig định nghĩa cấu hình lấy mẫu log (xem handler.SamplingOptions).
+type SamplingConfig struct {
+	// Initial số entry đầu tiên có cùng thông điệp được ghi trong mỗi chu kỳ. 0 = tắt lấy mẫu
+	Initial int `mapstructure:"initial" yaml:"initial" json:"initial"`
+
+	// Thereafter sau Initial entry, chỉ ghi mỗi entry thứ Thereafter. 0 = bỏ tất cả
+	Thereafter int `mapstructure:"thereafter" yaml:"thereafter" json:"thereafter"`
+
+	// Tick độ dài mỗi chu kỳ lấy mẫu. 0 = mặc định (handler.DefaultSamplingTick, 1 giây)
+	Tick time.Duration `mapstructure:"tick" yaml:"tick" json:"tick"`
+}
+
+// Enabled kiểm tra việc lấy mẫu có được bật hay không.
+//
+// Trả về:
+//   - bool: true nếu Initial lớn hơn 0
+func (s SamplingConfig) Enabled() bool {
+	return s.Initial > 0
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "initial=100 thereafter=100 tick=1s".
+func (s SamplingConfig) String() string {
+	return "initial=" + strconv.Itoa(s.Initial) + " thereafter=" + strconv.Itoa(s.Thereafter) + " tick=" + s.Tick.String()
+}
+
+// options chuyển cấu hình thành handler.SamplingOptions.
+func (s SamplingConfig) options() handler.SamplingOptions {
+	return handler.SamplingOptions{Tick: s.Tick, Initial: s.Initial, Thereafter: s.Thereafter}
 }
 
 // ConsoleConfig định nghĩa cấu hình cho console handler.
@@ -32,8 +200,81 @@ type ConsoleConfig struct {
 	// Enabled bật/tắt console handler
 	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
 
-	// Colored bật/tắt màu sắc cho console output
+	// Colored bật/tắt màu sắc cho console output. Màu tự tắt khi output không phải terminal
+	// hoặc khi NO_COLOR/CLICOLOR=0 được đặt (xem handler.ColorSupported)
 	Colored bool `mapstructure:"colored" yaml:"colored" json:"colored"`
+
+	// ForceColor luôn dùng màu khi Colored được bật, bỏ qua việc kiểm tra terminal và biến môi
+	// trường (VD: khi output được pipe qua một công cụ hiển thị được mã ANSI)
+	ForceColor bool `mapstructure:"force_color" yaml:"force_color" json:"force_color"`
+
+	// Colors thay thế màu mặc định theo cấp độ và màu của context
+	Colors ColorsConfig `mapstructure:"colors" yaml:"colors" json:"colors"`
+
+	// GroupBy các field key dùng để nhóm các entry có cùng request/operation ID khi debug
+	// (VD: ["request_id"]). Rỗng = không nhóm
+	GroupBy []string `mapstructure:"group_by" yaml:"group_by" json:"group_by"`
+
+	// OmitTimestamp bỏ timestamp ở đầu mỗi dòng, dùng khi nền tảng đã gắn timestamp cho
+	// output (VD: systemd/journald, Docker, Kubernetes)
+	OmitTimestamp bool `mapstructure:"omit_timestamp" yaml:"omit_timestamp" json:"omit_timestamp"`
+
+	// StderrLevel cấp độ thấp nhất được ghi ra stderr, các cấp độ thấp hơn ghi ra stdout
+	// (VD: "warning" để tách Warning/Error/Fatal khỏi Debug/Info). Rỗng = "error"
+	StderrLevel string `mapstructure:"stderr_level" yaml:"stderr_level" json:"stderr_level"`
+
+	// Output tên writer nhận mọi entry thay cho việc tách stdout/stderr: "stdout", "stderr" hoặc
+	// một writer đăng ký qua handler.RegisterConsoleWriter. Rỗng = tách theo StderrLevel
+	Output string `mapstructure:"output" yaml:"output" json:"output"`
+}
+
+// ColorsConfig định nghĩa màu của console handler theo cấp độ và của đoạn "[Context]". Mỗi giá
+// trị là mô tả style của handler.ParseStyle (VD: "bold red", "underline 208", "#ff8800"); giá trị
+// rỗng giữ màu mặc định.
+type ColorsConfig struct {
+	Debug   string `mapstructure:"debug" yaml:"debug" json:"debug"`
+	Info    string `mapstructure:"info" yaml:"info" json:"info"`
+	Warning string `mapstructure:"warning" yaml:"warning" json:"warning"`
+	Error   string `mapstructure:"error" yaml:"error" json:"error"`
+	Fatal   string `mapstructure:"fatal" yaml:"fatal" json:"fatal"`
+	Context string `mapstructure:"context" yaml:"context" json:"context"`
+}
+
+// styles trả về mô tả style theo tên, theo thứ tự debug, info, warning, error, fatal, context.
+func (c ColorsConfig) styles() [][2]string {
+	return [][2]string{
+		{"debug", c.Debug}, {"info", c.Info}, {"warning", c.Warning},
+		{"error", c.Error}, {"fatal", c.Fatal}, {"context", c.Context},
+	}
+}
+
+// String trả về mô tả ngắn gọn của các style khác rỗng, VD: "warning=bold 208,context=underline".
+func (c ColorsConfig) String() string {
+	var parts []string
+	for _, style := range c.styles() {
+		if style[1] != "" {
+			parts = append(parts, style[0]+"="+style[1])
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// scheme chuyển cấu hình thành handler.ColorScheme. Cấu hình phải hợp lệ (xem Config.Validate).
+func (c ColorsConfig) scheme() handler.ColorScheme {
+	scheme := handler.ColorScheme{Levels: make(map[handler.Level]string)}
+	for _, style := range c.styles() {
+		code, _ := handler.ParseStyle(style[1])
+		if code == "" {
+			continue
+		}
+		if style[0] == "context" {
+			scheme.Context = code
+		} else {
+			level, _ := handler.ParseLevel(style[0])
+			scheme.Levels[level] = code
+		}
+	}
+	return scheme
 }
 
 // FileConfig định nghĩa cấu hình cho file handler.
@@ -47,6 +288,515 @@ type FileConfig struct {
 	// MaxSize kích thước tối đa của file log (bytes) trước khi rotate
 	// 0 = không giới hạn
 	MaxSize int64 `mapstructure:"max_size" yaml:"max_size" json:"max_size"`
+
+	// Format định dạng dòng log của file chính: "text", "json", "common"/"combined", "cef" hoặc
+	// "leef". Rỗng = "text". File của các channel và Files dùng Format riêng của chúng
+	Format string `mapstructure:"format" yaml:"format" json:"format"`
+
+	// GrowthAlert cảnh báo khi file log (và file của các channel) tăng quá nhanh trong thời
+	// gian dài, VD: hơn 50MB/phút trong 5 phút
+	GrowthAlert GrowthAlertConfig `mapstructure:"growth_alert" yaml:"growth_alert" json:"growth_alert"`
+
+	// DiskGuard chuyển file log (và file của các channel) sang chế độ chỉ ghi từ warning trở lên
+	// khi ổ đĩa sắp đầy hoặc thư mục log quá lớn, thay vì làm đầy đĩa
+	DiskGuard DiskGuardConfig `mapstructure:"disk_guard" yaml:"disk_guard" json:"disk_guard"`
+
+	// Compression codec nén file sao lưu sau khi rotate (VD: "gzip", hoặc "zstd"/"lz4" sau khi
+	// đăng ký bằng handler.RegisterCodec). Rỗng = không nén
+	Compression string `mapstructure:"compression" yaml:"compression" json:"compression"`
+
+	// MaxBackups số file sao lưu mới nhất được giữ lại sau khi rotate, các file cũ hơn bị xóa
+	// (áp dụng cho cả file của các channel và Config.Files). 0 = không giới hạn
+	MaxBackups int `mapstructure:"max_backups" yaml:"max_backups" json:"max_backups"`
+
+	// MaxAge số ngày tối đa giữ file sao lưu, tính theo timestamp trong tên file. 0 = không giới hạn
+	MaxAge int `mapstructure:"max_age" yaml:"max_age" json:"max_age"`
+
+	// Sync gọi fsync sau mỗi entry, đánh đổi thông lượng lấy độ bền dữ liệu (áp dụng cho cả file
+	// của các channel và Config.Files)
+	Sync bool `mapstructure:"sync" yaml:"sync" json:"sync"`
+
+	// SyncOnLevel chỉ gọi fsync sau entry từ cấp độ này trở lên (VD: "error"). Rỗng = theo Sync
+	SyncOnLevel string `mapstructure:"sync_on_level" yaml:"sync_on_level" json:"sync_on_level"`
+
+	// Signing ký từng dòng log và/hoặc từng file sao lưu bằng HMAC-SHA256 để bên nhận log xác minh
+	// tính toàn vẹn và nguồn gốc (áp dụng cho cả file của các channel và Config.Files)
+	Signing SigningConfig `mapstructure:"signing" yaml:"signing" json:"signing"`
+
+	// Encryption mã hóa từng dòng log bằng AES-GCM cho log chứa dữ liệu phải được bảo vệ khi lưu
+	// trữ (áp dụng cho cả file của các channel và Config.Files)
+	Encryption EncryptionConfig `mapstructure:"encryption" yaml:"encryption" json:"encryption"`
+}
+
+// GrowthAlertConfig định nghĩa cấu hình cảnh báo tốc độ tăng trưởng file log (xem handler.GrowthAlert).
+type GrowthAlertConfig struct {
+	// MaxRate tốc độ ghi tối đa (bytes/phút). 0 = tắt cảnh báo
+	MaxRate int64 `mapstructure:"max_rate" yaml:"max_rate" json:"max_rate"`
+
+	// Period thời gian tốc độ ghi phải vượt MaxRate liên tục trước khi cảnh báo
+	Period time.Duration `mapstructure:"period" yaml:"period" json:"period"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "max_rate=52428800 period=5m0s".
+func (g GrowthAlertConfig) String() string {
+	return "max_rate=" + strconv.FormatInt(g.MaxRate, 10) + " period=" + g.Period.String()
+}
+
+// minSigningKeyLen là độ dài tối thiểu (bytes) của khóa HMAC trong SigningConfig.
+const minSigningKeyLen = 16
+
+// SigningConfig định nghĩa cấu hình chữ ký HMAC-SHA256 của file log (xem handler.Signing).
+type SigningConfig struct {
+	// Key khóa HMAC bí mật, ít nhất 16 bytes (khuyến nghị 32). Không nên ghi thẳng vào file cấu
+	// hình mà nạp từ biến môi trường hoặc secret store
+	Key string `mapstructure:"key" yaml:"key" json:"key"`
+
+	// Records thêm chữ ký vào cuối mỗi dòng log (field "sig")
+	Records bool `mapstructure:"records" yaml:"records" json:"records"`
+
+	// Segments ghi file <file sao lưu>.sig chứa chữ ký của file sau mỗi lần rotate
+	Segments bool `mapstructure:"segments" yaml:"segments" json:"segments"`
+}
+
+// Enabled kiểm tra có ký dòng log hoặc file sao lưu hay không.
+//
+// Trả về:
+//   - bool: true nếu Records hoặc Segments được bật
+func (s SigningConfig) Enabled() bool {
+	return s.Records || s.Segments
+}
+
+// String trả về mô tả ngắn gọn của cấu hình mà không lộ khóa, VD:
+// "records=true segments=false key=sha256:9f86d081". Khóa được thay bằng 8 ký tự đầu của mã
+// SHA-256 để vẫn thấy được việc đổi khóa.
+func (s SigningConfig) String() string {
+	key := "none"
+	if s.Key != "" {
+		sum := sha256.Sum256([]byte(s.Key))
+		key = "sha256:" + hex.EncodeToString(sum[:4])
+	}
+	return "records=" + strconv.FormatBool(s.Records) + " segments=" + strconv.FormatBool(s.Segments) + " key=" + key
+}
+
+// EncryptionConfig định nghĩa nguồn khóa AES của mã hóa file log (xem handler.FileHandler.SetEncryption).
+// Chỉ được đặt một trong Key, KeyEnv và KeyProvider.
+type EncryptionConfig struct {
+	// Key khóa AES 16, 24 hoặc 32 bytes mã hóa base64. Không nên ghi thẳng vào file cấu hình
+	Key string `mapstructure:"key" yaml:"key" json:"key"`
+
+	// KeyEnv tên biến môi trường chứa khóa AES mã hóa base64 (VD: "LOG_ENCRYPTION_KEY")
+	KeyEnv string `mapstructure:"key_env" yaml:"key_env" json:"key_env"`
+
+	// KeyProvider tên KeyProvider đã đăng ký bằng handler.RegisterKeyProvider (VD: KMS)
+	KeyProvider string `mapstructure:"key_provider" yaml:"key_provider" json:"key_provider"`
+}
+
+// Enabled kiểm tra có mã hóa file log hay không.
+//
+// Trả về:
+//   - bool: true nếu Key, KeyEnv hoặc KeyProvider được đặt
+func (e EncryptionConfig) Enabled() bool {
+	return e.Key != "" || e.KeyEnv != "" || e.KeyProvider != ""
+}
+
+// String trả về mô tả ngắn gọn của cấu hình mà không lộ khóa, VD: "key_env=LOG_ENCRYPTION_KEY"
+// hoặc "key=sha256:9f86d081".
+func (e EncryptionConfig) String() string {
+	var parts []string
+	if e.Key != "" {
+		sum := sha256.Sum256([]byte(e.Key))
+		parts = append(parts, "key=sha256:"+hex.EncodeToString(sum[:4]))
+	}
+	if e.KeyEnv != "" {
+		parts = append(parts, "key_env="+e.KeyEnv)
+	}
+	if e.KeyProvider != "" {
+		parts = append(parts, "key_provider="+e.KeyProvider)
+	}
+	return strings.Join(parts, " ")
+}
+
+// source trả về nguồn khóa AES của cấu hình.
+func (e EncryptionConfig) source() keySource {
+	return keySource{
+		field: "file.encryption", description: e.String(), key: e.Key, env: e.KeyEnv, provider: e.KeyProvider,
+		check: func(key []byte) string {
+			if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+				return "key must be a base64 AES key of 16, 24 or 32 bytes"
+			}
+			return ""
+		},
+	}
+}
+
+// keySource là nguồn khóa bí mật của một thiết lập: khóa base64 trong cấu hình, biến môi trường
+// chứa khóa base64 hoặc handler.KeyProvider đã đăng ký.
+type keySource struct {
+	field       string                  // Tên thiết lập trong cấu hình, VD: "file.encryption"
+	description string                  // Mô tả không lộ khóa, dùng làm Value của ConfigError
+	key         string                  // Khóa base64
+	env         string                  // Tên biến môi trường chứa khóa base64
+	provider    string                  // Tên KeyProvider đã đăng ký
+	check       func(key []byte) string // Trả về thông báo lỗi nếu khóa không hợp lệ
+}
+
+// validate kiểm tra chỉ một nguồn được đặt và khóa hợp lệ. KeyProvider chỉ được kiểm tra đã
+// đăng ký, không được gọi (VD: tránh gọi KMS khi validate).
+//
+// Trả về:
+//   - error: *ConfigError nếu nguồn khóa không hợp lệ
+func (s keySource) validate() error {
+	sources := 0
+	for _, source := range []string{s.key, s.env, s.provider} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return &ConfigError{
+			Field:   s.field,
+			Value:   s.description,
+			Message: "only one of key, key_env and key_provider can be set",
+		}
+	}
+	if s.provider != "" {
+		if _, ok := handler.LookupKeyProvider(s.provider); !ok {
+			return &ConfigError{
+				Field:   s.field + ".key_provider",
+				Value:   s.provider,
+				Message: "unknown key provider (registered: " + strings.Join(handler.KeyProviderNames(), ", ") + ")",
+			}
+		}
+		return nil
+	}
+	_, err := s.load()
+	return err
+}
+
+// load trả về khóa từ cấu hình, biến môi trường hoặc KeyProvider.
+//
+// Trả về:
+//   - []byte: khóa đã được kiểm tra bởi check
+//   - error: *ConfigError nếu không lấy được khóa hoặc khóa không hợp lệ
+func (s keySource) load() ([]byte, error) {
+	field, encoded := s.field+".key", s.key
+	var key []byte
+	switch {
+	case s.provider != "":
+		field = s.field + ".key_provider"
+		provider, ok := handler.LookupKeyProvider(s.provider)
+		if !ok {
+			return nil, &ConfigError{
+				Field:   field,
+				Value:   s.provider,
+				Message: "unknown key provider (registered: " + strings.Join(handler.KeyProviderNames(), ", ") + ")",
+			}
+		}
+		var err error
+		if key, err = provider(); err != nil {
+			return nil, &ConfigError{Field: field, Value: s.provider, Message: "key provider failed: " + err.Error()}
+		}
+	default:
+		if s.env != "" {
+			field, encoded = s.field+".key_env", os.Getenv(s.env)
+		}
+		var err error
+		if key, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, &ConfigError{Field: field, Value: s.description, Message: "key must be base64 encoded"}
+		}
+	}
+
+	if message := s.check(key); message != "" {
+		return nil, &ConfigError{Field: field, Value: s.description, Message: message}
+	}
+	return key, nil
+}
+
+// SIEMConfig định nghĩa thông tin thiết bị trong header CEF/LEEF (xem handler.Device). Trường rỗng
+// dùng giá trị mặc định của handler.Device.
+type SIEMConfig struct {
+	// Vendor nhà cung cấp (VD: tên công ty)
+	Vendor string `mapstructure:"vendor" yaml:"vendor" json:"vendor"`
+
+	// Product tên sản phẩm hoặc service (VD: "orders")
+	Product string `mapstructure:"product" yaml:"product" json:"product"`
+
+	// Version phiên bản của sản phẩm
+	Version string `mapstructure:"version" yaml:"version" json:"version"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "vendor=Acme product=orders version=1.4.2".
+func (s SIEMConfig) String() string {
+	return "vendor=" + s.Vendor + " product=" + s.Product + " version=" + s.Version
+}
+
+// DiskGuardConfig định nghĩa cấu hình bảo vệ dung lượng đĩa của file log (xem handler.DiskGuard).
+type DiskGuardConfig struct {
+	// MinFree dung lượng trống tối thiểu (bytes) của ổ đĩa chứa file log. 0 = không kiểm tra
+	MinFree int64 `mapstructure:"min_free" yaml:"min_free" json:"min_free"`
+
+	// MaxDirSize tổng kích thước tối đa (bytes) của file log và các file sao lưu của nó. 0 = không kiểm tra
+	MaxDirSize int64 `mapstructure:"max_dir_size" yaml:"max_dir_size" json:"max_dir_size"`
+
+	// Interval khoảng thời gian giữa hai lần kiểm tra. 0 = handler.DefaultDiskCheckInterval
+	Interval time.Duration `mapstructure:"interval" yaml:"interval" json:"interval"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "min_free=1073741824 max_dir_size=0 interval=10s".
+func (d DiskGuardConfig) String() string {
+	return "min_free=" + strconv.FormatInt(d.MinFree, 10) + " max_dir_size=" + strconv.FormatInt(d.MaxDirSize, 10) +
+		" interval=" + d.Interval.String()
+}
+
+// ChannelConfig định nghĩa cấu hình cho một channel log.
+type ChannelConfig struct {
+	// Driver đích ghi chính của channel: ChannelDriverSingle ("single") cho file riêng tại Path,
+	// hoặc tên một handler đã đăng ký với Manager (VD: "console", "stack", "slack").
+	// Rỗng = chỉ dùng Path và Handlers
+	Driver string `mapstructure:"driver" yaml:"driver" json:"driver"`
+
+	// Level cấp độ tối thiểu của các logger thuộc channel. Rỗng = theo Config.Level
+	Level string `mapstructure:"level" yaml:"level" json:"level"`
+
+	// Contexts các logger context thuộc channel (VD: ["HTTP"] cho access log).
+	// Rỗng = channel chứa context trùng tên channel (xem Manager.Channel)
+	Contexts []string `mapstructure:"contexts" yaml:"contexts" json:"contexts"`
+
+	// Handlers tên các handler đã đăng ký với Manager nhận log của channel
+	// (VD: "console", "file" hoặc handler tùy chỉnh như "loki")
+	Handlers []string `mapstructure:"handlers" yaml:"handlers" json:"handlers"`
+
+	// Path file log riêng của channel, được Manager tạo và quản lý. Rỗng = không có file riêng
+	Path string `mapstructure:"path" yaml:"path" json:"path"`
+
+	// MaxSize kích thước tối đa của file log riêng (bytes) trước khi rotate
+	// 0 = không giới hạn
+	MaxSize int64 `mapstructure:"max_size" yaml:"max_size" json:"max_size"`
+
+	// Format định dạng dòng log của file riêng: "text", "json", "common"/"combined" cho access
+	// log (Common/Combined Log Format của Apache/Nginx) hoặc "cef"/"leef" cho SIEM. Rỗng = "text"
+	Format string `mapstructure:"format" yaml:"format" json:"format"`
+}
+
+// HasSinks kiểm tra channel có đích ghi riêng (driver, handler hoặc file) hay không.
+// Context thuộc channel không có đích ghi được ghi như channel "app".
+//
+// Trả về:
+//   - bool: true nếu channel có driver, ít nhất một handler hoặc file riêng
+func (c ChannelConfig) HasSinks() bool {
+	return c.Driver != "" || len(c.Handlers) > 0 || c.Path != ""
+}
+
+// String trả về biểu diễn của cấu hình channel dùng trong báo cáo thay đổi.
+//
+// Trả về:
+//   - string: chuỗi dạng driver=single level=info contexts=HTTP handlers=console path=storage/logs/access.log max_size=0 format=text
+func (c ChannelConfig) String() string {
+	return fmt.Sprintf("driver=%s level=%s contexts=%s handlers=%s path=%s max_size=%d format=%s",
+		c.Driver, c.Level, strings.Join(c.Contexts, ","), strings.Join(c.Handlers, ","), c.Path, c.MaxSize, c.Format)
+}
+
+// AsyncConfig định nghĩa cấu hình ghi log bất đồng bộ cho một handler.
+type AsyncConfig struct {
+	// Workers số worker goroutine ghi log đến handler
+	// 0 = mặc định (handler.DefaultAsyncWorkers)
+	Workers int `mapstructure:"workers" yaml:"workers" json:"workers"`
+
+	// QueueSize số entry tối đa chờ trong hàng đợi trước khi bên gọi phải chờ
+	// 0 = mặc định (handler.DefaultAsyncQueueSize)
+	QueueSize int `mapstructure:"queue_size" yaml:"queue_size" json:"queue_size"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "workers=8 queue_size=10000".
+func (a AsyncConfig) String() string {
+	return "workers=" + strconv.Itoa(a.Workers) + " queue_size=" + strconv.Itoa(a.QueueSize)
+}
+
+// DeliveryConfig định nghĩa cam kết giao nhận cho một handler.
+type DeliveryConfig struct {
+	// Mode chế độ giao nhận: best_effort, at_least_once hoặc guaranteed
+	Mode string `mapstructure:"mode" yaml:"mode" json:"mode"`
+
+	// SpillPath file lưu các entry ghi thất bại để gửi lại, bắt buộc với at_least_once
+	SpillPath string `mapstructure:"spill_path" yaml:"spill_path" json:"spill_path"`
+
+	// Retries số lần thử lại trước khi báo lỗi cho bên gọi (guaranteed)
+	Retries int `mapstructure:"retries" yaml:"retries" json:"retries"`
+
+	// RetryDelay thời gian chờ giữa các lần thử lại (guaranteed)
+	RetryDelay time.Duration `mapstructure:"retry_delay" yaml:"retry_delay" json:"retry_delay"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "mode=at_least_once spill_path=loki.spill".
+func (d DeliveryConfig) String() string {
+	switch d.Mode {
+	case handler.AtLeastOnce.String():
+		return "mode=" + d.Mode + " spill_path=" + d.SpillPath
+	case handler.Guaranteed.String():
+		return "mode=" + d.Mode + " retries=" + strconv.Itoa(d.Retries) + " retry_delay=" + d.RetryDelay.String()
+	default:
+		return "mode=" + d.Mode
+	}
+}
+
+// FallbackConfig định nghĩa handler dự phòng của một handler (xem handler.FallbackHandler).
+type FallbackConfig struct {
+	// Handler tên handler dự phòng đã đăng ký với Manager, VD: "console" hoặc "file.spill"
+	Handler string `mapstructure:"handler" yaml:"handler" json:"handler"`
+
+	// After số lần ghi thất bại liên tiếp trước khi chuyển sang handler dự phòng. 0 = 1
+	After int `mapstructure:"after" yaml:"after" json:"after"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "handler=console after=3".
+func (f FallbackConfig) String() string {
+	return "handler=" + f.Handler + " after=" + strconv.Itoa(f.After)
+}
+
+// RetryConfig định nghĩa việc thử lại và ngắt mạch của một handler (xem handler.CircuitHandler).
+type RetryConfig struct {
+	// Retries số lần thử lại sau lần ghi đầu tiên thất bại
+	Retries int `mapstructure:"retries" yaml:"retries" json:"retries"`
+
+	// Backoff thời gian chờ trước lần thử lại đầu tiên, nhân đôi sau mỗi lần. 0 = 50ms
+	Backoff time.Duration `mapstructure:"backoff" yaml:"backoff" json:"backoff"`
+
+	// MaxBackoff thời gian chờ tối đa giữa hai lần thử lại. 0 = 5s
+	MaxBackoff time.Duration `mapstructure:"max_backoff" yaml:"max_backoff" json:"max_backoff"`
+
+	// FailureThreshold số entry ghi thất bại liên tiếp trước khi mở mạch. 0 = không ngắt mạch
+	FailureThreshold int `mapstructure:"failure_threshold" yaml:"failure_threshold" json:"failure_threshold"`
+
+	// OpenTimeout thời gian mạch mở trước khi ghi thử một entry. 0 = 30s
+	OpenTimeout time.Duration `mapstructure:"open_timeout" yaml:"open_timeout" json:"open_timeout"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "retries=3 backoff=100ms max_backoff=0s
+// failure_threshold=5 open_timeout=1m0s".
+func (r RetryConfig) String() string {
+	return "retries=" + strconv.Itoa(r.Retries) + " backoff=" + r.Backoff.String() + " max_backoff=" + r.MaxBackoff.String() +
+		" failure_threshold=" + strconv.Itoa(r.FailureThreshold) + " open_timeout=" + r.OpenTimeout.String()
+}
+
+// policy chuyển cấu hình thành handler.RetryPolicy.
+func (r RetryConfig) policy() handler.RetryPolicy {
+	return handler.RetryPolicy{
+		Retries:          r.Retries,
+		Backoff:          r.Backoff,
+		MaxBackoff:       r.MaxBackoff,
+		FailureThreshold: r.FailureThreshold,
+		OpenTimeout:      r.OpenTimeout,
+	}
+}
+
+// FieldFilterConfig định nghĩa các field một handler ghi ra (xem handler.FieldFilter).
+type FieldFilterConfig struct {
+	// Allow chỉ giữ các field khớp, hỗ trợ ký tự đại diện như "request_*". Rỗng = giữ tất cả
+	Allow []string `mapstructure:"allow" yaml:"allow" json:"allow"`
+
+	// Deny bỏ các field khớp, áp dụng sau Allow
+	Deny []string `mapstructure:"deny" yaml:"deny" json:"deny"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "allow= deny=user_agent,request_body".
+func (f FieldFilterConfig) String() string {
+	return "allow=" + strings.Join(f.Allow, ",") + " deny=" + strings.Join(f.Deny, ",")
+}
+
+// RecordFilterConfig định nghĩa các entry một handler ghi ra (xem handler.RecordFilter).
+type RecordFilterConfig struct {
+	// Include chỉ ghi các entry khớp một trong các điều kiện. Rỗng = ghi tất cả
+	Include []RecordRuleConfig `mapstructure:"include" yaml:"include" json:"include"`
+
+	// Exclude bỏ các entry khớp một trong các điều kiện, áp dụng sau Include
+	Exclude []RecordRuleConfig `mapstructure:"exclude" yaml:"exclude" json:"exclude"`
+}
+
+// RecordRuleConfig định nghĩa một điều kiện trên entry; entry khớp khi thỏa mọi điều kiện được đặt.
+type RecordRuleConfig struct {
+	// Level cấp độ tối thiểu của entry (VD: "error"). Rỗng = mọi cấp độ
+	Level string `mapstructure:"level" yaml:"level" json:"level"`
+
+	// Message biểu thức chính quy khớp thông điệp đã định dạng (gồm "[Context]" và các field)
+	Message string `mapstructure:"message" yaml:"message" json:"message"`
+
+	// Contexts context của logger, hỗ trợ ký tự đại diện như "grpc*"
+	Contexts []string `mapstructure:"contexts" yaml:"contexts" json:"contexts"`
+
+	// Fields điều kiện trên field, tất cả phải thỏa (VD: "status>=500", "component=grpc", "!user_id")
+	Fields []string `mapstructure:"fields" yaml:"fields" json:"fields"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "include=[] exclude=[level= message=^health contexts= fields=]".
+func (f RecordFilterConfig) String() string {
+	rules := func(rules []RecordRuleConfig) string {
+		parts := make([]string, len(rules))
+		for i, r := range rules {
+			parts[i] = "level=" + r.Level + " message=" + r.Message + " contexts=" + strings.Join(r.Contexts, ",") + " fields=" + strings.Join(r.Fields, ",")
+		}
+		return "[" + strings.Join(parts, "; ") + "]"
+	}
+	return "include=" + rules(f.Include) + " exclude=" + rules(f.Exclude)
+}
+
+// filter biên dịch cấu hình thành handler.RecordFilter.
+//
+// Trả về:
+//   - handler.RecordFilter: bộ lọc entry
+//   - error: lỗi nếu biểu thức chính quy, mẫu context hoặc điều kiện field không hợp lệ
+func (f RecordFilterConfig) filter() (handler.RecordFilter, error) {
+	var filter handler.RecordFilter
+	var err error
+	if filter.Include, err = compileRules(f.Include); err != nil {
+		return filter, err
+	}
+	filter.Exclude, err = compileRules(f.Exclude)
+	return filter, err
+}
+
+// compileRules biên dịch các điều kiện trong cấu hình thành handler.RecordRule.
+func compileRules(configs []RecordRuleConfig) ([]handler.RecordRule, error) {
+	rules := make([]handler.RecordRule, 0, len(configs))
+	for _, c := range configs {
+		rule, err := c.rule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// rule biên dịch cấu hình thành handler.RecordRule.
+func (c RecordRuleConfig) rule() (handler.RecordRule, error) {
+	rule := handler.RecordRule{Contexts: c.Contexts}
+	if c.Level != "" {
+		level, err := handler.ParseLevel(c.Level)
+		if err != nil {
+			return rule, err
+		}
+		rule.MinLevel = level
+	}
+	if c.Message != "" {
+		re, err := regexp.Compile(c.Message)
+		if err != nil {
+			return rule, fmt.Errorf("invalid message pattern %q: %w", c.Message, err)
+		}
+		rule.Message = re
+	}
+	for _, pattern := range c.Contexts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return rule, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+		}
+	}
+	for _, expr := range c.Fields {
+		p, err := handler.ParseFieldPredicate(expr)
+		if err != nil {
+			return rule, err
+		}
+		rule.Fields = append(rule.Fields, p)
+	}
+	return rule, nil
 }
 
 // StackConfig định nghĩa cấu hình cho stack handler.
@@ -56,6 +806,97 @@ type StackConfig struct {
 
 	// Handlers cấu hình các sub-handlers
 	Handlers StackHandlers `mapstructure:"handlers" yaml:"handlers" json:"handlers"`
+
+	// Include tên các handler bổ sung vào stack, theo tên đã đăng ký với Manager
+	// (VD: "file", hoặc handler tùy chỉnh như "loki", "sentry" thêm qua AddHandler).
+	// Handler chưa được đăng ký sẽ được gắn vào stack khi được thêm qua AddHandler
+	Include []string `mapstructure:"include" yaml:"include" json:"include"`
+
+	// Levels cấp độ tối thiểu riêng của từng handler con theo tên (VD: {"console": "debug",
+	// "file": "info", "alerting": "error"}). Handler không có trong Levels nhận mọi entry qua
+	// được cấp độ của logger
+	Levels map[string]string `mapstructure:"levels" yaml:"levels" json:"levels"`
+
+	// Mode cách chuyển entry đến các handler con: "broadcast" (ghi đến tất cả), "first_success"
+	// (dừng ở handler đầu tiên ghi thành công) hoặc "failover" (chỉ ghi đến các handler sau khi
+	// handler đầu tiên thất bại). Rỗng = broadcast
+	Mode string `mapstructure:"mode" yaml:"mode" json:"mode"`
+
+	// Concurrency số lần ghi song song tối đa đến các handler con, để một handler mạng chậm
+	// không làm chậm các handler nhanh. 0 hoặc 1 = ghi tuần tự
+	Concurrency int `mapstructure:"concurrency" yaml:"concurrency" json:"concurrency"`
+}
+
+// Members trả về danh sách handler thuộc stack theo thứ tự: console, file, rồi các tên trong Include.
+//
+// Trả về:
+//   - []HandlerType: các handler thuộc stack, không trùng lặp
+func (s StackConfig) Members() []HandlerType {
+	var members []HandlerType
+	seen := make(map[HandlerType]bool)
+	add := func(handlerType HandlerType) {
+		if !seen[handlerType] {
+			seen[handlerType] = true
+			members = append(members, handlerType)
+		}
+	}
+
+	if s.Handlers.Console {
+		add(HandlerTypeConsole)
+	}
+	if s.Handlers.File {
+		add(HandlerTypeFile)
+	}
+	for _, name := range s.Include {
+		add(HandlerType(name))
+	}
+	return members
+}
+
+// uses kiểm tra console hoặc file handler có được dùng theo cấu hình hay không: handler được
+// bật, thuộc stack đang bật, được một channel hay Config.Contexts tham chiếu, hoặc là handler dự
+// phòng trong Config.Fallback. Manager chỉ tạo các handler được dùng.
+func (c *Config) uses(handlerType HandlerType) bool {
+	if (handlerType == HandlerTypeConsole && c.Console.Enabled) || (handlerType == HandlerTypeFile && c.File.Enabled) {
+		return true
+	}
+	if c.Stack.Enabled && c.Stack.Contains(handlerType) {
+		return true
+	}
+	for name, channel := range c.Channels {
+		if containsType(channelTypes(name, channel), handlerType) {
+			return true
+		}
+	}
+	for _, context := range c.Contexts {
+		for _, h := range context.Handlers {
+			if HandlerType(h) == handlerType {
+				return true
+			}
+		}
+	}
+	for _, fallback := range c.Fallback {
+		if HandlerType(fallback.Handler) == handlerType {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains kiểm tra một handler có thuộc stack hay không.
+//
+// Tham số:
+//   - handlerType: HandlerType - handler cần kiểm tra
+//
+// Trả về:
+//   - bool: true nếu handler thuộc stack
+func (s StackConfig) Contains(handlerType HandlerType) bool {
+	for _, member := range s.Members() {
+		if member == handlerType {
+			return true
+		}
+	}
+	return false
 }
 
 // StackHandlers định nghĩa cấu hình các handler trong stack.
@@ -96,10 +937,17 @@ func DefaultConfig() *Config {
 				File:    false,
 			},
 		},
+		// Access log và bản ghi kiểm toán có channel riêng; chỉ cần đặt path hoặc handlers
+		// để tách chúng khỏi log ứng dụng
+		Channels: map[string]ChannelConfig{
+			ChannelAccess: {Contexts: []string{AccessContext}},
+			ChannelAudit:  {Contexts: []string{AuditContext}},
+		},
 	}
 }
 
-// Validate kiểm tra tính hợp lệ của cấu hình.
+// Validate kiểm tra tính hợp lệ của cấu hình, gồm cả kiểm tra cú pháp (ValidateSyntax) và kiểm
+// tra trên filesystem (ValidateRuntime).
 //
 // Phương thức này xác minh:
 //   - Level có hợp lệ không
@@ -111,6 +959,27 @@ func DefaultConfig() *Config {
 // Trả về:
 //   - error: Lỗi nếu cấu hình không hợp lệ
 func (c *Config) Validate() error {
+	if err := c.ValidateSyntax(); err != nil {
+		return err
+	}
+	return c.ValidateRuntime()
+}
+
+// ValidateSyntax kiểm tra tính hợp lệ của cấu hình mà không truy cập filesystem: không stat
+// thư mục log và không tạo file thử quyền ghi. Dùng trong CI chỉ đọc hoặc công cụ lint cấu
+// hình, nơi thư mục log của môi trường triển khai không tồn tại.
+//
+// Trả về:
+//   - error: Lỗi nếu cấu hình không hợp lệ
+//
+// Ví dụ:
+//
+//	// Lint file cấu hình production trên máy CI
+//	if err := config.ValidateSyntax(); err != nil {
+//	    fmt.Fprintln(os.Stderr, err)
+//	    os.Exit(1)
+//	}
+func (c *Config) ValidateSyntax() error {
 	// Kiểm tra level hợp lệ
 	validLevels := map[handler.Level]bool{
 		handler.DebugLevel:   true,
@@ -129,7 +998,7 @@ func (c *Config) Validate() error {
 	}
 
 	// Kiểm tra có ít nhất một handler được bật
-	if !c.Console.Enabled && !c.File.Enabled && !c.Stack.Enabled {
+	if !c.Console.Enabled && !c.File.Enabled && !c.Stack.Enabled && len(c.Files) == 0 {
 		return &ConfigError{
 			Field:   "handlers",
 			Message: "at least one handler must be enabled",
@@ -137,57 +1006,543 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate file handler path - chỉ yêu cầu khi file handler được sử dụng
-	needsFilePath := c.File.Enabled || (c.Stack.Enabled && c.Stack.Handlers.File)
-	if needsFilePath && c.File.Path == "" {
+	if c.uses(HandlerTypeFile) && c.File.Path == "" {
 		return &ConfigError{
 			Field:   "file.path",
 			Message: "path is required for file handler initialization",
 		}
 	}
 
-	// Kiểm tra thư mục log nếu có path
-	if c.File.Path != "" {
-		if err := c.validateAndCreateLogDir(c.File.Path); err != nil {
+	if c.File.MaxSize < 0 {
+		return &ConfigError{
+			Field:   "file.max_size",
+			Value:   string(rune(c.File.MaxSize)),
+			Message: "max_size must be non-negative (0 for unlimited)",
+		}
+	}
+
+	if c.File.GrowthAlert.MaxRate < 0 || c.File.GrowthAlert.Period < 0 {
+		return &ConfigError{
+			Field:   "file.growth_alert",
+			Value:   c.File.GrowthAlert.String(),
+			Message: "max_rate and period must be non-negative (max_rate 0 disables the alert)",
+		}
+	}
+
+	if c.File.DiskGuard.MinFree < 0 || c.File.DiskGuard.MaxDirSize < 0 || c.File.DiskGuard.Interval < 0 {
+		return &ConfigError{
+			Field:   "file.disk_guard",
+			Value:   c.File.DiskGuard.String(),
+			Message: "min_free, max_dir_size and interval must be non-negative (0 disables the check)",
+		}
+	}
+
+	if c.File.MaxBackups < 0 || c.File.MaxAge < 0 {
+		return &ConfigError{
+			Field:   "file.max_backups",
+			Value:   strconv.Itoa(c.File.MaxBackups) + "/" + strconv.Itoa(c.File.MaxAge),
+			Message: "max_backups and max_age must be non-negative (0 for unlimited)",
+		}
+	}
+
+	if _, err := handler.ParseFormat(c.File.Format); err != nil {
+		return &ConfigError{
+			Field:   "file.format",
+			Value:   c.File.Format,
+			Message: "invalid format, must be one of: text, json, common, combined, cef, leef",
+		}
+	}
+
+	if c.File.Compression != "" {
+		if _, ok := handler.LookupCodec(c.File.Compression); !ok {
 			return &ConfigError{
-				Field:   "file.path",
-				Value:   c.File.Path,
-				Message: "log directory validation failed: " + err.Error(),
+				Field:   "file.compression",
+				Value:   c.File.Compression,
+				Message: "unknown compression codec (registered: " + strings.Join(handler.CodecNames(), ", ") + ")",
 			}
 		}
 	}
 
-	if c.File.MaxSize < 0 {
+	if c.Console.StderrLevel != "" {
+		if _, err := handler.ParseLevel(c.Console.StderrLevel); err != nil {
+			return &ConfigError{
+				Field:   "console.stderr_level",
+				Value:   c.Console.StderrLevel,
+				Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+			}
+		}
+	}
+
+	for _, style := range c.Console.Colors.styles() {
+		if _, err := handler.ParseStyle(style[1]); err != nil {
+			return &ConfigError{
+				Field:   "console.colors." + style[0],
+				Value:   style[1],
+				Message: "invalid style, use bold/dim/italic/underline with a color name, 0-255 or #rrggbb",
+			}
+		}
+	}
+
+	if c.Console.Output != "" {
+		if _, ok := handler.LookupConsoleWriter(c.Console.Output); !ok {
+			return &ConfigError{
+				Field:   "console.output",
+				Value:   c.Console.Output,
+				Message: "unknown console writer (registered: " + strings.Join(handler.ConsoleWriterNames(), ", ") + ")",
+			}
+		}
+	}
+
+	if c.File.SyncOnLevel != "" {
+		if _, err := handler.ParseLevel(c.File.SyncOnLevel); err != nil {
+			return &ConfigError{
+				Field:   "file.sync_on_level",
+				Value:   c.File.SyncOnLevel,
+				Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+			}
+		}
+	}
+
+	if c.File.Signing.Enabled() && len(c.File.Signing.Key) < minSigningKeyLen {
 		return &ConfigError{
-			Field:   "file.max_size",
-			Value:   string(rune(c.File.MaxSize)),
-			Message: "max_size must be non-negative (0 for unlimited)",
+			Field:   "file.signing.key",
+			Value:   c.File.Signing.String(),
+			Message: "key must be at least " + strconv.Itoa(minSigningKeyLen) + " bytes when records or segments is enabled",
+		}
+	}
+
+	if c.File.Encryption.Enabled() {
+		if err := c.File.Encryption.source().validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.CallerSkip < 0 {
+		return &ConfigError{
+			Field:   "caller_skip",
+			Value:   strconv.Itoa(c.CallerSkip),
+			Message: "caller_skip must be non-negative",
+		}
+	}
+
+	for _, limit := range []struct {
+		field string
+		value int
+	}{
+		{"max_field_depth", c.MaxFieldDepth},
+		{"max_field_elements", c.MaxFieldElements},
+		{"max_fields", c.MaxFields},
+	} {
+		if limit.value < 0 {
+			return &ConfigError{
+				Field:   limit.field,
+				Value:   strconv.Itoa(limit.value),
+				Message: limit.field + " must be non-negative (0 for default)",
+			}
+		}
+	}
+
+	if c.Sampling.Initial < 0 || c.Sampling.Thereafter < 0 || c.Sampling.Tick < 0 {
+		return &ConfigError{
+			Field:   "sampling",
+			Value:   c.Sampling.String(),
+			Message: "initial, thereafter and tick must be non-negative (initial 0 disables sampling)",
+		}
+	}
+
+	if err := c.validateContextFields(); err != nil {
+		return err
+	}
+
+	if err := c.validateFields(); err != nil {
+		return err
+	}
+
+	if err := c.Readiness.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Retention.validate(); err != nil {
+		return err
+	}
+
+	if _, err := c.Redaction.options(); err != nil {
+		return &ConfigError{
+			Field:   "redaction.patterns",
+			Value:   strings.Join(c.Redaction.Patterns, ","),
+			Message: err.Error(),
+		}
+	}
+
+	if c.Pseudonymization.Enabled() {
+		if err := c.Pseudonymization.source().validate(); err != nil {
+			return err
+		}
+	}
+
+	for name, async := range c.Async {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "async",
+				Value:   name,
+				Message: "async must name a handler other than stack, configure its members instead",
+			}
+		}
+		if async.Workers < 0 || async.QueueSize < 0 {
+			return &ConfigError{
+				Field:   "async." + name,
+				Value:   async.String(),
+				Message: "workers and queue_size must be non-negative (0 for default)",
+			}
+		}
+	}
+
+	for name, delivery := range c.Delivery {
+		if err := c.validateDelivery(name, delivery); err != nil {
+			return err
+		}
+	}
+
+	for name, fallback := range c.Fallback {
+		if err := c.validateFallback(name, fallback); err != nil {
+			return err
+		}
+	}
+
+	for name, retry := range c.Retry {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "retry",
+				Value:   name,
+				Message: "retry must name a handler other than stack, configure its members instead",
+			}
+		}
+		if retry.Retries < 0 || retry.Backoff < 0 || retry.MaxBackoff < 0 || retry.FailureThreshold < 0 || retry.OpenTimeout < 0 {
+			return &ConfigError{
+				Field:   "retry." + name,
+				Value:   retry.String(),
+				Message: "retry settings must be non-negative (0 for default)",
+			}
+		}
+	}
+
+	for name, filter := range c.FieldFilters {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "field_filters",
+				Value:   name,
+				Message: "field_filters must name a handler other than stack, configure its members instead",
+			}
+		}
+		for _, pattern := range append(append([]string(nil), filter.Allow...), filter.Deny...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return &ConfigError{
+					Field:   "field_filters." + name,
+					Value:   pattern,
+					Message: "invalid field pattern: " + err.Error(),
+				}
+			}
+		}
+	}
+
+	for name, chain := range c.Middleware {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "middleware",
+				Value:   name,
+				Message: "middleware must name a handler other than stack, configure its members instead",
+			}
+		}
+		for _, middleware := range chain {
+			if _, ok := handler.LookupMiddleware(middleware); !ok {
+				return &ConfigError{
+					Field:   "middleware." + name,
+					Value:   middleware,
+					Message: "unknown middleware, must be registered with handler.RegisterMiddleware",
+				}
+			}
+		}
+	}
+
+	for name, mode := range c.Fold {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "fold",
+				Value:   name,
+				Message: "fold must name a handler other than stack, configure its members instead",
+			}
+		}
+		if _, err := handler.ParseFoldMode(mode); err != nil {
+			return &ConfigError{
+				Field:   "fold." + name,
+				Value:   mode,
+				Message: "invalid fold mode, must be one of: escape, field",
+			}
+		}
+	}
+
+	for name, filter := range c.Filters {
+		if name == "" || HandlerType(name) == HandlerTypeStack {
+			return &ConfigError{
+				Field:   "filters",
+				Value:   name,
+				Message: "filters must name a handler other than stack, configure its members instead",
+			}
+		}
+		if _, err := filter.filter(); err != nil {
+			return &ConfigError{
+				Field:   "filters." + name,
+				Value:   filter.String(),
+				Message: err.Error(),
+			}
 		}
 	}
 
+	if err := c.validateFiles(); err != nil {
+		return err
+	}
+
+	if err := c.validateRouting(); err != nil {
+		return err
+	}
+
+	if err := c.validateChannels(); err != nil {
+		return err
+	}
+
+	if err := c.validateContexts(); err != nil {
+		return err
+	}
+
 	// Validate file handler - luôn validate path nếu có
 	// (không phụ thuộc vào File.Enabled vì chúng ta luôn cần validate)
 
 	// Validate stack handler nếu được bật
 	if c.Stack.Enabled {
-		if !c.Stack.Handlers.Console && !c.Stack.Handlers.File {
+		for _, name := range c.Stack.Include {
+			if name == "" || HandlerType(name) == HandlerTypeStack {
+				return &ConfigError{
+					Field:   "stack.include",
+					Value:   name,
+					Message: "stack cannot include an empty name or itself",
+				}
+			}
+		}
+
+		if len(c.Stack.Members()) == 0 {
 			return &ConfigError{
 				Field:   "stack.handlers",
 				Message: "stack handler must have at least one sub-handler enabled",
 			}
 		}
 
+		if c.Stack.Concurrency < 0 {
+			return &ConfigError{
+				Field:   "stack.concurrency",
+				Value:   strconv.Itoa(c.Stack.Concurrency),
+				Message: "stack concurrency cannot be negative",
+			}
+		}
+
+		if c.Stack.Mode != "" {
+			if _, err := handler.ParseDispatchMode(c.Stack.Mode); err != nil {
+				return &ConfigError{
+					Field:   "stack.mode",
+					Value:   c.Stack.Mode,
+					Message: "invalid dispatch mode, must be one of: broadcast, first_success, failover",
+				}
+			}
+		}
+
+		for name, level := range c.Stack.Levels {
+			if !c.Stack.Contains(HandlerType(name)) {
+				return &ConfigError{
+					Field:   "stack.levels",
+					Value:   name,
+					Message: "stack level must name a stack member",
+				}
+			}
+			if _, err := handler.ParseLevel(level); err != nil {
+				return &ConfigError{
+					Field:   "stack.levels." + name,
+					Value:   level,
+					Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+				}
+			}
+		}
+
 		// File.Path đã được kiểm tra ở trên, không cần kiểm tra lại
 	}
 
 	return nil
 }
 
+// validateFallback kiểm tra cấu hình handler dự phòng của một handler.
+//
+// Tham số:
+//   - name: string - tên handler
+//   - fallback: FallbackConfig - cấu hình dự phòng
+//
+// Trả về:
+//   - error: ConfigError nếu cấu hình không hợp lệ
+func (c *Config) validateFallback(name string, fallback FallbackConfig) error {
+	field := "fallback." + name
+	if name == "" || HandlerType(name) == HandlerTypeStack {
+		return &ConfigError{
+			Field:   "fallback",
+			Value:   name,
+			Message: "fallback must name a handler other than stack, configure its members instead",
+		}
+	}
+	if fallback.Handler == "" || fallback.Handler == name || HandlerType(fallback.Handler) == HandlerTypeStack {
+		return &ConfigError{
+			Field:   field + ".handler",
+			Value:   fallback.Handler,
+			Message: "handler must name another registered handler other than stack",
+		}
+	}
+	// Handler dự phòng không được có dự phòng riêng để tránh chuỗi hoặc vòng dự phòng
+	if _, ok := c.Fallback[fallback.Handler]; ok {
+		return &ConfigError{
+			Field:   field + ".handler",
+			Value:   fallback.Handler,
+			Message: "fallback handler cannot have a fallback of its own",
+		}
+	}
+	if fallback.After < 0 {
+		return &ConfigError{
+			Field:   field + ".after",
+			Value:   strconv.Itoa(fallback.After),
+			Message: "after must be non-negative (0 for default)",
+		}
+	}
+	if _, ok := c.Delivery[name]; ok {
+		return &ConfigError{
+			Field:   field,
+			Value:   fallback.String(),
+			Message: "fallback cannot be combined with delivery, which handles failed entries itself",
+		}
+	}
+	return nil
+}
+
+// validateDelivery kiểm tra cấu hình giao nhận của một handler.
+//
+// Tham số:
+//   - name: string - tên handler
+//   - delivery: DeliveryConfig - cấu hình giao nhận
+//
+// Trả về:
+//   - error: ConfigError nếu cấu hình không hợp lệ
+func (c *Config) validateDelivery(name string, delivery DeliveryConfig) error {
+	field := "delivery." + name
+	if name == "" || HandlerType(name) == HandlerTypeStack {
+		return &ConfigError{
+			Field:   "delivery",
+			Value:   name,
+			Message: "delivery must name a handler other than stack, configure its members instead",
+		}
+	}
+
+	mode, err := handler.ParseDeliveryMode(delivery.Mode)
+	if err != nil {
+		return &ConfigError{
+			Field:   field + ".mode",
+			Value:   delivery.Mode,
+			Message: "invalid delivery mode, must be one of: best_effort, at_least_once, guaranteed",
+		}
+	}
+
+	switch mode {
+	case handler.AtLeastOnce:
+		if delivery.SpillPath == "" {
+			return &ConfigError{
+				Field:   field + ".spill_path",
+				Message: "spill_path is required for at_least_once delivery",
+			}
+		}
+	case handler.Guaranteed:
+		if _, ok := c.Async[name]; ok {
+			return &ConfigError{
+				Field:   field + ".mode",
+				Value:   delivery.Mode,
+				Message: "guaranteed delivery is synchronous and cannot be combined with async",
+			}
+		}
+	}
+
+	if delivery.Retries < 0 || delivery.RetryDelay < 0 {
+		return &ConfigError{
+			Field:   field,
+			Value:   delivery.String(),
+			Message: "retries and retry_delay must be non-negative",
+		}
+	}
+	return nil
+}
+
+// ValidateRuntime kiểm tra các thư mục mà cấu hình ghi vào (file.path, files.<name>.path,
+// channels.<name>.path và spill_path của delivery at_least_once) có tồn tại và có quyền ghi
+// không. Phương thức tạo rồi xóa một file tạm trong mỗi thư mục và giả định cấu hình đã qua
+// ValidateSyntax.
+//
+// Trả về:
+//   - error: ConfigError của đường dẫn đầu tiên không dùng được
+func (c *Config) ValidateRuntime() error {
+	for _, target := range c.runtimePaths() {
+		if err := c.validateAndCreateLogDir(target.path); err != nil {
+			return &ConfigError{
+				Field:   target.field,
+				Value:   target.path,
+				Message: target.message + err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// runtimePath là một đường dẫn được ValidateRuntime kiểm tra.
+type runtimePath struct {
+	field   string // Field của ConfigError
+	path    string // Đường dẫn file
+	message string // Tiền tố thông điệp lỗi
+}
+
+// runtimePaths trả về các đường dẫn mà cấu hình ghi vào: file chính, các file output và channel
+// theo thứ tự tên, rồi spill_path của các delivery at_least_once theo thứ tự tên.
+func (c *Config) runtimePaths() []runtimePath {
+	const dirFailed = "log directory validation failed: "
+
+	var paths []runtimePath
+	if c.File.Path != "" {
+		paths = append(paths, runtimePath{"file.path", c.File.Path, dirFailed})
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Files)) {
+		if path := c.Files[name].Path; path != "" {
+			paths = append(paths, runtimePath{"files." + name + ".path", path, dirFailed})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Channels)) {
+		if path := c.Channels[name].Path; path != "" {
+			paths = append(paths, runtimePath{"channels." + name + ".path", path, dirFailed})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Delivery)) {
+		delivery := c.Delivery[name]
+		if mode, err := handler.ParseDeliveryMode(delivery.Mode); err == nil && mode == handler.AtLeastOnce && delivery.SpillPath != "" {
+			paths = append(paths, runtimePath{"delivery." + name + ".spill_path", delivery.SpillPath, "spill directory validation failed: "})
+		}
+	}
+	return paths
+}
+
 // validateAndCreateLogDir kiểm tra thư mục log có tồn tại và có quyền ghi không.
 //
 // Phương thức này:
 //   - Kiểm tra thư mục cha của file log có tồn tại không
 //   - Kiểm tra quyền ghi vào thư mục
 //   - KHÔNG tự động tạo thư mục
+//   - Với file đặc biệt đã tồn tại (VD: os.DevNull, named pipe), chỉ kiểm tra file ghi được
 //
 // Tham số:
 //   - logPath: Đường dẫn file log
@@ -195,6 +1550,16 @@ func (c *Config) Validate() error {
 // Trả về:
 //   - error: Lỗi nếu thư mục không tồn tại hoặc không có quyền ghi
 func (c *Config) validateAndCreateLogDir(logPath string) error {
+	// File đặc biệt không bị xoay vòng nên không cần quyền ghi thư mục chứa nó
+	if info, err := os.Stat(logPath); err == nil && !info.Mode().IsRegular() && !info.IsDir() {
+		file, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("cannot open file for writing: %w", err)
+		}
+		file.Close()
+		return nil
+	}
+
 	// Lấy thư mục cha của file log
 	logDir := filepath.Dir(logPath)
 
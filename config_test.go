@@ -81,6 +81,71 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: "max_size must be non-negative",
 		},
+		{
+			name: "file_handler_with_negative_max_backups",
+			config: &Config{
+				Level: handler.InfoLevel,
+				Console: ConsoleConfig{
+					Enabled: false,
+				},
+				File: FileConfig{
+					Enabled:    true,
+					Path:       "/tmp/logs",
+					MaxBackups: -1,
+				},
+				Stack: StackConfig{
+					Enabled: false,
+				},
+			},
+			expectedErr: "max_backups and max_age must be non-negative",
+		},
+		{
+			name: "console_handler_with_invalid_color_style",
+			config: &Config{
+				Level: handler.InfoLevel,
+				Console: ConsoleConfig{
+					Enabled: true,
+					Colored: true,
+					Colors:  ColorsConfig{Warning: "bold orange"},
+				},
+				Stack: StackConfig{
+					Enabled: false,
+				},
+			},
+			expectedErr: "invalid style",
+		},
+		{
+			name: "console_handler_with_invalid_stderr_level",
+			config: &Config{
+				Level: handler.InfoLevel,
+				Console: ConsoleConfig{
+					Enabled:     true,
+					StderrLevel: "critical",
+				},
+				Stack: StackConfig{
+					Enabled: false,
+				},
+			},
+			expectedErr: "invalid log level",
+		},
+		{
+			name: "file_handler_with_invalid_sync_on_level",
+			config: &Config{
+				Level: handler.InfoLevel,
+				Console: ConsoleConfig{
+					Enabled: false,
+				},
+				File: FileConfig{
+					Enabled:     true,
+					Path:        "/tmp/logs",
+					SyncOnLevel: "critical",
+				},
+				Stack: StackConfig{
+					Enabled: false,
+				},
+			},
+			expectedErr: "invalid log level",
+		},
 		{
 			name: "stack_handler_enabled_without_sub_handlers",
 			config: &Config{
@@ -664,3 +729,140 @@ func BenchmarkConfig_Validate_MaxSizeVariations(b *testing.B) {
 		})
 	}
 }
+
+func TestConfig_ValidateMiddleware(t *testing.T) {
+	handler.RegisterMiddleware("test_throttle", handler.Throttle(handler.ThrottleOptions{}))
+
+	config := DefaultConfig()
+	config.Middleware = map[string][]string{"file": {"test_throttle"}}
+	assert.NoError(t, config.Validate())
+
+	config.Middleware = map[string][]string{"file": {"missing"}}
+	err := config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "middleware.file")
+	}
+
+	config.Middleware = map[string][]string{"stack": {"test_throttle"}}
+	assert.Error(t, config.Validate(), "Middleware không áp dụng cho stack")
+}
+
+func TestConfig_ValidateFold(t *testing.T) {
+	config := DefaultConfig()
+	config.Fold = map[string]string{"file": "field", "console": "Escape"}
+	assert.NoError(t, config.Validate())
+
+	config.Fold = map[string]string{"file": "array"}
+	err := config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "fold.file")
+	}
+
+	config.Fold = map[string]string{"stack": "escape"}
+	assert.Error(t, config.Validate(), "Gộp dòng không áp dụng cho stack")
+}
+
+func TestStackConfig_Members(t *testing.T) {
+	stack := StackConfig{
+		Enabled:  true,
+		Handlers: StackHandlers{File: true},
+		Include:  []string{"loki", "file", "sentry"},
+	}
+
+	assert.Equal(t, []HandlerType{HandlerTypeFile, "loki", "sentry"}, stack.Members())
+	assert.True(t, stack.Contains("sentry"))
+	assert.False(t, stack.Contains(HandlerTypeConsole))
+}
+
+func TestConfig_ValidateStackInclude(t *testing.T) {
+	config := DefaultConfig()
+	config.File.Enabled = false
+	config.Stack.Enabled = true
+	config.Stack.Include = []string{"loki"}
+	assert.NoError(t, config.Validate(), "Stack chỉ gồm handler tùy chỉnh nên hợp lệ")
+
+	config.Stack.Include = []string{"stack"}
+	err := config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stack.include")
+	}
+
+	config.Stack.Include = []string{"loki"}
+	config.Stack.Levels = map[string]string{"loki": "error"}
+	assert.NoError(t, config.Validate(), "Cấp độ riêng của handler con hợp lệ")
+
+	config.Stack.Levels = map[string]string{"sentry": "error"}
+	err = config.Validate()
+	if assert.Error(t, err, "Cấp độ riêng phải nêu handler thuộc stack") {
+		assert.Contains(t, err.Error(), "stack.levels")
+	}
+
+	config.Stack.Levels = map[string]string{"loki": "verbose"}
+	err = config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stack.levels.loki")
+	}
+	config.Stack.Levels = nil
+
+	config.Stack.Mode = "round_robin"
+	err = config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stack.mode")
+	}
+	config.Stack.Mode = "failover"
+	assert.NoError(t, config.Validate())
+
+	config.Stack.Concurrency = -1
+	err = config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stack.concurrency")
+	}
+	config.Stack.Concurrency = 4
+	assert.NoError(t, config.Validate())
+
+	config.Stack.Include = []string{"file"}
+	config.File.Path = ""
+	err = config.Validate()
+	if assert.Error(t, err, "Stack tham chiếu file handler nên yêu cầu file.path") {
+		assert.Contains(t, err.Error(), "file.path")
+	}
+}
+
+func TestConfig_ValidateSyntax(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	config := DefaultConfig()
+	config.File.Path = filepath.Join(missing, "app.log")
+	config.Files = map[string]FileOutputConfig{"errors": {Path: filepath.Join(missing, "errors.log")}}
+	config.Delivery = map[string]DeliveryConfig{
+		"file": {Mode: "at_least_once", SpillPath: filepath.Join(missing, "spill")},
+	}
+
+	assert.NoError(t, config.ValidateSyntax(), "Kiểm tra cú pháp không truy cập thư mục log")
+	_, err := os.Stat(missing)
+	assert.True(t, os.IsNotExist(err), "Kiểm tra cú pháp không được tạo thư mục")
+
+	err = config.ValidateRuntime()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "file.path")
+	}
+	err = config.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "file.path")
+	}
+
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	err = config.ValidateRuntime()
+	if assert.Error(t, err, "Mọi đường dẫn ghi đều được kiểm tra") {
+		assert.Contains(t, err.Error(), "files.errors.path")
+	}
+
+	config.Files = nil
+	err = config.ValidateRuntime()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "delivery.file.spill_path")
+		assert.Contains(t, err.Error(), "spill directory validation failed")
+	}
+
+	config.Level = handler.Level(42)
+	assert.Error(t, config.ValidateSyntax(), "Lỗi cú pháp vẫn được phát hiện")
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// ContextConfig định nghĩa cấp độ và đích ghi riêng cho các logger có context khớp một mẫu
+// trong Config.Contexts.
+type ContextConfig struct {
+	// Level cấp độ tối thiểu của logger. Rỗng = theo channel chứa context hoặc Config.Level
+	Level string `mapstructure:"level" yaml:"level" json:"level"`
+
+	// Handlers tên các handler đã đăng ký với Manager mà logger ghi đến, thay cho console, file,
+	// stack và handler của channel (VD: ["file.users", "loki"]). Handler tùy chỉnh và file trong
+	// Config.Files được nêu ở đây không được gắn vào logger của channel "app", như với channel.
+	// Rỗng = định tuyến mặc định
+	Handlers []string `mapstructure:"handlers" yaml:"handlers" json:"handlers"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "level=debug handlers=console,loki".
+func (c ContextConfig) String() string {
+	return "level=" + c.Level + " handlers=" + strings.Join(c.Handlers, ",")
+}
+
+// contextConfig trả về cấu hình trong Config.Contexts áp dụng cho context.
+//
+// Mẫu trùng khớp chính xác được ưu tiên; nếu không, mẫu có ký tự đại diện dài nhất khớp với
+// context được chọn (VD: "UserService.Auth*" trước "UserService*"), mẫu cùng độ dài được so
+// sánh theo thứ tự từ điển.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa Contexts
+//   - context: string - context của logger
+//
+// Trả về:
+//   - ContextConfig: cấu hình của context
+//   - bool: false nếu không có mẫu nào khớp
+func contextConfig(config *Config, context string) (ContextConfig, bool) {
+	if c, ok := config.Contexts[context]; ok {
+		return c, true
+	}
+	best, found := "", false
+	for pattern := range config.Contexts {
+		if matched, _ := path.Match(pattern, context); !matched {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, found = pattern, true
+		}
+	}
+	return config.Contexts[best], found
+}
+
+// dedicatedRoute trả về các handler riêng của logger: Handlers trong Config.Contexts nếu có,
+// hoặc handler của channel có đích ghi chứa context.
+//
+// Trả về:
+//   - []HandlerType: handler riêng của logger
+//   - bool: false nếu logger dùng định tuyến của channel "app"
+func dedicatedRoute(config *Config, context string) ([]HandlerType, bool) {
+	if c, ok := contextConfig(config, context); ok && len(c.Handlers) > 0 {
+		types := make([]HandlerType, len(c.Handlers))
+		for i, h := range c.Handlers {
+			types[i] = HandlerType(h)
+		}
+		return types, true
+	}
+	if name, channel := channelOf(config, context); name != ChannelApp {
+		return channelTypes(name, channel), true
+	}
+	return nil, false
+}
+
+// validateContexts kiểm tra các mẫu context, cấp độ và tên handler trong Config.Contexts.
+//
+// Trả về:
+//   - error: ConfigError nếu cấu hình không hợp lệ
+func (c *Config) validateContexts() error {
+	patterns := make([]string, 0, len(c.Contexts))
+	for pattern := range c.Contexts {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		context := c.Contexts[pattern]
+		field := "contexts." + pattern
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return &ConfigError{
+				Field:   "contexts",
+				Value:   pattern,
+				Message: "context pattern must be non-empty and valid (e.g. UserService*)",
+			}
+		}
+		if context.Level != "" {
+			if _, err := handler.ParseLevel(context.Level); err != nil {
+				return &ConfigError{
+					Field:   field + ".level",
+					Value:   context.Level,
+					Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+				}
+			}
+		}
+		for _, h := range context.Handlers {
+			if h == "" || strings.ContainsAny(h, " \t\r\n") {
+				return &ConfigError{
+					Field:   field + ".handlers",
+					Value:   h,
+					Message: "handlers must name handlers registered with the manager",
+				}
+			}
+		}
+	}
+	return nil
+}
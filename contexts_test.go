@@ -0,0 +1,109 @@
+package log
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestContextConfig_Match(t *testing.T) {
+	config := &Config{Contexts: map[string]ContextConfig{
+		"UserService*":      {Level: "info"},
+		"UserService.Auth*": {Level: "debug"},
+		"UserService.Cache": {Level: "error"},
+	}}
+	tests := map[string]string{
+		"UserService":         "info",
+		"UserService.Auth":    "debug",
+		"UserService.AuthJWT": "debug",
+		"UserService.Cache":   "error",
+	}
+	for context, want := range tests {
+		if got, ok := contextConfig(config, context); !ok || got.Level != want {
+			t.Errorf("contextConfig(%q) = %q, %v, want %q", context, got.Level, ok, want)
+		}
+	}
+	if _, ok := contextConfig(config, "Order"); ok {
+		t.Error("Context không khớp mẫu nào không nên có cấu hình")
+	}
+}
+
+func TestManager_Contexts(t *testing.T) {
+	config := newChannelTestConfig(t)
+	config.Files = map[string]FileOutputConfig{"users": {Path: filepath.Join(filepath.Dir(config.File.Path), "users.log")}}
+	config.Contexts = map[string]ContextConfig{
+		"UserService*": {Level: "debug", Handlers: []string{"file.users", "loki"}},
+		"Payment":      {Level: "error"},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+	user := m.GetLogger("UserService.Auth")
+	loki := &recordingHandler{}
+	m.AddHandler("loki", loki)
+
+	user.Debug("token refreshed")
+	m.GetLogger("Payment").Warning("retrying charge")
+	m.GetLogger("Order").Info("order created")
+
+	if users := readLog(t, config.Files["users"].Path); !strings.Contains(users, "[UserService.Auth] token refreshed") || strings.Contains(users, "order created") {
+		t.Errorf("users.log chỉ nên chứa log của context khớp UserService*, got %q", users)
+	}
+	if !loki.contains("token refreshed") || loki.contains("order created") {
+		t.Errorf("Handler tùy chỉnh trong contexts chỉ nên nhận log của context khớp mẫu, got %v", loki.messages)
+	}
+	app := readLog(t, config.File.Path)
+	if strings.Contains(app, "token refreshed") || strings.Contains(app, "retrying charge") || !strings.Contains(app, "order created") {
+		t.Errorf("File ứng dụng không nên nhận log của context có handler riêng hoặc dưới cấp độ riêng, got %q", app)
+	}
+}
+
+func TestManager_ApplyConfigContexts(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	user := m.GetLogger("UserService").(*logger)
+	m.GetLogger("Order")
+
+	updated := newChannelTestConfig(t)
+	updated.File.Path = config.File.Path
+	updated.Contexts = map[string]ContextConfig{"User*": {Level: "debug", Handlers: []string{"console"}}}
+	diff, err := m.ApplyConfig(updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "contexts.User*") || !containsString(diff.Levels, "UserService") || containsString(diff.Levels, "Order") {
+		t.Errorf("Diff nên báo thay đổi contexts và cấp độ của context khớp mẫu, got %q", diff.String())
+	}
+	if user.getMinLevel() != handler.DebugLevel {
+		t.Errorf("Logger đã tồn tại nên nhận cấp độ mới, got %v", user.getMinLevel())
+	}
+	if len(diff.Routes) != 1 || diff.Routes[0].Context != "UserService" {
+		t.Errorf("Diff nên báo đổi route của UserService, got %+v", diff.Routes)
+	}
+
+	user.Info("profile updated")
+	if strings.Contains(readLog(t, config.File.Path), "profile updated") {
+		t.Error("Logger có handler riêng không nên ghi vào file ứng dụng")
+	}
+}
+
+func TestConfig_ValidateContexts(t *testing.T) {
+	for name, contexts := range map[string]map[string]ContextConfig{
+		"mẫu không hợp lệ":    {"User[": {}},
+		"mẫu rỗng":            {"": {Level: "info"}},
+		"cấp độ không hợp lệ": {"User*": {Level: "loud"}},
+		"tên handler rỗng":    {"User*": {Handlers: []string{""}}},
+	} {
+		config := createTestConfig()
+		config.Contexts = contexts
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "contexts") {
+			t.Errorf("Validate() nên từ chối cấu hình %s, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HandlerAction mô tả thao tác sẽ được thực hiện trên một handler khi áp dụng cấu hình mới.
+type HandlerAction string
+
+// Các thao tác handler có thể xuất hiện trong ConfigDiff.
+const (
+	// HandlerActionCreate handler chưa tồn tại và sẽ được tạo mới.
+	HandlerActionCreate HandlerAction = "create"
+
+	// HandlerActionRecreate handler đang tồn tại sẽ được đóng và tạo lại với cấu hình mới.
+	HandlerActionRecreate HandlerAction = "recreate"
+
+	// HandlerActionRemove handler đang tồn tại sẽ được đóng và gỡ bỏ.
+	HandlerActionRemove HandlerAction = "remove"
+)
+
+// FieldChange mô tả sự thay đổi của một trường cấu hình.
+type FieldChange struct {
+	// Field tên trường theo key cấu hình (VD: "level", "file.path")
+	Field string
+
+	// Old giá trị hiện tại
+	Old string
+
+	// New giá trị sau khi áp dụng
+	New string
+}
+
+// HandlerChange mô tả thao tác sẽ được thực hiện trên một handler.
+type HandlerChange struct {
+	// Type loại handler bị ảnh hưởng
+	Type HandlerType
+
+	// Action thao tác sẽ thực hiện
+	Action HandlerAction
+}
+
+// RouteChange mô tả sự thay đổi tập handler mà một logger sẽ ghi đến.
+type RouteChange struct {
+	// Context context của logger bị ảnh hưởng
+	Context string
+
+	// Old danh sách handler hiện tại của logger
+	Old []HandlerType
+
+	// New danh sách handler của logger sau khi áp dụng
+	New []HandlerType
+}
+
+// ConfigDiff là báo cáo về các thay đổi sẽ xảy ra khi áp dụng một cấu hình mới.
+//
+// ConfigDiff được trả về bởi Manager.ValidateConfig và Manager.ApplyConfig,
+// cho phép xem trước tác động của hot-reload trước khi thực sự áp dụng.
+type ConfigDiff struct {
+	// Fields các trường cấu hình thay đổi giá trị
+	Fields []FieldChange
+
+	// Handlers các handler sẽ được tạo, tạo lại hoặc gỡ bỏ
+	Handlers []HandlerChange
+
+	// Routes các logger có tập handler thay đổi
+	Routes []RouteChange
+
+	// Levels các logger có cấp độ log tối thiểu thay đổi
+	Levels []string
+
+	// DryRun true nếu báo cáo được tạo mà không áp dụng thay đổi
+	DryRun bool
+}
+
+// HasChanges trả về true nếu cấu hình mới khác với cấu hình hiện tại.
+//
+// Trả về:
+//   - bool: true nếu có ít nhất một thay đổi
+func (d *ConfigDiff) HasChanges() bool {
+	return len(d.Fields) > 0 || len(d.Handlers) > 0 || len(d.Routes) > 0 || len(d.Levels) > 0
+}
+
+// String trả về mô tả dễ đọc của các thay đổi, mỗi thay đổi một dòng.
+//
+// Trả về:
+//   - string: mô tả các thay đổi, hoặc "no changes" nếu không có thay đổi
+func (d *ConfigDiff) String() string {
+	if !d.HasChanges() {
+		return "no changes"
+	}
+
+	var b strings.Builder
+	for _, f := range d.Fields {
+		fmt.Fprintf(&b, "field %s: %q -> %q\n", f.Field, f.Old, f.New)
+	}
+	for _, h := range d.Handlers {
+		fmt.Fprintf(&b, "handler %s: %s\n", h.Type, h.Action)
+	}
+	for _, r := range d.Routes {
+		fmt.Fprintf(&b, "route %s: %v -> %v\n", r.Context, r.Old, r.New)
+	}
+	for _, l := range d.Levels {
+		fmt.Fprintf(&b, "level %s\n", l)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// diffFields so sánh hai cấu hình và trả về danh sách các trường thay đổi.
+//
+// Tham số:
+//   - old: *Config - cấu hình hiện tại
+//   - new: *Config - cấu hình mới
+//
+// Trả về:
+//   - []FieldChange: các trường có giá trị khác nhau, theo thứ tự khai báo trong Config
+func diffFields(old, new *Config) []FieldChange {
+	var changes []FieldChange
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+
+	add("level", old.Level.String(), new.Level.String())
+	add("console.enabled", strconv.FormatBool(old.Console.Enabled), strconv.FormatBool(new.Console.Enabled))
+	add("console.colored", strconv.FormatBool(old.Console.Colored), strconv.FormatBool(new.Console.Colored))
+	add("console.force_color", strconv.FormatBool(old.Console.ForceColor), strconv.FormatBool(new.Console.ForceColor))
+	add("console.colors", old.Console.Colors.String(), new.Console.Colors.String())
+	add("console.group_by", strings.Join(old.Console.GroupBy, ","), strings.Join(new.Console.GroupBy, ","))
+	add("console.omit_timestamp", strconv.FormatBool(old.Console.OmitTimestamp), strconv.FormatBool(new.Console.OmitTimestamp))
+	add("console.stderr_level", old.Console.StderrLevel, new.Console.StderrLevel)
+	add("console.output", old.Console.Output, new.Console.Output)
+	add("file.enabled", strconv.FormatBool(old.File.Enabled), strconv.FormatBool(new.File.Enabled))
+	add("file.path", old.File.Path, new.File.Path)
+	add("file.max_size", strconv.FormatInt(old.File.MaxSize, 10), strconv.FormatInt(new.File.MaxSize, 10))
+	add("file.format", old.File.Format, new.File.Format)
+	add("file.growth_alert", old.File.GrowthAlert.String(), new.File.GrowthAlert.String())
+	add("file.disk_guard", old.File.DiskGuard.String(), new.File.DiskGuard.String())
+	add("file.compression", old.File.Compression, new.File.Compression)
+	add("file.max_backups", strconv.Itoa(old.File.MaxBackups), strconv.Itoa(new.File.MaxBackups))
+	add("file.max_age", strconv.Itoa(old.File.MaxAge), strconv.Itoa(new.File.MaxAge))
+	add("file.sync", strconv.FormatBool(old.File.Sync), strconv.FormatBool(new.File.Sync))
+	add("file.sync_on_level", old.File.SyncOnLevel, new.File.SyncOnLevel)
+	add("file.signing", old.File.Signing.String(), new.File.Signing.String())
+	add("file.encryption", old.File.Encryption.String(), new.File.Encryption.String())
+	add("stack.enabled", strconv.FormatBool(old.Stack.Enabled), strconv.FormatBool(new.Stack.Enabled))
+	add("stack.handlers.console", strconv.FormatBool(old.Stack.Handlers.Console), strconv.FormatBool(new.Stack.Handlers.Console))
+	add("stack.handlers.file", strconv.FormatBool(old.Stack.Handlers.File), strconv.FormatBool(new.Stack.Handlers.File))
+	add("stack.include", strings.Join(old.Stack.Include, ","), strings.Join(new.Stack.Include, ","))
+	add("stack.mode", old.Stack.Mode, new.Stack.Mode)
+	add("stack.concurrency", strconv.Itoa(old.Stack.Concurrency), strconv.Itoa(new.Stack.Concurrency))
+	for _, name := range unionKeys(old.Stack.Levels, new.Stack.Levels) {
+		add("stack.levels."+name, old.Stack.Levels[name], new.Stack.Levels[name])
+	}
+	for _, name := range unionKeys(old.Async, new.Async) {
+		o, n := "", ""
+		if async, ok := old.Async[name]; ok {
+			o = async.String()
+		}
+		if async, ok := new.Async[name]; ok {
+			n = async.String()
+		}
+		add("async."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Delivery, new.Delivery) {
+		o, n := "", ""
+		if delivery, ok := old.Delivery[name]; ok {
+			o = delivery.String()
+		}
+		if delivery, ok := new.Delivery[name]; ok {
+			n = delivery.String()
+		}
+		add("delivery."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Fallback, new.Fallback) {
+		o, n := "", ""
+		if fallback, ok := old.Fallback[name]; ok {
+			o = fallback.String()
+		}
+		if fallback, ok := new.Fallback[name]; ok {
+			n = fallback.String()
+		}
+		add("fallback."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Retry, new.Retry) {
+		o, n := "", ""
+		if retry, ok := old.Retry[name]; ok {
+			o = retry.String()
+		}
+		if retry, ok := new.Retry[name]; ok {
+			n = retry.String()
+		}
+		add("retry."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Middleware, new.Middleware) {
+		add("middleware."+name, strings.Join(old.Middleware[name], ","), strings.Join(new.Middleware[name], ","))
+	}
+	for _, name := range unionKeys(old.Fold, new.Fold) {
+		add("fold."+name, old.Fold[name], new.Fold[name])
+	}
+	for _, name := range unionKeys(old.FieldFilters, new.FieldFilters) {
+		o, n := "", ""
+		if filter, ok := old.FieldFilters[name]; ok {
+			o = filter.String()
+		}
+		if filter, ok := new.FieldFilters[name]; ok {
+			n = filter.String()
+		}
+		add("field_filters."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Filters, new.Filters) {
+		o, n := "", ""
+		if filter, ok := old.Filters[name]; ok {
+			o = filter.String()
+		}
+		if filter, ok := new.Filters[name]; ok {
+			n = filter.String()
+		}
+		add("filters."+name, o, n)
+	}
+	for _, name := range unionKeys(old.Files, new.Files) {
+		o, n := "", ""
+		if output, ok := old.Files[name]; ok {
+			o = output.String()
+		}
+		if output, ok := new.Files[name]; ok {
+			n = output.String()
+		}
+		add("files."+name, o, n)
+	}
+	routing := func(rules []RoutingRuleConfig) string {
+		parts := make([]string, len(rules))
+		for i, rule := range rules {
+			parts[i] = rule.String()
+		}
+		return strings.Join(parts, "; ")
+	}
+	add("routing", routing(old.Routing), routing(new.Routing))
+	for _, name := range unionKeys(old.Channels, new.Channels) {
+		o, n := "", ""
+		if channel, ok := old.Channels[name]; ok {
+			o = channel.String()
+		}
+		if channel, ok := new.Channels[name]; ok {
+			n = channel.String()
+		}
+		add("channels."+name, o, n)
+	}
+	for _, pattern := range unionKeys(old.Contexts, new.Contexts) {
+		o, n := "", ""
+		if context, ok := old.Contexts[pattern]; ok {
+			o = context.String()
+		}
+		if context, ok := new.Contexts[pattern]; ok {
+			n = context.String()
+		}
+		add("contexts."+pattern, o, n)
+	}
+	add("sampling", old.Sampling.String(), new.Sampling.String())
+	add("redaction", old.Redaction.String(), new.Redaction.String())
+	add("pseudonymization", old.Pseudonymization.String(), new.Pseudonymization.String())
+	add("retention.default", old.Retention.Default, new.Retention.Default)
+	for _, context := range unionKeys(old.Retention.Contexts, new.Retention.Contexts) {
+		add("retention.contexts."+context, old.Retention.Contexts[context], new.Retention.Contexts[context])
+	}
+	add("context_fields", old.contextFieldsString(), new.contextFieldsString())
+	add("fields", old.fieldsString(), new.fieldsString())
+	add("metadata", old.Metadata.String(), new.Metadata.String())
+	add("siem", old.SIEM.String(), new.SIEM.String())
+	add("readiness", old.Readiness.String(), new.Readiness.String())
+	add("enable_caller", strconv.FormatBool(old.EnableCaller), strconv.FormatBool(new.EnableCaller))
+	add("caller_skip", strconv.Itoa(old.CallerSkip), strconv.Itoa(new.CallerSkip))
+	add("raw_messages", strconv.FormatBool(old.RawMessages), strconv.FormatBool(new.RawMessages))
+	add("watch", strconv.FormatBool(old.Watch), strconv.FormatBool(new.Watch))
+	add("max_field_depth", strconv.Itoa(old.MaxFieldDepth), strconv.Itoa(new.MaxFieldDepth))
+	add("max_field_elements", strconv.Itoa(old.MaxFieldElements), strconv.Itoa(new.MaxFieldElements))
+	add("max_fields", strconv.Itoa(old.MaxFields), strconv.Itoa(new.MaxFields))
+
+	return changes
+}
+
+// unionKeys trả về các key xuất hiện trong ít nhất một map, đã sắp xếp.
+func unionKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// equalTypes so sánh hai danh sách handler type.
+func equalTypes(a, b []HandlerType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,316 @@
+package log
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestDiffFields(t *testing.T) {
+	old := DefaultConfig()
+	updated := DefaultConfig()
+	updated.Level = handler.DebugLevel
+	updated.Console.Colored = false
+
+	changes := diffFields(old, updated)
+	if len(changes) != 2 {
+		t.Fatalf("diffFields() nên trả về 2 thay đổi, got %d: %v", len(changes), changes)
+	}
+	if changes[0].Field != "level" || changes[0].Old != "INFO" || changes[0].New != "DEBUG" {
+		t.Errorf("Thay đổi level không đúng, got %+v", changes[0])
+	}
+	if changes[1].Field != "console.colored" {
+		t.Errorf("Thay đổi console.colored không đúng, got %+v", changes[1])
+	}
+
+	if len(diffFields(old, DefaultConfig())) != 0 {
+		t.Error("diffFields() với hai cấu hình giống nhau nên không có thay đổi")
+	}
+}
+
+func TestConfigDiff_String(t *testing.T) {
+	diff := &ConfigDiff{}
+	if diff.HasChanges() {
+		t.Error("ConfigDiff rỗng không nên có thay đổi")
+	}
+	if diff.String() != "no changes" {
+		t.Errorf("ConfigDiff rỗng nên trả về 'no changes', got %q", diff.String())
+	}
+
+	diff.Fields = []FieldChange{{Field: "level", Old: "INFO", New: "DEBUG"}}
+	diff.Handlers = []HandlerChange{{Type: HandlerTypeFile, Action: HandlerActionRecreate}}
+	out := diff.String()
+	if !strings.Contains(out, `field level: "INFO" -> "DEBUG"`) {
+		t.Errorf("String() thiếu thay đổi field, got %q", out)
+	}
+	if !strings.Contains(out, "handler file: recreate") {
+		t.Errorf("String() thiếu thay đổi handler, got %q", out)
+	}
+}
+
+func TestRouteTypes(t *testing.T) {
+	config := createTestConfig()
+	types := routeTypes(config)
+	if !equalTypes(types, []HandlerType{HandlerTypeStack}) {
+		t.Errorf("Stack chứa console và file nên chỉ route đến stack, got %v", types)
+	}
+
+	config.Stack.Enabled = false
+	types = routeTypes(config)
+	if !equalTypes(types, []HandlerType{HandlerTypeConsole, HandlerTypeFile}) {
+		t.Errorf("Không có stack nên route đến console và file, got %v", types)
+	}
+}
+
+func TestManager_ValidateConfig_ConsoleGroupBy(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/group.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.Console.GroupBy = []string{"request_id"}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "console.group_by" || diff.Fields[0].New != "request_id" {
+		t.Errorf("Thay đổi console.group_by không đúng, got %+v", diff.Fields)
+	}
+	if len(diff.Handlers) == 0 || diff.Handlers[0].Type != HandlerTypeConsole {
+		t.Errorf("Thay đổi group_by nên tạo lại console handler, got %+v", diff.Handlers)
+	}
+}
+
+func TestManager_ValidateConfig_ConsoleOmitTimestamp(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/omit.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.Console.OmitTimestamp = true
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "console.omit_timestamp" || diff.Fields[0].New != "true" {
+		t.Errorf("Thay đổi console.omit_timestamp không đúng, got %+v", diff.Fields)
+	}
+	if len(diff.Handlers) == 0 || diff.Handlers[0].Type != HandlerTypeConsole {
+		t.Errorf("Thay đổi omit_timestamp nên tạo lại console handler, got %+v", diff.Handlers)
+	}
+}
+
+func TestManager_ApplyConfig_ConsoleOutput(t *testing.T) {
+	var buf strings.Builder
+	handler.RegisterConsoleWriter("diff-test", &buf)
+
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/output.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.Console.Output = "diff-test"
+	updated.Console.OmitTimestamp = true
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "console.output") {
+		t.Errorf("Diff nên liệt kê thay đổi của console.output, got %q", diff.String())
+	}
+	m.GetLogger("App").Error("failed")
+	if buf.String() != "[ERROR] [App] failed\n" {
+		t.Errorf("Console handler nên ghi vào writer đã đăng ký, got %q", buf.String())
+	}
+
+	updated.Console.Output = "missing"
+	if _, err := m.ValidateConfig(&updated); err == nil || !strings.Contains(err.Error(), "unknown console writer") {
+		t.Errorf("ValidateConfig() với writer chưa đăng ký nên trả về lỗi, got %v", err)
+	}
+}
+
+func TestManager_ValidateConfig_FileGrowthAlert(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/growth.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.File.GrowthAlert = GrowthAlertConfig{MaxRate: 50 << 20, Period: 5 * time.Minute}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.growth_alert" || diff.Fields[0].New != "max_rate=52428800 period=5m0s" {
+		t.Errorf("Thay đổi file.growth_alert không đúng, got %+v", diff.Fields)
+	}
+	if !strings.Contains(diff.String(), "handler file: recreate") {
+		t.Errorf("Thay đổi growth_alert nên tạo lại file handler, got %q", diff.String())
+	}
+
+	updated.File.GrowthAlert.Period = -time.Minute
+	if _, err := m.ValidateConfig(&updated); err == nil {
+		t.Error("ValidateConfig() nên từ chối period âm")
+	}
+}
+
+func TestManager_ValidateConfig_FileDiskGuard(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/disk.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.File.DiskGuard = DiskGuardConfig{MinFree: 1 << 30, Interval: 30 * time.Second}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.disk_guard" ||
+		diff.Fields[0].New != "min_free=1073741824 max_dir_size=0 interval=30s" {
+		t.Errorf("Thay đổi file.disk_guard không đúng, got %+v", diff.Fields)
+	}
+	if !strings.Contains(diff.String(), "handler file: recreate") {
+		t.Errorf("Thay đổi disk_guard nên tạo lại file handler, got %q", diff.String())
+	}
+
+	updated.File.DiskGuard.MaxDirSize = -1
+	if _, err := m.ValidateConfig(&updated); err == nil || !strings.Contains(err.Error(), "file.disk_guard") {
+		t.Errorf("ValidateConfig() nên từ chối max_dir_size âm, got %v", err)
+	}
+}
+
+func TestManager_ValidateConfig_FileCompression(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/compress.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.File.Compression = "gzip"
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.compression" || diff.Fields[0].New != "gzip" {
+		t.Errorf("Thay đổi file.compression không đúng, got %+v", diff.Fields)
+	}
+	if !strings.Contains(diff.String(), "handler file: recreate") {
+		t.Errorf("Thay đổi compression nên tạo lại file handler, got %q", diff.String())
+	}
+
+	updated.File.Compression = "brotli"
+	_, err = m.ValidateConfig(&updated)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "file.compression" {
+		t.Errorf("ValidateConfig() nên từ chối codec chưa đăng ký, got %v", err)
+	}
+}
+
+func TestManager_ValidateConfig_FileFormat(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/app.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	updated := *config
+	updated.File.Format = "json"
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.format" || !strings.Contains(diff.String(), "handler file: recreate") {
+		t.Errorf("Thay đổi file.format nên tạo lại file handler, got %q", diff.String())
+	}
+
+	updated.File.Format = "xml"
+	_, err = m.ValidateConfig(&updated)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "file.format" {
+		t.Errorf("ValidateConfig() nên từ chối định dạng không hợp lệ, got %v", err)
+	}
+}
+
+func TestManager_ValidateConfig_FileSigning(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/signed.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	key := "0123456789abcdef0123456789abcdef"
+	updated := *config
+	updated.File.Signing = SigningConfig{Key: key, Records: true}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.signing" {
+		t.Fatalf("Thay đổi file.signing không đúng, got %+v", diff.Fields)
+	}
+	if strings.Contains(diff.String(), key) {
+		t.Errorf("Diff không được lộ khóa, got %q", diff.String())
+	}
+	if !strings.Contains(diff.String(), "handler file: recreate") {
+		t.Errorf("Thay đổi signing nên tạo lại file handler, got %q", diff.String())
+	}
+
+	updated.File.Signing.Key = "short"
+	_, err = m.ValidateConfig(&updated)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "file.signing.key" {
+		t.Errorf("ValidateConfig() nên từ chối khóa quá ngắn, got %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "short") {
+		t.Errorf("Lỗi không được lộ khóa, got %v", err)
+	}
+}
+
+func TestManager_ValidateConfig_FileEncryption(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/secret.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	t.Setenv("TEST_LOG_ENCRYPTION_KEY", key)
+	updated := *config
+	updated.File.Encryption = EncryptionConfig{KeyEnv: "TEST_LOG_ENCRYPTION_KEY"}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "file.encryption" || diff.Fields[0].New != "key_env=TEST_LOG_ENCRYPTION_KEY" {
+		t.Fatalf("Thay đổi file.encryption không đúng, got %+v", diff.Fields)
+	}
+
+	tests := []struct {
+		name       string
+		encryption EncryptionConfig
+		field      string
+	}{
+		{"invalid key", EncryptionConfig{Key: "c2hvcnQ="}, "file.encryption.key"},
+		{"unset env", EncryptionConfig{KeyEnv: "TEST_LOG_ENCRYPTION_KEY_UNSET"}, "file.encryption.key_env"},
+		{"unknown provider", EncryptionConfig{KeyProvider: "missing-kms"}, "file.encryption.key_provider"},
+		{"several sources", EncryptionConfig{Key: key, KeyEnv: "TEST_LOG_ENCRYPTION_KEY"}, "file.encryption"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated.File.Encryption = tt.encryption
+			_, err := m.ValidateConfig(&updated)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("ValidateConfig() error = %v, want field %s", err, tt.field)
+			}
+			if err != nil && strings.Contains(err.Error(), key) {
+				t.Errorf("Lỗi không được lộ khóa, got %v", err)
+			}
+		})
+	}
+}
@@ -208,31 +208,24 @@
 //
 // # Middleware Logging
 //
-// Tích hợp với HTTP middleware trong Fork Framework:
+// Package middleware cung cấp sẵn HTTP logging middleware cho net/http, ghi method, path,
+// status, latency, bytes, IP client và request ID dưới dạng field có cấu trúc:
 //
-//	func LoggingMiddleware(manager log.Manager) func(http.Handler) http.Handler {
-//	    logger := manager.GetLogger("HTTPMiddleware")
+//	import "go.fork.vn/log/middleware"
 //
-//	    return func(next http.Handler) http.Handler {
-//	        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-//	            start := time.Now()
+//	config := middleware.DefaultConfig()
+//	config.Skip = func(r *http.Request) bool { return r.URL.Path == "/health" }
+//	http.ListenAndServe(":8080", middleware.New(manager, config)(mux))
 //
-//	            logger.Info("Request started",
-//	                "method", r.Method,
-//	                "path", r.URL.Path,
-//	                "remote_addr", r.RemoteAddr,
-//	            )
+// Các router phổ biến được hỗ trợ qua các adapter dùng chung middleware.Config:
 //
-//	            next.ServeHTTP(w, r)
+//	router.Use(ginlog.New(manager, nil), ginlog.Recovery(manager, nil)) // go.fork.vn/log/middleware/ginlog
+//	e.Use(echolog.New(manager, nil))                                   // go.fork.vn/log/middleware/echolog
+//	app.Use(fiberlog.New(manager, nil))                                // go.fork.vn/log/middleware/fiberlog
 //
-//	            logger.Info("Request completed",
-//	                "method", r.Method,
-//	                "path", r.URL.Path,
-//	                "duration", time.Since(start).String(),
-//	            )
-//	        })
-//	    }
-//	}
+// Field có cấu trúc cũng có thể được truyền trực tiếp cho logger:
+//
+//	logger.Info("Request completed", log.Any("status", 200), log.Any("path", r.URL.Path))
 //
 // # Performance Monitoring
 //
@@ -277,12 +270,15 @@
 //
 // # Testing với Mock Loggers
 //
-// Package cung cấp mock interfaces cho testing:
+// Package go.fork.vn/log/mocks cung cấp mock tương thích mockery cho Logger, Manager và
+// handler.Handler:
 //
 //	func TestUserService_CreateUser(t *testing.T) {
-//	    // Setup mock logger
-//	    mockLogger := &MockLogger{}
-//	    service := &UserService{logger: mockLogger}
+//	    // Mock sinh bởi mockery trong go.fork.vn/log/mocks, tự kiểm tra kỳ vọng khi test kết thúc
+//	    logger := mocks.NewMockLogger(t)
+//	    logger.EXPECT().Info("Creating user %s", "testuser").Once()
+//	    logger.EXPECT().Info("User created successfully").Once()
+//	    service := &UserService{logger: logger}
 //
 //	    // Execute
 //	    user := &User{Username: "testuser"}
@@ -290,8 +286,6 @@
 //
 //	    // Assert
 //	    assert.NoError(t, err)
-//	    assert.Contains(t, mockLogger.Logs, "Creating user")
-//	    assert.Contains(t, mockLogger.Logs, "User created successfully")
 //	}
 //
 // # Default Configuration
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// elevation lưu trạng thái của một lần nâng cấp độ log tạm thời cho một context.
+type elevation struct {
+	previous handler.Level // Cấp độ sẽ được khôi phục khi hết hạn
+	level    handler.Level // Cấp độ tạm thời đang áp dụng
+	timer    *time.Timer   // Timer khôi phục cấp độ khi hết hạn
+}
+
+// ElevateLevel tạm thời thay đổi cấp độ log của một context và tự động khôi phục sau duration.
+//
+// Method này phù hợp cho việc debug có giới hạn thời gian trên môi trường production:
+// cấp độ trước đó luôn được khôi phục kể cả khi người vận hành quên tắt. Logger của context
+// sẽ được tạo nếu chưa tồn tại. Mỗi lần nâng và khôi phục cấp độ đều được ghi một bản ghi
+// kiểm toán qua logger của context, bất kể cấp độ hiện tại của logger; bản ghi này cũng được
+// sao chép đến channel "audit" khi channel đó có đích ghi (xem Config.Channels).
+//
+// Gọi lại ElevateLevel khi context đang được nâng cấp độ sẽ thay thế cấp độ tạm thời và
+// tính lại thời hạn, nhưng vẫn khôi phục về cấp độ ban đầu. Nếu cấp độ của logger bị thay đổi
+// bởi SetMinLevel trong thời gian nâng, cấp độ đó được giữ nguyên khi hết hạn. ApplyConfig
+// không hủy lần nâng đang diễn ra mà thay đổi cấp độ sẽ được khôi phục. Method này là thread-safe.
+//
+// Tham số:
+//   - context: string - context của logger cần thay đổi cấp độ
+//   - level: handler.Level - cấp độ log tạm thời
+//   - duration: time.Duration - thời gian áp dụng cấp độ tạm thời, phải lớn hơn 0
+//
+// Trả về:
+//   - error: lỗi nếu cấp độ hoặc thời gian không hợp lệ
+//
+// Ví dụ:
+//
+//	// Bật debug log cho PaymentService trong 15 phút
+//	if err := manager.ElevateLevel("PaymentService", handler.DebugLevel, 15*time.Minute); err != nil {
+//	    return err
+//	}
+func (m *manager) ElevateLevel(context string, level handler.Level, duration time.Duration) error {
+	if level < handler.DebugLevel || level > handler.FatalLevel {
+		return fmt.Errorf("invalid log level: %d", level)
+	}
+	if duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	l, ok := m.GetLogger(context).(*logger)
+	if !ok {
+		return fmt.Errorf("logger %q does not support level elevation", context)
+	}
+
+	m.mu.Lock()
+	previous := l.getMinLevel()
+	if e := m.elevated[context]; e != nil {
+		if e.timer.Stop() {
+			m.timers.Done()
+		}
+		previous = e.previous
+	}
+	e := &elevation{previous: previous, level: level}
+	m.timers.Add(1)
+	e.timer = time.AfterFunc(duration, func() {
+		defer m.timers.Done()
+		m.restoreLevel(context, e)
+	})
+	m.elevated[context] = e
+	l.SetMinLevel(level)
+	m.mu.Unlock()
+
+	m.audit(context, l, "log level elevated",
+		Any("level", level),
+		Any("previous", previous),
+		Any("duration", duration),
+	)
+	return nil
+}
+
+// restoreLevel khôi phục cấp độ log của context khi một lần nâng cấp độ hết hạn.
+//
+// Tham số:
+//   - context: string - context của logger cần khôi phục
+//   - e: *elevation - lần nâng cấp độ đã hết hạn; bị bỏ qua nếu đã bị thay thế hoặc hủy
+func (m *manager) restoreLevel(context string, e *elevation) {
+	m.mu.Lock()
+	if m.elevated[context] != e {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.elevated, context)
+	l, ok := m.loggers[context].(*logger)
+	if !ok || l.getMinLevel() != e.level {
+		m.mu.Unlock()
+		return
+	}
+	l.SetMinLevel(e.previous)
+	m.mu.Unlock()
+
+	m.audit(context, l, "log level restored", Any("level", e.previous), Any("elevated", e.level))
+}
@@ -0,0 +1,167 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// recordingHandler ghi lại thông điệp một cách thread-safe để kiểm tra các log entry
+// được ghi từ goroutine khác (VD: timer khôi phục cấp độ)
+type recordingHandler struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *recordingHandler) Log(level handler.Level, message string, args ...interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *recordingHandler) Close() error {
+	return nil
+}
+
+func (r *recordingHandler) contains(substr string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range r.messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// newElevateTestManager tạo manager với logger "Payment" ở cấp độ Error và một recordingHandler.
+func newElevateTestManager(t *testing.T) (*manager, *logger, *recordingHandler) {
+	config := createTestConfig()
+	config.Level = handler.ErrorLevel
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = t.TempDir() + "/elevate.log"
+
+	m := NewManager(config).(*manager)
+	t.Cleanup(func() { m.Close() })
+
+	l := m.GetLogger("Payment").(*logger)
+	rec := &recordingHandler{}
+	m.AddHandler(TestHandlerType, rec)
+	return m, l, rec
+}
+
+// waitFor chờ cho đến khi điều kiện đúng hoặc hết thời gian chờ.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Hết thời gian chờ điều kiện")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_ElevateLevel(t *testing.T) {
+	m, l, rec := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, 50*time.Millisecond); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	if got := l.getMinLevel(); got != handler.DebugLevel {
+		t.Fatalf("Cấp độ nên được nâng lên DEBUG, got %v", got)
+	}
+	if !rec.contains("[Payment] log level elevated level=DEBUG previous=ERROR duration=50ms") {
+		t.Errorf("Nên ghi bản ghi kiểm toán khi nâng cấp độ dù logger đang ở ERROR, got %v", rec.messages)
+	}
+
+	l.Debug("debug message")
+	if !rec.contains("debug message") {
+		t.Error("Debug log nên được ghi trong thời gian nâng cấp độ")
+	}
+
+	waitFor(t, func() bool { return l.getMinLevel() == handler.ErrorLevel })
+	waitFor(t, func() bool { return rec.contains("log level restored level=ERROR elevated=DEBUG") })
+}
+
+func TestManager_ElevateLevel_ExtendKeepsOriginalLevel(t *testing.T) {
+	m, l, _ := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.WarningLevel, time.Hour); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, 20*time.Millisecond); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+
+	waitFor(t, func() bool { return l.getMinLevel() == handler.ErrorLevel })
+}
+
+func TestManager_ElevateLevel_ManualChangeWins(t *testing.T) {
+	m, l, rec := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, 20*time.Millisecond); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	l.SetMinLevel(handler.WarningLevel)
+
+	waitFor(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return m.elevated["Payment"] == nil
+	})
+	if got := l.getMinLevel(); got != handler.WarningLevel {
+		t.Errorf("Cấp độ được thay đổi thủ công không nên bị ghi đè khi hết hạn, got %v", got)
+	}
+	if rec.contains("log level restored") {
+		t.Error("Không nên ghi bản ghi khôi phục khi cấp độ đã bị thay đổi thủ công")
+	}
+}
+
+func TestManager_ElevateLevel_ApplyConfig(t *testing.T) {
+	m, l, _ := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, 50*time.Millisecond); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+
+	config := *m.config
+	config.Level = handler.WarningLevel
+	if _, err := m.ApplyConfig(&config, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if got := l.getMinLevel(); got != handler.DebugLevel {
+		t.Errorf("ApplyConfig không nên hủy lần nâng cấp độ đang diễn ra, got %v", got)
+	}
+
+	waitFor(t, func() bool { return l.getMinLevel() == handler.WarningLevel })
+}
+
+func TestManager_ElevateLevel_Invalid(t *testing.T) {
+	m, _, _ := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.Level(42), time.Minute); err == nil {
+		t.Error("ElevateLevel() nên trả về lỗi với cấp độ không hợp lệ")
+	}
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, 0); err == nil {
+		t.Error("ElevateLevel() nên trả về lỗi với duration không dương")
+	}
+}
+
+func TestManager_Close_CancelsElevation(t *testing.T) {
+	m, _, _ := newElevateTestManager(t)
+
+	if err := m.ElevateLevel("Payment", handler.DebugLevel, time.Hour); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	m.Close()
+
+	if len(m.elevated) != 0 {
+		t.Errorf("Close() nên hủy các lần nâng cấp độ đang chờ, got %d", len(m.elevated))
+	}
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// ErrorHandler nhận lỗi khi một handler không ghi được log entry (VD: đĩa đầy, sink từ xa
+// không phản hồi). Hàm này được gọi đồng bộ trên goroutine ghi log nên phải nhanh và không
+// được ghi log qua chính logger gây lỗi để tránh đệ quy.
+type ErrorHandler func(handlerType HandlerType, err error)
+
+// errorReporter xử lý và đếm lỗi ghi log của handler, dùng chung giữa manager và các logger
+// của nó.
+type errorReporter struct {
+	handler atomic.Pointer[ErrorHandler] // Hàm nhận lỗi, nil = ghi ra stderr
+	count   atomic.Uint64                // Tổng số lỗi ghi log
+}
+
+// report đếm lỗi và gửi lỗi đến ErrorHandler đã đặt, hoặc ghi ra stderr.
+func (r *errorReporter) report(handlerType HandlerType, err error) {
+	r.count.Add(1)
+	if fn := r.handler.Load(); fn != nil {
+		(*fn)(handlerType, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Lỗi khi ghi log đến handler %s: %v\n", handlerType, err)
+}
+
+// set thay thế hàm nhận lỗi, nil để ghi ra stderr.
+func (r *errorReporter) set(fn ErrorHandler) {
+	if fn == nil {
+		r.handler.Store(nil)
+		return
+	}
+	r.handler.Store(&fn)
+}
+
+// WithErrorHandler đặt hàm nhận lỗi khi một handler của logger không ghi được entry, thay
+// cho việc ghi lỗi ra stderr. Logger do Manager tạo dùng hàm của Manager.SetErrorHandler.
+//
+// Tham số:
+//   - fn: ErrorHandler - hàm nhận lỗi, nil để ghi ra stderr
+//
+// Trả về:
+//   - LoggerOption: tùy chọn đặt hàm nhận lỗi
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Worker", log.WithErrorHandler(func(t log.HandlerType, err error) {
+//	    metrics.LogErrors.WithLabelValues(string(t)).Inc()
+//	}))
+func WithErrorHandler(fn ErrorHandler) LoggerOption {
+	return func(l *logger) {
+		l.errors = &errorReporter{}
+		l.errors.set(fn)
+	}
+}
+
+// withErrorReporter dùng reporter của manager cho logger.
+func withErrorReporter(r *errorReporter) LoggerOption {
+	return func(l *logger) {
+		l.errors = r
+	}
+}
+
+// SetErrorHandler đặt hàm nhận lỗi khi một handler không ghi được log entry, áp dụng cho mọi
+// logger của manager (kể cả logger đã tạo). Mặc định lỗi được ghi ra stderr. Method này là
+// thread-safe.
+//
+// Tham số:
+//   - fn: ErrorHandler - hàm nhận lỗi, nil để ghi ra stderr
+//
+// Ví dụ:
+//
+//	manager.SetErrorHandler(func(t log.HandlerType, err error) {
+//	    alerting.Notify("logging", fmt.Sprintf("handler %s: %v", t, err))
+//	})
+func (m *manager) SetErrorHandler(fn ErrorHandler) {
+	m.errors.set(fn)
+}
+
+// ErrorCount trả về tổng số lần một handler không ghi được log entry, kể từ khi manager được tạo.
+//
+// Trả về:
+//   - uint64: số lỗi ghi log
+//
+// Ví dụ:
+//
+//	if n := manager.ErrorCount(); n > 0 {
+//	    fmt.Fprintf(os.Stderr, "%d entry không ghi được\n", n)
+//	}
+func (m *manager) ErrorCount() uint64 {
+	return m.errors.count.Load()
+}
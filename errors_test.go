@@ -0,0 +1,79 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestManager_SetErrorHandler(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/errors.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	failing := &MockHandler{ShouldError: true}
+	m.AddHandler(TestHandlerType, failing)
+	logger := m.GetLogger("Order")
+
+	var reported []HandlerType
+	m.SetErrorHandler(func(handlerType HandlerType, err error) {
+		if err == nil {
+			t.Error("ErrorHandler nên nhận lỗi khác nil")
+		}
+		reported = append(reported, handlerType)
+	})
+	logger.Info("first")
+	m.GetLogger("Payment").Info("second")
+	if len(reported) != 2 || reported[0] != TestHandlerType {
+		t.Errorf("ErrorHandler nên nhận lỗi của mọi logger kèm tên handler, got %v", reported)
+	}
+	if got := m.ErrorCount(); got != 2 {
+		t.Errorf("ErrorCount() = %d, want 2", got)
+	}
+
+	// nil khôi phục việc ghi lỗi ra stderr nhưng vẫn đếm lỗi
+	m.SetErrorHandler(nil)
+	logger.Info("third")
+	if len(reported) != 2 || m.ErrorCount() != 3 {
+		t.Errorf("SetErrorHandler(nil) nên ngừng gọi hàm cũ và vẫn đếm lỗi, got %v, count %d", reported, m.ErrorCount())
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var count int
+	logger := NewLogger("Worker", WithErrorHandler(func(HandlerType, error) { count++ }))
+	logger.AddHandler(TestHandlerType, &MockHandler{ShouldError: true})
+	logger.AddHandler(HandlerTypeConsole, &MockHandler{})
+
+	logger.Warning("disk failure")
+	if count != 1 {
+		t.Errorf("ErrorHandler của logger nên nhận đúng một lỗi, got %d", count)
+	}
+}
+
+func TestManager_SetErrorHandler_Async(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/errors.log"
+	config.Async = map[string]AsyncConfig{string(TestHandlerType): {Workers: 1, QueueSize: 16}}
+	m := NewManager(config)
+	defer m.Close()
+
+	reported := make(chan HandlerType, 1)
+	m.SetErrorHandler(func(handlerType HandlerType, err error) { reported <- handlerType })
+	m.AddHandler(TestHandlerType, &MockHandler{ShouldError: true})
+	m.GetLogger("Order").Info("queued")
+	if err := m.FlushAll(); err != nil {
+		t.Fatalf("FlushAll() error = %v", err)
+	}
+
+	select {
+	case got := <-reported:
+		if got != TestHandlerType {
+			t.Errorf("ErrorHandler nên nhận tên của handler bất đồng bộ, got %v", got)
+		}
+	default:
+		t.Fatal("Lỗi ghi trên worker của handler bất đồng bộ nên đến ErrorHandler")
+	}
+	if got := m.ErrorCount(); got != 1 {
+		t.Errorf("ErrorCount() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,375 @@
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// ContextKey là kiểu key để lưu giá trị vào context.Context mà Config.ContextFields lấy được
+// theo tên.
+//
+// Ví dụ:
+//
+//	ctx = context.WithValue(ctx, log.ContextKey("request_id"), requestID)
+//	logger.InfoContext(ctx, "Order created") // [Order] Order created request_id=...
+type ContextKey string
+
+// FieldRequestID là tên field và tên key trong context của request ID (xem WithRequestID).
+const FieldRequestID = "request_id"
+
+// WithRequestID trả về context mang request ID dưới key ContextKey(FieldRequestID), để mọi
+// logger lấy field request_id từ context (qua Config.ContextFields hoặc middleware.RequestID)
+// gắn ID vào entry được ghi qua các method *Context.
+//
+// Tham số:
+//   - ctx: context.Context - context gốc
+//   - id: string - request ID
+//
+// Trả về:
+//   - context.Context: context mang request ID
+//
+// Ví dụ:
+//
+//	ctx = log.WithRequestID(ctx, log.NewRequestID())
+//	logger.InfoContext(ctx, "Job started") // [Worker] Job started request_id=...
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKey(FieldRequestID), id)
+}
+
+// RequestID trả về request ID được lưu bởi WithRequestID.
+//
+// Tham số:
+//   - ctx: context.Context - context cần đọc
+//
+// Trả về:
+//   - string: request ID, rỗng nếu ctx không có
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ContextKey(FieldRequestID)).(string)
+	return id
+}
+
+// NewRequestID tạo request ID ngẫu nhiên 32 ký tự hex.
+//
+// Trả về:
+//   - string: request ID mới
+func NewRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000000000000000000000000000"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ContextField ánh xạ một giá trị trong context.Context thành field của entry được ghi qua các
+// method *Context (VD: InfoContext).
+type ContextField struct {
+	Key   interface{} // Key của giá trị trong context (VD: log.ContextKey("tenant") hoặc key riêng của package)
+	Field string      // Tên field của giá trị
+
+	// Extract tính các field từ context thay cho Key và Field, dùng khi giá trị không được lưu
+	// trực tiếp theo một key (VD: trace ID của span OpenTelemetry, xem package otellog).
+	// Trả về nil khi context không có giá trị
+	Extract func(ctx context.Context) []Field
+}
+
+// ContextFieldConfig định nghĩa một giá trị được lấy từ context.Context thành field.
+type ContextFieldConfig struct {
+	// Key tên key của giá trị, được tìm với log.ContextKey(Key) rồi với chuỗi Key
+	// (VD: gin.Context lưu giá trị theo key chuỗi)
+	Key string `mapstructure:"key" yaml:"key" json:"key"`
+
+	// Field tên field ghi giá trị. Rỗng = dùng Key
+	Field string `mapstructure:"field" yaml:"field" json:"field"`
+}
+
+// name trả về tên field của cấu hình.
+func (c ContextFieldConfig) name() string {
+	if c.Field == "" {
+		return c.Key
+	}
+	return c.Field
+}
+
+// contextFields trả về các ContextField theo Config.ContextFields.
+func (c *Config) contextFields() []ContextField {
+	fields := make([]ContextField, 0, len(c.ContextFields))
+	for _, f := range c.ContextFields {
+		fields = append(fields, ContextField{Key: ContextKey(f.Key), Field: f.name()})
+	}
+	return fields
+}
+
+// contextFieldsString trả về mô tả ngắn gọn của Config.ContextFields, VD: "request_id=request_id,tenant=tenant_id".
+func (c *Config) contextFieldsString() string {
+	parts := make([]string, len(c.ContextFields))
+	for i, f := range c.ContextFields {
+		parts[i] = f.Key + "=" + f.name()
+	}
+	return strings.Join(parts, ",")
+}
+
+// validateContextFields kiểm tra các key và tên field.
+func (c *Config) validateContextFields() error {
+	for _, f := range c.ContextFields {
+		if f.Key == "" || strings.ContainsAny(f.name(), " =\t\r\n") {
+			return &ConfigError{
+				Field:   "context_fields",
+				Value:   f.Key + "=" + f.Field,
+				Message: "key must be non-empty and field must not contain whitespace or '='",
+			}
+		}
+	}
+	return nil
+}
+
+// WithContextFields thêm các giá trị được lấy từ context.Context thành field khi ghi log qua
+// các method *Context.
+//
+// Tham số:
+//   - fields: ...ContextField - các key cần lấy và tên field tương ứng
+//
+// Trả về:
+//   - LoggerOption: tùy chọn lấy field từ context
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("API", log.WithContextFields(
+//	    log.ContextField{Key: log.ContextKey("request_id"), Field: "request_id"},
+//	    log.ContextField{Key: tenantKey{}, Field: "tenant"},
+//	))
+func WithContextFields(fields ...ContextField) LoggerOption {
+	return func(l *logger) {
+		l.contextFields = append(l.contextFields[:len(l.contextFields):len(l.contextFields)], fields...)
+	}
+}
+
+// setContextFields thay thế các field được lấy từ context của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - fields: []ContextField - danh sách mới, không được sửa sau khi truyền vào
+func (l *logger) setContextFields(fields []ContextField) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.contextFields = fields
+	l.publish()
+}
+
+// AddContextField đăng ký một giá trị được lấy từ context.Context thành field cho mọi logger
+// do manager tạo, bổ sung cho Config.ContextFields. Dùng cho key không phải chuỗi (VD: key riêng
+// của một package). Cặp key và field đã đăng ký hoặc đã có trong Config.ContextFields chỉ được
+// lấy một lần. Method này là thread-safe.
+//
+// Tham số:
+//   - key: interface{} - key của giá trị trong context
+//   - field: string - tên field ghi giá trị
+//
+// Ví dụ:
+//
+//	manager.AddContextField(auth.UserKey{}, "user_id")
+func (m *manager) AddContextField(key interface{}, field string) {
+	if key == nil || field == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f := ContextField{Key: key, Field: field}
+	if hasContextField(m.contextFields, f) {
+		return
+	}
+	m.addContextField(f)
+}
+
+// AddContextExtractor đăng ký một hàm tính các field từ context.Context cho các method *Context
+// của mọi logger do manager tạo, bổ sung cho Config.ContextFields và AddContextField. Method này
+// là thread-safe.
+//
+// Tham số:
+//   - extract: func(ctx context.Context) []Field - hàm tính field, trả về nil khi context không có giá trị
+//
+// Ví dụ:
+//
+//	manager.AddContextExtractor(otellog.Fields) // trace_id và span_id của span đang hoạt động
+func (m *manager) AddContextExtractor(extract func(ctx context.Context) []Field) {
+	if extract == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addContextField(ContextField{Extract: extract})
+}
+
+// addContextField thêm field vào danh sách đăng ký và cập nhật các logger. Phải được gọi khi
+// đang giữ m.mu.
+func (m *manager) addContextField(f ContextField) {
+	m.contextFields = append(m.contextFields[:len(m.contextFields):len(m.contextFields)], f)
+	fields := m.loggerContextFields(m.config)
+	for _, lg := range m.loggers {
+		if l, ok := lg.(*logger); ok {
+			l.setContextFields(fields)
+		}
+	}
+}
+
+// loggerContextFields trả về các field được lấy từ context của logger theo cấu hình và các
+// field đăng ký qua AddContextField và AddContextExtractor. Phải được gọi khi đang giữ m.mu.
+func (m *manager) loggerContextFields(config *Config) []ContextField {
+	fields := config.contextFields()
+	for _, f := range m.contextFields {
+		if f.Extract != nil || !hasContextField(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// hasContextField cho biết fields đã có field lấy cùng key với cùng tên field như f.
+func hasContextField(fields []ContextField, f ContextField) bool {
+	for _, existing := range fields {
+		if existing.Extract == nil && existing.Key == f.Key && existing.Field == f.Field {
+			return true
+		}
+	}
+	return false
+}
+
+// contextArgs thêm các giá trị có trong ctx vào cuối args dưới dạng field.
+func contextArgs(ctx context.Context, fields []ContextField, args []interface{}) []interface{} {
+	if ctx == nil {
+		return args
+	}
+	for _, f := range fields {
+		if f.Extract != nil {
+			for _, field := range f.Extract(ctx) {
+				args = append(args[:len(args):len(args)], field)
+			}
+			continue
+		}
+		value := ctx.Value(f.Key)
+		if key, ok := f.Key.(ContextKey); ok && value == nil {
+			value = ctx.Value(string(key))
+		}
+		if value != nil {
+			args = append(args[:len(args):len(args)], Any(f.Field, value))
+		}
+	}
+	return args
+}
+
+// DebugContext ghi một thông điệp ở cấp độ debug kèm các field lấy từ ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context của yêu cầu (xem WithContextFields và Config.ContextFields)
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func (l *logger) DebugContext(ctx context.Context, message string, args ...interface{}) {
+	l.logContext(ctx, handler.DebugLevel, message, args)
+}
+
+// InfoContext ghi một thông điệp ở cấp độ info kèm các field lấy từ ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context của yêu cầu (xem WithContextFields và Config.ContextFields)
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+//
+// Ví dụ:
+//
+//	logger.InfoContext(r.Context(), "Order %d created", orderID)
+//	// Output: [Order] Order 42 created request_id=abc123 tenant=acme
+func (l *logger) InfoContext(ctx context.Context, message string, args ...interface{}) {
+	l.logContext(ctx, handler.InfoLevel, message, args)
+}
+
+// WarningContext ghi một thông điệp ở cấp độ warning kèm các field lấy từ ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context của yêu cầu (xem WithContextFields và Config.ContextFields)
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func (l *logger) WarningContext(ctx context.Context, message string, args ...interface{}) {
+	l.logContext(ctx, handler.WarningLevel, message, args)
+}
+
+// ErrorContext ghi một thông điệp ở cấp độ error kèm các field lấy từ ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context của yêu cầu (xem WithContextFields và Config.ContextFields)
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func (l *logger) ErrorContext(ctx context.Context, message string, args ...interface{}) {
+	l.logContext(ctx, handler.ErrorLevel, message, args)
+}
+
+// FatalContext ghi một thông điệp ở cấp độ fatal kèm các field lấy từ ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context của yêu cầu (xem WithContextFields và Config.ContextFields)
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func (l *logger) FatalContext(ctx context.Context, message string, args ...interface{}) {
+	l.logContext(ctx, handler.FatalLevel, message, args)
+}
+
+// logContext ghi entry kèm các field lấy từ ctx. Giá trị chỉ được lấy khi entry được ghi.
+func (l *logger) logContext(ctx context.Context, level handler.Level, message string, args []interface{}) {
+	if level < l.getMinLevel() {
+		return
+	}
+	snapshot := l.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, l.context, message) {
+		return
+	}
+
+	args = contextArgs(ctx, snapshot.contextFields, args)
+	l.write(snapshot, snapshot.now(), level, message, l.withCaller(args, 2)...)
+}
+
+// DebugContext ghi một thông điệp ở cấp độ debug kèm các field lấy từ ctx nếu thông điệp không bị chặn.
+func (r *repeatLogger) DebugContext(ctx context.Context, message string, args ...interface{}) {
+	r.logContext(ctx, handler.DebugLevel, message, args)
+}
+
+// InfoContext ghi một thông điệp ở cấp độ info kèm các field lấy từ ctx nếu thông điệp không bị chặn.
+func (r *repeatLogger) InfoContext(ctx context.Context, message string, args ...interface{}) {
+	r.logContext(ctx, handler.InfoLevel, message, args)
+}
+
+// WarningContext ghi một thông điệp ở cấp độ warning kèm các field lấy từ ctx nếu thông điệp không bị chặn.
+func (r *repeatLogger) WarningContext(ctx context.Context, message string, args ...interface{}) {
+	r.logContext(ctx, handler.WarningLevel, message, args)
+}
+
+// ErrorContext ghi một thông điệp ở cấp độ error kèm các field lấy từ ctx nếu thông điệp không bị chặn.
+func (r *repeatLogger) ErrorContext(ctx context.Context, message string, args ...interface{}) {
+	r.logContext(ctx, handler.ErrorLevel, message, args)
+}
+
+// FatalContext ghi một thông điệp ở cấp độ fatal kèm các field lấy từ ctx nếu thông điệp không bị chặn.
+func (r *repeatLogger) FatalContext(ctx context.Context, message string, args ...interface{}) {
+	r.logContext(ctx, handler.FatalLevel, message, args)
+}
+
+// logContext ghi entry kèm các field lấy từ ctx qua logger gốc nếu entry không bị chặn.
+func (r *repeatLogger) logContext(ctx context.Context, level handler.Level, message string, args []interface{}) {
+	snapshot, suppressed, ok := r.allow(level, message)
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		args = append(args[:len(args):len(args)], Uint64(FieldSuppressed, suppressed))
+	}
+	args = contextArgs(ctx, snapshot.contextFields, args)
+	r.write(snapshot, snapshot.now(), level, message, r.withCaller(args, 2)...)
+}
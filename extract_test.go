@@ -0,0 +1,141 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestLogger_InfoContext(t *testing.T) {
+	l := NewLogger("Order", WithCaller(), WithContextFields(
+		ContextField{Key: ContextKey("request_id"), Field: "request_id"},
+		ContextField{Key: tenantKey{}, Field: "tenant"},
+	))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	ctx := context.WithValue(context.Background(), ContextKey("request_id"), "abc123")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	want := nextLine()
+	l.InfoContext(ctx, "Order %d created", 42)
+	if h.entry.Message != "[Order] Order 42 created request_id=abc123 tenant=acme caller="+want {
+		t.Errorf("InfoContext() nên gắn các giá trị từ context và caller, got %q", h.entry.Message)
+	}
+
+	// Giá trị vắng mặt không tạo field; key chuỗi thường (VD: gin.Context) cũng được tìm theo tên
+	plain := context.WithValue(context.Background(), "request_id", "plain")
+	l.ErrorContext(plain, "failed")
+	if !strings.HasPrefix(h.entry.Message, "[Order] failed request_id=plain caller=") {
+		t.Errorf("ErrorContext() nên tìm key chuỗi và bỏ qua giá trị vắng mặt, got %q", h.entry.Message)
+	}
+
+	h.entry = nil
+	l.DebugContext(ctx, "verbose")
+	if h.entry != nil {
+		t.Error("DebugContext() dưới cấp độ tối thiểu không nên ghi")
+	}
+}
+
+func TestRepeatLogger_InfoContext(t *testing.T) {
+	l := NewLogger("Worker", WithContextFields(ContextField{Key: ContextKey("job"), Field: "job"}))
+	h := &recordingHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	ctx := context.WithValue(context.Background(), ContextKey("job"), "sync")
+	once := l.Once()
+	once.WarningContext(ctx, "slow")
+	once.WarningContext(ctx, "slow")
+	if got := strings.Join(h.messages, "|"); got != "[Worker] slow job=sync" {
+		t.Errorf("Once().WarningContext() nên ghi một lần kèm field từ context, got %q", got)
+	}
+}
+
+func TestManager_ContextFields(t *testing.T) {
+	config := createTestConfig()
+	config.ContextFields = []ContextFieldConfig{{Key: "request_id"}, {Key: "tenant", Field: "tenant_id"}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	l := m.GetLogger("API")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+	m.AddContextField(tenantKey{}, "org")
+
+	ctx := context.WithValue(context.Background(), ContextKey("request_id"), "r1")
+	ctx = context.WithValue(ctx, ContextKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, tenantKey{}, "o1")
+	l.InfoContext(ctx, "ok")
+	if h.entry == nil || h.entry.Message != "[API] ok request_id=r1 tenant_id=acme org=o1" {
+		t.Errorf("Logger của manager nên lấy field theo cấu hình và AddContextField, got %v", h.entry)
+	}
+
+	updated := *config
+	updated.ContextFields = nil
+	if _, err := m.ApplyConfig(&updated, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	l.InfoContext(ctx, "ok")
+	if h.entry.Message != "[API] ok org=o1" {
+		t.Errorf("ApplyConfig() nên thay field theo cấu hình mới và giữ AddContextField, got %q", h.entry.Message)
+	}
+
+	invalid := *createTestConfig()
+	invalid.ContextFields = []ContextFieldConfig{{Key: "", Field: "x"}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Validate() nên từ chối key rỗng")
+	}
+}
+
+func TestManager_AddContextExtractor(t *testing.T) {
+	m := NewManager(createTestConfig())
+	defer m.Close()
+	l := m.GetLogger("API")
+	h := &entryHandler{}
+	m.AddHandler(TestHandlerType, h)
+
+	m.AddContextExtractor(nil)
+	m.AddContextExtractor(func(ctx context.Context) []Field {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []Field{String("tenant", tenant), Bool("trusted", true)}
+		}
+		return nil
+	})
+
+	l.InfoContext(context.WithValue(context.Background(), tenantKey{}, "acme"), "ok")
+	if h.entry == nil || h.entry.Message != "[API] ok tenant=acme trusted=true" {
+		t.Errorf("AddContextExtractor() nên gắn các field được tính từ context, got %v", h.entry)
+	}
+	l.InfoContext(context.Background(), "ok")
+	if h.entry.Message != "[API] ok" {
+		t.Errorf("Extractor trả về nil không nên tạo field, got %q", h.entry.Message)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	config := createTestConfig()
+	config.ContextFields = []ContextFieldConfig{{Key: FieldRequestID}}
+	m := NewManager(config)
+	defer m.Close()
+	l := m.GetLogger("API")
+	h := &entryHandler{}
+	m.AddHandler(TestHandlerType, h)
+	m.AddContextField(ContextKey(FieldRequestID), FieldRequestID)
+
+	id := NewRequestID()
+	if len(id) != 32 || id == NewRequestID() {
+		t.Fatalf("NewRequestID() nên tạo ID ngẫu nhiên 32 ký tự, got %q", id)
+	}
+	ctx := WithRequestID(context.Background(), id)
+	if RequestID(ctx) != id || RequestID(context.Background()) != "" {
+		t.Errorf("RequestID() nên trả về ID đã lưu, got %q", RequestID(ctx))
+	}
+	l.InfoContext(ctx, "ok")
+	if h.entry == nil || h.entry.Message != "[API] ok request_id="+id {
+		t.Errorf("Field đã có trong cấu hình không nên được gắn hai lần, got %v", h.entry)
+	}
+}
@@ -0,0 +1,348 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// Các key của field được tạo bởi Err.
+const (
+	FieldError      = "error"       // Thông điệp của lỗi
+	FieldErrorType  = "error_type"  // Kiểu của lỗi ngoài cùng
+	FieldErrorChain = "error_chain" // Kiểu của các lỗi được bọc, theo errors.Unwrap
+	FieldErrorStack = "error_stack" // Stack trace của lỗi (nếu có, VD: pkg/errors)
+)
+
+// Field là một cặp key-value có cấu trúc được đính kèm vào log entry.
+//
+// Field có thể được truyền xen kẽ với các tham số định dạng trong các method
+// Debug, Info, Warning, Error và Fatal. Các Field được tách khỏi tham số định dạng
+// và được ghi dưới dạng key=value sau thông điệp.
+//
+// Trên hot path, dùng các constructor có kiểu (String, Int64, Duration...) cùng với
+// Logger.LogFields để tránh đóng gói giá trị vào interface{} và định dạng qua Sprintf.
+//
+// Ví dụ:
+//
+//	logger.Info("User %s logged in", username, log.Any("user_id", 42))
+//	// Output: [UserService] User john logged in user_id=42
+type Field = handler.Field
+
+// Any tạo một Field với giá trị bất kỳ.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: interface{} - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+//
+// Ví dụ:
+//
+//	logger.Info("Request completed", log.Any("status", 200))
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String tạo một Field chuỗi không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: string - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+//
+// Ví dụ:
+//
+//	logger.LogFields(handler.InfoLevel, "User logged in", log.String("user", username))
+func String(key, value string) Field {
+	return Field{Key: key, Type: handler.StringType, Str: value}
+}
+
+// Int tạo một Field số nguyên không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: int - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Int64 tạo một Field số nguyên 64-bit không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: int64 - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: handler.Int64Type, Integer: value}
+}
+
+// Uint64 tạo một Field số nguyên không dấu 64-bit không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: uint64 - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: handler.Uint64Type, Integer: int64(value)}
+}
+
+// Float64 tạo một Field số thực không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: float64 - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: handler.Float64Type, Integer: int64(math.Float64bits(value))}
+}
+
+// Bool tạo một Field bool không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: bool - giá trị của field
+//
+// Trả về:
+//   - Field: field đã được tạo
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: handler.BoolType, Integer: i}
+}
+
+// Duration tạo một Field khoảng thời gian không cần đóng gói giá trị vào interface{}.
+//
+// Tham số:
+//   - key: string - tên của field
+//   - value: time.Duration - giá trị của field, được ghi theo time.Duration.String (VD: 1.5s)
+//
+// Trả về:
+//   - Field: field đã được tạo
+//
+// Ví dụ:
+//
+//	logger.LogFields(handler.InfoLevel, "Request completed", log.Int("status", 200), log.Duration("latency", elapsed))
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: handler.DurationType, Integer: int64(value)}
+}
+
+// errorValue đánh dấu giá trị của field được tạo bởi Err để splitFields mở rộng thành nhiều field.
+type errorValue struct {
+	err error
+}
+
+// String trả về thông điệp của lỗi.
+func (e errorValue) String() string {
+	return e.err.Error()
+}
+
+// Err tạo một Field ghi lại chi tiết của lỗi.
+//
+// Khi được ghi, field được mở rộng thành:
+//   - error: thông điệp của lỗi
+//   - error_type: kiểu của lỗi (VD: *fs.PathError)
+//   - error_chain: kiểu của các lỗi được bọc qua fmt.Errorf("%w") (chỉ khi lỗi có bọc lỗi khác)
+//   - error_stack: stack trace nếu lỗi cung cấp method StackTrace (VD: github.com/pkg/errors)
+//
+// Tham số:
+//   - err: error - lỗi cần ghi (nil sẽ được ghi là error=<nil>)
+//
+// Trả về:
+//   - Field: field chứa lỗi
+//
+// Ví dụ:
+//
+//	if err := repo.Save(user); err != nil {
+//	    logger.Error("operation failed", log.Err(err))
+//	    // Output: [UserService] operation failed error="save user: connection refused"
+//	    //         error_type=*fmt.wrapError error_chain=*fmt.wrapError>*net.OpError
+//	}
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: FieldError, Value: nil}
+	}
+	return Field{Key: FieldError, Value: errorValue{err: err}}
+}
+
+// errorFields mở rộng lỗi thành các field thông điệp, kiểu, chuỗi lỗi được bọc và stack trace.
+//
+// Tham số:
+//   - key: string - key của field thông điệp
+//   - err: error - lỗi cần mở rộng
+//
+// Trả về:
+//   - []Field: các field mô tả lỗi
+func errorFields(key string, err error) []Field {
+	fields := []Field{
+		{Key: key, Value: err.Error()},
+		{Key: FieldErrorType, Value: fmt.Sprintf("%T", err)},
+	}
+
+	var chain []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, fmt.Sprintf("%T", e))
+	}
+	if len(chain) > 1 {
+		fields = append(fields, Field{Key: FieldErrorChain, Value: strings.Join(chain, ">")})
+	}
+
+	if stack := stackTrace(err); stack != "" {
+		fields = append(fields, Field{Key: FieldErrorStack, Value: stack})
+	}
+	return fields
+}
+
+// stackTrace trả về stack trace của lỗi sâu nhất trong chuỗi có method StackTrace (VD: pkg/errors).
+//
+// Method được gọi qua reflection để không phụ thuộc vào package cung cấp lỗi.
+func stackTrace(err error) string {
+	var stack string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		method := reflect.ValueOf(e).MethodByName("StackTrace")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		stack = strings.TrimSpace(fmt.Sprintf("%+v", method.Call(nil)[0].Interface()))
+	}
+	return stack
+}
+
+// LazyValue là một giá trị chỉ được tính khi log entry thực sự được ghi.
+//
+// LazyValue được tạo bởi Lazy và có thể dùng làm giá trị của field hoặc tham số định dạng.
+type LazyValue func() interface{}
+
+// Lazy bọc một phép tính tốn kém để nó chỉ được thực hiện khi log entry vượt qua cấp độ
+// tối thiểu và có ít nhất một handler chấp nhận cấp độ đó.
+//
+// Hàm được gọi tối đa một lần cho mỗi lời gọi log, trên goroutine của bên gọi.
+//
+// Tham số:
+//   - fn: func() interface{} - hàm tính giá trị
+//
+// Trả về:
+//   - LazyValue: giá trị trì hoãn dùng với Any hoặc làm tham số định dạng
+//
+// Ví dụ:
+//
+//	// dumpState chỉ được gọi khi debug log được bật
+//	logger.Debug("Cache state", log.Any("state", log.Lazy(func() interface{} { return cache.dumpState() })))
+//	logger.Debug("Queue: %v", log.Lazy(func() interface{} { return queue.Snapshot() }))
+func Lazy(fn func() interface{}) LazyValue {
+	return LazyValue(fn)
+}
+
+// resolveLazy thay các LazyValue trong tham số định dạng bằng giá trị đã tính.
+//
+// Danh sách ban đầu được trả về nguyên vẹn (không cấp phát) khi không có LazyValue nào.
+func resolveLazy(args []interface{}) []interface{} {
+	for i, arg := range args {
+		if _, isLazy := arg.(LazyValue); isLazy {
+			resolved := make([]interface{}, len(args))
+			copy(resolved, args)
+			for j := i; j < len(resolved); j++ {
+				if lazy, ok := resolved[j].(LazyValue); ok {
+					resolved[j] = lazy()
+				}
+			}
+			return resolved
+		}
+	}
+	return args
+}
+
+// splitFields tách các Field khỏi danh sách tham số định dạng.
+//
+// Tham số:
+//   - args: []interface{} - danh sách tham số truyền vào method log
+//
+// Trả về:
+//   - []interface{}: các tham số định dạng còn lại theo thứ tự ban đầu
+//   - []Field: các field có cấu trúc theo thứ tự ban đầu
+func splitFields(args []interface{}) ([]interface{}, []Field) {
+	var fields []Field
+	for _, arg := range args {
+		if _, ok := arg.(Field); ok {
+			fields = make([]Field, 0, len(args))
+			break
+		}
+	}
+	if fields == nil {
+		return args, nil
+	}
+
+	formatArgs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if f, ok := arg.(Field); ok {
+			fields = appendField(fields, f)
+		} else {
+			formatArgs = append(formatArgs, arg)
+		}
+	}
+	return formatArgs, fields
+}
+
+// expandFields mở rộng các field được tạo bởi Err thành các field mô tả lỗi và tính giá trị
+// của các field Lazy.
+//
+// Danh sách ban đầu được trả về nguyên vẹn (không cấp phát) khi không có field lỗi hoặc Lazy nào.
+//
+// Tham số:
+//   - fields: []Field - các field truyền vào LogFields
+//
+// Trả về:
+//   - []Field: các field đã được mở rộng theo thứ tự ban đầu
+func expandFields(fields []Field) []Field {
+	for i, f := range fields {
+		if needsExpand(f) {
+			expanded := make([]Field, i, len(fields)+3)
+			copy(expanded, fields[:i])
+			for _, f := range fields[i:] {
+				expanded = appendField(expanded, f)
+			}
+			return expanded
+		}
+	}
+	return fields
+}
+
+// needsExpand kiểm tra field có cần được appendField mở rộng hoặc tính giá trị hay không.
+func needsExpand(f Field) bool {
+	switch f.Value.(type) {
+	case errorValue, LazyValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// appendField thêm field vào danh sách, mở rộng field được tạo bởi Err và tính giá trị Lazy.
+func appendField(fields []Field, f Field) []Field {
+	if lazy, isLazy := f.Value.(LazyValue); isLazy {
+		f.Value = lazy()
+	}
+	if ev, isErr := f.Value.(errorValue); isErr {
+		return append(fields, errorFields(f.Key, ev.err)...)
+	}
+	return append(fields, f)
+}
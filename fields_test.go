@@ -0,0 +1,278 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestSplitFields(t *testing.T) {
+	args, fields := splitFields([]interface{}{"a", Any("k", 1), 2})
+	if len(args) != 2 || args[0] != "a" || args[1] != 2 {
+		t.Errorf("splitFields() trả về sai tham số định dạng, got %v", args)
+	}
+	if len(fields) != 1 || fields[0].Key != "k" {
+		t.Errorf("splitFields() trả về sai fields, got %v", fields)
+	}
+
+	original := []interface{}{"a", 1}
+	args, fields = splitFields(original)
+	if len(args) != 2 || fields != nil {
+		t.Errorf("splitFields() không có field nên trả về nguyên tham số, got %v %v", args, fields)
+	}
+}
+
+func TestLogger_LogWithFields(t *testing.T) {
+	l := NewLogger("UserService")
+	h := &MockHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("User %s logged in", "john", Any("user_id", 42))
+
+	if !strings.HasSuffix(h.LogMessage, "[UserService] User john logged in user_id=42") {
+		t.Errorf("Logger không ghi field đúng định dạng, got %q", h.LogMessage)
+	}
+	if h.LogLevel != handler.InfoLevel {
+		t.Errorf("Logger ghi sai level, got %v", h.LogLevel)
+	}
+}
+
+// stackedError mô phỏng lỗi của github.com/pkg/errors với method StackTrace
+type stackedError struct{ msg string }
+
+func (e *stackedError) Error() string { return e.msg }
+
+func (e *stackedError) StackTrace() []string {
+	return []string{"main.connect\n\tmain.go:12"}
+}
+
+func TestErr(t *testing.T) {
+	base := &stackedError{msg: "connection refused"}
+	err := fmt.Errorf("save user: %w", base)
+
+	l := NewLogger("UserService")
+	h := &MockHandler{}
+	l.AddHandler(TestHandlerType, h)
+	l.Error("operation failed", Err(err))
+
+	for _, want := range []string{
+		`error="save user: connection refused"`,
+		"error_type=*fmt.wrapError",
+		"error_chain=*fmt.wrapError>*log.stackedError",
+		`error_stack="[main.connect\n\tmain.go:12]"`,
+	} {
+		if !strings.Contains(h.LogMessage, want) {
+			t.Errorf("Err() thiếu %q, got %q", want, h.LogMessage)
+		}
+	}
+
+	l.Error("plain", Err(errors.New("boom")))
+	if strings.Contains(h.LogMessage, "error_chain") || strings.Contains(h.LogMessage, "error_stack") {
+		t.Errorf("Lỗi không bọc lỗi khác không nên có error_chain hoặc error_stack, got %q", h.LogMessage)
+	}
+
+	l.Error("none", Err(nil))
+	if !strings.HasSuffix(h.LogMessage, "error=<nil>") {
+		t.Errorf("Err(nil) nên ghi error=<nil>, got %q", h.LogMessage)
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		want  string
+		value interface{}
+	}{
+		{"String", String("user", "john doe"), `user="john doe"`, "john doe"},
+		{"Int", Int("id", -42), "id=-42", int64(-42)},
+		{"Int64", Int64("size", 1<<40), "size=1099511627776", int64(1 << 40)},
+		{"Uint64", Uint64("max", math.MaxUint64), "max=18446744073709551615", uint64(math.MaxUint64)},
+		{"Float64", Float64("ratio", 0.25), "ratio=0.25", 0.25},
+		{"Bool", Bool("ok", true), "ok=true", true},
+		{"Duration", Duration("latency", 1500*time.Millisecond), "latency=1.5s", 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if got := tt.field.Interface(); got != tt.value {
+				t.Errorf("Interface() = %#v, want %#v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestLogger_LogFields(t *testing.T) {
+	l := NewLogger("HTTP")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.LogFields(handler.InfoLevel, "100% done", String("method", "GET"), Int("status", 200), Err(errors.New("boom")))
+	if h.entry == nil {
+		t.Fatal("LogFields() nên gửi entry đến handler")
+	}
+	if want := "[HTTP] 100% done method=GET status=200 error=boom error_type=*errors.errorString"; h.entry.Message != want {
+		t.Errorf("LogFields() = %q, want %q", h.entry.Message, want)
+	}
+	if len(h.entry.Fields) != 4 {
+		t.Errorf("LogFields() nên mở rộng field lỗi, got %v", h.entry.Fields)
+	}
+
+	h.entry = nil
+	l.LogFields(handler.DebugLevel, "filtered", Int("n", 1))
+	if h.entry != nil {
+		t.Error("LogFields() nên lọc entry dưới cấp độ tối thiểu")
+	}
+}
+
+func TestLogger_LogFields_Allocations(t *testing.T) {
+	l := NewLogger("HTTP")
+	l.AddHandler(TestHandlerType, &discardHandler{})
+
+	// Qua interface Logger, slice variadic luôn được cấp phát; các giá trị field thì không
+	filtered := testing.AllocsPerRun(100, func() {
+		l.LogFields(handler.DebugLevel, "filtered", String("method", "GET"), Int("status", 200))
+	})
+	if filtered > 1 {
+		t.Errorf("LogFields() bị lọc theo cấp độ chỉ nên cấp phát slice variadic, got %v allocs", filtered)
+	}
+
+	typed := testing.AllocsPerRun(100, func() {
+		l.LogFields(handler.InfoLevel, "request", String("method", "GET"), Int("status", 200), Duration("latency", time.Millisecond))
+	})
+	boxed := testing.AllocsPerRun(100, func() {
+		l.Info("request", Any("method", "GET"), Any("status", 200), Any("latency", time.Millisecond))
+	})
+	if typed >= boxed {
+		t.Errorf("LogFields() với field có kiểu nên cấp phát ít hơn Info() với Any, got %v >= %v", typed, boxed)
+	}
+}
+
+func TestLogger_FieldLimits(t *testing.T) {
+	l := NewLogger("API", WithFieldLimits(handler.Limits{MaxDepth: 1, MaxElements: 2, MaxFields: 2}))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("request", Any("ids", []int{1, 2, 3}), Any("body", map[string][]int{"a": {1}}), Any("extra", 1))
+	if want := `[API] request ids="[1,2,\"+1 more\"]" body={"a":"[truncated]"} fields_truncated=1`; h.entry.Message != want {
+		t.Errorf("WithFieldLimits() = %q, want %q", h.entry.Message, want)
+	}
+	if len(h.entry.Fields) != 3 {
+		t.Errorf("Entry.Fields nên được cắt bớt theo MaxFields, got %v", h.entry.Fields)
+	}
+}
+
+func TestManager_FieldLimits(t *testing.T) {
+	config := createTestConfig()
+	config.MaxFields = 1
+	m := NewManager(config)
+	defer m.Close()
+
+	h := &entryHandler{}
+	lg := m.GetLogger("Orders")
+	lg.AddHandler(TestHandlerType, h)
+
+	lg.Info("placed", Any("a", 1), Any("b", 2))
+	if !strings.HasSuffix(h.entry.Message, "a=1 fields_truncated=1") {
+		t.Errorf("Config.MaxFields nên giới hạn số field, got %q", h.entry.Message)
+	}
+
+	updated := *config
+	updated.MaxFields = 0
+	if _, err := m.ApplyConfig(&updated, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	lg.Info("placed", Any("a", 1), Any("b", 2))
+	if !strings.HasSuffix(h.entry.Message, "a=1 b=2") {
+		t.Errorf("ApplyConfig nên cập nhật giới hạn field cho logger đã tồn tại, got %q", h.entry.Message)
+	}
+
+	for _, field := range []string{"max_field_depth", "max_field_elements", "max_fields"} {
+		invalid := *config
+		switch field {
+		case "max_field_depth":
+			invalid.MaxFieldDepth = -1
+		case "max_field_elements":
+			invalid.MaxFieldElements = -1
+		default:
+			invalid.MaxFields = -1
+		}
+		if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate() nên từ chối %s âm, got %v", field, err)
+		}
+	}
+}
+
+// levelFilterHandler chỉ chấp nhận entry từ cấp độ min trở lên qua handler.LevelEnabler
+type levelFilterHandler struct {
+	MockHandler
+	min handler.Level
+}
+
+func (h *levelFilterHandler) Enabled(level handler.Level) bool {
+	return level >= h.min
+}
+
+func TestLazy(t *testing.T) {
+	l := NewLogger("Cache")
+	h := &MockHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	calls := 0
+	state := Lazy(func() interface{} {
+		calls++
+		return map[string]int{"hits": 3}
+	})
+
+	l.Debug("Cache state %v", state, Any("state", state))
+	if calls != 0 || h.LogCalled {
+		t.Fatalf("Giá trị Lazy không nên được tính khi log bị lọc, got %d lần gọi", calls)
+	}
+
+	l.Info("Cache state %v", state, Any("state", state))
+	if calls != 2 {
+		t.Errorf("Giá trị Lazy nên được tính một lần cho mỗi vị trí sử dụng, got %d", calls)
+	}
+	if !strings.HasSuffix(h.LogMessage, `[Cache] Cache state map[hits:3] state={"hits":3}`) {
+		t.Errorf("Giá trị Lazy được ghi sai, got %q", h.LogMessage)
+	}
+
+	l.LogFields(handler.InfoLevel, "Cache state", Any("state", state))
+	if calls != 3 || !strings.HasSuffix(h.LogMessage, `state={"hits":3}`) {
+		t.Errorf("LogFields nên tính giá trị Lazy, got %d lần gọi, %q", calls, h.LogMessage)
+	}
+}
+
+func TestLogger_SkipsFormattingWhenNoHandlerAccepts(t *testing.T) {
+	l := NewLogger("Cache")
+	l.SetMinLevel(handler.DebugLevel)
+	errorOnly := &levelFilterHandler{min: handler.ErrorLevel}
+	l.AddHandler(TestHandlerType, errorOnly)
+
+	calls := 0
+	state := Lazy(func() interface{} {
+		calls++
+		return "dump"
+	})
+
+	l.Debug("Cache state %v", state)
+	l.LogFields(handler.InfoLevel, "Cache state", Any("state", state))
+	if calls != 0 || errorOnly.LogCalled {
+		t.Errorf("Không nên định dạng khi không có handler chấp nhận cấp độ, got %d lần gọi", calls)
+	}
+
+	all := &MockHandler{}
+	l.AddHandler(HandlerTypeConsole, all)
+	l.Debug("Cache state %v", state)
+	if calls != 1 || errorOnly.LogCalled || !all.LogCalled {
+		t.Errorf("Entry chỉ nên được gửi đến handler chấp nhận cấp độ, got errorOnly=%v all=%v", errorOnly.LogCalled, all.LogCalled)
+	}
+}
@@ -0,0 +1,173 @@
+package log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// fileHandlerPrefix là tiền tố tên của các file handler do Manager tạo theo Config.Files.
+const fileHandlerPrefix = "file."
+
+// FileOutputConfig định nghĩa một file log bổ sung, VD: file chỉ chứa lỗi hoặc file JSON cho
+// hệ thống thu thập log.
+type FileOutputConfig struct {
+	// Path đường dẫn file log
+	Path string `mapstructure:"path" yaml:"path" json:"path"`
+
+	// Level cấp độ tối thiểu của entry được ghi vào file (VD: "error"). Rỗng = theo Config.Level
+	Level string `mapstructure:"level" yaml:"level" json:"level"`
+
+	// MaxSize kích thước tối đa của file log (bytes) trước khi rotate
+	// 0 = không giới hạn
+	MaxSize int64 `mapstructure:"max_size" yaml:"max_size" json:"max_size"`
+
+	// Format định dạng dòng log: "text", "json", "common", "combined", "cef" hoặc "leef".
+	// Rỗng = "text"
+	Format string `mapstructure:"format" yaml:"format" json:"format"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "path=storage/logs/errors.log level=error max_size=0 format=json".
+func (f FileOutputConfig) String() string {
+	return "path=" + f.Path + " level=" + f.Level + " max_size=" + strconv.FormatInt(f.MaxSize, 10) + " format=" + f.Format
+}
+
+// FileHandlerType trả về tên mà Manager đăng ký file handler của một mục trong Config.Files.
+//
+// Tên này có thể được dùng với Manager.GetHandler, Stack.Include, Routing, Channels, Async,
+// Delivery và các bộ lọc.
+//
+// Tham số:
+//   - name: string - tên file trong Config.Files
+//
+// Trả về:
+//   - HandlerType: tên handler dạng "file.<name>"
+//
+// Ví dụ:
+//
+//	errorsFile := manager.GetHandler(log.FileHandlerType("errors"))
+func FileHandlerType(name string) HandlerType {
+	return HandlerType(fileHandlerPrefix + name)
+}
+
+// isFileOutput kiểm tra handler có phải là file do Manager tạo theo Config.Files hay không.
+func isFileOutput(handlerType HandlerType) bool {
+	return strings.HasPrefix(string(handlerType), fileHandlerPrefix)
+}
+
+// fileOutputTypes trả về tên handler của các file trong Config.Files, đã sắp xếp.
+func fileOutputTypes(config *Config) []HandlerType {
+	names := make([]string, 0, len(config.Files))
+	for name := range config.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := make([]HandlerType, len(names))
+	for i, name := range names {
+		types[i] = FileHandlerType(name)
+	}
+	return types
+}
+
+// newFileOutput tạo file handler của một mục trong Config.Files.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập cảnh báo và nén dùng chung
+//   - output: FileOutputConfig - cấu hình của file
+//
+// Trả về:
+//   - *handler.FileHandler: file handler đã được cấu hình
+//   - error: lỗi nếu không thể mở file
+func newFileOutput(config *Config, output FileOutputConfig) (*handler.FileHandler, error) {
+	fileHandler, err := newFileHandler(config, output.Path, output.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	// Cấu hình đã được Validate nên định dạng hợp lệ
+	format, _ := handler.ParseFormat(output.Format)
+	fileHandler.SetFormat(format)
+	return fileHandler, nil
+}
+
+// fileOutputFilter trả về bộ lọc cấp độ của handler theo Config.Files.
+//
+// Trả về:
+//   - handler.RecordFilter: bộ lọc chỉ giữ entry từ Level trở lên
+//   - bool: false nếu handler không phải file trong Config.Files hoặc không đặt Level
+func fileOutputFilter(config *Config, handlerType HandlerType) (handler.RecordFilter, bool) {
+	name, ok := strings.CutPrefix(string(handlerType), fileHandlerPrefix)
+	if !ok || config.Files[name].Level == "" {
+		return handler.RecordFilter{}, false
+	}
+	level, err := handler.ParseLevel(config.Files[name].Level)
+	if err != nil {
+		return handler.RecordFilter{}, false
+	}
+	return handler.RecordFilter{Include: []handler.RecordRule{{MinLevel: level}}}, true
+}
+
+// validateFiles kiểm tra cấu hình các file trong Config.Files.
+//
+// Trả về:
+//   - error: ConfigError nếu cấu hình không hợp lệ
+func (c *Config) validateFiles() error {
+	names := make([]string, 0, len(c.Files))
+	for name := range c.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := map[string]string{c.File.Path: "file"}
+	for _, name := range names {
+		output := c.Files[name]
+		field := "files." + name
+		if name == "" || strings.ContainsAny(name, " \t\r\n") {
+			return &ConfigError{
+				Field:   "files",
+				Value:   name,
+				Message: "file name must be non-empty and must not contain whitespace",
+			}
+		}
+		if output.Path == "" {
+			return &ConfigError{
+				Field:   field + ".path",
+				Message: "path is required for file handler initialization",
+			}
+		}
+		if other, ok := paths[output.Path]; ok {
+			return &ConfigError{
+				Field:   field + ".path",
+				Value:   output.Path,
+				Message: "path is already used by " + other,
+			}
+		}
+		paths[output.Path] = field
+		if output.Level != "" {
+			if _, err := handler.ParseLevel(output.Level); err != nil {
+				return &ConfigError{
+					Field:   field + ".level",
+					Value:   output.Level,
+					Message: "invalid log level, must be one of: debug, info, warning, error, fatal",
+				}
+			}
+		}
+		if output.MaxSize < 0 {
+			return &ConfigError{
+				Field:   field + ".max_size",
+				Value:   output.String(),
+				Message: "max_size must be non-negative (0 for unlimited)",
+			}
+		}
+		if _, err := handler.ParseFormat(output.Format); err != nil {
+			return &ConfigError{
+				Field:   field + ".format",
+				Value:   output.Format,
+				Message: "invalid format, must be one of: text, json, common, combined, cef, leef",
+			}
+		}
+	}
+	return nil
+}
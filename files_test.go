@@ -0,0 +1,102 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_Files(t *testing.T) {
+	dir := t.TempDir()
+	config := createTestConfig()
+	config.Stack.Enabled = false
+	config.Files = map[string]FileOutputConfig{
+		"app":    {Path: filepath.Join(dir, "app.log")},
+		"errors": {Path: filepath.Join(dir, "errors.log"), Level: "error", Format: "json"},
+		"audit":  {Path: filepath.Join(dir, "audit.log")},
+	}
+	config.Channels = map[string]ChannelConfig{
+		ChannelAudit: {Contexts: []string{AuditContext}, Handlers: []string{"file.audit"}},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+
+	if m.GetHandler(FileHandlerType("errors")) == nil {
+		t.Fatal("Manager nên đăng ký file errors với tên file.errors")
+	}
+	order, audit := m.GetLogger("Order"), m.GetLogger(AuditContext)
+	order.Info("created")
+	order.Error("failed", Int("order_id", 42))
+	audit.Info("role changed")
+	m.Close()
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if app := read("app.log"); !strings.Contains(app, "created") || !strings.Contains(app, "failed") || strings.Contains(app, "role changed") {
+		t.Errorf("app.log nên chứa mọi entry của channel app, got %q", app)
+	}
+	if errs := read("errors.log"); strings.Contains(errs, "created") || !strings.Contains(errs, `"level":"ERROR"`) ||
+		!strings.Contains(errs, `"order_id":42`) {
+		t.Errorf("errors.log nên chỉ chứa entry từ error trở lên dạng JSON, got %q", errs)
+	}
+	if a := read("audit.log"); !strings.Contains(a, "role changed") || strings.Contains(a, "created") {
+		t.Errorf("audit.log nên chỉ chứa entry của channel audit, got %q", a)
+	}
+}
+
+func TestManager_ApplyConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	config := createTestConfig()
+	m := NewManager(config)
+	defer m.Close()
+	logger := m.GetLogger("Order")
+
+	updated := *config
+	updated.Files = map[string]FileOutputConfig{"errors": {Path: filepath.Join(dir, "errors.log"), Level: "error"}}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "handler file.errors: create") {
+		t.Errorf("Diff nên báo tạo file.errors, got %q", diff.String())
+	}
+	logger.Error("failed")
+	if data, _ := os.ReadFile(filepath.Join(dir, "errors.log")); !strings.Contains(string(data), "failed") {
+		t.Errorf("Logger đã tồn tại nên ghi vào file mới, got %q", data)
+	}
+
+	removed := updated
+	removed.Files = nil
+	if diff, _ := m.ApplyConfig(&removed, false); !strings.Contains(diff.String(), "handler file.errors: remove") {
+		t.Errorf("Diff nên báo xóa file.errors, got %q", diff.String())
+	}
+	if m.GetHandler(FileHandlerType("errors")) != nil {
+		t.Error("file.errors nên bị hủy đăng ký sau khi bị xóa khỏi cấu hình")
+	}
+}
+
+func TestConfig_ValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, output := range map[string]FileOutputConfig{
+		"thiếu path":             {},
+		"trùng path file":        {Path: "/tmp/test_manager.log"},
+		"cấp độ không hợp lệ":    {Path: filepath.Join(dir, "a.log"), Level: "loud"},
+		"định dạng không hợp lệ": {Path: filepath.Join(dir, "a.log"), Format: "xml"},
+		"max_size âm":            {Path: filepath.Join(dir, "a.log"), MaxSize: -1},
+	} {
+		config := createTestConfig()
+		config.Files = map[string]FileOutputConfig{"errors": output}
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "files.errors") {
+			t.Errorf("Validate() nên từ chối cấu hình %s, got %v", name, err)
+		}
+	}
+}
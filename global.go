@@ -0,0 +1,152 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.fork.vn/log/handler"
+)
+
+// defaultManager là manager dùng bởi các hàm cấp package, nil = fallbackManager.
+var defaultManager atomic.Pointer[Manager]
+
+// fallback là manager chỉ ghi ra console, được tạo khi các hàm cấp package được dùng trước SetDefault.
+var fallback struct {
+	once    sync.Once
+	manager Manager
+}
+
+// fallbackManager trả về manager mặc định ghi ra console ở InfoLevel, không ghi file.
+func fallbackManager() Manager {
+	fallback.once.Do(func() {
+		fallback.manager = NewManager(DefaultConfig())
+	})
+	return fallback.manager
+}
+
+// SetDefault đặt manager dùng bởi các hàm cấp package (Debug, Info, ..., L). ServiceProvider
+// gọi SetDefault với manager đã đăng ký trong container. Hàm này là thread-safe.
+//
+// Manager cũ không bị đóng. Truyền nil để quay lại manager mặc định chỉ ghi ra console.
+//
+// Tham số:
+//   - m: Manager - manager mặc định mới
+//
+// Ví dụ:
+//
+//	manager, err := log.NewProductionManager("storage/logs/app.log")
+//	if err != nil {
+//	    return err
+//	}
+//	log.SetDefault(manager)
+//	log.Info("Máy chủ đã khởi động trên cổng %d", port)
+func SetDefault(m Manager) {
+	if m == nil {
+		defaultManager.Store(nil)
+		return
+	}
+	defaultManager.Store(&m)
+}
+
+// Default trả về manager dùng bởi các hàm cấp package.
+//
+// Trước khi SetDefault được gọi, Default trả về một manager ghi ra console có màu ở InfoLevel,
+// không ghi file, để chương trình nhỏ và mã khởi tạo ghi log được trước khi DI được thiết lập.
+//
+// Trả về:
+//   - Manager: manager mặc định
+func Default() Manager {
+	if m := defaultManager.Load(); m != nil {
+		return *m
+	}
+	return fallbackManager()
+}
+
+// L trả về logger của context từ manager mặc định, tương đương Default().GetLogger(context).
+//
+// Tham số:
+//   - context: string - context của logger
+//
+// Trả về:
+//   - Logger: logger của context
+//
+// Ví dụ:
+//
+//	log.L("Migration").Info("Đã chạy %d migration", count)
+func L(context string) Logger {
+	return Default().GetLogger(context)
+}
+
+// Debug ghi một thông điệp ở cấp độ debug qua logger không có context của manager mặc định.
+//
+// Tham số:
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func Debug(message string, args ...interface{}) {
+	logDefault(handler.DebugLevel, message, args)
+}
+
+// Info ghi một thông điệp ở cấp độ info qua logger không có context của manager mặc định.
+//
+// Tham số:
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+//
+// Ví dụ:
+//
+//	func init() {
+//	    log.Info("Đang tải cấu hình từ %s", path)
+//	}
+func Info(message string, args ...interface{}) {
+	logDefault(handler.InfoLevel, message, args)
+}
+
+// Warning ghi một thông điệp ở cấp độ warning qua logger không có context của manager mặc định.
+//
+// Tham số:
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func Warning(message string, args ...interface{}) {
+	logDefault(handler.WarningLevel, message, args)
+}
+
+// Error ghi một thông điệp ở cấp độ error qua logger không có context của manager mặc định.
+//
+// Tham số:
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func Error(message string, args ...interface{}) {
+	logDefault(handler.ErrorLevel, message, args)
+}
+
+// Fatal ghi một thông điệp ở cấp độ fatal qua logger không có context của manager mặc định.
+// Như Logger.Fatal, hàm này không kết thúc chương trình.
+//
+// Tham số:
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+func Fatal(message string, args ...interface{}) {
+	logDefault(handler.FatalLevel, message, args)
+}
+
+// logDefault ghi thông điệp qua logger không có context của manager mặc định, giữ vị trí gọi
+// là bên gọi hàm cấp package khi bật caller.
+func logDefault(level handler.Level, message string, args []interface{}) {
+	lg := L("")
+	if l, ok := lg.(*logger); ok {
+		l.logDepth(level, message, 3, args)
+		return
+	}
+	switch level {
+	case handler.DebugLevel:
+		lg.Debug(message, args...)
+	case handler.InfoLevel:
+		lg.Info(message, args...)
+	case handler.WarningLevel:
+		lg.Warning(message, args...)
+	case handler.ErrorLevel:
+		lg.Error(message, args...)
+	default:
+		lg.Fatal(message, args...)
+	}
+}
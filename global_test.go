@@ -0,0 +1,59 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestDefault_Fallback(t *testing.T) {
+	SetDefault(nil)
+	m := Default()
+	if m == nil || m != Default() {
+		t.Fatal("Default() nên trả về cùng một manager dự phòng trước khi SetDefault được gọi")
+	}
+	if _, ok := m.GetHandler(HandlerTypeConsole).(*handler.ConsoleHandler); !ok {
+		t.Error("Manager dự phòng nên ghi ra console")
+	}
+}
+
+func TestSetDefault_PackageFunctions(t *testing.T) {
+	config := createTestConfig()
+	config.Level = handler.DebugLevel
+	config.EnableCaller = true
+	m := NewManager(config)
+	defer m.Close()
+	rec := &entryHandler{}
+	m.AddHandler(TestHandlerType, rec)
+
+	SetDefault(m)
+	t.Cleanup(func() { SetDefault(nil) })
+	if Default() != m {
+		t.Fatal("Default() nên trả về manager đã đặt qua SetDefault")
+	}
+
+	for level, fn := range map[handler.Level]func(string, ...interface{}){
+		handler.DebugLevel:   Debug,
+		handler.InfoLevel:    Info,
+		handler.WarningLevel: Warning,
+		handler.ErrorLevel:   Error,
+		handler.FatalLevel:   Fatal,
+	} {
+		_, _, line, _ := runtime.Caller(0)
+		fn("user %d", 42)
+		if rec.entry == nil || rec.entry.Level != level || !strings.HasPrefix(rec.entry.Message, "user 42") {
+			t.Fatalf("Hàm cấp package %v nên ghi qua manager mặc định, got %+v", level, rec.entry)
+		}
+		if !strings.Contains(rec.entry.Message, "global_test.go:"+strconv.Itoa(line+1)) {
+			t.Errorf("Vị trí gọi nên là bên gọi hàm cấp package, got %q", rec.entry.Message)
+		}
+	}
+
+	L("Migration").Info("applied")
+	if L("Migration") != m.GetLogger("Migration") || !strings.Contains(rec.entry.Message, "[Migration] applied") {
+		t.Errorf("L() nên trả về logger của manager mặc định, got %q", rec.entry.Message)
+	}
+}
@@ -0,0 +1,278 @@
+// Package grpclog cung cấp các gRPC interceptor ghi log cho server và client thông qua log.Manager.
+//
+// Các interceptor ghi lại RPC method, status code, thời gian xử lý và thông tin peer
+// dưới dạng các field có cấu trúc. Cấp độ log được chọn theo status code thông qua
+// Config.LevelFunc (mặc định là DefaultLevel).
+//
+// Ví dụ:
+//
+//	server := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(manager, nil)),
+//	    grpc.ChainStreamInterceptor(grpclog.StreamServerInterceptor(manager, nil)),
+//	)
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithChainUnaryInterceptor(grpclog.UnaryClientInterceptor(manager, nil)),
+//	    grpc.WithChainStreamInterceptor(grpclog.StreamClientInterceptor(manager, nil)),
+//	)
+package grpclog
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// Các loại RPC được ghi trong field grpc.kind.
+const (
+	KindUnary        = "unary"
+	KindServerStream = "server_stream"
+	KindClientStream = "client_stream"
+	KindBidiStream   = "bidi_stream"
+)
+
+// Config định nghĩa cấu hình cho các gRPC interceptor.
+type Config struct {
+	// Context context của logger dùng để ghi log RPC
+	Context string
+
+	// Message thông điệp của mỗi bản ghi RPC
+	Message string
+
+	// LevelFunc chọn cấp độ log theo status code của RPC
+	LevelFunc func(code codes.Code) handler.Level
+
+	// Skip cho phép bỏ qua việc ghi log cho một số method (VD: health check)
+	Skip func(fullMethod string) bool
+}
+
+// DefaultConfig trả về cấu hình mặc định cho server interceptor.
+//
+// Cấu hình mặc định sử dụng:
+//   - Context: "gRPC"
+//   - Message: "gRPC call"
+//   - LevelFunc: DefaultLevel
+//
+// Trả về:
+//   - *Config: cấu hình mặc định
+func DefaultConfig() *Config {
+	return &Config{
+		Context:   "gRPC",
+		Message:   "gRPC call",
+		LevelFunc: DefaultLevel,
+	}
+}
+
+// DefaultLevel ánh xạ status code sang cấp độ log.
+//
+// OK được ghi ở Info, các lỗi do phía client (InvalidArgument, NotFound,
+// PermissionDenied, ...) được ghi ở Warning, các lỗi còn lại được ghi ở Error.
+//
+// Tham số:
+//   - code: codes.Code - status code của RPC
+//
+// Trả về:
+//   - handler.Level: cấp độ log tương ứng
+func DefaultLevel(code codes.Code) handler.Level {
+	switch code {
+	case codes.OK:
+		return handler.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return handler.WarningLevel
+	default:
+		return handler.ErrorLevel
+	}
+}
+
+// UnaryServerInterceptor tạo một unary server interceptor ghi log cho mỗi RPC.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger
+//   - config: *Config - cấu hình interceptor (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - grpc.UnaryServerInterceptor: interceptor đã được cấu hình
+func UnaryServerInterceptor(manager log.Manager, config *Config) grpc.UnaryServerInterceptor {
+	r := newRecorder(manager, config)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+		if r.skip(info.FullMethod) {
+			return next(ctx, req)
+		}
+		start := time.Now()
+		resp, err := next(ctx, req)
+		r.record(ctx, info.FullMethod, KindUnary, peerAddress(ctx), start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor tạo một stream server interceptor ghi log khi mỗi stream kết thúc.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger
+//   - config: *Config - cấu hình interceptor (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - grpc.StreamServerInterceptor: interceptor đã được cấu hình
+func StreamServerInterceptor(manager log.Manager, config *Config) grpc.StreamServerInterceptor {
+	r := newRecorder(manager, config)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+		if r.skip(info.FullMethod) {
+			return next(srv, ss)
+		}
+		start := time.Now()
+		err := next(srv, ss)
+		r.record(ss.Context(), info.FullMethod, streamKind(info.IsClientStream, info.IsServerStream), peerAddress(ss.Context()), start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor tạo một unary client interceptor ghi log cho mỗi RPC gửi đi.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger
+//   - config: *Config - cấu hình interceptor (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - grpc.UnaryClientInterceptor: interceptor đã được cấu hình
+func UnaryClientInterceptor(manager log.Manager, config *Config) grpc.UnaryClientInterceptor {
+	r := newRecorder(manager, config)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if r.skip(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.record(ctx, method, KindUnary, cc.Target(), start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor tạo một stream client interceptor ghi log khi mở stream.
+//
+// Vì client stream có thể kéo dài tùy ý, interceptor chỉ ghi lại kết quả thiết lập
+// stream (thời gian và lỗi nếu có) thay vì chờ stream kết thúc.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger
+//   - config: *Config - cấu hình interceptor (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - grpc.StreamClientInterceptor: interceptor đã được cấu hình
+func StreamClientInterceptor(manager log.Manager, config *Config) grpc.StreamClientInterceptor {
+	r := newRecorder(manager, config)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if r.skip(method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		r.record(ctx, method, streamKind(desc.ClientStreams, desc.ServerStreams), cc.Target(), start, err)
+		return stream, err
+	}
+}
+
+// recorder ghi log cho các RPC theo cấu hình đã cho.
+type recorder struct {
+	logger log.Logger
+	config *Config
+}
+
+// newRecorder tạo recorder với cấu hình đã được bổ sung giá trị mặc định.
+func newRecorder(manager log.Manager, config *Config) *recorder {
+	if manager == nil {
+		panic("manager cannot be nil")
+	}
+	if config == nil {
+		config = DefaultConfig()
+	}
+	// Sao chép để giá trị mặc định không ghi vào cấu hình dùng chung của người gọi
+	c := *config
+	if c.LevelFunc == nil {
+		c.LevelFunc = DefaultLevel
+	}
+	return &recorder{
+		logger: manager.GetLogger(c.Context),
+		config: &c,
+	}
+}
+
+// skip kiểm tra method có bị bỏ qua hay không.
+func (r *recorder) skip(fullMethod string) bool {
+	return r.config.Skip != nil && r.config.Skip(fullMethod)
+}
+
+// record ghi một bản ghi log cho RPC đã hoàn thành.
+func (r *recorder) record(ctx context.Context, fullMethod, kind, peerAddr string, start time.Time, err error) {
+	code := status.Code(err)
+	service, method := splitMethod(fullMethod)
+
+	args := []interface{}{
+		log.Any("grpc.service", service),
+		log.Any("grpc.method", method),
+		log.Any("grpc.kind", kind),
+		log.Any("grpc.code", code.String()),
+		log.Any("grpc.duration", time.Since(start)),
+	}
+	if peerAddr != "" {
+		args = append(args, log.Any("peer.address", peerAddr))
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		args = append(args, log.Any("grpc.deadline", deadline.Format(time.RFC3339Nano)))
+	}
+	if err != nil {
+		args = append(args, log.Any("error", status.Convert(err).Message()))
+	}
+
+	switch r.config.LevelFunc(code) {
+	case handler.DebugLevel:
+		r.logger.Debug(r.config.Message, args...)
+	case handler.InfoLevel:
+		r.logger.Info(r.config.Message, args...)
+	case handler.WarningLevel:
+		r.logger.Warning(r.config.Message, args...)
+	case handler.ErrorLevel:
+		r.logger.Error(r.config.Message, args...)
+	default:
+		r.logger.Fatal(r.config.Message, args...)
+	}
+}
+
+// splitMethod tách full method "/package.Service/Method" thành service và method.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", path.Base(fullMethod)
+}
+
+// streamKind xác định loại stream từ các cờ client/server streaming.
+func streamKind(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return KindBidiStream
+	case clientStream:
+		return KindClientStream
+	default:
+		return KindServerStream
+	}
+}
+
+// peerAddress trả về địa chỉ của peer lưu trong context, nếu có.
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package grpclog
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// newTestManager tạo manager cho testing với capture handler gắn vào logger "gRPC".
+func newTestManager(t *testing.T) (log.Manager, *handler.MemoryHandler) {
+	config := log.DefaultConfig()
+	config.File.Path = t.TempDir() + "/app.log"
+
+	m := log.NewManager(config)
+	t.Cleanup(func() { m.Close() })
+
+	capture := handler.NewMemoryHandler()
+	m.GetLogger("gRPC")
+	m.AddHandler("capture", capture)
+	return m, capture
+}
+
+// fakeServerStream triển khai grpc.ServerStream tối thiểu cho testing
+type fakeServerStream struct {
+	ctx context.Context
+}
+
+func (s *fakeServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *fakeServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *fakeServerStream) SetTrailer(metadata.MD)       {}
+func (s *fakeServerStream) Context() context.Context     { return s.ctx }
+func (s *fakeServerStream) SendMsg(interface{}) error    { return nil }
+func (s *fakeServerStream) RecvMsg(interface{}) error    { return nil }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m, capture := newTestManager(t)
+	interceptor := UnaryServerInterceptor(m, nil)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/GetUser"}
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Interceptor phải trả về lỗi gốc, got %v", err)
+	}
+
+	if capture.Len() != 1 {
+		t.Fatalf("Interceptor nên ghi 1 log entry, got %d", capture.Len())
+	}
+	msg := capture.Entries()[0].Message
+	for _, want := range []string{"grpc.service=user.v1.UserService", "grpc.method=GetUser", "grpc.kind=unary", "grpc.code=NotFound", "peer.address=10.0.0.1:5000", `error="user not found"`, "grpc.duration="} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Log entry thiếu %q, got %q", want, msg)
+		}
+	}
+	if capture.Entries()[0].Level != handler.WarningLevel {
+		t.Errorf("NotFound nên log ở WarningLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	m, capture := newTestManager(t)
+	interceptor := StreamServerInterceptor(m, nil)
+
+	info := &grpc.StreamServerInfo{FullMethod: "/chat.Chat/Talk", IsClientStream: true, IsServerStream: true}
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return status.Error(codes.Internal, "boom")
+	})
+	if err == nil {
+		t.Fatal("Interceptor phải trả về lỗi gốc")
+	}
+
+	if capture.Len() != 1 || !strings.Contains(capture.Entries()[0].Message, "grpc.kind=bidi_stream") {
+		t.Fatalf("Interceptor nên ghi log với grpc.kind=bidi_stream, got %v", capture.Entries())
+	}
+	if capture.Entries()[0].Level != handler.ErrorLevel {
+		t.Errorf("Internal nên log ở ErrorLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestUnaryServerInterceptor_Skip(t *testing.T) {
+	m, capture := newTestManager(t)
+	config := DefaultConfig()
+	config.Skip = func(fullMethod string) bool { return strings.HasPrefix(fullMethod, "/grpc.health") }
+	interceptor := UnaryServerInterceptor(m, config)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	if capture.Len() != 0 {
+		t.Errorf("Method bị skip không nên được ghi log, got %v", capture.Entries())
+	}
+}
+
+func TestNewRecorder_DoesNotModifyConfig(t *testing.T) {
+	m, _ := newTestManager(t)
+	config := DefaultConfig()
+	config.LevelFunc = nil
+
+	UnaryServerInterceptor(m, config)
+	UnaryClientInterceptor(m, config)
+	if config.LevelFunc != nil {
+		t.Error("Interceptor không được ghi giá trị mặc định vào cấu hình của người gọi")
+	}
+}
+
+func TestDefaultLevel(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want handler.Level
+	}{
+		{codes.OK, handler.InfoLevel},
+		{codes.InvalidArgument, handler.WarningLevel},
+		{codes.Unauthenticated, handler.WarningLevel},
+		{codes.Internal, handler.ErrorLevel},
+		{codes.Unavailable, handler.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultLevel(tt.code); got != tt.want {
+			t.Errorf("DefaultLevel(%v) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/pkg.Service/Method")
+	if service != "pkg.Service" || method != "Method" {
+		t.Errorf("splitMethod() = %q, %q", service, method)
+	}
+
+	service, method = splitMethod("malformed")
+	if service != "unknown" || method != "malformed" {
+		t.Errorf("splitMethod() với method không hợp lệ = %q, %q", service, method)
+	}
+}
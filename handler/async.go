@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Giá trị mặc định của AsyncHandler khi workers hoặc queueSize không dương.
+const (
+	DefaultAsyncWorkers   = 1    // Số worker mặc định
+	DefaultAsyncQueueSize = 1024 // Kích thước hàng đợi mặc định
+)
+
+// ErrAsyncHandlerClosed được trả về khi ghi log vào AsyncHandler đã bị dừng.
+var ErrAsyncHandlerClosed = errors.New("async handler is closed")
+
+// AsyncHandler bọc một handler và ghi log qua một nhóm worker goroutine với hàng đợi giới hạn.
+//
+// Bên gọi chỉ chờ khi hàng đợi đầy (với chế độ BestEffort của NewDeliveryHandler, entry
+// bị bỏ qua thay vì chờ); handler được bọc được gọi từ các worker. Với nhiều worker, thứ tự
+// ghi giữa các entry không được đảm bảo. Lỗi từ handler được bọc không còn bên gọi để nhận
+// nên được đếm (xem Failed) và gửi đến hàm đặt qua SetErrorHandler.
+//
+// Tính năng:
+//   - Số worker và kích thước hàng đợi riêng cho từng handler
+//   - Giữ nguyên timestamp của entry khi ghi bất đồng bộ
+//   - Xử lý hết hàng đợi trước khi dừng
+type AsyncHandler struct {
+	handler Handler                     // Handler được bọc
+	queue   chan *Entry                 // Hàng đợi các entry chờ ghi
+	wg      sync.WaitGroup              // Theo dõi các worker đang chạy
+	mu      sync.RWMutex                // Bảo vệ closed và việc gửi vào queue
+	closed  bool                        // Handler đã dừng nhận entry
+	drop    bool                        // Bỏ qua entry thay vì chờ khi hàng đợi đầy
+	dropped atomic.Uint64               // Số entry đã bị bỏ qua do hàng đợi đầy
+	failed  atomic.Uint64               // Số entry handler được bọc ghi thất bại
+	onError atomic.Pointer[func(error)] // Hàm nhận lỗi ghi của worker, nil = bỏ qua
+	pending atomic.Int64                // Số entry đã nhận nhưng chưa ghi xong
+	flushMu sync.Mutex                  // Đi kèm flushed
+	flushed *sync.Cond                  // Báo cho Flush khi pending về 0
+}
+
+// NewAsyncHandler tạo một AsyncHandler bọc h với số worker và kích thước hàng đợi đã cho.
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - workers: int - số worker goroutine (<= 0 để dùng DefaultAsyncWorkers)
+//   - queueSize: int - số entry tối đa chờ trong hàng đợi (<= 0 để dùng DefaultAsyncQueueSize)
+//
+// Trả về:
+//   - *AsyncHandler: handler bất đồng bộ đã khởi động các worker
+//
+// Ví dụ:
+//
+//	// Sink HTTP từ xa chịu độ trễ mạng nên cần nhiều worker và hàng đợi lớn
+//	remote := handler.NewAsyncHandler(lokiHandler, 8, 10000)
+//	defer remote.Close()
+func NewAsyncHandler(h Handler, workers, queueSize int) *AsyncHandler {
+	if workers <= 0 {
+		workers = DefaultAsyncWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncQueueSize
+	}
+
+	a := &AsyncHandler{
+		handler: h,
+		queue:   make(chan *Entry, queueSize),
+	}
+	a.flushed = sync.NewCond(&a.flushMu)
+	a.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go a.work()
+	}
+	return a
+}
+
+// Log đưa một log entry vào hàng đợi với timestamp hiện tại.
+//
+// Tham số:
+//   - level: Level - cấp độ nghiêm trọng của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - các tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: ErrAsyncHandlerClosed nếu handler đã dừng
+func (a *AsyncHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return a.enqueue(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// LogEntry đưa bản sao của một log entry hoàn chỉnh vào hàng đợi, chờ nếu hàng đợi đầy
+// (hoặc bỏ qua entry nếu handler được tạo với chế độ BestEffort).
+//
+// Entry được sao chép (xem Entry.Clone) vì nó được ghi sau khi LogEntry trả về, trong khi
+// các handler khác và bên gọi vẫn giữ entry gốc cùng slice Fields của nó.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: ErrAsyncHandlerClosed nếu handler đã dừng
+func (a *AsyncHandler) LogEntry(entry *Entry) error {
+	return a.enqueue(entry.Clone())
+}
+
+// enqueue đưa entry thuộc sở hữu của handler vào hàng đợi.
+func (a *AsyncHandler) enqueue(entry *Entry) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrAsyncHandlerClosed
+	}
+	a.addPending(1)
+	if a.drop {
+		select {
+		case a.queue <- entry:
+		default:
+			a.dropped.Add(1)
+			a.addPending(-1)
+		}
+		return nil
+	}
+	a.queue <- entry
+	return nil
+}
+
+// addPending cộng delta vào số entry chưa ghi xong và đánh thức các lời gọi Flush khi số này về 0.
+func (a *AsyncHandler) addPending(delta int64) {
+	if a.pending.Add(delta) == 0 {
+		a.flushMu.Lock()
+		a.flushed.Broadcast()
+		a.flushMu.Unlock()
+	}
+}
+
+// Flush chờ đến khi mọi entry đã vào hàng đợi được ghi đến handler được bọc (AsyncHandler
+// triển khai Flusher). Entry được đưa vào trong lúc chờ cũng được chờ. Bộ đệm của handler được
+// bọc do handler.Flush ghi hết khi đi qua chuỗi Unwrap.
+//
+// Trả về:
+//   - error: luôn là nil
+//
+// Ví dụ:
+//
+//	logger.Info("Shutting down")
+//	_ = remote.Flush() // entry trên đã được gửi đến lokiHandler
+func (a *AsyncHandler) Flush() error {
+	a.flushMu.Lock()
+	defer a.flushMu.Unlock()
+	for a.pending.Load() > 0 {
+		a.flushed.Wait()
+	}
+	return nil
+}
+
+// Dropped trả về số entry đã bị bỏ qua do hàng đợi đầy.
+//
+// Trả về:
+//   - uint64: số entry bị bỏ qua, luôn bằng 0 nếu handler chờ khi hàng đợi đầy
+func (a *AsyncHandler) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Failed trả về số entry mà handler được bọc ghi thất bại trên các worker.
+//
+// Trả về:
+//   - uint64: số entry ghi thất bại
+func (a *AsyncHandler) Failed() uint64 {
+	return a.failed.Load()
+}
+
+// SetErrorHandler đặt hàm nhận lỗi khi handler được bọc không ghi được một entry. Hàm được gọi
+// trên goroutine của worker nên phải nhanh và an toàn khi dùng đồng thời. Manager gửi lỗi này
+// đến Manager.SetErrorHandler như lỗi của handler ghi đồng bộ. Method này là thread-safe.
+//
+// Tham số:
+//   - fn: func(error) - hàm nhận lỗi, nil để chỉ đếm lỗi
+//
+// Ví dụ:
+//
+//	remote.SetErrorHandler(func(err error) {
+//	    metrics.RemoteLogErrors.Inc()
+//	})
+func (a *AsyncHandler) SetErrorHandler(fn func(err error)) {
+	if fn == nil {
+		a.onError.Store(nil)
+		return
+	}
+	a.onError.Store(&fn)
+}
+
+// QueueDepth trả về số entry đang chờ trong hàng đợi.
+//
+// Trả về:
+//   - int: số entry đang chờ
+func (a *AsyncHandler) QueueDepth() int {
+	return len(a.queue)
+}
+
+// Health kiểm tra handler còn nhận entry hay không.
+//
+// Trả về:
+//   - error: ErrAsyncHandlerClosed nếu handler đã dừng
+func (a *AsyncHandler) Health() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrAsyncHandlerClosed
+	}
+	return nil
+}
+
+// Saturation trả về tỷ lệ lấp đầy của hàng đợi. Hàng đợi đầy khiến bên gọi phải chờ (hoặc
+// entry bị bỏ qua với BestEffort) nên tỷ lệ cao cho thấy handler được bọc ghi không kịp.
+//
+// Trả về:
+//   - float64: từ 0 (rỗng) đến 1 (đầy)
+func (a *AsyncHandler) Saturation() float64 {
+	return float64(len(a.queue)) / float64(cap(a.queue))
+}
+
+// Stop ngừng nhận entry mới và chờ các worker ghi hết hàng đợi, nhưng không đóng
+// handler được bọc. Dùng khi handler được bọc thuộc sở hữu của bên khác.
+func (a *AsyncHandler) Stop() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	a.wg.Wait()
+}
+
+// Close ghi hết hàng đợi, dừng các worker rồi đóng handler được bọc.
+//
+// Trả về:
+//   - error: lỗi từ Close của handler được bọc
+func (a *AsyncHandler) Close() error {
+	a.Stop()
+	return a.handler.Close()
+}
+
+// Unwrap trả về handler được bọc.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (a *AsyncHandler) Unwrap() Handler {
+	return a.handler
+}
+
+// work ghi các entry từ hàng đợi đến handler được bọc cho đến khi hàng đợi bị đóng.
+func (a *AsyncHandler) work() {
+	defer a.wg.Done()
+
+	for entry := range a.queue {
+		if err := Dispatch(a.handler, entry); err != nil {
+			a.failed.Add(1)
+			if fn := a.onError.Load(); fn != nil {
+				(*fn)(err)
+			}
+		}
+		a.addPending(-1)
+	}
+}
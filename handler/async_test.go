@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowRecorder ghi lại các entry nhận được, có thể chặn cho đến khi được giải phóng
+type slowRecorder struct {
+	mu      sync.Mutex
+	entries []*Entry
+	release chan struct{}
+	closed  bool
+}
+
+func (s *slowRecorder) Log(level Level, message string, args ...interface{}) error {
+	return s.LogEntry(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+func (s *slowRecorder) LogEntry(entry *Entry) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *slowRecorder) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestAsyncHandler_DeliversAndDrains(t *testing.T) {
+	rec := &slowRecorder{release: make(chan struct{})}
+	a := NewAsyncHandler(rec, 1, 10)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			a.LogEntry(&Entry{Time: at, Level: InfoLevel, Message: "queued"})
+		}
+		a.Log(WarningLevel, "user %d", 7)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LogEntry() không nên chờ handler được bọc khi hàng đợi còn chỗ")
+	}
+
+	close(rec.release)
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(rec.entries) != 6 {
+		t.Fatalf("Close() nên ghi hết hàng đợi, got %d entry", len(rec.entries))
+	}
+	if !rec.entries[0].Time.Equal(at) {
+		t.Errorf("AsyncHandler nên giữ timestamp gốc, got %v", rec.entries[0].Time)
+	}
+	if rec.entries[5].Message != "user 7" {
+		t.Errorf("Log() nên định dạng thông điệp trước khi đưa vào hàng đợi, got %q", rec.entries[5].Message)
+	}
+	if !rec.closed {
+		t.Error("Close() nên đóng handler được bọc")
+	}
+	if err := a.Log(InfoLevel, "late"); !errors.Is(err, ErrAsyncHandlerClosed) {
+		t.Errorf("Log() sau khi đóng nên trả về ErrAsyncHandlerClosed, got %v", err)
+	}
+}
+
+func TestAsyncHandler_StopKeepsWrappedOpen(t *testing.T) {
+	rec := &slowRecorder{}
+	a := NewAsyncHandler(rec, 4, 0)
+	for i := 0; i < 100; i++ {
+		a.Log(InfoLevel, "entry")
+	}
+	a.Stop()
+	a.Stop()
+
+	if len(rec.entries) != 100 {
+		t.Errorf("Stop() nên ghi hết hàng đợi, got %d entry", len(rec.entries))
+	}
+	if rec.closed {
+		t.Error("Stop() không nên đóng handler được bọc")
+	}
+	if a.Unwrap() != rec {
+		t.Error("Unwrap() nên trả về handler được bọc")
+	}
+}
+
+func TestAsyncHandler_LogEntryCopiesEntry(t *testing.T) {
+	rec := &slowRecorder{release: make(chan struct{})}
+	a := NewAsyncHandler(rec, 1, 4)
+
+	entry := &Entry{Level: InfoLevel, Message: "queued", Fields: []Field{{Key: "user", Type: StringType, Str: "alice"}}}
+	if err := a.LogEntry(entry); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+	// Entry gốc được tái sử dụng hoặc sửa sau khi LogEntry trả về
+	entry.Message = "reused"
+	entry.Fields[0].Str = "bob"
+
+	close(rec.release)
+	a.Close()
+	if len(rec.entries) != 1 || rec.entries[0].Message != "queued" || rec.entries[0].Fields[0].Str != "alice" {
+		t.Errorf("AsyncHandler nên ghi bản sao của entry tại thời điểm LogEntry, got %+v", rec.entries)
+	}
+}
+
+func TestAsyncHandler_SetErrorHandler(t *testing.T) {
+	a := NewAsyncHandler(&flakyRecorder{failing: true}, 1, 4)
+	var reported atomic.Int32
+	a.SetErrorHandler(func(err error) {
+		if err != nil {
+			reported.Add(1)
+		}
+	})
+
+	a.Log(InfoLevel, "first")
+	a.Log(InfoLevel, "second")
+	a.Flush()
+	if reported.Load() != 2 || a.Failed() != 2 {
+		t.Errorf("Lỗi của worker nên được đếm và gửi đến ErrorHandler, got %d lỗi, Failed() = %d", reported.Load(), a.Failed())
+	}
+
+	a.SetErrorHandler(nil)
+	a.Log(InfoLevel, "third")
+	a.Close()
+	if reported.Load() != 2 || a.Failed() != 3 {
+		t.Errorf("SetErrorHandler(nil) nên chỉ đếm lỗi, got %d lỗi, Failed() = %d", reported.Load(), a.Failed())
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+)
+
+// Các interface tùy chọn dưới đây mở rộng Handler mà không thay đổi hợp đồng tối thiểu (Log và
+// Close). Manager và các hàm trợ giúp của package phát hiện chúng bằng type assertion trên cả
+// chuỗi handler được bọc (theo Unwrap), giống LevelEnabler, HealthChecker và Rotator.
+
+// Flusher là interface tùy chọn cho các handler giữ entry trong bộ đệm hoặc hàng đợi (VD:
+// AsyncHandler, sink gửi theo lô) và có thể ghi hết theo yêu cầu, VD: trước khi tiến trình thoát
+// hoặc trước khi đọc lại log trong test.
+type Flusher interface {
+	// Flush ghi hết các entry đang được giữ trong bộ đệm.
+	//
+	// Trả về:
+	//   - error: lỗi nếu không thể ghi hết
+	Flush() error
+}
+
+// Leveler là interface tùy chọn cho các handler có cấp độ tối thiểu riêng thay đổi được lúc
+// chạy (VD: LevelHandler). Handler triển khai Leveler nên triển khai cả LevelEnabler để logger
+// không định dạng entry bị từ chối.
+type Leveler interface {
+	// Level trả về cấp độ tối thiểu hiện tại của handler.
+	//
+	// Trả về:
+	//   - Level: cấp độ tối thiểu
+	Level() Level
+
+	// SetLevel thay đổi cấp độ tối thiểu của handler.
+	//
+	// Tham số:
+	//   - level: Level - cấp độ tối thiểu mới
+	SetLevel(level Level)
+}
+
+// Namer là interface tùy chọn cho các handler tự đặt tên (VD: "loki", "sentry"), để đăng ký với
+// Manager.AddNamedHandler mà không cần nêu tên.
+type Namer interface {
+	// Name trả về tên mặc định của handler.
+	//
+	// Trả về:
+	//   - string: tên handler
+	Name() string
+}
+
+// Flush ghi hết bộ đệm của mọi handler trong chuỗi của h (theo Unwrap) triển khai Flusher, từ
+// ngoài vào trong, để entry từ hàng đợi bên ngoài được ghi xuống trước khi bộ đệm bên trong
+// được ghi hết.
+//
+// Tham số:
+//   - h: Handler - handler cần ghi hết bộ đệm
+//
+// Trả về:
+//   - error: lỗi của các handler ghi thất bại, nil nếu không có lỗi
+//
+// Ví dụ:
+//
+//	defer handler.Flush(remote)
+func Flush(h Handler) error {
+	var errs []error
+	for h != nil {
+		if f, ok := h.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return errors.Join(errs...)
+}
+
+// LevelerOf trả về Leveler đầu tiên trong chuỗi của h (theo Unwrap).
+//
+// Tham số:
+//   - h: Handler - handler cần tìm
+//
+// Trả về:
+//   - Leveler: handler có cấp độ riêng
+//   - bool: false nếu không có handler nào trong chuỗi triển khai Leveler
+//
+// Ví dụ:
+//
+//	if leveler, ok := handler.LevelerOf(h); ok {
+//	    leveler.SetLevel(handler.DebugLevel)
+//	}
+func LevelerOf(h Handler) (Leveler, bool) {
+	for h != nil {
+		if l, ok := h.(Leveler); ok {
+			return l, true
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return nil, false
+}
+
+// Name trả về tên mặc định của handler đầu tiên trong chuỗi của h (theo Unwrap) triển khai Namer.
+//
+// Tham số:
+//   - h: Handler - handler cần lấy tên
+//
+// Trả về:
+//   - string: tên handler, rỗng nếu không có handler nào trong chuỗi triển khai Namer
+func Name(h Handler) string {
+	for h != nil {
+		if n, ok := h.(Namer); ok {
+			return n.Name()
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flushRecorder ghi nhận số lần Flush và trả về lỗi đã cho.
+type flushRecorder struct {
+	MockTestHandler
+	flushes int
+	err     error
+}
+
+func (f *flushRecorder) Flush() error {
+	f.flushes++
+	return f.err
+}
+
+// namedHandler là handler tự đặt tên.
+type namedHandler struct {
+	MockTestHandler
+}
+
+func (namedHandler) Name() string { return "loki" }
+
+func TestAsyncHandler_Flush(t *testing.T) {
+	rec := &slowRecorder{release: make(chan struct{})}
+	async := NewAsyncHandler(rec, 1, 8)
+	defer async.Close()
+
+	for i := 0; i < 3; i++ {
+		_ = async.Log(InfoLevel, "entry")
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = async.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Flush() không nên trả về khi entry còn chờ ghi")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(rec.release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Flush() nên trả về khi hàng đợi đã được ghi hết")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.entries) != 3 {
+		t.Errorf("Flush() nên chờ đủ 3 entry, got %d", len(rec.entries))
+	}
+}
+
+func TestFlush(t *testing.T) {
+	inner := &flushRecorder{err: errors.New("upload failed")}
+	async := NewAsyncHandler(NewTransformHandler(inner, TransformOptions{}), 1, 4)
+	defer async.Close()
+
+	if err := Flush(async); err == nil || inner.flushes != 1 {
+		t.Errorf("Flush() nên đi qua chuỗi Unwrap và trả về lỗi, got %v (%d lần)", err, inner.flushes)
+	}
+	if err := Flush(&MockTestHandler{}); err != nil {
+		t.Errorf("Handler không triển khai Flusher nên được bỏ qua, got %v", err)
+	}
+	if err := NewStackHandler(&MockTestHandler{}, inner).Flush(); err == nil || inner.flushes != 2 {
+		t.Errorf("StackHandler.Flush() nên ghi hết các handler con, got %v (%d lần)", err, inner.flushes)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	mem := NewMemoryHandler()
+	h := NewLevelHandler(mem, ErrorLevel)
+
+	_ = h.Log(WarningLevel, "ignored")
+	_ = h.LogEntry(&Entry{Level: ErrorLevel, Message: "kept"})
+	if mem.Len() != 1 || !mem.Contains("kept") {
+		t.Errorf("LevelHandler chỉ nên chuyển entry từ ErrorLevel trở lên, got %v", mem.Entries())
+	}
+	if Enabled(h, WarningLevel) || !Enabled(h, ErrorLevel) {
+		t.Error("Enabled() nên theo cấp độ của LevelHandler")
+	}
+
+	leveler, ok := LevelerOf(NewTransformHandler(h, TransformOptions{}))
+	if !ok {
+		t.Fatal("LevelerOf() nên tìm thấy LevelHandler qua chuỗi Unwrap")
+	}
+	leveler.SetLevel(WarningLevel)
+	if h.Level() != WarningLevel || !Enabled(h, WarningLevel) {
+		t.Errorf("SetLevel() nên thay đổi cấp độ, got %v", h.Level())
+	}
+	if _, ok := LevelerOf(mem); ok {
+		t.Error("MemoryHandler không triển khai Leveler")
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name(NewTransformHandler(&namedHandler{}, TransformOptions{})); got != "loki" {
+		t.Errorf("Name() nên đi qua chuỗi Unwrap, got %q", got)
+	}
+	if got := Name(&MockTestHandler{}); got != "" {
+		t.Errorf("Handler không triển khai Namer nên có tên rỗng, got %q", got)
+	}
+}
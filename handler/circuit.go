@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Giá trị mặc định của CircuitHandler.
+const (
+	DefaultRetryBackoff       = 50 * time.Millisecond // Thời gian chờ trước lần thử lại đầu tiên
+	DefaultRetryMaxBackoff    = 5 * time.Second       // Thời gian chờ tối đa giữa hai lần thử lại
+	DefaultCircuitOpenTimeout = 30 * time.Second      // Thời gian mạch mở trước khi thử lại handler
+)
+
+// ErrCircuitOpen được trả về khi CircuitHandler từ chối entry vì mạch đang mở.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState là trạng thái của bộ ngắt mạch trong CircuitHandler.
+type CircuitState int
+
+const (
+	// CircuitClosed: handler hoạt động bình thường, mọi entry được ghi
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen: handler thất bại liên tiếp, entry bị từ chối ngay với ErrCircuitOpen
+	CircuitOpen
+
+	// CircuitHalfOpen: hết thời gian mở, một entry được ghi thử để kiểm tra handler đã hồi phục
+	CircuitHalfOpen
+)
+
+// String trả về tên của trạng thái.
+//
+// Trả về:
+//   - string: "closed", "open", "half_open" hoặc "unknown"
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryPolicy cấu hình việc thử lại và ngắt mạch của CircuitHandler.
+type RetryPolicy struct {
+	Retries          int           // Số lần thử lại sau lần ghi đầu tiên thất bại
+	Backoff          time.Duration // Thời gian chờ trước lần thử lại đầu tiên, nhân đôi sau mỗi lần (0 = DefaultRetryBackoff)
+	MaxBackoff       time.Duration // Thời gian chờ tối đa giữa hai lần thử lại (0 = DefaultRetryMaxBackoff)
+	FailureThreshold int           // Số entry ghi thất bại liên tiếp trước khi mở mạch (0 = không ngắt mạch)
+	OpenTimeout      time.Duration // Thời gian mạch mở trước khi ghi thử (0 = DefaultCircuitOpenTimeout)
+}
+
+// CircuitStats là trạng thái và bộ đếm của một CircuitHandler, dùng cho metrics.
+type CircuitStats struct {
+	State    CircuitState // Trạng thái hiện tại của mạch
+	Failures int          // Số entry ghi thất bại liên tiếp
+	Retries  uint64       // Tổng số lần thử lại
+	Rejected uint64       // Tổng số entry bị từ chối khi mạch mở
+	Opens    uint64       // Số lần mạch chuyển sang mở
+}
+
+// CircuitReporter là interface tùy chọn cho các handler có bộ ngắt mạch (VD: CircuitHandler).
+type CircuitReporter interface {
+	// CircuitStats trả về trạng thái và bộ đếm hiện tại của bộ ngắt mạch.
+	//
+	// Trả về:
+	//   - CircuitStats: ảnh chụp trạng thái
+	CircuitStats() CircuitStats
+}
+
+// CircuitHandler bọc một handler, thử lại các lần ghi thất bại tạm thời với thời gian chờ tăng
+// theo cấp số nhân và mở mạch sau FailureThreshold entry thất bại liên tiếp.
+//
+// Khi mạch mở, entry bị từ chối ngay với ErrCircuitOpen thay vì làm chậm bên gọi. Sau
+// OpenTimeout, một entry được ghi thử (không thử lại); thành công đóng mạch, thất bại mở lại
+// mạch. Việc thử lại chặn bên gọi nên thường được kết hợp với AsyncHandler. Handler an toàn khi
+// dùng đồng thời.
+type CircuitHandler struct {
+	handler Handler     // Handler được bọc
+	policy  RetryPolicy // Chính sách thử lại và ngắt mạch đã áp dụng mặc định
+
+	mu       sync.Mutex
+	stats    CircuitStats
+	openedAt time.Time // Thời điểm mạch mở gần nhất
+	probing  bool      // Đang có một entry ghi thử ở trạng thái half-open
+	lastErr  error     // Lỗi gần nhất của handler được bọc
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewCircuitHandler tạo một CircuitHandler bọc h.
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - policy: RetryPolicy - chính sách thử lại và ngắt mạch
+//
+// Trả về:
+//   - *CircuitHandler: handler đã được cấu hình
+//
+// Ví dụ:
+//
+//	remote := handler.NewAsyncHandler(handler.NewCircuitHandler(lokiHandler, handler.RetryPolicy{
+//	    Retries:          3,
+//	    Backoff:          100 * time.Millisecond,
+//	    FailureThreshold: 5,
+//	    OpenTimeout:      time.Minute,
+//	}), 4, 10000)
+func NewCircuitHandler(h Handler, policy RetryPolicy) *CircuitHandler {
+	if policy.Retries < 0 {
+		policy.Retries = 0
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = DefaultRetryBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if policy.OpenTimeout <= 0 {
+		policy.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+	return &CircuitHandler{handler: h, policy: policy, now: time.Now, sleep: time.Sleep}
+}
+
+// Log ghi một log entry với timestamp hiện tại.
+//
+// Tham số:
+//   - level: Level - cấp độ nghiêm trọng của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - các tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: ErrCircuitOpen nếu mạch đang mở, hoặc lỗi sau tất cả các lần thử
+func (c *CircuitHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return c.LogEntry(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// LogEntry ghi entry vào handler được bọc, thử lại khi thất bại và cập nhật trạng thái mạch.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: ErrCircuitOpen nếu mạch đang mở, hoặc lỗi sau tất cả các lần thử
+func (c *CircuitHandler) LogEntry(entry *Entry) error {
+	probe, err := c.admit()
+	if err != nil {
+		return err
+	}
+
+	retries := c.policy.Retries
+	if probe {
+		retries = 0
+	}
+	backoff := c.policy.Backoff
+	for attempt := 0; ; attempt++ {
+		if err = Dispatch(c.handler, entry); err == nil || attempt >= retries {
+			break
+		}
+		c.mu.Lock()
+		c.stats.Retries++
+		c.mu.Unlock()
+		c.sleep(backoff)
+		backoff = min(backoff*2, c.policy.MaxBackoff)
+	}
+
+	c.record(probe, err)
+	if err != nil && retries > 0 {
+		return fmt.Errorf("write failed after %d attempts: %w", retries+1, err)
+	}
+	return err
+}
+
+// admit kiểm tra entry có được ghi theo trạng thái mạch hay không.
+//
+// Trả về:
+//   - bool: true nếu entry là lần ghi thử ở trạng thái half-open
+//   - error: ErrCircuitOpen nếu entry bị từ chối
+func (c *CircuitHandler) admit() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.stats.State {
+	case CircuitOpen:
+		if c.now().Sub(c.openedAt) < c.policy.OpenTimeout {
+			c.stats.Rejected++
+			return false, ErrCircuitOpen
+		}
+		c.stats.State = CircuitHalfOpen
+		c.probing = true
+		return true, nil
+	case CircuitHalfOpen:
+		// Chỉ một entry được ghi thử tại một thời điểm
+		if c.probing {
+			c.stats.Rejected++
+			return false, ErrCircuitOpen
+		}
+		c.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// record cập nhật trạng thái mạch theo kết quả ghi.
+func (c *CircuitHandler) record(probe bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if probe {
+		c.probing = false
+	}
+	if err == nil {
+		c.stats.Failures, c.lastErr = 0, nil
+		c.stats.State = CircuitClosed
+		return
+	}
+	c.stats.Failures++
+	c.lastErr = err
+	threshold := c.policy.FailureThreshold
+	if probe || (threshold > 0 && c.stats.Failures >= threshold && c.stats.State == CircuitClosed) {
+		c.stats.State = CircuitOpen
+		c.stats.Opens++
+		c.openedAt = c.now()
+	}
+}
+
+// CircuitStats trả về trạng thái và bộ đếm hiện tại của bộ ngắt mạch. Method này là thread-safe.
+//
+// Trả về:
+//   - CircuitStats: ảnh chụp trạng thái
+func (c *CircuitHandler) CircuitStats() CircuitStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// Health báo cáo khi mạch không ở trạng thái đóng.
+//
+// Trả về:
+//   - error: trạng thái mạch và lỗi gần nhất của handler được bọc, nil nếu mạch đóng
+func (c *CircuitHandler) Health() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats.State == CircuitClosed {
+		return nil
+	}
+	return fmt.Errorf("circuit %s after %d consecutive failures: %w", c.stats.State, c.stats.Failures, c.lastErr)
+}
+
+// Close đóng handler được bọc.
+//
+// Trả về:
+//   - error: lỗi từ Close của handler được bọc
+func (c *CircuitHandler) Close() error {
+	return c.handler.Close()
+}
+
+// Unwrap trả về handler được bọc.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (c *CircuitHandler) Unwrap() Handler {
+	return c.handler
+}
+
+// Circuit trả về trạng thái bộ ngắt mạch đầu tiên trong chuỗi handler (theo Unwrap).
+//
+// Tham số:
+//   - h: Handler - handler cần kiểm tra
+//
+// Trả về:
+//   - CircuitStats: trạng thái của bộ ngắt mạch
+//   - bool: false nếu không có handler nào triển khai CircuitReporter
+//
+// Ví dụ:
+//
+//	if stats, ok := handler.Circuit(manager.GetHandler("loki")); ok && stats.State == handler.CircuitOpen {
+//	    alert("loki đang bị ngắt mạch")
+//	}
+func Circuit(h Handler) (CircuitStats, bool) {
+	for h != nil {
+		if r, ok := h.(CircuitReporter); ok {
+			return r.CircuitStats(), true
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return CircuitStats{}, false
+}
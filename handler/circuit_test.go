@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyHandler thất bại ở failures lần ghi đầu tiên.
+type flakyHandler struct {
+	MockTestHandler
+	failures int
+	calls    int
+}
+
+func (f *flakyHandler) Log(level Level, message string, args ...interface{}) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("transient error")
+	}
+	return f.MockTestHandler.Log(level, message, args...)
+}
+
+func TestCircuitHandler_RetriesWithBackoff(t *testing.T) {
+	flaky := &flakyHandler{failures: 3}
+	c := NewCircuitHandler(flaky, RetryPolicy{Retries: 3, Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond})
+	var delays []time.Duration
+	c.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if err := c.Log(InfoLevel, "retry"); err != nil {
+		t.Fatalf("Log() nên thành công sau khi thử lại, got %v", err)
+	}
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("Thời gian chờ = %v, want %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("Thời gian chờ lần %d = %v, want %v", i+1, delays[i], want[i])
+		}
+	}
+	if stats := c.CircuitStats(); stats.Retries != 3 || stats.State != CircuitClosed {
+		t.Errorf("CircuitStats() = %+v", stats)
+	}
+}
+
+func TestCircuitHandler_OpensAndProbes(t *testing.T) {
+	primary := &MockTestHandler{ShouldError: true}
+	c := NewCircuitHandler(primary, RetryPolicy{FailureThreshold: 2, OpenTimeout: time.Minute})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Log(ErrorLevel, "first")
+	c.Log(ErrorLevel, "second")
+	if stats, _ := Circuit(c); stats.State != CircuitOpen || stats.Opens != 1 {
+		t.Fatalf("Mạch nên mở sau 2 lần thất bại liên tiếp, got %+v", stats)
+	}
+	primary.LogCalled = false
+	if err := c.Log(ErrorLevel, "rejected"); !errors.Is(err, ErrCircuitOpen) || primary.LogCalled {
+		t.Errorf("Entry nên bị từ chối khi mạch mở, got %v", err)
+	}
+	if err := Health(c); err == nil {
+		t.Error("Health() nên báo lỗi khi mạch mở")
+	}
+
+	// Lần ghi thử thất bại mở lại mạch
+	now = now.Add(time.Minute)
+	if err := c.Log(ErrorLevel, "probe"); err == nil || errors.Is(err, ErrCircuitOpen) || !primary.LogCalled {
+		t.Errorf("Handler nên được ghi thử sau OpenTimeout, got %v", err)
+	}
+	if stats := c.CircuitStats(); stats.State != CircuitOpen || stats.Opens != 2 {
+		t.Errorf("Mạch nên mở lại khi ghi thử thất bại, got %+v", stats)
+	}
+
+	// Lần ghi thử thành công đóng mạch
+	now = now.Add(time.Minute)
+	primary.ShouldError = false
+	if err := c.Log(ErrorLevel, "recovered"); err != nil {
+		t.Errorf("Log() error = %v", err)
+	}
+	if stats := c.CircuitStats(); stats.State != CircuitClosed || stats.Failures != 0 || stats.Rejected != 1 {
+		t.Errorf("Mạch nên đóng khi ghi thử thành công, got %+v", stats)
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock cung cấp thời điểm hiện tại cho handler và logger, cho phép test cố định thời gian để so
+// sánh output log một cách tất định (VD: với golden file).
+type Clock interface {
+	// Now trả về thời điểm hiện tại.
+	Now() time.Time
+}
+
+// SystemClock là Clock dùng thời gian của hệ thống (time.Now), được dùng khi không có Clock nào
+// được thiết lập.
+var SystemClock Clock = systemClock{}
+
+// systemClock triển khai Clock bằng time.Now.
+type systemClock struct{}
+
+// Now trả về time.Now().
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock là Clock chỉ thay đổi khi được đặt hoặc tăng thủ công, dùng trong test.
+// ManualClock là thread-safe.
+type ManualClock struct {
+	now time.Time  // Thời điểm hiện tại của clock
+	mu  sync.Mutex // Mutex bảo vệ now
+}
+
+// NewManualClock tạo một ManualClock dừng tại thời điểm t.
+//
+// Tham số:
+//   - t: time.Time - thời điểm ban đầu
+//
+// Trả về:
+//   - *ManualClock: clock dừng tại t
+//
+// Ví dụ:
+//
+//	clock := handler.NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+//	fileHandler.SetClock(clock)
+//	clock.Advance(time.Second)
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now trả về thời điểm hiện tại của clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set đặt thời điểm hiện tại của clock.
+//
+// Tham số:
+//   - t: time.Time - thời điểm mới
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance tăng thời điểm hiện tại của clock thêm d.
+//
+// Tham số:
+//   - d: time.Duration - khoảng thời gian cần tăng
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// clockRef giữ Clock của một handler, có thể được thay thế trong khi handler đang ghi log.
+// Zero value dùng SystemClock.
+type clockRef struct {
+	clock atomic.Value // clockBox
+}
+
+// clockBox bọc Clock để atomic.Value luôn lưu cùng một kiểu cụ thể.
+type clockBox struct{ Clock }
+
+// Now trả về thời điểm hiện tại theo Clock đã thiết lập.
+func (r *clockRef) Now() time.Time {
+	if box, ok := r.clock.Load().(clockBox); ok {
+		return box.Now()
+	}
+	return time.Now()
+}
+
+// set thay thế Clock, nil để dùng SystemClock.
+func (r *clockRef) set(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	r.clock.Store(clockBox{clock})
+}
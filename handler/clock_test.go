@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("Now() = %v, want %v", clock.Now(), start)
+	}
+	clock.Advance(time.Minute)
+	if want := start.Add(time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("Advance() nên tăng thời điểm, got %v want %v", clock.Now(), want)
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Set() nên đặt thời điểm, got %v", clock.Now())
+	}
+}
+
+func TestFileHandler_SetClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	clock := NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local))
+	h.SetClock(clock)
+	h.Log(InfoLevel, "ready")
+	if err := h.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path + "." + clock.Now().Format(backupSuffixLayout))
+	if err != nil {
+		t.Fatalf("File sao lưu nên được đặt tên theo clock: %v", err)
+	}
+	if got := string(data); got != "2024/03/01 12:00:00 [INFO] ready\n" {
+		t.Errorf("Log() nên dùng thời điểm của clock, got %q", got)
+	}
+
+	h.SetClock(nil)
+	h.Log(InfoLevel, "now")
+	data, _ = os.ReadFile(path)
+	if strings.HasPrefix(string(data), "2024/03/01") {
+		t.Errorf("SetClock(nil) nên dùng SystemClock, got %q", data)
+	}
+}
+
+func TestFileHandler_Rotate_SameSecond(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	clock := NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local))
+	h.SetClock(clock)
+	for _, message := range []string{"first", "second", "third"} {
+		h.Log(InfoLevel, message)
+		if err := h.Rotate(); err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+	}
+
+	base := path + "." + clock.Now().Format(backupSuffixLayout)
+	for backup, want := range map[string]string{base: "first", base + "-1": "second", base + "-2": "third"} {
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			t.Fatalf("Xoay vòng trong cùng một giây không được ghi đè file sao lưu: %v", err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s = %q, want %q", backup, data, want)
+		}
+	}
+
+	want := []string{base + "-2", base + "-1", base}
+	if got := backupPaths(path); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("backupPaths() = %v, want %v", got, want)
+	}
+	if created, ok := backupTime(path, base+"-2"); !ok || !created.Equal(clock.Now()) {
+		t.Errorf("backupTime() = %v, %v, want %v", created, ok, clock.Now())
+	}
+}
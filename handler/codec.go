@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Codec nén và giải nén dữ liệu log, dùng cho file sao lưu khi xoay vòng và cho các handler
+// gửi log qua mạng.
+//
+// Thư viện chuẩn chỉ hỗ trợ gzip; các codec khác như zstd hoặc lz4 được đăng ký qua
+// RegisterCodec bằng một adapter nhỏ quanh thư viện tương ứng.
+type Codec interface {
+	// Name trả về tên của codec dùng trong cấu hình (VD: "gzip", "zstd").
+	Name() string
+
+	// Extension trả về phần mở rộng được thêm vào tên file đã nén (VD: ".gz", ".zst").
+	Extension() string
+
+	// NewWriter tạo writer nén dữ liệu ghi vào w. Close của writer phải ghi hết dữ liệu
+	// nén nhưng không đóng w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// NewReader tạo reader giải nén dữ liệu đọc từ r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// codecs là registry các codec theo tên.
+var codecs = struct {
+	sync.RWMutex
+	byName map[string]Codec
+}{byName: map[string]Codec{"gzip": gzipCodec{}}}
+
+// RegisterCodec đăng ký một codec, thay thế codec đã đăng ký cùng tên (VD: để dùng một
+// triển khai gzip nhanh hơn). Hàm này thường được gọi trong init.
+//
+// Tham số:
+//   - codec: Codec - codec cần đăng ký, tên không được rỗng
+//
+// Ví dụ:
+//
+//	// Adapter zstd dùng github.com/klauspost/compress/zstd
+//	type zstdCodec struct{}
+//
+//	func (zstdCodec) Name() string      { return "zstd" }
+//	func (zstdCodec) Extension() string { return ".zst" }
+//	func (zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }
+//	func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+//	    d, err := zstd.NewReader(r)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return d.IOReadCloser(), nil
+//	}
+//
+//	func init() { handler.RegisterCodec(zstdCodec{}) }
+func RegisterCodec(codec Codec) {
+	if codec == nil || codec.Name() == "" {
+		panic("handler: RegisterCodec codec is nil or has no name")
+	}
+
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.byName[codec.Name()] = codec
+}
+
+// LookupCodec trả về codec đã đăng ký theo tên.
+//
+// Tham số:
+//   - name: string - tên codec
+//
+// Trả về:
+//   - Codec: codec tương ứng
+//   - bool: false nếu chưa có codec nào được đăng ký với tên này
+func LookupCodec(name string) (Codec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+	codec, ok := codecs.byName[name]
+	return codec, ok
+}
+
+// CodecNames trả về tên các codec đã đăng ký theo thứ tự bảng chữ cái.
+//
+// Trả về:
+//   - []string: tên các codec
+func CodecNames() []string {
+	codecs.RLock()
+	defer codecs.RUnlock()
+	names := make([]string, 0, len(codecs.byName))
+	for name := range codecs.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CodecForPath trả về codec có phần mở rộng khớp với đường dẫn file.
+//
+// Tham số:
+//   - path: string - đường dẫn file (VD: "app.log.20240101120000.gz")
+//
+// Trả về:
+//   - Codec: codec tương ứng, hoặc nil nếu file không được nén bởi codec nào đã đăng ký
+func CodecForPath(path string) Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+	for _, codec := range codecs.byName {
+		if ext := codec.Extension(); ext != "" && strings.HasSuffix(path, ext) {
+			return codec
+		}
+	}
+	return nil
+}
+
+// gzipCodec là codec gzip của thư viện chuẩn.
+type gzipCodec struct{}
+
+// Name trả về "gzip".
+func (gzipCodec) Name() string { return "gzip" }
+
+// Extension trả về ".gz".
+func (gzipCodec) Extension() string { return ".gz" }
+
+// NewWriter tạo gzip writer.
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+
+// NewReader tạo gzip reader.
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+
+// SetCompression bật nén các file sao lưu sau khi xoay vòng.
+//
+// File sao lưu được nén ở nền thành "<file>.<timestamp><ext>" rồi file chưa nén bị xóa; nếu
+// nén thất bại, file chưa nén được giữ lại và lỗi được ghi ra stderr. Close chờ các lần nén
+// đang chạy kết thúc. Method này là thread-safe.
+//
+// Tham số:
+//   - codec: Codec - codec dùng để nén, nil để tắt
+//
+// Ví dụ:
+//
+//	codec, _ := handler.LookupCodec("gzip")
+//	fileHandler.SetCompression(codec)
+func (a *FileHandler) SetCompression(codec Codec) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.codec = codec
+}
+
+// compressFile nén path thành path + codec.Extension() rồi xóa path.
+//
+// Tham số:
+//   - path: string - file cần nén
+//   - codec: Codec - codec dùng để nén
+//
+// Trả về:
+//   - error: lỗi khi nén; file gốc được giữ nguyên và file nén dở bị xóa
+func compressFile(path string, codec Codec) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path + codec.Extension()
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	w, err := codec.NewWriter(dst)
+	if err == nil {
+		if _, err = io.Copy(w, src); err == nil {
+			err = w.Close()
+		}
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dstPath)
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
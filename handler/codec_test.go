@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// upperCodec là codec thử nghiệm "nén" bằng cách đổi chữ thường thành chữ hoa
+type upperCodec struct{}
+
+func (upperCodec) Name() string      { return "upper" }
+func (upperCodec) Extension() string { return ".up" }
+func (upperCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return upperWriter{w}, nil
+}
+func (upperCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(r)
+	return io.NopCloser(bytes.NewReader(bytes.ToLower(data))), err
+}
+
+type upperWriter struct{ w io.Writer }
+
+func (u upperWriter) Write(p []byte) (int, error) { return u.w.Write(bytes.ToUpper(p)) }
+func (u upperWriter) Close() error                { return nil }
+
+func TestCodecRegistry(t *testing.T) {
+	if codec, ok := LookupCodec("gzip"); !ok || codec.Extension() != ".gz" {
+		t.Fatalf("gzip nên được đăng ký sẵn, got %v %v", codec, ok)
+	}
+	if _, ok := LookupCodec("zstd"); ok {
+		t.Error("zstd không nên được đăng ký sẵn")
+	}
+
+	RegisterCodec(upperCodec{})
+	if codec := CodecForPath("app.log.20240101120000.up"); codec == nil || codec.Name() != "upper" {
+		t.Errorf("CodecForPath() nên tìm codec theo phần mở rộng, got %v", codec)
+	}
+	if codec := CodecForPath("app.log.20240101120000"); codec != nil {
+		t.Errorf("CodecForPath() nên trả về nil với file chưa nén, got %v", codec)
+	}
+	if names := strings.Join(CodecNames(), ","); !strings.Contains(names, "gzip") || !strings.Contains(names, "upper") {
+		t.Errorf("CodecNames() nên liệt kê các codec đã đăng ký, got %s", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterCodec(nil) nên panic")
+		}
+	}()
+	RegisterCodec(nil)
+}
+
+func TestFileHandler_SetCompression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compress.log")
+	h, err := NewFileHandler(path, 200)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	codec, _ := LookupCodec("gzip")
+	h.SetCompression(codec)
+
+	_ = h.Log(InfoLevel, "before rotation %s", strings.Repeat("x", 200))
+	_ = h.Log(InfoLevel, "after rotation")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Close chờ nén xong: chỉ còn file nén, file sao lưu chưa nén đã bị xóa
+	backups := backupPaths(path)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("Nên có một file sao lưu đã nén, got %v", backups)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(backups[0], ".gz")); !os.IsNotExist(err) {
+		t.Errorf("File sao lưu chưa nén nên bị xóa sau khi nén, got %v", err)
+	}
+
+	entries, err := tailFile(backups[0], 10, nil)
+	if err != nil || len(entries) != 1 || !strings.HasPrefix(entries[0].Message, "before rotation") {
+		t.Errorf("tailFile() nên đọc được file nén, got %v (err %v)", entries, err)
+	}
+}
+
+func TestFileHandler_Tail_CompressedBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tail.log")
+	RegisterCodec(upperCodec{})
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("tail.log", "2024/01/01 12:00:03 [INFO] current\n")
+	writeFile("tail.log.20240101120002.up", "2024/01/01 12:00:02 [INFO] NEWER BACKUP\n")
+	writeFile("tail.log.20240101120001", "2024/01/01 12:00:01 [INFO] older backup\n")
+	// File nén dở của bản sao lưu chưa nén xong bị bỏ qua
+	writeFile("tail.log.20240101120001.up", "2024/01/01 12:00:01 [INFO] PARTIAL\n")
+
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	entries, err := h.Tail(3)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+	if got := strings.Join(messages, "|"); got != "older backup|newer backup|current" {
+		t.Errorf("Tail() nên đọc cả file sao lưu đã nén theo thứ tự thời gian, got %q", got)
+	}
+}
+
+func TestCompressFile_KeepsSourceOnError(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.log.20240101120000")
+	if err := os.WriteFile(src, []byte("data\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	codec, _ := LookupCodec("gzip")
+	// File đích đã tồn tại nên không được ghi đè
+	if err := os.WriteFile(src+".gz", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compressFile(src, codec); err == nil {
+		t.Error("compressFile() nên trả về lỗi khi file đích đã tồn tại")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("File gốc nên được giữ lại khi nén thất bại, got %v", err)
+	}
+}
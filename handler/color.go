@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorMode xác định khi nào console handler dùng mã màu ANSI.
+type ColorMode int
+
+const (
+	// ColorAuto chỉ dùng màu khi ColorSupported trả về true cho đích ghi
+	ColorAuto ColorMode = iota
+	// ColorAlways luôn dùng màu, kể cả khi output bị pipe hoặc NO_COLOR được đặt
+	ColorAlways
+	// ColorNever không bao giờ dùng màu
+	ColorNever
+)
+
+// String trả về tên của chế độ màu.
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAuto:
+		return "auto"
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	default:
+		return "unknown"
+	}
+}
+
+// ColorSupported kiểm tra có nên ghi mã màu ANSI vào w hay không theo các quy ước phổ biến:
+//   - NO_COLOR được đặt (khác rỗng): không dùng màu (https://no-color.org)
+//   - CLICOLOR_FORCE khác rỗng và khác "0": luôn dùng màu
+//   - CLICOLOR=0 hoặc TERM=dumb: không dùng màu
+//   - Còn lại: chỉ dùng màu khi w là một terminal; trên Windows, console phải bật được chế độ
+//     virtual terminal (Windows 10 trở lên)
+//
+// Tham số:
+//   - w: io.Writer - đích ghi của output
+//
+// Trả về:
+//   - bool: true nếu nên dùng màu
+//
+// Ví dụ:
+//
+//	console := handler.NewConsoleHandler(handler.ColorSupported(os.Stdout))
+func ColorSupported(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w) && enableVirtualTerminal(w)
+}
+
+// isTerminal kiểm tra w có phải là một terminal (character device) hay không.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorScheme thay thế màu mặc định của console handler. Các giá trị là mã ANSI, thường được tạo
+// bởi ParseStyle; giá trị rỗng giữ màu mặc định.
+type ColorScheme struct {
+	Levels  map[Level]string // Style của cả dòng theo cấp độ
+	Context string           // Style của đoạn "[Context]" ở đầu thông điệp, rỗng = theo cấp độ
+}
+
+// styleNames ánh xạ tên màu và thuộc tính sang tham số SGR của ANSI.
+var styleNames = map[string]string{
+	"bold": "1", "dim": "2", "italic": "3", "underline": "4",
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+	"gray": "90", "grey": "90", "bright-red": "91", "bright-green": "92", "bright-yellow": "93",
+	"bright-blue": "94", "bright-magenta": "95", "bright-cyan": "96", "bright-white": "97",
+}
+
+// ParseStyle chuyển mô tả style thành mã ANSI. Mô tả gồm các từ cách nhau bởi khoảng trắng:
+//   - Thuộc tính: bold, dim, italic, underline
+//   - Màu cơ bản: black, red, green, yellow, blue, magenta, cyan, white, gray và bright-<màu>
+//   - Màu 256: số từ 0 đến 255
+//   - Truecolor: #rrggbb
+//
+// Tham số:
+//   - spec: string - mô tả style (VD: "bold red", "underline 208", "#ff8800")
+//
+// Trả về:
+//   - string: mã ANSI tương ứng, rỗng nếu spec rỗng
+//   - error: lỗi nếu spec chứa từ không hợp lệ
+//
+// Ví dụ:
+//
+//	code, err := handler.ParseStyle("bold #ff8800") // "\033[1;38;2;255;136;0m"
+func ParseStyle(spec string) (string, error) {
+	tokens := strings.Fields(strings.ToLower(spec))
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	params := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if param, ok := styleNames[token]; ok {
+			params = append(params, param)
+			continue
+		}
+		if strings.HasPrefix(token, "#") && len(token) == 7 {
+			if rgb, err := strconv.ParseUint(token[1:], 16, 32); err == nil {
+				params = append(params, fmt.Sprintf("38;2;%d;%d;%d", rgb>>16, rgb>>8&0xff, rgb&0xff))
+				continue
+			}
+		}
+		if n, err := strconv.ParseUint(token, 10, 8); err == nil {
+			params = append(params, "38;5;"+strconv.FormatUint(n, 10))
+			continue
+		}
+		return "", fmt.Errorf("invalid style %q: unknown token %q", spec, token)
+	}
+	return "\033[" + strings.Join(params, ";") + "m", nil
+}
+
+// appendStyledLine nối một dòng log có màu vào dst: cả dòng dùng style của cấp độ, đoạn
+// "[Context]" ở đầu thông điệp dùng contextStyle nếu khác rỗng.
+func appendStyledLine(dst []byte, entry *Entry, style, contextStyle string, omitTime bool) []byte {
+	dst = append(dst, style...)
+	if !omitTime {
+		dst = entry.Time.AppendFormat(dst, TimeLayout)
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, '[')
+	dst = append(dst, entry.Level.String()...)
+	dst = append(dst, "] "...)
+
+	message := entry.Message
+	if contextStyle != "" && strings.HasPrefix(message, "[") {
+		if i := strings.IndexByte(message, ']'); i > 0 {
+			dst = append(dst, colorReset...)
+			dst = append(dst, contextStyle...)
+			dst = append(dst, message[:i+1]...)
+			dst = append(dst, colorReset...)
+			dst = append(dst, style...)
+			message = message[i+1:]
+		}
+	}
+	dst = append(dst, message...)
+	dst = append(dst, '\n')
+	return append(dst, colorReset...)
+}
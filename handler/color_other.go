@@ -0,0 +1,11 @@
+//go:build !windows
+
+package handler
+
+import "io"
+
+// enableVirtualTerminal luôn trả về true vì terminal trên các nền tảng khác Windows hiển thị mã
+// màu ANSI mà không cần thiết lập.
+func enableVirtualTerminal(io.Writer) bool {
+	return true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestColorSupported(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	var buf strings.Builder
+	if ColorSupported(&buf) {
+		t.Error("ColorSupported() với writer không phải terminal nên trả về false")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if ColorSupported(f) {
+		t.Error("ColorSupported() với file thường nên trả về false")
+	}
+
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if !ColorSupported(&buf) {
+		t.Error("CLICOLOR_FORCE=1 nên bật màu")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if ColorSupported(&buf) {
+		t.Error("NO_COLOR nên được ưu tiên hơn CLICOLOR_FORCE")
+	}
+}
+
+func TestConsoleHandler_ColorMode(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
+	var buf strings.Builder
+	h := NewConsoleWriterHandler(&buf, true)
+	h.SetColorMode(ColorAuto)
+	_ = h.Log(InfoLevel, "piped")
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("ColorAuto không nên ghi mã ANSI khi output không phải terminal, got %q", buf.String())
+	}
+
+	buf.Reset()
+	h.SetColorMode(ColorAlways)
+	_ = h.Log(InfoLevel, "forced")
+	if !strings.HasPrefix(buf.String(), "\033[32m") {
+		t.Errorf("ColorAlways nên luôn ghi mã ANSI, got %q", buf.String())
+	}
+
+	buf.Reset()
+	t.Setenv("CLICOLOR_FORCE", "1")
+	h.SetColorMode(ColorAuto)
+	_ = h.Log(InfoLevel, "env forced")
+	if !strings.HasPrefix(buf.String(), "\033[32m") {
+		t.Errorf("CLICOLOR_FORCE nên bật màu với ColorAuto, got %q", buf.String())
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"red", "\033[31m", false},
+		{"Bold Underline bright-cyan", "\033[1;4;96m", false},
+		{"208", "\033[38;5;208m", false},
+		{"bold #ff8800", "\033[1;38;2;255;136;0m", false},
+		{"256", "", true},
+		{"#ff88", "", true},
+		{"blink", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStyle(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStyle(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseStyle(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleHandler_ColorScheme(t *testing.T) {
+	var buf strings.Builder
+	h := NewConsoleWriterHandler(&buf, true)
+	h.SetOmitTimestamp(true)
+	h.SetColorScheme(ColorScheme{
+		Levels:  map[Level]string{WarningLevel: "\033[1;38;5;208m"},
+		Context: "\033[4m",
+	})
+
+	_ = h.Log(WarningLevel, "[API] slow request")
+	want := "\033[1;38;5;208m[WARNING] \033[0m\033[4m[API]\033[0m\033[1;38;5;208m slow request\n\033[0m"
+	if buf.String() != want {
+		t.Errorf("Output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	_ = h.Log(InfoLevel, "no context")
+	if buf.String() != "\033[32m[INFO] no context\n\033[0m" {
+		t.Errorf("Cấp độ không có trong scheme nên giữ màu mặc định, got %q", buf.String())
+	}
+}
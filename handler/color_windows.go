@@ -0,0 +1,36 @@
+//go:build windows
+
+package handler
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// enableVirtualTerminalProcessing là cờ ENABLE_VIRTUAL_TERMINAL_PROCESSING của console mode.
+const enableVirtualTerminalProcessing = 0x0004
+
+// procSetConsoleMode là hàm SetConsoleMode của kernel32.
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableVirtualTerminal bật chế độ virtual terminal cho console để hiển thị mã màu ANSI.
+//
+// Trả về false khi w là console không hỗ trợ chế độ này (trước Windows 10); writer không phải
+// console (file, pipe, bytes.Buffer) trả về true vì mã ANSI được chuyển nguyên vẹn.
+func enableVirtualTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return true
+	}
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return true
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnableVirtualTerminal_NotConsole(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "console.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if !enableVirtualTerminal(f) {
+		t.Error("enableVirtualTerminal() với file thường nên trả về true")
+	}
+
+	h := NewConsoleWriterHandler(f, true)
+	var buf strings.Builder
+	h.SetOutput(&buf, &buf)
+	_ = h.Log(InfoLevel, "colored")
+	if !strings.HasPrefix(buf.String(), "\033[32m") {
+		t.Errorf("Writer không phải console nên nhận mã ANSI khi màu được bật, got %q", buf.String())
+	}
+}
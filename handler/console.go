@@ -2,19 +2,38 @@ package handler
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"time"
+	"sort"
+	"sync"
+	"sync/atomic"
 )
 
 // ConsoleHandler triển khai một log handler ghi ra console (stdout/stderr).
 //
 // Tính năng:
-//   - Output có mã màu dựa trên cấp độ log
-//   - Tự động định tuyến errors ra stderr
+//   - Output có mã màu dựa trên cấp độ log, tự tắt khi output không phải terminal (xem SetColorMode)
+//   - Tự động định tuyến errors ra stderr (ngưỡng cấu hình qua SetStderrLevel)
+//   - Ghi ra io.Writer tùy chọn thay cho stdout/stderr (xem SetOutput)
 //   - Định dạng timestamp chuẩn
 //   - Tùy chọn zero-configuration
+//   - Nhóm các entry theo request/operation ID khi debug (xem SetGroupBy)
+//   - Bỏ timestamp khi nền tảng đã gắn timestamp cho mỗi dòng (xem SetOmitTimestamp)
 type ConsoleHandler struct {
-	colored bool // Có sử dụng mã màu ANSI hay không
+	colored     bool          // Có sử dụng mã màu ANSI hay không
+	autoColor   bool          // Chỉ dùng màu cho đích ghi hỗ trợ màu (ColorAuto)
+	plainOut    bool          // stdout không hiển thị được màu, được xác định khi đặt chế độ màu hoặc đích ghi
+	plainErr    bool          // stderr không hiển thị được màu, được xác định khi đặt chế độ màu hoặc đích ghi
+	scheme      ColorScheme   // Màu tùy chỉnh theo cấp độ và context
+	stdout      io.Writer     // Đích của entry dưới stderrLevel (nil = os.Stdout)
+	stderr      io.Writer     // Đích của entry từ stderrLevel trở lên (nil = os.Stderr)
+	stderrLevel Level         // Cấp độ thấp nhất được ghi ra stderr
+	omitTime    bool          // Bỏ timestamp ở đầu mỗi dòng
+	groupBy     []string      // Các field key dùng để nhóm entry
+	lastGroup   string        // Nhóm của entry được ghi gần nhất
+	written     atomic.Uint64 // Tổng số byte đã ghi
+	clock       clockRef      // Nguồn thời điểm của entry ghi qua Log
+	mu          sync.Mutex    // Mutex bảo vệ cấu hình output và trạng thái nhóm
 }
 
 // NewConsoleHandler tạo một console handler mới.
@@ -33,9 +52,100 @@ type ConsoleHandler struct {
 //	// Tạo một console handler plain-text
 //	handler := handler.NewConsoleHandler(false)
 func NewConsoleHandler(colored bool) *ConsoleHandler {
-	return &ConsoleHandler{
-		colored: colored,
+	console := &ConsoleHandler{
+		colored:     colored,
+		stderrLevel: ErrorLevel,
 	}
+	console.detectColor()
+	return console
+}
+
+// NewConsoleWriterHandler tạo một console handler ghi mọi entry vào w thay cho stdout/stderr
+// (VD: bytes.Buffer trong test, một socket hoặc os.Stderr).
+//
+// Tham số:
+//   - w: io.Writer - đích ghi của mọi entry
+//   - colored: bool - có sử dụng mã màu ANSI trong output hay không
+//
+// Trả về:
+//   - *ConsoleHandler: một console handler đã được cấu hình
+//
+// Ví dụ:
+//
+//	var buf bytes.Buffer
+//	handler := handler.NewConsoleWriterHandler(&buf, false)
+func NewConsoleWriterHandler(w io.Writer, colored bool) *ConsoleHandler {
+	console := NewConsoleHandler(colored)
+	console.SetOutput(w, w)
+	return console
+}
+
+// consoleWriters là registry các writer của console handler theo tên.
+var consoleWriters = struct {
+	sync.RWMutex
+	byName map[string]io.Writer
+}{byName: map[string]io.Writer{}}
+
+// RegisterConsoleWriter đăng ký một writer theo tên để console handler do Manager tạo ghi vào
+// qua cấu hình (console.output), thay thế writer đã đăng ký cùng tên. Hàm này thường được gọi
+// trong init hoặc trước khi tạo Manager.
+//
+// Tham số:
+//   - name: string - tên writer, không được rỗng hoặc trùng "stdout"/"stderr"
+//   - w: io.Writer - writer cần đăng ký
+//
+// Ví dụ:
+//
+//	conn, _ := net.Dial("unix", "/run/collector.sock")
+//	handler.RegisterConsoleWriter("collector", conn)
+//	// log.console.output: collector
+func RegisterConsoleWriter(name string, w io.Writer) {
+	if w == nil || name == "" || name == "stdout" || name == "stderr" {
+		panic("handler: RegisterConsoleWriter writer is nil or name is empty or reserved")
+	}
+
+	consoleWriters.Lock()
+	defer consoleWriters.Unlock()
+	consoleWriters.byName[name] = w
+}
+
+// LookupConsoleWriter trả về writer theo tên: "stdout", "stderr" hoặc một writer đăng ký qua
+// RegisterConsoleWriter.
+//
+// Tham số:
+//   - name: string - tên writer
+//
+// Trả về:
+//   - io.Writer: writer tương ứng
+//   - bool: false nếu không có writer nào với tên này
+func LookupConsoleWriter(name string) (io.Writer, bool) {
+	switch name {
+	case "stdout":
+		return os.Stdout, true
+	case "stderr":
+		return os.Stderr, true
+	}
+
+	consoleWriters.RLock()
+	defer consoleWriters.RUnlock()
+	w, ok := consoleWriters.byName[name]
+	return w, ok
+}
+
+// ConsoleWriterNames trả về tên các writer có thể dùng theo thứ tự bảng chữ cái, gồm "stdout"
+// và "stderr".
+//
+// Trả về:
+//   - []string: tên các writer
+func ConsoleWriterNames() []string {
+	consoleWriters.RLock()
+	defer consoleWriters.RUnlock()
+	names := []string{"stderr", "stdout"}
+	for name := range consoleWriters.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Log ghi một log entry ra console.
@@ -52,26 +162,299 @@ func NewConsoleHandler(colored bool) *ConsoleHandler {
 // Trả về:
 //   - error: một lỗi nếu ghi ra console thất bại
 func (a *ConsoleHandler) Log(level Level, message string, args ...interface{}) error {
-	// Định dạng với timestamp và cấp độ
-	timestamp := time.Now().Format("2006/01/02 15:04:05")
-	formattedMessage := fmt.Sprintf("%s [%s] %s\n", timestamp, level.String(), message)
+	entry := getEntry(a.clock.Now(), level, message)
+	defer putEntry(entry)
+
+	return a.LogEntry(entry)
+}
 
-	// Áp dụng mã màu ANSI nếu được bật
-	if a.colored {
-		formattedMessage = a.colorize(level, formattedMessage)
+// LogEntry ghi một log entry ra console với timestamp lấy từ entry.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: một lỗi nếu ghi ra console thất bại
+func (a *ConsoleHandler) LogEntry(entry *Entry) error {
+	level := entry.Level
+
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	colored := a.useColor(level)
+	if len(a.groupBy) > 0 {
+		*buf = append(*buf, a.group(entry, colored)...)
 	}
 
-	// Ghi ra stderr cho log Error và Fatal
-	if level >= ErrorLevel {
-		_, err := fmt.Fprint(os.Stderr, formattedMessage)
-		return err
+	// Định dạng với timestamp và cấp độ, áp dụng mã màu ANSI nếu được bật
+	switch {
+	case colored:
+		*buf = appendStyledLine(*buf, entry, a.levelStyle(level), a.scheme.Context, a.omitTime)
+	case a.omitTime:
+		*buf = appendUntimedLine(*buf, entry)
+	default:
+		*buf = appendLine(*buf, entry)
 	}
 
-	// Ghi ra stdout cho các cấp độ khác
-	_, err := fmt.Fprint(os.Stdout, formattedMessage)
+	n, err := a.writer(level).Write(*buf)
+	a.written.Add(uint64(n))
 	return err
 }
 
+// writer trả về đích ghi của entry theo cấp độ: stderr cho entry từ stderrLevel trở lên, stdout
+// cho các cấp độ khác.
+//
+// Method này phải được gọi khi đang giữ lock của handler.
+func (a *ConsoleHandler) writer(level Level) io.Writer {
+	if level >= a.stderrLevel {
+		if a.stderr == nil {
+			return os.Stderr
+		}
+		return a.stderr
+	}
+	if a.stdout == nil {
+		return os.Stdout
+	}
+	return a.stdout
+}
+
+// SetStderrLevel đặt cấp độ thấp nhất được ghi ra stderr; các cấp độ thấp hơn được ghi ra
+// stdout. Mặc định là ErrorLevel. Đặt WarningLevel để tách Debug/Info (stdout) khỏi
+// Warning/Error/Fatal (stderr) theo quy ước 12-factor và container. Method này là thread-safe.
+//
+// Tham số:
+//   - level: Level - cấp độ thấp nhất được ghi ra stderr
+//
+// Ví dụ:
+//
+//	console := handler.NewConsoleHandler(false)
+//	console.SetStderrLevel(handler.WarningLevel)
+func (a *ConsoleHandler) SetStderrLevel(level Level) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stderrLevel = level
+}
+
+// SetOutput thay thế đích ghi của handler. Entry dưới ngưỡng của SetStderrLevel được ghi vào
+// stdout, các entry còn lại vào stderr. Truyền cùng một writer cho cả hai để ghi mọi entry vào
+// một nơi. Method này là thread-safe.
+//
+// Tham số:
+//   - stdout: io.Writer - đích của entry mức thấp, nil để dùng os.Stdout
+//   - stderr: io.Writer - đích của entry mức cao, nil để dùng os.Stderr
+//
+// Ví dụ:
+//
+//	var buf bytes.Buffer
+//	console := handler.NewConsoleHandler(false)
+//	console.SetOutput(&buf, &buf)
+func (a *ConsoleHandler) SetOutput(stdout, stderr io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stdout = stdout
+	a.stderr = stderr
+	a.detectColor()
+}
+
+// SetColorMode đặt chế độ màu của handler. Với ColorAuto, màu chỉ được dùng cho stdout/stderr
+// mà ColorSupported trả về true, được xác định khi gọi SetColorMode hoặc SetOutput; ColorAlways
+// là lựa chọn ghi đè tường minh. Method này là thread-safe.
+//
+// Tham số:
+//   - mode: ColorMode - chế độ màu
+//
+// Ví dụ:
+//
+//	console := handler.NewConsoleHandler(true)
+//	console.SetColorMode(handler.ColorAuto) // Không ghi mã ANSI khi output bị pipe
+func (a *ConsoleHandler) SetColorMode(mode ColorMode) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.colored = mode != ColorNever
+	a.autoColor = mode == ColorAuto
+	a.detectColor()
+}
+
+// SetColorScheme thay thế màu mặc định theo cấp độ và màu của đoạn "[Context]" ở đầu thông
+// điệp. Method này là thread-safe.
+//
+// Tham số:
+//   - scheme: ColorScheme - màu tùy chỉnh; cấp độ không có trong scheme.Levels giữ màu mặc định
+//
+// Ví dụ:
+//
+//	warning, _ := handler.ParseStyle("bold 208")
+//	context, _ := handler.ParseStyle("underline #8a8a8a")
+//	console.SetColorScheme(handler.ColorScheme{
+//		Levels:  map[handler.Level]string{handler.WarningLevel: warning},
+//		Context: context,
+//	})
+func (a *ConsoleHandler) SetColorScheme(scheme ColorScheme) {
+	levels := make(map[Level]string, len(scheme.Levels))
+	for level, style := range scheme.Levels {
+		levels[level] = style
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.scheme = ColorScheme{Levels: levels, Context: scheme.Context}
+}
+
+// levelStyle trả về mã màu của cấp độ theo scheme, hoặc màu mặc định.
+//
+// Method này phải được gọi khi đang giữ lock của handler.
+func (a *ConsoleHandler) levelStyle(level Level) string {
+	if style := a.scheme.Levels[level]; style != "" {
+		return style
+	}
+	return colorCode(level)
+}
+
+// detectColor xác định stdout và stderr có hiển thị được màu hay không. Trên Windows, chế độ
+// virtual terminal của console được bật để hiển thị mã ANSI; console không hỗ trợ (trước Windows
+// 10) được ghi không màu thay vì hiện mã escape.
+//
+// Method này phải được gọi khi đang giữ lock của handler.
+func (a *ConsoleHandler) detectColor() {
+	if !a.colored {
+		return
+	}
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if a.stdout != nil {
+		stdout = a.stdout
+	}
+	if a.stderr != nil {
+		stderr = a.stderr
+	}
+	if a.autoColor {
+		a.plainOut = !ColorSupported(stdout)
+		a.plainErr = !ColorSupported(stderr)
+	} else {
+		a.plainOut = !enableVirtualTerminal(stdout)
+		a.plainErr = !enableVirtualTerminal(stderr)
+	}
+}
+
+// useColor kiểm tra entry ở cấp độ level có được ghi với mã màu hay không.
+//
+// Method này phải được gọi khi đang giữ lock của handler.
+func (a *ConsoleHandler) useColor(level Level) bool {
+	if !a.colored {
+		return false
+	}
+	if level >= a.stderrLevel {
+		return !a.plainErr
+	}
+	return !a.plainOut
+}
+
+// BytesWritten trả về tổng số byte đã ghi ra stdout và stderr. Method này là thread-safe.
+//
+// Trả về:
+//   - uint64: số byte đã ghi
+func (a *ConsoleHandler) BytesWritten() uint64 {
+	return a.written.Load()
+}
+
+// SetGroupBy bật chế độ nhóm các entry có cùng giá trị của một field (VD: request_id).
+//
+// Khi được bật, mỗi khi nhóm thay đổi so với entry trước đó, một dòng phân cách chứa
+// key=value của nhóm được ghi ra, và các entry thuộc nhóm được thụt lề. Điều này giúp
+// theo dõi các request chạy đồng thời dễ dàng hơn khi debug trên máy local. Entry không
+// chứa field nào trong keys được ghi bình thường. Method này là thread-safe.
+//
+// Tham số:
+//   - keys: ...string - các field key theo thứ tự ưu tiên; không truyền key nào để tắt chế độ nhóm
+//
+// Ví dụ:
+//
+//	console := handler.NewConsoleHandler(true)
+//	console.SetGroupBy("request_id", "operation_id")
+func (a *ConsoleHandler) SetGroupBy(keys ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.groupBy = append([]string(nil), keys...)
+	a.lastGroup = ""
+}
+
+// SetOmitTimestamp bật/tắt việc bỏ timestamp ở đầu mỗi dòng.
+//
+// Khi được bật, mỗi dòng có dạng "[LEVEL] message". Tùy chọn này phù hợp khi chạy dưới
+// systemd/journald hoặc trên nền tảng container, nơi mỗi dòng output đã được gắn timestamp,
+// giúp tránh trùng lặp và rút ngắn dòng log. Method này là thread-safe.
+//
+// Tham số:
+//   - omit: bool - true để bỏ timestamp, false để ghi timestamp (mặc định)
+//
+// Ví dụ:
+//
+//	console := handler.NewConsoleHandler(false)
+//	console.SetOmitTimestamp(true)
+func (a *ConsoleHandler) SetOmitTimestamp(omit bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.omitTime = omit
+}
+
+// SetClock thay thế nguồn thời điểm của các entry ghi qua Log (entry ghi qua LogEntry dùng
+// Entry.Time), để test cố định timestamp của output. Method này là thread-safe.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng SystemClock
+func (a *ConsoleHandler) SetClock(clock Clock) {
+	a.clock.set(clock)
+}
+
+// group trả về tiền tố nhóm cho entry: dòng phân cách khi nhóm thay đổi cùng thụt lề.
+//
+// Method này phải được gọi khi đang giữ lock của handler.
+//
+// Tham số:
+//   - entry: *Entry - entry cần xác định nhóm
+//   - colored: bool - có dùng mã màu cho dòng phân cách hay không
+//
+// Trả về:
+//   - string: tiền tố cần ghi trước entry, hoặc chuỗi rỗng nếu entry không thuộc nhóm nào
+func (a *ConsoleHandler) group(entry *Entry, colored bool) string {
+	var current string
+	for _, key := range a.groupBy {
+		for _, f := range entry.Fields {
+			if f.Key == key {
+				current = f.String()
+				break
+			}
+		}
+		if current != "" {
+			break
+		}
+	}
+
+	if current == "" {
+		a.lastGroup = ""
+		return ""
+	}
+
+	prefix := "  "
+	if current != a.lastGroup {
+		a.lastGroup = current
+		separator := fmt.Sprintf("── %s ──\n", current)
+		if colored {
+			separator = fmt.Sprintf("\033[1m%s\033[0m", separator)
+		}
+		prefix = separator + prefix
+	}
+	return prefix
+}
+
 // Close giải phóng tài nguyên được sử dụng bởi console handler.
 //
 // Đối với console handler, đây là thao tác rỗng vì I/O console không yêu cầu
@@ -93,24 +476,27 @@ func (a *ConsoleHandler) Close() error {
 // Trả về:
 //   - string: thông điệp với mã màu ANSI đã áp dụng
 func (a *ConsoleHandler) colorize(level Level, message string) string {
-	// Chọn mã màu ANSI dựa trên cấp độ log
-	var colorCode string
+	// Áp dụng màu và đảm bảo reset ở cuối
+	return colorCode(level) + message + colorReset
+}
+
+// colorReset là mã ANSI khôi phục màu mặc định.
+const colorReset = "\033[0m"
 
+// colorCode trả về mã màu ANSI của cấp độ log.
+func colorCode(level Level) string {
 	switch level {
 	case DebugLevel:
-		colorCode = "\033[36m" // Cyan cho debug
+		return "\033[36m" // Cyan cho debug
 	case InfoLevel:
-		colorCode = "\033[32m" // Green cho info
+		return "\033[32m" // Green cho info
 	case WarningLevel:
-		colorCode = "\033[33m" // Yellow cho warning
+		return "\033[33m" // Yellow cho warning
 	case ErrorLevel:
-		colorCode = "\033[31m" // Red cho error
+		return "\033[31m" // Red cho error
 	case FatalLevel:
-		colorCode = "\033[35m" // Magenta cho fatal
+		return "\033[35m" // Magenta cho fatal
 	default:
-		colorCode = "\033[0m" // Mặc định (reset)
+		return colorReset // Mặc định (reset)
 	}
-
-	// Áp dụng màu và đảm bảo reset ở cuối
-	return fmt.Sprintf("%s%s\033[0m", colorCode, message)
 }
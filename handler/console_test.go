@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Tạo một buffer để bắt đầu ra của console
@@ -211,3 +212,110 @@ func TestConsoleHandler_Colorize(t *testing.T) {
 		})
 	}
 }
+
+func TestConsoleHandler_GroupBy(t *testing.T) {
+	capture, err := newCaptureOutput()
+	if err != nil {
+		t.Fatalf("Không thể tạo capture: %v", err)
+	}
+
+	h := NewConsoleHandler(false)
+	h.SetGroupBy("request_id")
+
+	now := time.Now()
+	reqA := []Field{{Key: "request_id", Value: "a"}}
+	reqB := []Field{{Key: "request_id", Value: "b"}}
+	_ = h.LogEntry(&Entry{Time: now, Level: InfoLevel, Message: "a started", Fields: reqA})
+	_ = h.LogEntry(&Entry{Time: now, Level: InfoLevel, Message: "a step", Fields: reqA})
+	_ = h.LogEntry(&Entry{Time: now, Level: InfoLevel, Message: "b started", Fields: reqB})
+	_ = h.LogEntry(&Entry{Time: now, Level: InfoLevel, Message: "no request"})
+	_ = h.LogEntry(&Entry{Time: now, Level: InfoLevel, Message: "b done", Fields: reqB})
+
+	output, err := capture.read()
+	if err != nil {
+		t.Fatalf("Không thể đọc đầu ra: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("Nên có 5 entry và 3 dòng phân cách, got %d dòng:\n%s", len(lines), output)
+	}
+	if lines[0] != "── request_id=a ──" || lines[3] != "── request_id=b ──" || lines[6] != "── request_id=b ──" {
+		t.Errorf("Dòng phân cách nên được ghi mỗi khi nhóm thay đổi, got:\n%s", output)
+	}
+	if !strings.HasPrefix(lines[1], "  ") || !strings.HasPrefix(lines[2], "  ") {
+		t.Errorf("Entry thuộc nhóm nên được thụt lề, got:\n%s", output)
+	}
+	if strings.HasPrefix(lines[5], " ") || !strings.Contains(lines[5], "no request") {
+		t.Errorf("Entry không thuộc nhóm nào nên được ghi bình thường, got %q", lines[5])
+	}
+}
+
+func TestConsoleHandler_OmitTimestamp(t *testing.T) {
+	capture, err := newCaptureOutput()
+	if err != nil {
+		t.Fatalf("Không thể tạo capture: %v", err)
+	}
+
+	h := NewConsoleHandler(false)
+	h.SetOmitTimestamp(true)
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	_ = h.LogEntry(&Entry{Time: at, Level: InfoLevel, Message: "service started"})
+	h.SetOmitTimestamp(false)
+	_ = h.LogEntry(&Entry{Time: at, Level: InfoLevel, Message: "service ready"})
+
+	output, err := capture.read()
+	if err != nil {
+		t.Fatalf("Không thể đọc đầu ra: %v", err)
+	}
+	want := "[INFO] service started\n2024/03/01 12:00:00 [INFO] service ready\n"
+	if output != want {
+		t.Errorf("Output = %q, want %q", output, want)
+	}
+}
+
+func TestConsoleHandler_StderrLevel(t *testing.T) {
+	var stdout, stderr strings.Builder
+	h := NewConsoleHandler(false)
+	h.SetOmitTimestamp(true)
+	h.SetOutput(&stdout, &stderr)
+
+	_ = h.Log(InfoLevel, "info")
+	_ = h.Log(WarningLevel, "warning")
+	_ = h.Log(ErrorLevel, "error")
+	if stdout.String() != "[INFO] info\n[WARNING] warning\n" || stderr.String() != "[ERROR] error\n" {
+		t.Errorf("Mặc định chỉ Error trở lên ghi ra stderr, got stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	h.SetStderrLevel(WarningLevel)
+	_ = h.Log(DebugLevel, "debug")
+	_ = h.Log(WarningLevel, "warning")
+	_ = h.Log(FatalLevel, "fatal")
+	if stdout.String() != "[DEBUG] debug\n" || stderr.String() != "[WARNING] warning\n[FATAL] fatal\n" {
+		t.Errorf("Warning trở lên nên ghi ra stderr, got stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+}
+
+func TestConsoleHandler_Writer(t *testing.T) {
+	var buf strings.Builder
+	h := NewConsoleWriterHandler(&buf, false)
+	h.SetOmitTimestamp(true)
+	_ = h.Log(InfoLevel, "info")
+	_ = h.Log(ErrorLevel, "error")
+	if buf.String() != "[INFO] info\n[ERROR] error\n" {
+		t.Errorf("Mọi entry nên được ghi vào writer, got %q", buf.String())
+	}
+
+	RegisterConsoleWriter("console-test", &buf)
+	if w, ok := LookupConsoleWriter("console-test"); !ok || w != &buf {
+		t.Error("LookupConsoleWriter() nên trả về writer đã đăng ký")
+	}
+	if w, ok := LookupConsoleWriter("stderr"); !ok || w != os.Stderr {
+		t.Error("LookupConsoleWriter(\"stderr\") nên trả về os.Stderr")
+	}
+	if _, ok := LookupConsoleWriter("missing"); ok {
+		t.Error("LookupConsoleWriter() với tên chưa đăng ký nên trả về false")
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeliveryMode xác định cam kết giao nhận log entry của một handler.
+type DeliveryMode int
+
+const (
+	// BestEffort ghi bất đồng bộ và bỏ qua entry khi hàng đợi đầy; bên gọi không bao giờ bị chặn.
+	BestEffort DeliveryMode = iota
+
+	// AtLeastOnce ghi bất đồng bộ và lưu entry ghi thất bại xuống đĩa để gửi lại sau;
+	// entry có thể bị ghi lặp nhưng không bị mất.
+	AtLeastOnce
+
+	// Guaranteed ghi đồng bộ, thử lại khi thất bại và chỉ trả về khi handler xác nhận (ack)
+	// entry đã được lưu; bên gọi nhận lỗi nếu không có xác nhận.
+	Guaranteed
+)
+
+// String trả về tên của chế độ giao nhận dùng trong cấu hình.
+//
+// Trả về:
+//   - string: "best_effort", "at_least_once", "guaranteed" hoặc "unknown"
+func (m DeliveryMode) String() string {
+	switch m {
+	case BestEffort:
+		return "best_effort"
+	case AtLeastOnce:
+		return "at_least_once"
+	case Guaranteed:
+		return "guaranteed"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDeliveryMode chuyển tên chế độ giao nhận (không phân biệt hoa thường) thành DeliveryMode.
+//
+// Tham số:
+//   - s: string - tên chế độ (best_effort, at_least_once, guaranteed)
+//
+// Trả về:
+//   - DeliveryMode: chế độ tương ứng
+//   - error: lỗi nếu tên không hợp lệ
+func ParseDeliveryMode(s string) (DeliveryMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "best_effort":
+		return BestEffort, nil
+	case "at_least_once":
+		return AtLeastOnce, nil
+	case "guaranteed":
+		return Guaranteed, nil
+	default:
+		return BestEffort, fmt.Errorf("invalid delivery mode: %q", s)
+	}
+}
+
+// Syncer là interface tùy chọn cho các handler có thể xác nhận entry đã được lưu bền vững
+// (VD: fsync file). Chế độ Guaranteed dùng Sync làm xác nhận (ack) sau mỗi lần ghi.
+type Syncer interface {
+	// Sync đảm bảo các entry đã ghi được lưu bền vững.
+	//
+	// Trả về:
+	//   - error: lỗi nếu không thể xác nhận
+	Sync() error
+}
+
+// DeliveryOptions cấu hình các wrapper được tạo bởi NewDeliveryHandler.
+type DeliveryOptions struct {
+	Workers    int           // Số worker cho BestEffort và AtLeastOnce (0 = DefaultAsyncWorkers)
+	QueueSize  int           // Kích thước hàng đợi cho BestEffort và AtLeastOnce (0 = DefaultAsyncQueueSize)
+	SpillPath  string        // File lưu entry ghi thất bại, bắt buộc với AtLeastOnce
+	Retries    int           // Số lần thử lại cho Guaranteed
+	RetryDelay time.Duration // Thời gian chờ giữa các lần thử lại cho Guaranteed
+}
+
+// NewDeliveryHandler bọc h với các wrapper phù hợp với chế độ giao nhận đã chọn.
+//
+// Các wrapper được tạo:
+//   - BestEffort: AsyncHandler bỏ qua entry khi hàng đợi đầy
+//   - AtLeastOnce: AsyncHandler bọc SpillHandler lưu entry thất bại vào opts.SpillPath
+//   - Guaranteed: GuaranteedHandler ghi đồng bộ, thử lại và xác nhận qua Syncer
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - mode: DeliveryMode - chế độ giao nhận
+//   - opts: DeliveryOptions - tùy chọn của các wrapper
+//
+// Trả về:
+//   - Handler: handler đã được bọc
+//   - error: lỗi nếu chế độ không hợp lệ hoặc thiếu tùy chọn bắt buộc
+//
+// Ví dụ:
+//
+//	remote, err := handler.NewDeliveryHandler(lokiHandler, handler.AtLeastOnce, handler.DeliveryOptions{
+//	    Workers:   8,
+//	    QueueSize: 10000,
+//	    SpillPath: "storage/logs/loki.spill",
+//	})
+func NewDeliveryHandler(h Handler, mode DeliveryMode, opts DeliveryOptions) (Handler, error) {
+	switch mode {
+	case BestEffort:
+		a := NewAsyncHandler(h, opts.Workers, opts.QueueSize)
+		a.drop = true
+		return a, nil
+	case AtLeastOnce:
+		if opts.SpillPath == "" {
+			return nil, errors.New("spill path is required for at-least-once delivery")
+		}
+		return NewAsyncHandler(NewSpillHandler(h, opts.SpillPath), opts.Workers, opts.QueueSize), nil
+	case Guaranteed:
+		return NewGuaranteedHandler(h, opts.Retries, opts.RetryDelay), nil
+	default:
+		return nil, fmt.Errorf("invalid delivery mode: %d", mode)
+	}
+}
+
+// GuaranteedHandler ghi đồng bộ đến handler được bọc, thử lại khi thất bại và chỉ trả về
+// thành công khi handler xác nhận entry đã được lưu.
+//
+// Handler triển khai Syncer được gọi Sync sau mỗi lần ghi làm xác nhận; với các handler
+// khác, việc Log trả về nil được coi là xác nhận.
+type GuaranteedHandler struct {
+	handler Handler       // Handler được bọc
+	retries int           // Số lần thử lại sau lần ghi đầu tiên
+	delay   time.Duration // Thời gian chờ giữa các lần thử lại
+	mu      sync.Mutex    // Đảm bảo mỗi entry được xác nhận trước khi ghi entry kế tiếp
+}
+
+// NewGuaranteedHandler tạo một GuaranteedHandler bọc h.
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - retries: int - số lần thử lại sau lần ghi đầu tiên thất bại
+//   - delay: time.Duration - thời gian chờ giữa các lần thử lại
+//
+// Trả về:
+//   - *GuaranteedHandler: handler đã được cấu hình
+func NewGuaranteedHandler(h Handler, retries int, delay time.Duration) *GuaranteedHandler {
+	if retries < 0 {
+		retries = 0
+	}
+	return &GuaranteedHandler{handler: h, retries: retries, delay: delay}
+}
+
+// Log ghi một log entry với timestamp hiện tại và chờ xác nhận.
+//
+// Tham số:
+//   - level: Level - cấp độ nghiêm trọng của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - các tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi nếu entry không được xác nhận sau tất cả các lần thử
+func (g *GuaranteedHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return g.LogEntry(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// LogEntry ghi một log entry và chờ xác nhận, thử lại nếu thất bại.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: lỗi nếu entry không được xác nhận sau tất cả các lần thử
+func (g *GuaranteedHandler) LogEntry(entry *Entry) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt <= g.retries; attempt++ {
+		if attempt > 0 && g.delay > 0 {
+			time.Sleep(g.delay)
+		}
+		if err = Dispatch(g.handler, entry); err != nil {
+			continue
+		}
+		if syncer, ok := g.handler.(Syncer); ok {
+			if err = syncer.Sync(); err != nil {
+				continue
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("delivery not acknowledged after %d attempts: %w", g.retries+1, err)
+}
+
+// Close đóng handler được bọc.
+//
+// Trả về:
+//   - error: lỗi từ Close của handler được bọc
+func (g *GuaranteedHandler) Close() error {
+	return g.handler.Close()
+}
+
+// Unwrap trả về handler được bọc.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (g *GuaranteedHandler) Unwrap() Handler {
+	return g.handler
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseDeliveryMode(t *testing.T) {
+	for _, mode := range []DeliveryMode{BestEffort, AtLeastOnce, Guaranteed} {
+		got, err := ParseDeliveryMode(mode.String())
+		if err != nil || got != mode {
+			t.Errorf("ParseDeliveryMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := ParseDeliveryMode("exactly_once"); err == nil {
+		t.Error("ParseDeliveryMode() nên trả về lỗi với tên không hợp lệ")
+	}
+}
+
+func TestNewDeliveryHandler_BestEffortDrops(t *testing.T) {
+	sink := &slowRecorder{release: make(chan struct{})}
+	h, err := NewDeliveryHandler(sink, BestEffort, DeliveryOptions{Workers: 1, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewDeliveryHandler() error = %v", err)
+	}
+	a := h.(*AsyncHandler)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			a.Log(InfoLevel, "burst")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("BestEffort không nên chặn bên gọi khi hàng đợi đầy")
+	}
+
+	close(sink.release)
+	a.Close()
+	if a.Dropped() == 0 || uint64(len(sink.entries))+a.Dropped() != 10 {
+		t.Errorf("Entry vượt quá hàng đợi nên bị bỏ qua và được đếm, got %d ghi, %d bỏ qua", len(sink.entries), a.Dropped())
+	}
+}
+
+func TestNewDeliveryHandler_AtLeastOnce(t *testing.T) {
+	if _, err := NewDeliveryHandler(&slowRecorder{}, AtLeastOnce, DeliveryOptions{}); err == nil {
+		t.Fatal("AtLeastOnce nên yêu cầu SpillPath")
+	}
+
+	sink := &flakyRecorder{failing: true}
+	h, err := NewDeliveryHandler(sink, AtLeastOnce, DeliveryOptions{SpillPath: filepath.Join(t.TempDir(), "at.spill")})
+	if err != nil {
+		t.Fatalf("NewDeliveryHandler() error = %v", err)
+	}
+	h.Log(InfoLevel, "kept")
+	h.(*AsyncHandler).Stop()
+
+	sink.failing = false
+	if err := h.(*AsyncHandler).Unwrap().Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(sink.entries) != 1 || sink.entries[0].Message != "kept" {
+		t.Errorf("AtLeastOnce không nên làm mất entry ghi thất bại, got %v", sink.entries)
+	}
+}
+
+func TestGuaranteedHandler(t *testing.T) {
+	sink := &flakyRecorder{}
+	g := NewGuaranteedHandler(sink, 2, 0)
+
+	if err := g.Log(ErrorLevel, "payment %d", 42); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if sink.syncs != 1 || sink.entries[0].Message != "payment 42" {
+		t.Errorf("Guaranteed nên ghi và chờ xác nhận qua Sync, got %d sync, %v", sink.syncs, sink.entries)
+	}
+
+	sink.failing = true
+	if err := g.Log(ErrorLevel, "lost"); err == nil {
+		t.Error("Guaranteed nên trả về lỗi khi không có xác nhận sau tất cả các lần thử")
+	}
+}
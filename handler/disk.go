@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultDiskCheckInterval là khoảng thời gian mặc định giữa hai lần kiểm tra dung lượng đĩa.
+const DefaultDiskCheckInterval = 10 * time.Second
+
+// diskScanInterval là thời gian tối đa giữa hai lần đo lại kích thước các file sao lưu cho
+// MaxDirSize; kích thước cũng được đo lại sau mỗi lần xoay vòng.
+const diskScanInterval = time.Minute
+
+// DiskGuard cấu hình bảo vệ dung lượng đĩa của FileHandler. Khi dung lượng trống của ổ đĩa
+// xuống dưới MinFree hoặc file log cùng các file sao lưu của nó vượt MaxDirSize, handler chuyển
+// sang chế độ suy giảm: entry dưới WarningLevel bị bỏ thay vì làm đầy đĩa, cho đến khi dung
+// lượng được giải phóng.
+type DiskGuard struct {
+	MinFree    int64           // Dung lượng trống tối thiểu (byte) của ổ đĩa chứa file log, 0 để bỏ qua
+	MaxDirSize int64           // Tổng kích thước tối đa (byte) của file log và các file sao lưu của nó, 0 để bỏ qua
+	Interval   time.Duration   // Khoảng thời gian giữa hai lần kiểm tra, 0 = DefaultDiskCheckInterval
+	Notify     func(DiskEvent) // Hàm nhận cảnh báo, nil để ghi cảnh báo ra stderr
+}
+
+// DiskEvent mô tả việc FileHandler chuyển sang hoặc thoát khỏi chế độ suy giảm.
+type DiskEvent struct {
+	Path     string // Đường dẫn file log
+	Degraded bool   // true khi chuyển sang chế độ suy giảm, false khi phục hồi
+	Free     int64  // Dung lượng trống của ổ đĩa (byte), -1 nếu không kiểm tra
+	DirSize  int64  // Tổng kích thước file log và các file sao lưu (byte), -1 nếu không kiểm tra
+	Dropped  int64  // Số entry đã bị bỏ trong chế độ suy giảm, chỉ có khi phục hồi
+}
+
+// String trả về mô tả sự kiện.
+//
+// Trả về:
+//   - string: mô tả dạng "file log app.log chuyển sang chế độ suy giảm ..."
+func (e DiskEvent) String() string {
+	if e.Degraded {
+		return fmt.Sprintf("file log %s chuyển sang chế độ suy giảm, bỏ entry dưới %s (trống %d byte, thư mục %d byte)",
+			e.Path, WarningLevel, e.Free, e.DirSize)
+	}
+	return fmt.Sprintf("file log %s đã phục hồi sau khi bỏ %d entry (trống %d byte, thư mục %d byte)",
+		e.Path, e.Dropped, e.Free, e.DirSize)
+}
+
+// diskGuard theo dõi dung lượng đĩa theo định kỳ và trạng thái suy giảm của FileHandler.
+type diskGuard struct {
+	guard    DiskGuard
+	checked  time.Time // Thời điểm kiểm tra gần nhất
+	degraded bool      // Đang ở chế độ suy giảm
+	dropped  int64     // Số entry đã bị bỏ trong đợt suy giảm hiện tại
+	backups  int64     // Tổng kích thước các file sao lưu lần đo gần nhất
+	scanned  time.Time // Thời điểm đo backups gần nhất (zero = cần đo lại, VD: sau khi xoay vòng)
+	now      func() time.Time
+	free     func(dir string) (int64, error)
+}
+
+// check kiểm tra dung lượng đĩa nếu đã đến lúc và trả về sự kiện khi trạng thái suy giảm thay đổi.
+// current là kích thước hiện tại của file log.
+func (g *diskGuard) check(path string, current int64) *DiskEvent {
+	now := g.now()
+	interval := g.guard.Interval
+	if interval <= 0 {
+		interval = DefaultDiskCheckInterval
+	}
+	if !g.checked.IsZero() && now.Sub(g.checked) < interval {
+		return nil
+	}
+	g.checked = now
+
+	dir := filepath.Dir(path)
+	free, size, low := int64(-1), int64(-1), false
+	if g.guard.MinFree > 0 {
+		// Không xác định được dung lượng trống (VD: nền tảng không hỗ trợ) thì bỏ qua giới hạn
+		if f, err := g.free(dir); err == nil {
+			free, low = f, f < g.guard.MinFree
+		}
+	}
+	if g.guard.MaxDirSize > 0 {
+		if g.scanned.IsZero() || now.Sub(g.scanned) >= diskScanInterval {
+			g.backups, g.scanned = backupSize(path), now
+		}
+		size = current + g.backups
+		low = low || size > g.guard.MaxDirSize
+	}
+	if low == g.degraded {
+		return nil
+	}
+
+	g.degraded = low
+	event := &DiskEvent{Path: path, Degraded: low, Free: free, DirSize: size}
+	if !low {
+		event.Dropped, g.dropped = g.dropped, 0
+	}
+	return event
+}
+
+// allow kiểm tra entry ở level có được ghi hay không, và đếm entry bị bỏ khi đang suy giảm.
+func (g *diskGuard) allow(level Level) bool {
+	if g.degraded && level < WarningLevel {
+		g.dropped++
+		return false
+	}
+	return true
+}
+
+// rescan đánh dấu kích thước các file sao lưu cần được đo lại ở lần kiểm tra tiếp theo.
+func (g *diskGuard) rescan() {
+	g.scanned = time.Time{}
+}
+
+// backupSize trả về tổng kích thước các file sao lưu của path, không duyệt thư mục con.
+func backupSize(path string) int64 {
+	var size int64
+	for _, backup := range backupPaths(path) {
+		if info, err := os.Lstat(backup); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// SetDiskGuard bật hoặc thay đổi bảo vệ dung lượng đĩa của file log.
+//
+// Dung lượng được kiểm tra khi ghi entry, tối đa một lần mỗi Interval. MaxDirSize được so với
+// tổng kích thước file log và các file sao lưu của nó; file khác trong thư mục và thư mục con
+// không được tính. Kích thước các file sao lưu được lưu lại và chỉ đo lại sau khi xoay vòng hoặc
+// mỗi phút. Khi chuyển sang hoặc thoát khỏi chế độ suy giảm, một cảnh báo được gửi đến Notify
+// (hoặc ghi ra stderr); Notify được gọi sau khi handler nhả lock nên có thể ghi log. Method này
+// là thread-safe.
+//
+// Tham số:
+//   - guard: DiskGuard - cấu hình bảo vệ; MinFree và MaxDirSize <= 0 để tắt
+//
+// Ví dụ:
+//
+//	// Chỉ ghi Warning trở lên khi ổ đĩa còn dưới 1GB hoặc file log và các bản sao lưu vượt 20GB
+//	fileHandler.SetDiskGuard(handler.DiskGuard{MinFree: 1 << 30, MaxDirSize: 20 << 30})
+func (a *FileHandler) SetDiskGuard(guard DiskGuard) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if guard.MinFree <= 0 && guard.MaxDirSize <= 0 {
+		a.disk = nil
+		return
+	}
+	a.disk = &diskGuard{guard: guard, now: time.Now, free: diskFree}
+}
+
+// notifyDisk gửi cảnh báo dung lượng đĩa đến Notify hoặc ra stderr.
+func (a *FileHandler) notifyDisk(notify func(DiskEvent), event DiskEvent) {
+	if notify != nil {
+		notify(event)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Cảnh báo: %s\n", event)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package handler
+
+import "errors"
+
+// diskFree không được hỗ trợ trên nền tảng này nên giới hạn MinFree của DiskGuard bị bỏ qua.
+func diskFree(dir string) (int64, error) {
+	return 0, errors.New("không hỗ trợ kiểm tra dung lượng trống trên nền tảng này")
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileHandler_SetDiskGuard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	var events []DiskEvent
+	h.SetDiskGuard(DiskGuard{MinFree: 1 << 30, Interval: time.Minute, Notify: func(e DiskEvent) {
+		events = append(events, e)
+		// Notify được gọi sau khi nhả lock nên có thể ghi log qua chính handler
+		_ = h.Log(WarningLevel, "%s", e)
+	}})
+	now := time.Now()
+	free := int64(100 << 20)
+	h.disk.now = func() time.Time { return now }
+	h.disk.free = func(string) (int64, error) { return free, nil }
+
+	_ = h.Log(InfoLevel, "dropped info")
+	_ = h.Log(ErrorLevel, "kept error")
+	if len(events) != 1 || !events[0].Degraded || events[0].Free != free {
+		t.Fatalf("Handler nên chuyển sang chế độ suy giảm khi thiếu dung lượng, got %+v", events)
+	}
+
+	// Dung lượng được giải phóng nhưng chưa đến lần kiểm tra tiếp theo
+	free = 2 << 30
+	_ = h.Log(DebugLevel, "dropped debug")
+	now = now.Add(time.Minute)
+	_ = h.Log(InfoLevel, "recovered info")
+	if len(events) != 2 || events[1].Degraded || events[1].Dropped != 2 {
+		t.Fatalf("Handler nên phục hồi và báo số entry đã bỏ, got %+v", events)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{"kept error", "recovered info", "chế độ suy giảm", "đã phục hồi"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("File log nên chứa %q, got %q", want, content)
+		}
+	}
+	if strings.Contains(content, "dropped") {
+		t.Errorf("Entry dưới WarningLevel nên bị bỏ khi suy giảm, got %q", content)
+	}
+}
+
+func TestFileHandler_SetDiskGuard_MaxDirSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(filepath.Join(dir, "app.log.20240101000000"), make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	var events []DiskEvent
+	h.SetDiskGuard(DiskGuard{MaxDirSize: 1024, Notify: func(e DiskEvent) { events = append(events, e) }})
+	_ = h.Log(InfoLevel, "dropped info")
+	if len(events) != 1 || events[0].DirSize < 4096 || events[0].Free != -1 {
+		t.Errorf("Handler nên suy giảm khi thư mục log vượt MaxDirSize, got %+v", events)
+	}
+
+	h.SetDiskGuard(DiskGuard{})
+	if _, err := os.Stat(path); err != nil || h.disk != nil {
+		t.Fatal("SetDiskGuard với giới hạn 0 nên tắt bảo vệ dung lượng")
+	}
+	_ = h.Log(InfoLevel, "written info")
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "written info") {
+		t.Errorf("Entry nên được ghi sau khi tắt bảo vệ, got %q", data)
+	}
+}
+
+func TestFileHandler_SetDiskGuard_MaxDirSizeCountsOwnBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.MkdirAll(filepath.Join(dir, "archive"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range []string{filepath.Join(dir, "archive", "app.log.20240101000000"), filepath.Join(dir, "other.log")} {
+		if err := os.WriteFile(other, make([]byte, 4096), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	var events []DiskEvent
+	h.SetDiskGuard(DiskGuard{MaxDirSize: 1024, Interval: time.Second, Notify: func(e DiskEvent) { events = append(events, e) }})
+	now := time.Now()
+	h.disk.now = func() time.Time { return now }
+
+	_ = h.Log(InfoLevel, "%s", strings.Repeat("x", 600))
+	if len(events) != 0 {
+		t.Fatalf("Chỉ file log và file sao lưu của nó được tính, got %+v", events)
+	}
+
+	// File sao lưu mới chỉ được tính sau khi kích thước được đo lại
+	if err := os.WriteFile(path+".20240101000000", make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	_ = h.Log(InfoLevel, "cached")
+	if len(events) != 0 {
+		t.Fatalf("Kích thước file sao lưu nên được lưu lại giữa các lần đo, got %+v", events)
+	}
+	now = now.Add(diskScanInterval)
+	_ = h.Log(InfoLevel, "dropped")
+	if len(events) != 1 || !events[0].Degraded || events[0].DirSize < 4096 {
+		t.Fatalf("Handler nên suy giảm khi file sao lưu vượt MaxDirSize, got %+v", events)
+	}
+
+	// Xoay vòng đo lại ngay kích thước file sao lưu
+	if err := os.Remove(path + ".20240101000000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	now = now.Add(time.Second)
+	_ = h.Log(WarningLevel, "rotated")
+	if len(events) != 2 || events[1].Degraded || events[1].DirSize >= 1024 {
+		t.Errorf("Handler nên phục hồi khi file sao lưu được đo lại sau xoay vòng, got %+v", events)
+	}
+}
+
+func TestDiskFree(t *testing.T) {
+	free, err := diskFree(t.TempDir())
+	if err != nil {
+		t.Skipf("Nền tảng không hỗ trợ kiểm tra dung lượng trống: %v", err)
+	}
+	if free <= 0 {
+		t.Errorf("diskFree() = %d, want > 0", free)
+	}
+}
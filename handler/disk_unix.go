@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd
+
+package handler
+
+import "syscall"
+
+// diskFree trả về dung lượng trống (byte) mà tiến trình không có quyền root được dùng trên ổ
+// đĩa chứa dir.
+func diskFree(dir string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package handler
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// procGetDiskFreeSpaceEx là hàm GetDiskFreeSpaceExW của kernel32.
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// diskFree trả về dung lượng trống (byte) mà người dùng hiện tại được dùng trên ổ đĩa chứa dir.
+func diskFree(dir string) (int64, error) {
+	path, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if r, _, err := procGetDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(&free)), 0, 0); r == 0 {
+		return 0, err
+	}
+	return int64(free), nil
+}
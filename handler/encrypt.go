@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// EncryptedPrefix mở đầu mỗi dòng log đã mã hóa, theo sau là số thứ tự bản ghi, nonce và bản mã
+// AES-GCM mã hóa base64.
+const EncryptedPrefix = "enc1:"
+
+// recordSeqSize là độ dài số thứ tự bản ghi (uint64 big-endian) ở đầu mỗi bản ghi đã mã hóa.
+const recordSeqSize = 8
+
+// ErrDecrypt được trả về khi một dòng không giải mã được: sai khóa, dòng bị sửa, bị xóa hoặc đổi
+// thứ tự, hoặc dòng không được mã hóa.
+var ErrDecrypt = errors.New("cannot decrypt log record")
+
+// KeyProvider trả về khóa AES (16, 24 hoặc 32 byte) để mã hóa file log, VD: giải mã data key
+// qua KMS hoặc đọc từ secret store. Provider được gọi mỗi khi Manager tạo file handler.
+type KeyProvider func() ([]byte, error)
+
+// keyProviders là registry các KeyProvider theo tên.
+var keyProviders = struct {
+	sync.RWMutex
+	byName map[string]KeyProvider
+}{byName: make(map[string]KeyProvider)}
+
+// RegisterKeyProvider đăng ký một KeyProvider theo tên, thay thế provider đã đăng ký cùng tên,
+// để cấu hình lấy khóa mã hóa từ KMS (Config.File.Encryption.KeyProvider). Hàm này thường được
+// gọi trong init.
+//
+// Tham số:
+//   - name: string - tên provider dùng trong cấu hình, không được rỗng
+//   - provider: KeyProvider - provider cần đăng ký, không được nil
+//
+// Ví dụ:
+//
+//	func init() {
+//		handler.RegisterKeyProvider("aws-kms", func() ([]byte, error) {
+//			out, err := kmsClient.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: wrappedKey})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return out.Plaintext, nil
+//		})
+//	}
+func RegisterKeyProvider(name string, provider KeyProvider) {
+	if name == "" || provider == nil {
+		panic("handler: RegisterKeyProvider name is empty or provider is nil")
+	}
+
+	keyProviders.Lock()
+	defer keyProviders.Unlock()
+	keyProviders.byName[name] = provider
+}
+
+// LookupKeyProvider trả về KeyProvider đã đăng ký theo tên.
+//
+// Tham số:
+//   - name: string - tên provider
+//
+// Trả về:
+//   - KeyProvider: provider tương ứng
+//   - bool: false nếu chưa có provider nào được đăng ký với tên này
+func LookupKeyProvider(name string) (KeyProvider, bool) {
+	keyProviders.RLock()
+	defer keyProviders.RUnlock()
+	provider, ok := keyProviders.byName[name]
+	return provider, ok
+}
+
+// KeyProviderNames trả về tên các KeyProvider đã đăng ký theo thứ tự bảng chữ cái.
+//
+// Trả về:
+//   - []string: tên các provider
+func KeyProviderNames() []string {
+	keyProviders.RLock()
+	defer keyProviders.RUnlock()
+	names := make([]string, 0, len(keyProviders.byName))
+	for name := range keyProviders.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newAEAD tạo AES-GCM từ khóa AES-128, AES-192 hoặc AES-256.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// SetEncryption mã hóa các dòng log được ghi sau lời gọi bằng AES-GCM, cho log chứa dữ liệu
+// phải được bảo vệ khi lưu trữ. Method này là thread-safe.
+//
+// Mỗi dòng (sau khi ký, xem SetSigning) được mã hóa riêng với nonce ngẫu nhiên và ghi thành
+// EncryptedPrefix + base64(số thứ tự || nonce || bản mã) trên một dòng, nên xoay vòng, nén và
+// Tail vẫn hoạt động. Số thứ tự đếm từ 0 trong mỗi file (tiếp tục khi ghi thêm vào file đã có
+// bản ghi mã hóa) và được xác thực như dữ liệu kèm theo (AAD), nên NewDecryptReader phát hiện
+// dòng bị xóa hoặc đổi thứ tự. Đọc lại file bằng NewDecryptReader hoặc reader.WithDecryption.
+// Nonce ngẫu nhiên 96 bit nên đổi khóa trước khi ghi khoảng 2^32 dòng với cùng một khóa.
+//
+// Tham số:
+//   - key: []byte - khóa AES 16, 24 hoặc 32 byte; nil để tắt mã hóa
+//
+// Trả về:
+//   - error: lỗi nếu độ dài khóa không hợp lệ; thiết lập cũ được giữ nguyên
+//
+// Ví dụ:
+//
+//	if err := fileHandler.SetEncryption(key); err != nil {
+//	    return err
+//	}
+//	// enc1:q9bR0v1l4uN2...
+func (a *FileHandler) SetEncryption(key []byte) error {
+	var aead cipher.AEAD
+	if key != nil {
+		var err error
+		if aead, err = newAEAD(key); err != nil {
+			return err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.aead = aead
+	if aead != nil {
+		a.records = countRecords(a.path)
+	}
+	return nil
+}
+
+// countRecords đếm các dòng đã mã hóa trong file path, để số thứ tự bản ghi tiếp tục khi ghi
+// thêm vào file đã có.
+func countRecords(path string) uint64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var count uint64
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadSlice('\n')
+		if bytes.HasPrefix(line, []byte(EncryptedPrefix)) {
+			count++
+		}
+		for err == bufio.ErrBufferFull {
+			_, err = r.ReadSlice('\n')
+		}
+		if err != nil {
+			return count
+		}
+	}
+}
+
+// appendEncrypted nối dạng mã hóa của dòng log line (kết thúc bằng '\n') vào cuối dst, với seq
+// là số thứ tự của bản ghi trong file.
+func appendEncrypted(dst, line []byte, aead cipher.AEAD, seq uint64) ([]byte, error) {
+	body := bytes.TrimSuffix(line, []byte{'\n'})
+	header := recordSeqSize + aead.NonceSize()
+	sealed := make([]byte, header, header+len(body)+aead.Overhead())
+	binary.BigEndian.PutUint64(sealed, seq)
+	if _, err := rand.Read(sealed[recordSeqSize:]); err != nil {
+		return dst, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+	sealed = aead.Seal(sealed, sealed[recordSeqSize:header], body, sealed[:recordSeqSize])
+
+	dst = append(dst, EncryptedPrefix...)
+	dst = base64.StdEncoding.AppendEncode(dst, sealed)
+	return append(dst, '\n'), nil
+}
+
+// decryptLine giải mã một dòng bắt đầu bằng EncryptedPrefix (không có ký tự xuống dòng) và trả
+// về số thứ tự bản ghi đã được xác thực cùng bản rõ.
+func decryptLine(aead cipher.AEAD, line []byte) (uint64, []byte, error) {
+	sealed, err := base64.StdEncoding.AppendDecode(nil, line[len(EncryptedPrefix):])
+	if err != nil || len(sealed) < recordSeqSize+aead.NonceSize() {
+		return 0, nil, ErrDecrypt
+	}
+	aad, nonce, ciphertext := sealed[:recordSeqSize], sealed[recordSeqSize:recordSeqSize+aead.NonceSize()],
+		sealed[recordSeqSize+aead.NonceSize():]
+	plain, err := aead.Open(ciphertext[:0], nonce, ciphertext, aad)
+	if err != nil {
+		return 0, nil, ErrDecrypt
+	}
+	return binary.BigEndian.Uint64(aad), plain, nil
+}
+
+// DecryptOptions cấu hình NewDecryptReader.
+type DecryptOptions struct {
+	// AllowPlaintext giữ nguyên các dòng không bắt đầu bằng EncryptedPrefix (VD: được ghi trước
+	// khi bật mã hóa) thay vì trả về lỗi. Không nên bật khi cần phát hiện dòng bị chèn vào file.
+	AllowPlaintext bool
+}
+
+// decryptReader giải mã từng dòng của một file log đã mã hóa.
+type decryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	opts    DecryptOptions
+	out     []byte // Phần đã giải mã chưa được đọc
+	line    int
+	records uint64 // Số thứ tự của bản ghi mã hóa tiếp theo
+	err     error
+}
+
+// NewDecryptReader trả về reader giải mã file log do FileHandler ghi với SetEncryption.
+//
+// Mọi dòng phải bắt đầu bằng EncryptedPrefix và các bản ghi phải có số thứ tự liên tiếp từ 0, nên
+// dòng bị chèn, bị xóa hoặc đổi thứ tự đều làm Read trả về lỗi. Dòng không mã hóa chỉ được chấp
+// nhận khi bật DecryptOptions.AllowPlaintext.
+//
+// Tham số:
+//   - r: io.Reader - nội dung một file log đã mã hóa (đã giải nén nếu là file sao lưu đã nén)
+//   - key: []byte - khóa AES đã dùng để mã hóa
+//   - opts: DecryptOptions - các tùy chọn giải mã
+//
+// Trả về:
+//   - io.Reader: reader trả về các dòng log gốc; Read trả về lỗi bọc ErrDecrypt kèm số dòng khi
+//     một dòng không giải mã được
+//   - error: lỗi nếu độ dài khóa không hợp lệ
+//
+// Ví dụ:
+//
+//	file, _ := os.Open("storage/logs/app.log")
+//	plain, err := handler.NewDecryptReader(file, key, handler.DecryptOptions{})
+//	if err != nil {
+//	    return err
+//	}
+//	io.Copy(os.Stdout, plain)
+func NewDecryptReader(r io.Reader, key []byte, opts DecryptOptions) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{src: bufio.NewReader(r), aead: aead, opts: opts}, nil
+}
+
+// Read đọc các dòng đã giải mã.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		line, err := d.src.ReadBytes('\n')
+		if len(line) > 0 {
+			d.line++
+			d.out = d.decode(line)
+		}
+		if err != nil && d.err == nil {
+			d.err = err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// decode giải mã một dòng (có hoặc không có ký tự xuống dòng); lỗi được lưu vào d.err.
+func (d *decryptReader) decode(line []byte) []byte {
+	body, newline := bytes.CutSuffix(line, []byte{'\n'})
+	if !bytes.HasPrefix(body, []byte(EncryptedPrefix)) {
+		if d.opts.AllowPlaintext {
+			return line
+		}
+		d.err = fmt.Errorf("line %d: not encrypted: %w", d.line, ErrDecrypt)
+		return nil
+	}
+	seq, plain, err := decryptLine(d.aead, bytes.TrimSuffix(body, []byte{'\r'}))
+	if err != nil {
+		d.err = fmt.Errorf("line %d: %w", d.line, err)
+		return nil
+	}
+	if seq != d.records {
+		d.err = fmt.Errorf("line %d: record %d out of sequence, want %d: %w", d.line, seq, d.records, ErrDecrypt)
+		return nil
+	}
+	d.records++
+	if newline {
+		plain = append(plain, '\n')
+	}
+	return plain
+}
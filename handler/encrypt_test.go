@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestFileHandler_SetEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.log")
+	if err := os.WriteFile(path, []byte("2024/03/01 12:00:00 [INFO] written before encryption\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	if err := h.SetEncryption(testEncryptionKey); err != nil {
+		t.Fatalf("SetEncryption() error = %v", err)
+	}
+	h.SetSigning(Signing{Key: testSigningKey, Records: true})
+	_ = h.Log(InfoLevel, "[Patient] record viewed patient_id=%d", 42)
+	_ = h.Log(ErrorLevel, "line one\nline two")
+
+	entries, err := h.Tail(10)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if len(entries) != 3 || !strings.HasPrefix(entries[1].Message, "[Patient] record viewed patient_id=42") ||
+		!strings.HasPrefix(entries[2].Message, "line one\nline two") {
+		t.Errorf("Tail() nên giải mã các dòng đã mã hóa, got %v", entries)
+	}
+	_ = h.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("patient_id")) || bytes.Count(data, []byte("\n"+EncryptedPrefix)) != 2 {
+		t.Fatalf("File không được chứa bản rõ, got %q", data)
+	}
+
+	if _, err := io.ReadAll(mustDecryptReader(t, data, DecryptOptions{})); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Dòng không mã hóa nên bị từ chối mặc định, got %v", err)
+	}
+	plain, err := NewDecryptReader(bytes.NewReader(data), testEncryptionKey, DecryptOptions{AllowPlaintext: true})
+	if err != nil {
+		t.Fatalf("NewDecryptReader() error = %v", err)
+	}
+	out, err := io.ReadAll(plain)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "2024/03/01 12:00:00 [INFO] written before encryption" ||
+		!strings.Contains(lines[1], "[INFO] [Patient] record viewed patient_id=42") {
+		t.Fatalf("Bản rõ không đúng, got %q", out)
+	}
+	// Chữ ký được tính trên dòng gốc trước khi mã hóa
+	if !VerifyLine(testSigningKey, []byte(lines[1])) {
+		t.Errorf("Dòng đã giải mã nên có chữ ký hợp lệ, got %q", lines[1])
+	}
+}
+
+func TestFileHandler_SetEncryption_InvalidKey(t *testing.T) {
+	h, err := NewFileHandler(filepath.Join(t.TempDir(), "secret.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.SetEncryption([]byte("short")); err == nil {
+		t.Error("SetEncryption() nên từ chối khóa không phải 16, 24 hoặc 32 byte")
+	}
+	if err := h.SetEncryption(nil); err != nil {
+		t.Errorf("SetEncryption(nil) nên tắt mã hóa, got %v", err)
+	}
+}
+
+func TestNewDecryptReader_Errors(t *testing.T) {
+	if _, err := NewDecryptReader(strings.NewReader(""), []byte("short"), DecryptOptions{}); err == nil {
+		t.Error("NewDecryptReader() nên từ chối khóa không hợp lệ")
+	}
+
+	aead := mustAEAD(t, testEncryptionKey)
+	line, err := appendEncrypted(nil, []byte("2024/03/01 12:00:00 [INFO] secret\n"), aead, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := appendEncrypted(nil, []byte("2024/03/01 12:00:01 [INFO] next\n"), aead, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := bytes.Clone(line)
+	tampered[len(EncryptedPrefix)+30] ^= 'A' ^ 'B' // Đổi một ký tự base64 của bản mã
+	renumbered := bytes.Clone(next)
+	renumbered[len(EncryptedPrefix)+8] ^= 'A' ^ 'B' // Đổi số thứ tự bản ghi
+	for name, input := range map[string]string{
+		"wrong key":  string(line),
+		"tampered":   string(tampered),
+		"garbage":    EncryptedPrefix + "!!!\n",
+		"plaintext":  string(line) + "2024/03/01 12:00:01 [INFO] injected\n",
+		"reordered":  string(next) + string(line),
+		"dropped":    string(next),
+		"renumbered": string(line) + string(renumbered),
+	} {
+		t.Run(name, func(t *testing.T) {
+			key := testEncryptionKey
+			if name == "wrong key" {
+				key = []byte("fedcba9876543210fedcba9876543210")
+			}
+			plain, err := NewDecryptReader(strings.NewReader(input), key, DecryptOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.ReadAll(plain); !errors.Is(err, ErrDecrypt) {
+				t.Errorf("ReadAll() error = %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestFileHandler_SetEncryption_RecordSequence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.log")
+	for _, message := range []string{"first", "second"} {
+		h, err := NewFileHandler(path, 0)
+		if err != nil {
+			t.Fatalf("NewFileHandler() error = %v", err)
+		}
+		if err := h.SetEncryption(testEncryptionKey); err != nil {
+			t.Fatalf("SetEncryption() error = %v", err)
+		}
+		_ = h.Log(InfoLevel, message)
+		_ = h.Log(InfoLevel, message+" again")
+		_ = h.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(mustDecryptReader(t, data, DecryptOptions{}))
+	if err != nil || bytes.Count(out, []byte("\n")) != 4 {
+		t.Fatalf("Ghi thêm vào file đã mã hóa nên tiếp tục số thứ tự bản ghi, got %q, %v", out, err)
+	}
+
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+	if err := h.SetEncryption(testEncryptionKey); err != nil {
+		t.Fatalf("SetEncryption() error = %v", err)
+	}
+	if err := h.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	_ = h.Log(InfoLevel, "after rotation")
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := io.ReadAll(mustDecryptReader(t, data, DecryptOptions{})); err != nil || !bytes.Contains(out, []byte("after rotation")) {
+		t.Errorf("File mới sau khi xoay vòng nên đánh số lại từ 0, got %q, %v", out, err)
+	}
+}
+
+func TestRegisterKeyProvider(t *testing.T) {
+	RegisterKeyProvider("test-kms", func() ([]byte, error) { return testEncryptionKey, nil })
+
+	provider, ok := LookupKeyProvider("test-kms")
+	if !ok {
+		t.Fatal("LookupKeyProvider() nên tìm thấy provider đã đăng ký")
+	}
+	if key, err := provider(); err != nil || !bytes.Equal(key, testEncryptionKey) {
+		t.Errorf("provider() = %q, %v", key, err)
+	}
+	if _, ok := LookupKeyProvider("missing"); ok {
+		t.Error("LookupKeyProvider() không nên tìm thấy provider chưa đăng ký")
+	}
+	found := false
+	for _, name := range KeyProviderNames() {
+		found = found || name == "test-kms"
+	}
+	if !found {
+		t.Errorf("KeyProviderNames() nên chứa test-kms, got %v", KeyProviderNames())
+	}
+}
+
+func mustDecryptReader(t *testing.T, data []byte, opts DecryptOptions) io.Reader {
+	t.Helper()
+	plain, err := NewDecryptReader(bytes.NewReader(data), testEncryptionKey, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plain
+}
+
+func mustAEAD(t *testing.T, key []byte) cipher.AEAD {
+	t.Helper()
+	aead, err := newAEAD(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead
+}
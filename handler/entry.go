@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"strings"
+	"time"
+)
+
+// Entry đại diện cho một log entry hoàn chỉnh, bao gồm thời điểm phát sinh.
+//
+// Entry cho phép thời điểm của log được xác định bởi bên gọi thay vì thời điểm ghi,
+// cần thiết khi phát lại các sự kiện đã được đệm hoặc ghi log thay cho một tiến trình
+// xử lý theo lô bị trễ.
+//
+// Cùng một Entry (và slice Fields của nó) được gửi đến mọi handler của một lời gọi log, nên
+// handler phải coi entry là chỉ đọc: handler cần sửa entry (VD: thêm field, đổi thông điệp)
+// phải sửa trên bản sao từ Clone, hoặc được bọc bằng NewIsolatedHandler. Handler giữ entry sau
+// khi LogEntry trả về (VD: AsyncHandler) tự giữ một bản sao.
+type Entry struct {
+	Time    time.Time // Thời điểm phát sinh của entry
+	Level   Level     // Cấp độ nghiêm trọng của entry
+	Message string    // Thông điệp log đã được định dạng
+	Text    string    // Thông điệp gốc chưa gắn context và field (rỗng nếu entry không do Logger tạo)
+	Fields  []Field   // Các field có cấu trúc của entry, đã được hiển thị trong Message
+}
+
+// EntryHandler là interface tùy chọn cho các handler có thể nhận toàn bộ Entry.
+//
+// Handler không triển khai EntryHandler vẫn nhận được log qua Log, nhưng sẽ
+// tự gán timestamp tại thời điểm ghi thay vì dùng Entry.Time.
+type EntryHandler interface {
+	Handler
+
+	// LogEntry xử lý một log entry hoàn chỉnh.
+	//
+	// Entry được chia sẻ với các handler khác và không được sửa (xem Entry).
+	//
+	// Tham số:
+	//   - entry: *Entry - log entry cần xử lý
+	//
+	// Trả về:
+	//   - error: một lỗi nếu log entry không thể được xử lý
+	LogEntry(entry *Entry) error
+}
+
+// Dispatch gửi một entry đến handler, sử dụng LogEntry nếu handler hỗ trợ.
+//
+// Tham số:
+//   - h: Handler - handler nhận entry
+//   - entry: *Entry - log entry cần gửi
+//
+// Trả về:
+//   - error: lỗi trả về bởi handler
+//
+// Ví dụ:
+//
+//	entry := &handler.Entry{Time: event.Time, Level: handler.InfoLevel, Message: "replayed"}
+//	err := handler.Dispatch(fileHandler, entry)
+func Dispatch(h Handler, entry *Entry) error {
+	if eh, ok := h.(EntryHandler); ok {
+		return eh.LogEntry(entry)
+	}
+	return h.Log(entry.Level, entry.Message)
+}
+
+// Clone trả về bản sao của entry với slice Fields riêng, để sửa hoặc giữ lại entry mà không
+// ảnh hưởng đến các handler khác nhận cùng entry.
+//
+// Giá trị của field AnyType (VD: map, slice do bên gọi truyền vào) không được sao chép sâu.
+//
+// Trả về:
+//   - *Entry: bản sao của entry
+//
+// Ví dụ:
+//
+//	func (h *enricher) LogEntry(entry *handler.Entry) error {
+//	    enriched := entry.Clone()
+//	    enriched.Fields = append(enriched.Fields, handler.Field{Key: "host", Type: handler.StringType, Str: h.host})
+//	    return handler.Dispatch(h.next, enriched)
+//	}
+func (e *Entry) Clone() *Entry {
+	clone := *e
+	if e.Fields != nil {
+		clone.Fields = make([]Field, len(e.Fields))
+		copy(clone.Fields, e.Fields)
+	}
+	return &clone
+}
+
+// Context trả về context của logger trong phần "[Context]" đầu thông điệp.
+//
+// Trả về:
+//   - string: context của entry, chuỗi rỗng nếu thông điệp không có context
+func (e *Entry) Context() string {
+	// Message bắt đầu bằng thông điệp gốc khi logger không có context, kể cả khi thông điệp gốc
+	// tự bắt đầu bằng "[...]"
+	if e.Text != "" && strings.HasPrefix(e.Message, e.Text) {
+		return ""
+	}
+	return messageContext(e.Message)
+}
+
+// text trả về thông điệp gốc của entry cho các định dạng có cấu trúc: Text, hoặc Message đã bỏ
+// phần "[Context]" đầu và các field cuối thông điệp với entry không có Text.
+func (e *Entry) text() string {
+	if e.Text != "" {
+		return e.Text
+	}
+	message := e.Message
+	if context := messageContext(message); context != "" {
+		message = strings.TrimPrefix(message[len(context)+2:], " ")
+	}
+	if len(e.Fields) > 0 {
+		buf := GetBuffer()
+		*buf = append(*buf, ' ')
+		*buf = Limits{}.AppendFields(*buf, e.Fields)
+		message = strings.TrimSuffix(message, string(*buf))
+		PutBuffer(buf)
+	}
+	return message
+}
+
+// Field trả về field đầu tiên có key của entry.
+//
+// Tham số:
+//   - key: string - key của field
+//
+// Trả về:
+//   - Field: field tìm được
+//   - bool: false nếu entry không có field với key này
+func (e *Entry) Field(key string) (Field, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// IsolatedHandler bọc một handler sửa entry tại chỗ (VD: interceptor hoặc enricher của bên thứ
+// ba) và gửi cho nó bản sao riêng của mỗi entry, để việc sửa không ảnh hưởng đến các handler
+// khác nhận cùng entry, kể cả các handler ghi bất đồng bộ.
+type IsolatedHandler struct {
+	handler Handler
+}
+
+// NewIsolatedHandler tạo handler gửi đến h bản sao riêng của mỗi entry.
+//
+// Tham số:
+//   - h: Handler - handler có thể sửa entry nhận được
+//
+// Trả về:
+//   - *IsolatedHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	stack := handler.NewStackHandler(fileHandler, handler.NewIsolatedHandler(thirdPartyEnricher))
+func NewIsolatedHandler(h Handler) *IsolatedHandler {
+	return &IsolatedHandler{handler: h}
+}
+
+// Log chuyển thông điệp đến handler bên trong. Thông điệp không có entry dùng chung nên không
+// cần sao chép.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (i *IsolatedHandler) Log(level Level, message string, args ...interface{}) error {
+	return i.handler.Log(level, message, args...)
+}
+
+// LogEntry gửi bản sao của entry đến handler bên trong.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (i *IsolatedHandler) LogEntry(entry *Entry) error {
+	return Dispatch(i.handler, entry.Clone())
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (i *IsolatedHandler) Unwrap() Handler {
+	return i.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (i *IsolatedHandler) Close() error {
+	return i.handler.Close()
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// entryRecorder ghi lại entry nhận được qua LogEntry
+type entryRecorder struct {
+	MockTestHandler
+	entry *Entry
+}
+
+func (r *entryRecorder) LogEntry(entry *Entry) error {
+	r.entry = entry
+	return nil
+}
+
+func TestDispatch_FallbackToLog(t *testing.T) {
+	h := &MockTestHandler{}
+	entry := &Entry{Time: time.Now(), Level: WarningLevel, Message: "plain handler"}
+
+	if err := Dispatch(h, entry); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if !h.LogCalled || h.LogLevel != WarningLevel || h.LogMessage != "plain handler" {
+		t.Errorf("Dispatch() nên gọi Log với level và message của entry, got %v %q", h.LogLevel, h.LogMessage)
+	}
+}
+
+func TestStackHandler_LogEntry(t *testing.T) {
+	plain := &MockTestHandler{}
+	rec := &entryRecorder{}
+	stack := NewStackHandler(plain, rec)
+
+	entry := &Entry{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Level: InfoLevel, Message: "replayed"}
+	if err := Dispatch(stack, entry); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if rec.entry != entry {
+		t.Error("StackHandler nên chuyển tiếp entry đến handler con hỗ trợ EntryHandler")
+	}
+	if rec.LogCalled {
+		t.Error("Handler con hỗ trợ EntryHandler không nên nhận entry qua Log")
+	}
+	if !plain.LogCalled || plain.LogMessage != "replayed" {
+		t.Error("Handler con không hỗ trợ EntryHandler nên nhận entry qua Log")
+	}
+}
+
+func TestFileHandler_LogEntry_UsesEntryTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entry.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	at := time.Date(2020, 5, 6, 7, 8, 9, 0, time.Local)
+	if err := h.LogEntry(&Entry{Time: at, Level: ErrorLevel, Message: "batch failed"}); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Không thể đọc file log: %v", err)
+	}
+	if want := "2020/05/06 07:08:09 [ERROR] batch failed\n"; string(content) != want {
+		t.Errorf("LogEntry() nên ghi với timestamp của entry, got %q, want %q", content, want)
+	}
+}
+
+// mutatingRecorder sửa entry nhận được tại chỗ, mô phỏng một enricher không tuân thủ hợp đồng chỉ đọc
+type mutatingRecorder struct {
+	MockTestHandler
+	entry *Entry
+}
+
+func (r *mutatingRecorder) LogEntry(entry *Entry) error {
+	entry.Message = "mutated"
+	entry.Fields[0].Str = "mutated"
+	entry.Fields = append(entry.Fields, Field{Key: "host", Type: StringType, Str: "web-1"})
+	r.entry = entry
+	return nil
+}
+
+func TestEntry_Clone(t *testing.T) {
+	entry := &Entry{Level: InfoLevel, Message: "original", Fields: []Field{{Key: "user", Type: StringType, Str: "alice"}}}
+	clone := entry.Clone()
+	clone.Message = "changed"
+	clone.Fields[0].Str = "bob"
+
+	if entry.Message != "original" || entry.Fields[0].Str != "alice" {
+		t.Errorf("Sửa bản sao không nên ảnh hưởng đến entry gốc, got %q %q", entry.Message, entry.Fields[0].Str)
+	}
+	if (&Entry{}).Clone().Fields != nil {
+		t.Error("Clone của entry không có field nên giữ Fields nil")
+	}
+}
+
+func TestIsolatedHandler_LogEntry(t *testing.T) {
+	inner := &mutatingRecorder{}
+	rec := &entryRecorder{}
+	isolated := NewIsolatedHandler(inner)
+	stack := NewStackHandler(isolated, rec)
+
+	entry := &Entry{Level: InfoLevel, Message: "original", Fields: []Field{{Key: "user", Type: StringType, Str: "alice"}}}
+	if err := Dispatch(stack, entry); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if inner.entry == entry || len(inner.entry.Fields) != 2 {
+		t.Error("Handler bên trong nên nhận bản sao của entry")
+	}
+	if rec.entry.Message != "original" || rec.entry.Fields[0].Str != "alice" || len(rec.entry.Fields) != 1 {
+		t.Errorf("Handler khác không nên thấy thay đổi của handler được bọc, got %q %v", rec.entry.Message, rec.entry.Fields)
+	}
+	if isolated.Unwrap() != inner {
+		t.Error("Unwrap() nên trả về handler bên trong")
+	}
+}
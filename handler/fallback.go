@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FallbackHandler bọc một handler chính và chuyển entry sang handler dự phòng (VD: console
+// hoặc file spill cục bộ) khi handler chính ghi thất bại liên tiếp, để log không bị mất âm
+// thầm khi sink từ xa hoặc ổ đĩa gặp sự cố.
+//
+// Sau after lần thất bại liên tiếp, mỗi entry mà handler chính ghi thất bại được ghi vào
+// handler dự phòng và lỗi chỉ được trả về nếu handler dự phòng cũng thất bại. Lần ghi thành
+// công đầu tiên của handler chính kết thúc chế độ dự phòng. Handler an toàn khi dùng đồng thời.
+type FallbackHandler struct {
+	handler  Handler                 // Handler chính
+	fallback atomic.Pointer[Handler] // Handler dự phòng, nil = không có
+	after    int                     // Số lần thất bại liên tiếp trước khi chuyển sang dự phòng
+
+	mu       sync.Mutex
+	failures int   // Số lần thất bại liên tiếp của handler chính
+	lastErr  error // Lỗi gần nhất của handler chính
+}
+
+// NewFallbackHandler tạo một FallbackHandler bọc h.
+//
+// Tham số:
+//   - h: Handler - handler chính
+//   - fallback: Handler - handler dự phòng, nil để đặt sau qua SetFallback
+//   - after: int - số lần thất bại liên tiếp trước khi chuyển sang dự phòng (<= 0 = 1)
+//
+// Trả về:
+//   - *FallbackHandler: handler đã được cấu hình
+//
+// Ví dụ:
+//
+//	remote := handler.NewFallbackHandler(lokiHandler, handler.NewConsoleHandler(false), 3)
+func NewFallbackHandler(h, fallback Handler, after int) *FallbackHandler {
+	if after <= 0 {
+		after = 1
+	}
+	f := &FallbackHandler{handler: h, after: after}
+	f.SetFallback(fallback)
+	return f
+}
+
+// SetFallback đặt handler dự phòng. Handler dự phòng không được đóng bởi FallbackHandler.
+// Method này là thread-safe.
+//
+// Tham số:
+//   - fallback: Handler - handler dự phòng mới, nil để tắt
+func (f *FallbackHandler) SetFallback(fallback Handler) {
+	if fallback == nil {
+		f.fallback.Store(nil)
+		return
+	}
+	f.fallback.Store(&fallback)
+}
+
+// Log ghi một log entry với timestamp hiện tại.
+//
+// Tham số:
+//   - level: Level - cấp độ nghiêm trọng của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - các tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi nếu entry không được ghi vào handler chính hoặc dự phòng
+func (f *FallbackHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return f.LogEntry(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// LogEntry ghi entry vào handler chính, chuyển sang handler dự phòng nếu handler chính đã
+// thất bại liên tiếp đủ số lần.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: lỗi của handler chính khi chưa chuyển sang dự phòng, hoặc lỗi của handler dự
+//     phòng
+func (f *FallbackHandler) LogEntry(entry *Entry) error {
+	err := Dispatch(f.handler, entry)
+
+	f.mu.Lock()
+	if err == nil {
+		f.failures, f.lastErr = 0, nil
+		f.mu.Unlock()
+		return nil
+	}
+	f.failures++
+	f.lastErr = err
+	failing := f.failures >= f.after
+	f.mu.Unlock()
+
+	fallback := f.fallback.Load()
+	if !failing || fallback == nil {
+		return err
+	}
+	if ferr := Dispatch(*fallback, entry); ferr != nil {
+		return fmt.Errorf("%w (fallback: %v)", err, ferr)
+	}
+	return nil
+}
+
+// Health báo cáo khi handler chính đang thất bại và entry được chuyển sang dự phòng.
+//
+// Trả về:
+//   - error: lỗi gần nhất của handler chính khi ở chế độ dự phòng, nil nếu bình thường
+func (f *FallbackHandler) Health() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures < f.after {
+		return nil
+	}
+	return fmt.Errorf("handler failing after %d consecutive errors, using fallback: %w", f.failures, f.lastErr)
+}
+
+// Close đóng handler chính. Handler dự phòng do bên tạo sở hữu và không bị đóng.
+//
+// Trả về:
+//   - error: lỗi từ Close của handler chính
+func (f *FallbackHandler) Close() error {
+	return f.handler.Close()
+}
+
+// Unwrap trả về handler chính.
+//
+// Trả về:
+//   - Handler: handler chính
+func (f *FallbackHandler) Unwrap() Handler {
+	return f.handler
+}
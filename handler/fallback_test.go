@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+)
+
+func TestFallbackHandler_SwitchesAfterFailures(t *testing.T) {
+	primary := &MockTestHandler{ShouldError: true}
+	fallback := &MockTestHandler{}
+	f := NewFallbackHandler(primary, fallback, 2)
+
+	if err := f.Log(ErrorLevel, "first"); err == nil {
+		t.Error("Lần thất bại đầu tiên nên trả về lỗi của handler chính")
+	}
+	if fallback.LogCalled {
+		t.Error("Handler dự phòng chưa nên nhận entry trước khi đủ số lần thất bại")
+	}
+	if err := f.Log(ErrorLevel, "second"); err != nil {
+		t.Errorf("Entry nên được ghi vào handler dự phòng, got %v", err)
+	}
+	if !fallback.LogCalled || fallback.LogMessage != "second" {
+		t.Errorf("Handler dự phòng nên nhận entry second, got %q", fallback.LogMessage)
+	}
+	if err := Health(f); err == nil {
+		t.Error("Health() nên báo lỗi khi đang dùng handler dự phòng")
+	}
+
+	primary.ShouldError = false
+	fallback.LogCalled = false
+	if err := f.Log(InfoLevel, "recovered"); err != nil {
+		t.Errorf("Log() error = %v", err)
+	}
+	if fallback.LogCalled || primary.LogMessage != "recovered" {
+		t.Error("Entry nên quay lại handler chính khi ghi thành công")
+	}
+	if err := Health(f); err != nil {
+		t.Errorf("Health() nên bình thường sau khi handler chính hồi phục, got %v", err)
+	}
+}
+
+func TestFallbackHandler_FallbackError(t *testing.T) {
+	f := NewFallbackHandler(&MockTestHandler{ShouldError: true}, nil, 0)
+	if err := f.Log(InfoLevel, "no fallback"); err == nil {
+		t.Error("Log() nên trả về lỗi khi chưa có handler dự phòng")
+	}
+
+	fallback := &MockTestHandler{ShouldError: true}
+	f.SetFallback(fallback)
+	if err := f.Log(InfoLevel, "both fail"); err == nil {
+		t.Error("Log() nên trả về lỗi khi cả handler dự phòng cũng thất bại")
+	}
+	if err := f.Close(); err == nil || fallback.CloseCalled {
+		t.Error("Close() chỉ nên đóng handler chính")
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType xác định nơi lưu giá trị của Field.
+//
+// Các field có kiểu (được tạo bởi log.String, log.Int64, log.Duration...) lưu giá trị trong
+// Integer hoặc Str thay vì Value, nên không cần đóng gói (boxing) vào interface{} và được
+// định dạng trực tiếp bằng strconv thay vì fmt.
+type FieldType uint8
+
+const (
+	// AnyType lưu giá trị trong Value (mặc định của Field{Key, Value})
+	AnyType FieldType = iota
+	// StringType lưu giá trị trong Str
+	StringType
+	// Int64Type lưu giá trị trong Integer
+	Int64Type
+	// Uint64Type lưu giá trị trong Integer dưới dạng bit của uint64
+	Uint64Type
+	// Float64Type lưu giá trị trong Integer dưới dạng math.Float64bits
+	Float64Type
+	// BoolType lưu giá trị trong Integer (1 là true)
+	BoolType
+	// DurationType lưu giá trị trong Integer dưới dạng nanosecond
+	DurationType
+)
+
+// Field đại diện cho một cặp key-value có cấu trúc đính kèm vào log entry.
+//
+// Field cho phép ghi dữ liệu có cấu trúc (VD: user_id, duration) tách biệt
+// khỏi thông điệp, giúp việc lọc và phân tích log dễ dàng hơn. Handler đọc giá trị
+// qua Interface() để hỗ trợ cả field có kiểu lẫn field Value.
+type Field struct {
+	Key     string      // Tên của field
+	Value   interface{} // Giá trị của field khi Type là AnyType
+	Type    FieldType   // Nơi lưu giá trị của field
+	Integer int64       // Giá trị của field số, bool và duration
+	Str     string      // Giá trị của field chuỗi
+}
+
+// Interface trả về giá trị của field dưới dạng interface{}, bất kể field được lưu theo kiểu nào.
+//
+// Trả về:
+//   - interface{}: giá trị của field (VD: int64 với Int64Type, time.Duration với DurationType)
+func (f Field) Interface() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.Str
+	case Int64Type:
+		return f.Integer
+	case Uint64Type:
+		return uint64(f.Integer)
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	default:
+		return f.Value
+	}
+}
+
+// String trả về biểu diễn key=value của field theo định dạng logfmt.
+//
+// Giá trị chứa khoảng trắng, dấu '=' hoặc dấu nháy kép sẽ được đặt trong nháy kép.
+//
+// Trả về:
+//   - string: field ở dạng key=value
+//
+// Ví dụ:
+//
+//	Field{Key: "user", Value: "john doe"}.String() // user="john doe"
+func (f Field) String() string {
+	return string(f.appendTo(nil, Limits{}.withDefaults()))
+}
+
+// FormatFields nối các field thành một chuỗi key=value cách nhau bởi khoảng trắng.
+//
+// Tham số:
+//   - fields: []Field - danh sách field cần định dạng
+//
+// Trả về:
+//   - string: chuỗi đã định dạng, hoặc chuỗi rỗng nếu không có field nào
+func FormatFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return string(AppendFields(make([]byte, 0, 32*len(fields)), fields))
+}
+
+// AppendFields nối các field ở dạng key=value cách nhau bởi khoảng trắng vào cuối dst.
+//
+// Field có kiểu được ghi trực tiếp bằng strconv mà không cấp phát bộ nhớ trung gian; map,
+// slice và struct được mã hóa theo giới hạn mặc định (xem Limits.AppendFields).
+//
+// Tham số:
+//   - dst: []byte - buffer đích
+//   - fields: []Field - danh sách field cần định dạng
+//
+// Trả về:
+//   - []byte: buffer đã được nối thêm các field
+func AppendFields(dst []byte, fields []Field) []byte {
+	return Limits{}.AppendFields(dst, fields)
+}
+
+// appendTo nối field ở dạng key=value vào cuối dst. limits phải đã được áp dụng giá trị mặc định.
+func (f Field) appendTo(dst []byte, limits Limits) []byte {
+	dst = append(dst, f.Key...)
+	dst = append(dst, '=')
+	switch f.Type {
+	case StringType:
+		if needsQuote(f.Str) {
+			return strconv.AppendQuote(dst, f.Str)
+		}
+		return append(dst, f.Str...)
+	case Int64Type:
+		return strconv.AppendInt(dst, f.Integer, 10)
+	case Uint64Type:
+		return strconv.AppendUint(dst, uint64(f.Integer), 10)
+	case Float64Type:
+		return strconv.AppendFloat(dst, math.Float64frombits(uint64(f.Integer)), 'g', -1, 64)
+	case BoolType:
+		return strconv.AppendBool(dst, f.Integer == 1)
+	case DurationType:
+		return append(dst, time.Duration(f.Integer).String()...)
+	default:
+		return append(dst, formatValue(f.Value, limits)...)
+	}
+}
+
+// needsQuote kiểm tra chuỗi có cần đặt trong nháy kép khi ghi ở dạng logfmt hay không. Chuỗi
+// chứa ký tự mà Sanitize thoát (VD: mã escape ANSI) cũng được đặt trong nháy kép để strconv.Quote
+// thoát các ký tự đó.
+func needsQuote(s string) bool {
+	return s == "" || strings.ContainsAny(s, " =\"\t\r\n") || unsafeIndex(s) >= 0
+}
+
+// formatValue chuyển giá trị của field thành chuỗi, đặt trong nháy kép khi cần.
+func formatValue(value interface{}, limits Limits) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	case []byte:
+		s = string(v)
+	default:
+		// Map, slice và struct được mã hóa JSON qua NormalizeValue để hiển thị nhất quán;
+		// JSON tự phân tách nên chỉ cần đặt trong nháy kép khi chứa khoảng trắng
+		if isComposite(v) {
+			s = encodeComposite(v, limits)
+			if strings.Contains(s, " ") {
+				return strconv.Quote(s)
+			}
+			return s
+		}
+		s = fmt.Sprint(v)
+	}
+
+	if needsQuote(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
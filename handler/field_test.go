@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestField_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		want  string
+	}{
+		{"String", Field{Key: "user", Value: "john"}, "user=john"},
+		{"Int", Field{Key: "id", Value: 42}, "id=42"},
+		{"Quoted", Field{Key: "name", Value: "john doe"}, `name="john doe"`},
+		{"Empty", Field{Key: "name", Value: ""}, `name=""`},
+		{"Error", Field{Key: "error", Value: errors.New("boom")}, "error=boom"},
+		{"Nil", Field{Key: "value", Value: nil}, "value=<nil>"},
+		{"Slice", Field{Key: "ids", Value: []int{1, 2, 3}}, "ids=[1,2,3]"},
+		{"Map", Field{Key: "tags", Value: map[string]int{"b": 2, "a": 1}}, `tags={"a":1,"b":2}`},
+		{"MapWithSpace", Field{Key: "meta", Value: map[string]string{"name": "john doe"}}, `meta="{\"name\":\"john doe\"}"`},
+		{"Struct", Field{Key: "user", Value: struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Secret string `json:"-"`
+			hidden string
+		}{ID: 1, Name: "john", Secret: "x", hidden: "y"}}, `user={"id":1,"name":"john"}`},
+		{"NestedFields", Field{Key: "req", Value: []Field{{Key: "method", Value: "GET"}, {Key: "ids", Value: []string{"a"}}}}, `req={"ids":["a"],"method":"GET"}`},
+		{"Bytes", Field{Key: "body", Value: []byte("ok")}, "body=ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.String(); got != tt.want {
+				t.Errorf("Field.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	if got := FormatFields(nil); got != "" {
+		t.Errorf("FormatFields(nil) = %q, want chuỗi rỗng", got)
+	}
+
+	got := FormatFields([]Field{{Key: "a", Value: 1}, {Key: "b", Value: "x y"}})
+	if want := `a=1 b="x y"`; got != want {
+		t.Errorf("FormatFields() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeValue_Limits(t *testing.T) {
+	t.Run("ElementCount", func(t *testing.T) {
+		values := make([]int, DefaultMaxFieldElements+3)
+		got := NormalizeValue(values).([]interface{})
+		if len(got) != DefaultMaxFieldElements+1 {
+			t.Fatalf("len = %d, want %d", len(got), DefaultMaxFieldElements+1)
+		}
+		if got[DefaultMaxFieldElements] != "+3 more" {
+			t.Errorf("marker = %v, want +3 more", got[DefaultMaxFieldElements])
+		}
+	})
+
+	t.Run("MapCount", func(t *testing.T) {
+		values := make(map[int]bool, DefaultMaxFieldElements+2)
+		for i := 0; i < DefaultMaxFieldElements+2; i++ {
+			values[i] = true
+		}
+		got := NormalizeValue(values).(map[string]interface{})
+		if got[TruncatedKey] != "+2 more" {
+			t.Errorf("marker = %v, want +2 more", got[TruncatedKey])
+		}
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		var value interface{} = "leaf"
+		for i := 0; i < DefaultMaxFieldDepth+2; i++ {
+			value = []interface{}{value}
+		}
+		if got := formatValue(value, Limits{}.withDefaults()); got != `[[[[["[truncated]"]]]]]` {
+			t.Errorf("formatValue() = %s", got)
+		}
+	})
+
+	t.Run("Pointers", func(t *testing.T) {
+		var nilMap *map[string]int
+		if got := NormalizeValue(nilMap); got != nil {
+			t.Errorf("NormalizeValue(nil pointer) = %v, want nil", got)
+		}
+		m := map[string]int{"a": 1}
+		if got := formatValue(&m, Limits{}.withDefaults()); got != `{"a":1}` {
+			t.Errorf("formatValue(&map) = %s", got)
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	limits := Limits{MaxDepth: 1, MaxElements: 2}
+
+	if got := string(limits.AppendFields(nil, []Field{{Key: "ids", Value: []int{1, 2, 3}}})); got != `ids="[1,2,\"+1 more\"]"` {
+		t.Errorf("AppendFields() với MaxElements = %s", got)
+	}
+	if got := string(limits.AppendFields(nil, []Field{{Key: "m", Value: map[string][]int{"a": {1}}}})); got != `m={"a":"[truncated]"}` {
+		t.Errorf("AppendFields() với MaxDepth = %s", got)
+	}
+
+	fields := []Field{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}}
+	if got := FormatFields(Limits{MaxFields: 1}.TruncateFields(fields)); got != "a=1 fields_truncated=2" {
+		t.Errorf("TruncateFields() = %s", got)
+	}
+	if got := (Limits{}).TruncateFields(fields); len(got) != 3 {
+		t.Errorf("TruncateFields() không vượt giới hạn nên giữ nguyên, got %v", got)
+	}
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"crypto/cipher"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
 // FileHandler triển khai một log handler ghi vào file với khả năng xoay vòng.
@@ -21,13 +24,33 @@ import (
 //   - Thư mục chứa file log phải tồn tại trước
 //   - Thư mục phải có quyền ghi
 type FileHandler struct {
-	path        string     // Đường dẫn đến file log
-	file        *os.File   // File handle hiện tại
-	maxSize     int64      // Kích thước file tối đa tính bằng byte trước khi xoay vòng
-	currentSize int64      // Kích thước file hiện tại tính bằng byte
-	mu          sync.Mutex // Mutex để đảm bảo thread-safety
+	path        string                          // Đường dẫn đến file log
+	file        *os.File                        // File handle hiện tại
+	maxSize     int64                           // Kích thước file tối đa tính bằng byte trước khi xoay vòng
+	currentSize int64                           // Kích thước file hiện tại tính bằng byte
+	growth      *growthTracker                  // Theo dõi tốc độ ghi để cảnh báo (nil = tắt)
+	disk        *diskGuard                      // Bảo vệ dung lượng đĩa (nil = tắt)
+	codec       Codec                           // Codec nén file sao lưu sau khi xoay vòng (nil = không nén)
+	retention   BackupRetention                 // Giới hạn số lượng và tuổi của file sao lưu
+	format      Format                          // Định dạng dòng log (mặc định TextFormat)
+	device      Device                          // Thiết bị trong header của CEFFormat và LEEFFormat
+	signing     Signing                         // Chữ ký HMAC của dòng log và file sao lưu (xem SetSigning)
+	aead        cipher.AEAD                     // Mã hóa AES-GCM từng dòng log (nil = không mã hóa, xem SetEncryption)
+	records     uint64                          // Số thứ tự của bản ghi mã hóa tiếp theo trong file hiện tại
+	syncOn      bool                            // Bật fsync sau khi ghi entry từ syncLevel trở lên
+	syncLevel   Level                           // Cấp độ tối thiểu của entry được fsync
+	onRotate    []func(oldPath, newPath string) // Các callback được gọi sau mỗi lần xoay vòng
+	background  sync.WaitGroup                  // Các lần nén và callback xoay vòng đang chạy ở nền
+	err         error                           // Lỗi của lần ghi gần nhất, nil sau khi ghi thành công
+	written     atomic.Uint64                   // Tổng số byte đã ghi, kể cả các file đã xoay vòng
+	rotations   atomic.Uint64                   // Số lần xoay vòng thành công
+	clock       clockRef                        // Nguồn thời điểm của entry ghi qua Log, tên file sao lưu và MaxAge
+	mu          sync.Mutex                      // Mutex để đảm bảo thread-safety
 }
 
+// syncFile gọi fsync cho file log sau khi ghi entry (xem SetSyncLevel), được thay thế trong test.
+var syncFile = (*os.File).Sync
+
 // NewFileHandler tạo một file handler mới cho đường dẫn và kích thước tối đa được chỉ định.
 //
 // Tham số:
@@ -114,46 +137,205 @@ func NewFileHandler(path string, maxSize int64) (*FileHandler, error) {
 // Trả về:
 //   - error: một lỗi nếu ghi vào file thất bại
 func (a *FileHandler) Log(level Level, message string, args ...interface{}) error {
+	// Định dạng thông điệp nếu có tham số
+	formattedMessage := message
+	if len(args) > 0 {
+		formattedMessage = fmt.Sprintf(message, args...)
+	}
+	entry := getEntry(a.clock.Now(), level, formattedMessage)
+	defer putEntry(entry)
+
+	return a.LogEntry(entry)
+}
+
+// LogEntry ghi một log entry vào file với timestamp lấy từ entry.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: một lỗi nếu ghi vào file thất bại
+func (a *FileHandler) LogEntry(entry *Entry) error {
+	// Cảnh báo tốc độ tăng trưởng và dung lượng đĩa được gửi sau khi nhả lock (defer chạy theo
+	// thứ tự ngược) để hàm nhận cảnh báo có thể ghi log
+	var event *GrowthEvent
+	var notify func(GrowthEvent)
+	var diskEvent *DiskEvent
+	var diskNotify func(DiskEvent)
+	defer func() {
+		if diskEvent != nil {
+			a.notifyDisk(diskNotify, *diskEvent)
+		}
+		if event != nil {
+			a.notifyGrowth(notify, *event)
+		}
+	}()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	// Ở chế độ suy giảm do thiếu dung lượng đĩa, entry dưới WarningLevel bị bỏ
+	if a.disk != nil {
+		if diskEvent = a.disk.check(a.path, a.currentSize); diskEvent != nil {
+			diskNotify = a.disk.guard.Notify
+		}
+		if !a.disk.allow(entry.Level) {
+			return nil
+		}
+	}
+
 	// Kiểm tra xem file có cần xoay vòng không
 	if a.maxSize > 0 && a.currentSize >= a.maxSize {
 		if err := a.rotate(); err != nil {
-			return fmt.Errorf("không thể xoay vòng file log: %w", err)
+			a.err = fmt.Errorf("không thể xoay vòng file log: %w", err)
+			return a.err
 		}
 	}
 
-	// Định dạng với timestamp và mức độ
-	timestamp := time.Now().Format("2006/01/02 15:04:05")
-
-	// Định dạng thông điệp nếu có tham số
-	formattedMessage := message
-	if len(args) > 0 {
-		formattedMessage = fmt.Sprintf(message, args...)
+	// Định dạng với timestamp và mức độ vào buffer dùng lại từ pool
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	*buf = appendFormatted(*buf, a.format, a.device, entry)
+	if a.signing.Records && len(a.signing.Key) > 0 {
+		*buf = appendSignature(*buf, a.signing.Key, a.format)
+	}
+	if a.aead != nil {
+		encrypted := GetBuffer()
+		defer PutBuffer(encrypted)
+		line, err := appendEncrypted(*encrypted, *buf, a.aead, a.records)
+		if err != nil {
+			a.err = fmt.Errorf("không thể mã hóa log: %w", err)
+			return a.err
+		}
+		*encrypted = line
+		buf = encrypted
 	}
-	formattedMessage = fmt.Sprintf("%s [%s] %s\n", timestamp, level.String(), formattedMessage)
 
 	// Ghi vào file
-	n, err := a.file.WriteString(formattedMessage)
+	n, err := a.file.Write(*buf)
 	if err != nil {
-		return fmt.Errorf("không thể ghi vào file log: %w", err)
+		a.err = fmt.Errorf("không thể ghi vào file log: %w", err)
+		return a.err
+	}
+	a.err = nil
+	if a.aead != nil {
+		a.records++
 	}
 
 	// Cập nhật kích thước file hiện tại
 	a.currentSize += int64(n)
+	a.written.Add(uint64(n))
+	if a.syncOn && entry.Level >= a.syncLevel {
+		if err := syncFile(a.file); err != nil {
+			a.err = fmt.Errorf("không thể sync file log: %w", err)
+			return a.err
+		}
+	}
+	if a.growth != nil {
+		event = a.growth.add(a.path, a.growth.now(), int64(n))
+		notify = a.growth.alert.Notify
+	}
 
 	return nil
 }
 
+// BytesWritten trả về tổng số byte đã ghi kể từ khi handler được tạo, kể cả vào các file đã
+// xoay vòng. Method này là thread-safe.
+//
+// Trả về:
+//   - uint64: số byte đã ghi
+func (a *FileHandler) BytesWritten() uint64 {
+	return a.written.Load()
+}
+
+// Rotations trả về số lần file đã được xoay vòng thành công, theo kích thước hoặc qua Rotate.
+// Method này là thread-safe.
+//
+// Trả về:
+//   - uint64: số lần xoay vòng
+func (a *FileHandler) Rotations() uint64 {
+	return a.rotations.Load()
+}
+
+// Sync ghi dữ liệu của file log xuống đĩa (fsync).
+//
+// FileHandler triển khai Syncer nên chế độ giao nhận Guaranteed dùng Sync để xác nhận
+// entry đã được lưu bền vững.
+//
+// Trả về:
+//   - error: một lỗi nếu file đã đóng hoặc fsync thất bại
+func (a *FileHandler) Sync() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return fmt.Errorf("không thể sync file log đã đóng")
+	}
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("không thể sync file log: %w", err)
+	}
+	return nil
+}
+
+// SetSyncLevel bật fsync ngay sau mỗi entry có cấp độ từ level trở lên, để entry quan trọng
+// (VD: log kiểm toán, Error và Fatal) không bị mất khi tiến trình hoặc máy bị dừng đột ngột.
+// Mỗi lần fsync làm chậm việc ghi đáng kể; mặc định không fsync. Method này là thread-safe.
+//
+// Tham số:
+//   - level: Level - cấp độ tối thiểu được fsync; DebugLevel để fsync sau mọi entry
+//
+// Ví dụ:
+//
+//	// Đảm bảo entry Error và Fatal đã được ghi xuống đĩa trước khi LogEntry trả về
+//	fileHandler.SetSyncLevel(handler.ErrorLevel)
+func (a *FileHandler) SetSyncLevel(level Level) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.syncOn = true
+	a.syncLevel = level
+}
+
+// SetClock thay thế nguồn thời điểm của các entry ghi qua Log (entry ghi qua LogEntry dùng
+// Entry.Time), của hậu tố tên file sao lưu và của việc tính tuổi file sao lưu theo MaxAge, để test
+// cố định timestamp của output. Method này là thread-safe.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng SystemClock
+//
+// Ví dụ:
+//
+//	clock := handler.NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+//	fileHandler.SetClock(clock)
+//	fileHandler.Log(handler.InfoLevel, "ready") // 2024/03/01 12:00:00 [INFO] ready
+func (a *FileHandler) SetClock(clock Clock) {
+	a.clock.set(clock)
+}
+
+// Health kiểm tra file log còn mở và lần ghi gần nhất thành công.
+//
+// Trả về:
+//   - error: lỗi nếu file đã đóng hoặc lần ghi (hay xoay vòng) gần nhất thất bại
+func (a *FileHandler) Health() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return fmt.Errorf("file log %s đã đóng", a.path)
+	}
+	return a.err
+}
+
 // Close đóng file log một cách chính xác.
 //
 // Phương thức này nên được gọi khi handler không còn cần thiết nữa
-// để đảm bảo file được đóng chính xác và tất cả dữ liệu được ghi đệm.
+// để đảm bảo file được đóng chính xác và tất cả dữ liệu được ghi đệm. Close cũng chờ các
+// file sao lưu đang được nén và các callback OnRotate đang chạy ở nền.
 //
 // Trả về:
 //   - error: một lỗi nếu đóng file thất bại
 func (a *FileHandler) Close() error {
+	// Chờ sau khi nhả khóa để callback OnRotate vẫn có thể gọi handler
+	defer a.background.Wait()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -169,33 +351,251 @@ func (a *FileHandler) Close() error {
 
 // rotate thực hiện xoay vòng file log khi kích thước file vượt quá giới hạn tối đa.
 //
-// File hiện tại được đổi tên với hậu tố timestamp, và một file mới được tạo.
+// File hiện tại được đổi tên với hậu tố timestamp (thêm "-N" khi đã có file sao lưu cùng giây),
+// và một file mới được tạo. Trên Windows,
+// việc đổi tên được thử lại với backoff khi một tiến trình khác đang giữ file; nếu vẫn thất bại,
+// nội dung được sao chép sang file sao lưu rồi file gốc bị cắt ngắn (copy-truncate).
 //
 // Trả về:
-//   - error: một lỗi nếu việc xoay vòng thất bại
+//   - error: một lỗi nếu việc xoay vòng thất bại; file log vẫn được mở lại để tiếp tục ghi
 func (a *FileHandler) rotate() error {
 	// Đóng file hiện tại
 	if err := a.file.Close(); err != nil {
 		return fmt.Errorf("không thể đóng file log hiện tại: %w", err)
 	}
 
-	// Tạo tên file sao lưu với timestamp
-	backupPath := fmt.Sprintf("%s.%s", a.path, time.Now().Format("20060102150405"))
+	// Tạo tên file sao lưu với timestamp, không trùng với file sao lưu đã có
+	backupPath := backupPathFor(a.path, a.clock.Now())
 
-	// Đổi tên file hiện tại thành file sao lưu
-	if err := os.Rename(a.path, backupPath); err != nil {
-		return fmt.Errorf("không thể đổi tên file log: %w", err)
+	// Đổi tên file hiện tại thành file sao lưu, hoặc sao chép rồi cắt ngắn nếu file đang bị giữ
+	var rotateErr error
+	if err := renameFile(a.path, backupPath); err != nil {
+		if copyErr := copyTruncate(a.path, backupPath); copyErr != nil {
+			rotateErr = fmt.Errorf("không thể đổi tên file log: %w (sao chép: %v)", err, copyErr)
+		}
 	}
 
-	// Mở file log mới
+	// Mở file log mới (hoặc mở lại file cũ nếu xoay vòng thất bại để không mất log)
 	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
+		a.file = nil
 		return fmt.Errorf("không thể mở file log mới: %w", err)
 	}
+	a.file = file
+	if rotateErr != nil {
+		return rotateErr
+	}
 
 	// Cập nhật trạng thái handler
-	a.file = file
 	a.currentSize = 0
+	a.records = 0
+	if a.disk != nil {
+		a.disk.rescan()
+	}
+	a.rotations.Add(1)
+	a.finishRotation(backupPath)
 
 	return nil
 }
+
+// Rotator là interface tùy chọn cho các handler có thể xoay vòng hoặc mở lại file theo yêu
+// cầu (VD: khi logrotate gửi SIGHUP).
+type Rotator interface {
+	// Rotate xoay vòng file đang ghi.
+	//
+	// Trả về:
+	//   - error: lỗi nếu không thể xoay vòng hoặc mở lại file
+	Rotate() error
+}
+
+// Rotate xoay vòng mọi handler trong chuỗi của h (theo Unwrap) triển khai Rotator.
+//
+// Tham số:
+//   - h: Handler - handler cần xoay vòng
+//
+// Trả về:
+//   - error: lỗi của các handler xoay vòng thất bại, nil nếu không có lỗi
+func Rotate(h Handler) error {
+	var errs []error
+	for h != nil {
+		if r, ok := h.(Rotator); ok {
+			if err := r.Rotate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return errors.Join(errs...)
+}
+
+// Rotate xoay vòng file log ngay lập tức, không chờ đến MaxSize. Method này là thread-safe.
+//
+// Nếu file tại đường dẫn log đã bị công cụ bên ngoài đổi tên hoặc xóa (VD: logrotate không
+// dùng copytruncate), handler chỉ mở lại file tại đường dẫn log thay vì tạo thêm file sao lưu.
+// File log rỗng không được xoay vòng.
+//
+// Trả về:
+//   - error: lỗi nếu file đã đóng hoặc không thể xoay vòng hay mở lại file
+//
+// Ví dụ:
+//
+//	// Xoay vòng file log lúc nửa đêm
+//	if err := fileHandler.Rotate(); err != nil {
+//	    fmt.Fprintf(os.Stderr, "Không thể xoay vòng file log: %v\n", err)
+//	}
+func (a *FileHandler) Rotate() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return fmt.Errorf("không thể xoay vòng file log đã đóng")
+	}
+	if a.moved() {
+		if err := a.reopen(); err != nil {
+			a.err = err
+			return a.err
+		}
+		return nil
+	}
+	if a.currentSize == 0 {
+		return nil
+	}
+	if err := a.rotate(); err != nil {
+		a.err = fmt.Errorf("không thể xoay vòng file log: %w", err)
+		return a.err
+	}
+	return nil
+}
+
+// moved kiểm tra file đang mở không còn nằm tại đường dẫn log. Phải được gọi khi đang giữ a.mu.
+func (a *FileHandler) moved() bool {
+	current, err := a.file.Stat()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(a.path)
+	return err != nil || !os.SameFile(current, info)
+}
+
+// reopen đóng file đang mở và mở lại file tại đường dẫn log. Phải được gọi khi đang giữ a.mu.
+func (a *FileHandler) reopen() error {
+	a.file.Close()
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		a.file = nil
+		return fmt.Errorf("không thể mở lại file log: %w", err)
+	}
+	a.file = file
+	a.currentSize = 0
+	if info, err := file.Stat(); err == nil {
+		a.currentSize = info.Size()
+	}
+	if a.aead != nil {
+		a.records = countRecords(a.path)
+	}
+	if a.disk != nil {
+		a.disk.rescan()
+	}
+	a.err = nil
+	return nil
+}
+
+// OnRotate đăng ký một callback được gọi sau mỗi lần xoay vòng thành công, VD: để chuyển,
+// tính checksum hoặc tải lên file sao lưu vừa hoàn tất. Method này là thread-safe.
+//
+// Callback chạy ở nền, không giữ khóa của handler, sau khi file sao lưu đã được nén (nếu bật
+// SetCompression) và trước khi các file sao lưu vượt giới hạn của BackupRetention bị xóa. Các
+// callback được gọi tuần tự theo thứ tự đăng ký; Close chờ các callback đang chạy kết thúc.
+//
+// Tham số:
+//   - fn: func(oldPath, newPath string) - nhận đường dẫn file sao lưu vừa hoàn tất (kèm phần mở
+//     rộng của codec nếu đã nén) và đường dẫn file log đang được ghi
+//
+// Ví dụ:
+//
+//	fileHandler.OnRotate(func(oldPath, newPath string) {
+//	    if err := uploader.Upload(oldPath); err != nil {
+//	        fmt.Fprintf(os.Stderr, "Không thể tải lên %s: %v\n", oldPath, err)
+//	    }
+//	})
+func (a *FileHandler) OnRotate(fn func(oldPath, newPath string)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onRotate = append(a.onRotate, fn)
+}
+
+// finishRotation nén file sao lưu, ký file sao lưu, gọi các callback OnRotate rồi xóa các file sao
+// lưu vượt giới hạn của BackupRetention. Khi bật nén, ký file sao lưu hoặc có callback, các bước
+// này chạy ở nền để không giữ a.mu. Phải được gọi khi đang giữ a.mu.
+func (a *FileHandler) finishRotation(backupPath string) {
+	var signKey []byte
+	if a.signing.Segments {
+		signKey = a.signing.Key
+	}
+	if a.codec == nil && len(a.onRotate) == 0 && len(signKey) == 0 {
+		pruneBackups(a.path, a.retention, a.clock.Now())
+		return
+	}
+	path, codec, retention, callbacks := a.path, a.codec, a.retention, a.onRotate
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		segment := backupPath
+		if codec != nil {
+			if err := compressFile(backupPath, codec); err != nil {
+				fmt.Fprintf(os.Stderr, "Lỗi khi nén file log %s: %v\n", backupPath, err)
+			} else {
+				segment = backupPath + codec.Extension()
+			}
+		}
+		if len(signKey) > 0 {
+			if err := signSegment(segment, signKey); err != nil {
+				fmt.Fprintf(os.Stderr, "Lỗi khi ký file log %s: %v\n", segment, err)
+			}
+		}
+		for _, fn := range callbacks {
+			fn(segment, path)
+		}
+		pruneBackups(path, retention, a.clock.Now())
+	}()
+}
+
+// copyTruncate sao chép nội dung file log sang file sao lưu rồi cắt ngắn file gốc về 0 byte.
+//
+// Đây là cách xoay vòng dự phòng khi không thể đổi tên file (VD: trên Windows khi tiến trình
+// khác giữ file mà không chia sẻ quyền xóa). Các entry được ghi vào file gốc giữa lúc sao chép
+// và cắt ngắn bởi tiến trình khác có thể bị mất.
+//
+// Tham số:
+//   - path: string - đường dẫn file log
+//   - backupPath: string - đường dẫn file sao lưu, không được tồn tại trước
+//
+// Trả về:
+//   - error: một lỗi nếu việc sao chép hoặc cắt ngắn thất bại
+func copyTruncate(path, backupPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(backupPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(backupPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(backupPath)
+		return err
+	}
+
+	return os.Truncate(path, 0)
+}
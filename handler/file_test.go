@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bufio"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -744,3 +745,37 @@ func TestFileHandler_New_ErrorMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestFileHandler_SetSyncLevel(t *testing.T) {
+	var synced []string
+	original := syncFile
+	syncFile = func(f *os.File) error {
+		synced = append(synced, f.Name())
+		return nil
+	}
+	defer func() { syncFile = original }()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	_ = h.Log(ErrorLevel, "before sync enabled")
+	if len(synced) != 0 {
+		t.Fatalf("Mặc định không nên fsync, got %d lần", len(synced))
+	}
+	h.SetSyncLevel(ErrorLevel)
+	_ = h.Log(InfoLevel, "info")
+	_ = h.Log(ErrorLevel, "error")
+	_ = h.Log(FatalLevel, "fatal")
+	if len(synced) != 2 || synced[0] != path {
+		t.Errorf("Chỉ entry từ ErrorLevel trở lên nên được fsync, got %v", synced)
+	}
+
+	syncFile = func(*os.File) error { return errors.New("disk failure") }
+	if err := h.Log(ErrorLevel, "sync fails"); err == nil || h.Health() == nil {
+		t.Errorf("Lỗi fsync nên được trả về và báo qua Health, got %v", err)
+	}
+}
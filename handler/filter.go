@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"path"
+	"strings"
+)
+
+// FieldFilter chọn các field có cấu trúc mà một handler ghi ra.
+//
+// Tên field có thể chứa ký tự đại diện theo path.Match (VD: "request_*"). Field chỉ được giữ
+// khi khớp Allow (hoặc Allow rỗng) và không khớp Deny.
+type FieldFilter struct {
+	Allow  []string // Chỉ giữ các field khớp, rỗng để giữ tất cả
+	Deny   []string // Bỏ các field khớp, áp dụng sau Allow
+	Limits Limits   // Giới hạn của logger khi định dạng field trong thông điệp (xem FieldFilterHandler)
+}
+
+// Keep kiểm tra field có được giữ lại hay không.
+//
+// Tham số:
+//   - key: string - tên field
+//
+// Trả về:
+//   - bool: true nếu field được giữ
+func (f FieldFilter) Keep(key string) bool {
+	if len(f.Allow) > 0 && !matchAny(f.Allow, key) {
+		return false
+	}
+	return !matchAny(f.Deny, key)
+}
+
+// Apply trả về các field được giữ lại.
+//
+// Slice đầu vào không bị sửa; một slice mới được trả về khi có field bị bỏ.
+//
+// Tham số:
+//   - fields: []Field - các field của entry
+//
+// Trả về:
+//   - []Field: các field được giữ
+func (f FieldFilter) Apply(fields []Field) []Field {
+	var out []Field
+	for i, field := range fields {
+		keep := f.Keep(field.Key)
+		if !keep && out == nil {
+			out = make([]Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		if keep && out != nil {
+			out = append(out, field)
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// matchAny kiểm tra key có khớp một trong các mẫu hay không.
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if pattern == key {
+			return true
+		}
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldFilterHandler bọc một handler và chỉ chuyển các field được FieldFilter giữ lại, VD: bỏ
+// user_agent và body của request khỏi console nhưng vẫn ghi đầy đủ vào file hoặc ELK.
+//
+// Logger hiển thị các field ở cuối Message dạng " key=value ..."; handler định dạng lại phần
+// này với FieldFilter.Limits và thay bằng các field được giữ. Nếu phần cuối của Message không
+// khớp (VD: entry không được tạo bởi logger), chỉ Entry.Fields được lọc. Handler an toàn khi
+// dùng đồng thời.
+type FieldFilterHandler struct {
+	handler Handler
+	filter  FieldFilter
+}
+
+// NewFieldFilterHandler tạo handler lọc field của các entry trước khi chuyển đến h.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry đã được lọc
+//   - filter: FieldFilter - các field được giữ hoặc bỏ
+//
+// Trả về:
+//   - *FieldFilterHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	console := handler.NewFieldFilterHandler(handler.NewConsoleHandler(true), handler.FieldFilter{
+//	    Deny: []string{"user_agent", "request_body", "response_*"},
+//	})
+func NewFieldFilterHandler(h Handler, filter FieldFilter) *FieldFilterHandler {
+	return &FieldFilterHandler{handler: h, filter: filter}
+}
+
+// Log chuyển thông điệp đến handler bên trong. Thông điệp không có field có cấu trúc nên
+// không bị thay đổi.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *FieldFilterHandler) Log(level Level, message string, args ...interface{}) error {
+	return f.handler.Log(level, message, args...)
+}
+
+// LogEntry lọc field của entry rồi chuyển đến handler bên trong.
+//
+// Entry gốc không bị sửa vì có thể được gửi đồng thời đến các handler khác.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *FieldFilterHandler) LogEntry(entry *Entry) error {
+	fields := f.filter.Apply(entry.Fields)
+	if len(fields) == len(entry.Fields) {
+		return Dispatch(f.handler, entry)
+	}
+
+	filtered := *entry
+	filtered.Fields = fields
+	if len(entry.Fields) > 0 {
+		buf := GetBuffer()
+		*buf = append(*buf, ' ')
+		*buf = f.filter.Limits.AppendFields(*buf, entry.Fields)
+		if base, ok := strings.CutSuffix(entry.Message, string(*buf)); ok {
+			*buf = append((*buf)[:0], base...)
+			if len(fields) > 0 {
+				*buf = append(*buf, ' ')
+				*buf = f.filter.Limits.AppendFields(*buf, fields)
+			}
+			filtered.Message = string(*buf)
+		}
+		PutBuffer(buf)
+	}
+	return Dispatch(f.handler, &filtered)
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (f *FieldFilterHandler) Unwrap() Handler {
+	return f.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (f *FieldFilterHandler) Close() error {
+	return f.handler.Close()
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"testing"
+)
+
+func TestFieldFilter_Keep(t *testing.T) {
+	filter := FieldFilter{Allow: []string{"request_*", "user_id"}, Deny: []string{"request_body"}}
+	tests := map[string]bool{
+		"request_id":   true,
+		"user_id":      true,
+		"request_body": false,
+		"user_agent":   false,
+	}
+	for key, want := range tests {
+		if got := filter.Keep(key); got != want {
+			t.Errorf("Keep(%q) = %v, want %v", key, got, want)
+		}
+	}
+	if !(FieldFilter{}).Keep("anything") {
+		t.Error("FieldFilter rỗng nên giữ mọi field")
+	}
+}
+
+func TestFieldFilterHandler_LogEntry(t *testing.T) {
+	rec := &entryRecorder{}
+	h := NewFieldFilterHandler(rec, FieldFilter{Deny: []string{"user_agent"}})
+	fields := []Field{
+		{Key: "path", Type: StringType, Str: "/api/users"},
+		{Key: "user_agent", Type: StringType, Str: "Mozilla/5.0 (X11)"},
+		{Key: "status", Type: Int64Type, Integer: 200},
+	}
+	entry := &Entry{Level: InfoLevel, Message: `[HTTP] request path=/api/users user_agent="Mozilla/5.0 (X11)" status=200`, Fields: fields}
+
+	if err := h.LogEntry(entry); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+	if rec.entry.Message != "[HTTP] request path=/api/users status=200" || len(rec.entry.Fields) != 2 {
+		t.Errorf("Field bị bỏ nên được xóa khỏi thông điệp và Fields, got %q %+v", rec.entry.Message, rec.entry.Fields)
+	}
+	if len(entry.Fields) != 3 || entry.Message != `[HTTP] request path=/api/users user_agent="Mozilla/5.0 (X11)" status=200` {
+		t.Error("Entry gốc không được bị sửa")
+	}
+
+	// Thông điệp không kết thúc bằng các field: chỉ Entry.Fields được lọc
+	custom := &Entry{Level: InfoLevel, Message: "custom message", Fields: fields}
+	_ = h.LogEntry(custom)
+	if rec.entry.Message != "custom message" || len(rec.entry.Fields) != 2 {
+		t.Errorf("Thông điệp không khớp nên được giữ nguyên, got %q %+v", rec.entry.Message, rec.entry.Fields)
+	}
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FoldMode xác định cách FoldHandler gộp thông điệp nhiều dòng thành một bản ghi.
+type FoldMode int
+
+const (
+	// FoldEscape thay xuống dòng trong thông điệp bằng `\n` và `\r`, giữ toàn bộ nội dung trên
+	// một dòng (mặc định).
+	FoldEscape FoldMode = iota
+
+	// FoldField giữ dòng đầu tiên làm thông điệp và chuyển các dòng còn lại vào một field mảng
+	// (mặc định "lines"); field chuỗi nhiều dòng (VD: stack trace) cũng được chuyển thành mảng
+	// các dòng, phù hợp với định dạng JSON.
+	FoldField
+)
+
+// FieldFoldedLines là key mặc định của field chứa các dòng sau dòng đầu tiên của thông điệp
+// trong FoldField.
+const FieldFoldedLines = "lines"
+
+// String trả về tên của chế độ gộp dòng dùng trong cấu hình.
+//
+// Trả về:
+//   - string: "escape", "field" hoặc "unknown"
+func (m FoldMode) String() string {
+	switch m {
+	case FoldEscape:
+		return "escape"
+	case FoldField:
+		return "field"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFoldMode chuyển tên chế độ gộp dòng (không phân biệt hoa thường) thành FoldMode.
+//
+// Tham số:
+//   - s: string - tên chế độ (escape, field)
+//
+// Trả về:
+//   - FoldMode: chế độ tương ứng
+//   - error: lỗi nếu tên không hợp lệ
+func ParseFoldMode(s string) (FoldMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "escape":
+		return FoldEscape, nil
+	case "field":
+		return FoldField, nil
+	default:
+		return FoldEscape, fmt.Errorf("invalid fold mode: %q", s)
+	}
+}
+
+// FoldOptions cấu hình FoldHandler.
+type FoldOptions struct {
+	Mode   FoldMode // Cách gộp dòng
+	Field  string   // Key của field chứa các dòng còn lại trong FoldField (rỗng = FieldFoldedLines)
+	Limits Limits   // Giới hạn của logger khi định dạng field trong thông điệp (xem FoldHandler)
+}
+
+// lineEscaper thoát xuống dòng trong FoldEscape.
+var lineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// FoldHandler bọc một handler và gộp thông điệp nhiều dòng (VD: stack trace, output của lệnh)
+// thành một bản ghi, để các bộ thu thập đọc theo dòng (VD: Promtail, Fluent Bit) không tách một
+// sự kiện thành nhiều bản ghi.
+//
+// Logger mặc định đã thoát xuống dòng trong thông điệp (xem Sanitize), nên FoldHandler chủ yếu
+// dùng với logger ở raw mode, hoặc với FoldField để chuyển field chuỗi nhiều dòng thành mảng.
+//
+// Trong FoldField, phần " key=value ..." cuối Message được định dạng lại với FoldOptions.Limits
+// và các field mới; nếu phần cuối của Message không khớp, chỉ field chứa các dòng được thêm vào
+// cuối dòng đầu tiên. Entry gốc không bị sửa. Handler an toàn khi dùng đồng thời.
+type FoldHandler struct {
+	handler Handler
+	opts    FoldOptions
+}
+
+// NewFoldHandler tạo handler gộp thông điệp nhiều dòng trước khi chuyển đến h.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry đã gộp
+//   - opts: FoldOptions - chế độ gộp, key của field và giới hạn định dạng
+//
+// Trả về:
+//   - *FoldHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	file := handler.NewFoldHandler(jsonFileHandler, handler.FoldOptions{Mode: handler.FoldField})
+//	// {"context":"Worker","message":"panic: boom","lines":["goroutine 1 [running]:","main.main()"],...}
+func NewFoldHandler(h Handler, opts FoldOptions) *FoldHandler {
+	if opts.Field == "" {
+		opts.Field = FieldFoldedLines
+	}
+	return &FoldHandler{handler: h, opts: opts}
+}
+
+// Log gộp thông điệp rồi chuyển đến handler bên trong.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *FoldHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return f.LogEntry(&Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// LogEntry gộp thông điệp của bản sao entry rồi chuyển đến handler bên trong.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *FoldHandler) LogEntry(entry *Entry) error {
+	if f.opts.Mode == FoldEscape {
+		if !strings.ContainsAny(entry.Message, "\r\n") {
+			return Dispatch(f.handler, entry)
+		}
+		folded := *entry
+		folded.Message, folded.Text = lineEscaper.Replace(entry.Message), lineEscaper.Replace(entry.Text)
+		return Dispatch(f.handler, &folded)
+	}
+
+	fields, changed := f.splitFields(entry.Fields)
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	if len(entry.Fields) > 0 {
+		*buf = append(*buf, ' ')
+		*buf = f.opts.Limits.AppendFields(*buf, entry.Fields)
+	}
+	base, ok := strings.CutSuffix(entry.Message, string(*buf))
+	if !ok {
+		base = entry.Message
+	}
+	lines := splitLines(base)
+	if len(lines) == 1 && !changed {
+		return Dispatch(f.handler, entry)
+	}
+	if len(lines) > 1 {
+		fields = append(fields, Field{Key: f.opts.Field, Value: lines[1:]})
+	}
+
+	// Phần field cuối thông điệp không khớp thì chỉ ghi thêm field chứa các dòng, để handler chỉ
+	// ghi Message (VD: console) không làm mất các dòng
+	var suffix []Field
+	switch {
+	case ok:
+		suffix = fields
+	case len(lines) > 1:
+		suffix = fields[len(fields)-1:]
+	}
+	folded := *entry
+	folded.Fields = fields
+	if len(lines) > 1 && entry.Text != "" {
+		folded.Text = splitLines(entry.Text)[0]
+	}
+	*buf = append((*buf)[:0], lines[0]...)
+	if len(suffix) > 0 {
+		*buf = append(*buf, ' ')
+		*buf = f.opts.Limits.AppendFields(*buf, suffix)
+	}
+	folded.Message = string(*buf)
+	return Dispatch(f.handler, &folded)
+}
+
+// splitFields trả về bản sao của fields với field chuỗi nhiều dòng được chuyển thành mảng các
+// dòng, và có field nào được chuyển hay không.
+func (f *FoldHandler) splitFields(fields []Field) ([]Field, bool) {
+	out := make([]Field, len(fields), len(fields)+1)
+	copy(out, fields)
+	changed := false
+	for i, field := range out {
+		s, ok := field.Str, field.Type == StringType
+		if v, isString := field.Value.(string); field.Type == AnyType && isString {
+			s, ok = v, true
+		}
+		if ok && strings.ContainsAny(s, "\r\n") {
+			out[i] = Field{Key: field.Key, Value: splitLines(s)}
+			changed = true
+		}
+	}
+	return out, changed
+}
+
+// splitLines tách s thành các dòng, bỏ các dòng trống ở cuối.
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\r\n"), "\n")
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (f *FoldHandler) Unwrap() Handler {
+	return f.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (f *FoldHandler) Close() error {
+	return f.handler.Close()
+}
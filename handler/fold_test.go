@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFoldMode(t *testing.T) {
+	for _, mode := range []FoldMode{FoldEscape, FoldField} {
+		if got, err := ParseFoldMode(mode.String()); err != nil || got != mode {
+			t.Errorf("ParseFoldMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := ParseFoldMode("array"); err == nil {
+		t.Error("ParseFoldMode() nên từ chối tên không hợp lệ")
+	}
+}
+
+func TestFoldHandler_Escape(t *testing.T) {
+	mem := NewMemoryHandler()
+	h := NewFoldHandler(mem, FoldOptions{})
+
+	entry := &Entry{Level: ErrorLevel, Message: "panic: boom\r\ngoroutine 1 [running]:\nmain.main()"}
+	h.LogEntry(entry)
+	h.Log(InfoLevel, "single line")
+	entries := mem.Entries()
+	if entries[0].Message != `panic: boom\r\ngoroutine 1 [running]:\nmain.main()` {
+		t.Errorf("FoldEscape = %q", entries[0].Message)
+	}
+	if entry.Message != "panic: boom\r\ngoroutine 1 [running]:\nmain.main()" {
+		t.Error("Entry gốc không được bị sửa")
+	}
+	if entries[1].Message != "single line" {
+		t.Errorf("Thông điệp một dòng nên giữ nguyên, got %q", entries[1].Message)
+	}
+}
+
+func TestFoldHandler_Field(t *testing.T) {
+	mem := NewMemoryHandler()
+	h := NewFoldHandler(mem, FoldOptions{Mode: FoldField})
+
+	fields := []Field{
+		{Key: "job", Type: StringType, Str: "sync"},
+		{Key: "stack", Value: "main.run()\n\tmain.go:12\n"},
+	}
+	message := "[Worker] panic: boom\ngoroutine 1 [running]:\nmain.main()\n " + string(AppendFields(nil, fields))
+	h.LogEntry(&Entry{Level: ErrorLevel, Message: message, Text: "panic: boom\ngoroutine 1 [running]:\nmain.main()", Fields: fields})
+
+	got := mem.Entries()[0]
+	wantLines := []string{"goroutine 1 [running]:", "main.main()"}
+	if lines, _ := got.Field(FieldFoldedLines); !reflect.DeepEqual(lines.Value, wantLines) {
+		t.Errorf("lines = %#v, want %#v", lines.Value, wantLines)
+	}
+	if stack, _ := got.Field("stack"); !reflect.DeepEqual(stack.Value, []string{"main.run()", "\tmain.go:12"}) {
+		t.Errorf("Field chuỗi nhiều dòng nên thành mảng, got %#v", stack.Value)
+	}
+	want := `[Worker] panic: boom job=sync stack=["main.run()","\tmain.go:12"] lines="[\"goroutine 1 [running]:\",\"main.main()\"]"`
+	if got.Message != want {
+		t.Errorf("Message = %q, want %q", got.Message, want)
+	}
+	if got.Text != "panic: boom" {
+		t.Errorf("Text nên chỉ giữ dòng đầu của thông điệp gốc, got %q", got.Text)
+	}
+	if fields[1].Value != "main.run()\n\tmain.go:12\n" {
+		t.Error("Field gốc không được bị sửa")
+	}
+
+	mem.Reset()
+	single := &Entry{Level: InfoLevel, Message: "ok n=1", Fields: []Field{{Key: "n", Type: Int64Type, Integer: 1}}}
+	h.LogEntry(single)
+	if got := mem.Entries()[0]; got.Message != "ok n=1" || len(got.Fields) != 1 {
+		t.Errorf("Entry một dòng nên giữ nguyên, got %+v", got)
+	}
+
+	mem.Reset()
+	NewFoldHandler(mem, FoldOptions{Mode: FoldField, Field: "trace"}).Log(ErrorLevel, "a\nb")
+	if got := mem.Entries()[0]; got.Message != `a trace=["b"]` || !reflect.DeepEqual(got.Fields[0].Value, []string{"b"}) || got.Fields[0].Key != "trace" {
+		t.Errorf("Log() = %+v", got)
+	}
+
+	// Phần field cuối thông điệp không khớp: chỉ thêm field chứa các dòng
+	mem.Reset()
+	h.LogEntry(&Entry{Level: InfoLevel, Message: "a\nb custom", Fields: []Field{{Key: "n", Type: Int64Type, Integer: 1}}})
+	if got := mem.Entries()[0]; got.Message != `a lines="[\"b custom\"]"` || len(got.Fields) != 2 {
+		t.Errorf("LogEntry() = %+v", got)
+	}
+}
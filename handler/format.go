@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format là định dạng dòng log mà FileHandler ghi ra.
+type Format int
+
+const (
+	// TextFormat ghi dòng "timestamp [LEVEL] message" (mặc định).
+	TextFormat Format = iota
+
+	// JSONFormat ghi mỗi entry thành một object JSON trên một dòng, gồm time (RFC 3339), level,
+	// context, thông điệp gốc trong message và các field có cấu trúc, phù hợp cho các hệ thống thu
+	// thập log (VD: Loki, Elasticsearch).
+	JSONFormat
+
+	// CommonLogFormat ghi access log theo Common Log Format của Apache/Nginx từ các field của
+	// HTTP middleware (remote_ip, method, path, proto, status, bytes). Entry không có field
+	// method được ghi như TextFormat.
+	CommonLogFormat
+
+	// CombinedLogFormat ghi access log theo Combined Log Format: Common Log Format cùng
+	// referer và user_agent, tương thích với các công cụ phân tích access log (VD: GoAccess, AWStats).
+	CombinedLogFormat
+
+	// CEFFormat ghi mỗi entry theo ArcSight Common Event Format, để gửi thẳng log bảo mật đến
+	// SIEM (VD: ArcSight, Splunk) mà không cần lớp chuyển đổi (xem SetDevice).
+	CEFFormat
+
+	// LEEFFormat ghi mỗi entry theo IBM QRadar Log Event Extended Format 1.0 (xem SetDevice).
+	LEEFFormat
+)
+
+// Các field key mà CommonLogFormat và CombinedLogFormat đọc, trùng với field của package middleware.
+const (
+	accessRemoteIP  = "remote_ip"
+	accessMethod    = "method"
+	accessPath      = "path"
+	accessProto     = "proto"
+	accessStatus    = "status"
+	accessBytes     = "bytes"
+	accessReferer   = "referer"
+	accessUserAgent = "user_agent"
+)
+
+// accessTimeLayout là định dạng thời gian của Common Log Format.
+const accessTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// ReservedFieldPrefix là tiền tố được thêm vào key của field trùng với key mà định dạng có cấu
+// trúc dùng cho thuộc tính của entry (VD: field "time" được ghi thành "fields.time" trong
+// JSONFormat), để field không ghi đè hoặc bị bỏ.
+const ReservedFieldPrefix = "fields."
+
+// jsonKeys là các key JSONFormat dùng cho thuộc tính của entry.
+var jsonKeys = []string{"time", "level", "context", "message"}
+
+// String trả về tên của định dạng dùng trong cấu hình.
+//
+// Trả về:
+//   - string: "text", "json", "common", "combined", "cef" hoặc "leef"
+func (f Format) String() string {
+	switch f {
+	case JSONFormat:
+		return "json"
+	case CommonLogFormat:
+		return "common"
+	case CombinedLogFormat:
+		return "combined"
+	case CEFFormat:
+		return "cef"
+	case LEEFFormat:
+		return "leef"
+	default:
+		return "text"
+	}
+}
+
+// ParseFormat chuyển tên định dạng thành Format, không phân biệt hoa thường.
+//
+// Tham số:
+//   - s: string - tên định dạng: "text", "json", "common", "combined", "cef" hoặc "leef" (rỗng = "text")
+//
+// Trả về:
+//   - Format: định dạng tương ứng
+//   - error: lỗi nếu tên không hợp lệ
+//
+// Ví dụ:
+//
+//	format, err := handler.ParseFormat("json") // JSONFormat
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "text":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	case "common":
+		return CommonLogFormat, nil
+	case "combined":
+		return CombinedLogFormat, nil
+	case "cef":
+		return CEFFormat, nil
+	case "leef":
+		return LEEFFormat, nil
+	default:
+		return TextFormat, fmt.Errorf("invalid log format: %q", s)
+	}
+}
+
+// SetFormat chọn định dạng của các dòng log được ghi sau lời gọi. Method này là thread-safe.
+//
+// Tham số:
+//   - format: Format - định dạng dòng log
+//
+// Ví dụ:
+//
+//	fileHandler.SetFormat(handler.JSONFormat)
+//	// {"time":"2026-01-02T15:04:05.123+07:00","level":"INFO","context":"Order","message":"created","order_id":42}
+func (a *FileHandler) SetFormat(format Format) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.format = format
+}
+
+// Append nối dòng log của entry theo định dạng vào cuối dst, giống dòng FileHandler ghi ra
+// (header của CEFFormat và LEEFFormat dùng Device mặc định), để công cụ bên ngoài (VD: golden
+// file trong test) định dạng entry mà không cần ghi file.
+//
+// Tham số:
+//   - dst: []byte - buffer cần nối vào
+//   - entry: *Entry - log entry cần định dạng
+//
+// Trả về:
+//   - []byte: dst đã nối thêm dòng log, kết thúc bằng '\n'
+func (f Format) Append(dst []byte, entry *Entry) []byte {
+	return appendFormatted(dst, f, Device{}, entry)
+}
+
+// appendFormatted nối dòng log của entry theo định dạng vào cuối dst; device dùng cho header của
+// CEFFormat và LEEFFormat.
+func appendFormatted(dst []byte, format Format, device Device, entry *Entry) []byte {
+	switch format {
+	case JSONFormat:
+		return appendJSONLine(dst, entry)
+	case CommonLogFormat, CombinedLogFormat:
+		return appendAccessLine(dst, entry, format == CombinedLogFormat)
+	case CEFFormat:
+		return appendCEFLine(dst, entry, device)
+	case LEEFFormat:
+		return appendLEEFLine(dst, entry, device)
+	default:
+		return appendLine(dst, entry)
+	}
+}
+
+// appendAccessLine nối entry dạng Common/Combined Log Format vào cuối dst, VD:
+//
+//	203.0.113.7 - - [01/Mar/2024:12:00:00 +0000] "GET /orders HTTP/1.1" 200 512 "-" "curl/8.5.0"
+//
+// Entry không có field method (không phải access log) được ghi như TextFormat.
+func appendAccessLine(dst []byte, entry *Entry, combined bool) []byte {
+	method, ok := accessField(entry, accessMethod)
+	if !ok {
+		return appendLine(dst, entry)
+	}
+
+	dst = appendAccessValue(dst, entry, accessRemoteIP)
+	dst = append(dst, " - - ["...)
+	dst = entry.Time.AppendFormat(dst, accessTimeLayout)
+	dst = append(dst, "] \""...)
+	dst = appendAccessQuoted(dst, method)
+	if path, ok := accessField(entry, accessPath); ok {
+		dst = append(dst, ' ')
+		dst = appendAccessQuoted(dst, path)
+	}
+	if proto, ok := accessField(entry, accessProto); ok {
+		dst = append(dst, ' ')
+		dst = appendAccessQuoted(dst, proto)
+	}
+	dst = append(dst, "\" "...)
+	dst = appendAccessValue(dst, entry, accessStatus)
+	dst = append(dst, ' ')
+	if bytes, ok := accessField(entry, accessBytes); ok && bytes != "0" {
+		dst = append(dst, bytes...)
+	} else {
+		dst = append(dst, '-')
+	}
+	if combined {
+		for _, key := range []string{accessReferer, accessUserAgent} {
+			dst = append(dst, " \""...)
+			if value, ok := accessField(entry, key); ok {
+				dst = appendAccessQuoted(dst, value)
+			} else {
+				dst = append(dst, '-')
+			}
+			dst = append(dst, '"')
+		}
+	}
+	return append(dst, '\n')
+}
+
+// accessField trả về giá trị dạng chuỗi của field key trong entry.
+//
+// Trả về:
+//   - string: giá trị của field
+//   - bool: false nếu entry không có field key hoặc giá trị rỗng
+func accessField(entry *Entry, key string) (string, bool) {
+	for _, f := range entry.Fields {
+		if f.Key != key {
+			continue
+		}
+		if f.Type == StringType {
+			return f.Str, f.Str != ""
+		}
+		value := fmt.Sprint(f.Interface())
+		return value, value != ""
+	}
+	return "", false
+}
+
+// appendAccessValue nối giá trị của field key vào cuối dst, hoặc "-" nếu không có.
+func appendAccessValue(dst []byte, entry *Entry, key string) []byte {
+	value, ok := accessField(entry, key)
+	if !ok {
+		return append(dst, '-')
+	}
+	return appendAccessQuoted(dst, value)
+}
+
+// appendAccessQuoted nối value vào cuối dst, escape dấu nháy kép, dấu gạch chéo ngược và ký tự
+// điều khiển như Apache để mỗi bản ghi nằm trên một dòng.
+func appendAccessQuoted(dst []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c < 0x20 || c == 0x7f:
+			dst = append(dst, fmt.Sprintf("\\x%02x", c)...)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// appendJSONLine nối entry dạng object JSON một dòng vào cuối dst. Thông điệp được ghi không kèm
+// context và field vì hai phần này đã có key riêng; field trùng tên với key của entry được ghi với
+// ReservedFieldPrefix để các key của object là duy nhất.
+func appendJSONLine(dst []byte, entry *Entry) []byte {
+	dst = append(dst, `{"time":"`...)
+	dst = entry.Time.AppendFormat(dst, time.RFC3339Nano)
+	dst = append(dst, `","level":"`...)
+	dst = append(dst, entry.Level.String()...)
+	dst = append(dst, '"')
+	if context := entry.Context(); context != "" {
+		dst = append(dst, `,"context":`...)
+		dst = appendJSONValue(dst, context)
+	}
+	dst = append(dst, `,"message":`...)
+	dst = appendJSONValue(dst, entry.text())
+
+	limits := Limits{}.withDefaults()
+	for _, f := range entry.Fields {
+		dst = append(dst, ',')
+		dst = appendJSONValue(dst, fieldKey(f.Key, jsonKeys))
+		dst = append(dst, ':')
+		dst = appendJSONValue(dst, normalize(f.Interface(), 0, limits))
+	}
+	return append(dst, "}\n"...)
+}
+
+// fieldKey trả về key của field, thêm ReservedFieldPrefix nếu key trùng với một trong reserved.
+func fieldKey(key string, reserved []string) string {
+	for _, r := range reserved {
+		if key == r {
+			return ReservedFieldPrefix + key
+		}
+	}
+	return key
+}
+
+// appendJSONValue nối giá trị đã mã hóa JSON vào cuối dst, hoặc chuỗi fmt.Sprint của giá trị
+// nếu không mã hóa được (VD: NaN).
+func appendJSONValue(dst []byte, value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(dst, data...)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"": TextFormat, "text": TextFormat, "JSON": JSONFormat, "common": CommonLogFormat, "Combined": CombinedLogFormat}
+	for name, want := range tests {
+		got, err := ParseFormat(name)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", name, got, err, want)
+		}
+		if name != "" && !strings.EqualFold(got.String(), name) {
+			t.Errorf("Format.String() = %q, want %q", got.String(), name)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat nên báo lỗi với định dạng không hợp lệ")
+	}
+}
+
+func TestFileHandler_JSONFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	h.SetFormat(JSONFormat)
+
+	entry := &Entry{
+		Time:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Level:   ErrorLevel,
+		Message: `[Order] "failed" order_id=42 tags=[a b] level="bị bỏ"`,
+		Text:    `"failed"`,
+		Fields: []Field{
+			{Key: "order_id", Type: Int64Type, Integer: 42},
+			{Key: "tags", Value: []string{"a", "b"}},
+			{Key: "level", Type: StringType, Str: "bị bỏ"},
+		},
+	}
+	if err := h.LogEntry(entry); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+	h.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Dòng log không phải JSON hợp lệ: %v\n%s", err, data)
+	}
+	if got["level"] != "ERROR" || got["context"] != "Order" || got["message"] != entry.Text || got["time"] != "2024-03-01T12:00:00Z" {
+		t.Errorf("time, level, context hoặc message không đúng, got %v", got)
+	}
+	if got["fields.level"] != "bị bỏ" {
+		t.Errorf("Field trùng key của entry nên được ghi với tiền tố %q, got %v", ReservedFieldPrefix, got)
+	}
+	if got["order_id"] != float64(42) || len(got["tags"].([]interface{})) != 2 {
+		t.Errorf("Field có cấu trúc không được ghi đúng, got %v", got)
+	}
+}
+
+func TestFileHandler_AccessLogFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	h.SetFormat(CombinedLogFormat)
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	_ = h.LogEntry(&Entry{Time: at, Level: InfoLevel, Message: "[HTTP] HTTP request", Fields: []Field{
+		{Key: "remote_ip", Type: StringType, Str: "203.0.113.7"},
+		{Key: "method", Type: StringType, Str: "GET"},
+		{Key: "path", Type: StringType, Str: "/orders"},
+		{Key: "proto", Type: StringType, Str: "HTTP/1.1"},
+		{Key: "status", Value: 200},
+		{Key: "bytes", Value: int64(512)},
+		{Key: "user_agent", Type: StringType, Str: `curl/8.5.0 "test"`},
+		{Key: "latency", Type: DurationType, Integer: int64(time.Millisecond)},
+	}})
+	h.SetFormat(CommonLogFormat)
+	_ = h.LogEntry(&Entry{Time: at, Level: WarningLevel, Message: "[HTTP] HTTP request", Fields: []Field{
+		{Key: "remote_ip", Type: StringType, Str: "203.0.113.7"},
+		{Key: "method", Type: StringType, Str: "POST"},
+		{Key: "path", Type: StringType, Str: "/orders"},
+		{Key: "status", Value: 404},
+		{Key: "bytes", Value: int64(0)},
+	}})
+	_ = h.LogEntry(&Entry{Time: at, Level: InfoLevel, Message: "[App] started"})
+	h.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `203.0.113.7 - - [01/Mar/2024:12:00:00 +0000] "GET /orders HTTP/1.1" 200 512 "-" "curl/8.5.0 \"test\""` + "\n" +
+		`203.0.113.7 - - [01/Mar/2024:12:00:00 +0000] "POST /orders" 404 -` + "\n" +
+		"2024/03/01 12:00:00 [INFO] [App] started\n"
+	if string(data) != want {
+		t.Errorf("Output = %q, want %q", data, want)
+	}
+}
+
+func TestFormat_Append(t *testing.T) {
+	entry := &Entry{Time: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Level: InfoLevel, Message: "[Order] created"}
+	if got := string(TextFormat.Append([]byte("> "), entry)); got != "> 2024/03/01 12:00:00 [INFO] [Order] created\n" {
+		t.Errorf("TextFormat.Append() = %q", got)
+	}
+	if got := string(CEFFormat.Append(nil, entry)); !strings.HasPrefix(got, "CEF:0|go.fork.vn|log|1.0|") {
+		t.Errorf("CEFFormat.Append() = %q", got)
+	}
+
+	// Entry không có Text (VD: không do Logger tạo) dùng Message đã bỏ context và field
+	entry = &Entry{Time: entry.Time, Level: InfoLevel, Message: "[Order] created order_id=42", Fields: []Field{{Key: "order_id", Type: Int64Type, Integer: 42}}}
+	want := `{"time":"2024-03-01T12:00:00Z","level":"INFO","context":"Order","message":"created","order_id":42}` + "\n"
+	if got := string(JSONFormat.Append(nil, entry)); got != want {
+		t.Errorf("JSONFormat.Append() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,343 @@
+// Package formattest cung cấp bộ kiểm tra tuân thủ cho các handler và định dạng log của bên
+// thứ ba, tương tự testing/fstest của thư viện chuẩn.
+//
+// Run kiểm tra cách handler xử lý cấp độ log, thoát ký tự đặc biệt, cắt bớt giá trị lớn hoặc
+// lồng nhau quá sâu, và ngữ nghĩa của Close:
+//
+//	func TestMyHandler(t *testing.T) {
+//	    formattest.Run(t, formattest.Harness{
+//	        New: func(t *testing.T) (handler.Handler, func() []byte) {
+//	            var buf bytes.Buffer
+//	            return myhandler.New(&buf), buf.Bytes
+//	        },
+//	        Decode: formattest.DecodeJSON,
+//	    })
+//	}
+package formattest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// Harness mô tả handler cần kiểm tra.
+type Harness struct {
+	// New tạo một handler mới cùng hàm trả về toàn bộ output mà handler đã ghi. Output chỉ được
+	// đọc sau khi handler được đóng. Bắt buộc.
+	New func(t *testing.T) (handler.Handler, func() []byte)
+
+	// Decode phân tích một dòng output thành Record. Khi được đặt, handler phải ghi mỗi entry
+	// trên đúng một dòng kèm các field có cấu trúc (qua handler.EntryHandler), và thông điệp,
+	// field được so sánh chính xác sau khi phân tích. nil = định dạng văn bản, chỉ kiểm tra
+	// output có chứa thông điệp
+	Decode func(line []byte) (Record, error)
+}
+
+// Record là một entry được phân tích từ output của handler.
+type Record struct {
+	Level   string                 // Tên cấp độ, VD: "INFO"
+	Context string                 // Context của logger, rỗng nếu entry không có context
+	Message string                 // Thông điệp của entry
+	Fields  map[string]interface{} // Các field có cấu trúc, giá trị theo kiểu của encoding/json
+}
+
+// DecodeJSON phân tích một dòng JSON theo định dạng handler.JSONFormat: key "level", "context" và
+// "message" chứa cấp độ, context và thông điệp, các key còn lại (trừ "time") là field; key có
+// handler.ReservedFieldPrefix của field trùng tên được bỏ tiền tố.
+//
+// Tham số:
+//   - line: []byte - một dòng output
+//
+// Trả về:
+//   - Record: entry đã được phân tích
+//   - error: lỗi nếu dòng không phải object JSON
+func DecodeJSON(line []byte) (Record, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal(line, &object); err != nil {
+		return Record{}, err
+	}
+	record := Record{Fields: make(map[string]interface{})}
+	for key, value := range object {
+		switch key {
+		case "time":
+		case "level":
+			record.Level, _ = value.(string)
+		case "context":
+			record.Context, _ = value.(string)
+		case "message":
+			record.Message, _ = value.(string)
+		default:
+			switch name, ok := strings.CutPrefix(key, handler.ReservedFieldPrefix); name {
+			case "time", "level", "context", "message":
+				if ok {
+					key = name
+				}
+			}
+			record.Fields[key] = value
+		}
+	}
+	return record, nil
+}
+
+// Run chạy bộ kiểm tra tuân thủ với handler do h.New tạo. Mỗi nhóm kiểm tra là một subtest
+// dùng một handler mới:
+//   - Levels: entry ở mọi cấp độ được ghi kèm tên cấp độ, trừ cấp độ mà handler báo không
+//     xử lý qua handler.LevelEnabler
+//   - Escaping: dấu nháy, xuống dòng, tab, ký tự điều khiển và Unicode không làm hỏng output
+//   - Truncation: giá trị lồng nhau quá sâu, slice rất lớn và con trỏ vòng được cắt bớt thay
+//     vì làm treo hoặc phình output
+//   - Close: Close ghi hết entry đã nhận, gọi lại Close hoặc ghi log sau Close không panic
+//
+// Tham số:
+//   - t: *testing.T - test đang chạy
+//   - h: Harness - handler cần kiểm tra
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+	if h.New == nil {
+		t.Fatal("formattest: Harness.New is required")
+	}
+	t.Run("Levels", func(t *testing.T) { testLevels(t, h) })
+	t.Run("Escaping", func(t *testing.T) { testEscaping(t, h) })
+	t.Run("Truncation", func(t *testing.T) { testTruncation(t, h) })
+	t.Run("Close", func(t *testing.T) { testClose(t, h) })
+}
+
+// levels là các cấp độ được kiểm tra.
+var levels = []handler.Level{handler.DebugLevel, handler.InfoLevel, handler.WarningLevel, handler.ErrorLevel, handler.FatalLevel}
+
+// newHandler tạo handler của h và trả về hàm đóng handler rồi đọc output.
+func newHandler(t *testing.T, h Harness) (handler.Handler, func() []byte) {
+	t.Helper()
+	hd, output := h.New(t)
+	if hd == nil || output == nil {
+		t.Fatal("formattest: Harness.New returned a nil handler or output function")
+	}
+	var once sync.Once
+	closeHandler := func() {
+		once.Do(func() {
+			if err := hd.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		})
+	}
+	t.Cleanup(closeHandler)
+	return hd, func() []byte {
+		closeHandler()
+		return output()
+	}
+}
+
+// lines tách output thành các dòng không rỗng.
+func lines(output []byte) [][]byte {
+	var out [][]byte
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// decodeOne phân tích output của đúng một entry.
+func decodeOne(t *testing.T, h Harness, output []byte) Record {
+	t.Helper()
+	ls := lines(output)
+	if len(ls) != 1 {
+		t.Fatalf("Mỗi entry nên được ghi trên đúng một dòng, got %d dòng:\n%s", len(ls), output)
+	}
+	record, err := h.Decode(ls[0])
+	if err != nil {
+		t.Fatalf("Decode() error = %v\n%s", err, ls[0])
+	}
+	return record
+}
+
+func testLevels(t *testing.T, h Harness) {
+	for _, level := range levels {
+		hd, output := newHandler(t, h)
+		marker := "level check " + strings.ToLower(level.String())
+		if err := hd.Log(level, "%s", marker); err != nil {
+			t.Errorf("Log(%v) error = %v", level, err)
+		}
+		out := output()
+
+		if enabler, ok := hd.(handler.LevelEnabler); ok && !enabler.Enabled(level) {
+			if bytes.Contains(out, []byte(marker)) {
+				t.Errorf("Handler báo không xử lý cấp độ %v nhưng vẫn ghi entry: %s", level, out)
+			}
+			continue
+		}
+		if h.Decode == nil {
+			if !bytes.Contains(out, []byte(marker)) || !bytes.Contains(out, []byte(level.String())) {
+				t.Errorf("Output nên chứa thông điệp và cấp độ %v, got %q", level, out)
+			}
+			continue
+		}
+		if record := decodeOne(t, h, out); record.Level != level.String() || record.Message != marker {
+			t.Errorf("Entry %v được phân tích thành level=%q message=%q", level, record.Level, record.Message)
+		}
+	}
+}
+
+// awkward là chuỗi chứa các ký tự thường làm hỏng định dạng log.
+const awkward = "quote=\" backslash=\\ newline=\n cr=\r tab=\t nul=\x00 bell=\x07 unicode=Tiếng Việt ✓"
+
+func testEscaping(t *testing.T, h Harness) {
+	hd, output := newHandler(t, h)
+	entry := &handler.Entry{
+		Time:    time.Now(),
+		Level:   handler.InfoLevel,
+		Message: "escaping " + awkward,
+		Fields: []handler.Field{
+			{Key: "value", Type: handler.StringType, Str: awkward},
+			{Key: "json", Type: handler.StringType, Str: `{"nested":"object"}`},
+		},
+	}
+	if err := handler.Dispatch(hd, entry); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+	out := output()
+
+	if h.Decode == nil {
+		if !bytes.Contains(out, []byte(entry.Message)) {
+			t.Errorf("Output nên chứa nguyên thông điệp, got %q", out)
+		}
+		return
+	}
+	record := decodeOne(t, h, out)
+	if record.Message != entry.Message {
+		t.Errorf("Thông điệp sau khi phân tích = %q, want %q", record.Message, entry.Message)
+	}
+	for _, f := range entry.Fields {
+		if got := record.Fields[f.Key]; got != f.Str {
+			t.Errorf("Field %s sau khi phân tích = %#v, want %q", f.Key, got, f.Str)
+		}
+	}
+}
+
+// node là kiểu có thể tạo con trỏ vòng.
+type node struct {
+	Name string
+	Next *node
+}
+
+func testTruncation(t *testing.T, h Harness) {
+	deep := map[string]interface{}{"leaf": true}
+	for i := 0; i < 64; i++ {
+		deep = map[string]interface{}{"next": deep}
+	}
+	cycle := &node{Name: "cycle"}
+	cycle.Next = cycle
+	large := make([]int, 100000)
+
+	hd, output := newHandler(t, h)
+	entry := &handler.Entry{
+		Time:    time.Now(),
+		Level:   handler.InfoLevel,
+		Message: "truncation",
+		Fields: []handler.Field{
+			{Key: "deep", Value: deep},
+			{Key: "cycle", Value: cycle},
+			{Key: "large", Value: large},
+		},
+	}
+	done := make(chan error, 1)
+	go func() { done <- handler.Dispatch(hd, entry) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("LogEntry() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("LogEntry() không nên treo với giá trị lồng nhau hoặc con trỏ vòng")
+	}
+	out := output()
+
+	// Giá trị lớn phải được cắt bớt thay vì ghi nguyên vẹn (100000 phần tử)
+	if len(out) > 64<<10 {
+		t.Errorf("Output nên được cắt bớt theo giới hạn của handler.Limits, got %d bytes", len(out))
+	}
+	if h.Decode == nil {
+		return
+	}
+	record := decodeOne(t, h, out)
+	for _, key := range []string{"deep", "cycle"} {
+		if !contains(record.Fields[key], handler.TruncatedValue) {
+			t.Errorf("Field %s nên được cắt bớt bằng %q, got %v", key, handler.TruncatedValue, record.Fields[key])
+		}
+	}
+	if values, ok := record.Fields["large"].([]interface{}); !ok || len(values) > handler.DefaultMaxFieldElements+1 {
+		t.Errorf("Field large nên giữ tối đa %d phần tử, got %d", handler.DefaultMaxFieldElements, len(values))
+	}
+}
+
+// contains kiểm tra value (đã được phân tích từ JSON) có chứa chuỗi s ở bất kỳ độ sâu nào.
+func contains(value interface{}, s string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == s
+	case map[string]interface{}:
+		for _, child := range v {
+			if contains(child, s) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if contains(child, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func testClose(t *testing.T, h Harness) {
+	hd, output := h.New(t)
+	if hd == nil || output == nil {
+		t.Fatal("formattest: Harness.New returned a nil handler or output function")
+	}
+	const n = 100
+	for i := 0; i < n; i++ {
+		if err := hd.Log(handler.ErrorLevel, "before close %03d", i); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+	if err := hd.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	out := output()
+	for i := 0; i < n; i++ {
+		if !bytes.Contains(out, []byte(fmt.Sprintf("before close %03d", i))) {
+			t.Fatalf("Close() nên ghi hết các entry đã nhận, thiếu entry %d", i)
+		}
+	}
+
+	// Gọi lại Close và ghi log sau Close có thể trả về lỗi nhưng không được panic
+	mustNotPanic(t, "Close() lần hai", func() { hd.Close() })
+	mustNotPanic(t, "Log() sau Close", func() { hd.Log(handler.InfoLevel, "after close") })
+	mustNotPanic(t, "LogEntry() sau Close", func() {
+		handler.Dispatch(hd, &handler.Entry{Time: time.Now(), Level: handler.InfoLevel, Message: "after close"})
+	})
+	if after := output(); bytes.Contains(after, []byte("after close")) {
+		t.Errorf("Handler đã đóng không nên ghi thêm entry, got %q", after)
+	}
+}
+
+// mustNotPanic báo lỗi nếu fn panic.
+func mustNotPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("%s không nên panic, got %v", name, r)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,60 @@
+package formattest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// newFileHarness tạo Harness cho FileHandler với định dạng đã cho.
+func newFileHarness(format handler.Format) func(t *testing.T) (handler.Handler, func() []byte) {
+	return func(t *testing.T) (handler.Handler, func() []byte) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		h, err := handler.NewFileHandler(path, 0)
+		if err != nil {
+			t.Fatalf("NewFileHandler() error = %v", err)
+		}
+		h.SetFormat(format)
+		return h, func() []byte {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return data
+		}
+	}
+}
+
+func TestRun_FileHandlerText(t *testing.T) {
+	Run(t, Harness{New: newFileHarness(handler.TextFormat)})
+}
+
+func TestRun_FileHandlerJSON(t *testing.T) {
+	Run(t, Harness{New: newFileHarness(handler.JSONFormat), Decode: DecodeJSON})
+}
+
+func TestRun_AsyncHandler(t *testing.T) {
+	newFile := newFileHarness(handler.JSONFormat)
+	Run(t, Harness{
+		New: func(t *testing.T) (handler.Handler, func() []byte) {
+			h, output := newFile(t)
+			return handler.NewAsyncHandler(h, 2, 16), output
+		},
+		Decode: DecodeJSON,
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	record, err := DecodeJSON([]byte(`{"time":"2024/03/01 12:00:00","level":"WARNING","message":"disk full","free":0}`))
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if record.Level != "WARNING" || record.Message != "disk full" || len(record.Fields) != 1 || record.Fields["free"] != float64(0) {
+		t.Errorf("DecodeJSON() = %+v", record)
+	}
+	if _, err := DecodeJSON([]byte("2024/03/01 12:00:00 [INFO] text")); err == nil {
+		t.Error("DecodeJSON nên báo lỗi với dòng không phải JSON")
+	}
+}
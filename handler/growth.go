@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// growthWindow là độ dài mỗi cửa sổ đo tốc độ ghi của FileHandler.
+const growthWindow = time.Minute
+
+// GrowthAlert cấu hình cảnh báo khi file log tăng kích thước quá nhanh trong thời gian dài,
+// giúp phát hiện vòng lặp ghi debug mất kiểm soát trước khi đĩa bị đầy.
+type GrowthAlert struct {
+	MaxRate int64             // Tốc độ ghi tối đa (byte/phút), 0 để tắt cảnh báo
+	Period  time.Duration     // Thời gian tốc độ ghi phải vượt MaxRate liên tục trước khi cảnh báo
+	Notify  func(GrowthEvent) // Hàm nhận cảnh báo, nil để ghi cảnh báo ra stderr
+}
+
+// GrowthEvent mô tả một cảnh báo tốc độ tăng trưởng của file log.
+type GrowthEvent struct {
+	Path  string    // Đường dẫn file log
+	Rate  int64     // Tốc độ ghi của cửa sổ đo gần nhất (byte/phút)
+	Since time.Time // Thời điểm tốc độ ghi bắt đầu vượt MaxRate
+}
+
+// String trả về mô tả cảnh báo.
+//
+// Trả về:
+//   - string: mô tả dạng "file log app.log tăng 52428800 byte/phút liên tục từ ..."
+func (e GrowthEvent) String() string {
+	return fmt.Sprintf("file log %s tăng %d byte/phút liên tục từ %s", e.Path, e.Rate, e.Since.Format(TimeLayout))
+}
+
+// growthTracker đo tốc độ ghi theo các cửa sổ liên tiếp và phát hiện tốc độ vượt ngưỡng kéo dài.
+type growthTracker struct {
+	alert    GrowthAlert
+	window   time.Duration
+	start    time.Time // Thời điểm bắt đầu cửa sổ hiện tại
+	bytes    int64     // Số byte đã ghi trong cửa sổ hiện tại
+	exceeded time.Time // Thời điểm bắt đầu vượt ngưỡng, zero nếu không vượt
+	alerted  bool      // Đã cảnh báo cho đợt vượt ngưỡng hiện tại
+	now      func() time.Time
+}
+
+// add ghi nhận n byte được ghi tại now và trả về cảnh báo nếu tốc độ ghi đã vượt ngưỡng
+// trong khoảng thời gian Period.
+func (g *growthTracker) add(path string, now time.Time, n int64) *GrowthEvent {
+	if g.start.IsZero() {
+		g.start = now
+	}
+
+	var event *GrowthEvent
+	if elapsed := now.Sub(g.start); elapsed >= g.window {
+		// Khoảng thời gian không ghi giữa các cửa sổ làm giảm tốc độ đo được
+		rate := int64(float64(g.bytes) * float64(time.Minute) / float64(elapsed))
+		if rate > g.alert.MaxRate {
+			if g.exceeded.IsZero() {
+				g.exceeded = g.start
+			}
+			if !g.alerted && now.Sub(g.exceeded) >= g.alert.Period {
+				g.alerted = true
+				event = &GrowthEvent{Path: path, Rate: rate, Since: g.exceeded}
+			}
+		} else {
+			g.exceeded = time.Time{}
+			g.alerted = false
+		}
+		g.start = now
+		g.bytes = 0
+	}
+	g.bytes += n
+	return event
+}
+
+// SetGrowthAlert bật hoặc thay đổi cảnh báo tốc độ tăng trưởng của file log.
+//
+// Tốc độ ghi được đo theo từng phút. Khi tốc độ vượt MaxRate liên tục trong Period, một cảnh
+// báo được gửi đến Notify (hoặc ghi ra stderr) một lần cho đến khi tốc độ giảm xuống dưới
+// ngưỡng. Notify được gọi sau khi handler nhả lock nên có thể ghi log. Method này là thread-safe.
+//
+// Tham số:
+//   - alert: GrowthAlert - cấu hình cảnh báo; MaxRate <= 0 để tắt
+//
+// Ví dụ:
+//
+//	// Cảnh báo khi file tăng hơn 50MB/phút trong 5 phút
+//	fileHandler.SetGrowthAlert(handler.GrowthAlert{MaxRate: 50 << 20, Period: 5 * time.Minute})
+func (a *FileHandler) SetGrowthAlert(alert GrowthAlert) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if alert.MaxRate <= 0 {
+		a.growth = nil
+		return
+	}
+	a.growth = &growthTracker{alert: alert, window: growthWindow, now: time.Now}
+}
+
+// notifyGrowth gửi cảnh báo tốc độ tăng trưởng đến Notify hoặc ra stderr.
+func (a *FileHandler) notifyGrowth(notify func(GrowthEvent), event GrowthEvent) {
+	if notify != nil {
+		notify(event)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Cảnh báo: %s\n", event)
+}
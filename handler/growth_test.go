@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGrowthTracker_SustainedRate(t *testing.T) {
+	g := &growthTracker{alert: GrowthAlert{MaxRate: 1000, Period: 3 * time.Minute}, window: time.Minute}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// 2000 byte/phút trong 3 phút: cảnh báo ở cuối phút thứ ba, chỉ một lần
+	var events []*GrowthEvent
+	for minute := 0; minute <= 5; minute++ {
+		for i := 0; i < 4; i++ {
+			now := start.Add(time.Duration(minute)*time.Minute + time.Duration(i)*15*time.Second)
+			if e := g.add("app.log", now, 500); e != nil {
+				events = append(events, e)
+			}
+		}
+	}
+	if len(events) != 1 {
+		t.Fatalf("Nên cảnh báo đúng một lần cho đợt vượt ngưỡng, got %d", len(events))
+	}
+	if events[0].Rate != 2000 || !events[0].Since.Equal(start) || events[0].Path != "app.log" {
+		t.Errorf("Cảnh báo không đúng, got %+v", events[0])
+	}
+}
+
+func TestGrowthTracker_ResetsBelowRate(t *testing.T) {
+	g := &growthTracker{alert: GrowthAlert{MaxRate: 1000, Period: 2 * time.Minute}, window: time.Minute}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Phút đầu vượt ngưỡng, phút thứ hai dưới ngưỡng, phút thứ ba vượt lại: chưa đủ 2 phút liên tục
+	sizes := []int64{2000, 100, 2000, 2000}
+	for minute, size := range sizes {
+		if e := g.add("app.log", start.Add(time.Duration(minute)*time.Minute), size); e != nil {
+			t.Fatalf("Không nên cảnh báo khi tốc độ không vượt ngưỡng liên tục, got %+v at minute %d", e, minute)
+		}
+	}
+
+	// Khoảng lặng dài làm tốc độ đo được giảm xuống
+	if e := g.add("app.log", start.Add(time.Hour), 10); e != nil {
+		t.Errorf("Khoảng không ghi nên làm giảm tốc độ đo được, got %+v", e)
+	}
+}
+
+func TestFileHandler_GrowthAlert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "growth.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	var events []GrowthEvent
+	h.SetGrowthAlert(GrowthAlert{
+		MaxRate: 100,
+		Notify: func(e GrowthEvent) {
+			events = append(events, e)
+			// Notify được gọi sau khi nhả lock nên có thể ghi vào chính handler
+			_ = h.Log(WarningLevel, e.String())
+		},
+	})
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	h.growth.now = func() time.Time { return now }
+
+	_ = h.Log(DebugLevel, strings.Repeat("x", 200))
+	now = now.Add(time.Minute)
+	_ = h.Log(DebugLevel, "next window")
+
+	if len(events) != 1 || events[0].Path != path {
+		t.Fatalf("Nên nhận một cảnh báo cho file, got %+v", events)
+	}
+	entries, err := h.Tail(1)
+	if err != nil || len(entries) != 1 || !strings.Contains(entries[0].Message, "byte/phút liên tục") {
+		t.Errorf("Cảnh báo nên được ghi vào file qua Notify, got %v (err %v)", entries, err)
+	}
+
+	h.SetGrowthAlert(GrowthAlert{})
+	if h.growth != nil {
+		t.Error("MaxRate = 0 nên tắt cảnh báo")
+	}
+}
@@ -1,5 +1,10 @@
 package handler
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Level đại diện cho cấp độ nghiêm trọng của một log entry.
 //
 // Các cấp độ được sắp xếp từ thấp đến cao nhất, cho phép lọc dựa trên
@@ -52,6 +57,35 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel chuyển tên cấp độ log thành Level, không phân biệt hoa thường.
+//
+// Tham số:
+//   - s: string - tên cấp độ (VD: "DEBUG", "info", "Warning")
+//
+// Trả về:
+//   - Level: cấp độ tương ứng
+//   - error: lỗi nếu tên cấp độ không hợp lệ
+//
+// Ví dụ:
+//
+//	level, err := handler.ParseLevel("warning") // WarningLevel
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "DEBUG":
+		return DebugLevel, nil
+	case "INFO":
+		return InfoLevel, nil
+	case "WARNING", "WARN":
+		return WarningLevel, nil
+	case "ERROR":
+		return ErrorLevel, nil
+	case "FATAL":
+		return FatalLevel, nil
+	default:
+		return DebugLevel, fmt.Errorf("invalid log level: %q", s)
+	}
+}
+
 // Handler là interface mà tất cả các log handler phải triển khai.
 //
 // Handler chịu trách nhiệm xử lý các log entry và ghi chúng vào
@@ -78,3 +112,50 @@ type Handler interface {
 	//   - error: một lỗi nếu dọn dẹp thất bại
 	Close() error
 }
+
+// LevelEnabler là interface tùy chọn cho các handler tự lọc theo cấp độ.
+//
+// Logger chỉ định dạng thông điệp và mã hóa các field khi ít nhất một handler chấp nhận
+// cấp độ của log entry, và không gửi entry đến handler từ chối cấp độ đó. Handler không
+// triển khai LevelEnabler được xem là chấp nhận mọi cấp độ.
+type LevelEnabler interface {
+	// Enabled kiểm tra handler có xử lý log entry ở cấp độ đã cho hay không.
+	//
+	// Tham số:
+	//   - level: Level - cấp độ của log entry
+	//
+	// Trả về:
+	//   - bool: true nếu handler sẽ ghi entry ở cấp độ này
+	Enabled(level Level) bool
+}
+
+// Enabled kiểm tra handler có chấp nhận cấp độ đã cho hay không.
+//
+// Handler bọc (có method Unwrap, VD: AsyncHandler) được kiểm tra theo handler bên trong khi
+// chính nó không triển khai LevelEnabler.
+//
+// Tham số:
+//   - h: Handler - handler cần kiểm tra
+//   - level: Level - cấp độ của log entry
+//
+// Trả về:
+//   - bool: false nếu handler (hoặc handler được bọc) triển khai LevelEnabler và từ chối cấp độ
+//
+// Ví dụ:
+//
+//	if handler.Enabled(h, handler.DebugLevel) {
+//	    h.Log(handler.DebugLevel, expensiveDump())
+//	}
+func Enabled(h Handler, level Level) bool {
+	for h != nil {
+		if e, ok := h.(LevelEnabler); ok {
+			return e.Enabled(level)
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			return true
+		}
+		h = w.Unwrap()
+	}
+	return true
+}
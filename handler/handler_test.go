@@ -72,3 +72,69 @@ func TestLevel_String_WithValues(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []Level{DebugLevel, InfoLevel, WarningLevel, ErrorLevel, FatalLevel} {
+		got, err := ParseLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", level.String(), got, err, level)
+		}
+	}
+
+	if got, err := ParseLevel(" warn "); err != nil || got != WarningLevel {
+		t.Errorf("ParseLevel(\" warn \") = %v, %v; want WARNING", got, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(\"verbose\") nên trả về lỗi")
+	}
+}
+
+// levelHandler chỉ chấp nhận các entry từ cấp độ min trở lên
+type levelHandler struct {
+	MockTestHandler
+	min Level
+}
+
+func (h *levelHandler) Enabled(level Level) bool {
+	return level >= h.min
+}
+
+func TestEnabled(t *testing.T) {
+	errorOnly := &levelHandler{min: ErrorLevel}
+	async := NewAsyncHandler(errorOnly, 1, 1)
+	defer async.Close()
+
+	tests := []struct {
+		name  string
+		h     Handler
+		level Level
+		want  bool
+	}{
+		{"handler không triển khai LevelEnabler", &MockTestHandler{}, DebugLevel, true},
+		{"handler từ chối cấp độ", errorOnly, InfoLevel, false},
+		{"handler chấp nhận cấp độ", errorOnly, ErrorLevel, true},
+		{"handler bọc dùng handler bên trong", async, InfoLevel, false},
+		{"stack có handler chấp nhận", NewStackHandler(errorOnly, &MockTestHandler{}), InfoLevel, true},
+		{"stack không có handler chấp nhận", NewStackHandler(errorOnly), InfoLevel, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(tt.h, tt.level); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackHandler_SkipsDisabledHandlers(t *testing.T) {
+	errorOnly := &levelHandler{min: ErrorLevel}
+	all := &MockTestHandler{}
+	stack := NewStackHandler(errorOnly, all)
+
+	if err := stack.Log(InfoLevel, "info"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if errorOnly.LogCalled || !all.LogCalled {
+		t.Errorf("Stack chỉ nên gửi entry đến handler chấp nhận cấp độ, got errorOnly=%v all=%v", errorOnly.LogCalled, all.LogCalled)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+)
+
+// HealthChecker là interface tùy chọn cho các handler tự báo cáo tình trạng hoạt động, VD:
+// file log đã đóng hoặc sink từ xa đang ghi thất bại.
+type HealthChecker interface {
+	// Health kiểm tra handler có đang ghi log bình thường hay không.
+	//
+	// Trả về:
+	//   - error: lý do handler không hoạt động bình thường, nil nếu bình thường
+	Health() error
+}
+
+// Saturator là interface tùy chọn cho các handler có hàng đợi giới hạn (VD: AsyncHandler).
+type Saturator interface {
+	// Saturation trả về tỷ lệ lấp đầy của hàng đợi.
+	//
+	// Trả về:
+	//   - float64: từ 0 (rỗng) đến 1 (đầy)
+	Saturation() float64
+}
+
+// Health kiểm tra tình trạng của handler và mọi handler được bọc (theo Unwrap).
+//
+// Tham số:
+//   - h: Handler - handler cần kiểm tra
+//
+// Trả về:
+//   - error: lỗi của các handler trong chuỗi triển khai HealthChecker, nil nếu tất cả bình thường
+//
+// Ví dụ:
+//
+//	if err := handler.Health(fileHandler); err != nil {
+//	    fmt.Fprintln(os.Stderr, "log file không khả dụng:", err)
+//	}
+func Health(h Handler) error {
+	var errs []error
+	for h != nil {
+		if c, ok := h.(HealthChecker); ok {
+			if err := c.Health(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return errors.Join(errs...)
+}
+
+// Saturation trả về tỷ lệ lấp đầy cao nhất của các hàng đợi trong chuỗi handler (theo Unwrap).
+//
+// Tham số:
+//   - h: Handler - handler cần kiểm tra
+//
+// Trả về:
+//   - float64: từ 0 đến 1, 0 nếu không có handler nào triển khai Saturator
+func Saturation(h Handler) float64 {
+	var saturation float64
+	for h != nil {
+		if s, ok := h.(Saturator); ok {
+			saturation = max(saturation, s.Saturation())
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return saturation
+}
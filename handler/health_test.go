@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	async := NewAsyncHandler(file, 1, 4)
+	if err := Health(async); err != nil {
+		t.Errorf("Handler đang hoạt động nên khỏe mạnh, got %v", err)
+	}
+	if Health(&MockTestHandler{}) != nil {
+		t.Error("Handler không triển khai HealthChecker nên được xem là khỏe mạnh")
+	}
+
+	_ = async.Close()
+	err = Health(async)
+	if !errors.Is(err, ErrAsyncHandlerClosed) || !strings.Contains(err.Error(), "đã đóng") {
+		t.Errorf("Health() nên báo cả async và file đã đóng, got %v", err)
+	}
+	if err := NewStackHandler(&MockTestHandler{}, file).Health(); err == nil {
+		t.Error("StackHandler.Health() nên báo lỗi của handler con")
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	rec := &slowRecorder{release: make(chan struct{})}
+	async := NewAsyncHandler(rec, 1, 4)
+	defer func() {
+		close(rec.release)
+		async.Close()
+	}()
+
+	if got := Saturation(NewFieldFilterHandler(async, FieldFilter{})); got != 0 {
+		t.Errorf("Hàng đợi rỗng nên có Saturation() = 0, got %v", got)
+	}
+	// Worker giữ entry đầu tiên, 4 entry tiếp theo lấp đầy hàng đợi
+	for i := 0; i < 5; i++ {
+		_ = async.Log(InfoLevel, "entry")
+	}
+	if got := Saturation(NewFieldFilterHandler(async, FieldFilter{})); got != 1 {
+		t.Errorf("Hàng đợi đầy nên có Saturation() = 1, got %v", got)
+	}
+	if Saturation(&MockTestHandler{}) != 0 {
+		t.Error("Handler không có hàng đợi nên có Saturation() = 0")
+	}
+}
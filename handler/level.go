@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"sync/atomic"
+)
+
+// LevelHandler bọc một handler với cấp độ tối thiểu riêng thay đổi được lúc chạy, VD: để bật
+// debug cho một sink khi điều tra sự cố mà không đổi cấp độ của logger. LevelHandler triển khai
+// Leveler và LevelEnabler nên logger không định dạng entry bị từ chối. Handler an toàn khi dùng
+// đồng thời.
+type LevelHandler struct {
+	handler Handler
+	level   atomic.Int32
+}
+
+// NewLevelHandler tạo handler chỉ chuyển entry từ level trở lên đến h.
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - level: Level - cấp độ tối thiểu ban đầu
+//
+// Trả về:
+//   - *LevelHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	alerting := handler.NewLevelHandler(slackHandler, handler.ErrorLevel)
+//	manager.AddHandler("alerting", alerting)
+//	_ = manager.SetHandlerLevel("alerting", handler.WarningLevel)
+func NewLevelHandler(h Handler, level Level) *LevelHandler {
+	l := &LevelHandler{handler: h}
+	l.level.Store(int32(level))
+	return l
+}
+
+// Level trả về cấp độ tối thiểu hiện tại.
+//
+// Trả về:
+//   - Level: cấp độ tối thiểu
+func (l *LevelHandler) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel thay đổi cấp độ tối thiểu cho các entry được ghi sau lời gọi. Method này là thread-safe.
+//
+// Tham số:
+//   - level: Level - cấp độ tối thiểu mới
+func (l *LevelHandler) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Enabled kiểm tra level có đạt cấp độ tối thiểu và được handler bên trong chấp nhận hay không.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//
+// Trả về:
+//   - bool: true nếu entry ở cấp độ này được ghi
+func (l *LevelHandler) Enabled(level Level) bool {
+	return level >= l.Level() && Enabled(l.handler, level)
+}
+
+// Log chuyển thông điệp đến handler bên trong nếu đạt cấp độ tối thiểu.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (l *LevelHandler) Log(level Level, message string, args ...interface{}) error {
+	if level < l.Level() {
+		return nil
+	}
+	return l.handler.Log(level, message, args...)
+}
+
+// LogEntry chuyển entry đến handler bên trong nếu đạt cấp độ tối thiểu.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (l *LevelHandler) LogEntry(entry *Entry) error {
+	if entry.Level < l.Level() {
+		return nil
+	}
+	return Dispatch(l.handler, entry)
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (l *LevelHandler) Unwrap() Handler {
+	return l.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (l *LevelHandler) Close() error {
+	return l.handler.Close()
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryHandler lưu các entry nhận được trong bộ nhớ, để test kiểm tra log của mã đang được
+// kiểm thử mà không cần đọc và phân tích file. Handler an toàn khi dùng đồng thời và vẫn đọc
+// được sau khi đóng.
+type MemoryHandler struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryHandler tạo một MemoryHandler rỗng.
+//
+// Trả về:
+//   - *MemoryHandler: handler lưu entry trong bộ nhớ
+//
+// Ví dụ:
+//
+//	mem := handler.NewMemoryHandler()
+//	logger := log.NewLogger("Orders")
+//	logger.AddHandler("memory", mem)
+//	service.PlaceOrder(logger)
+//	if !mem.Contains("order placed") {
+//	    t.Error("expected order placed log")
+//	}
+func NewMemoryHandler() *MemoryHandler {
+	return &MemoryHandler{}
+}
+
+// Log lưu thông điệp đã định dạng với timestamp hiện tại.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: luôn là nil
+func (m *MemoryHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	m.append(Entry{Time: time.Now(), Level: level, Message: message})
+	return nil
+}
+
+// LogEntry lưu bản sao của entry.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần lưu
+//
+// Trả về:
+//   - error: luôn là nil
+func (m *MemoryHandler) LogEntry(entry *Entry) error {
+	m.append(*entry.Clone())
+	return nil
+}
+
+// append thêm entry vào cuối danh sách.
+func (m *MemoryHandler) append(entry Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+}
+
+// Entries trả về các entry đã lưu theo thứ tự nhận.
+//
+// Trả về:
+//   - []Entry: bản sao danh sách entry, có thể sửa tự do
+func (m *MemoryHandler) Entries() []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Entry(nil), m.entries...)
+}
+
+// ByLevel trả về các entry đã lưu có cấp độ đã cho.
+//
+// Tham số:
+//   - level: Level - cấp độ cần lọc
+//
+// Trả về:
+//   - []Entry: các entry có cấp độ level theo thứ tự nhận
+func (m *MemoryHandler) ByLevel(level Level) []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []Entry
+	for _, entry := range m.entries {
+		if entry.Level == level {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Contains kiểm tra có entry nào chứa chuỗi msg trong thông điệp hay không.
+//
+// Tham số:
+//   - msg: string - chuỗi cần tìm trong thông điệp
+//
+// Trả về:
+//   - bool: true nếu có ít nhất một entry chứa msg
+func (m *MemoryHandler) Contains(msg string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range m.entries {
+		if strings.Contains(entry.Message, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len trả về số entry đã lưu.
+func (m *MemoryHandler) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Reset xóa các entry đã lưu, VD: giữa các bước của một test.
+func (m *MemoryHandler) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = nil
+}
+
+// Close không làm gì; các entry đã lưu vẫn đọc được sau khi đóng.
+//
+// Trả về:
+//   - error: luôn là nil
+func (m *MemoryHandler) Close() error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMemoryHandler(t *testing.T) {
+	mem := NewMemoryHandler()
+	mem.Log(InfoLevel, "order %d placed", 42)
+	mem.LogEntry(&Entry{Level: ErrorLevel, Message: "payment failed", Fields: []Field{{Key: "code", Type: StringType, Str: "E1"}}})
+	mem.Log(ErrorLevel, "retry failed")
+
+	if mem.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", mem.Len())
+	}
+	entries := mem.Entries()
+	if entries[0].Message != "order 42 placed" || entries[0].Time.IsZero() {
+		t.Errorf("Log() nên lưu thông điệp đã định dạng và timestamp, got %+v", entries[0])
+	}
+	if entries[1].Fields[0].Str != "E1" {
+		t.Errorf("LogEntry() nên lưu field, got %+v", entries[1])
+	}
+	if got := mem.ByLevel(ErrorLevel); len(got) != 2 || got[1].Message != "retry failed" {
+		t.Errorf("ByLevel(Error) = %+v", got)
+	}
+	if !mem.Contains("42 placed") || mem.Contains("shipped") {
+		t.Error("Contains() nên tìm theo chuỗi con của thông điệp")
+	}
+
+	entries[0].Message = "changed"
+	if mem.Entries()[0].Message != "order 42 placed" {
+		t.Error("Entries() nên trả về bản sao")
+	}
+
+	mem.Close()
+	mem.Reset()
+	if mem.Len() != 0 || len(mem.Entries()) != 0 {
+		t.Error("Reset() nên xóa các entry đã lưu")
+	}
+}
+
+func TestMemoryHandler_LogEntryCopiesFields(t *testing.T) {
+	mem := NewMemoryHandler()
+	fields := []Field{{Key: "user", Type: StringType, Str: "john"}}
+	mem.LogEntry(&Entry{Message: "login", Fields: fields})
+	fields[0].Str = "reused"
+
+	if got := mem.Entries()[0].Fields[0].Str; got != "john" {
+		t.Errorf("Entry đã lưu không nên bị ảnh hưởng khi slice Fields được dùng lại, got %q", got)
+	}
+}
+
+func TestMemoryHandler_Concurrent(t *testing.T) {
+	mem := NewMemoryHandler()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mem.LogEntry(&Entry{Level: InfoLevel, Message: "entry"})
+				mem.Contains("entry")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if mem.Len() != 800 {
+		t.Errorf("Len() = %d, want 800", mem.Len())
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+// ByteCounter là interface tùy chọn cho các handler đếm số byte đã ghi (VD: FileHandler,
+// ConsoleHandler).
+type ByteCounter interface {
+	// BytesWritten trả về tổng số byte đã ghi kể từ khi handler được tạo.
+	//
+	// Trả về:
+	//   - uint64: số byte đã ghi
+	BytesWritten() uint64
+}
+
+// Dropper là interface tùy chọn cho các handler có thể bỏ entry (VD: AsyncHandler khi hàng đợi
+// đầy, SamplingHandler).
+type Dropper interface {
+	// Dropped trả về tổng số entry đã bị bỏ.
+	//
+	// Trả về:
+	//   - uint64: số entry bị bỏ
+	Dropped() uint64
+}
+
+// QueueReporter là interface tùy chọn cho các handler có hàng đợi (VD: AsyncHandler).
+type QueueReporter interface {
+	// QueueDepth trả về số entry đang chờ trong hàng đợi.
+	//
+	// Trả về:
+	//   - int: số entry đang chờ
+	QueueDepth() int
+}
+
+// RotationCounter là interface tùy chọn cho các handler đếm số lần xoay vòng file (VD:
+// FileHandler).
+type RotationCounter interface {
+	// Rotations trả về số lần file đã được xoay vòng.
+	//
+	// Trả về:
+	//   - uint64: số lần xoay vòng
+	Rotations() uint64
+}
+
+// HandlerCounters là các bộ đếm của một chuỗi handler, dùng cho metrics.
+type HandlerCounters struct {
+	BytesWritten uint64 // Tổng số byte đã ghi của các handler triển khai ByteCounter
+	Dropped      uint64 // Tổng số entry bị bỏ của các handler triển khai Dropper
+	QueueDepth   int    // Tổng số entry đang chờ của các handler triển khai QueueReporter
+	Rotations    uint64 // Tổng số lần xoay vòng của các handler triển khai RotationCounter
+}
+
+// Counters cộng dồn các bộ đếm của handler và mọi handler được bọc (theo Unwrap).
+//
+// Tham số:
+//   - h: Handler - handler cần đọc
+//
+// Trả về:
+//   - HandlerCounters: các bộ đếm, bằng 0 với handler không triển khai interface tương ứng
+//
+// Ví dụ:
+//
+//	counters := handler.Counters(manager.GetHandler(log.HandlerTypeFile))
+//	fmt.Printf("đã ghi %d byte, bỏ %d entry\n", counters.BytesWritten, counters.Dropped)
+func Counters(h Handler) HandlerCounters {
+	var counters HandlerCounters
+	for h != nil {
+		if c, ok := h.(ByteCounter); ok {
+			counters.BytesWritten += c.BytesWritten()
+		}
+		if d, ok := h.(Dropper); ok {
+			counters.Dropped += d.Dropped()
+		}
+		if q, ok := h.(QueueReporter); ok {
+			counters.QueueDepth += q.QueueDepth()
+		}
+		if r, ok := h.(RotationCounter); ok {
+			counters.Rotations += r.Rotations()
+		}
+		w, ok := h.(interface{ Unwrap() Handler })
+		if !ok {
+			break
+		}
+		h = w.Unwrap()
+	}
+	return counters
+}
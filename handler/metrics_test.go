@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCounters(t *testing.T) {
+	file, err := NewFileHandler(filepath.Join(t.TempDir(), "app.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	sink := &slowRecorder{release: make(chan struct{})}
+	h, _ := NewDeliveryHandler(sink, BestEffort, DeliveryOptions{Workers: 1, QueueSize: 2})
+	async := h.(*AsyncHandler)
+	defer func() {
+		close(sink.release)
+		async.Close()
+		file.Close()
+	}()
+
+	file.Log(InfoLevel, "counted")
+	if c := Counters(NewFallbackHandler(file, nil, 0)); c.BytesWritten == 0 || c.Dropped != 0 || c.QueueDepth != 0 {
+		t.Errorf("Counters() nên đọc BytesWritten qua Unwrap, got %+v", c)
+	}
+
+	for i := 0; i < 5; i++ {
+		async.Log(InfoLevel, "burst")
+	}
+	deadline := time.Now().Add(time.Second)
+	for Counters(async).QueueDepth != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if c := Counters(async); c.QueueDepth != 2 || c.Dropped == 0 {
+		t.Errorf("Counters() nên báo hàng đợi đầy và entry bị bỏ, got %+v", c)
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"sort"
+	"sync"
+)
+
+// Middleware bọc một handler trong một decorator (VD: giới hạn tốc độ, thử lại, lọc, thống kê)
+// và trả về handler mới. Middleware trả về handler triển khai Unwrap để Enabled, Health và
+// Counters nhìn thấy handler bên trong.
+type Middleware func(Handler) Handler
+
+// Chain ghép nhiều middleware thành một. Middleware đầu tiên bọc ngoài cùng, nên nhận entry
+// trước các middleware sau; middleware nil bị bỏ qua.
+//
+// Tham số:
+//   - middlewares: ...Middleware - các middleware theo thứ tự từ ngoài vào trong
+//
+// Trả về:
+//   - Middleware: middleware đã ghép, trả về nguyên handler nếu không có middleware nào
+//
+// Ví dụ:
+//
+//	remote := handler.Chain(
+//		handler.Throttle(handler.ThrottleOptions{PerContext: handler.ThrottleLimit{Rate: 100}}),
+//		handler.Retry(handler.RetryPolicy{Retries: 3}),
+//	)(lokiHandler)
+func Chain(middlewares ...Middleware) Middleware {
+	return func(h Handler) Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				h = middlewares[i](h)
+			}
+		}
+		return h
+	}
+}
+
+// Throttle trả về middleware bọc handler trong ThrottledHandler.
+//
+// Tham số:
+//   - opts: ThrottleOptions - giới hạn tốc độ theo cấp độ và theo context
+//
+// Trả về:
+//   - Middleware: middleware giới hạn tốc độ
+func Throttle(opts ThrottleOptions) Middleware {
+	return func(h Handler) Handler { return NewThrottledHandler(h, opts) }
+}
+
+// Retry trả về middleware bọc handler trong CircuitHandler.
+//
+// Tham số:
+//   - policy: RetryPolicy - chính sách thử lại và ngắt mạch
+//
+// Trả về:
+//   - Middleware: middleware thử lại
+func Retry(policy RetryPolicy) Middleware {
+	return func(h Handler) Handler { return NewCircuitHandler(h, policy) }
+}
+
+// Sample trả về middleware bọc handler trong SamplingHandler.
+//
+// Tham số:
+//   - opts: SamplingOptions - tùy chọn lấy mẫu
+//
+// Trả về:
+//   - Middleware: middleware lấy mẫu
+func Sample(opts SamplingOptions) Middleware {
+	return func(h Handler) Handler { return NewSamplingHandler(h, opts) }
+}
+
+// FilterFields trả về middleware bọc handler trong FieldFilterHandler.
+//
+// Tham số:
+//   - filter: FieldFilter - các field được giữ lại hoặc bỏ
+//
+// Trả về:
+//   - Middleware: middleware lọc field
+func FilterFields(filter FieldFilter) Middleware {
+	return func(h Handler) Handler { return NewFieldFilterHandler(h, filter) }
+}
+
+// FilterRecords trả về middleware bọc handler trong RecordFilterHandler.
+//
+// Tham số:
+//   - filter: RecordFilter - điều kiện chọn entry
+//
+// Trả về:
+//   - Middleware: middleware lọc entry
+func FilterRecords(filter RecordFilter) Middleware {
+	return func(h Handler) Handler { return NewRecordFilterHandler(h, filter) }
+}
+
+// TransformFields trả về middleware bọc handler trong TransformHandler.
+//
+// Tham số:
+//   - opts: TransformOptions - các transform và giới hạn định dạng
+//
+// Trả về:
+//   - Middleware: middleware biến đổi field
+func TransformFields(opts TransformOptions) Middleware {
+	return func(h Handler) Handler { return NewTransformHandler(h, opts) }
+}
+
+// Fold trả về middleware bọc handler trong FoldHandler.
+//
+// Tham số:
+//   - opts: FoldOptions - chế độ gộp dòng, key của field và giới hạn định dạng
+//
+// Trả về:
+//   - Middleware: middleware gộp thông điệp nhiều dòng
+func Fold(opts FoldOptions) Middleware {
+	return func(h Handler) Handler { return NewFoldHandler(h, opts) }
+}
+
+// middlewares là registry các middleware theo tên, để cấu hình nêu middleware theo tên.
+var middlewares = struct {
+	sync.RWMutex
+	byName map[string]Middleware
+}{byName: make(map[string]Middleware)}
+
+// RegisterMiddleware đăng ký một middleware theo tên, thay thế middleware đã đăng ký cùng tên,
+// để bọc handler từ cấu hình (Config.Middleware). Middleware được gọi một lần cho mỗi handler
+// được bọc nên phải tạo decorator mới mỗi lần gọi. Hàm này thường được gọi trong init.
+//
+// Tham số:
+//   - name: string - tên middleware dùng trong cấu hình, không được rỗng
+//   - middleware: Middleware - middleware cần đăng ký, không được nil
+//
+// Ví dụ:
+//
+//	func init() {
+//		handler.RegisterMiddleware("throttle_remote", handler.Throttle(handler.ThrottleOptions{
+//			PerContext: handler.ThrottleLimit{Rate: 50},
+//		}))
+//	}
+func RegisterMiddleware(name string, middleware Middleware) {
+	if name == "" || middleware == nil {
+		panic("handler: RegisterMiddleware name is empty or middleware is nil")
+	}
+
+	middlewares.Lock()
+	defer middlewares.Unlock()
+	middlewares.byName[name] = middleware
+}
+
+// LookupMiddleware trả về middleware đã đăng ký theo tên.
+//
+// Tham số:
+//   - name: string - tên middleware
+//
+// Trả về:
+//   - Middleware: middleware tương ứng
+//   - bool: false nếu chưa có middleware nào được đăng ký với tên này
+func LookupMiddleware(name string) (Middleware, bool) {
+	middlewares.RLock()
+	defer middlewares.RUnlock()
+	middleware, ok := middlewares.byName[name]
+	return middleware, ok
+}
+
+// MiddlewareNames trả về tên các middleware đã đăng ký theo thứ tự bảng chữ cái.
+//
+// Trả về:
+//   - []string: tên các middleware
+func MiddlewareNames() []string {
+	middlewares.RLock()
+	defer middlewares.RUnlock()
+	names := make([]string, 0, len(middlewares.byName))
+	for name := range middlewares.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+// tagHandler ghi tên của nó vào trace trước khi chuyển entry vào handler bên trong
+type tagHandler struct {
+	name    string
+	handler Handler
+	trace   *[]string
+}
+
+func (t *tagHandler) Log(level Level, message string, args ...interface{}) error {
+	*t.trace = append(*t.trace, t.name)
+	return t.handler.Log(level, message, args...)
+}
+
+func (t *tagHandler) Close() error { return t.handler.Close() }
+
+func (t *tagHandler) Unwrap() Handler { return t.handler }
+
+func tagMiddleware(name string, trace *[]string) Middleware {
+	return func(h Handler) Handler { return &tagHandler{name: name, handler: h, trace: trace} }
+}
+
+func TestChain(t *testing.T) {
+	var trace []string
+	inner := &MockTestHandler{}
+	h := Chain(tagMiddleware("outer", &trace), nil, tagMiddleware("inner", &trace))(inner)
+
+	if err := h.Log(InfoLevel, "chained"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if got := strings.Join(trace, ","); got != "outer,inner" {
+		t.Errorf("Middleware đầu tiên nên bọc ngoài cùng, got %s", got)
+	}
+	if !inner.LogCalled {
+		t.Error("Handler bên trong nên nhận entry")
+	}
+	if Chain()(inner) != inner {
+		t.Error("Chain() không có middleware nên trả về nguyên handler")
+	}
+}
+
+func TestChain_BuiltinMiddleware(t *testing.T) {
+	inner := &MockTestHandler{}
+	h := Chain(
+		FilterRecords(RecordFilter{Include: []RecordRule{{MinLevel: WarningLevel}}}),
+		Retry(RetryPolicy{}),
+	)(inner)
+
+	if _, ok := h.(*RecordFilterHandler); !ok {
+		t.Fatalf("Middleware đầu tiên nên bọc ngoài cùng, got %T", h)
+	}
+	if Enabled(h, InfoLevel) != Enabled(inner, InfoLevel) {
+		t.Error("Enabled() nên nhìn thấy handler bên trong qua Unwrap")
+	}
+
+	h.(EntryHandler).LogEntry(&Entry{Level: InfoLevel, Message: "dropped"})
+	if inner.LogCalled {
+		t.Error("Entry dưới cấp độ của bộ lọc không nên đến handler bên trong")
+	}
+	h.(EntryHandler).LogEntry(&Entry{Level: ErrorLevel, Message: "kept"})
+	if inner.LogMessage != "kept" {
+		t.Errorf("Entry khớp bộ lọc nên đến handler bên trong, got %q", inner.LogMessage)
+	}
+}
+
+func TestMiddlewareRegistry(t *testing.T) {
+	var trace []string
+	RegisterMiddleware("tag", tagMiddleware("tag", &trace))
+
+	middleware, ok := LookupMiddleware("tag")
+	if !ok {
+		t.Fatal("LookupMiddleware() nên tìm middleware đã đăng ký")
+	}
+	middleware(&MockTestHandler{}).Log(InfoLevel, "registered")
+	if len(trace) != 1 {
+		t.Errorf("Middleware đã đăng ký nên bọc handler, got %v", trace)
+	}
+	if _, ok := LookupMiddleware("missing"); ok {
+		t.Error("LookupMiddleware() với tên chưa đăng ký nên trả về false")
+	}
+	if names := strings.Join(MiddlewareNames(), ","); !strings.Contains(names, "tag") {
+		t.Errorf("MiddlewareNames() nên liệt kê middleware đã đăng ký, got %s", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterMiddleware với middleware nil nên panic")
+		}
+	}()
+	RegisterMiddleware("nil", nil)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"sync"
+	"time"
+)
+
+// Giới hạn của các buffer được trả về pool.
+const (
+	defaultBufferSize = 256      // Dung lượng ban đầu của buffer mới
+	maxPooledBuffer   = 64 << 10 // Buffer lớn hơn không được trả về pool để tránh giữ bộ nhớ
+)
+
+// bufferPool lưu các buffer byte dùng lại giữa các lần ghi log.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, defaultBufferSize)
+		return &b
+	},
+}
+
+// entryPool lưu các Entry dùng lại trong các đường ghi sở hữu toàn bộ vòng đời của entry.
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return new(Entry)
+	},
+}
+
+// GetBuffer lấy một buffer rỗng từ pool.
+//
+// Buffer phải được trả lại bằng PutBuffer sau khi dùng xong và không được tham chiếu sau đó.
+//
+// Trả về:
+//   - *[]byte: buffer có độ dài 0
+//
+// Ví dụ:
+//
+//	buf := handler.GetBuffer()
+//	defer handler.PutBuffer(buf)
+//	*buf = append(*buf, entry.Message...)
+func GetBuffer() *[]byte {
+	buf := bufferPool.Get().(*[]byte)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// PutBuffer trả buffer về pool. Buffer vượt quá 64KB bị bỏ để pool không giữ bộ nhớ lớn.
+//
+// Tham số:
+//   - buf: *[]byte - buffer được lấy từ GetBuffer
+func PutBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// getEntry lấy một Entry từ pool. Chỉ dùng khi entry không được giữ lại sau lời gọi
+// LogEntry đồng bộ của chính handler (VD: ConsoleHandler.Log, FileHandler.Log).
+func getEntry(t time.Time, level Level, message string) *Entry {
+	entry := entryPool.Get().(*Entry)
+	entry.Time = t
+	entry.Level = level
+	entry.Message = message
+	return entry
+}
+
+// putEntry xóa nội dung của entry và trả về pool.
+func putEntry(entry *Entry) {
+	*entry = Entry{}
+	entryPool.Put(entry)
+}
+
+// appendLine nối dòng log "timestamp [LEVEL] message\n" của entry vào cuối dst.
+func appendLine(dst []byte, entry *Entry) []byte {
+	dst = entry.Time.AppendFormat(dst, TimeLayout)
+	dst = append(dst, ' ')
+	return appendUntimedLine(dst, entry)
+}
+
+// appendUntimedLine nối một dòng log dạng "[LEVEL] message\n" (không có timestamp) vào dst.
+func appendUntimedLine(dst []byte, entry *Entry) []byte {
+	dst = append(dst, '[')
+	dst = append(dst, entry.Level.String()...)
+	dst = append(dst, "] "...)
+	dst = append(dst, entry.Message...)
+	return append(dst, '\n')
+}
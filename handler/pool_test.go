@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetBuffer(t *testing.T) {
+	buf := GetBuffer()
+	*buf = append(*buf, "dirty"...)
+	PutBuffer(buf)
+
+	if got := GetBuffer(); len(*got) != 0 {
+		t.Errorf("GetBuffer() nên trả về buffer rỗng, got %q", *got)
+	}
+
+	// Buffer quá lớn không được trả về pool nhưng PutBuffer vẫn an toàn
+	large := make([]byte, 0, maxPooledBuffer+1)
+	PutBuffer(&large)
+}
+
+func TestAppendLine(t *testing.T) {
+	entry := &Entry{Time: time.Date(2024, 3, 1, 12, 0, 5, 0, time.UTC), Level: WarningLevel, Message: "disk almost full"}
+	if got, want := string(appendLine(nil, entry)), "2024/03/01 12:00:05 [WARNING] disk almost full\n"; got != want {
+		t.Errorf("appendLine() = %q, want %q", got, want)
+	}
+}
+
+func TestFileHandler_LogEntry_Allocations(t *testing.T) {
+	h, err := NewFileHandler(filepath.Join(t.TempDir(), "alloc.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	entry := &Entry{Time: time.Now(), Level: InfoLevel, Message: "request completed"}
+	allocs := testing.AllocsPerRun(100, func() {
+		h.LogEntry(entry)
+	})
+	if allocs != 0 {
+		t.Errorf("FileHandler.LogEntry() nên dùng buffer từ pool và không cấp phát, got %v allocs", allocs)
+	}
+}
+
+func BenchmarkFileHandler_Log(b *testing.B) {
+	h, err := NewFileHandler(filepath.Join(b.TempDir(), "bench.log"), 0)
+	if err != nil {
+		b.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Log(InfoLevel, "request completed")
+	}
+}
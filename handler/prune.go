@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// BackupRetention giới hạn số lượng và tuổi của các file sao lưu do FileHandler tạo khi xoay
+// vòng, để thư mục log không tăng không giới hạn.
+type BackupRetention struct {
+	MaxBackups int           // Số file sao lưu mới nhất được giữ lại (0 = không giới hạn)
+	MaxAge     time.Duration // Tuổi tối đa của file sao lưu theo timestamp trong tên file (0 = không giới hạn)
+}
+
+// SetBackupRetention đặt giới hạn số lượng và tuổi của các file sao lưu. File sao lưu vượt giới
+// hạn (kể cả file đã nén) bị xóa ngay khi gọi và sau mỗi lần xoay vòng; khi bật nén, việc xóa
+// chạy sau khi nén xong. Lỗi khi xóa được ghi ra stderr. Method này là thread-safe.
+//
+// Tham số:
+//   - retention: BackupRetention - giới hạn mới; giá trị 0 để tắt từng giới hạn
+//
+// Ví dụ:
+//
+//	// Giữ tối đa 7 file sao lưu trong 30 ngày
+//	fileHandler.SetBackupRetention(handler.BackupRetention{MaxBackups: 7, MaxAge: 30 * 24 * time.Hour})
+func (a *FileHandler) SetBackupRetention(retention BackupRetention) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.retention = retention
+	pruneBackups(a.path, retention, a.clock.Now())
+}
+
+// pruneBackups xóa các file sao lưu của path vượt quá giới hạn của retention.
+func pruneBackups(path string, retention BackupRetention, now time.Time) {
+	if retention.MaxBackups <= 0 && retention.MaxAge <= 0 {
+		return
+	}
+	for i, backup := range backupPaths(path) {
+		expired := retention.MaxBackups > 0 && i >= retention.MaxBackups
+		if !expired && retention.MaxAge > 0 {
+			created, ok := backupTime(path, backup)
+			expired = ok && now.Sub(created) > retention.MaxAge
+		}
+		if !expired {
+			continue
+		}
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Lỗi khi xóa file log cũ %s: %v\n", backup, err)
+		}
+		if err := os.Remove(backup + signatureExtension); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Lỗi khi xóa file log cũ %s: %v\n", backup+signatureExtension, err)
+		}
+	}
+}
+
+// backupTime trả về thời điểm xoay vòng được ghi trong tên file sao lưu của path.
+func backupTime(path, backup string) (time.Time, bool) {
+	name := backup
+	if codec := CodecForPath(backup); codec != nil {
+		name = strings.TrimSuffix(backup, codec.Extension())
+	}
+	created, _, ok := parseBackupSuffix(strings.TrimPrefix(name, path+"."))
+	return created, ok
+}
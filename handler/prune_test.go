@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// createBackups tạo các file sao lưu của path với timestamp cách hiện tại theo ages.
+func createBackups(t *testing.T, path string, ages ...time.Duration) []string {
+	t.Helper()
+	var backups []string
+	for _, age := range ages {
+		backup := path + "." + time.Now().Add(-age).Format(backupSuffixLayout)
+		if err := os.WriteFile(backup, []byte("old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, backup)
+	}
+	return backups
+}
+
+func TestFileHandler_SetBackupRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	backups := createBackups(t, path, time.Hour, 2*time.Hour, 3*time.Hour)
+	compressed := createBackups(t, path, 4*time.Hour)[0] + ".gz"
+	if err := os.Rename(compressed[:len(compressed)-3], compressed); err != nil {
+		t.Fatal(err)
+	}
+	other := path + ".notes"
+	os.WriteFile(other, nil, 0644)
+
+	h.SetBackupRetention(BackupRetention{MaxBackups: 2})
+	for i, backup := range append(backups, compressed) {
+		_, err := os.Stat(backup)
+		if kept := i < 2; kept != (err == nil) {
+			t.Errorf("File sao lưu %s: giữ lại = %v, want %v", filepath.Base(backup), err == nil, kept)
+		}
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error("File không phải bản sao lưu không nên bị xóa")
+	}
+
+	h.SetBackupRetention(BackupRetention{MaxAge: 90 * time.Minute})
+	if _, err := os.Stat(backups[0]); err != nil {
+		t.Error("File sao lưu chưa quá MaxAge nên được giữ lại")
+	}
+	if _, err := os.Stat(backups[1]); !os.IsNotExist(err) {
+		t.Error("File sao lưu quá MaxAge nên bị xóa")
+	}
+}
+
+func TestFileHandler_Rotate_PrunesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	old := createBackups(t, path, 48*time.Hour, 72*time.Hour)
+	h, err := NewFileHandler(path, 10)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+	h.SetBackupRetention(BackupRetention{MaxBackups: 5, MaxAge: 24 * time.Hour})
+	if _, err := os.Stat(old[0]); !os.IsNotExist(err) {
+		t.Fatal("SetBackupRetention nên xóa ngay các file sao lưu quá hạn")
+	}
+
+	h.Log(InfoLevel, "first entry")
+	h.Log(InfoLevel, "rotated entry")
+	if backups := backupPaths(path); len(backups) != 1 {
+		t.Errorf("Xoay vòng nên giữ file sao lưu mới, got %v", backups)
+	}
+
+	h.SetBackupRetention(BackupRetention{MaxBackups: 1})
+	createBackups(t, path, time.Hour)
+	h.Log(InfoLevel, "rotated again")
+	if backups := backupPaths(path); len(backups) != 1 {
+		t.Errorf("Sau khi xoay vòng chỉ nên giữ MaxBackups file sao lưu, got %v", backups)
+	}
+}
+
+func TestFileHandler_Rotate_PrunesCompressedBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	createBackups(t, path, time.Hour)
+	h, err := NewFileHandler(path, 10)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	codec, _ := LookupCodec("gzip")
+	h.SetCompression(codec)
+	h.SetBackupRetention(BackupRetention{MaxBackups: 1})
+
+	h.Log(InfoLevel, "first entry")
+	h.Log(InfoLevel, "rotated entry")
+	h.Close() // Chờ việc nén và xóa ở nền hoàn tất
+
+	backups := backupPaths(path)
+	if len(backups) != 1 || CodecForPath(backups[0]) == nil {
+		t.Errorf("Chỉ nên giữ file sao lưu mới nhất đã nén, got %v", backups)
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// pseudonymSize là số byte của HMAC được giữ lại trong pseudonym (16 ký tự hex).
+const pseudonymSize = 8
+
+// PseudonymOptions cấu hình Pseudonymizer.
+type PseudonymOptions struct {
+	Fields []string // Tên field định danh, không phân biệt hoa thường (VD: "user_id", "email")
+	KeyID  string   // Định danh của khóa, ghi trước pseudonym để biết khóa nào đã dùng (VD: "2024-03")
+	Key    []byte   // Khóa HMAC bí mật; nil để che giá trị bằng DefaultRedactMask
+}
+
+// pseudonymKey là khóa hiện tại của Pseudonymizer.
+type pseudonymKey struct {
+	id     string
+	secret []byte
+}
+
+// Pseudonymizer thay giá trị của các field định danh (VD: user_id, email) bằng pseudonym tất
+// định HMAC-SHA256 với khóa luân phiên, để vẫn liên kết được các entry của cùng một người trong
+// một kỳ khóa mà không ghi ra định danh gốc.
+//
+// Pseudonym có dạng "<KeyID>:<16 ký tự hex>". Khi hủy khóa của một kỳ, không ai tính lại được
+// pseudonym từ định danh gốc, nên dữ liệu của người dùng trong các log của kỳ đó coi như đã bị
+// "quên" mà không cần ghi lại log. Khi không có khóa, giá trị bị che bằng DefaultRedactMask thay
+// vì ghi định danh gốc. Pseudonymizer là thread-safe.
+type Pseudonymizer struct {
+	fields map[string]bool
+	key    atomic.Pointer[pseudonymKey]
+}
+
+// NewPseudonymizer tạo Pseudonymizer từ các tùy chọn.
+//
+// Tham số:
+//   - opts: PseudonymOptions - tên field định danh và khóa hiện tại
+//
+// Trả về:
+//   - *Pseudonymizer: pseudonymizer đã được cấu hình
+//
+// Ví dụ:
+//
+//	pseudonymizer := handler.NewPseudonymizer(handler.PseudonymOptions{
+//	    Fields: []string{"user_id", "email"},
+//	    KeyID:  "2024-03",
+//	    Key:    monthlyKey,
+//	})
+//	// user_id=2024-03:9f86d081884c7d65
+func NewPseudonymizer(opts PseudonymOptions) *Pseudonymizer {
+	p := &Pseudonymizer{fields: make(map[string]bool, len(opts.Fields))}
+	for _, name := range opts.Fields {
+		if name != "" {
+			p.fields[strings.ToLower(name)] = true
+		}
+	}
+	p.Rotate(opts.KeyID, opts.Key)
+	return p
+}
+
+// Rotate thay khóa cho các entry được ghi sau lời gọi (VD: đầu mỗi tháng). Pseudonym của cùng
+// một định danh với khóa mới khác với khóa cũ. Method này là thread-safe.
+//
+// Tham số:
+//   - keyID: string - định danh của khóa mới (VD: "2024-04")
+//   - key: []byte - khóa HMAC bí mật mới; nil để che giá trị bằng DefaultRedactMask
+func (p *Pseudonymizer) Rotate(keyID string, key []byte) {
+	p.key.Store(&pseudonymKey{id: keyID, secret: bytes.Clone(key)})
+}
+
+// Pseudonym trả về pseudonym của value với khóa hiện tại.
+//
+// Tham số:
+//   - value: string - định danh gốc (VD: "alice@example.com")
+//
+// Trả về:
+//   - string: "<KeyID>:<hex>" (hoặc "<hex>" khi KeyID rỗng), hoặc DefaultRedactMask khi không có khóa
+func (p *Pseudonymizer) Pseudonym(value string) string {
+	key := p.key.Load()
+	if len(key.secret) == 0 {
+		return DefaultRedactMask
+	}
+	mac := hmac.New(sha256.New, key.secret)
+	mac.Write([]byte(value))
+	sum := hex.EncodeToString(mac.Sum(nil)[:pseudonymSize])
+	if key.id == "" {
+		return sum
+	}
+	return key.id + ":" + sum
+}
+
+// PseudonymizeFields thay giá trị của các field định danh bằng pseudonym. Giá trị không phải
+// chuỗi được chuyển thành chuỗi trước khi băm; field có giá trị nil hoặc chuỗi rỗng được giữ
+// nguyên. Map lồng nhau không được duyệt.
+//
+// Slice đầu vào không bị sửa; một slice mới được trả về khi có field bị thay.
+//
+// Tham số:
+//   - fields: []Field - các field cần xử lý
+//
+// Trả về:
+//   - []Field: các field đã được thay
+func (p *Pseudonymizer) PseudonymizeFields(fields []Field) []Field {
+	var out []Field
+	for i, f := range fields {
+		if !p.fields[strings.ToLower(f.Key)] || (f.Type == StringType && f.Str == "") || (f.Type == AnyType && f.Value == nil) {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, Field{Key: f.Key, Type: StringType, Str: p.Pseudonym(fieldText(f))})
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// Pseudonymize trả về FieldTransform thay giá trị của các field định danh bằng pseudonym của p,
+// để dùng với TransformHandler cho từng handler.
+//
+// Tham số:
+//   - p: *Pseudonymizer - pseudonymizer dùng để thay giá trị
+//
+// Trả về:
+//   - FieldTransform: transform thay định danh bằng pseudonym
+func Pseudonymize(p *Pseudonymizer) FieldTransform {
+	return p.PseudonymizeFields
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPseudonymizer_PseudonymizeFields(t *testing.T) {
+	p := NewPseudonymizer(PseudonymOptions{Fields: []string{"user_id", "Email"}, KeyID: "2024-03", Key: []byte("0123456789abcdef")})
+	fields := []Field{
+		{Key: "order_id", Type: Int64Type, Integer: 42},
+		{Key: "user_id", Type: StringType, Str: "alice"},
+		{Key: "email", Value: "alice@example.com"},
+		{Key: "user_id", Type: StringType, Str: ""},
+	}
+
+	out := p.PseudonymizeFields(fields)
+	if fields[1].Str != "alice" {
+		t.Fatal("PseudonymizeFields() không được sửa slice đầu vào")
+	}
+	if out[0] != fields[0] || out[3].Str != "" {
+		t.Errorf("Field không phải định danh hoặc rỗng nên được giữ nguyên, got %+v", out)
+	}
+	user := out[1].Str
+	if !strings.HasPrefix(user, "2024-03:") || len(user) != len("2024-03:")+16 || strings.Contains(user, "alice") {
+		t.Errorf("Pseudonym không đúng định dạng, got %q", user)
+	}
+	if out[2].Type != StringType || out[2].Str == "alice@example.com" {
+		t.Errorf("Field khớp không phân biệt hoa thường nên được thay, got %+v", out[2])
+	}
+
+	// Tất định trong một kỳ khóa, khác nhau giữa các kỳ
+	if again := p.PseudonymizeFields(fields); again[1].Str != user {
+		t.Errorf("Pseudonym nên tất định, got %q và %q", user, again[1].Str)
+	}
+	p.Rotate("2024-04", []byte("fedcba9876543210"))
+	if rotated := p.Pseudonym("alice"); rotated == user || !strings.HasPrefix(rotated, "2024-04:") {
+		t.Errorf("Pseudonym sau khi luân phiên khóa nên khác, got %q", rotated)
+	}
+
+	untouched := []Field{{Key: "order_id", Type: Int64Type, Integer: 1}}
+	if got := p.PseudonymizeFields(untouched); &got[0] != &untouched[0] {
+		t.Error("PseudonymizeFields() nên trả về slice gốc khi không có field bị thay")
+	}
+}
+
+func TestPseudonymizer_NoKey(t *testing.T) {
+	p := NewPseudonymizer(PseudonymOptions{Fields: []string{"user_id"}})
+	if got := p.Pseudonym("alice"); got != DefaultRedactMask {
+		t.Errorf("Pseudonym() không có khóa nên che giá trị, got %q", got)
+	}
+}
+
+func TestPseudonymize_Transform(t *testing.T) {
+	p := NewPseudonymizer(PseudonymOptions{Fields: []string{"user_id"}, Key: []byte("0123456789abcdef")})
+	memory := NewMemoryHandler()
+	h := NewTransformHandler(memory, TransformOptions{Transforms: []FieldTransform{Pseudonymize(p)}})
+	_ = h.LogEntry(&Entry{Level: InfoLevel, Message: "[Checkout] paid user_id=alice", Fields: []Field{{Key: "user_id", Type: StringType, Str: "alice"}}})
+
+	entries := memory.Entries()
+	want := "[Checkout] paid user_id=" + p.Pseudonym("alice")
+	if len(entries) != 1 || entries[0].Message != want {
+		t.Errorf("TransformHandler nên thay định danh trong thông điệp, got %v, want %q", entries, want)
+	}
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldOperators là các toán tử so sánh của FieldPredicate, toán tử dài được thử trước.
+var fieldOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// FieldPredicate là điều kiện trên một field của entry, VD: "status>=500", "component=grpc",
+// "request_id" (field tồn tại) hoặc "!user_id" (field không tồn tại).
+type FieldPredicate struct {
+	Key   string // Tên field
+	Op    string // Toán tử: "=", "!=", ">", ">=", "<", "<=", rỗng = tồn tại, "!" = không tồn tại
+	Value string // Giá trị so sánh
+}
+
+// ParseFieldPredicate phân tích biểu thức điều kiện field dạng "key", "!key" hoặc "key<op>value"
+// với op là một trong =, !=, >, >=, <, <=.
+//
+// Tham số:
+//   - expr: string - biểu thức điều kiện
+//
+// Trả về:
+//   - FieldPredicate: điều kiện đã phân tích
+//   - error: lỗi nếu biểu thức không có tên field
+//
+// Ví dụ:
+//
+//	p, err := handler.ParseFieldPredicate("duration>=500ms")
+func ParseFieldPredicate(expr string) (FieldPredicate, error) {
+	expr = strings.TrimSpace(expr)
+	if i := strings.IndexAny(expr, "=!<>"); i > 0 {
+		for _, op := range fieldOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				return FieldPredicate{
+					Key:   strings.TrimSpace(expr[:i]),
+					Op:    op,
+					Value: strings.TrimSpace(expr[i+len(op):]),
+				}, nil
+			}
+		}
+	}
+	if key, ok := strings.CutPrefix(expr, "!"); ok && key != "" && !strings.ContainsAny(key, "=!<>") {
+		return FieldPredicate{Key: key, Op: "!"}, nil
+	}
+	if expr == "" || strings.ContainsAny(expr, "=!<>") {
+		return FieldPredicate{}, fmt.Errorf("invalid field predicate %q", expr)
+	}
+	return FieldPredicate{Key: expr}, nil
+}
+
+// Match kiểm tra các field có thỏa điều kiện hay không.
+//
+// Giá trị được so sánh theo số khi cả hai vế là số, theo thời lượng khi field là
+// time.Duration, và theo chuỗi trong các trường hợp còn lại.
+//
+// Tham số:
+//   - fields: []Field - các field của entry
+//
+// Trả về:
+//   - bool: true nếu điều kiện được thỏa
+func (p FieldPredicate) Match(fields []Field) bool {
+	for _, f := range fields {
+		if f.Key != p.Key {
+			continue
+		}
+		switch p.Op {
+		case "":
+			return true
+		case "!":
+			return false
+		}
+		return p.compare(f)
+	}
+	return p.Op == "!"
+}
+
+// compare so sánh giá trị của field với Value theo Op.
+func (p FieldPredicate) compare(f Field) bool {
+	value := fmt.Sprint(f.Interface())
+	cmp := strings.Compare(value, p.Value)
+	if f.Type == DurationType {
+		if d, err := time.ParseDuration(p.Value); err == nil {
+			cmp = compareOrdered(time.Duration(f.Integer), d)
+		}
+	} else if a, err := strconv.ParseFloat(value, 64); err == nil {
+		if b, err := strconv.ParseFloat(p.Value, 64); err == nil {
+			cmp = compareOrdered(a, b)
+		}
+	}
+
+	switch p.Op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// compareOrdered trả về -1, 0 hoặc 1 khi a nhỏ hơn, bằng hoặc lớn hơn b.
+func compareOrdered[T int64 | float64 | time.Duration](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// RecordRule là một điều kiện trên entry; entry khớp khi thỏa mọi điều kiện được đặt.
+type RecordRule struct {
+	MinLevel Level            // Cấp độ tối thiểu của entry (DebugLevel = mọi cấp độ)
+	Message  *regexp.Regexp   // Khớp thông điệp đã định dạng của entry (nil = mọi thông điệp)
+	Contexts []string         // Context của logger, hỗ trợ ký tự đại diện như "grpc*" (rỗng = mọi context)
+	Fields   []FieldPredicate // Điều kiện trên field, tất cả phải thỏa
+}
+
+// Match kiểm tra entry có khớp điều kiện hay không.
+//
+// Context được lấy từ phần "[Context]" đầu thông điệp do logger định dạng; entry không có
+// context chỉ khớp mẫu rỗng.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần kiểm tra
+//
+// Trả về:
+//   - bool: true nếu entry khớp
+func (r RecordRule) Match(entry *Entry) bool {
+	if entry.Level < r.MinLevel {
+		return false
+	}
+	if len(r.Contexts) > 0 && !matchAny(r.Contexts, messageContext(entry.Message)) {
+		return false
+	}
+	if r.Message != nil && !r.Message.MatchString(entry.Message) {
+		return false
+	}
+	for _, p := range r.Fields {
+		if !p.Match(entry.Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordFilter chọn các entry mà một handler ghi ra.
+//
+// Entry được ghi khi khớp một trong các Include (hoặc Include rỗng) và không khớp Exclude nào.
+type RecordFilter struct {
+	Include []RecordRule // Chỉ ghi các entry khớp một trong các điều kiện, rỗng để ghi tất cả
+	Exclude []RecordRule // Bỏ các entry khớp một trong các điều kiện, áp dụng sau Include
+}
+
+// Keep kiểm tra entry có được ghi hay không.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần kiểm tra
+//
+// Trả về:
+//   - bool: true nếu entry được ghi
+func (f RecordFilter) Keep(entry *Entry) bool {
+	if len(f.Include) > 0 && !matchRule(f.Include, entry) {
+		return false
+	}
+	return !matchRule(f.Exclude, entry)
+}
+
+// matchRule kiểm tra entry có khớp một trong các điều kiện hay không.
+func matchRule(rules []RecordRule, entry *Entry) bool {
+	for _, rule := range rules {
+		if rule.Match(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordFilterHandler bọc một handler và bỏ các entry không được RecordFilter giữ lại, VD: bỏ
+// log debug ồn ào của thư viện bên thứ ba khỏi console nhưng vẫn ghi đầy đủ vào file. Handler
+// an toàn khi dùng đồng thời.
+type RecordFilterHandler struct {
+	handler Handler
+	filter  RecordFilter
+}
+
+// NewRecordFilterHandler tạo handler chỉ chuyển đến h các entry được filter giữ lại.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry được giữ
+//   - filter: RecordFilter - điều kiện chọn entry
+//
+// Trả về:
+//   - *RecordFilterHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	console := handler.NewRecordFilterHandler(handler.NewConsoleHandler(true), handler.RecordFilter{
+//	    Exclude: []handler.RecordRule{
+//	        {Contexts: []string{"grpc*"}, Fields: []handler.FieldPredicate{{Key: "status", Op: "<", Value: "500"}}},
+//	        {Message: regexp.MustCompile(`health check`)},
+//	    },
+//	})
+func NewRecordFilterHandler(h Handler, filter RecordFilter) *RecordFilterHandler {
+	return &RecordFilterHandler{handler: h, filter: filter}
+}
+
+// Log chuyển thông điệp đến handler bên trong nếu được giữ lại. Thông điệp không có field có
+// cấu trúc nên điều kiện trên field được đánh giá với danh sách field rỗng.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *RecordFilterHandler) Log(level Level, message string, args ...interface{}) error {
+	formatted := message
+	if len(args) > 0 {
+		formatted = fmt.Sprintf(message, args...)
+	}
+	if !f.filter.Keep(&Entry{Level: level, Message: formatted}) {
+		return nil
+	}
+	return f.handler.Log(level, message, args...)
+}
+
+// LogEntry chuyển entry đến handler bên trong nếu được giữ lại.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (f *RecordFilterHandler) LogEntry(entry *Entry) error {
+	if !f.filter.Keep(entry) {
+		return nil
+	}
+	return Dispatch(f.handler, entry)
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (f *RecordFilterHandler) Unwrap() Handler {
+	return f.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (f *RecordFilterHandler) Close() error {
+	return f.handler.Close()
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseFieldPredicate(t *testing.T) {
+	tests := map[string]FieldPredicate{
+		"status>=500":      {Key: "status", Op: ">=", Value: "500"},
+		"component = grpc": {Key: "component", Op: "=", Value: "grpc"},
+		"user!=admin":      {Key: "user", Op: "!=", Value: "admin"},
+		"request_id":       {Key: "request_id"},
+		"!user_id":         {Key: "user_id", Op: "!"},
+	}
+	for expr, want := range tests {
+		got, err := ParseFieldPredicate(expr)
+		if err != nil || got != want {
+			t.Errorf("ParseFieldPredicate(%q) = %+v, %v, want %+v", expr, got, err, want)
+		}
+	}
+	for _, expr := range []string{"", "=500", "a!b", "!"} {
+		if _, err := ParseFieldPredicate(expr); err == nil {
+			t.Errorf("ParseFieldPredicate(%q) nên trả về lỗi", expr)
+		}
+	}
+}
+
+func TestFieldPredicate_Match(t *testing.T) {
+	fields := []Field{
+		{Key: "status", Type: Int64Type, Integer: 503},
+		{Key: "component", Type: StringType, Str: "grpc"},
+		{Key: "latency", Type: DurationType, Integer: int64(750 * time.Millisecond)},
+	}
+	tests := map[string]bool{
+		"status>=500":     true,
+		"status<500":      false,
+		"status=503":      true,
+		"component=grpc":  true,
+		"component!=grpc": false,
+		"latency>500ms":   true,
+		"latency<=1s":     true,
+		"status":          true,
+		"!status":         false,
+		"!user_id":        true,
+		"user_id=42":      false,
+	}
+	for expr, want := range tests {
+		p, _ := ParseFieldPredicate(expr)
+		if got := p.Match(fields); got != want {
+			t.Errorf("%q.Match() = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestRecordFilterHandler_LogEntry(t *testing.T) {
+	rec := &entryRecorder{}
+	h := NewRecordFilterHandler(rec, RecordFilter{
+		Exclude: []RecordRule{
+			{Contexts: []string{"grpc*"}, Fields: []FieldPredicate{{Key: "status", Op: "<", Value: "500"}}},
+			{Message: regexp.MustCompile(`health check`)},
+		},
+	})
+
+	entries := []struct {
+		entry *Entry
+		kept  bool
+	}{
+		{&Entry{Message: "[grpc.server] call status=0", Fields: []Field{{Key: "status", Type: Int64Type}}}, false},
+		{&Entry{Message: "[grpc.server] call status=503", Fields: []Field{{Key: "status", Type: Int64Type, Integer: 503}}}, true},
+		{&Entry{Message: "[HTTP] health check ok"}, false},
+		{&Entry{Message: "[HTTP] request path=/"}, true},
+	}
+	for _, tt := range entries {
+		rec.entry = nil
+		if err := h.LogEntry(tt.entry); err != nil {
+			t.Fatalf("LogEntry() error = %v", err)
+		}
+		if kept := rec.entry != nil; kept != tt.kept {
+			t.Errorf("Entry %q: kept = %v, want %v", tt.entry.Message, kept, tt.kept)
+		}
+	}
+
+	errorsOnly := NewRecordFilterHandler(rec, RecordFilter{Include: []RecordRule{{MinLevel: ErrorLevel}}})
+	rec.entry = nil
+	_ = errorsOnly.LogEntry(&Entry{Level: WarningLevel, Message: "slow"})
+	if rec.entry != nil {
+		t.Error("Entry dưới MinLevel không nên khớp điều kiện")
+	}
+	_ = errorsOnly.LogEntry(&Entry{Level: FatalLevel, Message: "down"})
+	if rec.entry == nil {
+		t.Error("Entry từ MinLevel trở lên nên khớp điều kiện")
+	}
+
+	include := NewRecordFilterHandler(rec, RecordFilter{Include: []RecordRule{{Contexts: []string{"Payment"}}}})
+	rec.LogCalled = false
+	_ = include.Log(InfoLevel, "[Order] created")
+	if rec.LogCalled {
+		t.Error("Log() nên bỏ thông điệp không khớp Include")
+	}
+	_ = include.Log(InfoLevel, "[Payment] charged %d", 100)
+	if !rec.LogCalled {
+		t.Error("Log() nên chuyển thông điệp khớp Include")
+	}
+	if include.Unwrap() != rec {
+		t.Error("Unwrap() nên trả về handler bên trong")
+	}
+}
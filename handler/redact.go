@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask là chuỗi thay thế dữ liệu nhạy cảm khi RedactOptions.Mask rỗng.
+const DefaultRedactMask = "[REDACTED]"
+
+// RedactPattern là một mẫu dữ liệu nhạy cảm cần che trong thông điệp và giá trị field.
+type RedactPattern struct {
+	Name   string                  // Tên của mẫu (VD: "email"), dùng trong cấu hình
+	Regexp *regexp.Regexp          // Biểu thức tìm dữ liệu nhạy cảm
+	Verify func(match string) bool // Kiểm tra thêm đoạn khớp trước khi che (nil = luôn che)
+}
+
+// Các mẫu dữ liệu nhạy cảm có sẵn.
+var (
+	// RedactCreditCard che số thẻ thanh toán 13-19 chữ số (cho phép khoảng trắng hoặc dấu gạch
+	// ngang), chỉ khi số thỏa mãn thuật toán Luhn để tránh che nhầm timestamp và ID
+	RedactCreditCard = RedactPattern{
+		Name:   "credit_card",
+		Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Verify: luhnValid,
+	}
+
+	// RedactEmail che địa chỉ email
+	RedactEmail = RedactPattern{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+
+	// RedactJWT che JSON Web Token
+	RedactJWT = RedactPattern{
+		Name:   "jwt",
+		Regexp: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+)
+
+// redactPatterns là các mẫu có sẵn theo tên.
+var redactPatterns = map[string]RedactPattern{
+	RedactCreditCard.Name: RedactCreditCard,
+	RedactEmail.Name:      RedactEmail,
+	RedactJWT.Name:        RedactJWT,
+}
+
+// ParseRedactPattern trả về mẫu có sẵn theo tên ("credit_card", "email", "jwt"), hoặc biên dịch
+// expr thành một mẫu mới.
+//
+// Tham số:
+//   - expr: string - tên mẫu có sẵn hoặc biểu thức chính quy
+//
+// Trả về:
+//   - RedactPattern: mẫu dữ liệu nhạy cảm
+//   - error: lỗi nếu expr không phải biểu thức chính quy hợp lệ
+//
+// Ví dụ:
+//
+//	email, _ := handler.ParseRedactPattern("email")
+//	apiKey, err := handler.ParseRedactPattern(`sk_live_[0-9a-zA-Z]{24}`)
+func ParseRedactPattern(expr string) (RedactPattern, error) {
+	if pattern, ok := redactPatterns[expr]; ok {
+		return pattern, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return RedactPattern{}, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+	}
+	return RedactPattern{Name: expr, Regexp: re}, nil
+}
+
+// RedactOptions cấu hình Redactor.
+type RedactOptions struct {
+	Fields   []string        // Tên field có giá trị luôn bị che, không phân biệt hoa thường (VD: "password")
+	Patterns []RedactPattern // Mẫu dữ liệu nhạy cảm cần che trong thông điệp và giá trị chuỗi
+	Mask     string          // Chuỗi thay thế, rỗng để dùng DefaultRedactMask
+}
+
+// Redactor che dữ liệu nhạy cảm trong thông điệp và field của log entry.
+//
+// Giá trị của field có tên nhạy cảm bị thay toàn bộ bằng mask, kể cả trong map lồng nhau; các
+// giá trị chuỗi khác và thông điệp được che theo các mẫu. Cặp "key=value" có key nhạy cảm trong
+// thông điệp (VD: "login password=secret") cũng bị che. Redactor là thread-safe.
+type Redactor struct {
+	fields   map[string]bool
+	keyValue *regexp.Regexp // Khớp "key=value" với key nhạy cảm (nil khi không có Fields)
+	patterns []RedactPattern
+	mask     string
+}
+
+// NewRedactor tạo Redactor từ các tùy chọn.
+//
+// Tham số:
+//   - opts: RedactOptions - tên field và mẫu dữ liệu nhạy cảm
+//
+// Trả về:
+//   - *Redactor: redactor đã được cấu hình
+//
+// Ví dụ:
+//
+//	redactor := handler.NewRedactor(handler.RedactOptions{
+//	    Fields:   []string{"password", "token", "authorization"},
+//	    Patterns: []handler.RedactPattern{handler.RedactCreditCard, handler.RedactEmail, handler.RedactJWT},
+//	})
+func NewRedactor(opts RedactOptions) *Redactor {
+	r := &Redactor{
+		fields:   make(map[string]bool, len(opts.Fields)),
+		patterns: opts.Patterns,
+		mask:     opts.Mask,
+	}
+	if r.mask == "" {
+		r.mask = DefaultRedactMask
+	}
+
+	names := make([]string, 0, len(opts.Fields))
+	for _, name := range opts.Fields {
+		if name == "" {
+			continue
+		}
+		r.fields[strings.ToLower(name)] = true
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	if len(names) > 0 {
+		r.keyValue = regexp.MustCompile(`(?i)\b(` + strings.Join(names, "|") + `)=("(?:[^"\\]|\\.)*"|\S+)`)
+	}
+	return r
+}
+
+// RedactString che dữ liệu nhạy cảm trong s.
+//
+// Tham số:
+//   - s: string - chuỗi cần che (VD: thông điệp log)
+//
+// Trả về:
+//   - string: chuỗi đã được che
+func (r *Redactor) RedactString(s string) string {
+	if r.keyValue != nil {
+		s = r.keyValue.ReplaceAllString(s, "${1}="+strings.ReplaceAll(r.mask, "$", "$$"))
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if pattern.Verify != nil && !pattern.Verify(match) {
+				return match
+			}
+			return r.mask
+		})
+	}
+	return s
+}
+
+// RedactFields che dữ liệu nhạy cảm trong các field.
+//
+// Slice đầu vào không bị sửa; một slice mới được trả về khi có field bị che.
+//
+// Tham số:
+//   - fields: []Field - các field cần che
+//
+// Trả về:
+//   - []Field: các field đã được che
+func (r *Redactor) RedactFields(fields []Field) []Field {
+	var out []Field
+	for i, f := range fields {
+		redacted, changed := r.redactField(f)
+		if changed && out == nil {
+			out = make([]Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		if out != nil {
+			out = append(out, redacted)
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactField che giá trị của một field.
+func (r *Redactor) redactField(f Field) (Field, bool) {
+	if r.fields[strings.ToLower(f.Key)] {
+		return Field{Key: f.Key, Type: StringType, Str: r.mask}, true
+	}
+	switch f.Type {
+	case StringType:
+		if s := r.RedactString(f.Str); s != f.Str {
+			f.Str = s
+			return f, true
+		}
+	case AnyType:
+		if v, changed := r.redactValue(f.Value); changed {
+			f.Value = v
+			return f, true
+		}
+	}
+	return f, false
+}
+
+// redactValue che giá trị chuỗi, lỗi và map lồng nhau của field AnyType.
+func (r *Redactor) redactValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		s := r.RedactString(v)
+		return s, s != v
+	case error:
+		msg := v.Error()
+		if s := r.RedactString(msg); s != msg {
+			return s, true
+		}
+	case map[string]string:
+		var out map[string]string
+		for key, item := range v {
+			s := r.mask
+			if !r.fields[strings.ToLower(key)] {
+				s = r.RedactString(item)
+			}
+			if s != item && out == nil {
+				out = make(map[string]string, len(v))
+				for k, i := range v {
+					out[k] = i
+				}
+			}
+			if out != nil {
+				out[key] = s
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for key, item := range v {
+			var redacted interface{} = r.mask
+			changed := true
+			if !r.fields[strings.ToLower(key)] {
+				redacted, changed = r.redactValue(item)
+			}
+			if changed && out == nil {
+				out = make(map[string]interface{}, len(v))
+				for k, i := range v {
+					out[k] = i
+				}
+			}
+			if changed {
+				out[key] = redacted
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case []interface{}:
+		var out []interface{}
+		for i, item := range v {
+			redacted, changed := r.redactValue(item)
+			if changed && out == nil {
+				out = append([]interface{}(nil), v...)
+			}
+			if changed {
+				out[i] = redacted
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	}
+	return value, false
+}
+
+// luhnValid kiểm tra chuỗi số (có thể chứa khoảng trắng hoặc dấu gạch ngang) theo thuật toán Luhn.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
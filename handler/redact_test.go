@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRedactor_RedactString(t *testing.T) {
+	r := NewRedactor(RedactOptions{
+		Fields:   []string{"password", "Authorization"},
+		Patterns: []RedactPattern{RedactCreditCard, RedactEmail, RedactJWT},
+	})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"key=value", "login password=hunter2 user=alice", "login password=[REDACTED] user=alice"},
+		{"key=value có nháy kép", `auth authorization="Bearer abc def" ok`, `auth authorization=[REDACTED] ok`},
+		{"số thẻ hợp lệ", "paid with 4111 1111 1111 1111", "paid with [REDACTED]"},
+		{"số không thỏa Luhn", "backup 20240101120000", "backup 20240101120000"},
+		{"email", "sent to alice@example.com", "sent to [REDACTED]"},
+		{"jwt", "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig-_1 expired", "token [REDACTED] expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.RedactString(tt.in); got != tt.want {
+				t.Errorf("RedactString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_RedactFields(t *testing.T) {
+	r := NewRedactor(RedactOptions{Fields: []string{"token"}, Patterns: []RedactPattern{RedactEmail}, Mask: "***"})
+	fields := []Field{
+		{Key: "user", Type: StringType, Str: "alice"},
+		{Key: "TOKEN", Type: StringType, Str: "s3cr3t"},
+		{Key: "contact", Value: "bob@example.com"},
+		{Key: "error", Value: errors.New("mail to carol@example.com failed")},
+		{Key: "payload", Value: map[string]interface{}{"token": "x", "tags": []interface{}{"dave@example.com", 1}}},
+	}
+	original := append([]Field(nil), fields...)
+
+	got := r.RedactFields(fields)
+	want := []Field{
+		{Key: "user", Type: StringType, Str: "alice"},
+		{Key: "TOKEN", Type: StringType, Str: "***"},
+		{Key: "contact", Value: "***"},
+		{Key: "error", Value: "mail to *** failed"},
+		{Key: "payload", Value: map[string]interface{}{"token": "***", "tags": []interface{}{"***", 1}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactFields() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(fields, original) {
+		t.Error("RedactFields() không được sửa slice đầu vào")
+	}
+
+	clean := []Field{{Key: "user", Type: StringType, Str: "alice"}}
+	if got := r.RedactFields(clean); &got[0] != &clean[0] {
+		t.Error("RedactFields() nên trả về slice gốc khi không có gì cần che")
+	}
+}
+
+func TestParseRedactPattern(t *testing.T) {
+	if p, err := ParseRedactPattern("email"); err != nil || p.Regexp != RedactEmail.Regexp {
+		t.Errorf("ParseRedactPattern() nên trả về mẫu có sẵn theo tên, got %v, %v", p.Name, err)
+	}
+	if p, err := ParseRedactPattern(`sk_live_\w+`); err != nil || !p.Regexp.MatchString("sk_live_abc") {
+		t.Errorf("ParseRedactPattern() nên biên dịch biểu thức chính quy, got %v", err)
+	}
+	if _, err := ParseRedactPattern("("); err == nil {
+		t.Error("ParseRedactPattern() nên trả về lỗi với biểu thức không hợp lệ")
+	}
+}
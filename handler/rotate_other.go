@@ -0,0 +1,9 @@
+//go:build !windows
+
+package handler
+
+import "os"
+
+// renameFile đổi tên file log khi xoay vòng. Trên các hệ điều hành không phải Windows, file
+// đang được mở vẫn có thể đổi tên nên không cần thử lại.
+var renameFile = os.Rename
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useRenameFile thay renameFile trong thời gian chạy test.
+func useRenameFile(t *testing.T, rename func(oldpath, newpath string) error) {
+	original := renameFile
+	renameFile = rename
+	t.Cleanup(func() { renameFile = original })
+}
+
+// backupFiles trả về nội dung các file sao lưu của logPath.
+func backupFiles(t *testing.T, logPath string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(logPath + ".*")
+	if err != nil {
+		t.Fatalf("Không thể tìm file sao lưu: %v", err)
+	}
+	var contents []string
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			t.Fatalf("Không thể đọc file sao lưu: %v", err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func TestFileHandler_Rotate_CopyTruncateFallback(t *testing.T) {
+	useRenameFile(t, func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errors.New("file is in use")}
+	})
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	h, err := NewFileHandler(logPath, 10)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.Log(InfoLevel, "before rotation"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if err := h.Log(InfoLevel, "after rotation"); err != nil {
+		t.Fatalf("Log() nên xoay vòng bằng copy-truncate khi không đổi tên được, error = %v", err)
+	}
+
+	backups := backupFiles(t, logPath)
+	if len(backups) != 1 || !strings.Contains(backups[0], "before rotation") {
+		t.Errorf("File sao lưu nên chứa nội dung trước khi xoay vòng, got %q", backups)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Không thể đọc file log: %v", err)
+	}
+	if strings.Contains(string(content), "before rotation") || !strings.Contains(string(content), "after rotation") {
+		t.Errorf("File log nên bị cắt ngắn và chỉ chứa entry sau xoay vòng, got %q", content)
+	}
+}
+
+func TestFileHandler_Rotate_FailureKeepsLogging(t *testing.T) {
+	// Đổi tên thất bại và file sao lưu đã tồn tại nên copy-truncate cũng thất bại
+	useRenameFile(t, func(oldpath, newpath string) error {
+		if err := os.Mkdir(newpath, 0755); err != nil && !os.IsExist(err) {
+			return err
+		}
+		return errors.New("file is in use")
+	})
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	h, err := NewFileHandler(logPath, 10)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.Log(InfoLevel, "first"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	err = h.Log(InfoLevel, "second")
+	if err == nil || !strings.Contains(err.Error(), "không thể đổi tên file log") {
+		t.Fatalf("Lỗi xoay vòng nên được trả về thay vì bị bỏ qua, got %v", err)
+	}
+
+	// Khi file được giải phóng, handler vẫn mở file và xoay vòng ở lần ghi tiếp theo
+	renameFile = os.Rename
+	blocked, _ := filepath.Glob(logPath + ".*")
+	for _, path := range blocked {
+		os.Remove(path)
+	}
+	if err := h.Log(InfoLevel, "third"); err != nil {
+		t.Fatalf("Handler nên tiếp tục ghi sau khi xoay vòng thất bại, error = %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Không thể đọc file log: %v", err)
+	}
+	if !strings.Contains(string(content), "third") {
+		t.Errorf("Entry sau khi file được giải phóng nên được ghi, got %q", content)
+	}
+}
+
+func TestFileHandler_OnRotate(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "app.log")
+		h, err := NewFileHandler(path, 10)
+		if err != nil {
+			t.Fatalf("NewFileHandler() error = %v", err)
+		}
+		if compressed {
+			codec, _ := LookupCodec("gzip")
+			h.SetCompression(codec)
+		}
+		var calls [][2]string
+		h.OnRotate(func(oldPath, newPath string) {
+			calls = append(calls, [2]string{oldPath, newPath})
+			// Callback ghi vào chính handler không được gây deadlock
+			_ = h.Log(InfoLevel, "rotated %s", filepath.Base(oldPath))
+		})
+
+		_ = h.Log(InfoLevel, "first entry")
+		_ = h.Log(InfoLevel, "second entry")
+		if err := h.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		backups := backupPaths(path)
+		if len(calls) != 1 || len(backups) != 1 || calls[0] != [2]string{backups[0], path} {
+			t.Fatalf("compressed=%v: OnRotate nên nhận file sao lưu %v và file đang ghi, got %v", compressed, backups, calls)
+		}
+		if strings.HasSuffix(calls[0][0], ".gz") != compressed {
+			t.Errorf("compressed=%v: OnRotate nên nhận file sao lưu sau khi nén, got %s", compressed, calls[0][0])
+		}
+		if _, err := os.Stat(calls[0][0]); err != nil {
+			t.Errorf("File sao lưu nên tồn tại khi callback chạy: %v", err)
+		}
+	}
+}
+
+func TestFileHandler_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.Rotate(); err != nil || len(backupPaths(path)) != 0 {
+		t.Fatalf("Rotate() không nên tạo file sao lưu cho file rỗng, err = %v", err)
+	}
+	_ = h.Log(InfoLevel, "before rotation")
+	async := NewAsyncHandler(h, 1, 1)
+	defer async.Stop()
+	// Rotate tìm FileHandler qua chuỗi Unwrap
+	if err := Rotate(async); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if n := Counters(async).Rotations; n != 1 {
+		t.Errorf("Rotations() = %d, want 1 (file rỗng không được xoay vòng)", n)
+	}
+	_ = h.Log(InfoLevel, "after rotation")
+	if backups := backupFiles(t, path); len(backups) != 1 || !strings.Contains(backups[0], "before rotation") {
+		t.Errorf("Rotate() nên chuyển nội dung hiện tại sang file sao lưu, got %v", backups)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "before rotation") || !strings.Contains(string(data), "after rotation") {
+		t.Errorf("File log mới chỉ nên chứa entry sau khi xoay vòng, got %q", data)
+	}
+}
+
+func TestFileHandler_Rotate_ReopensMovedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	// Mô phỏng logrotate: đổi tên file log rồi gửi SIGHUP
+	_ = h.Log(InfoLevel, "before logrotate")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	_ = h.Log(InfoLevel, "after logrotate")
+
+	if backups := backupPaths(path); len(backups) != 0 {
+		t.Errorf("Rotate() chỉ nên mở lại file khi file đã bị đổi tên, got %v", backups)
+	}
+	if data, _ := os.ReadFile(path); string(data) == "" || strings.Contains(string(data), "before logrotate") {
+		t.Errorf("Entry sau khi mở lại nên được ghi vào file mới, got %q", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); strings.Contains(string(data), "after logrotate") {
+		t.Errorf("File đã bị đổi tên không nên nhận thêm entry, got %q", data)
+	}
+}
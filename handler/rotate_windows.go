@@ -0,0 +1,61 @@
+//go:build windows
+
+package handler
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Mã lỗi Windows khi file đang bị tiến trình khác giữ.
+const (
+	errorSharingViolation syscall.Errno = 32 // ERROR_SHARING_VIOLATION
+	errorLockViolation    syscall.Errno = 33 // ERROR_LOCK_VIOLATION
+)
+
+// renameRetryDelays là thời gian chờ giữa các lần thử đổi tên file log bị giữ (tổng cộng ~1s).
+var renameRetryDelays = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+}
+
+// renameFile đổi tên file log khi xoay vòng.
+//
+// Windows không cho đổi tên file khi tiến trình khác (VD: log shipper, antivirus) đang mở file
+// mà không chia sẻ quyền xóa, nên việc đổi tên được thử lại với backoff cho đến khi file được
+// giải phóng.
+var renameFile = renameWithRetry
+
+// renameWithRetry gọi os.Rename và thử lại theo renameRetryDelays khi file đang bị giữ.
+//
+// Tham số:
+//   - oldpath: string - đường dẫn file hiện tại
+//   - newpath: string - đường dẫn mới
+//
+// Trả về:
+//   - error: lỗi của lần thử cuối cùng, hoặc lỗi không phải do file bị giữ
+func renameWithRetry(oldpath, newpath string) error {
+	err := os.Rename(oldpath, newpath)
+	for _, delay := range renameRetryDelays {
+		if err == nil || !isFileInUse(err) {
+			return err
+		}
+		time.Sleep(delay)
+		err = os.Rename(oldpath, newpath)
+	}
+	return err
+}
+
+// isFileInUse kiểm tra lỗi có phải do file đang bị tiến trình khác giữ hay không.
+func isFileInUse(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	return errno == errorSharingViolation || errno == errorLockViolation || errno == syscall.ERROR_ACCESS_DENIED
+}
@@ -0,0 +1,101 @@
+//go:build windows
+
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// useShortRenameRetries rút ngắn thời gian chờ giữa các lần thử đổi tên trong test.
+func useShortRenameRetries(t *testing.T) {
+	original := renameRetryDelays
+	renameRetryDelays = []time.Duration{time.Millisecond, 5 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond}
+	t.Cleanup(func() { renameRetryDelays = original })
+}
+
+func TestIsFileInUse(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&os.LinkError{Op: "rename", Err: errorSharingViolation}, true},
+		{&os.LinkError{Op: "rename", Err: errorLockViolation}, true},
+		{&os.LinkError{Op: "rename", Err: syscall.ERROR_ACCESS_DENIED}, true},
+		{&os.LinkError{Op: "rename", Err: syscall.ERROR_FILE_NOT_FOUND}, false},
+		{os.ErrClosed, false},
+	}
+	for _, tt := range tests {
+		if got := isFileInUse(tt.err); got != tt.want {
+			t.Errorf("isFileInUse(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFileHandler_Rotate_HeldByOtherProcess(t *testing.T) {
+	useShortRenameRetries(t)
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	h, err := NewFileHandler(logPath, 10)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if err := h.Log(InfoLevel, "before rotation"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	// os.Open không chia sẻ quyền xóa nên file không thể đổi tên khi handle này còn mở
+	holder, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("Không thể mở file log: %v", err)
+	}
+	defer holder.Close()
+
+	if err := h.Log(InfoLevel, "after rotation"); err != nil {
+		t.Fatalf("Log() nên xoay vòng bằng copy-truncate khi file bị giữ, error = %v", err)
+	}
+
+	matches, _ := filepath.Glob(logPath + ".*")
+	if len(matches) != 1 {
+		t.Fatalf("Nên có đúng một file sao lưu, got %v", matches)
+	}
+	backup, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(backup), "before rotation") {
+		t.Errorf("File sao lưu nên chứa nội dung trước khi xoay vòng, got %q", backup)
+	}
+	content, _ := os.ReadFile(logPath)
+	if strings.Contains(string(content), "before rotation") || !strings.Contains(string(content), "after rotation") {
+		t.Errorf("File log nên bị cắt ngắn và tiếp tục được ghi, got %q", content)
+	}
+}
+
+func TestRenameWithRetry_WaitsForRelease(t *testing.T) {
+	useShortRenameRetries(t)
+
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "app.log")
+	newPath := oldPath + ".1"
+	if err := os.WriteFile(oldPath, []byte("entry\n"), 0644); err != nil {
+		t.Fatalf("Không thể tạo file: %v", err)
+	}
+
+	holder, err := os.Open(oldPath)
+	if err != nil {
+		t.Fatalf("Không thể mở file: %v", err)
+	}
+	time.AfterFunc(10*time.Millisecond, func() { holder.Close() })
+
+	if err := renameWithRetry(oldPath, newPath); err != nil {
+		t.Fatalf("renameWithRetry() nên thành công khi file được giải phóng trong thời gian thử lại, error = %v", err)
+	}
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("File nên được đổi tên, error = %v", err)
+	}
+}
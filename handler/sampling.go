@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Giá trị mặc định và giới hạn của Sampler.
+const (
+	// DefaultSamplingTick là khoảng thời gian mặc định của mỗi chu kỳ lấy mẫu
+	DefaultSamplingTick = time.Second
+
+	// samplerCounters là số bộ đếm của mỗi cấp độ; các thông điệp khác nhau có cùng hash dùng
+	// chung bộ đếm
+	samplerCounters = 1024
+)
+
+// SamplingOptions cấu hình việc lấy mẫu log theo chu kỳ.
+//
+// Trong mỗi chu kỳ Tick, Initial entry đầu tiên có cùng cấp độ và thông điệp được ghi, sau đó
+// chỉ ghi mỗi entry thứ Thereafter (các entry còn lại bị bỏ). Cách lấy mẫu này giống sampler
+// của zap: log lặp lại với tần suất cao bị giới hạn, còn log hiếm vẫn luôn được ghi.
+type SamplingOptions struct {
+	Tick       time.Duration // Độ dài mỗi chu kỳ, 0 để dùng DefaultSamplingTick
+	Initial    int           // Số entry đầu tiên được ghi trong mỗi chu kỳ
+	Thereafter int           // Sau Initial, ghi mỗi entry thứ Thereafter; 0 để bỏ tất cả
+}
+
+// samplerCounter đếm số entry của một khóa trong chu kỳ hiện tại.
+type samplerCounter struct {
+	resetAt atomic.Int64  // Thời điểm (UnixNano) kết thúc chu kỳ hiện tại
+	count   atomic.Uint64 // Số entry trong chu kỳ hiện tại
+}
+
+// incr tăng bộ đếm và trả về số thứ tự của entry trong chu kỳ chứa now.
+func (c *samplerCounter) incr(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	// Chu kỳ mới: chỉ một goroutine được đặt lại bộ đếm, các goroutine khác tiếp tục đếm
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// Sampler quyết định một log entry có được ghi hay không theo SamplingOptions.
+//
+// Entry được nhóm theo cấp độ và thông điệp (với logger: thông điệp trước khi định dạng, kèm
+// context). Sampler không dùng lock và an toàn khi dùng đồng thời.
+type Sampler struct {
+	tick       int64
+	initial    uint64
+	thereafter uint64
+	counters   [FatalLevel + 1][samplerCounters]samplerCounter
+	dropped    atomic.Uint64
+	now        func() time.Time
+}
+
+// NewSampler tạo một Sampler với các tùy chọn đã cho.
+//
+// Tham số:
+//   - opts: SamplingOptions - cấu hình lấy mẫu
+//
+// Trả về:
+//   - *Sampler: sampler đã được khởi tạo
+//
+// Ví dụ:
+//
+//	// Mỗi giây ghi 100 entry đầu tiên của mỗi thông điệp, sau đó cứ 100 entry ghi 1
+//	sampler := handler.NewSampler(handler.SamplingOptions{Initial: 100, Thereafter: 100})
+func NewSampler(opts SamplingOptions) *Sampler {
+	tick := opts.Tick
+	if tick <= 0 {
+		tick = DefaultSamplingTick
+	}
+	s := &Sampler{
+		tick: int64(tick),
+		now:  time.Now,
+	}
+	if opts.Initial > 0 {
+		s.initial = uint64(opts.Initial)
+	}
+	if opts.Thereafter > 0 {
+		s.thereafter = uint64(opts.Thereafter)
+	}
+	return s
+}
+
+// Allow kiểm tra entry có cấp độ và thông điệp đã cho có được ghi hay không.
+//
+// Tham số:
+//   - level: Level - cấp độ của entry
+//   - message: string - khóa nhóm của entry (thường là thông điệp)
+//
+// Trả về:
+//   - bool: true nếu entry được ghi, false nếu entry bị bỏ
+func (s *Sampler) Allow(level Level, message string) bool {
+	return s.allow(level, hashString(fnvOffset, message))
+}
+
+// AllowContext giống Allow nhưng nhóm entry theo cả context và thông điệp, để cùng một thông
+// điệp từ các logger khác nhau được lấy mẫu riêng mà không cần nối chuỗi.
+//
+// Tham số:
+//   - level: Level - cấp độ của entry
+//   - context: string - context của logger
+//   - message: string - thông điệp của entry
+//
+// Trả về:
+//   - bool: true nếu entry được ghi, false nếu entry bị bỏ
+func (s *Sampler) AllowContext(level Level, context, message string) bool {
+	h := hashString(hashString(fnvOffset, context), "\x00") // Phân tách context và message
+	return s.allow(level, hashString(h, message))
+}
+
+// Dropped trả về tổng số entry đã bị bỏ bởi sampler.
+//
+// Trả về:
+//   - uint64: số entry bị bỏ kể từ khi tạo sampler
+func (s *Sampler) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// allow áp dụng quy tắc lấy mẫu cho khóa có hash đã cho.
+func (s *Sampler) allow(level Level, hash uint32) bool {
+	if level < DebugLevel || level > FatalLevel {
+		return true
+	}
+	n := s.counters[level][hash%samplerCounters].incr(s.now().UnixNano(), s.tick)
+	if n <= s.initial || (s.thereafter > 0 && (n-s.initial)%s.thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Hằng số của hàm băm FNV-1a 32-bit.
+const (
+	fnvOffset uint32 = 2166136261
+	fnvPrime  uint32 = 16777619
+)
+
+// hashString tiếp tục băm FNV-1a từ h với các byte của s, không cấp phát bộ nhớ.
+func hashString(h uint32, s string) uint32 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+// SamplingHandler bọc một handler và bỏ bớt các entry lặp lại theo Sampler.
+//
+// Entry được nhóm theo thông điệp đã định dạng, nên thông điệp chứa giá trị thay đổi (VD: field)
+// được xem là khác nhau; dùng Config.Sampling của Manager để lấy mẫu theo thông điệp trước khi
+// định dạng. Handler này phù hợp để giới hạn riêng một đích ghi tốn kém (VD: sink từ xa).
+type SamplingHandler struct {
+	handler Handler
+	sampler *Sampler
+}
+
+// NewSamplingHandler tạo một handler lấy mẫu các entry trước khi chuyển đến h.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry được giữ lại
+//   - opts: SamplingOptions - cấu hình lấy mẫu
+//
+// Trả về:
+//   - *SamplingHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	// Sink từ xa chỉ nhận tối đa 10 entry giống nhau mỗi giây, sau đó 1/1000
+//	sampled := handler.NewSamplingHandler(lokiHandler, handler.SamplingOptions{Initial: 10, Thereafter: 1000})
+//	manager.AddHandler("loki", sampled)
+func NewSamplingHandler(h Handler, opts SamplingOptions) *SamplingHandler {
+	return &SamplingHandler{
+		handler: h,
+		sampler: NewSampler(opts),
+	}
+}
+
+// Log chuyển entry đến handler bên trong nếu entry được sampler giữ lại.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong, hoặc nil nếu entry bị bỏ
+func (s *SamplingHandler) Log(level Level, message string, args ...interface{}) error {
+	if !s.sampler.Allow(level, message) {
+		return nil
+	}
+	return s.handler.Log(level, message, args...)
+}
+
+// LogEntry chuyển entry hoàn chỉnh đến handler bên trong nếu entry được sampler giữ lại.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong, hoặc nil nếu entry bị bỏ
+func (s *SamplingHandler) LogEntry(entry *Entry) error {
+	if !s.sampler.Allow(entry.Level, entry.Message) {
+		return nil
+	}
+	return Dispatch(s.handler, entry)
+}
+
+// Dropped trả về số entry đã bị bỏ bởi handler.
+//
+// Trả về:
+//   - uint64: số entry bị bỏ
+func (s *SamplingHandler) Dropped() uint64 {
+	return s.sampler.Dropped()
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (s *SamplingHandler) Unwrap() Handler {
+	return s.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (s *SamplingHandler) Close() error {
+	return s.handler.Close()
+}
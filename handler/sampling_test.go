@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSampler_InitialAndThereafter(t *testing.T) {
+	s := NewSampler(SamplingOptions{Initial: 3, Thereafter: 5})
+
+	var kept []int
+	for i := 1; i <= 20; i++ {
+		if s.Allow(InfoLevel, "burst") {
+			kept = append(kept, i)
+		}
+	}
+	want := []int{1, 2, 3, 8, 13, 18}
+	if len(kept) != len(want) {
+		t.Fatalf("Sampler giữ lại %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Fatalf("Sampler giữ lại %v, want %v", kept, want)
+		}
+	}
+	if got := s.Dropped(); got != 14 {
+		t.Errorf("Dropped() = %d, want 14", got)
+	}
+
+	// Cấp độ khác và thông điệp khác dùng bộ đếm riêng
+	if !s.Allow(ErrorLevel, "burst") || !s.Allow(InfoLevel, "other") {
+		t.Error("Cấp độ và thông điệp khác nên có bộ đếm riêng")
+	}
+}
+
+func TestSampler_ThereafterZeroDropsAll(t *testing.T) {
+	s := NewSampler(SamplingOptions{Initial: 1})
+	if !s.Allow(DebugLevel, "x") {
+		t.Fatal("Entry đầu tiên nên được giữ lại")
+	}
+	for i := 0; i < 10; i++ {
+		if s.Allow(DebugLevel, "x") {
+			t.Fatal("Thereafter = 0 nên bỏ tất cả entry sau Initial")
+		}
+	}
+}
+
+func TestSampler_TickReset(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSampler(SamplingOptions{Initial: 1, Tick: time.Second})
+	s.now = func() time.Time { return now }
+
+	if !s.Allow(InfoLevel, "x") || s.Allow(InfoLevel, "x") {
+		t.Fatal("Chỉ entry đầu tiên trong chu kỳ nên được giữ lại")
+	}
+	now = now.Add(time.Second)
+	if !s.Allow(InfoLevel, "x") {
+		t.Error("Bộ đếm nên được đặt lại ở chu kỳ mới")
+	}
+}
+
+func TestSampler_AllowContext(t *testing.T) {
+	s := NewSampler(SamplingOptions{Initial: 1})
+	if !s.AllowContext(InfoLevel, "API", "started") || !s.AllowContext(InfoLevel, "Worker", "started") {
+		t.Error("Cùng thông điệp từ các context khác nhau nên được lấy mẫu riêng")
+	}
+	if s.AllowContext(InfoLevel, "API", "started") {
+		t.Error("Entry lặp lại của cùng context nên bị bỏ")
+	}
+}
+
+func TestSampler_Concurrent(t *testing.T) {
+	s := NewSampler(SamplingOptions{Initial: 100, Thereafter: 10, Tick: time.Hour})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	kept := 0
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := 0
+			for i := 0; i < 1000; i++ {
+				if s.Allow(WarningLevel, "hot") {
+					n++
+				}
+			}
+			mu.Lock()
+			kept += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// 8000 entry: 100 đầu tiên, sau đó 1/10 của 7900 còn lại
+	if kept != 890 {
+		t.Errorf("Sampler giữ lại %d entry, want 890", kept)
+	}
+	if got := s.Dropped(); got != 8000-890 {
+		t.Errorf("Dropped() = %d, want %d", got, 8000-890)
+	}
+}
+
+func TestSamplingHandler(t *testing.T) {
+	inner := &MockTestHandler{}
+	h := NewSamplingHandler(inner, SamplingOptions{Initial: 1})
+
+	for i := 0; i < 3; i++ {
+		if err := h.Log(InfoLevel, "same"); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+	if err := h.LogEntry(&Entry{Level: InfoLevel, Message: "same", Time: time.Now()}); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+	if !inner.LogCalled || inner.LogMessage != "same" {
+		t.Error("Entry đầu tiên nên được chuyển đến handler bên trong")
+	}
+	if h.Dropped() != 3 {
+		t.Errorf("Dropped() = %d, want 3", h.Dropped())
+	}
+	if h.Unwrap() != inner {
+		t.Error("Unwrap() nên trả về handler bên trong")
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"unicode/utf8"
+)
+
+// hexDigits là các chữ số hex dùng khi thoát ký tự.
+const hexDigits = "0123456789abcdef"
+
+// Sanitize thoát các ký tự có thể giả mạo dòng log hoặc điều khiển terminal trong s, để dữ liệu
+// từ bên ngoài (VD: header, tham số request) không tạo được dòng log giả hay chèn mã escape ANSI:
+//
+//   - '\n' và '\r' thành `\n` và `\r`
+//   - ký tự điều khiển C0 khác (trừ tab) và DEL thành `\xNN`
+//   - ký tự điều khiển C1, U+2028, U+2029 và các ký tự điều khiển hướng văn bản (bidi) thành `\uNNNN`
+//   - byte UTF-8 không hợp lệ thành `\xNN`
+//
+// Dấu gạch chéo ngược không được thoát nên kết quả dùng để đọc an toàn, không dùng để khôi phục
+// chuỗi gốc. Chuỗi không chứa ký tự cần thoát được trả về nguyên vẹn, không cấp phát bộ nhớ.
+//
+// Tham số:
+//   - s: string - chuỗi cần làm sạch
+//
+// Trả về:
+//   - string: chuỗi đã thoát các ký tự nguy hiểm
+//
+// Ví dụ:
+//
+//	handler.Sanitize("login failed\n2024/03/01 12:00:00 [INFO] admin logged in")
+//	// `login failed\n2024/03/01 12:00:00 [INFO] admin logged in` trên một dòng
+func Sanitize(s string) string {
+	i := unsafeIndex(s)
+	if i < 0 {
+		return s
+	}
+
+	b := make([]byte, 0, len(s)+16)
+	b = append(b, s[:i]...)
+	for i < len(s) {
+		size, unsafe := scanUnsafe(s, i)
+		if !unsafe {
+			b = append(b, s[i:i+size]...)
+			i += size
+			continue
+		}
+		switch c := s[i]; {
+		case c == '\n':
+			b = append(b, `\n`...)
+		case c == '\r':
+			b = append(b, `\r`...)
+		case c < utf8.RuneSelf || size == 1:
+			b = append(b, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			b = append(b, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+		}
+		i += size
+	}
+	return string(b)
+}
+
+// unsafeIndex trả về vị trí của ký tự đầu tiên trong s cần được Sanitize thoát, hoặc -1 nếu
+// không có.
+func unsafeIndex(s string) int {
+	for i := 0; i < len(s); {
+		size, unsafe := scanUnsafe(s, i)
+		if unsafe {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// scanUnsafe trả về độ dài của ký tự bắt đầu tại s[i] và ký tự đó có cần được Sanitize thoát
+// hay không.
+func scanUnsafe(s string, i int) (size int, unsafe bool) {
+	if c := s[i]; c < utf8.RuneSelf {
+		return 1, (c < 0x20 && c != '\t') || c == 0x7f
+	}
+	r, size := utf8.DecodeRuneInString(s[i:])
+	switch {
+	case r == utf8.RuneError && size == 1:
+		return 1, true
+	case r <= 0x9f, r == 0x2028, r == 0x2029, r >= 0x202a && r <= 0x202e, r >= 0x2066 && r <= 0x2069:
+		return size, true
+	default:
+		return size, false
+	}
+}
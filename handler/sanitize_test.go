@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text, tab\tand Tiếng Việt ✓", "plain text, tab\tand Tiếng Việt ✓"},
+		{"failed\n2024/03/01 12:00:00 [INFO] forged", `failed\n2024/03/01 12:00:00 [INFO] forged`},
+		{"a\r\nb", `a\r\nb`},
+		{"nul\x00 bell\x07 esc\x1b[31m del\x7f", `nul\x00 bell\x07 esc\x1b[31m del\x7f`},
+		{"c1\u0085 ls\u2028 rlo\u202e isolate\u2066", `c1\u0085 ls\u2028 rlo\u202e isolate\u2066`},
+		{"bad\xff\xfeutf8", `bad\xff\xfeutf8`},
+		{`C:\logs\app.log`, `C:\logs\app.log`},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(tt.in); got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAppendFields_QuotesUnsafeValues(t *testing.T) {
+	fields := []Field{
+		{Key: "user", Type: StringType, Str: "alice\x1b[2J"},
+		{Key: "path", Value: "/a\u202eb"},
+		{Key: "err", Value: errors.New("line1\nline2")},
+	}
+	got := string(AppendFields(nil, fields))
+	if want := `user="alice\x1b[2J" path="/a\u202eb" err="line1\nline2"`; got != want {
+		t.Errorf("AppendFields() = %s, want %s", got, want)
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	for _, seed := range []string{"", "plain", "a\nb\r\x00\x1b[0m", "\u2028\u202e\u0085", "\xff\xc3"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		got := Sanitize(s)
+		if strings.ContainsAny(got, "\n\r\x00\x1b\x7f\u2028\u2029\u202e\u0085") || !utf8.ValidString(got) {
+			t.Errorf("Sanitize(%q) = %q vẫn chứa ký tự không an toàn", s, got)
+		}
+		if Sanitize(got) != got {
+			t.Errorf("Sanitize() nên ổn định khi áp dụng lại, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Device mô tả thiết bị phát sinh log trong header của CEF và LEEF, được SIEM dùng để phân loại
+// và chọn bộ phân tích cho nguồn log.
+type Device struct {
+	Vendor  string // Nhà cung cấp (mặc định "go.fork.vn")
+	Product string // Sản phẩm (mặc định "log")
+	Version string // Phiên bản (mặc định "1.0")
+}
+
+// withDefaults trả về device với giá trị mặc định cho các trường rỗng.
+func (d Device) withDefaults() Device {
+	if d.Vendor == "" {
+		d.Vendor = "go.fork.vn"
+	}
+	if d.Product == "" {
+		d.Product = "log"
+	}
+	if d.Version == "" {
+		d.Version = "1.0"
+	}
+	return d
+}
+
+// SetDevice đặt thông tin thiết bị trong header của các dòng CEFFormat và LEEFFormat. Method
+// này là thread-safe.
+//
+// Tham số:
+//   - device: Device - thông tin thiết bị, trường rỗng dùng giá trị mặc định
+//
+// Ví dụ:
+//
+//	fileHandler.SetFormat(handler.CEFFormat)
+//	fileHandler.SetDevice(handler.Device{Vendor: "Acme", Product: "Orders", Version: "1.4.2"})
+func (a *FileHandler) SetDevice(device Device) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.device = device
+}
+
+// leefTimeLayout là định dạng devTime của LEEF, được khai báo cho SIEM qua devTimeFormat
+// (leefTimeFormat, theo cú pháp SimpleDateFormat của Java).
+const (
+	leefTimeLayout = "Jan 02 2006 15:04:05.000 MST"
+	leefTimeFormat = "MMM dd yyyy HH:mm:ss.SSS z"
+)
+
+// FieldEventID là field key dùng làm Signature ID của CEF và Event ID của LEEF. Entry không có
+// field này dùng context của logger (VD: "Audit"), hoặc "log".
+const FieldEventID = "event_id"
+
+// cefKeys và leefKeys là các key CEFFormat và LEEFFormat dùng cho thuộc tính của entry; field trùng
+// tên được ghi với ReservedFieldPrefix (VD: "fields_rt").
+var (
+	cefKeys  = []string{"rt", "context"}
+	leefKeys = []string{"devTime", "devTimeFormat", "sev", "context", "msg"}
+)
+
+// siemKeys ánh xạ field của entry sang key chuẩn của CEF và LEEF, để SIEM nhận diện mà không
+// cần ánh xạ tùy chỉnh.
+var siemKeys = map[string][2]string{
+	"remote_ip":  {"src", "src"},
+	"user":       {"suser", "usrName"},
+	"method":     {"requestMethod", "method"},
+	"path":       {"request", "url"},
+	"user_agent": {"requestClientApplication", "userAgent"},
+}
+
+// cefSeverity là mức nghiêm trọng của CEF (0-10) theo cấp độ log; LEEF dùng cùng thang đo.
+func cefSeverity(level Level) int {
+	switch level {
+	case DebugLevel:
+		return 1
+	case InfoLevel:
+		return 3
+	case WarningLevel:
+		return 5
+	case ErrorLevel:
+		return 8
+	default:
+		return 10
+	}
+}
+
+// siemEvent trả về event ID của entry: field FieldEventID, context của logger, hoặc "log".
+func siemEvent(entry *Entry) string {
+	if id, ok := accessField(entry, FieldEventID); ok {
+		return id
+	}
+	if context := entry.Context(); context != "" {
+		return context
+	}
+	return "log"
+}
+
+// appendCEFLine nối entry dạng ArcSight Common Event Format vào cuối dst, VD:
+//
+//	CEF:0|Acme|Orders|1.4.2|Audit|Role granted|3|rt=1709294400000 context=Audit suser=alice
+func appendCEFLine(dst []byte, entry *Entry, device Device) []byte {
+	device = device.withDefaults()
+	dst = append(dst, "CEF:0|"...)
+	for _, value := range []string{device.Vendor, device.Product, device.Version, siemEvent(entry), entry.text()} {
+		dst = appendSIEMEscaped(dst, value, "\\|")
+		dst = append(dst, '|')
+	}
+	dst = strconv.AppendInt(dst, int64(cefSeverity(entry.Level)), 10)
+	dst = append(dst, "|rt="...)
+	dst = strconv.AppendInt(dst, entry.Time.UnixMilli(), 10)
+	if context := entry.Context(); context != "" {
+		dst = append(dst, " context="...)
+		dst = appendSIEMEscaped(dst, context, "\\=")
+	}
+	for _, f := range entry.Fields {
+		if f.Key == FieldEventID {
+			continue
+		}
+		value, _ := accessField(entry, f.Key)
+		dst = append(dst, ' ')
+		dst = append(dst, siemKey(fieldKey(f.Key, cefKeys), 0)...)
+		dst = append(dst, '=')
+		dst = appendSIEMEscaped(dst, value, "\\=")
+	}
+	return append(dst, '\n')
+}
+
+// appendLEEFLine nối entry dạng IBM QRadar Log Event Extended Format 1.0 vào cuối dst, các
+// thuộc tính cách nhau bởi tab, VD:
+//
+//	LEEF:1.0|Acme|Orders|1.4.2|Audit|devTime=Mar 01 2024 12:00:00.000 UTC	devTimeFormat=MMM dd yyyy HH:mm:ss.SSS z	sev=3	context=Audit	usrName=alice	msg=Role granted
+func appendLEEFLine(dst []byte, entry *Entry, device Device) []byte {
+	device = device.withDefaults()
+	dst = append(dst, "LEEF:1.0|"...)
+	for _, value := range []string{device.Vendor, device.Product, device.Version, siemEvent(entry)} {
+		dst = appendSIEMEscaped(dst, value, "\\|")
+		dst = append(dst, '|')
+	}
+	dst = append(dst, "devTime="...)
+	dst = entry.Time.AppendFormat(dst, leefTimeLayout)
+	dst = append(dst, "\tdevTimeFormat="+leefTimeFormat+"\tsev="...)
+	dst = strconv.AppendInt(dst, int64(cefSeverity(entry.Level)), 10)
+	if context := entry.Context(); context != "" {
+		dst = append(dst, "\tcontext="...)
+		dst = appendSIEMEscaped(dst, context, "")
+	}
+	for _, f := range entry.Fields {
+		if f.Key == FieldEventID {
+			continue
+		}
+		value, _ := accessField(entry, f.Key)
+		dst = append(dst, '\t')
+		dst = append(dst, siemKey(fieldKey(f.Key, leefKeys), 1)...)
+		dst = append(dst, '=')
+		dst = appendSIEMEscaped(dst, value, "")
+	}
+	dst = append(dst, "\tmsg="...)
+	dst = appendSIEMEscaped(dst, entry.text(), "")
+	return append(dst, '\n')
+}
+
+// siemKey trả về key chuẩn của field theo định dạng (0 = CEF, 1 = LEEF), hoặc key của field với
+// các ký tự không phải chữ, số hoặc '_' được thay bằng '_'.
+func siemKey(key string, format int) string {
+	if keys, ok := siemKeys[key]; ok {
+		return keys[format]
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// appendSIEMEscaped nối value vào cuối dst, escape dấu gạch chéo ngược, các ký tự trong special
+// và xuống dòng/tab để mỗi bản ghi nằm trên một dòng.
+func appendSIEMEscaped(dst []byte, value, special string) []byte {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\n':
+			dst = append(dst, `\n`...)
+		case c == '\r':
+			dst = append(dst, `\r`...)
+		case c == '\t':
+			dst = append(dst, `\t`...)
+		case strings.IndexByte(special, c) >= 0:
+			dst = append(dst, '\\', c)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
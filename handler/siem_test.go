@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileHandler_SIEMFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "security.log")
+	h, err := NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+
+	entry := &Entry{
+		Time:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Level:   WarningLevel,
+		Message: "[Audit] Login failed|locked user=alice reason=\"a=b\\nc\" attempts=3",
+		Text:    "Login failed|locked",
+		Fields: []Field{
+			{Key: "user", Type: StringType, Str: "alice"},
+			{Key: "reason", Type: StringType, Str: "a=b\nc"},
+			{Key: "attempts", Type: Int64Type, Integer: 3},
+			{Key: "rt", Type: StringType, Str: "edge"},
+		},
+	}
+	h.SetFormat(CEFFormat)
+	_ = h.LogEntry(entry)
+	h.SetDevice(Device{Vendor: "Acme", Product: "Orders", Version: "1.4.2"})
+	h.SetFormat(LEEFFormat)
+	_ = h.LogEntry(&Entry{Time: entry.Time, Level: ErrorLevel, Message: "denied", Fields: []Field{
+		{Key: FieldEventID, Type: StringType, Str: "AUTH-403"},
+		{Key: "remote_ip", Type: StringType, Str: "203.0.113.7"},
+		{Key: "msg", Type: StringType, Str: "spoofed"},
+	}})
+	h.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `CEF:0|go.fork.vn|log|1.0|Audit|Login failed\|locked|5|rt=1709294400000 context=Audit suser=alice reason=a\=b\nc attempts=3 fields_rt=edge` + "\n" +
+		"LEEF:1.0|Acme|Orders|1.4.2|AUTH-403|devTime=Mar 01 2024 12:00:00.000 UTC\tdevTimeFormat=MMM dd yyyy HH:mm:ss.SSS z\tsev=8\tsrc=203.0.113.7\tfields_msg=spoofed\tmsg=denied\n"
+	if string(data) != want {
+		t.Errorf("Output = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Signing cấu hình chữ ký HMAC-SHA256 của FileHandler, để bên nhận log (VD: SIEM, bộ lưu trữ
+// kiểm toán) xác minh log bảo mật không bị sửa và đến từ nơi giữ khóa.
+type Signing struct {
+	Key      []byte // Khóa HMAC bí mật, nên dài ít nhất 32 byte
+	Records  bool   // Ký từng dòng log (xem VerifyLine)
+	Segments bool   // Ghi file <file sao lưu>.sig chứa HMAC của file sau mỗi lần xoay vòng (xem VerifySegment)
+}
+
+// FieldSignature là key của chữ ký HMAC được thêm vào mỗi dòng log khi bật Signing.Records.
+const FieldSignature = "sig"
+
+// signatureExtension là phần mở rộng của file chữ ký của file sao lưu.
+const signatureExtension = ".sig"
+
+// SetSigning bật chữ ký HMAC-SHA256 cho các dòng log được ghi sau lời gọi và các file sao lưu
+// được xoay vòng sau lời gọi. Method này là thread-safe.
+//
+// Với Records, chữ ký của dòng (không gồm ký tự xuống dòng) được thêm vào cuối dòng: field "sig"
+// với JSONFormat, thuộc tính "sig" ngăn cách bằng tab với LEEFFormat và " sig=<hex>" với các định
+// dạng khác. Mỗi dòng được ký độc lập nên chữ ký không phát hiện việc xóa cả dòng; dùng Segments
+// để ký toàn bộ file sao lưu. File đang được ghi chỉ được ký khi được xoay vòng.
+//
+// Tham số:
+//   - signing: Signing - khóa và phạm vi ký; Key rỗng để tắt
+//
+// Ví dụ:
+//
+//	fileHandler.SetSigning(handler.Signing{Key: key, Records: true, Segments: true})
+//	// 2024/03/01 12:00:00 [INFO] [Audit] role granted user=alice sig=5d41402a...
+func (a *FileHandler) SetSigning(signing Signing) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	signing.Key = bytes.Clone(signing.Key)
+	a.signing = signing
+}
+
+// signatureOf trả về HMAC-SHA256 của data với key.
+func signatureOf(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// appendSignature thêm chữ ký của dòng log line (kết thúc bằng '\n') vào cuối dòng theo định dạng.
+func appendSignature(line []byte, key []byte, format Format) []byte {
+	body := bytes.TrimSuffix(line, []byte{'\n'})
+	var sig [sha256.Size * 2]byte
+	hex.Encode(sig[:], signatureOf(key, body))
+
+	switch {
+	case format == JSONFormat && bytes.HasSuffix(body, []byte{'}'}):
+		line = append(body[:len(body)-1], `,"`+FieldSignature+`":"`...)
+		line = append(line, sig[:]...)
+		line = append(line, `"}`...)
+	case format == LEEFFormat:
+		line = append(body, "\t"+FieldSignature+"="...)
+		line = append(line, sig[:]...)
+	default:
+		line = append(body, " "+FieldSignature+"="...)
+		line = append(line, sig[:]...)
+	}
+	return append(line, '\n')
+}
+
+// VerifyLine kiểm tra chữ ký HMAC của một dòng log do FileHandler ghi với Signing.Records.
+//
+// Tham số:
+//   - key: []byte - khóa HMAC đã dùng để ký
+//   - line: []byte - một dòng log, có hoặc không có ký tự xuống dòng ở cuối
+//
+// Trả về:
+//   - bool: true nếu dòng có chữ ký hợp lệ; false nếu không có chữ ký hoặc dòng đã bị sửa
+//
+// Ví dụ:
+//
+//	scanner := bufio.NewScanner(file)
+//	for scanner.Scan() {
+//	    if !handler.VerifyLine(key, scanner.Bytes()) {
+//	        fmt.Println("tampered:", scanner.Text())
+//	    }
+//	}
+func VerifyLine(key, line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	const hexLen = sha256.Size * 2
+
+	var body, sig []byte
+	jsonTail := len(`,"`+FieldSignature+`":"`) + hexLen + len(`"}`)
+	textTail := len(" "+FieldSignature+"=") + hexLen
+	switch {
+	case len(line) >= jsonTail && bytes.HasSuffix(line, []byte(`"}`)) &&
+		string(line[len(line)-jsonTail:len(line)-jsonTail+len(`,"`+FieldSignature+`":"`)]) == `,"`+FieldSignature+`":"`:
+		sig = line[len(line)-hexLen-2 : len(line)-2]
+		body = append(bytes.Clone(line[:len(line)-jsonTail]), '}')
+	case len(line) >= textTail && (line[len(line)-textTail] == ' ' || line[len(line)-textTail] == '\t') &&
+		string(line[len(line)-textTail+1:len(line)-hexLen]) == FieldSignature+"=":
+		sig = line[len(line)-hexLen:]
+		body = line[:len(line)-textTail]
+	default:
+		return false
+	}
+
+	want := make([]byte, sha256.Size)
+	if _, err := hex.Decode(want, sig); err != nil {
+		return false
+	}
+	return hmac.Equal(want, signatureOf(key, body))
+}
+
+// signSegment ghi HMAC của file path vào file path + ".sig" dạng hex.
+func signSegment(path string, key []byte) error {
+	sum, err := segmentSignature(path, key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+signatureExtension, []byte(hex.EncodeToString(sum)+"\n"), 0644)
+}
+
+// segmentSignature trả về HMAC-SHA256 của nội dung file path.
+func segmentSignature(path string, key []byte) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mac := hmac.New(sha256.New, key)
+	if _, err := io.Copy(mac, file); err != nil {
+		return nil, err
+	}
+	return mac.Sum(nil), nil
+}
+
+// VerifySegment kiểm tra file sao lưu (đã nén hoặc chưa) với chữ ký trong file path + ".sig" do
+// FileHandler ghi với Signing.Segments.
+//
+// Tham số:
+//   - key: []byte - khóa HMAC đã dùng để ký
+//   - path: string - đường dẫn file sao lưu, VD: "app.log.20240301120000.gz"
+//
+// Trả về:
+//   - bool: true nếu nội dung file khớp chữ ký
+//   - error: lỗi khi đọc file hoặc file chữ ký không hợp lệ
+func VerifySegment(key []byte, path string) (bool, error) {
+	data, err := os.ReadFile(path + signatureExtension)
+	if err != nil {
+		return false, err
+	}
+	want, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return false, fmt.Errorf("invalid signature file %s: %w", path+signatureExtension, err)
+	}
+	sum, err := segmentSignature(path, key)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(want, sum), nil
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSigningKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestFileHandler_SetSigning_Records(t *testing.T) {
+	for _, format := range []Format{TextFormat, JSONFormat, LEEFFormat} {
+		t.Run(format.String(), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "signed.log")
+			h, err := NewFileHandler(path, 0)
+			if err != nil {
+				t.Fatalf("NewFileHandler() error = %v", err)
+			}
+			h.SetFormat(format)
+			h.SetSigning(Signing{Key: testSigningKey, Records: true})
+			_ = h.LogEntry(&Entry{Level: InfoLevel, Message: "[Audit] role granted user=alice", Fields: []Field{{Key: "user", Type: StringType, Str: "alice"}}})
+			_ = h.Log(WarningLevel, "second")
+			_ = h.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+			if len(lines) != 2 {
+				t.Fatalf("Nên có 2 dòng, got %q", data)
+			}
+			for _, line := range lines {
+				if !VerifyLine(testSigningKey, line) {
+					t.Errorf("VerifyLine() = false cho dòng hợp lệ %q", line)
+				}
+				if VerifyLine([]byte("another key 0123456789abcdef"), line) {
+					t.Errorf("VerifyLine() với khóa khác nên = false, line %q", line)
+				}
+			}
+			if format == JSONFormat && !bytes.Contains(lines[0], []byte(`,"`+FieldSignature+`":"`)) {
+				t.Errorf("Dòng JSON nên có field sig, got %q", lines[0])
+			}
+
+			tampered := bytes.Replace(lines[0], []byte("alice"), []byte("mallory"), 1)
+			if VerifyLine(testSigningKey, tampered) {
+				t.Errorf("VerifyLine() nên = false khi dòng bị sửa: %q", tampered)
+			}
+		})
+	}
+}
+
+func TestVerifyLine_Unsigned(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"2024/03/01 12:00:00 [INFO] plain",
+		`{"time":"2024/03/01 12:00:00","level":"INFO","message":"plain"}`,
+		"2024/03/01 12:00:00 [INFO] plain sig=" + strings.Repeat("z", 64),
+	} {
+		if VerifyLine(testSigningKey, []byte(line)) {
+			t.Errorf("VerifyLine(%q) = true, want false", line)
+		}
+	}
+}
+
+func TestFileHandler_SetSigning_Segments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "segment.log")
+	h, err := NewFileHandler(path, 200)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	codec, _ := LookupCodec("gzip")
+	h.SetCompression(codec)
+	h.SetSigning(Signing{Key: testSigningKey, Segments: true})
+
+	_ = h.Log(InfoLevel, "before rotation %s", strings.Repeat("x", 200))
+	_ = h.Log(InfoLevel, "after rotation")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Close chờ ký xong; file chữ ký không được tính là file sao lưu
+	backups := backupPaths(path)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("Nên có một file sao lưu đã nén, got %v", backups)
+	}
+	if ok, err := VerifySegment(testSigningKey, backups[0]); err != nil || !ok {
+		t.Fatalf("VerifySegment() = %v, %v, want true", ok, err)
+	}
+	if _, err := os.Stat(path + signatureExtension); !os.IsNotExist(err) {
+		t.Errorf("File đang ghi không nên được ký, got %v", err)
+	}
+
+	if err := os.WriteFile(backups[0], []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifySegment(testSigningKey, backups[0]); err != nil || ok {
+		t.Errorf("VerifySegment() sau khi sửa file = %v, %v, want false", ok, err)
+	}
+}
+
+func TestPruneBackups_RemovesSignature(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	for _, name := range []string{"app.log.20240101120002", "app.log.20240101120001", "app.log.20240101120001.sig"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruneBackups(path, BackupRetention{MaxBackups: 1}, time.Now())
+
+	if _, err := os.Stat(filepath.Join(dir, "app.log.20240101120001.sig")); !os.IsNotExist(err) {
+		t.Errorf("File chữ ký của file sao lưu bị xóa nên bị xóa theo, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.log.20240101120002")); err != nil {
+		t.Errorf("File sao lưu mới nhất nên được giữ, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Khoảng chờ trước khi gửi lại file spill sau một lần ghi thất bại; khoảng chờ tăng gấp đôi sau
+// mỗi lần gửi lại thất bại, đến tối đa spillMaxRetryDelay.
+const (
+	spillRetryDelay    = time.Second
+	spillMaxRetryDelay = time.Minute
+)
+
+// spillRecord là định dạng của một entry được lưu trong file spill, mỗi dòng một record JSON.
+type spillRecord struct {
+	Time    time.Time    `json:"time"`
+	Level   Level        `json:"level"`
+	Message string       `json:"message"`
+	Text    string       `json:"text,omitempty"`
+	Fields  []spillField `json:"fields,omitempty"`
+}
+
+// spillField là một field có cấu trúc trong spillRecord. Field có kiểu được lưu nguyên vẹn; giá
+// trị của field AnyType được lưu dạng JSON nên được gửi lại dưới dạng kiểu JSON tổng quát (VD:
+// float64, map[string]interface{}).
+type spillField struct {
+	Key     string          `json:"key"`
+	Type    FieldType       `json:"type,omitempty"`
+	Integer int64           `json:"integer,omitempty"`
+	Str     string          `json:"str,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+}
+
+// SpillHandler bọc một handler và lưu các entry ghi thất bại xuống đĩa để gửi lại sau.
+//
+// Khi handler được bọc trả về lỗi, entry được nối vào file spill và các entry sau cũng được nối
+// vào file (để giữ thứ tự) cho đến khi gửi lại thành công. Việc gửi lại chỉ được thử sau một
+// khoảng chờ tăng dần từ 1 giây đến 1 phút, nên khi handler được bọc ngừng hoạt động, mỗi lần
+// ghi chỉ nối một dòng vào file. Vị trí đã gửi lại được giữ trong bộ nhớ và file được xóa khi
+// đã gửi lại hết. Entry còn lại trong file spill từ lần chạy trước cũng được gửi lại, nên mỗi
+// entry được ghi ít nhất một lần nhưng có thể bị ghi lặp nếu tiến trình dừng giữa lúc gửi lại.
+type SpillHandler struct {
+	handler Handler       // Handler được bọc
+	path    string        // Đường dẫn file spill
+	pending bool          // File spill còn entry chưa được gửi lại
+	offset  int64         // Số byte đầu file spill đã được gửi lại
+	backoff time.Duration // Khoảng chờ hiện tại giữa hai lần gửi lại
+	retryAt time.Time     // Thời điểm sớm nhất được thử gửi lại
+	clock   clockRef      // Nguồn thời điểm của entry ghi qua Log và của khoảng chờ gửi lại
+	mu      sync.Mutex    // Đảm bảo thứ tự gửi và truy cập file spill
+}
+
+// NewSpillHandler tạo một SpillHandler bọc h, lưu các entry ghi thất bại vào path.
+//
+// Thư mục chứa file spill phải tồn tại; file được tạo khi có entry đầu tiên cần lưu.
+//
+// Tham số:
+//   - h: Handler - handler được bọc
+//   - path: string - đường dẫn file spill
+//
+// Trả về:
+//   - *SpillHandler: handler đã được cấu hình
+//
+// Ví dụ:
+//
+//	remote := handler.NewSpillHandler(lokiHandler, "storage/logs/loki.spill")
+func NewSpillHandler(h Handler, path string) *SpillHandler {
+	s := &SpillHandler{handler: h, path: path}
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		s.pending = true
+	}
+	return s
+}
+
+// Log ghi một log entry với timestamp hiện tại.
+//
+// Tham số:
+//   - level: Level - cấp độ nghiêm trọng của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - các tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi nếu entry không thể được ghi hoặc lưu vào file spill
+func (s *SpillHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return s.LogEntry(&Entry{Time: s.clock.Now(), Level: level, Message: message})
+}
+
+// LogEntry gửi lại các entry đang chờ trong file spill (khi đã hết khoảng chờ) rồi ghi entry
+// đến handler được bọc, lưu entry vào file spill nếu không thể ghi.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: lỗi nếu entry không thể được ghi và cũng không thể lưu vào file spill
+func (s *SpillHandler) LogEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Giữ thứ tự: entry mới chỉ được ghi trực tiếp khi file spill đã được gửi lại hết
+	now := s.clock.Now()
+	if s.pending {
+		if now.Before(s.retryAt) {
+			return s.spill(entry)
+		}
+		if err := s.replay(); err != nil {
+			s.delay(now)
+			return s.spill(entry)
+		}
+	}
+	if err := Dispatch(s.handler, entry); err != nil {
+		s.delay(now)
+		return s.spill(entry)
+	}
+	s.backoff = 0
+	return nil
+}
+
+// SetClock thay nguồn thời điểm của entry ghi qua Log và của khoảng chờ gửi lại. Method này là
+// thread-safe.
+//
+// Tham số:
+//   - clock: Clock - nguồn thời điểm, nil để dùng SystemClock
+func (s *SpillHandler) SetClock(clock Clock) {
+	s.clock.set(clock)
+}
+
+// Pending cho biết file spill còn entry chưa được gửi lại hay không.
+//
+// Trả về:
+//   - bool: true nếu còn entry đang chờ
+func (s *SpillHandler) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pending
+}
+
+// Health kiểm tra file spill có còn entry chờ gửi lại hay không; entry chờ nghĩa là handler
+// được bọc đang ghi thất bại.
+//
+// Trả về:
+//   - error: lỗi nếu còn entry đang chờ
+func (s *SpillHandler) Health() error {
+	if s.Pending() {
+		return fmt.Errorf("spill file %s has entries pending redelivery", s.path)
+	}
+	return nil
+}
+
+// Close thử gửi lại các entry đang chờ rồi đóng handler được bọc.
+// Entry chưa gửi được vẫn nằm trong file spill cho lần chạy sau.
+//
+// Trả về:
+//   - error: lỗi từ Close của handler được bọc
+func (s *SpillHandler) Close() error {
+	s.mu.Lock()
+	if s.pending {
+		s.replay()
+	}
+	s.mu.Unlock()
+
+	return s.handler.Close()
+}
+
+// Unwrap trả về handler được bọc.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (s *SpillHandler) Unwrap() Handler {
+	return s.handler
+}
+
+// delay lùi lần gửi lại kế tiếp sau một lần ghi thất bại tại now. Method này phải được gọi khi
+// đang giữ lock.
+func (s *SpillHandler) delay(now time.Time) {
+	if s.backoff == 0 {
+		s.backoff = spillRetryDelay
+	} else {
+		s.backoff = min(s.backoff*2, spillMaxRetryDelay)
+	}
+	s.retryAt = now.Add(s.backoff)
+}
+
+// spill nối entry vào cuối file spill. Method này phải được gọi khi đang giữ lock.
+func (s *SpillHandler) spill(entry *Entry) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open spill file: %w", err)
+	}
+	defer file.Close()
+
+	record := spillRecord{Time: entry.Time, Level: entry.Level, Message: entry.Message, Text: entry.Text}
+	if len(entry.Fields) > 0 {
+		record.Fields = make([]spillField, len(entry.Fields))
+		for i, f := range entry.Fields {
+			record.Fields[i] = spillField{Key: f.Key, Type: f.Type, Integer: f.Integer, Str: f.Str}
+			if f.Type == AnyType {
+				record.Fields[i].Value = appendJSONValue(nil, normalize(f.Value, 0, Limits{}.withDefaults()))
+			}
+		}
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode spilled entry: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	s.pending = true
+	return nil
+}
+
+// replay gửi lại các entry trong file spill từ vị trí đã gửi theo thứ tự, dừng ở lỗi đầu tiên
+// và giữ lại các entry chưa gửi được. File spill được xóa khi đã gửi lại hết. Method này phải
+// được gọi khi đang giữ lock.
+func (s *SpillHandler) replay() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		s.pending, s.offset, s.backoff = false, 0, 0
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open spill file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek spill file: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record spillRecord
+			// Bỏ qua dòng hỏng (VD: ghi dở khi tiến trình dừng) thay vì chặn toàn bộ file
+			if err := json.Unmarshal(line, &record); err == nil {
+				if err := Dispatch(s.handler, record.entry()); err != nil {
+					return err
+				}
+			}
+			s.offset += int64(len(line))
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read spill file: %w", readErr)
+		}
+	}
+
+	// Lỗi xóa file chỉ khiến entry đã gửi có thể bị gửi lặp, vẫn đúng cam kết ít nhất một lần
+	s.pending, s.offset, s.backoff = false, 0, 0
+	os.Remove(s.path)
+	return nil
+}
+
+// entry chuyển record thành Entry để gửi lại.
+func (r *spillRecord) entry() *Entry {
+	entry := &Entry{Time: r.Time, Level: r.Level, Message: r.Message, Text: r.Text}
+	if len(r.Fields) > 0 {
+		entry.Fields = make([]Field, len(r.Fields))
+		for i, f := range r.Fields {
+			entry.Fields[i] = Field{Key: f.Key, Type: f.Type, Integer: f.Integer, Str: f.Str}
+			if f.Type == AnyType && len(f.Value) > 0 {
+				json.Unmarshal(f.Value, &entry.Fields[i].Value)
+			}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// flakyRecorder ghi lại các entry, trả về lỗi khi failing được bật
+type flakyRecorder struct {
+	slowRecorder
+	failing  bool
+	syncs    int
+	attempts int
+	failAt   int // Số entry ghi thành công trước khi lỗi trở lại (0 = không giới hạn)
+}
+
+func (f *flakyRecorder) LogEntry(entry *Entry) error {
+	f.attempts++
+	if f.failing || (f.failAt > 0 && len(f.entries) >= f.failAt) {
+		return errors.New("sink unavailable")
+	}
+	return f.slowRecorder.LogEntry(entry)
+}
+
+func (f *flakyRecorder) Sync() error {
+	f.syncs++
+	return nil
+}
+
+func TestSpillHandler_SpillsAndReplaysInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spill")
+	sink := &flakyRecorder{failing: true}
+	s := NewSpillHandler(sink, path)
+	clock := NewManualClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	s.SetClock(clock)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, msg := range []string{"first", "second"} {
+		if err := s.LogEntry(&Entry{Time: at, Level: ErrorLevel, Message: msg}); err != nil {
+			t.Fatalf("LogEntry() nên lưu entry vào file spill thay vì trả về lỗi, got %v", err)
+		}
+	}
+	if !s.Pending() {
+		t.Fatal("Pending() nên là true khi có entry trong file spill")
+	}
+
+	sink.failing = false
+	clock.Advance(spillRetryDelay)
+	s.Log(InfoLevel, "third")
+
+	if len(sink.entries) != 3 {
+		t.Fatalf("Entry trong file spill nên được gửi lại trước entry mới, got %d entry", len(sink.entries))
+	}
+	for i, want := range []string{"first", "second", "third"} {
+		if sink.entries[i].Message != want {
+			t.Errorf("Entry %d sai thứ tự, got %q, want %q", i, sink.entries[i].Message, want)
+		}
+	}
+	if !sink.entries[0].Time.Equal(at) || sink.entries[0].Level != ErrorLevel {
+		t.Errorf("Entry gửi lại nên giữ timestamp và level gốc, got %v %v", sink.entries[0].Time, sink.entries[0].Level)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("File spill nên được xóa sau khi gửi lại hết, got %v", err)
+	}
+}
+
+func TestSpillHandler_ReplaysPreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spill")
+	NewSpillHandler(&flakyRecorder{failing: true}, path).Log(WarningLevel, "left over")
+
+	sink := &flakyRecorder{}
+	s := NewSpillHandler(sink, path)
+	if !s.Pending() {
+		t.Fatal("NewSpillHandler() nên phát hiện file spill từ lần chạy trước")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(sink.entries) != 1 || sink.entries[0].Message != "left over" {
+		t.Errorf("Close() nên gửi lại entry đang chờ, got %v", sink.entries)
+	}
+	if !sink.closed {
+		t.Error("Close() nên đóng handler được bọc")
+	}
+}
+
+func TestSpillHandler_BacksOffWhileSinkIsDown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spill")
+	sink := &flakyRecorder{failing: true}
+	s := NewSpillHandler(sink, path)
+	clock := NewManualClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	s.SetClock(clock)
+
+	for i := 0; i < 100; i++ {
+		s.Log(InfoLevel, "entry %d", i)
+	}
+	if sink.attempts != 1 {
+		t.Errorf("Không nên gửi lại file spill trong khoảng chờ, got %d lần ghi", sink.attempts)
+	}
+
+	clock.Advance(spillRetryDelay)
+	s.Log(InfoLevel, "retry")
+	clock.Advance(spillRetryDelay)
+	s.Log(InfoLevel, "still waiting")
+	if sink.attempts != 2 {
+		t.Errorf("Khoảng chờ nên tăng gấp đôi sau mỗi lần gửi lại thất bại, got %d lần ghi", sink.attempts)
+	}
+
+	sink.failing = false
+	clock.Advance(spillMaxRetryDelay)
+	s.Log(InfoLevel, "recovered")
+	if len(sink.entries) != 103 || s.Pending() {
+		t.Errorf("Nên gửi lại hết file spill khi handler hoạt động lại, got %d entry, pending=%v", len(sink.entries), s.Pending())
+	}
+}
+
+func TestSpillHandler_ResumesFromOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spill")
+	sink := &flakyRecorder{failing: true}
+	s := NewSpillHandler(sink, path)
+	clock := NewManualClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	s.SetClock(clock)
+	for _, msg := range []string{"a", "b", "c"} {
+		s.Log(InfoLevel, msg)
+	}
+
+	sink.failing, sink.failAt = false, 2
+	clock.Advance(spillRetryDelay)
+	s.Log(InfoLevel, "d")
+	if len(sink.entries) != 2 || !s.Pending() {
+		t.Fatalf("Nên dừng gửi lại ở lỗi đầu tiên, got %d entry, pending=%v", len(sink.entries), s.Pending())
+	}
+
+	sink.failAt = 0
+	clock.Advance(spillMaxRetryDelay)
+	s.Log(InfoLevel, "e")
+	var got []string
+	for _, entry := range sink.entries {
+		got = append(got, entry.Message)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Nên gửi tiếp từ vị trí đã gửi mà không gửi lặp, got %v, want %v", got, want)
+	}
+}
+
+func TestSpillHandler_KeepsFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spill")
+	sink := &flakyRecorder{failing: true}
+	s := NewSpillHandler(sink, path)
+	fields := []Field{
+		{Key: "user", Type: StringType, Str: "alice"},
+		{Key: "latency", Type: DurationType, Integer: int64(250 * time.Millisecond)},
+		{Key: "tags", Value: []string{"a", "b"}},
+	}
+	s.LogEntry(&Entry{Time: time.Now(), Level: ErrorLevel, Message: "failed", Fields: fields})
+
+	sink.failing = false
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(sink.entries) != 1 || len(sink.entries[0].Fields) != 3 {
+		t.Fatalf("Entry gửi lại nên giữ field có cấu trúc, got %v", sink.entries)
+	}
+	got := sink.entries[0].Fields
+	if got[0] != fields[0] || got[1].Interface() != 250*time.Millisecond {
+		t.Errorf("Field có kiểu nên được giữ nguyên, got %+v", got[:2])
+	}
+	if fmt.Sprint(got[2].Interface()) != "[a b]" {
+		t.Errorf("Field AnyType nên được gửi lại với giá trị JSON, got %v", got[2].Interface())
+	}
+}
@@ -1,14 +1,96 @@
 package handler
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DispatchMode xác định cách StackHandler chuyển entry đến các handler con.
+type DispatchMode int32
+
+const (
+	// Broadcast ghi entry đến tất cả các handler con (mặc định).
+	Broadcast DispatchMode = iota
+
+	// FirstSuccess ghi entry đến từng handler con theo thứ tự và dừng ở handler đầu tiên ghi
+	// thành công.
+	FirstSuccess
+
+	// Failover ghi entry đến handler con đầu tiên nhận cấp độ của entry (chính) và chỉ ghi đến
+	// tất cả các handler con còn lại (dự phòng) khi handler chính ghi thất bại.
+	Failover
+)
+
+// String trả về tên của chế độ chuyển entry dùng trong cấu hình.
+//
+// Trả về:
+//   - string: "broadcast", "first_success", "failover" hoặc "unknown"
+func (m DispatchMode) String() string {
+	switch m {
+	case Broadcast:
+		return "broadcast"
+	case FirstSuccess:
+		return "first_success"
+	case Failover:
+		return "failover"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDispatchMode chuyển tên chế độ chuyển entry (không phân biệt hoa thường) thành DispatchMode.
+//
+// Tham số:
+//   - s: string - tên chế độ (broadcast, first_success, failover)
+//
+// Trả về:
+//   - DispatchMode: chế độ tương ứng
+//   - error: lỗi nếu tên không hợp lệ
+func ParseDispatchMode(s string) (DispatchMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "broadcast":
+		return Broadcast, nil
+	case "first_success":
+		return FirstSuccess, nil
+	case "failover":
+		return Failover, nil
+	default:
+		return Broadcast, fmt.Errorf("invalid dispatch mode: %q", s)
+	}
+}
+
 // StackHandler triển khai một handler log tổng hợp chuyển tiếp các bản ghi log đến nhiều handlers.
 //
 // Tính năng:
 //   - Quản lý nhiều handlers như một đơn vị
 //   - Chuyển tiếp tuần tự đến tất cả các handlers con
 //   - Xử lý lỗi tập trung
-//   - Thêm handler động
+//   - Thêm và gỡ handler động (có thể đặt tên), an toàn khi đang ghi log đồng thời
+//   - Cấp độ tối thiểu riêng cho từng handler con có tên
+//   - Chế độ chuyển entry: broadcast, first_success hoặc failover (xem DispatchMode)
+//   - Ghi song song đến các handler con qua pool có giới hạn (xem SetConcurrency)
 type StackHandler struct {
-	handlers []Handler // Slice chứa các handlers con
+	handlers atomic.Pointer[[]stackChild]  // Slice các handlers con, chỉ được thay thế (không sửa tại chỗ)
+	mu       sync.Mutex                    // Tuần tự hóa các lần thêm và gỡ handler
+	mode     atomic.Int32                  // DispatchMode hiện tại
+	pool     atomic.Pointer[chan struct{}] // Semaphore giới hạn số lần ghi song song, nil = tuần tự
+}
+
+// stackChild là một handler con của StackHandler cùng tên của nó ("" với handler không tên) và
+// cấp độ tối thiểu riêng (mặc định DebugLevel, nhận mọi cấp độ).
+type stackChild struct {
+	name    string
+	handler Handler
+	level   Level
+}
+
+// enabled kiểm tra handler con có nhận entry ở cấp độ đã cho hay không, theo cấp độ tối thiểu
+// của nó và LevelEnabler của handler.
+func (c stackChild) enabled(level Level) bool {
+	return level >= c.level && Enabled(c.handler, level)
 }
 
 // NewStackHandler tạo một stack handler mới với các handlers con được chỉ định.
@@ -26,16 +108,26 @@ type StackHandler struct {
 //	fileHandler, _ := handler.NewFileHandler("app.log", 10*1024*1024)
 //	stackHandler := handler.NewStackHandler(consoleHandler, fileHandler)
 func NewStackHandler(handlers ...Handler) *StackHandler {
-	return &StackHandler{
-		handlers: handlers,
+	children := make([]stackChild, len(handlers))
+	for i, handler := range handlers {
+		children[i] = stackChild{handler: handler}
 	}
+	s := &StackHandler{}
+	s.handlers.Store(&children)
+	return s
 }
 
-// Log chuyển tiếp một log entry đến tất cả các handlers trong stack.
+// children trả về các handlers con hiện tại bằng một lần đọc atomic.
+func (a *StackHandler) children() []stackChild {
+	return *a.handlers.Load()
+}
+
+// Log chuyển tiếp một log entry đến các handlers trong stack theo DispatchMode.
 //
-// Phương thức này gọi phương thức Log của mỗi handler con theo thứ tự.
-// Nếu bất kỳ handler nào trả về lỗi, lỗi đầu tiên sẽ được trả về,
-// nhưng tất cả các handlers sẽ vẫn được gọi.
+// Phương thức này gọi phương thức Log của các handler con theo thứ tự, bỏ qua handler có cấp
+// độ tối thiểu cao hơn hoặc từ chối cấp độ của entry (xem LevelEnabler). Với Broadcast, tất cả
+// các handlers vẫn được gọi khi một handler trả về lỗi và lỗi đầu tiên được trả về (hoặc lỗi
+// của tất cả handler được gộp khi ghi song song, xem SetConcurrency).
 //
 // Tham số:
 //   - level: Level - cấp độ nghiêm trọng của log entry
@@ -43,15 +135,222 @@ func NewStackHandler(handlers ...Handler) *StackHandler {
 //   - args: ...interface{} - các tham số định dạng tùy chọn
 //
 // Trả về:
-//   - error: lỗi đầu tiên gặp phải, hoặc nil nếu tất cả handlers thành công
+//   - error: lỗi đầu tiên gặp phải (hoặc lỗi đã gộp khi ghi song song) với Broadcast; với FirstSuccess và Failover, lỗi của các
+//     handler đã gọi khi không handler nào ghi thành công; nil nếu thành công
 func (a *StackHandler) Log(level Level, message string, args ...interface{}) error {
-	var firstErr error
-	for _, handler := range a.handlers {
-		if err := handler.Log(level, message, args...); err != nil && firstErr == nil {
-			firstErr = err
+	return a.dispatch(level, func(h Handler) error {
+		return h.Log(level, message, args...)
+	})
+}
+
+// LogEntry chuyển tiếp một log entry hoàn chỉnh đến các handlers trong stack theo DispatchMode.
+//
+// Các handler con triển khai EntryHandler nhận entry với timestamp gốc; các handler
+// còn lại nhận entry qua Log.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần chuyển tiếp
+//
+// Trả về:
+//   - error: lỗi theo cùng quy tắc với Log, hoặc nil nếu thành công
+func (a *StackHandler) LogEntry(entry *Entry) error {
+	return a.dispatch(entry.Level, func(h Handler) error {
+		return Dispatch(h, entry)
+	})
+}
+
+// dispatch gọi write với các handler con nhận cấp độ đã cho theo DispatchMode hiện tại.
+func (a *StackHandler) dispatch(level Level, write func(Handler) error) error {
+	switch a.Mode() {
+	case FirstSuccess:
+		var errs []error
+		for _, child := range a.children() {
+			if !child.enabled(level) {
+				continue
+			}
+			err := write(child.handler)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	case Failover:
+		children := a.children()
+		for i, child := range children {
+			if !child.enabled(level) {
+				continue
+			}
+			err := write(child.handler)
+			if err == nil {
+				return nil
+			}
+			calls, errs := a.broadcast(children[i+1:], level, write)
+			if calls > len(errs) {
+				return nil
+			}
+			return errors.Join(append([]error{err}, errs...)...)
+		}
+		return nil
+	default:
+		_, errs := a.broadcast(a.children(), level, write)
+		switch {
+		case len(errs) == 0:
+			return nil
+		case a.pool.Load() == nil:
+			return errs[0]
+		default:
+			return errors.Join(errs...)
 		}
 	}
-	return firstErr
+}
+
+// broadcast gọi write với tất cả các handler con nhận cấp độ đã cho, song song qua pool khi
+// SetConcurrency bật ghi đồng thời.
+//
+// Trả về:
+//   - int: số handler con đã gọi
+//   - []error: lỗi của các handler con ghi thất bại, theo thứ tự trong stack
+func (a *StackHandler) broadcast(children []stackChild, level Level, write func(Handler) error) (int, []error) {
+	pool := a.pool.Load()
+	if pool == nil {
+		calls := 0
+		var errs []error
+		for _, child := range children {
+			if !child.enabled(level) {
+				continue
+			}
+			calls++
+			if err := write(child.handler); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return calls, errs
+	}
+
+	results := make([]error, len(children))
+	calls := 0
+	var wg sync.WaitGroup
+	for i, child := range children {
+		if !child.enabled(level) {
+			continue
+		}
+		calls++
+		select {
+		case *pool <- struct{}{}:
+			wg.Add(1)
+			go func(i int, h Handler) {
+				defer func() {
+					<-*pool
+					wg.Done()
+				}()
+				results[i] = write(h)
+			}(i, child.handler)
+		default:
+			// Pool đã dùng hết, ghi ngay trên goroutine gọi thay vì chờ
+			results[i] = write(child.handler)
+		}
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return calls, errs
+}
+
+// SetConcurrency bật ghi song song đến các handler con với tối đa n lần ghi đồng thời (dùng
+// chung cho mọi lời gọi Log), để một handler mạng chậm không làm chậm việc ghi đến các handler
+// nhanh. Log vẫn chỉ trả về khi mọi handler con đã ghi xong, và lỗi của các handler con được gộp
+// bằng errors.Join. Khi pool đã dùng hết, handler con được ghi trên goroutine gọi. Áp dụng cho
+// Broadcast và các handler dự phòng của Failover. Method này là thread-safe.
+//
+// Tham số:
+//   - n: int - số lần ghi đồng thời tối đa, <= 1 để ghi tuần tự (mặc định)
+//
+// Ví dụ:
+//
+//	stackHandler := handler.NewStackHandler(consoleHandler, fileHandler, lokiHandler)
+//	stackHandler.SetConcurrency(4)
+func (a *StackHandler) SetConcurrency(n int) {
+	if n <= 1 {
+		a.pool.Store(nil)
+		return
+	}
+	pool := make(chan struct{}, n)
+	a.pool.Store(&pool)
+}
+
+// Concurrency trả về số lần ghi đồng thời tối đa, 1 nếu stack ghi tuần tự.
+func (a *StackHandler) Concurrency() int {
+	if pool := a.pool.Load(); pool != nil {
+		return cap(*pool)
+	}
+	return 1
+}
+
+// SetMode đặt cách chuyển entry đến các handler con. Method này là thread-safe.
+//
+// Tham số:
+//   - mode: DispatchMode - Broadcast (mặc định), FirstSuccess hoặc Failover
+//
+// Ví dụ:
+//
+//	// Ghi vào Loki, chỉ ghi ra file cục bộ khi Loki không nhận
+//	stackHandler := handler.NewStackHandler(lokiHandler, fileHandler)
+//	stackHandler.SetMode(handler.Failover)
+func (a *StackHandler) SetMode(mode DispatchMode) {
+	a.mode.Store(int32(mode))
+}
+
+// Mode trả về cách chuyển entry hiện tại.
+func (a *StackHandler) Mode() DispatchMode {
+	return DispatchMode(a.mode.Load())
+}
+
+// Enabled kiểm tra có ít nhất một handler con chấp nhận cấp độ đã cho hay không.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//
+// Trả về:
+//   - bool: true nếu có handler con sẽ ghi entry ở cấp độ này
+func (a *StackHandler) Enabled(level Level) bool {
+	for _, child := range a.children() {
+		if child.enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Health kiểm tra tình trạng của tất cả các handlers con (xem Health).
+//
+// Trả về:
+//   - error: lỗi của các handlers con không hoạt động bình thường, hoặc nil
+func (a *StackHandler) Health() error {
+	children := a.children()
+	errs := make([]error, 0, len(children))
+	for _, child := range children {
+		errs = append(errs, Health(child.handler))
+	}
+	return errors.Join(errs...)
+}
+
+// Flush ghi hết bộ đệm của tất cả các handlers con (xem Flush).
+//
+// Trả về:
+//   - error: lỗi của các handlers con ghi thất bại, hoặc nil
+func (a *StackHandler) Flush() error {
+	children := a.children()
+	errs := make([]error, 0, len(children))
+	for _, child := range children {
+		errs = append(errs, Flush(child.handler))
+	}
+	return errors.Join(errs...)
 }
 
 // Close đóng đúng cách tất cả các handlers trong stack.
@@ -64,15 +363,19 @@ func (a *StackHandler) Log(level Level, message string, args ...interface{}) err
 //   - error: lỗi đầu tiên gặp phải, hoặc nil nếu tất cả handlers đóng thành công
 func (a *StackHandler) Close() error {
 	var firstErr error
-	for _, handler := range a.handlers {
-		if err := handler.Close(); err != nil && firstErr == nil {
+	for _, child := range a.children() {
+		if err := child.handler.Close(); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
 	return firstErr
 }
 
-// AddHandler thêm một handler mới vào stack.
+// AddHandler thêm một handler mới không tên vào stack.
+//
+// Slice handlers con được thay thế thay vì sửa tại chỗ, nên các lời gọi Log đang chạy tiếp tục
+// với danh sách cũ và mọi lời gọi bắt đầu sau khi AddHandler trả về đều ghi đến handler mới.
+// Handler không tên không thể gỡ qua RemoveChild. Method này là thread-safe.
 //
 // Tham số:
 //   - handler: Handler - handler để thêm vào stack
@@ -83,5 +386,181 @@ func (a *StackHandler) Close() error {
 //	networkHandler := NewNetworkHandler("logs.example.com:514")
 //	stackHandler.AddHandler(networkHandler)
 func (a *StackHandler) AddHandler(handler Handler) {
-	a.handlers = append(a.handlers, handler)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.store(append(a.cloneChildren(), stackChild{handler: handler}))
+}
+
+// AddChild thêm một handler con có tên vào cuối stack, để có thể gỡ hoặc liệt kê nó khi đang chạy.
+// Giống AddHandler, mọi lời gọi Log bắt đầu sau khi AddChild trả về đều ghi đến handler mới.
+// Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên duy nhất trong stack của handler con
+//   - handler: Handler - handler để thêm vào stack
+//
+// Trả về:
+//   - error: lỗi nếu name rỗng, handler là nil hoặc stack đã có handler con cùng tên
+//
+// Ví dụ:
+//
+//	if err := stackHandler.AddChild("alerting", alertHandler); err != nil {
+//		return err
+//	}
+func (a *StackHandler) AddChild(name string, handler Handler) error {
+	if name == "" {
+		return errors.New("stack child name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("stack child %q: handler is nil", name)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.indexOf(name) >= 0 {
+		return fmt.Errorf("stack child %q already exists", name)
+	}
+	a.store(append(a.cloneChildren(), stackChild{name: name, handler: handler}))
+	return nil
+}
+
+// RemoveChild gỡ handler con có tên khỏi stack. Handler đã gỡ không được đóng, người gọi chịu
+// trách nhiệm đóng nó sau khi các lời gọi Log đang chạy kết thúc. Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên của handler con đã dùng trong AddChild
+//
+// Trả về:
+//   - Handler: handler đã gỡ, nil nếu stack không có handler con với tên này
+//
+// Ví dụ:
+//
+//	if h := stackHandler.RemoveChild("alerting"); h != nil {
+//		h.Close()
+//	}
+func (a *StackHandler) RemoveChild(name string) Handler {
+	if name == "" {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := a.indexOf(name)
+	if i < 0 {
+		return nil
+	}
+	children := a.cloneChildren()
+	removed := children[i].handler
+	a.store(append(children[:i], children[i+1:]...))
+	return removed
+}
+
+// SetChildLevel đặt cấp độ tối thiểu của handler con có tên, để một stack phục vụ nhiều đích với
+// mức chi tiết khác nhau (VD: console nhận debug, file nhận info, cảnh báo chỉ nhận error).
+// Entry dưới cấp độ này không được chuyển đến handler con, các handler con khác không bị ảnh
+// hưởng. Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên của handler con đã dùng trong AddChild
+//   - level: Level - cấp độ tối thiểu, DebugLevel để nhận mọi cấp độ
+//
+// Trả về:
+//   - error: lỗi nếu stack không có handler con với tên này
+//
+// Ví dụ:
+//
+//	stackHandler.AddChild("alerting", alertHandler)
+//	stackHandler.SetChildLevel("alerting", handler.ErrorLevel)
+func (a *StackHandler) SetChildLevel(name string, level Level) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := a.indexOf(name)
+	if name == "" || i < 0 {
+		return fmt.Errorf("stack child %q not found", name)
+	}
+	children := a.cloneChildren()
+	children[i].level = level
+	a.store(children)
+	return nil
+}
+
+// ChildLevel trả về cấp độ tối thiểu của handler con có tên.
+//
+// Tham số:
+//   - name: string - tên của handler con
+//
+// Trả về:
+//   - Level: cấp độ tối thiểu của handler con
+//   - bool: false nếu stack không có handler con với tên này
+func (a *StackHandler) ChildLevel(name string) (Level, bool) {
+	if name == "" {
+		return DebugLevel, false
+	}
+	for _, child := range a.children() {
+		if child.name == name {
+			return child.level, true
+		}
+	}
+	return DebugLevel, false
+}
+
+// Child trả về handler con có tên.
+//
+// Tham số:
+//   - name: string - tên của handler con
+//
+// Trả về:
+//   - Handler: handler con, nil nếu stack không có handler con với tên này
+func (a *StackHandler) Child(name string) Handler {
+	if name == "" {
+		return nil
+	}
+	for _, child := range a.children() {
+		if child.name == name {
+			return child.handler
+		}
+	}
+	return nil
+}
+
+// Children trả về tên của các handler con có tên theo thứ tự ghi; handler thêm qua AddHandler
+// hoặc NewStackHandler không có tên nên không được liệt kê.
+//
+// Trả về:
+//   - []string: bản sao danh sách tên, có thể sửa tự do
+func (a *StackHandler) Children() []string {
+	children := a.children()
+	names := make([]string, 0, len(children))
+	for _, child := range children {
+		if child.name != "" {
+			names = append(names, child.name)
+		}
+	}
+	return names
+}
+
+// indexOf trả về vị trí của handler con có tên, -1 nếu không có. Phải được gọi khi đang giữ a.mu.
+func (a *StackHandler) indexOf(name string) int {
+	for i, child := range a.children() {
+		if child.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// cloneChildren trả về bản sao của slice handlers con để sửa rồi store. Phải được gọi khi đang
+// giữ a.mu.
+func (a *StackHandler) cloneChildren() []stackChild {
+	children := a.children()
+	return append(make([]stackChild, 0, len(children)+1), children...)
+}
+
+// store thay thế slice handlers con. Phải được gọi khi đang giữ a.mu.
+func (a *StackHandler) store(children []stackChild) {
+	a.handlers.Store(&children)
 }
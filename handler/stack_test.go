@@ -2,6 +2,8 @@ package handler
 
 import (
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -44,9 +46,9 @@ func TestStackHandler_New(t *testing.T) {
 	if stack1 == nil {
 		t.Fatal("NewStackHandler() không handler trả về nil")
 	}
-	if len(stack1.handlers) != 0 {
+	if len(stack1.children()) != 0 {
 		t.Errorf("NewStackHandler() không handler nên có slice handlers trống, got length = %d",
-			len(stack1.handlers))
+			len(stack1.children()))
 	}
 
 	// Test với một handler
@@ -54,9 +56,9 @@ func TestStackHandler_New(t *testing.T) {
 	if stack2 == nil {
 		t.Fatal("NewStackHandler() với một handler trả về nil")
 	}
-	if len(stack2.handlers) != 1 {
+	if len(stack2.children()) != 1 {
 		t.Errorf("NewStackHandler() với một handler nên có len(handlers) = 1, got = %d",
-			len(stack2.handlers))
+			len(stack2.children()))
 	}
 
 	// Test với nhiều handlers
@@ -64,9 +66,9 @@ func TestStackHandler_New(t *testing.T) {
 	if stack3 == nil {
 		t.Fatal("NewStackHandler() với nhiều handlers trả về nil")
 	}
-	if len(stack3.handlers) != 2 {
+	if len(stack3.children()) != 2 {
 		t.Errorf("NewStackHandler() với hai handlers nên có len(handlers) = 2, got = %d",
-			len(stack3.handlers))
+			len(stack3.children()))
 	}
 }
 
@@ -165,17 +167,17 @@ func TestStackHandler_AddHandler(t *testing.T) {
 	// Thêm một handler
 	handler1 := &MockTestHandler{}
 	stack.AddHandler(handler1)
-	if len(stack.handlers) != 1 {
+	if len(stack.children()) != 1 {
 		t.Errorf("StackHandler.AddHandler() sau thêm 1 handler nên có len(handlers) = 1, got = %d",
-			len(stack.handlers))
+			len(stack.children()))
 	}
 
 	// Thêm handler thứ hai
 	handler2 := &MockTestHandler{}
 	stack.AddHandler(handler2)
-	if len(stack.handlers) != 2 {
+	if len(stack.children()) != 2 {
 		t.Errorf("StackHandler.AddHandler() sau thêm 2 handlers nên có len(handlers) = 2, got = %d",
-			len(stack.handlers))
+			len(stack.children()))
 	}
 
 	// Kiểm tra cả hai handlers có hoạt động không
@@ -187,3 +189,318 @@ func TestStackHandler_AddHandler(t *testing.T) {
 		t.Error("StackHandler.Log() không gọi tất cả các handlers sau khi thêm")
 	}
 }
+
+// countingTestHandler đếm số lần Log được gọi, an toàn khi dùng đồng thời
+type countingTestHandler struct {
+	calls atomic.Int64
+}
+
+func (c *countingTestHandler) Log(level Level, message string, args ...interface{}) error {
+	c.calls.Add(1)
+	return nil
+}
+
+func (c *countingTestHandler) Close() error {
+	return nil
+}
+
+func TestStackHandler_ConcurrentAddHandler(t *testing.T) {
+	first := &countingTestHandler{}
+	stack := NewStackHandler(first)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				stack.Log(InfoLevel, "message")
+				stack.LogEntry(&Entry{Level: InfoLevel, Message: "entry"})
+				stack.Enabled(InfoLevel)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		stack.AddHandler(&countingTestHandler{})
+	}
+	wg.Wait()
+
+	if got := first.calls.Load(); got != 1600 {
+		t.Errorf("Handler có sẵn nên nhận đủ 1600 entry trong khi stack được thêm handler, got %d", got)
+	}
+	last := &countingTestHandler{}
+	stack.AddHandler(last)
+	stack.Log(InfoLevel, "after add")
+	if last.calls.Load() != 1 {
+		t.Errorf("Lời gọi Log sau AddHandler nên đến handler mới, got %d", last.calls.Load())
+	}
+}
+
+func TestStackHandler_AddChild(t *testing.T) {
+	stack := NewStackHandler(&MockTestHandler{})
+	console := &MockTestHandler{}
+	alerting := &MockTestHandler{}
+
+	if err := stack.AddChild("console", console); err != nil {
+		t.Fatalf("AddChild() lỗi: %v", err)
+	}
+	if err := stack.AddChild("alerting", alerting); err != nil {
+		t.Fatalf("AddChild() lỗi: %v", err)
+	}
+	if err := stack.AddChild("console", &MockTestHandler{}); err == nil {
+		t.Error("AddChild() với tên đã tồn tại nên trả về lỗi")
+	}
+	if err := stack.AddChild("", &MockTestHandler{}); err == nil {
+		t.Error("AddChild() với tên rỗng nên trả về lỗi")
+	}
+	if err := stack.AddChild("nil", nil); err == nil {
+		t.Error("AddChild() với handler nil nên trả về lỗi")
+	}
+
+	names := stack.Children()
+	if len(names) != 2 || names[0] != "console" || names[1] != "alerting" {
+		t.Errorf("Children() = %v, want [console alerting]", names)
+	}
+	if stack.Child("alerting") != alerting {
+		t.Error("Child() nên trả về handler con theo tên")
+	}
+	if len(stack.children()) != 3 {
+		t.Errorf("Stack nên có 3 handler con kể cả handler không tên, got %d", len(stack.children()))
+	}
+
+	stack.Log(InfoLevel, "hello")
+	if !console.LogCalled || !alerting.LogCalled {
+		t.Error("Log() nên ghi đến các handler con có tên")
+	}
+}
+
+func TestStackHandler_RemoveChild(t *testing.T) {
+	console := &MockTestHandler{}
+	alerting := &MockTestHandler{}
+	stack := NewStackHandler()
+	stack.AddChild("console", console)
+	stack.AddChild("alerting", alerting)
+
+	if got := stack.RemoveChild("alerting"); got != alerting {
+		t.Errorf("RemoveChild() nên trả về handler đã gỡ, got %v", got)
+	}
+	if alerting.CloseCalled {
+		t.Error("RemoveChild() không nên đóng handler đã gỡ")
+	}
+	if got := stack.RemoveChild("alerting"); got != nil {
+		t.Errorf("RemoveChild() với tên không tồn tại nên trả về nil, got %v", got)
+	}
+	if names := stack.Children(); len(names) != 1 || names[0] != "console" {
+		t.Errorf("Children() sau khi gỡ = %v, want [console]", names)
+	}
+
+	stack.Log(InfoLevel, "after remove")
+	if alerting.LogCalled {
+		t.Error("Handler đã gỡ không nên nhận entry")
+	}
+	if !console.LogCalled {
+		t.Error("Handler còn lại nên nhận entry")
+	}
+
+	// Tên đã gỡ có thể được dùng lại
+	if err := stack.AddChild("alerting", &MockTestHandler{}); err != nil {
+		t.Errorf("AddChild() với tên đã gỡ nên thành công, got %v", err)
+	}
+}
+
+func TestStackHandler_SetChildLevel(t *testing.T) {
+	console := &countingTestHandler{}
+	file := &countingTestHandler{}
+	alerting := &countingTestHandler{}
+	stack := NewStackHandler()
+	stack.AddChild("console", console)
+	stack.AddChild("file", file)
+	stack.AddChild("alerting", alerting)
+
+	if err := stack.SetChildLevel("file", InfoLevel); err != nil {
+		t.Fatalf("SetChildLevel() lỗi: %v", err)
+	}
+	if err := stack.SetChildLevel("alerting", ErrorLevel); err != nil {
+		t.Fatalf("SetChildLevel() lỗi: %v", err)
+	}
+	if err := stack.SetChildLevel("missing", ErrorLevel); err == nil {
+		t.Error("SetChildLevel() với tên không tồn tại nên trả về lỗi")
+	}
+	if level, ok := stack.ChildLevel("alerting"); !ok || level != ErrorLevel {
+		t.Errorf("ChildLevel() = %v, %v, want ERROR, true", level, ok)
+	}
+
+	stack.Log(DebugLevel, "debug")
+	stack.LogEntry(&Entry{Level: InfoLevel, Message: "info"})
+	stack.Log(ErrorLevel, "error")
+
+	if got := console.calls.Load(); got != 3 {
+		t.Errorf("Console không đặt cấp độ nên nhận 3 entry, got %d", got)
+	}
+	if got := file.calls.Load(); got != 2 {
+		t.Errorf("File cấp độ info nên nhận 2 entry, got %d", got)
+	}
+	if got := alerting.calls.Load(); got != 1 {
+		t.Errorf("Alerting cấp độ error nên nhận 1 entry, got %d", got)
+	}
+
+	stack.RemoveChild("console")
+	if stack.Enabled(DebugLevel) {
+		t.Error("Enabled(Debug) nên false khi không còn handler con nhận debug")
+	}
+	if !stack.Enabled(InfoLevel) {
+		t.Error("Enabled(Info) nên true khi file nhận info")
+	}
+}
+
+func TestParseDispatchMode(t *testing.T) {
+	for _, mode := range []DispatchMode{Broadcast, FirstSuccess, Failover} {
+		got, err := ParseDispatchMode(mode.String())
+		if err != nil || got != mode {
+			t.Errorf("ParseDispatchMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := ParseDispatchMode("round_robin"); err == nil {
+		t.Error("ParseDispatchMode() với tên không hợp lệ nên trả về lỗi")
+	}
+}
+
+func TestStackHandler_DispatchModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       DispatchMode
+		failing    []bool // handler con nào trả về lỗi
+		wantCalled []bool
+		wantErr    bool
+	}{
+		{"broadcast", Broadcast, []bool{true, false, false}, []bool{true, true, true}, true},
+		{"first success dừng ở handler đầu", FirstSuccess, []bool{false, false, false}, []bool{true, false, false}, false},
+		{"first success thử lần lượt", FirstSuccess, []bool{true, true, false}, []bool{true, true, true}, false},
+		{"first success đều lỗi", FirstSuccess, []bool{true, true, true}, []bool{true, true, true}, true},
+		{"failover chính thành công", Failover, []bool{false, false, false}, []bool{true, false, false}, false},
+		{"failover ghi tất cả dự phòng", Failover, []bool{true, false, false}, []bool{true, true, true}, false},
+		{"failover một dự phòng thành công", Failover, []bool{true, true, false}, []bool{true, true, true}, false},
+		{"failover đều lỗi", Failover, []bool{true, true, true}, []bool{true, true, true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children := make([]*MockTestHandler, len(tt.failing))
+			stack := NewStackHandler()
+			for i, failing := range tt.failing {
+				children[i] = &MockTestHandler{ShouldError: failing}
+				stack.AddHandler(children[i])
+			}
+			stack.SetMode(tt.mode)
+
+			err := stack.LogEntry(&Entry{Level: InfoLevel, Message: "entry"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LogEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, child := range children {
+				if child.LogCalled != tt.wantCalled[i] {
+					t.Errorf("Handler con %d: LogCalled = %v, want %v", i, child.LogCalled, tt.wantCalled[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStackHandler_FailoverSkipsDisabledPrimary(t *testing.T) {
+	primary := &countingTestHandler{}
+	backup := &countingTestHandler{}
+	stack := NewStackHandler()
+	stack.AddChild("primary", primary)
+	stack.AddChild("backup", backup)
+	stack.SetChildLevel("primary", ErrorLevel)
+	stack.SetMode(Failover)
+
+	stack.Log(InfoLevel, "info")
+	stack.Log(ErrorLevel, "error")
+
+	if primary.calls.Load() != 1 || backup.calls.Load() != 1 {
+		t.Errorf("Handler con đầu tiên nhận cấp độ nên là handler chính, got primary=%d backup=%d",
+			primary.calls.Load(), backup.calls.Load())
+	}
+}
+
+// blockingTestHandler chờ release trước khi trả về, để kiểm tra ghi song song
+type blockingTestHandler struct {
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (b *blockingTestHandler) Log(level Level, message string, args ...interface{}) error {
+	b.started <- struct{}{}
+	<-b.release
+	return b.err
+}
+
+func (b *blockingTestHandler) Close() error {
+	return nil
+}
+
+func TestStackHandler_SetConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	slow1 := &blockingTestHandler{started: started, release: release, err: errors.New("slow1")}
+	slow2 := &blockingTestHandler{started: started, release: release, err: errors.New("slow2")}
+	fast := &countingTestHandler{}
+
+	stack := NewStackHandler(slow1, slow2, fast)
+	stack.SetConcurrency(4)
+	if stack.Concurrency() != 4 {
+		t.Errorf("Concurrency() = %d, want 4", stack.Concurrency())
+	}
+
+	done := make(chan error)
+	go func() { done <- stack.Log(InfoLevel, "parallel") }()
+
+	// Cả hai handler chậm phải bắt đầu trước khi handler nào trả về
+	<-started
+	<-started
+	close(release)
+
+	err := <-done
+	if !errors.Is(err, slow1.err) || !errors.Is(err, slow2.err) {
+		t.Errorf("Log() nên gộp lỗi của các handler con, got %v", err)
+	}
+	if fast.calls.Load() != 1 {
+		t.Errorf("Handler nhanh nên nhận entry, got %d", fast.calls.Load())
+	}
+
+	stack.SetConcurrency(0)
+	if stack.Concurrency() != 1 {
+		t.Errorf("SetConcurrency(0) nên trở về ghi tuần tự, got %d", stack.Concurrency())
+	}
+}
+
+func TestStackHandler_ConcurrencyPoolSaturated(t *testing.T) {
+	children := make([]Handler, 8)
+	counters := make([]*countingTestHandler, len(children))
+	for i := range children {
+		counters[i] = &countingTestHandler{}
+		children[i] = counters[i]
+	}
+	stack := NewStackHandler(children...)
+	stack.SetConcurrency(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				stack.LogEntry(&Entry{Level: InfoLevel, Message: "entry"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range counters {
+		if got := c.calls.Load(); got != 400 {
+			t.Errorf("Handler con %d nên nhận đủ 400 entry khi pool đã dùng hết, got %d", i, got)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout là định dạng timestamp được ghi bởi FileHandler và ConsoleHandler.
+const TimeLayout = "2006/01/02 15:04:05"
+
+// backupSuffixLayout là định dạng hậu tố timestamp của file sao lưu khi xoay vòng.
+const backupSuffixLayout = "20060102150405"
+
+// tailChunkSize là kích thước mỗi lần Tail đọc ngược từ cuối file.
+const tailChunkSize = 64 << 10
+
+// ParseLine phân tích một dòng có dạng "2006/01/02 15:04:05 [LEVEL] message" do FileHandler
+// và ConsoleHandler (không màu) ghi.
+//
+// Tham số:
+//   - line: string - dòng log, không chứa ký tự xuống dòng
+//   - loc: *time.Location - múi giờ dùng để diễn giải timestamp (nil để dùng time.Local)
+//
+// Trả về:
+//   - *Entry: entry đã phân tích với Time, Level và Message
+//   - error: lỗi nếu dòng không bắt đầu bằng timestamp và cấp độ hợp lệ (VD: dòng tiếp theo
+//     của một thông điệp nhiều dòng)
+//
+// Ví dụ:
+//
+//	entry, err := handler.ParseLine("2024/03/01 12:00:00 [INFO] [HTTP] GET /health", time.UTC)
+func ParseLine(line string, loc *time.Location) (*Entry, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if len(line) < len(TimeLayout)+3 || line[len(TimeLayout):len(TimeLayout)+2] != " [" {
+		return nil, errors.New("missing timestamp and level")
+	}
+
+	t, err := time.ParseInLocation(TimeLayout, line[:len(TimeLayout)], loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	rest := line[len(TimeLayout)+2:]
+	name, message, ok := strings.Cut(rest, "]")
+	if !ok {
+		return nil, errors.New("missing level")
+	}
+	level, err := ParseLevel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		Time:    t,
+		Level:   level,
+		Message: strings.TrimPrefix(message, " "),
+	}, nil
+}
+
+// Tail trả về n entry cuối cùng đã được ghi, theo thứ tự từ cũ đến mới.
+//
+// File được đọc ngược từ cuối nên chi phí chỉ phụ thuộc vào n, không phụ thuộc kích thước file.
+// Khi file hiện tại có ít hơn n entry (VD: ngay sau khi xoay vòng), các entry còn thiếu được lấy
+// từ file sao lưu mới nhất. Thông điệp nhiều dòng được giữ nguyên và timestamp được diễn giải
+// theo time.Local. Method này là thread-safe và không đọc được dòng đang ghi dở.
+//
+// Tham số:
+//   - n: int - số entry cần lấy
+//
+// Trả về:
+//   - []*Entry: tối đa n entry, hoặc nil nếu n <= 0
+//   - error: lỗi nếu không thể đọc file log
+//
+// Ví dụ:
+//
+//	entries, err := fileHandler.Tail(50)
+//	for _, e := range entries {
+//	    fmt.Printf("%s [%s] %s\n", e.Time.Format(handler.TimeLayout), e.Level, e.Message)
+//	}
+func (a *FileHandler) Tail(n int) ([]*Entry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	paths := append([]string{a.path}, backupPaths(a.path)...)
+	var entries []*Entry
+	for _, path := range paths {
+		fileEntries, err := tailFile(path, n-len(entries), a.aead)
+		if err != nil {
+			return nil, fmt.Errorf("không thể đọc file log %s: %w", path, err)
+		}
+		entries = append(fileEntries, entries...)
+		if len(entries) >= n {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// backupPaths trả về các file sao lưu của path do rotate tạo, kể cả file đã nén, theo thứ tự
+// từ mới đến cũ. File nén của một bản sao lưu chưa nén xong bị bỏ qua.
+func backupPaths(path string) []string {
+	matches, _ := filepath.Glob(path + ".*")
+	uncompressed := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		uncompressed[match] = true
+	}
+
+	type backup struct {
+		path  string
+		stamp string
+		seq   int
+	}
+	backups := make([]backup, 0, len(matches))
+	for _, match := range matches {
+		name := match
+		if codec := CodecForPath(match); codec != nil {
+			name = strings.TrimSuffix(match, codec.Extension())
+			if uncompressed[name] {
+				continue
+			}
+		}
+		suffix := strings.TrimPrefix(name, path+".")
+		if _, seq, ok := parseBackupSuffix(suffix); ok {
+			backups = append(backups, backup{path: match, stamp: suffix[:len(backupSuffixLayout)], seq: seq})
+		}
+	}
+	// Hậu tố timestamp có độ dài cố định nên thứ tự chuỗi trùng với thứ tự thời gian; các bản
+	// sao lưu trong cùng một giây được xếp theo số thứ tự
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].stamp != backups[j].stamp {
+			return backups[i].stamp > backups[j].stamp
+		}
+		return backups[i].seq > backups[j].seq
+	})
+
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths
+}
+
+// backupPathFor trả về tên file sao lưu chưa tồn tại (kể cả dạng đã nén hoặc đã ký) cho lần
+// xoay vòng tại now: path cùng hậu tố timestamp, thêm "-1", "-2"... khi nhiều lần xoay vòng
+// rơi vào cùng một giây, để lần xoay vòng sau không ghi đè file sao lưu của lần trước.
+func backupPathFor(path string, now time.Time) string {
+	base := path + "." + now.Format(backupSuffixLayout)
+	backup := base
+	for n := 1; backupExists(backup); n++ {
+		backup = base + "-" + strconv.Itoa(n)
+	}
+	return backup
+}
+
+// backupExists kiểm tra file sao lưu hoặc một file dẫn xuất của nó (VD: ".gz", ".sig") đã tồn tại.
+func backupExists(backup string) bool {
+	if _, err := os.Lstat(backup); err == nil {
+		return true
+	}
+	matches, _ := filepath.Glob(backup + ".*")
+	return len(matches) > 0
+}
+
+// parseBackupSuffix tách hậu tố của file sao lưu (VD: "20240301120000" hoặc "20240301120000-2")
+// thành thời điểm xoay vòng theo giờ địa phương và số thứ tự trong cùng một giây.
+//
+// Trả về:
+//   - time.Time: thời điểm xoay vòng
+//   - int: số thứ tự, 0 nếu không có hậu tố "-N"
+//   - bool: false nếu suffix không phải hậu tố của file sao lưu
+func parseBackupSuffix(suffix string) (time.Time, int, bool) {
+	stamp, seq, hasSeq := strings.Cut(suffix, "-")
+	created, err := time.ParseInLocation(backupSuffixLayout, stamp, time.Local)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	if !hasSeq {
+		return created, 0, true
+	}
+	n, err := strconv.Atoi(seq)
+	if err != nil || n < 1 || strconv.Itoa(n) != seq {
+		return time.Time{}, 0, false
+	}
+	return created, n, true
+}
+
+// tailFile đọc ngược từ cuối file cho đến khi có đủ n entry hoặc đến đầu file. File nén được
+// giải nén toàn bộ vì không thể đọc ngược. Dòng đã mã hóa được giải mã bằng aead nếu khác nil.
+func tailFile(path string, n int, aead cipher.AEAD) ([]*Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if codec := CodecForPath(path); codec != nil {
+		r, err := codec.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		entries := parseTail(data, false, aead)
+		if len(entries) > n {
+			entries = entries[len(entries)-n:]
+		}
+		return entries, nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var entries []*Entry
+	for offset := info.Size(); offset > 0; {
+		size := int64(tailChunkSize)
+		if size > offset {
+			size = offset
+		}
+		offset -= size
+
+		chunk := make([]byte, int(size)+len(data))
+		if _, err := file.ReadAt(chunk[:size], offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		copy(chunk[size:], data)
+		data = chunk
+
+		entries = parseTail(data, offset > 0, aead)
+		if len(entries) >= n {
+			return entries[len(entries)-n:], nil
+		}
+	}
+	return entries, nil
+}
+
+// parseTail phân tích các entry trong data. Khi partial là true, dòng đầu tiên có thể bị cắt
+// giữa chừng nên được bỏ qua; các dòng tiếp theo không có entry đứng trước cũng bị bỏ qua vì
+// chúng thuộc một entry nằm ngoài data. Khi aead khác nil, dòng đã mã hóa được giải mã trước khi
+// phân tích và dòng không giải mã được bị bỏ qua.
+func parseTail(data []byte, partial bool, aead cipher.AEAD) []*Entry {
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte{'\n'}), []byte{'\n'})
+	if partial {
+		lines = lines[1:]
+	}
+	if aead != nil {
+		lines = decryptLines(lines, aead)
+	}
+
+	var entries []*Entry
+	for _, line := range lines {
+		entry, err := ParseLine(string(line), time.Local)
+		if err != nil {
+			if len(entries) > 0 {
+				last := entries[len(entries)-1]
+				last.Message += "\n" + string(line)
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// decryptLines giải mã các dòng bắt đầu bằng EncryptedPrefix, tách bản rõ nhiều dòng thành
+// nhiều dòng và bỏ các dòng không giải mã được.
+func decryptLines(lines [][]byte, aead cipher.AEAD) [][]byte {
+	out := make([][]byte, 0, len(lines))
+	for _, line := range lines {
+		if !bytes.HasPrefix(line, []byte(EncryptedPrefix)) {
+			out = append(out, line)
+			continue
+		}
+		_, plain, err := decryptLine(aead, line)
+		if err != nil {
+			continue
+		}
+		out = append(out, bytes.Split(plain, []byte{'\n'})...)
+	}
+	return out
+}
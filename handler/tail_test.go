@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	entry, err := ParseLine("2024/03/01 12:00:00 [WARNING] [HTTP] slow request", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseLine() error = %v", err)
+	}
+	if !entry.Time.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) || entry.Level != WarningLevel {
+		t.Errorf("ParseLine() phân tích sai timestamp hoặc level, got %v %v", entry.Time, entry.Level)
+	}
+	if entry.Message != "[HTTP] slow request" {
+		t.Errorf("ParseLine() phân tích sai message, got %q", entry.Message)
+	}
+
+	for _, line := range []string{"", "    at main.go:10", "2024/03/01 12:00:00 [VERBOSE] x", "2024/13/01 12:00:00 [INFO] x"} {
+		if _, err := ParseLine(line, nil); err == nil {
+			t.Errorf("ParseLine(%q) nên trả về lỗi", line)
+		}
+	}
+}
+
+func TestFileHandler_Tail(t *testing.T) {
+	h, err := NewFileHandler(filepath.Join(t.TempDir(), "app.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	if entries, err := h.Tail(5); err != nil || len(entries) != 0 {
+		t.Fatalf("Tail() trên file rỗng nên không trả về entry, got %v, %v", entries, err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		if err := h.Log(InfoLevel, "message %d", i); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+	if err := h.Log(ErrorLevel, "panic: boom\ngoroutine 1 [running]:\nmain.main()"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	entries, err := h.Tail(3)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Tail(3) nên trả về 3 entry, got %d", len(entries))
+	}
+	if entries[0].Message != "message 4" || entries[1].Message != "message 5" {
+		t.Errorf("Tail() nên trả về các entry cuối theo thứ tự cũ đến mới, got %q %q", entries[0].Message, entries[1].Message)
+	}
+	if entries[2].Level != ErrorLevel || entries[2].Message != "panic: boom\ngoroutine 1 [running]:\nmain.main()" {
+		t.Errorf("Tail() nên giữ nguyên thông điệp nhiều dòng, got %v %q", entries[2].Level, entries[2].Message)
+	}
+
+	if entries, _ := h.Tail(100); len(entries) != 6 {
+		t.Errorf("Tail() với n lớn hơn số entry nên trả về tất cả, got %d", len(entries))
+	}
+	if entries, _ := h.Tail(0); entries != nil {
+		t.Errorf("Tail(0) nên trả về nil, got %v", entries)
+	}
+}
+
+func TestFileHandler_Tail_AcrossChunks(t *testing.T) {
+	h, err := NewFileHandler(filepath.Join(t.TempDir(), "app.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	// Các entry lớn để n entry cuối nằm trên nhiều lần đọc ngược
+	payload := strings.Repeat("x", 20<<10)
+	for i := 0; i < 10; i++ {
+		if err := h.Log(InfoLevel, "%d %s", i, payload); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	entries, err := h.Tail(4)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Tail(4) nên trả về 4 entry, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if want := fmt.Sprintf("%d %s", i+6, payload); entry.Message != want {
+			t.Errorf("Entry %d bị đọc sai, got %d byte", i, len(entry.Message))
+		}
+	}
+}
+
+func TestFileHandler_Tail_IncludesLatestBackup(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	old := "2024/03/01 11:00:00 [INFO] oldest\n"
+	previous := "2024/03/01 12:00:00 [INFO] before rotation\n"
+	if err := os.WriteFile(logPath+".20240301110000", []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath+".20240301120000", []byte(previous), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath+".spill", []byte("not a log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := NewFileHandler(logPath, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	defer h.Close()
+	if err := h.Log(InfoLevel, "after rotation"); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	entries, err := h.Tail(2)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Message != "before rotation" || entries[1].Message != "after rotation" {
+		t.Errorf("Tail() nên lấy các entry còn thiếu từ file sao lưu mới nhất, got %v", entries)
+	}
+
+	if entries, _ := h.Tail(10); len(entries) != 3 || entries[0].Message != "oldest" {
+		t.Errorf("Tail() nên đọc các file sao lưu từ mới đến cũ, got %v", entries)
+	}
+}
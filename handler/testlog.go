@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TestingT là phần của testing.TB mà TestHandler cần, để package handler không phụ thuộc vào
+// package testing. *testing.T và *testing.B đều thỏa interface này.
+type TestingT interface {
+	// Helper đánh dấu hàm gọi là hàm hỗ trợ của test.
+	Helper()
+
+	// Logf ghi một dòng vào log của test.
+	Logf(format string, args ...interface{})
+
+	// Cleanup đăng ký hàm được gọi khi test kết thúc.
+	Cleanup(func())
+}
+
+// TestHandler ghi entry qua t.Logf của một test, nên log của mã đang được kiểm thử xuất hiện xen
+// kẽ với output của test trong `go test -v` và chỉ được in khi test thất bại nếu không có -v.
+//
+// Mỗi entry được ghi trên một dòng dạng "[LEVEL] message" (t.Logf đã gắn vị trí và thời gian của
+// test). Sau khi test kết thúc, entry bị bỏ thay vì gọi t.Logf (gây panic), để goroutine còn chạy
+// sau test không làm hỏng các test khác. Handler an toàn khi dùng đồng thời.
+type TestHandler struct {
+	t    TestingT
+	mu   sync.RWMutex
+	done bool // Test đã kết thúc
+}
+
+// NewTestHandler tạo handler ghi entry qua t.Logf, tự dừng ghi khi test kết thúc.
+//
+// Tham số:
+//   - t: TestingT - test nhận log, thường là *testing.T
+//
+// Trả về:
+//   - *TestHandler: handler ghi vào log của test
+//
+// Ví dụ:
+//
+//	func TestPlaceOrder(t *testing.T) {
+//	    logger := log.NewLogger("Orders")
+//	    logger.AddHandler("test", handler.NewTestHandler(t))
+//	    PlaceOrder(logger, 42) // orders.go:17: [INFO] [Orders] order 42 placed
+//	}
+func NewTestHandler(t TestingT) *TestHandler {
+	h := &TestHandler{t: t}
+	t.Cleanup(func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.done = true
+	})
+	return h
+}
+
+// Log ghi thông điệp vào log của test.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: luôn là nil
+func (h *TestHandler) Log(level Level, message string, args ...interface{}) error {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !h.done {
+		h.t.Helper()
+		h.t.Logf("[%s] %s", level, message)
+	}
+	return nil
+}
+
+// LogEntry ghi thông điệp của entry vào log của test.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần ghi
+//
+// Trả về:
+//   - error: luôn là nil
+func (h *TestHandler) LogEntry(entry *Entry) error {
+	return h.Log(entry.Level, entry.Message)
+}
+
+// Close không làm gì; handler tự dừng ghi khi test kết thúc.
+//
+// Trả về:
+//   - error: luôn là nil
+func (h *TestHandler) Close() error {
+	return nil
+}
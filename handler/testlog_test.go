@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fakeT ghi lại các dòng log và hàm cleanup thay cho *testing.T.
+type fakeT struct {
+	lines   []string
+	cleanup func()
+}
+
+func (f *fakeT) Helper() {}
+func (f *fakeT) Logf(format string, args ...interface{}) {
+	f.lines = append(f.lines, fmt.Sprintf(format, args...))
+}
+func (f *fakeT) Cleanup(fn func()) { f.cleanup = fn }
+
+func TestTestHandler(t *testing.T) {
+	ft := &fakeT{}
+	h := NewTestHandler(ft)
+
+	h.Log(InfoLevel, "order %d placed", 42)
+	h.LogEntry(&Entry{Level: ErrorLevel, Message: "payment failed"})
+	if len(ft.lines) != 2 || ft.lines[0] != "[INFO] order 42 placed" || ft.lines[1] != "[ERROR] payment failed" {
+		t.Fatalf("lines = %q", ft.lines)
+	}
+
+	ft.cleanup()
+	if err := h.Log(InfoLevel, "after test"); err != nil {
+		t.Errorf("Log() sau khi test kết thúc error = %v", err)
+	}
+	if len(ft.lines) != 2 {
+		t.Errorf("Log() sau khi test kết thúc không nên ghi, got %q", ft.lines)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestTestHandler_RealT(t *testing.T) {
+	var h *TestHandler
+	t.Run("sub", func(t *testing.T) {
+		h = NewTestHandler(t)
+		h.Log(DebugLevel, "inside subtest")
+	})
+	// Subtest đã kết thúc: ghi log không được panic
+	h.Log(InfoLevel, "after subtest")
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Giá trị mặc định và giới hạn của ThrottledHandler.
+const (
+	// DefaultThrottleSummaryInterval là khoảng thời gian mặc định giữa hai bản ghi tóm tắt
+	DefaultThrottleSummaryInterval = time.Minute
+
+	// maxThrottleContexts là số context tối đa được theo dõi; khi vượt quá, các bucket theo
+	// context được đặt lại để bộ nhớ không tăng vô hạn
+	maxThrottleContexts = 1024
+)
+
+// ThrottleLimit giới hạn tốc độ ghi log theo thuật toán token bucket.
+type ThrottleLimit struct {
+	Rate  float64 // Số entry được phép trung bình mỗi giây, 0 để không giới hạn
+	Burst int     // Số entry tối đa được ghi liên tiếp, 0 để dùng Rate (tối thiểu 1)
+}
+
+// enabled kiểm tra giới hạn có được bật hay không.
+func (l ThrottleLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+// burst trả về dung lượng của bucket.
+func (l ThrottleLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return max(1, l.Rate)
+}
+
+// ThrottleOptions cấu hình ThrottledHandler.
+type ThrottleOptions struct {
+	Levels          map[Level]ThrottleLimit // Giới hạn chung theo cấp độ, cấp độ không có trong map không bị giới hạn
+	PerContext      ThrottleLimit           // Giới hạn riêng cho mỗi context (phần "[Context]" đầu thông điệp)
+	SummaryInterval time.Duration           // Khoảng thời gian tối thiểu giữa hai bản ghi tóm tắt, 0 để dùng mặc định
+}
+
+// tokenBucket lưu số token còn lại của một bucket.
+type tokenBucket struct {
+	tokens float64   // Số token hiện có
+	last   time.Time // Thời điểm cập nhật gần nhất
+}
+
+// refill bổ sung token theo thời gian đã trôi qua và trả về true nếu có ít nhất một token.
+func (b *tokenBucket) refill(limit ThrottleLimit, now time.Time) bool {
+	if b.last.IsZero() {
+		b.tokens = limit.burst()
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(limit.burst(), b.tokens+elapsed*limit.Rate)
+	}
+	b.last = now
+	return b.tokens >= 1
+}
+
+// ThrottledHandler bọc một handler và giới hạn tốc độ ghi log theo cấp độ và theo context.
+//
+// Mỗi entry phải còn token trong bucket của cấp độ và bucket của context (nếu được cấu hình);
+// entry vượt giới hạn bị bỏ. Khi có entry bị bỏ, một bản ghi tóm tắt cấp độ Warning
+// ("suppressed 1532 messages in last 60s") được ghi đến handler bên trong, tối đa một lần
+// mỗi SummaryInterval, và khi handler được đóng. Handler không dùng goroutine nền và an toàn
+// khi dùng đồng thời.
+type ThrottledHandler struct {
+	handler     Handler
+	levels      [FatalLevel + 1]ThrottleLimit
+	perContext  ThrottleLimit
+	interval    time.Duration
+	mu          sync.Mutex
+	levelBucket [FatalLevel + 1]tokenBucket
+	contexts    map[string]*tokenBucket
+	pending     uint64        // Số entry bị bỏ chưa được tóm tắt
+	windowStart time.Time     // Thời điểm bắt đầu cửa sổ tóm tắt hiện tại
+	suppressed  atomic.Uint64 // Tổng số entry bị bỏ
+	now         func() time.Time
+}
+
+// NewThrottledHandler tạo một handler giới hạn tốc độ các entry trước khi chuyển đến h.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry không bị giới hạn
+//   - opts: ThrottleOptions - giới hạn theo cấp độ, theo context và chu kỳ tóm tắt
+//
+// Trả về:
+//   - *ThrottledHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	// Tối đa 100 debug/giây cho cả ứng dụng và 10 entry/giây (burst 50) cho mỗi context
+//	throttled := handler.NewThrottledHandler(fileHandler, handler.ThrottleOptions{
+//	    Levels:     map[handler.Level]handler.ThrottleLimit{handler.DebugLevel: {Rate: 100}},
+//	    PerContext: handler.ThrottleLimit{Rate: 10, Burst: 50},
+//	})
+func NewThrottledHandler(h Handler, opts ThrottleOptions) *ThrottledHandler {
+	t := &ThrottledHandler{
+		handler:    h,
+		perContext: opts.PerContext,
+		interval:   opts.SummaryInterval,
+		contexts:   make(map[string]*tokenBucket),
+		now:        time.Now,
+	}
+	if t.interval <= 0 {
+		t.interval = DefaultThrottleSummaryInterval
+	}
+	for level, limit := range opts.Levels {
+		if level >= DebugLevel && level <= FatalLevel {
+			t.levels[level] = limit
+		}
+	}
+	return t
+}
+
+// Log chuyển entry đến handler bên trong nếu entry không vượt giới hạn.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong, hoặc nil nếu entry bị bỏ
+func (t *ThrottledHandler) Log(level Level, message string, args ...interface{}) error {
+	allowed, summary := t.allow(level, message)
+	err := t.writeSummary(summary)
+	if !allowed {
+		return err
+	}
+	if logErr := t.handler.Log(level, message, args...); logErr != nil {
+		return logErr
+	}
+	return err
+}
+
+// LogEntry chuyển entry hoàn chỉnh đến handler bên trong nếu entry không vượt giới hạn.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong, hoặc nil nếu entry bị bỏ
+func (t *ThrottledHandler) LogEntry(entry *Entry) error {
+	allowed, summary := t.allow(entry.Level, entry.Message)
+	err := t.writeSummary(summary)
+	if !allowed {
+		return err
+	}
+	if logErr := Dispatch(t.handler, entry); logErr != nil {
+		return logErr
+	}
+	return err
+}
+
+// Suppressed trả về tổng số entry đã bị bỏ do vượt giới hạn.
+//
+// Trả về:
+//   - uint64: số entry bị bỏ kể từ khi tạo handler
+func (t *ThrottledHandler) Suppressed() uint64 {
+	return t.suppressed.Load()
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (t *ThrottledHandler) Unwrap() Handler {
+	return t.handler
+}
+
+// Close ghi bản ghi tóm tắt còn lại (nếu có) rồi đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi ghi bản ghi tóm tắt hoặc khi đóng handler bên trong
+func (t *ThrottledHandler) Close() error {
+	t.mu.Lock()
+	summary := t.takeSummary(t.now())
+	t.mu.Unlock()
+
+	err := t.writeSummary(summary)
+	if closeErr := t.handler.Close(); closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+// allow kiểm tra giới hạn của entry và trả về bản ghi tóm tắt cần ghi (nếu đến hạn).
+func (t *ThrottledHandler) allow(level Level, message string) (bool, *Entry) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var levelBucket, contextBucket *tokenBucket
+	if level >= DebugLevel && level <= FatalLevel && t.levels[level].enabled() {
+		levelBucket = &t.levelBucket[level]
+	}
+	if t.perContext.enabled() {
+		context := messageContext(message)
+		contextBucket = t.contexts[context]
+		if contextBucket == nil {
+			if len(t.contexts) >= maxThrottleContexts {
+				t.contexts = make(map[string]*tokenBucket)
+			}
+			contextBucket = &tokenBucket{}
+			t.contexts[context] = contextBucket
+		}
+	}
+
+	// Chỉ tiêu thụ token khi cả hai bucket đều còn token
+	allowed := true
+	if levelBucket != nil && !levelBucket.refill(t.levels[level], now) {
+		allowed = false
+	}
+	if contextBucket != nil && !contextBucket.refill(t.perContext, now) {
+		allowed = false
+	}
+	if allowed {
+		if levelBucket != nil {
+			levelBucket.tokens--
+		}
+		if contextBucket != nil {
+			contextBucket.tokens--
+		}
+	} else {
+		if t.pending == 0 {
+			t.windowStart = now
+		}
+		t.pending++
+		t.suppressed.Add(1)
+	}
+
+	if t.pending > 0 && now.Sub(t.windowStart) >= t.interval {
+		return allowed, t.takeSummary(now)
+	}
+	return allowed, nil
+}
+
+// takeSummary tạo bản ghi tóm tắt cho các entry bị bỏ và bắt đầu cửa sổ mới.
+//
+// Method này phải được gọi khi đang giữ t.mu.
+func (t *ThrottledHandler) takeSummary(now time.Time) *Entry {
+	if t.pending == 0 {
+		return nil
+	}
+	window := now.Sub(t.windowStart).Round(time.Second)
+	entry := &Entry{
+		Time:    now,
+		Level:   WarningLevel,
+		Message: fmt.Sprintf("suppressed %d messages in last %ds", t.pending, int64(window/time.Second)),
+	}
+	t.pending = 0
+	return entry
+}
+
+// writeSummary ghi bản ghi tóm tắt đến handler bên trong.
+func (t *ThrottledHandler) writeSummary(summary *Entry) error {
+	if summary == nil {
+		return nil
+	}
+	return Dispatch(t.handler, summary)
+}
+
+// messageContext trả về context trong phần "[Context]" đầu thông điệp do logger định dạng,
+// hoặc chuỗi rỗng nếu thông điệp không có context.
+func messageContext(message string) string {
+	if !strings.HasPrefix(message, "[") {
+		return ""
+	}
+	context, _, ok := strings.Cut(message[1:], "]")
+	if !ok {
+		return ""
+	}
+	return context
+}
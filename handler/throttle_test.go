@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// messagesOf trả về thông điệp của các entry mà rec đã nhận.
+func messagesOf(rec *slowRecorder) []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	messages := make([]string, len(rec.entries))
+	for i, e := range rec.entries {
+		messages[i] = e.Message
+	}
+	return messages
+}
+
+// newThrottleTest tạo ThrottledHandler với đồng hồ có thể điều khiển.
+func newThrottleTest(opts ThrottleOptions) (*ThrottledHandler, *slowRecorder, *time.Time) {
+	rec := &slowRecorder{}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	h := NewThrottledHandler(rec, opts)
+	h.now = func() time.Time { return now }
+	return h, rec, &now
+}
+
+func TestThrottledHandler_LevelLimit(t *testing.T) {
+	h, rec, now := newThrottleTest(ThrottleOptions{
+		Levels: map[Level]ThrottleLimit{DebugLevel: {Rate: 2, Burst: 3}},
+	})
+
+	for i := 0; i < 10; i++ {
+		_ = h.Log(DebugLevel, "[Worker] tick")
+	}
+	_ = h.Log(ErrorLevel, "[Worker] failed")
+	if got := len(messagesOf(rec)); got != 4 {
+		t.Fatalf("Nên ghi 3 debug (burst) và 1 error không giới hạn, got %d: %v", got, messagesOf(rec))
+	}
+	if h.Suppressed() != 7 {
+		t.Errorf("Suppressed() = %d, want 7", h.Suppressed())
+	}
+
+	// Sau 1 giây bucket được bổ sung 2 token
+	*now = now.Add(time.Second)
+	for i := 0; i < 5; i++ {
+		_ = h.Log(DebugLevel, "[Worker] tick")
+	}
+	if got := len(messagesOf(rec)); got != 6 {
+		t.Errorf("Bucket nên được bổ sung theo Rate, got %d entry", got)
+	}
+}
+
+func TestThrottledHandler_PerContext(t *testing.T) {
+	h, rec, _ := newThrottleTest(ThrottleOptions{PerContext: ThrottleLimit{Rate: 1}})
+
+	for i := 0; i < 5; i++ {
+		_ = h.LogEntry(&Entry{Level: InfoLevel, Message: "[Reconnect] retrying"})
+		_ = h.LogEntry(&Entry{Level: InfoLevel, Message: "[Payment] charged"})
+	}
+	got := messagesOf(rec)
+	if len(got) != 2 || got[0] != "[Reconnect] retrying" || got[1] != "[Payment] charged" {
+		t.Errorf("Mỗi context nên có bucket riêng, got %v", got)
+	}
+}
+
+func TestThrottledHandler_Summary(t *testing.T) {
+	h, rec, now := newThrottleTest(ThrottleOptions{
+		Levels:          map[Level]ThrottleLimit{WarningLevel: {Rate: 1}},
+		SummaryInterval: time.Minute,
+	})
+
+	for i := 0; i < 1533; i++ {
+		_ = h.Log(WarningLevel, "[DB] slow query")
+	}
+	if len(messagesOf(rec)) != 1 {
+		t.Fatalf("Bản ghi tóm tắt chưa đến hạn không nên được ghi, got %v", messagesOf(rec))
+	}
+
+	*now = now.Add(time.Minute)
+	_ = h.Log(WarningLevel, "[DB] slow query")
+	got := messagesOf(rec)
+	if len(got) != 3 || got[1] != "suppressed 1532 messages in last 60s" || got[2] != "[DB] slow query" {
+		t.Fatalf("Nên ghi bản ghi tóm tắt trước entry tiếp theo, got %v", got)
+	}
+	if rec.entries[1].Level != WarningLevel {
+		t.Errorf("Bản ghi tóm tắt nên có cấp độ Warning, got %v", rec.entries[1].Level)
+	}
+
+	// Entry bị bỏ còn lại được tóm tắt khi đóng
+	*now = now.Add(10 * time.Second)
+	_ = h.Log(WarningLevel, "[DB] slow query")
+	_ = h.Log(WarningLevel, "[DB] slow query")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	got = messagesOf(rec)
+	if got[len(got)-1] != "suppressed 1 messages in last 0s" {
+		t.Errorf("Close() nên ghi bản ghi tóm tắt còn lại, got %v", got)
+	}
+}
+
+func TestThrottledHandler_Concurrent(t *testing.T) {
+	rec := &slowRecorder{}
+	h := NewThrottledHandler(rec, ThrottleOptions{
+		Levels:     map[Level]ThrottleLimit{InfoLevel: {Rate: 0.001, Burst: 100}},
+		PerContext: ThrottleLimit{Rate: 1, Burst: 1000},
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_ = h.Log(InfoLevel, "[Worker] tick")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if kept := uint64(len(messagesOf(rec))); kept != 100 || h.Suppressed() != 700 {
+		t.Errorf("Nên ghi đúng 100 entry (burst) và bỏ phần còn lại, got kept=%d suppressed=%d", kept, h.Suppressed())
+	}
+	if h.Unwrap() != rec {
+		t.Error("Unwrap() nên trả về handler bên trong")
+	}
+}
+
+func TestMessageContext(t *testing.T) {
+	tests := map[string]string{
+		"[Payment] charged": "Payment",
+		"no context":        "",
+		"[unterminated":     "",
+		"[] empty":          "",
+	}
+	for message, want := range tests {
+		if got := messageContext(message); got != want {
+			t.Errorf("messageContext(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// FieldTransform biến đổi các field của một entry trước khi handler ghi ra (VD: đổi tên, bỏ,
+// băm hoặc tính thêm field).
+//
+// Transform nhận bản sao riêng của các field nên có thể sửa tại chỗ hoặc append, và trả về
+// danh sách field mới. Transform được gọi trên goroutine ghi log nên phải an toàn khi dùng
+// đồng thời.
+type FieldTransform func(fields []Field) []Field
+
+// TransformOptions cấu hình TransformHandler.
+type TransformOptions struct {
+	Transforms []FieldTransform // Các transform, áp dụng theo thứ tự
+	Limits     Limits           // Giới hạn của logger khi định dạng field trong thông điệp (xem TransformHandler)
+}
+
+// RenameField đổi key của các field có key from thành to.
+//
+// Tham số:
+//   - from: string - key hiện tại
+//   - to: string - key mới
+//
+// Trả về:
+//   - FieldTransform: transform đổi tên field
+func RenameField(from, to string) FieldTransform {
+	return func(fields []Field) []Field {
+		for i := range fields {
+			if fields[i].Key == from {
+				fields[i].Key = to
+			}
+		}
+		return fields
+	}
+}
+
+// DropFields bỏ các field có key khớp một trong các mẫu (hỗ trợ ký tự đại diện theo path.Match,
+// VD: "request_*").
+//
+// Tham số:
+//   - patterns: ...string - các mẫu key cần bỏ
+//
+// Trả về:
+//   - FieldTransform: transform bỏ field
+func DropFields(patterns ...string) FieldTransform {
+	return func(fields []Field) []Field {
+		out := fields[:0]
+		for _, f := range fields {
+			if !matchAny(patterns, f.Key) {
+				out = append(out, f)
+			}
+		}
+		return out
+	}
+}
+
+// LowercaseKeys chuyển key của mọi field thành chữ thường (VD: "UserID" thành "userid"), để
+// thống nhất tên field từ nhiều nguồn.
+//
+// Trả về:
+//   - FieldTransform: transform chuyển key thành chữ thường
+func LowercaseKeys() FieldTransform {
+	return func(fields []Field) []Field {
+		for i := range fields {
+			fields[i].Key = strings.ToLower(fields[i].Key)
+		}
+		return fields
+	}
+}
+
+// MapField thay giá trị của các field có key bằng kết quả của fn.
+//
+// Tham số:
+//   - key: string - key của field cần biến đổi
+//   - fn: func(Field) Field - hàm nhận field hiện tại và trả về field mới
+//
+// Trả về:
+//   - FieldTransform: transform biến đổi field
+//
+// Ví dụ:
+//
+//	// Che số thẻ, chỉ giữ 4 chữ số cuối
+//	handler.MapField("card", func(f handler.Field) handler.Field {
+//	    s := f.Str
+//	    return handler.Field{Key: f.Key, Type: handler.StringType, Str: "****" + s[max(0, len(s)-4):]}
+//	})
+func MapField(key string, fn func(Field) Field) FieldTransform {
+	return func(fields []Field) []Field {
+		for i := range fields {
+			if fields[i].Key == key {
+				fields[i] = fn(fields[i])
+			}
+		}
+		return fields
+	}
+}
+
+// HashFields thay giá trị của các field có key bằng mã SHA-256 dạng hex của giá trị, để vẫn
+// liên kết được các entry của cùng một người dùng mà không ghi ra định danh gốc.
+//
+// Băm không có khóa nên giá trị dễ đoán (VD: ID tuần tự) có thể bị dò ngược; hãy dùng MapField
+// với HMAC khi cần bảo vệ chặt hơn.
+//
+// Tham số:
+//   - keys: ...string - key của các field cần băm
+//
+// Trả về:
+//   - FieldTransform: transform băm field
+func HashFields(keys ...string) FieldTransform {
+	return func(fields []Field) []Field {
+		for i, f := range fields {
+			for _, key := range keys {
+				if f.Key == key {
+					sum := sha256.Sum256([]byte(fieldText(f)))
+					fields[i] = Field{Key: f.Key, Type: StringType, Str: hex.EncodeToString(sum[:])}
+					break
+				}
+			}
+		}
+		return fields
+	}
+}
+
+// ComputeField thêm field key với giá trị tính từ các field hiện có; fn trả về false để không
+// thêm field.
+//
+// Tham số:
+//   - key: string - key của field được thêm
+//   - fn: func([]Field) (interface{}, bool) - hàm tính giá trị từ các field của entry
+//
+// Trả về:
+//   - FieldTransform: transform thêm field
+//
+// Ví dụ:
+//
+//	// Thêm "slow" cho request chậm hơn 1 giây
+//	handler.ComputeField("slow", func(fields []handler.Field) (interface{}, bool) {
+//	    for _, f := range fields {
+//	        if f.Key == "latency" && f.Type == handler.DurationType {
+//	            return time.Duration(f.Integer) > time.Second, true
+//	        }
+//	    }
+//	    return nil, false
+//	})
+func ComputeField(key string, fn func([]Field) (interface{}, bool)) FieldTransform {
+	return func(fields []Field) []Field {
+		if value, ok := fn(fields); ok {
+			fields = append(fields, Field{Key: key, Value: value})
+		}
+		return fields
+	}
+}
+
+// fieldText trả về giá trị của field dưới dạng chuỗi không có dấu ngoặc kép.
+func fieldText(f Field) string {
+	if f.Type == StringType {
+		return f.Str
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+// TransformHandler bọc một handler và biến đổi các field có cấu trúc của entry (đổi tên, bỏ,
+// băm, tính thêm) trước khi handler bên trong ghi ra.
+//
+// Giống FieldFilterHandler, phần " key=value ..." cuối Message được định dạng lại với
+// TransformOptions.Limits và thay bằng các field đã biến đổi; nếu phần cuối của Message không
+// khớp, chỉ Entry.Fields được biến đổi. Entry gốc không bị sửa. Handler an toàn khi dùng đồng
+// thời nếu các transform an toàn.
+type TransformHandler struct {
+	handler Handler
+	opts    TransformOptions
+}
+
+// NewTransformHandler tạo handler biến đổi field của các entry trước khi chuyển đến h.
+//
+// Tham số:
+//   - h: Handler - handler nhận các entry đã biến đổi
+//   - opts: TransformOptions - các transform và giới hạn định dạng
+//
+// Trả về:
+//   - *TransformHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	file := handler.NewTransformHandler(fileHandler, handler.TransformOptions{
+//	    Transforms: []handler.FieldTransform{
+//	        handler.LowercaseKeys(),
+//	        handler.RenameField("uid", "user_id"),
+//	        handler.HashFields("user_id"),
+//	        handler.DropFields("request_*"),
+//	    },
+//	})
+func NewTransformHandler(h Handler, opts TransformOptions) *TransformHandler {
+	return &TransformHandler{handler: h, opts: opts}
+}
+
+// Log chuyển thông điệp đến handler bên trong. Thông điệp không có field có cấu trúc nên
+// không bị thay đổi.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (t *TransformHandler) Log(level Level, message string, args ...interface{}) error {
+	return t.handler.Log(level, message, args...)
+}
+
+// LogEntry biến đổi field của bản sao entry rồi chuyển đến handler bên trong.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (t *TransformHandler) LogEntry(entry *Entry) error {
+	if len(t.opts.Transforms) == 0 {
+		return Dispatch(t.handler, entry)
+	}
+
+	fields := make([]Field, len(entry.Fields), len(entry.Fields)+1)
+	copy(fields, entry.Fields)
+	for _, transform := range t.opts.Transforms {
+		fields = transform(fields)
+	}
+
+	transformed := *entry
+	transformed.Fields = fields
+	buf := GetBuffer()
+	if len(entry.Fields) > 0 {
+		*buf = append(*buf, ' ')
+		*buf = t.opts.Limits.AppendFields(*buf, entry.Fields)
+	}
+	if base, ok := strings.CutSuffix(entry.Message, string(*buf)); ok {
+		*buf = append((*buf)[:0], base...)
+		if len(fields) > 0 {
+			*buf = append(*buf, ' ')
+			*buf = t.opts.Limits.AppendFields(*buf, fields)
+		}
+		transformed.Message = string(*buf)
+	}
+	PutBuffer(buf)
+	return Dispatch(t.handler, &transformed)
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (t *TransformHandler) Unwrap() Handler {
+	return t.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (t *TransformHandler) Close() error {
+	return t.handler.Close()
+}
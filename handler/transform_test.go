@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// entryTestHandler giữ entry cuối cùng nhận được qua LogEntry
+type entryTestHandler struct {
+	MockTestHandler
+	entry *Entry
+}
+
+func (e *entryTestHandler) LogEntry(entry *Entry) error {
+	e.entry = entry
+	return nil
+}
+
+func TestTransformHandler(t *testing.T) {
+	fields := []Field{
+		{Key: "UID", Type: StringType, Str: "42"},
+		{Key: "request_body", Type: StringType, Str: "{}"},
+		{Key: "Status", Type: Int64Type, Integer: 500},
+	}
+	entry := &Entry{Level: InfoLevel, Message: "[API] done " + FormatFields(fields), Fields: fields}
+	inner := &entryTestHandler{}
+
+	h := NewTransformHandler(inner, TransformOptions{Transforms: []FieldTransform{
+		LowercaseKeys(),
+		RenameField("uid", "user_id"),
+		HashFields("user_id"),
+		DropFields("request_*"),
+		ComputeField("failed", func(fields []Field) (interface{}, bool) {
+			for _, f := range fields {
+				if f.Key == "status" {
+					return f.Integer >= 500, true
+				}
+			}
+			return nil, false
+		}),
+	}})
+	if err := h.LogEntry(entry); err != nil {
+		t.Fatalf("LogEntry() error = %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("42"))
+	want := "[API] done user_id=" + hex.EncodeToString(sum[:]) + " status=500 failed=true"
+	if inner.entry.Message != want {
+		t.Errorf("Message = %q, want %q", inner.entry.Message, want)
+	}
+	if got := FormatFields(inner.entry.Fields); !strings.HasPrefix(got, "user_id=") || strings.Contains(got, "request_body") {
+		t.Errorf("Fields chưa được biến đổi, got %s", got)
+	}
+	if entry.Fields[0].Key != "UID" || len(entry.Fields) != 3 {
+		t.Error("Entry gốc không nên bị sửa")
+	}
+}
+
+func TestTransformHandler_MessageWithoutFields(t *testing.T) {
+	inner := &entryTestHandler{}
+	h := NewTransformHandler(inner, TransformOptions{Transforms: []FieldTransform{
+		MapField("user", func(f Field) Field { return Field{Key: f.Key, Type: StringType, Str: "***"} }),
+	}})
+
+	// Thông điệp không kết thúc bằng field nên chỉ Fields được biến đổi
+	entry := &Entry{Message: "custom message", Fields: []Field{{Key: "user", Type: StringType, Str: "john"}}}
+	h.LogEntry(entry)
+	if inner.entry.Message != "custom message" || inner.entry.Fields[0].Str != "***" {
+		t.Errorf("got %q %v", inner.entry.Message, inner.entry.Fields)
+	}
+
+	h.Log(InfoLevel, "plain")
+	if !inner.LogCalled {
+		t.Error("Log() nên chuyển thông điệp đến handler bên trong")
+	}
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Giới hạn mặc định khi giá trị của Limits không dương.
+const (
+	DefaultMaxFieldDepth    = 5   // Độ sâu lồng nhau tối đa của map, slice và struct
+	DefaultMaxFieldElements = 100 // Số phần tử tối đa được giữ lại của mỗi map, slice hoặc struct
+	DefaultMaxFields        = 100 // Số field tối đa của mỗi entry
+)
+
+// Các marker thay thế cho phần giá trị bị cắt bớt khi vượt giới hạn.
+const (
+	// TruncatedKey là key được thêm vào map/struct khi số phần tử vượt giới hạn, với giá trị "+N more"
+	TruncatedKey = "…"
+
+	// TruncatedValue thay thế giá trị lồng nhau vượt quá độ sâu tối đa
+	TruncatedValue = "[truncated]"
+
+	// FieldsTruncatedKey là key của field được thêm vào cuối entry khi số field vượt giới hạn,
+	// với giá trị là số field bị bỏ
+	FieldsTruncatedKey = "fields_truncated"
+)
+
+// Limits giới hạn kích thước của các field trong một entry, bảo vệ formatter và hệ thống
+// đánh chỉ mục phía sau khỏi các payload bất thường (VD: ghi nguyên struct của một request).
+//
+// Giá trị không dương dùng giới hạn mặc định tương ứng.
+type Limits struct {
+	MaxDepth    int // Độ sâu lồng nhau tối đa của map, slice và struct (0 = DefaultMaxFieldDepth)
+	MaxElements int // Số phần tử tối đa của mỗi map, slice hoặc struct (0 = DefaultMaxFieldElements)
+	MaxFields   int // Số field tối đa của mỗi entry (0 = DefaultMaxFields)
+}
+
+// withDefaults trả về Limits với các giá trị không dương được thay bằng giá trị mặc định.
+func (l Limits) withDefaults() Limits {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultMaxFieldDepth
+	}
+	if l.MaxElements <= 0 {
+		l.MaxElements = DefaultMaxFieldElements
+	}
+	if l.MaxFields <= 0 {
+		l.MaxFields = DefaultMaxFields
+	}
+	return l
+}
+
+// Normalize chuyển giá trị của field thành dạng tương thích JSON theo giới hạn độ sâu và
+// số phần tử của l. Xem NormalizeValue.
+//
+// Tham số:
+//   - value: interface{} - giá trị cần chuẩn hóa
+//
+// Trả về:
+//   - interface{}: giá trị đã chuẩn hóa
+func (l Limits) Normalize(value interface{}) interface{} {
+	return normalize(value, 0, l.withDefaults())
+}
+
+// TruncateFields giữ lại tối đa MaxFields field đầu tiên và thêm field FieldsTruncatedKey
+// chứa số field bị bỏ. Danh sách được trả về nguyên vẹn khi không vượt giới hạn.
+//
+// Tham số:
+//   - fields: []Field - các field của entry
+//
+// Trả về:
+//   - []Field: các field sau khi cắt bớt
+//
+// Ví dụ:
+//
+//	handler.Limits{MaxFields: 2}.TruncateFields(fields) // [a b fields_truncated=3]
+func (l Limits) TruncateFields(fields []Field) []Field {
+	max := l.withDefaults().MaxFields
+	if len(fields) <= max {
+		return fields
+	}
+	return append(fields[:max:max], Field{Key: FieldsTruncatedKey, Type: Int64Type, Integer: int64(len(fields) - max)})
+}
+
+// AppendFields nối các field ở dạng key=value vào cuối dst, mã hóa map, slice và struct
+// theo giới hạn của l. Xem AppendFields.
+//
+// Tham số:
+//   - dst: []byte - buffer đích
+//   - fields: []Field - danh sách field cần định dạng
+//
+// Trả về:
+//   - []byte: buffer đã được nối thêm các field
+func (l Limits) AppendFields(dst []byte, fields []Field) []byte {
+	l = l.withDefaults()
+	for i, f := range fields {
+		if i > 0 {
+			dst = append(dst, ' ')
+		}
+		dst = f.appendTo(dst, l)
+	}
+	return dst
+}
+
+// NormalizeValue chuyển giá trị của field thành dạng tương thích JSON với thứ tự xác định.
+//
+// Map được chuyển thành map[string]interface{}, slice và array thành []interface{}, struct
+// thành map theo tên field (tôn trọng tag json), Field và []Field thành object. Giá trị
+// lồng nhau quá sâu (DefaultMaxFieldDepth) được thay bằng TruncatedValue; map, slice và struct
+// có quá nhiều phần tử (DefaultMaxFieldElements) chỉ giữ lại các phần tử đầu tiên kèm marker
+// số phần tử bị bỏ. Dùng Limits.Normalize để chọn giới hạn khác. Mọi formatter (logfmt,
+// console, JSON) dùng cùng kết quả này để giá trị được hiển thị nhất quán giữa các handler.
+//
+// Tham số:
+//   - value: interface{} - giá trị cần chuẩn hóa
+//
+// Trả về:
+//   - interface{}: giá trị đã chuẩn hóa, có thể truyền trực tiếp cho json.Marshal
+//
+// Ví dụ:
+//
+//	handler.NormalizeValue(map[string][]int{"ids": {1, 2}}) // map[string]interface{}{"ids": []interface{}{1, 2}}
+func NormalizeValue(value interface{}) interface{} {
+	return Limits{}.Normalize(value)
+}
+
+// normalize chuẩn hóa một giá trị ở độ sâu đã cho. limits phải đã được áp dụng giá trị mặc định.
+func normalize(value interface{}, depth int, limits Limits) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string, bool, json.Number:
+		return v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case []byte:
+		return string(v)
+	case error:
+		if isNilPointer(v) {
+			return nil
+		}
+		return v.Error()
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return nil
+		}
+		return v.String()
+	case json.Marshaler, encoding.TextMarshaler:
+		if isNilPointer(v) {
+			return nil
+		}
+		return v
+	case Field:
+		if depth >= limits.MaxDepth {
+			return TruncatedValue
+		}
+		return map[string]interface{}{v.Key: normalize(v.Interface(), depth+1, limits)}
+	case []Field:
+		if depth >= limits.MaxDepth {
+			return TruncatedValue
+		}
+		out := make(map[string]interface{}, len(v))
+		for i, f := range v {
+			if i == limits.MaxElements {
+				out[TruncatedKey] = moreMarker(len(v) - i)
+				break
+			}
+			out[f.Key] = normalize(f.Interface(), depth+1, limits)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface(), depth, limits)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		if depth >= limits.MaxDepth {
+			return TruncatedValue
+		}
+		n := rv.Len()
+		out := make([]interface{}, 0, min(n, limits.MaxElements+1))
+		for i := 0; i < n; i++ {
+			if i == limits.MaxElements {
+				out = append(out, moreMarker(n-i))
+				break
+			}
+			out = append(out, normalize(rv.Index(i).Interface(), depth+1, limits))
+		}
+		return out
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		if depth >= limits.MaxDepth {
+			return TruncatedValue
+		}
+		keys := make([]string, 0, rv.Len())
+		values := make(map[string]reflect.Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		// Sắp xếp để các phần tử được giữ lại khi cắt bớt là xác định
+		sort.Strings(keys)
+		out := make(map[string]interface{}, min(len(keys), limits.MaxElements+1))
+		for i, key := range keys {
+			if i == limits.MaxElements {
+				out[TruncatedKey] = moreMarker(len(keys) - i)
+				break
+			}
+			out[key] = normalize(values[key].Interface(), depth+1, limits)
+		}
+		return out
+	case reflect.Struct:
+		if depth >= limits.MaxDepth {
+			return TruncatedValue
+		}
+		return normalizeStruct(rv, depth, limits)
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return fmt.Sprintf("%T", value)
+	default:
+		// Kiểu cơ bản được định nghĩa lại (VD: type Status int)
+		return fmt.Sprint(value)
+	}
+}
+
+// normalizeStruct chuyển các field exported của struct thành map theo tên trong tag json.
+func normalizeStruct(rv reflect.Value, depth int, limits Limits) map[string]interface{} {
+	rt := rv.Type()
+	out := make(map[string]interface{}, rt.NumField())
+	count := 0
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		if count == limits.MaxElements {
+			out[TruncatedKey] = moreMarker(exportedFields(rt) - count)
+			break
+		}
+		out[name] = normalize(rv.Field(i).Interface(), depth+1, limits)
+		count++
+	}
+	return out
+}
+
+// exportedFields đếm số field exported không bị bỏ qua bởi tag json của struct.
+func exportedFields(rt reflect.Type) int {
+	n := 0
+	for i := 0; i < rt.NumField(); i++ {
+		if sf := rt.Field(i); sf.IsExported() && sf.Tag.Get("json") != "-" {
+			n++
+		}
+	}
+	return n
+}
+
+// isNilPointer kiểm tra giá trị có phải là con trỏ nil được bọc trong interface hay không.
+func isNilPointer(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// moreMarker trả về marker cho số phần tử bị bỏ qua, VD: "+3 more".
+func moreMarker(n int) string {
+	return "+" + strconv.Itoa(n) + " more"
+}
+
+// isComposite kiểm tra giá trị có phải là map, slice, array hoặc struct cần được mã hóa
+// qua NormalizeValue thay vì fmt.Sprint hay không.
+func isComposite(value interface{}) bool {
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// encodeComposite mã hóa map, slice, array hoặc struct thành JSON đã chuẩn hóa theo limits.
+func encodeComposite(value interface{}, limits Limits) string {
+	data, err := json.Marshal(normalize(value, 0, limits))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
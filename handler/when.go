@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConditionalHandler chỉ chuyển đến handler bên trong các entry thỏa một điều kiện tùy ý trên
+// cấp độ, context và field (VD: chỉ entry của tenant=acme đến một file riêng). Khác
+// RecordFilterHandler, điều kiện là một hàm Go nên không giới hạn ở cú pháp của RecordRule.
+type ConditionalHandler struct {
+	handler   Handler
+	predicate func(Entry) bool
+}
+
+// When tạo handler chỉ chuyển đến h các entry mà predicate trả về true.
+//
+// Predicate nhận bản sao của entry nhưng slice Fields vẫn dùng chung với các handler khác nên
+// không được sửa. Predicate được gọi trên goroutine ghi log nên phải nhanh và an toàn khi dùng
+// đồng thời.
+//
+// Tham số:
+//   - predicate: func(Entry) bool - điều kiện chọn entry, nil để chọn mọi entry
+//   - h: Handler - handler nhận các entry thỏa điều kiện
+//
+// Trả về:
+//   - *ConditionalHandler: handler đã được bọc
+//
+// Ví dụ:
+//
+//	acme := handler.When(func(e handler.Entry) bool {
+//	    tenant, ok := e.Field("tenant")
+//	    return ok && tenant.Str == "acme"
+//	}, acmeFileHandler)
+//	stack := handler.NewStackHandler(fileHandler, acme)
+func When(predicate func(Entry) bool, h Handler) *ConditionalHandler {
+	return &ConditionalHandler{handler: h, predicate: predicate}
+}
+
+// Log chuyển thông điệp đến handler bên trong nếu thỏa điều kiện. Thông điệp không có field có
+// cấu trúc nên predicate nhận entry không có field.
+//
+// Tham số:
+//   - level: Level - cấp độ của log entry
+//   - message: string - thông điệp log
+//   - args: ...interface{} - tham số định dạng tùy chọn
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (c *ConditionalHandler) Log(level Level, message string, args ...interface{}) error {
+	if c.predicate != nil {
+		formatted := message
+		if len(args) > 0 {
+			formatted = fmt.Sprintf(message, args...)
+		}
+		if !c.predicate(Entry{Time: time.Now(), Level: level, Message: formatted}) {
+			return nil
+		}
+	}
+	return c.handler.Log(level, message, args...)
+}
+
+// LogEntry chuyển entry đến handler bên trong nếu thỏa điều kiện.
+//
+// Tham số:
+//   - entry: *Entry - log entry cần xử lý
+//
+// Trả về:
+//   - error: lỗi của handler bên trong
+func (c *ConditionalHandler) LogEntry(entry *Entry) error {
+	if c.predicate != nil && !c.predicate(*entry) {
+		return nil
+	}
+	return Dispatch(c.handler, entry)
+}
+
+// Unwrap trả về handler bên trong.
+//
+// Trả về:
+//   - Handler: handler được bọc
+func (c *ConditionalHandler) Unwrap() Handler {
+	return c.handler
+}
+
+// Close đóng handler bên trong.
+//
+// Trả về:
+//   - error: lỗi khi đóng handler bên trong
+func (c *ConditionalHandler) Close() error {
+	return c.handler.Close()
+}
@@ -0,0 +1,67 @@
+package handler
+
+import "testing"
+
+func TestWhen(t *testing.T) {
+	inner := &MockTestHandler{}
+	acme := When(func(e Entry) bool {
+		tenant, ok := e.Field("tenant")
+		return ok && tenant.Str == "acme" && e.Context() == "Billing"
+	}, inner)
+
+	acme.LogEntry(&Entry{Level: InfoLevel, Message: "[Billing] other", Fields: []Field{{Key: "tenant", Type: StringType, Str: "globex"}}})
+	if inner.LogCalled {
+		t.Error("Entry không thỏa điều kiện không nên đến handler bên trong")
+	}
+
+	acme.LogEntry(&Entry{Level: InfoLevel, Message: "[Billing] invoice", Fields: []Field{{Key: "tenant", Type: StringType, Str: "acme"}}})
+	if inner.LogMessage != "[Billing] invoice" {
+		t.Errorf("Entry thỏa điều kiện nên đến handler bên trong, got %q", inner.LogMessage)
+	}
+
+	if acme.Unwrap() != inner {
+		t.Error("Unwrap() nên trả về handler bên trong")
+	}
+	if err := acme.Close(); err != nil || !inner.CloseCalled {
+		t.Errorf("Close() nên đóng handler bên trong, got %v", err)
+	}
+}
+
+func TestWhen_Log(t *testing.T) {
+	inner := &MockTestHandler{}
+	severe := When(func(e Entry) bool { return e.Level >= ErrorLevel }, inner)
+
+	severe.Log(InfoLevel, "skipped %d", 1)
+	if inner.LogCalled {
+		t.Error("Log() dưới điều kiện không nên đến handler bên trong")
+	}
+	severe.Log(ErrorLevel, "failed %d", 2)
+	if inner.LogMessage != "failed %d" || len(inner.LogArgs) != 1 {
+		t.Errorf("Log() nên chuyển nguyên thông điệp và tham số, got %q %v", inner.LogMessage, inner.LogArgs)
+	}
+
+	all := &MockTestHandler{}
+	When(nil, all).Log(DebugLevel, "any")
+	if !all.LogCalled {
+		t.Error("Predicate nil nên chọn mọi entry")
+	}
+}
+
+func TestEntry_ContextAndField(t *testing.T) {
+	entry := &Entry{Message: "[API] request", Fields: []Field{{Key: "status", Type: Int64Type, Integer: 200}}}
+	if entry.Context() != "API" {
+		t.Errorf("Context() = %q, want API", entry.Context())
+	}
+	if f, ok := entry.Field("status"); !ok || f.Integer != 200 {
+		t.Errorf("Field(status) = %v, %v", f, ok)
+	}
+	if _, ok := entry.Field("missing"); ok {
+		t.Error("Field() với key không tồn tại nên trả về false")
+	}
+	if (&Entry{Message: "plain"}).Context() != "" {
+		t.Error("Context() của thông điệp không có context nên rỗng")
+	}
+	if (&Entry{Message: "[draft] saved", Text: "[draft] saved"}).Context() != "" {
+		t.Error("Context() của thông điệp gốc bắt đầu bằng \"[...]\" nên rỗng khi logger không có context")
+	}
+}
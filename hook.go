@@ -0,0 +1,114 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go.fork.vn/log/handler"
+)
+
+// Entry là log entry được truyền cho các Hook (xem handler.Entry).
+type Entry = handler.Entry
+
+// ErrDropEntry được Hook trả về để bỏ log entry mà không báo lỗi.
+var ErrDropEntry = errors.New("log entry dropped by hook")
+
+// Hook xử lý một log entry trước khi entry được gửi đến các handler.
+//
+// Hook nhận entry với Message đã được định dạng nhưng chưa gắn context và field, nên có thể
+// bổ sung hoặc sửa Fields, Message và Level (VD: thêm field chung, che dữ liệu nhạy cảm,
+// đếm số entry theo cấp độ). Trả về ErrDropEntry để bỏ entry; các lỗi khác được ghi ra stderr
+// và entry vẫn được ghi, để một hook bị lỗi không làm mất log. Hook được gọi đồng thời từ
+// nhiều goroutine nên phải thread-safe.
+type Hook func(entry *Entry) error
+
+// AddHook đăng ký một hook chạy trước khi mọi log entry của các logger do manager tạo được
+// gửi đến handler.
+//
+// Các hook chạy theo thứ tự đăng ký và áp dụng cho cả logger đã tồn tại. Method này là
+// thread-safe.
+//
+// Tham số:
+//   - hook: Hook - hàm xử lý entry
+//
+// Ví dụ:
+//
+//	// Gắn field chung cho mọi entry
+//	manager.AddHook(func(e *log.Entry) error {
+//	    e.Fields = append(e.Fields, log.String("region", "ap-southeast-1"))
+//	    return nil
+//	})
+//
+//	// Bỏ các entry health check
+//	manager.AddHook(func(e *log.Entry) error {
+//	    if strings.HasPrefix(e.Message, "GET /health") {
+//	        return log.ErrDropEntry
+//	    }
+//	    return nil
+//	})
+func (m *manager) AddHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Tạo slice mới để các logger đang giữ danh sách cũ không bị ảnh hưởng
+	m.hooks = append(m.hooks[:len(m.hooks):len(m.hooks)], hook)
+	for _, lg := range m.loggers {
+		if l, ok := lg.(*logger); ok {
+			l.setHooks(m.loggerHooks())
+		}
+	}
+}
+
+// WithHooks thêm các hook chạy trước khi log entry được gửi đến handler.
+//
+// Tham số:
+//   - hooks: ...Hook - các hook theo thứ tự chạy
+//
+// Trả về:
+//   - LoggerOption: tùy chọn thêm hook
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Worker", log.WithHooks(countEntries))
+func WithHooks(hooks ...Hook) LoggerOption {
+	return func(l *logger) {
+		for _, hook := range hooks {
+			if hook != nil {
+				l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], hook)
+			}
+		}
+	}
+}
+
+// setHooks thay thế danh sách hook của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - hooks: []Hook - danh sách hook mới, không được sửa sau khi truyền vào
+func (l *logger) setHooks(hooks []Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = hooks
+	l.publish()
+}
+
+// runHooks chạy các hook trên entry.
+//
+// Trả về:
+//   - bool: false nếu một hook đã bỏ entry
+func runHooks(hooks []Hook, entry *Entry) bool {
+	for _, hook := range hooks {
+		if err := hook(entry); err != nil {
+			if errors.Is(err, ErrDropEntry) {
+				return false
+			}
+			fmt.Fprintf(os.Stderr, "Lỗi khi chạy log hook: %v\n", err)
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestManager_AddHook(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/hook.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	existing := m.GetLogger("Payment")
+	h := &entryHandler{}
+	existing.AddHandler(TestHandlerType, h)
+
+	var count atomic.Int64
+	m.AddHook(func(e *Entry) error {
+		count.Add(1)
+		e.Fields = append(e.Fields, String("region", "hn"))
+		return nil
+	})
+	m.AddHook(func(e *Entry) error {
+		if strings.HasPrefix(e.Message, "GET /health") {
+			return ErrDropEntry
+		}
+		return nil
+	})
+
+	existing.Info("charge %d created", 7)
+	if h.entry == nil || h.entry.Message != "[Payment] charge 7 created region=hn" {
+		t.Fatalf("Hook nên bổ sung field cho logger đã tồn tại, got %v", h.entry)
+	}
+	if len(h.entry.Fields) != 1 || h.entry.Fields[0].Key != "region" {
+		t.Errorf("Entry gửi đến handler nên chứa field do hook thêm, got %v", h.entry.Fields)
+	}
+
+	h.entry = nil
+	existing.Info("GET /health")
+	if h.entry != nil {
+		t.Errorf("Entry bị hook bỏ không nên được gửi đến handler, got %v", h.entry)
+	}
+
+	created := m.GetLogger("Order")
+	rec := &entryHandler{}
+	created.AddHandler(TestHandlerType, rec)
+	created.LogFields(handler.InfoLevel, "order placed")
+	if rec.entry == nil || !strings.HasSuffix(rec.entry.Message, "region=hn") {
+		t.Errorf("Logger tạo sau AddHook cũng nên chạy hook, got %v", rec.entry)
+	}
+	if count.Load() != 3 {
+		t.Errorf("Hook nên chạy cho mỗi entry, got %d", count.Load())
+	}
+}
+
+func TestWithHooks_MutateAndFailOpen(t *testing.T) {
+	l := NewLogger("Worker", WithHooks(
+		func(e *Entry) error {
+			e.Level = handler.ErrorLevel
+			e.Message = strings.ToUpper(e.Message)
+			return nil
+		},
+		func(e *Entry) error {
+			return errors.New("metrics unavailable")
+		},
+	))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("job failed")
+	if h.entry == nil {
+		t.Fatal("Hook trả về lỗi khác ErrDropEntry không nên bỏ entry")
+	}
+	if h.entry.Level != handler.ErrorLevel || h.entry.Message != "[Worker] JOB FAILED" {
+		t.Errorf("Hook nên có thể sửa cấp độ và thông điệp, got %v %q", h.entry.Level, h.entry.Message)
+	}
+}
+
+func TestWithHooks_MutateDoesNotAliasCallerFields(t *testing.T) {
+	l := NewLogger("Auth", WithHooks(func(e *Entry) error {
+		for i := range e.Fields {
+			if e.Fields[i].Key == "password" {
+				e.Fields[i] = String("password", "***")
+			}
+		}
+		return nil
+	}))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	fields := []Field{String("user", "alice"), String("password", "secret")}
+	l.LogFields(handler.InfoLevel, "login", fields...)
+	if fields[1].Str != "secret" {
+		t.Errorf("Hook không nên sửa slice field của bên gọi, got %q", fields[1].Str)
+	}
+	if h.entry == nil || h.entry.Fields[1].Str != "***" {
+		t.Error("Handler nên nhận field đã được hook sửa")
+	}
+}
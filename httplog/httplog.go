@@ -0,0 +1,308 @@
+// Package httplog cung cấp http.RoundTripper ghi log các request HTTP gửi đi qua log.Logger.
+//
+// Mỗi request được ghi kèm method, URL, status, thời gian thực thi và lỗi (nếu có). Số lần
+// thử lại được ghi khi context của request được đánh dấu bằng TrackRetries. Header và body
+// có thể được ghi kèm cho một tỷ lệ request qua WithHeaders và WithBodySampling.
+//
+// Ví dụ:
+//
+//	client := &http.Client{
+//	    Transport: httplog.NewTransport(http.DefaultTransport, manager.GetLogger("HTTPClient")),
+//	}
+package httplog
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// Các field được ghi cho mỗi request.
+const (
+	FieldMethod       = "method"        // HTTP method của request
+	FieldURL          = "url"           // URL của request (đã ẩn thông tin đăng nhập)
+	FieldStatus       = "status"        // Status code của response
+	FieldDuration     = "duration"      // Thời gian từ khi gửi request đến khi nhận header của response
+	FieldAttempt      = "attempt"       // Lần thử của request (bắt đầu từ 1), chỉ có khi dùng TrackRetries
+	FieldError        = "error"         // Lỗi trả về bởi transport
+	FieldRequestBody  = "request_body"  // Mẫu body của request
+	FieldResponseBody = "response_body" // Mẫu body của response
+
+	// HeaderFieldPrefix là tiền tố của field header request (VD: request_header.Content-Type)
+	HeaderFieldPrefix = "request_header."
+
+	// ResponseHeaderFieldPrefix là tiền tố của field header response (VD: response_header.Retry-After)
+	ResponseHeaderFieldPrefix = "response_header."
+)
+
+// Option cấu hình Transport.
+type Option func(*Transport)
+
+// WithHeaders ghi kèm giá trị của các header được chỉ định từ request và response.
+//
+// Chỉ các header được liệt kê mới được ghi; không nên liệt kê các header nhạy cảm
+// như Authorization hoặc Cookie.
+//
+// Tham số:
+//   - names: ...string - tên các header cần ghi
+//
+// Trả về:
+//   - Option: option cấu hình Transport
+func WithHeaders(names ...string) Option {
+	return func(t *Transport) {
+		t.headers = append(t.headers, names...)
+	}
+}
+
+// WithBodySampling ghi kèm tối đa maxBytes đầu tiên của body request và response
+// cho một tỷ lệ request được lấy mẫu.
+//
+// Body của request chỉ được đọc qua Request.GetBody nên không bị tiêu thụ. Body của
+// response không được đọc trước: mẫu được lấy trong lúc người gọi đọc body và log của
+// request được ghi khi body được đọc hết hoặc được đóng. Response 101 Switching Protocols
+// và text/event-stream không được lấy mẫu body và được ghi log ngay.
+//
+// Tham số:
+//   - rate: float64 - tỷ lệ request được lấy mẫu, từ 0 (không lấy) đến 1 (mọi request)
+//   - maxBytes: int - số byte tối đa được ghi cho mỗi body
+//
+// Trả về:
+//   - Option: option cấu hình Transport
+func WithBodySampling(rate float64, maxBytes int) Option {
+	return func(t *Transport) {
+		t.sampleRate = rate
+		t.sampleBytes = maxBytes
+	}
+}
+
+// WithLevel thiết lập cấp độ log cho các request thành công (mặc định InfoLevel).
+// Response 4xx được ghi ở WarningLevel, response 5xx và lỗi transport ở ErrorLevel.
+//
+// Tham số:
+//   - level: handler.Level - cấp độ log cho request thành công
+//
+// Trả về:
+//   - Option: option cấu hình Transport
+func WithLevel(level handler.Level) Option {
+	return func(t *Transport) {
+		t.level = level
+	}
+}
+
+// retryCounterKey là key context của bộ đếm số lần thử.
+type retryCounterKey struct{}
+
+// TrackRetries đánh dấu context để Transport đếm số lần request được gửi với context này.
+//
+// Các vòng lặp thử lại gửi lại request với cùng context sẽ được ghi field attempt
+// tăng dần, giúp phân biệt lần gửi đầu tiên với các lần thử lại.
+//
+// Tham số:
+//   - ctx: context.Context - context của request
+//
+// Trả về:
+//   - context.Context: context đã được đánh dấu
+//
+// Ví dụ:
+//
+//	ctx := httplog.TrackRetries(ctx)
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	for attempt := 0; attempt < 3; attempt++ {
+//	    if resp, err = client.Do(req); err == nil {
+//	        break
+//	    }
+//	}
+func TrackRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryCounterKey{}, new(atomic.Int32))
+}
+
+// Transport là http.RoundTripper ghi log mọi request gửi đi.
+type Transport struct {
+	next        http.RoundTripper
+	logger      log.Logger
+	level       handler.Level
+	headers     []string
+	sampleRate  float64
+	sampleBytes int
+}
+
+// NewTransport tạo Transport bọc rt và ghi log qua logger.
+//
+// Tham số:
+//   - rt: http.RoundTripper - transport gốc (nil để dùng http.DefaultTransport)
+//   - logger: log.Logger - logger dùng để ghi log request
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - *Transport: transport đã được cấu hình
+//
+// Ví dụ:
+//
+//	transport := httplog.NewTransport(nil, logger,
+//	    httplog.WithHeaders("Content-Type", "Retry-After"),
+//	    httplog.WithBodySampling(0.01, 512),
+//	)
+func NewTransport(rt http.RoundTripper, logger log.Logger, opts ...Option) *Transport {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	t := &Transport{
+		next:   rt,
+		logger: logger,
+		level:  handler.InfoLevel,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip gửi request qua transport gốc và ghi log kết quả.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields := []interface{}{
+		log.Any(FieldMethod, req.Method),
+		log.Any(FieldURL, req.URL.Redacted()),
+	}
+	if counter, ok := req.Context().Value(retryCounterKey{}).(*atomic.Int32); ok {
+		fields = append(fields, log.Any(FieldAttempt, counter.Add(1)))
+	}
+	for _, name := range t.headers {
+		if v := req.Header.Get(name); v != "" {
+			fields = append(fields, log.Any(HeaderFieldPrefix+name, v))
+		}
+	}
+
+	sampled := t.sampleBytes > 0 && t.sampleRate > 0 && rand.Float64() < t.sampleRate
+	if sampled {
+		if body, ok := sampleRequestBody(req, t.sampleBytes); ok {
+			fields = append(fields, log.Any(FieldRequestBody, body))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		fields = append(fields, log.Any(FieldDuration, duration), log.Any(FieldError, err))
+		t.logger.LogAt(time.Time{}, handler.ErrorLevel, "HTTP client request", fields...)
+		return resp, err
+	}
+
+	fields = append(fields, log.Any(FieldStatus, resp.StatusCode), log.Any(FieldDuration, duration))
+	for _, name := range t.headers {
+		if v := resp.Header.Get(name); v != "" {
+			fields = append(fields, log.Any(ResponseHeaderFieldPrefix+name, v))
+		}
+	}
+
+	level := t.level
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		level = handler.ErrorLevel
+	case resp.StatusCode >= http.StatusBadRequest:
+		level = handler.WarningLevel
+	}
+
+	if sampled && sampleable(resp) {
+		resp.Body = &sampledBody{
+			body:     resp.Body,
+			maxBytes: t.sampleBytes,
+			emit: func(body string) {
+				t.logger.LogAt(time.Time{}, level, "HTTP client request", append(fields, log.Any(FieldResponseBody, body))...)
+			},
+		}
+		return resp, nil
+	}
+	t.logger.LogAt(time.Time{}, level, "HTTP client request", fields...)
+
+	return resp, nil
+}
+
+// sampleRequestBody đọc tối đa maxBytes của body request qua GetBody mà không tiêu thụ body gốc.
+func sampleRequestBody(req *http.Request, maxBytes int) (string, bool) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return "", false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	buf, err := io.ReadAll(io.LimitReader(body, int64(maxBytes)))
+	if err != nil {
+		return "", false
+	}
+	return string(buf), true
+}
+
+// sampleable cho biết body của response có được lấy mẫu không.
+//
+// Body của response 101 Switching Protocols là kết nối hai chiều (io.ReadWriteCloser) và body
+// text/event-stream không kết thúc, nên cả hai được giữ nguyên.
+func sampleable(resp *http.Response) bool {
+	if resp.Body == nil || resp.Body == http.NoBody || resp.StatusCode == http.StatusSwitchingProtocols {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType != "text/event-stream"
+}
+
+// sampledBody giữ lại tối đa maxBytes đầu tiên trong lúc người gọi đọc body response và
+// gọi emit với phần đã giữ khi body được đọc hết hoặc được đóng (chỉ một lần).
+type sampledBody struct {
+	body     io.ReadCloser
+	maxBytes int
+	emit     func(body string)
+
+	mu      sync.Mutex
+	sample  []byte
+	emitted bool
+}
+
+// Read đọc từ body gốc và giữ lại phần đầu của dữ liệu đã đọc.
+func (b *sampledBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.mu.Lock()
+	if room := b.maxBytes - len(b.sample); room > 0 && n > 0 {
+		b.sample = append(b.sample, p[:min(n, room)]...)
+	}
+	b.mu.Unlock()
+	if err == io.EOF {
+		b.flush()
+	}
+	return n, err
+}
+
+// Close đóng body gốc và ghi log nếu chưa được ghi.
+func (b *sampledBody) Close() error {
+	err := b.body.Close()
+	b.flush()
+	return err
+}
+
+// flush gọi emit với phần body đã giữ nếu chưa gọi.
+func (b *sampledBody) flush() {
+	b.mu.Lock()
+	if b.emitted {
+		b.mu.Unlock()
+		return
+	}
+	b.emitted = true
+	sample := string(b.sample)
+	b.mu.Unlock()
+	b.emit(sample)
+}
@@ -0,0 +1,201 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Service là một thành phần chạy nền (VD: watcher cấu hình, shipper, metric reporter) có vòng
+// đời do Manager quản lý.
+//
+// Start được gọi khi Manager.Start được gọi (hoặc ngay khi đăng ký nếu manager đang chạy) và
+// phải trả về sau khi service đã khởi động; ctx chỉ giới hạn thời gian khởi động. Stop phải
+// dừng tất cả goroutine của service trước khi trả về, hoặc trả về lỗi khi ctx hết hạn.
+type Service interface {
+	// Start khởi động service.
+	//
+	// Tham số:
+	//   - ctx: context.Context - giới hạn thời gian khởi động
+	//
+	// Trả về:
+	//   - error: lỗi nếu không thể khởi động service
+	Start(ctx context.Context) error
+
+	// Stop dừng service và chờ các goroutine của service kết thúc.
+	//
+	// Tham số:
+	//   - ctx: context.Context - giới hạn thời gian chờ
+	//
+	// Trả về:
+	//   - error: lỗi khi dừng service hoặc khi ctx hết hạn
+	Stop(ctx context.Context) error
+}
+
+// namedService là một service đã đăng ký cùng tên của nó.
+type namedService struct {
+	name    string
+	service Service
+}
+
+// AddService đăng ký một service chạy nền với manager.
+//
+// Service được khởi động bởi Start, hoặc ngay lập tức nếu manager đang chạy, và được dừng
+// theo thứ tự ngược với thứ tự đăng ký bởi Stop hoặc Close. Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên duy nhất của service
+//   - service: Service - service cần đăng ký
+//
+// Trả về:
+//   - error: lỗi nếu tên đã được đăng ký hoặc không thể khởi động service khi manager đang chạy
+//
+// Ví dụ:
+//
+//	if err := manager.AddService("shipper", shipper); err != nil {
+//	    return err
+//	}
+func (m *manager) AddService(name string, service Service) error {
+	if service == nil {
+		return errors.New("service cannot be nil")
+	}
+
+	m.mu.Lock()
+	for _, s := range m.services {
+		if s.name == name {
+			m.mu.Unlock()
+			return fmt.Errorf("service %s already registered", name)
+		}
+	}
+	m.services = append(m.services, namedService{name: name, service: service})
+	running := m.running
+	m.mu.Unlock()
+
+	if !running {
+		return nil
+	}
+	if err := service.Start(context.Background()); err != nil {
+		m.removeService(name)
+		return fmt.Errorf("failed to start service %s: %w", name, err)
+	}
+	return nil
+}
+
+// Start khởi động tất cả service đã đăng ký theo thứ tự đăng ký.
+//
+// Nếu một service không thể khởi động, các service đã khởi động trước đó được dừng lại và
+// manager trở về trạng thái chưa chạy. Gọi Start khi manager đang chạy không có tác dụng.
+// Method này là thread-safe.
+//
+// Tham số:
+//   - ctx: context.Context - giới hạn thời gian khởi động của các service
+//
+// Trả về:
+//   - error: lỗi của service đầu tiên không thể khởi động
+//
+// Ví dụ:
+//
+//	if err := manager.Start(ctx); err != nil {
+//	    return err
+//	}
+//	defer manager.Stop(context.Background())
+func (m *manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.running = true
+	services := append([]namedService(nil), m.services...)
+	m.mu.Unlock()
+
+	for i, s := range services {
+		if err := s.service.Start(ctx); err != nil {
+			stopServices(ctx, services[:i])
+			m.mu.Lock()
+			m.running = false
+			m.mu.Unlock()
+			return fmt.Errorf("failed to start service %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// Stop dừng tất cả thành phần chạy nền của manager rồi đóng các handler.
+//
+// Thứ tự dừng: các service (ngược thứ tự đăng ký), các lần nâng cấp độ log đang chờ khôi
+// phục, rồi các handler (bao gồm worker của handler bất đồng bộ, sau khi ghi hết hàng đợi).
+// Khi Stop trả về nil, không còn goroutine nào do manager hoặc handler của nó tạo ra, nên
+// có thể kiểm tra bằng goleak trong test. Nếu ctx hết hạn trước, Stop trả về lỗi bọc
+// ctx.Err() và các thành phần chưa dừng tiếp tục dừng ở nền. Manager có thể được dùng lại
+// sau Stop. Method này là thread-safe.
+//
+// Tham số:
+//   - ctx: context.Context - giới hạn thời gian chờ các thành phần dừng
+//
+// Trả về:
+//   - error: lỗi đầu tiên khi dừng service hoặc đóng handler, hoặc lỗi khi ctx hết hạn
+//
+// Ví dụ:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := manager.Stop(ctx); err != nil {
+//	    fmt.Fprintf(os.Stderr, "Lỗi khi dừng logging: %v\n", err)
+//	}
+func (m *manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	running := m.running
+	m.running = false
+	services := append([]namedService(nil), m.services...)
+	m.mu.Unlock()
+
+	var firstErr error
+	if running {
+		firstErr = stopServices(ctx, services)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := m.closeHandlers()
+		// Chờ các callback khôi phục cấp độ đang chạy dở
+		m.timers.Wait()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if firstErr == nil {
+			firstErr = err
+		}
+	case <-ctx.Done():
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to stop background components: %w", ctx.Err())
+		}
+	}
+	return firstErr
+}
+
+// removeService hủy đăng ký service có tên đã cho.
+func (m *manager) removeService(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, s := range m.services {
+		if s.name == name {
+			m.services = append(m.services[:i], m.services[i+1:]...)
+			return
+		}
+	}
+}
+
+// stopServices dừng các service theo thứ tự ngược và trả về lỗi đầu tiên.
+func stopServices(ctx context.Context, services []namedService) error {
+	var firstErr error
+	for i := len(services) - 1; i >= 0; i-- {
+		if err := services[i].service.Stop(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to stop service %s: %w", services[i].name, err)
+		}
+	}
+	return firstErr
+}
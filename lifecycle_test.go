@@ -0,0 +1,157 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingService ghi lại thứ tự Start/Stop vào events dùng chung.
+type recordingService struct {
+	name     string
+	events   *[]string
+	mu       *sync.Mutex
+	startErr error
+}
+
+func (s *recordingService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, "start "+s.name)
+	return s.startErr
+}
+
+func (s *recordingService) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, "stop "+s.name)
+	return nil
+}
+
+// blockingHandler chặn Close cho đến khi release được đóng.
+type blockingHandler struct {
+	MockHandler
+	release chan struct{}
+}
+
+func (b *blockingHandler) Close() error {
+	<-b.release
+	return nil
+}
+
+// waitGoroutines chờ số goroutine trở về tối đa baseline.
+func waitGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("Còn %d goroutine sau khi dừng (baseline %d):\n%s",
+				runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_Services(t *testing.T) {
+	m := NewManager(createTestConfig())
+	var events []string
+	mu := &sync.Mutex{}
+	newService := func(name string) *recordingService {
+		return &recordingService{name: name, events: &events, mu: mu}
+	}
+
+	if err := m.AddService("watcher", newService("watcher")); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := m.AddService("watcher", newService("watcher")); err == nil {
+		t.Error("AddService() nên từ chối tên đã đăng ký")
+	}
+	if err := m.AddService("shipper", newService("shipper")); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Service không nên được khởi động trước Start, got %v", events)
+	}
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() lần hai error = %v", err)
+	}
+	if err := m.AddService("reporter", newService("reporter")); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := "start watcher,start shipper,start reporter,stop reporter,stop shipper,stop watcher"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("Thứ tự vòng đời = %q, want %q", got, want)
+	}
+}
+
+func TestManager_Start_RollsBackOnError(t *testing.T) {
+	m := NewManager(createTestConfig())
+	defer m.Close()
+	var events []string
+	mu := &sync.Mutex{}
+	failure := errors.New("port in use")
+
+	_ = m.AddService("watcher", &recordingService{name: "watcher", events: &events, mu: mu})
+	_ = m.AddService("reporter", &recordingService{name: "reporter", events: &events, mu: mu, startErr: failure})
+
+	err := m.Start(context.Background())
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "reporter") {
+		t.Fatalf("Start() nên trả về lỗi của service reporter, got %v", err)
+	}
+	want := "start watcher,start reporter,stop watcher"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("Service đã khởi động nên được dừng khi Start thất bại, got %q, want %q", got, want)
+	}
+}
+
+func TestManager_Close_NoGoroutineLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	config := createTestConfig()
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.Async = map[string]AsyncConfig{"file": {Workers: 4, QueueSize: 100}}
+	m := NewManager(config)
+	l := m.GetLogger("Worker")
+	for i := 0; i < 100; i++ {
+		l.Info("job %d", i)
+	}
+	if err := m.ElevateLevel("Worker", 0, time.Hour); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	if err := m.ElevateLevel("Payment", 0, time.Millisecond); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	waitGoroutines(t, baseline)
+}
+
+func TestManager_Stop_Timeout(t *testing.T) {
+	m := NewManager(createTestConfig())
+	blocking := &blockingHandler{release: make(chan struct{})}
+	m.AddHandler("remote", blocking)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := m.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() nên trả về lỗi khi ctx hết hạn, got %v", err)
+	}
+	close(blocking.release)
+}
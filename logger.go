@@ -1,8 +1,13 @@
 package log
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.fork.vn/log/handler"
 )
@@ -50,6 +55,130 @@ type Logger interface {
 	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
 	Fatal(message string, args ...interface{})
 
+	// Debugf ghi một thông điệp ở cấp độ debug theo ngữ nghĩa printf: mọi tham số, kể cả Field,
+	// đều được dùng để định dạng thông điệp.
+	//
+	// Tham số:
+	//   - format: string - chuỗi định dạng
+	//   - args: ...interface{} - các tham số định dạng
+	Debugf(format string, args ...interface{})
+
+	// Infof ghi một thông điệp ở cấp độ info theo ngữ nghĩa printf: mọi tham số, kể cả Field,
+	// đều được dùng để định dạng thông điệp.
+	//
+	// Tham số:
+	//   - format: string - chuỗi định dạng
+	//   - args: ...interface{} - các tham số định dạng
+	Infof(format string, args ...interface{})
+
+	// Warningf ghi một thông điệp ở cấp độ warning theo ngữ nghĩa printf: mọi tham số, kể cả Field,
+	// đều được dùng để định dạng thông điệp.
+	//
+	// Tham số:
+	//   - format: string - chuỗi định dạng
+	//   - args: ...interface{} - các tham số định dạng
+	Warningf(format string, args ...interface{})
+
+	// Errorf ghi một thông điệp ở cấp độ error theo ngữ nghĩa printf: mọi tham số, kể cả Field,
+	// đều được dùng để định dạng thông điệp.
+	//
+	// Tham số:
+	//   - format: string - chuỗi định dạng
+	//   - args: ...interface{} - các tham số định dạng
+	Errorf(format string, args ...interface{})
+
+	// Fatalf ghi một thông điệp ở cấp độ fatal theo ngữ nghĩa printf: mọi tham số, kể cả Field,
+	// đều được dùng để định dạng thông điệp.
+	//
+	// Tham số:
+	//   - format: string - chuỗi định dạng
+	//   - args: ...interface{} - các tham số định dạng
+	Fatalf(format string, args ...interface{})
+
+	// DebugContext ghi một thông điệp ở cấp độ debug kèm các field lấy từ ctx.
+	//
+	// Tham số:
+	//   - ctx: context.Context - context của yêu cầu
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	DebugContext(ctx context.Context, message string, args ...interface{})
+
+	// InfoContext ghi một thông điệp ở cấp độ info kèm các field lấy từ ctx.
+	//
+	// Tham số:
+	//   - ctx: context.Context - context của yêu cầu
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	InfoContext(ctx context.Context, message string, args ...interface{})
+
+	// WarningContext ghi một thông điệp ở cấp độ warning kèm các field lấy từ ctx.
+	//
+	// Tham số:
+	//   - ctx: context.Context - context của yêu cầu
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	WarningContext(ctx context.Context, message string, args ...interface{})
+
+	// ErrorContext ghi một thông điệp ở cấp độ error kèm các field lấy từ ctx.
+	//
+	// Tham số:
+	//   - ctx: context.Context - context của yêu cầu
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	ErrorContext(ctx context.Context, message string, args ...interface{})
+
+	// FatalContext ghi một thông điệp ở cấp độ fatal kèm các field lấy từ ctx.
+	//
+	// Tham số:
+	//   - ctx: context.Context - context của yêu cầu
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	FatalContext(ctx context.Context, message string, args ...interface{})
+
+	// LogAt ghi một thông điệp ở cấp độ chỉ định với thời điểm do bên gọi cung cấp.
+	//
+	// Tham số:
+	//   - t: time.Time - thời điểm phát sinh của log entry (zero value để dùng thời điểm hiện tại)
+	//   - level: handler.Level - cấp độ log của thông điệp
+	//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+	//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+	LogAt(t time.Time, level handler.Level, message string, args ...interface{})
+
+	// LogFields ghi một thông điệp ở cấp độ chỉ định chỉ với các field có cấu trúc.
+	//
+	// Thông điệp không được định dạng bằng Sprintf và các field không bị đóng gói vào
+	// interface{}, nên đây là đường ghi nhanh cho các hot path.
+	//
+	// Tham số:
+	//   - level: handler.Level - cấp độ log của thông điệp
+	//   - message: string - thông điệp log (không phải chuỗi định dạng)
+	//   - fields: ...Field - các field có cấu trúc (VD: log.String, log.Int64, log.Duration)
+	LogFields(level handler.Level, message string, fields ...Field)
+
+	// Once trả về một Logger chỉ ghi mỗi thông điệp một lần.
+	//
+	// Trả về:
+	//   - Logger: logger chỉ ghi lần đầu tiên của mỗi thông điệp
+	Once() Logger
+
+	// EveryN trả về một Logger ghi lần đầu tiên và mỗi lần thứ n của cùng một thông điệp.
+	//
+	// Tham số:
+	//   - n: int - chu kỳ ghi
+	//
+	// Trả về:
+	//   - Logger: logger ghi mỗi lần thứ n, kèm field suppressed
+	EveryN(n int) Logger
+
+	// Dedup trả về một Logger ghi mỗi thông điệp tối đa một lần trong mỗi khoảng thời gian.
+	//
+	// Tham số:
+	//   - window: time.Duration - khoảng thời gian chặn thông điệp lặp lại
+	//
+	// Trả về:
+	//   - Logger: logger loại bỏ thông điệp lặp lại, kèm field suppressed
+	Dedup(window time.Duration) Logger
+
 	// AddHandler đăng ký một handler mới vào logger.
 	//
 	// Tham số:
@@ -59,6 +188,9 @@ type Logger interface {
 
 	// RemoveHandler hủy đăng ký và đóng một handler.
 	//
+	// Lời gọi log bắt đầu sau khi RemoveHandler trả về không còn ghi đến handler; lời gọi đang
+	// chạy có thể vẫn ghi đến handler sau khi nó bị đóng.
+	//
 	// Tham số:
 	//   - handlerType: HandlerType - loại handler cần xóa
 	RemoveHandler(handlerType HandlerType)
@@ -74,6 +206,8 @@ type Logger interface {
 
 	// SetMinLevel thiết lập ngưỡng cấp độ log tối thiểu.
 	//
+	// Mọi lời gọi log bắt đầu sau khi SetMinLevel trả về (happens-before) thấy cấp độ mới.
+	//
 	// Tham số:
 	//   - level: handler.Level - cấp độ tối thiểu để log
 	SetMinLevel(level handler.Level)
@@ -92,16 +226,68 @@ type Logger interface {
 // trong môi trường đa goroutine.
 //
 // Tính năng:
-//   - Quản lý handler thread-safe bằng RWMutex
+//   - Quản lý handler thread-safe bằng RWMutex; đường ghi log đọc snapshot bất biến
+//     qua atomic.Value nên không cần lock và không sao chép map
+//   - Mọi thay đổi cấu hình (cấp độ, handler, giới hạn, caller...) happens-before các lời gọi
+//     log bắt đầu sau khi method thay đổi trả về; lời gọi đang chạy hoàn tất với snapshot cũ
 //   - Lọc cấp độ log
 //   - Thêm/xóa handler động
 //   - Dọn dẹp tài nguyên an toàn khi tắt
 //   - Context cố định để xác định nguồn gốc log (immutable sau khi tạo)
 type logger struct {
-	handlers map[HandlerType]handler.Handler // Map các handler theo loại
-	minLevel handler.Level                   // Ngưỡng cấp độ log tối thiểu
-	context  string                          // Context cố định để xác định nguồn gốc log (immutable)
-	mu       sync.RWMutex                    // Mutex để đảm bảo thread-safety
+	handlers      map[HandlerType]handler.Handler // Map các handler theo loại
+	minLevel      atomic.Int32                    // Ngưỡng cấp độ log tối thiểu, đọc không cần lock
+	context       string                          // Context cố định để xác định nguồn gốc log (immutable)
+	caller        bool                            // Ghi kèm vị trí gọi log
+	callerSkip    int                             // Số stack frame bổ sung bỏ qua khi xác định vị trí gọi
+	raw           bool                            // Ghi thông điệp và key của field không qua handler.Sanitize
+	limits        handler.Limits                  // Giới hạn độ sâu, số phần tử và số field khi ghi field
+	sampler       *handler.Sampler                // Sampler bỏ bớt log lặp lại (nil = không lấy mẫu)
+	hooks         []Hook                          // Các hook chạy trước khi gửi entry đến handler
+	retention     string                          // Lớp lưu trữ gắn vào mọi entry (rỗng = không gắn)
+	redactor      *handler.Redactor               // Che dữ liệu nhạy cảm trước khi gửi đến handler (nil = tắt)
+	unredacted    map[HandlerType]bool            // Các handler nhận entry chưa được che
+	pseudonymizer *handler.Pseudonymizer          // Thay field định danh bằng pseudonym (nil = tắt)
+	contextFields []ContextField                  // Các giá trị lấy từ context.Context thành field trong các method *Context
+	staticFields  []Field                         // Các field cố định gắn vào mọi entry, chỉ được thay thế
+	clock         Clock                           // Nguồn thời điểm của entry (nil = time.Now)
+	errors        *errorReporter                  // Xử lý lỗi ghi log của handler, không đổi sau khi tạo
+	metrics       *metricsReporter                // Observer nhận sự kiện ghi log, không đổi sau khi tạo
+	snapshot      atomic.Value                    // *loggerSnapshot được dựng lại sau mỗi thay đổi handlers hoặc limits
+	repeats       sync.Map                        // Bộ đếm của Once, EveryN và Dedup theo repeatKey
+	mu            sync.RWMutex                    // Mutex bảo vệ các thay đổi để đảm bảo thread-safety
+}
+
+// loggerSnapshot là bản chụp bất biến của handlers và limits mà đường ghi log đọc không cần lock.
+//
+// Mỗi thay đổi (dưới l.mu) tạo một snapshot mới thay vì sửa snapshot cũ (kiểu RCU), nên các
+// lời gọi log đang chạy vẫn dùng snapshot cũ một cách an toàn.
+type loggerSnapshot struct {
+	handlers      []namedHandler         // Các handler theo thứ tự tên, không chứa handler nil
+	limits        handler.Limits         // Giới hạn field tại thời điểm chụp
+	sampler       *handler.Sampler       // Sampler tại thời điểm chụp (nil = không lấy mẫu)
+	hooks         []Hook                 // Các hook tại thời điểm chụp
+	retention     string                 // Lớp lưu trữ tại thời điểm chụp
+	redactor      *handler.Redactor      // Redactor tại thời điểm chụp (nil = không che)
+	unredacted    map[HandlerType]bool   // Các handler nhận entry chưa được che, không được sửa
+	pseudonymizer *handler.Pseudonymizer // Pseudonymizer tại thời điểm chụp (nil = không thay)
+	contextFields []ContextField         // Các field lấy từ context tại thời điểm chụp
+	staticFields  []Field                // Các field cố định tại thời điểm chụp
+	clock         Clock                  // Nguồn thời điểm của entry tại thời điểm chụp (nil = time.Now)
+	caller        bool                   // Ghi kèm vị trí gọi log
+	callerSkip    int                    // Số stack frame bổ sung bỏ qua khi xác định vị trí gọi
+	raw           bool                   // Ghi thông điệp và key của field không qua handler.Sanitize
+}
+
+// sample kiểm tra entry có được sampler của snapshot giữ lại hay không.
+func (s *loggerSnapshot) sample(level handler.Level, context, message string) bool {
+	return s.sampler == nil || s.sampler.AllowContext(level, context, message)
+}
+
+// namedHandler là một handler kèm tên đăng ký trong logger.
+type namedHandler struct {
+	handlerType HandlerType
+	handler     handler.Handler
 }
 
 // NewLogger tạo và trả về một instance logger mới với context cố định.
@@ -111,6 +297,7 @@ type logger struct {
 //
 // Tham số:
 //   - context: string - context cố định để xác định nguồn gốc log (VD: UserService, UserController)
+//   - opts: ...LoggerOption - tùy chọn của logger (VD: WithCaller)
 //
 // Trả về:
 //   - Logger: một instance mới của logger triển khai interface Logger.
@@ -120,12 +307,21 @@ type logger struct {
 //	logger := log.NewLogger("UserService")
 //	logger.AddHandler("console", handler.NewConsoleHandler(true))
 //	// context "UserService" sẽ không thể thay đổi trong suốt vòng đời của logger
-func NewLogger(context string) Logger {
-	return &logger{
+func NewLogger(context string, opts ...LoggerOption) Logger {
+	l := &logger{
 		handlers: make(map[HandlerType]handler.Handler),
-		minLevel: handler.InfoLevel, // Mặc định là InfoLevel
-		context:  context,           // Thiết lập context từ tham số
+		context:  context, // Thiết lập context từ tham số
+		errors:   &errorReporter{},
+		metrics:  &metricsReporter{},
+	}
+	l.minLevel.Store(int32(handler.InfoLevel)) // Mặc định là InfoLevel
+	for _, opt := range opts {
+		if opt != nil {
+			opt(l)
+		}
 	}
+	l.publish()
+	return l
 }
 
 // Debug ghi một thông điệp ở cấp độ debug.
@@ -213,6 +409,74 @@ func (l *logger) Fatal(message string, args ...interface{}) {
 	l.log(handler.FatalLevel, message, args...)
 }
 
+// LogAt ghi một thông điệp ở cấp độ chỉ định với thời điểm do bên gọi cung cấp.
+//
+// LogAt hữu ích khi phát lại các sự kiện đã được đệm hoặc ghi log thay cho một tiến trình
+// xử lý theo lô bị trễ, khi thời điểm ghi không phản ánh đúng thời điểm phát sinh sự kiện.
+// Thời điểm chỉ được sử dụng bởi các handler triển khai handler.EntryHandler (console, file
+// và stack); các handler khác tự gán timestamp tại thời điểm ghi.
+//
+// Tham số:
+//   - t: time.Time - thời điểm phát sinh của log entry (zero value để dùng thời điểm hiện tại)
+//   - level: handler.Level - cấp độ log của thông điệp
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - các tham số tùy chọn để định dạng thông điệp
+//
+// Ví dụ:
+//
+//	for _, event := range bufferedEvents {
+//	    logger.LogAt(event.Time, handler.InfoLevel, "Order processed", log.Any("order_id", event.OrderID))
+//	}
+func (l *logger) LogAt(t time.Time, level handler.Level, message string, args ...interface{}) {
+	if level < l.getMinLevel() {
+		return
+	}
+	snapshot := l.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, l.context, message) {
+		return
+	}
+	if t.IsZero() {
+		t = snapshot.now()
+	}
+
+	l.write(snapshot, t, level, message, l.withCaller(args, 1)...)
+}
+
+// LogFields ghi một thông điệp ở cấp độ chỉ định chỉ với các field có cấu trúc.
+//
+// Khác với Info hay LogAt, thông điệp không đi qua fmt.Sprintf và các field không bị đóng
+// gói vào interface{}. Kết hợp với các field có kiểu (log.String, log.Int64, log.Duration...),
+// lời gọi bị lọc theo cấp độ chỉ cấp phát slice của tham số variadic, và lời gọi được ghi
+// chỉ cấp phát cho thông điệp và log entry thay vì cho từng giá trị field.
+//
+// Tham số:
+//   - level: handler.Level - cấp độ log của thông điệp
+//   - message: string - thông điệp log (không phải chuỗi định dạng)
+//   - fields: ...Field - các field có cấu trúc
+//
+// Ví dụ:
+//
+//	logger.LogFields(handler.InfoLevel, "Request completed",
+//	    log.String("method", r.Method),
+//	    log.Int("status", status),
+//	    log.Duration("latency", time.Since(start)),
+//	)
+//	// Output: [HTTP] Request completed method=GET status=200 latency=1.2ms
+func (l *logger) LogFields(level handler.Level, message string, fields ...Field) {
+	if level < l.getMinLevel() {
+		return
+	}
+	snapshot := l.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, l.context, message) {
+		return
+	}
+
+	if caller := l.withCaller(nil, 1); len(caller) > 0 {
+		fields = append(fields[:len(fields):len(fields)], caller[0].(Field))
+	}
+	l.emit(snapshot, snapshot.now(), level, message, expandFields(fields))
+}
+
 // AddHandler thêm một handler log mới vào logger.
 //
 // Method này đăng ký một handler với loại đã cho. Nếu một handler với cùng loại
@@ -230,17 +494,21 @@ func (l *logger) Fatal(message string, args ...interface{}) {
 func (l *logger) AddHandler(handlerType HandlerType, handler handler.Handler) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	// Nếu handler cũ cùng loại tồn tại, đóng lại để tránh leak resource
-	if old, ok := l.handlers[handlerType]; ok {
+	old, exists := l.handlers[handlerType]
+	l.handlers[handlerType] = handler
+	l.publish()
+	// Handler cũ cùng loại được đóng sau khi snapshot mới được công bố để tránh leak resource;
+	// chỉ các lời gọi log đang chạy mới có thể còn ghi vào nó
+	if exists && old != handler {
 		old.Close()
 	}
-	l.handlers[handlerType] = handler
 }
 
 // RemoveHandler xóa một handler khỏi logger theo loại.
 //
-// Handler sẽ được đóng đúng cách trước khi xóa để đảm bảo tất cả các tài nguyên
-// được giải phóng. Method này là thread-safe.
+// Handler sẽ được đóng đúng cách sau khi xóa để đảm bảo tất cả các tài nguyên
+// được giải phóng. Lời gọi log bắt đầu sau khi method trả về không còn ghi đến handler;
+// lời gọi đang chạy có thể vẫn ghi đến nó sau khi đóng. Method này là thread-safe.
 //
 // Tham số:
 //   - handlerType: HandlerType - loại handler cần xóa
@@ -253,10 +521,11 @@ func (l *logger) AddHandler(handlerType HandlerType, handler handler.Handler) {
 func (l *logger) RemoveHandler(handlerType HandlerType) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	// Đóng và xóa handler nếu nó tồn tại
+	// Xóa rồi đóng handler nếu nó tồn tại, sau khi snapshot mới được công bố
 	if handler, ok := l.handlers[handlerType]; ok {
-		handler.Close()
 		delete(l.handlers, handlerType)
+		l.publish()
+		handler.Close()
 	}
 }
 
@@ -286,8 +555,9 @@ func (l *logger) GetHandler(handlerType HandlerType) handler.Handler {
 
 // SetMinLevel thiết lập cấp độ log tối thiểu cho logger.
 //
-// Bất kỳ log entry nào có cấp độ dưới ngưỡng này sẽ bị bỏ qua.
-// Method này là thread-safe.
+// Bất kỳ log entry nào có cấp độ dưới ngưỡng này sẽ bị bỏ qua. Cấp độ được lưu bằng
+// atomic nên việc lọc log không cần lock, và mọi lời gọi log bắt đầu sau khi method trả về
+// thấy cấp độ mới. Method này là thread-safe.
 //
 // Tham số:
 //   - level: handler.Level - cấp độ log tối thiểu cần thiết lập
@@ -297,10 +567,56 @@ func (l *logger) GetHandler(handlerType HandlerType) handler.Handler {
 //	// Chỉ xử lý log Warning, Error và Fatal
 //	logger.SetMinLevel(handler.WarningLevel)
 func (l *logger) SetMinLevel(level handler.Level) {
+	l.minLevel.Store(int32(level))
+}
+
+// attachHandler gắn một handler do Manager quản lý vào logger.
+//
+// Khác với AddHandler, handler cũ cùng loại không bị đóng vì nó thuộc quyền
+// quản lý của Manager. Method này là thread-safe.
+//
+// Tham số:
+//   - handlerType: HandlerType - loại handler
+//   - h: handler.Handler - handler cần gắn
+func (l *logger) attachHandler(handlerType HandlerType, h handler.Handler) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	l.minLevel = level
+	l.handlers[handlerType] = h
+	l.publish()
+}
+
+// detachHandler gỡ một handler do Manager quản lý khỏi logger mà không đóng nó.
+//
+// Tham số:
+//   - handlerType: HandlerType - loại handler cần gỡ
+func (l *logger) detachHandler(handlerType HandlerType) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.handlers, handlerType)
+	l.publish()
+}
+
+// resetHandlers thay thế các handler thuộc các loại đã cho bằng một tập handler mới.
+//
+// Khác với AddHandler và RemoveHandler, method này không đóng handler cũ vì
+// chúng thuộc quyền quản lý của Manager. Method này là thread-safe.
+//
+// Tham số:
+//   - types: []HandlerType - các loại handler cần gỡ bỏ trước khi thay thế
+//   - handlers: map[HandlerType]handler.Handler - các handler mới cần gắn vào
+func (l *logger) resetHandlers(types []HandlerType, handlers map[HandlerType]handler.Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, handlerType := range types {
+		delete(l.handlers, handlerType)
+	}
+	for handlerType, h := range handlers {
+		l.handlers[handlerType] = h
+	}
+	l.publish()
 }
 
 // Close đóng tất cả các handler log đã đăng ký và giải phóng tài nguyên của chúng.
@@ -342,47 +658,242 @@ func (l *logger) Close() error {
 // log là method nội bộ để ghi một log entry đến tất cả các handler.
 //
 // Method này xử lý lọc cấp độ, định dạng thông điệp với context và gửi
-// log entry đến tất cả các handler đã đăng ký. Nó được thiết kế để giảm thiểu
-// thời gian giữ lock để tăng concurrency.
+// log entry đến tất cả các handler đã đăng ký. Thông điệp chỉ được định dạng (và các
+// giá trị Lazy chỉ được tính) khi log entry vượt qua cấp độ tối thiểu và có ít nhất
+// một handler chấp nhận cấp độ đó.
 //
 // Tham số:
 //   - level: handler.Level - cấp độ log của thông điệp
 //   - message: string - thông điệp log (có thể là chuỗi định dạng)
-//   - args: ...interface{} - tham số tùy chọn để định dạng thông điệp
+//   - args: ...interface{} - tham số tùy chọn để định dạng thông điệp, có thể chứa các Field
 func (l *logger) log(level handler.Level, message string, args ...interface{}) {
-	// Bỏ qua nếu dưới cấp độ tối thiểu
-	if level < l.minLevel {
+	l.logDepth(level, message, 3, args)
+}
+
+// logDepth ghi thông điệp như log, với depth là số stack frame từ withCaller đến bên gọi cần
+// ghi vị trí (VD: các hàm cấp package như log.Info).
+func (l *logger) logDepth(level handler.Level, message string, depth int, args []interface{}) {
+	// Bỏ qua nếu dưới cấp độ tối thiểu, không có handler nào chấp nhận cấp độ hoặc bị lấy mẫu bỏ
+	if level < l.getMinLevel() {
+		return
+	}
+	snapshot := l.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, l.context, message) {
 		return
 	}
 
-	// Lấy snapshot của handlers để giảm thiểu thời gian giữ lock
-	l.mu.RLock()
-	handlersCopy := make(map[HandlerType]handler.Handler, len(l.handlers))
-	for k, v := range l.handlers {
-		handlersCopy[k] = v
+	l.write(snapshot, snapshot.now(), level, message, l.withCaller(args, depth)...)
+}
+
+// audit ghi một bản ghi kiểm toán ở cấp độ info, bỏ qua ngưỡng cấp độ tối thiểu và lấy mẫu.
+//
+// Method này được Manager sử dụng để ghi lại các thay đổi vận hành (VD: nâng cấp độ log tạm thời)
+// mà người vận hành cần thấy bất kể cấp độ hiện tại của logger.
+//
+// Tham số:
+//   - message: string - thông điệp kiểm toán
+//   - args: ...interface{} - các field đính kèm
+func (l *logger) audit(message string, args ...interface{}) {
+	if snapshot := l.accepting(handler.InfoLevel); snapshot != nil {
+		l.write(snapshot, snapshot.now(), handler.InfoLevel, message, args...)
+	}
+}
+
+// setFieldLimits thay đổi giới hạn field của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - limits: handler.Limits - giới hạn mới (giá trị không dương dùng mặc định)
+func (l *logger) setFieldLimits(limits handler.Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits = limits
+	l.publish()
+}
+
+// setSampler thay đổi sampler của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - sampler: *handler.Sampler - sampler mới (nil để tắt lấy mẫu)
+func (l *logger) setSampler(sampler *handler.Sampler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sampler = sampler
+	l.publish()
+}
+
+// publish dựng snapshot mới từ handlers và limits hiện tại rồi thay thế snapshot cũ.
+//
+// Phải được gọi khi đang giữ l.mu (hoặc trước khi logger được chia sẻ, như trong NewLogger)
+// sau mỗi thay đổi handlers hoặc limits. Chi phí dựng snapshot chỉ phát sinh khi cấu hình
+// thay đổi, không phải trên mỗi lời gọi log.
+func (l *logger) publish() {
+	handlers := make([]namedHandler, 0, len(l.handlers))
+	for handlerType, h := range l.handlers {
+		if h != nil {
+			handlers = append(handlers, namedHandler{handlerType: handlerType, handler: h})
+		}
+	}
+	sort.Slice(handlers, func(i, j int) bool { return handlers[i].handlerType < handlers[j].handlerType })
+	l.snapshot.Store(&loggerSnapshot{handlers: handlers, limits: l.limits, sampler: l.sampler, hooks: l.hooks, retention: l.retention,
+		redactor: l.redactor, unredacted: l.unredacted, contextFields: l.contextFields, staticFields: l.staticFields, clock: l.clock, caller: l.caller, callerSkip: l.callerSkip,
+		raw: l.raw, pseudonymizer: l.pseudonymizer})
+}
+
+// accepting trả về snapshot hiện tại nếu có ít nhất một handler chấp nhận cấp độ đã cho.
+//
+// Tham số:
+//   - level: handler.Level - cấp độ của log entry
+//
+// Trả về:
+//   - *loggerSnapshot: snapshot dùng để ghi entry, hoặc nil nếu không handler nào ghi entry
+func (l *logger) accepting(level handler.Level) *loggerSnapshot {
+	snapshot := l.snapshot.Load().(*loggerSnapshot)
+	for _, h := range snapshot.handlers {
+		if handler.Enabled(h.handler, level) {
+			return snapshot
+		}
 	}
-	l.mu.RUnlock()
+	return nil
+}
 
-	// Định dạng thông điệp nếu có tham số
-	formattedMessage := message
+// getMinLevel trả về cấp độ log tối thiểu hiện tại của logger bằng một lần đọc atomic.
+// Method này là thread-safe.
+func (l *logger) getMinLevel() handler.Level {
+	return handler.Level(l.minLevel.Load())
+}
+
+// write định dạng và gửi một log entry đến các handler của snapshot mà không lọc theo cấp độ
+// tối thiểu.
+//
+// Tham số:
+//   - snapshot: *loggerSnapshot - handlers và limits dùng cho entry (từ accepting)
+//   - t: time.Time - thời điểm phát sinh của log entry
+//   - level: handler.Level - cấp độ log của thông điệp
+//   - message: string - thông điệp log (có thể là chuỗi định dạng)
+//   - args: ...interface{} - tham số tùy chọn để định dạng thông điệp, có thể chứa các Field
+func (l *logger) write(snapshot *loggerSnapshot, t time.Time, level handler.Level, message string, args ...interface{}) {
+	// Tách các field có cấu trúc khỏi tham số định dạng
+	args, fields := splitFields(args)
+
+	// Định dạng thông điệp nếu có tham số, tính các giá trị Lazy ngay trước khi định dạng
 	if len(args) > 0 {
-		formattedMessage = fmt.Sprintf(message, args...)
+		message = fmt.Sprintf(message, resolveLazy(args)...)
 	}
 
-	// Thêm context vào thông điệp nếu có (context là immutable nên không cần lock)
+	l.emit(snapshot, t, level, message, fields)
+}
+
+// format gắn context và các field dạng key=value vào thông điệp trong một buffer dùng lại từ
+// pool (context là immutable nên không cần lock).
+//
+// Tham số:
+//   - limits: handler.Limits - giới hạn khi ghi field
+//   - message: string - thông điệp đã được định dạng
+//   - fields: []Field - các field có cấu trúc
+//
+// Trả về:
+//   - string: thông điệp dạng "[context] message key=value ..."
+func (l *logger) format(limits handler.Limits, message string, fields []Field) string {
+	if l.context == "" && len(fields) == 0 {
+		return message
+	}
+
+	buf := handler.GetBuffer()
 	if l.context != "" {
-		formattedMessage = fmt.Sprintf("[%s] %s", l.context, formattedMessage)
+		*buf = append(*buf, '[')
+		*buf = append(*buf, l.context...)
+		*buf = append(*buf, "] "...)
+	}
+	*buf = append(*buf, message...)
+	if len(fields) > 0 {
+		*buf = append(*buf, ' ')
+		*buf = limits.AppendFields(*buf, fields)
 	}
+	formatted := string(*buf)
+	handler.PutBuffer(buf)
+	return formatted
+}
 
-	// Ghi log entry đến tất cả các handler
-	for handlerType, handler := range handlersCopy {
-		// Bỏ qua handler nil
-		if handler == nil {
+// emit chạy các hook, che dữ liệu nhạy cảm, gắn context và các field vào thông điệp đã định
+// dạng rồi gửi log entry đến các handler của snapshot chấp nhận cấp độ của entry.
+//
+// Tham số:
+//   - snapshot: *loggerSnapshot - handlers và limits dùng cho entry (từ accepting)
+//   - t: time.Time - thời điểm phát sinh của log entry
+//   - level: handler.Level - cấp độ log của thông điệp
+//   - message: string - thông điệp đã được định dạng
+//   - fields: []Field - các field có cấu trúc đã được mở rộng
+func (l *logger) emit(snapshot *loggerSnapshot, t time.Time, level handler.Level, message string, fields []Field) {
+	limits := snapshot.limits
+
+	// Entry không được lấy từ pool vì handler được phép giữ lại entry sau khi LogEntry trả về
+	// (VD: AsyncHandler đưa entry vào hàng đợi)
+	entry := &handler.Entry{Time: t, Level: level, Message: message, Fields: fields}
+
+	// Hook nhận entry trước khi gắn context và field nên có thể sửa thông điệp và field; field
+	// được sao chép vì hook có thể sửa tại chỗ slice do bên gọi truyền vào LogFields
+	if len(snapshot.hooks) > 0 {
+		entry.Fields = slices.Clone(fields)
+		if !runHooks(snapshot.hooks, entry) {
+			return
+		}
+		level, message, fields = entry.Level, entry.Message, entry.Fields
+	}
+
+	// Giới hạn số field trước khi định dạng để entry gửi đến handler cũng được cắt bớt; lớp
+	// lưu trữ và các field cố định được gắn sau để không bị cắt
+	fields = withStaticFields(withRetention(limits.TruncateFields(fields), snapshot.retention), snapshot.staticFields)
+
+	// Thoát ký tự điều khiển trong thông điệp và key của field để dữ liệu từ bên ngoài không
+	// tạo được dòng log giả; giá trị của field đã được các định dạng đặt trong nháy kép và thoát
+	if !snapshot.raw {
+		message, fields = handler.Sanitize(message), sanitizeKeys(fields)
+	}
+
+	// Thay định danh bằng pseudonym trước khi che để cả entry chưa che cũng không chứa định danh gốc
+	if snapshot.pseudonymizer != nil {
+		fields = snapshot.pseudonymizer.PseudonymizeFields(fields)
+	}
+
+	// Che dữ liệu nhạy cảm sau khi chạy hook để field do hook thêm vào cũng được che; entry
+	// chưa che chỉ được định dạng khi có handler nhận entry chưa che
+	var raw *handler.Entry
+	if snapshot.redactor != nil {
+		rawMessage, rawFields := message, fields
+		message, fields = snapshot.redactor.RedactString(message), snapshot.redactor.RedactFields(fields)
+		if len(snapshot.unredacted) > 0 {
+			raw = &handler.Entry{Time: t, Level: level, Message: l.format(limits, rawMessage, rawFields), Text: rawMessage, Fields: rawFields}
+		}
+	}
+
+	// Ghi log entry đến tất cả các handler, chỉ đo thời gian ghi khi có observer
+	entry.Message, entry.Text, entry.Fields = l.format(limits, message, fields), message, fields
+	l.metrics.entries.Add(1)
+	observer := l.metrics.load()
+	if observer != nil {
+		observer.ObserveEntry(l.context, level)
+	}
+	for _, h := range snapshot.handlers {
+		if !handler.Enabled(h.handler, level) {
 			continue
 		}
-		if err := handler.Log(level, formattedMessage); err != nil {
-			// Xử lý lỗi logging (ghi ra stderr)
-			fmt.Printf("Lỗi khi ghi log đến handler %s: %v\n", handlerType, err)
+		target := entry
+		if raw != nil && snapshot.unredacted[h.handlerType] {
+			target = raw
+		}
+		var start time.Time
+		if observer != nil {
+			start = time.Now()
+		}
+		err := handler.Dispatch(h.handler, target)
+		l.metrics.write(h.handlerType, err)
+		if observer != nil {
+			observer.ObserveWrite(h.handlerType, time.Since(start), err)
+		}
+		if err != nil {
+			l.errors.report(h.handlerType, err)
 		}
 	}
 }
@@ -0,0 +1,260 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// entryHandler ghi lại entry cuối cùng nhận được qua LogEntry
+type entryHandler struct {
+	MockHandler
+	entry *handler.Entry
+}
+
+func (e *entryHandler) LogEntry(entry *handler.Entry) error {
+	e.entry = entry
+	return nil
+}
+
+func TestLogger_LogAt(t *testing.T) {
+	l := NewLogger("Batch")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.LogAt(at, handler.WarningLevel, "Order %d processed", 7, Any("late", true))
+
+	if h.entry == nil {
+		t.Fatal("LogAt() nên gửi entry đến handler hỗ trợ EntryHandler")
+	}
+	if !h.entry.Time.Equal(at) {
+		t.Errorf("LogAt() nên giữ thời điểm do bên gọi cung cấp, got %v", h.entry.Time)
+	}
+	if h.entry.Level != handler.WarningLevel {
+		t.Errorf("LogAt() ghi sai level, got %v", h.entry.Level)
+	}
+	if h.entry.Message != "[Batch] Order 7 processed late=true" {
+		t.Errorf("LogAt() định dạng thông điệp sai, got %q", h.entry.Message)
+	}
+}
+
+func TestLogger_LogAt_FiltersAndDefaults(t *testing.T) {
+	l := NewLogger("Batch")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.LogAt(time.Now(), handler.DebugLevel, "filtered")
+	if h.entry != nil {
+		t.Error("LogAt() nên lọc entry dưới cấp độ tối thiểu")
+	}
+
+	before := time.Now()
+	l.LogAt(time.Time{}, handler.InfoLevel, "now")
+	if h.entry == nil || h.entry.Time.Before(before) {
+		t.Errorf("LogAt() với zero time nên dùng thời điểm hiện tại, got %v", h.entry)
+	}
+}
+
+func TestLogger_HandlerSnapshot(t *testing.T) {
+	l := NewLogger("Snapshot").(*logger)
+	before := l.snapshot.Load().(*loggerSnapshot)
+
+	l.AddHandler(TestHandlerType, &discardHandler{})
+	l.AddHandler(HandlerTypeConsole, nil)
+	after := l.snapshot.Load().(*loggerSnapshot)
+
+	if len(before.handlers) != 0 {
+		t.Errorf("Snapshot cũ không được thay đổi khi thêm handler, got %d handler", len(before.handlers))
+	}
+	if len(after.handlers) != 1 || after.handlers[0].handlerType != TestHandlerType {
+		t.Errorf("Snapshot mới nên chứa handler vừa thêm và bỏ handler nil, got %+v", after.handlers)
+	}
+
+	l.detachHandler(TestHandlerType)
+	if got := l.snapshot.Load().(*loggerSnapshot); len(got.handlers) != 0 {
+		t.Errorf("Snapshot nên được dựng lại sau khi gỡ handler, got %+v", got.handlers)
+	}
+}
+
+func TestLogger_ConcurrentHandlerChanges(t *testing.T) {
+	l := NewLogger("Concurrent").(*logger)
+	h := &discardHandler{}
+	l.attachHandler(TestHandlerType, h)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.Info("message %d", j)
+			}
+		}()
+	}
+	for j := 0; j < 200; j++ {
+		l.attachHandler(HandlerTypeConsole, &discardHandler{})
+		l.detachHandler(HandlerTypeConsole)
+		l.setFieldLimits(handler.Limits{MaxFields: j + 1})
+	}
+	wg.Wait()
+
+	if got := h.calls.Load(); got != 800 {
+		t.Errorf("Handler không bị thay đổi nên nhận đủ 800 entry, got %d", got)
+	}
+}
+
+func TestLogger_MinLevelConcurrent(t *testing.T) {
+	l := NewLogger("Level").(*logger)
+	h := &discardHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			l.SetMinLevel(handler.Level(i % 3))
+		}
+	}()
+	for i := 0; i < 500; i++ {
+		l.Debug("debug %d", i)
+	}
+	wg.Wait()
+
+	l.SetMinLevel(handler.ErrorLevel)
+	before := h.calls.Load()
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Debug("filtered")
+	})
+	if allocs != 0 {
+		t.Errorf("Log bị lọc theo cấp độ không nên cấp phát bộ nhớ, got %v allocs", allocs)
+	}
+	if h.calls.Load() != before {
+		t.Error("Log dưới cấp độ tối thiểu không nên được gửi đến handler")
+	}
+}
+
+// suffixCounter đếm các thông điệp kết thúc bằng suffix, an toàn khi dùng đồng thời
+type suffixCounter struct {
+	discardHandler
+	suffix  string
+	matched atomic.Int64
+}
+
+func (s *suffixCounter) Log(level handler.Level, message string, args ...interface{}) error {
+	if strings.HasSuffix(message, s.suffix) {
+		s.matched.Add(1)
+	}
+	return s.discardHandler.Log(level, message, args...)
+}
+
+func TestLogger_SetMinLevelHappensBefore(t *testing.T) {
+	l := NewLogger("Level")
+	h := &suffixCounter{suffix: "after"}
+	l.AddHandler(TestHandlerType, h)
+
+	// Lời gọi log bắt đầu sau khi quan sát được SetMinLevel cuối cùng phải thấy cấp độ mới
+	var applied atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for after := 0; after < 200; {
+				if applied.Load() {
+					l.Info("after")
+					after++
+				} else {
+					l.Info("before")
+				}
+			}
+		}()
+	}
+	for i := 0; i < 500; i++ {
+		l.SetMinLevel(handler.Level(i % 2))
+	}
+	l.SetMinLevel(handler.ErrorLevel)
+	applied.Store(true)
+	wg.Wait()
+
+	if got := h.matched.Load(); got != 0 {
+		t.Errorf("Entry info ghi sau SetMinLevel(ErrorLevel) không được gửi đến handler, got %d", got)
+	}
+}
+
+func TestLogger_ConcurrentReconfigure(t *testing.T) {
+	l := NewLogger("Reconfigure").(*logger)
+	base := &discardHandler{}
+	l.AddHandler(TestHandlerType, base)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.Info("order %d", 1, Int("id", 1))
+				l.LogFields(handler.WarningLevel, "fields", String("k", "v"))
+				l.EveryN(2).Error("repeat")
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		// Handler bị thay thế hoặc xóa có thể nhận lời gọi đang chạy sau khi đóng
+		l.AddHandler(HandlerTypeConsole, &discardHandler{})
+		l.RemoveHandler(HandlerTypeConsole)
+		l.setCaller(i%2 == 0, i%3)
+		l.setFieldLimits(handler.Limits{MaxFields: i%5 + 1})
+		l.SetMinLevel(handler.Level(i % 3))
+	}
+	close(stop)
+	wg.Wait()
+
+	// Handler được thêm trước lời gọi log nhận entry của lời gọi đó
+	added := &discardHandler{}
+	l.SetMinLevel(handler.InfoLevel)
+	l.AddHandler(HandlerTypeFile, added)
+	l.Info("after add")
+	if added.calls.Load() != 1 {
+		t.Errorf("Lời gọi log sau AddHandler nên đến handler mới, got %d", added.calls.Load())
+	}
+}
+
+func TestLogger_WithSampling(t *testing.T) {
+	l := NewLogger("Worker", WithSampling(handler.SamplingOptions{Initial: 2, Thereafter: 3, Tick: time.Hour}))
+	h := &discardHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	// Thông điệp được nhóm trước khi định dạng: 2 entry đầu, sau đó entry thứ 5 và 8
+	for i := 0; i < 10; i++ {
+		l.Info("job %d done", i)
+	}
+	if got := h.calls.Load(); got != 4 {
+		t.Errorf("Sampler nên giữ lại 4/10 entry, got %d", got)
+	}
+
+	// Thông điệp khác có bộ đếm riêng
+	l.Info("job failed")
+	if got := h.calls.Load(); got != 5 {
+		t.Errorf("Thông điệp khác không nên bị ảnh hưởng bởi bộ đếm, got %d", got)
+	}
+
+	l.(*logger).setSampler(nil)
+	for i := 0; i < 10; i++ {
+		l.Info("job %d done", i)
+	}
+	if got := h.calls.Load(); got != 15 {
+		t.Errorf("Tắt sampler nên ghi tất cả entry, got %d", got)
+	}
+}
@@ -0,0 +1,153 @@
+package logtest
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// UpdateEnv là biến môi trường bật ghi đè golden file (LOGTEST_UPDATE=1), dùng được với
+// `go test ./...` kể cả khi có package không import logtest.
+const UpdateEnv = "LOGTEST_UPDATE"
+
+// update là flag -logtest.update của `go test`, ghi đè golden file bằng output hiện tại thay vì
+// so sánh. Tên flag có tiền tố "logtest." để không trùng với flag -update của package test.
+var update = flag.Bool("logtest.update", false, "rewrite logtest golden files with the current output")
+
+// updating cho biết golden file có được ghi đè không (flag -logtest.update hoặc LOGTEST_UPDATE=1).
+func updating() bool {
+	return *update || os.Getenv(UpdateEnv) == "1"
+}
+
+// GoldenTime là thời điểm cố định mặc định của mọi entry khi render golden file.
+var GoldenTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// GoldenOptions cấu hình cách Render định dạng các entry.
+type GoldenOptions struct {
+	Format handler.Format // Định dạng dòng log (mặc định handler.TextFormat)
+	Time   time.Time      // Thời điểm gán cho mọi entry (zero = GoldenTime)
+	Ignore []string       // Key của các field thay đổi giữa các lần chạy (VD: "pid", "latency"), bị bỏ khi render
+}
+
+// Render định dạng các entry thành output ổn định giữa các lần chạy: mọi entry có cùng thời
+// điểm (clock cố định), các field trong Ignore bị bỏ và các field còn lại được sắp xếp theo key.
+//
+// Phần " key=value ..." cuối thông điệp do logger thêm vào được định dạng lại theo các field đã
+// sắp xếp; nếu phần cuối của thông điệp không khớp, chỉ Entry.Fields được sắp xếp.
+//
+// Tham số:
+//   - entries: []handler.Entry - các entry cần render, VD: MemoryHandler.Entries()
+//   - opts: GoldenOptions - định dạng, thời điểm và các field bị bỏ
+//
+// Trả về:
+//   - []byte: output, mỗi entry một dòng
+func Render(entries []handler.Entry, opts GoldenOptions) []byte {
+	at := opts.Time
+	if at.IsZero() {
+		at = GoldenTime
+	}
+
+	var out []byte
+	for i := range entries {
+		entry := entries[i].Clone()
+		entry.Time = at
+
+		fields := make([]handler.Field, 0, len(entry.Fields))
+		for _, f := range entry.Fields {
+			if !slices.Contains(opts.Ignore, f.Key) {
+				fields = append(fields, f)
+			}
+		}
+		slices.SortStableFunc(fields, func(a, b handler.Field) int { return cmp.Compare(a.Key, b.Key) })
+
+		if len(entry.Fields) > 0 {
+			suffix := handler.Limits{}.AppendFields([]byte{' '}, entry.Fields)
+			if base, ok := strings.CutSuffix(entry.Message, string(suffix)); ok {
+				message := []byte(base)
+				if len(fields) > 0 {
+					message = append(message, ' ')
+					message = handler.Limits{}.AppendFields(message, fields)
+				}
+				entry.Message = string(message)
+			}
+		}
+		entry.Fields = fields
+		out = opts.Format.Append(out, entry)
+	}
+	return out
+}
+
+// AssertGolden render các entry của capture (xem Render) và so sánh với nội dung của golden file
+// path, báo lỗi test kèm dòng khác biệt đầu tiên nếu không khớp. Khi chạy
+// `go test -logtest.update` hoặc với LOGTEST_UPDATE=1, golden file (và thư mục chứa nó) được ghi
+// đè bằng output hiện tại.
+//
+// Tham số:
+//   - t: testing.TB - test đang chạy
+//   - capture: Capture - nguồn entry
+//   - path: string - đường dẫn golden file, thường nằm trong testdata
+//   - opts: GoldenOptions - định dạng, thời điểm và các field bị bỏ
+//
+// Trả về:
+//   - bool: true nếu output khớp golden file (hoặc golden file vừa được ghi)
+//
+// Ví dụ:
+//
+//	logtest.AssertGolden(t, mem, "testdata/checkout.golden", logtest.GoldenOptions{
+//	    Format: handler.JSONFormat,
+//	    Ignore: []string{"latency"},
+//	})
+func AssertGolden(t testing.TB, capture Capture, path string, opts GoldenOptions) bool {
+	t.Helper()
+	got := Render(capture.Entries(), opts)
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create golden directory: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("failed to write golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("failed to read golden file (run go test -logtest.update to create it): %v", err)
+		return false
+	}
+	if bytes.Equal(got, want) {
+		return true
+	}
+	t.Errorf("log output does not match %s (run go test -logtest.update to accept it)\n%s", path, firstDiff(got, want))
+	return false
+}
+
+// firstDiff mô tả dòng khác biệt đầu tiên giữa output got và golden file want.
+func firstDiff(got, want []byte) string {
+	gotLines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+	wantLines := strings.Split(strings.TrimSuffix(string(want), "\n"), "\n")
+	for i := 0; ; i++ {
+		g, w := "(end of output)", "(end of file)"
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w || i >= len(gotLines) || i >= len(wantLines) {
+			return "line " + strconv.Itoa(i+1) + ":\n\tgot:  " + g + "\n\twant: " + w
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package logtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func newGoldenCapture() *handler.MemoryHandler {
+	mem := handler.NewMemoryHandler()
+	mem.LogEntry(&handler.Entry{Level: handler.InfoLevel, Message: "[Orders] order placed order_id=42 customer=alice pid=4242", Fields: []handler.Field{
+		{Key: "order_id", Type: handler.Int64Type, Integer: 42},
+		{Key: "customer", Type: handler.StringType, Str: "alice"},
+		{Key: "pid", Type: handler.Int64Type, Integer: 4242},
+	}})
+	mem.Log(handler.WarningLevel, "[Orders] slow payment")
+	return mem
+}
+
+func TestRender(t *testing.T) {
+	got := string(Render(newGoldenCapture().Entries(), GoldenOptions{Ignore: []string{"pid"}}))
+	want := "2024/03/01 12:00:00 [INFO] [Orders] order placed customer=alice order_id=42\n" +
+		"2024/03/01 12:00:00 [WARNING] [Orders] slow payment\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	got = string(Render(newGoldenCapture().Entries(), GoldenOptions{Format: handler.JSONFormat, Ignore: []string{"pid"}}))
+	if !strings.HasPrefix(got, `{"time":"2024-03-01T12:00:00Z","level":"INFO","context":"Orders","message":"order placed","customer":"alice","order_id":42}`) {
+		t.Errorf("Render(JSON) = %q", got)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	mem := newGoldenCapture()
+	opts := GoldenOptions{Ignore: []string{"pid"}}
+	if !AssertGolden(t, mem, "testdata/orders.golden", opts) {
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "orders.golden")
+	r := &recorder{TB: t}
+	if AssertGolden(r, mem, path, opts) || !strings.Contains(r.errors[0], "go test -logtest.update") {
+		t.Errorf("AssertGolden() với golden file chưa có nên thất bại, errors = %q", r.errors)
+	}
+
+	*update = true
+	defer func() { *update = false }()
+	if !AssertGolden(r, mem, path, opts) {
+		t.Fatalf("AssertGolden(-logtest.update) errors = %q", r.errors)
+	}
+	*update = false
+
+	// LOGTEST_UPDATE=1 có tác dụng như flag
+	mem.Log(handler.WarningLevel, "[Orders] stock low")
+	t.Setenv(UpdateEnv, "1")
+	if !AssertGolden(r, mem, path, opts) {
+		t.Fatalf("AssertGolden(LOGTEST_UPDATE=1) errors = %q", r.errors)
+	}
+	t.Setenv(UpdateEnv, "")
+
+	mem.Log(handler.ErrorLevel, "[Orders] payment failed")
+	r.errors = nil
+	if AssertGolden(r, mem, path, opts) || !strings.Contains(r.errors[0], "line 4:\n\tgot:  2024/03/01 12:00:00 [ERROR] [Orders] payment failed\n\twant: (end of file)") {
+		t.Errorf("AssertGolden() errors = %q", r.errors)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "payment failed") {
+		t.Error("AssertGolden() không được ghi đè golden file khi không có -logtest.update")
+	}
+}
@@ -0,0 +1,267 @@
+// Package logtest cung cấp các hàm kiểm tra log cho test hành vi của mã có ghi log, dùng cùng
+// handler.MemoryHandler (hoặc bất kỳ Capture nào):
+//
+//	func TestPlaceOrder(t *testing.T) {
+//	    mem := handler.NewMemoryHandler()
+//	    logger := log.NewLogger("Orders")
+//	    logger.AddHandler("memory", mem)
+//
+//	    PlaceOrder(logger, 42)
+//
+//	    logtest.AssertLogged(t, mem, handler.InfoLevel, "order placed", logtest.FieldEquals("order_id", 42))
+//	    logtest.AssertNotLogged(t, mem, handler.ErrorLevel, "")
+//	    logtest.AssertOrder(t, mem, "order placed", "payment captured")
+//	}
+//
+// Khi kiểm tra thất bại, thông báo lỗi liệt kê các entry đã ghi để dễ tìm nguyên nhân.
+//
+// AssertGolden so sánh output đã định dạng với golden file để kiểm tra hồi quy định dạng log;
+// chạy `go test -logtest.update` (hoặc LOGTEST_UPDATE=1) để ghi lại golden file.
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// Capture là nguồn entry được kiểm tra, VD: *handler.MemoryHandler.
+type Capture interface {
+	// Entries trả về các entry đã ghi theo thứ tự nhận.
+	Entries() []handler.Entry
+}
+
+// Matcher là điều kiện bổ sung trên một entry, VD: entry có field với giá trị đã cho.
+type Matcher struct {
+	desc  string
+	match func(entry *handler.Entry) bool
+}
+
+// NewMatcher tạo Matcher từ một hàm kiểm tra, để test tự viết điều kiện riêng.
+//
+// Tham số:
+//   - desc: string - mô tả điều kiện, dùng trong thông báo lỗi
+//   - match: func(*handler.Entry) bool - trả về true nếu entry thỏa điều kiện
+//
+// Trả về:
+//   - Matcher: điều kiện trên entry
+func NewMatcher(desc string, match func(entry *handler.Entry) bool) Matcher {
+	return Matcher{desc: desc, match: match}
+}
+
+// Match kiểm tra entry có thỏa điều kiện hay không.
+//
+// Tham số:
+//   - entry: *handler.Entry - entry cần kiểm tra
+//
+// Trả về:
+//   - bool: true nếu entry thỏa điều kiện
+func (m Matcher) Match(entry *handler.Entry) bool {
+	return m.match(entry)
+}
+
+// String trả về mô tả của điều kiện.
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// HasField kiểm tra entry có field key.
+//
+// Tham số:
+//   - key: string - key của field
+//
+// Trả về:
+//   - Matcher: điều kiện trên entry
+func HasField(key string) Matcher {
+	return NewMatcher("has field "+key, func(entry *handler.Entry) bool {
+		_, ok := entry.Field(key)
+		return ok
+	})
+}
+
+// FieldEquals kiểm tra entry có field key với giá trị value. Giá trị được so sánh theo dạng văn
+// bản (fmt.Sprint), nên FieldEquals("order_id", 42) khớp field int64 hoặc uint64 có giá trị 42.
+//
+// Tham số:
+//   - key: string - key của field
+//   - value: interface{} - giá trị mong đợi
+//
+// Trả về:
+//   - Matcher: điều kiện trên entry
+func FieldEquals(key string, value interface{}) Matcher {
+	want := fmt.Sprint(value)
+	return NewMatcher(fmt.Sprintf("field %s=%s", key, want), func(entry *handler.Entry) bool {
+		f, ok := entry.Field(key)
+		return ok && fmt.Sprint(f.Interface()) == want
+	})
+}
+
+// FieldContains kiểm tra entry có field key với giá trị (dạng văn bản) chứa chuỗi substr.
+//
+// Tham số:
+//   - key: string - key của field
+//   - substr: string - chuỗi cần tìm trong giá trị
+//
+// Trả về:
+//   - Matcher: điều kiện trên entry
+func FieldContains(key, substr string) Matcher {
+	return NewMatcher(fmt.Sprintf("field %s containing %q", key, substr), func(entry *handler.Entry) bool {
+		f, ok := entry.Field(key)
+		return ok && strings.Contains(fmt.Sprint(f.Interface()), substr)
+	})
+}
+
+// Find trả về các entry có cấp độ level, thông điệp chứa substr và thỏa mọi matcher.
+//
+// Tham số:
+//   - capture: Capture - nguồn entry
+//   - level: handler.Level - cấp độ của entry
+//   - substr: string - chuỗi cần tìm trong thông điệp, rỗng để khớp mọi thông điệp
+//   - matchers: ...Matcher - các điều kiện bổ sung
+//
+// Trả về:
+//   - []handler.Entry: các entry khớp theo thứ tự nhận
+func Find(capture Capture, level handler.Level, substr string, matchers ...Matcher) []handler.Entry {
+	var found []handler.Entry
+	for _, entry := range capture.Entries() {
+		if matches(&entry, level, substr, matchers) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// AssertLogged báo lỗi test nếu không có entry nào có cấp độ level, thông điệp chứa substr và
+// thỏa mọi matcher.
+//
+// Tham số:
+//   - t: testing.TB - test đang chạy
+//   - capture: Capture - nguồn entry
+//   - level: handler.Level - cấp độ của entry
+//   - substr: string - chuỗi cần tìm trong thông điệp, rỗng để khớp mọi thông điệp
+//   - matchers: ...Matcher - các điều kiện bổ sung
+//
+// Trả về:
+//   - bool: true nếu có entry khớp
+func AssertLogged(t testing.TB, capture Capture, level handler.Level, substr string, matchers ...Matcher) bool {
+	t.Helper()
+	if len(Find(capture, level, substr, matchers...)) > 0 {
+		return true
+	}
+	t.Errorf("no %s entry containing %q%s was logged\n%s", level, substr, describe(matchers), dump(capture))
+	return false
+}
+
+// AssertNotLogged báo lỗi test nếu có entry có cấp độ level, thông điệp chứa substr và thỏa mọi
+// matcher.
+//
+// Tham số:
+//   - t: testing.TB - test đang chạy
+//   - capture: Capture - nguồn entry
+//   - level: handler.Level - cấp độ của entry
+//   - substr: string - chuỗi cần tìm trong thông điệp, rỗng để khớp mọi thông điệp
+//   - matchers: ...Matcher - các điều kiện bổ sung
+//
+// Trả về:
+//   - bool: true nếu không có entry khớp
+func AssertNotLogged(t testing.TB, capture Capture, level handler.Level, substr string, matchers ...Matcher) bool {
+	t.Helper()
+	if len(Find(capture, level, substr, matchers...)) == 0 {
+		return true
+	}
+	t.Errorf("unexpected %s entry containing %q%s was logged\n%s", level, substr, describe(matchers), dump(capture))
+	return false
+}
+
+// AssertCount báo lỗi test nếu số entry có cấp độ level, thông điệp chứa substr và thỏa mọi
+// matcher khác n.
+//
+// Tham số:
+//   - t: testing.TB - test đang chạy
+//   - capture: Capture - nguồn entry
+//   - n: int - số entry mong đợi
+//   - level: handler.Level - cấp độ của entry
+//   - substr: string - chuỗi cần tìm trong thông điệp, rỗng để khớp mọi thông điệp
+//   - matchers: ...Matcher - các điều kiện bổ sung
+//
+// Trả về:
+//   - bool: true nếu số entry khớp bằng n
+func AssertCount(t testing.TB, capture Capture, n int, level handler.Level, substr string, matchers ...Matcher) bool {
+	t.Helper()
+	if got := len(Find(capture, level, substr, matchers...)); got != n {
+		t.Errorf("got %d %s entries containing %q%s, want %d\n%s", got, level, substr, describe(matchers), n, dump(capture))
+		return false
+	}
+	return true
+}
+
+// AssertOrder báo lỗi test nếu các thông điệp chứa lần lượt các chuỗi substrs không xuất hiện
+// theo đúng thứ tự (các entry khác có thể xen giữa), ở bất kỳ cấp độ nào.
+//
+// Tham số:
+//   - t: testing.TB - test đang chạy
+//   - capture: Capture - nguồn entry
+//   - substrs: ...string - các chuỗi theo thứ tự mong đợi
+//
+// Trả về:
+//   - bool: true nếu các thông điệp xuất hiện đúng thứ tự
+//
+// Ví dụ:
+//
+//	logtest.AssertOrder(t, mem, "connecting", "connected", "serving")
+func AssertOrder(t testing.TB, capture Capture, substrs ...string) bool {
+	t.Helper()
+	entries := capture.Entries()
+	next := 0
+	for _, entry := range entries {
+		if next < len(substrs) && strings.Contains(entry.Message, substrs[next]) {
+			next++
+		}
+	}
+	if next == len(substrs) {
+		return true
+	}
+	t.Errorf("entry containing %q was not logged after %q\n%s", substrs[next], substrs[:next], dump(capture))
+	return false
+}
+
+// matches kiểm tra entry có cấp độ level, thông điệp chứa substr và thỏa mọi matcher.
+func matches(entry *handler.Entry, level handler.Level, substr string, matchers []Matcher) bool {
+	if entry.Level != level || !strings.Contains(entry.Message, substr) {
+		return false
+	}
+	for _, m := range matchers {
+		if !m.Match(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe trả về mô tả các matcher để thêm vào thông báo lỗi.
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return ""
+	}
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.String()
+	}
+	return " with " + strings.Join(descs, ", ")
+}
+
+// dump liệt kê các entry đã ghi, mỗi entry một dòng.
+func dump(capture Capture) string {
+	entries := capture.Entries()
+	if len(entries) == 0 {
+		return "logged entries: (none)"
+	}
+	var b strings.Builder
+	b.WriteString("logged entries:")
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\n\t[%s] %s", entry.Level, entry.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// recorder thay testing.TB để ghi lại các lỗi mà assertion báo.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newCapture() *handler.MemoryHandler {
+	mem := handler.NewMemoryHandler()
+	mem.Log(handler.InfoLevel, "connecting")
+	mem.LogEntry(&handler.Entry{Level: handler.InfoLevel, Message: "order placed order_id=42", Fields: []handler.Field{
+		{Key: "order_id", Type: handler.Int64Type, Integer: 42},
+		{Key: "customer", Type: handler.StringType, Str: "alice@example.com"},
+	}})
+	mem.Log(handler.WarningLevel, "slow payment")
+	mem.Log(handler.InfoLevel, "payment captured")
+	return mem
+}
+
+func TestAssertLogged(t *testing.T) {
+	mem := newCapture()
+	r := &recorder{TB: t}
+
+	if !AssertLogged(r, mem, handler.InfoLevel, "order placed", HasField("customer"), FieldEquals("order_id", 42), FieldContains("customer", "@example")) {
+		t.Errorf("AssertLogged() errors = %q", r.errors)
+	}
+	if AssertLogged(r, mem, handler.InfoLevel, "order placed", FieldEquals("order_id", 7)) {
+		t.Error("AssertLogged() nên thất bại khi field không khớp")
+	}
+	if AssertLogged(r, mem, handler.ErrorLevel, "order placed") {
+		t.Error("AssertLogged() nên thất bại khi cấp độ không khớp")
+	}
+	if len(r.errors) != 2 || !strings.Contains(r.errors[0], "field order_id=7") || !strings.Contains(r.errors[0], "[WARNING] slow payment") {
+		t.Errorf("errors = %q", r.errors)
+	}
+}
+
+func TestAssertNotLoggedAndCount(t *testing.T) {
+	mem := newCapture()
+	r := &recorder{TB: t}
+
+	if !AssertNotLogged(r, mem, handler.ErrorLevel, "") || !AssertCount(r, mem, 2, handler.InfoLevel, "o") {
+		t.Errorf("errors = %q", r.errors)
+	}
+	if AssertNotLogged(r, mem, handler.WarningLevel, "slow") || AssertCount(r, mem, 1, handler.InfoLevel, "") {
+		t.Error("AssertNotLogged() và AssertCount() nên thất bại")
+	}
+	if len(r.errors) != 2 {
+		t.Errorf("errors = %q", r.errors)
+	}
+	if got := Find(mem, handler.InfoLevel, "", HasField("order_id")); len(got) != 1 || got[0].Message != "order placed order_id=42" {
+		t.Errorf("Find() = %+v", got)
+	}
+}
+
+func TestAssertOrder(t *testing.T) {
+	mem := newCapture()
+	r := &recorder{TB: t}
+
+	if !AssertOrder(r, mem, "connecting", "order placed", "payment captured") || !AssertOrder(r, mem) {
+		t.Errorf("errors = %q", r.errors)
+	}
+	if AssertOrder(r, mem, "payment captured", "order placed") {
+		t.Error("AssertOrder() nên thất bại khi thứ tự sai")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `"order placed" was not logged after ["payment captured"]`) {
+		t.Errorf("errors = %q", r.errors)
+	}
+
+	r.errors = nil
+	AssertOrder(r, handler.NewMemoryHandler(), "anything")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "(none)") {
+		t.Errorf("errors = %q", r.errors)
+	}
+}
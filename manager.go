@@ -1,8 +1,16 @@
 package log
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.fork.vn/log/handler"
 )
@@ -18,7 +26,21 @@ type Manager interface {
 	// Tham số:
 	//   - handlerType: HandlerType - loại handler (console, file, stack)
 	//   - handler: handler.Handler - instance của handler cần thêm
-	AddHandler(handlerType HandlerType, handler handler.Handler)
+	//   - opts: ...HandlerOption - tùy chọn quản lý handler (VD: WithExternalOwnership)
+	AddHandler(handlerType HandlerType, handler handler.Handler, opts ...HandlerOption)
+
+	// AddNamedHandler đăng ký thêm một handler với tên riêng (VD: "audit-file", "loki-eu"),
+	// không thay thế handler đã đăng ký cùng tên.
+	//
+	// Tham số:
+	//   - name: string - tên handler, khác console, file, stack, "channel.*" và "file.*"; rỗng để
+	//     dùng tên của handler.Namer
+	//   - h: handler.Handler - instance của handler cần thêm
+	//   - opts: ...HandlerOption - tùy chọn quản lý handler
+	//
+	// Trả về:
+	//   - error: lỗi nếu tên không hợp lệ hoặc đã được đăng ký
+	AddNamedHandler(name string, h handler.Handler, opts ...HandlerOption) error
 
 	// RemoveHandler hủy đăng ký và đóng một handler.
 	//
@@ -59,8 +81,216 @@ type Manager interface {
 	//	userLogger2 := manager.GetLogger("UserService") // trả về cái đã tồn tại
 	GetLogger(context string) Logger
 
+	// Loggers trả về context của các logger đã tạo, đã sắp xếp.
+	//
+	// Trả về:
+	//   - []string: danh sách context
+	Loggers() []string
+
+	// RemoveLogger xóa logger của context khỏi manager. Handler dùng chung không bị đóng.
+	//
+	// Tham số:
+	//   - context: string - context của logger cần xóa
+	//
+	// Trả về:
+	//   - bool: false nếu chưa có logger nào với context này
+	RemoveLogger(context string) bool
+
+	// Reset xóa tất cả logger khỏi manager. Handler và service không bị ảnh hưởng.
+	Reset()
+
+	// Channel trả về logger ghi vào channel đã cấu hình trong Config.Channels.
+	//
+	// Tham số:
+	//   - name: string - tên channel (VD: "audit", "slack")
+	//
+	// Trả về:
+	//   - Logger: logger của context đầu tiên của channel, hoặc của context trùng tên channel
+	//
+	// Ví dụ:
+	//
+	//	manager.Channel("slack").Error("Payment gateway down")
+	Channel(name string) Logger
+
+	// ValidateConfig kiểm tra một cấu hình mới và báo cáo các thay đổi sẽ xảy ra
+	// nếu áp dụng nó, mà không thay đổi trạng thái của manager.
+	//
+	// Tham số:
+	//   - config: *Config - cấu hình mới cần kiểm tra
+	//
+	// Trả về:
+	//   - *ConfigDiff: báo cáo các handler, route và level sẽ thay đổi
+	//   - error: lỗi nếu cấu hình không hợp lệ
+	ValidateConfig(config *Config) (*ConfigDiff, error)
+
+	// ApplyConfig áp dụng một cấu hình mới cho manager và tất cả loggers đã tạo.
+	//
+	// Khi dryRun là true, method chỉ kiểm tra cấu hình bằng ValidateSyntax (không truy cập
+	// filesystem) và trả về báo cáo thay đổi mà không áp dụng.
+	//
+	// Tham số:
+	//   - config: *Config - cấu hình mới cần áp dụng
+	//   - dryRun: bool - chỉ báo cáo thay đổi, không áp dụng
+	//
+	// Trả về:
+	//   - *ConfigDiff: báo cáo các handler, route và level đã (hoặc sẽ) thay đổi
+	//   - error: lỗi nếu cấu hình không hợp lệ hoặc không thể tạo handler mới
+	ApplyConfig(config *Config, dryRun bool) (*ConfigDiff, error)
+
+	// ElevateLevel tạm thời thay đổi cấp độ log của một context và tự động khôi phục
+	// cấp độ trước đó sau khoảng thời gian đã cho.
+	//
+	// Tham số:
+	//   - context: string - context của logger cần thay đổi cấp độ
+	//   - level: handler.Level - cấp độ log tạm thời
+	//   - duration: time.Duration - thời gian áp dụng cấp độ tạm thời
+	//
+	// Trả về:
+	//   - error: lỗi nếu cấp độ hoặc thời gian không hợp lệ
+	ElevateLevel(context string, level handler.Level, duration time.Duration) error
+
+	// AddHook đăng ký một hook chạy trước khi mọi log entry được gửi đến handler.
+	//
+	// Tham số:
+	//   - hook: Hook - hàm bổ sung, sửa, bỏ hoặc đếm entry
+	AddHook(hook Hook)
+
+	// AddContextField đăng ký một giá trị được lấy từ context.Context thành field cho các method
+	// *Context của mọi logger, bổ sung cho Config.ContextFields.
+	//
+	// Tham số:
+	//   - key: interface{} - key của giá trị trong context
+	//   - field: string - tên field ghi giá trị
+	AddContextField(key interface{}, field string)
+
+	// AddContextExtractor đăng ký một hàm tính các field từ context.Context cho các method
+	// *Context của mọi logger (VD: trace ID của OpenTelemetry, xem package otellog).
+	//
+	// Tham số:
+	//   - extract: func(ctx context.Context) []Field - hàm tính field
+	AddContextExtractor(extract func(ctx context.Context) []Field)
+
+	// WithFields đăng ký các field cố định (VD: tên service, phiên bản, môi trường) được gắn vào
+	// mọi entry của mọi logger, bổ sung cho Config.Fields.
+	//
+	// Tham số:
+	//   - fields: ...Field - các field cố định
+	WithFields(fields ...Field)
+
+	// AddService đăng ký một thành phần chạy nền có vòng đời do manager quản lý.
+	//
+	// Tham số:
+	//   - name: string - tên duy nhất của service
+	//   - service: Service - service cần đăng ký
+	//
+	// Trả về:
+	//   - error: lỗi nếu tên đã được đăng ký hoặc không thể khởi động service
+	AddService(name string, service Service) error
+
+	// Start khởi động tất cả service đã đăng ký.
+	//
+	// Tham số:
+	//   - ctx: context.Context - giới hạn thời gian khởi động
+	//
+	// Trả về:
+	//   - error: lỗi của service đầu tiên không thể khởi động
+	Start(ctx context.Context) error
+
+	// Stop dừng tất cả service, timer và handler, chờ mọi goroutine nền kết thúc.
+	//
+	// Tham số:
+	//   - ctx: context.Context - giới hạn thời gian chờ
+	//
+	// Trả về:
+	//   - error: lỗi đầu tiên khi dừng, hoặc lỗi khi ctx hết hạn
+	Stop(ctx context.Context) error
+
+	// Readiness kiểm tra hệ thống log có đang ghi log bình thường hay không (handler lỗi, hàng
+	// đợi bất đồng bộ quá tải, service lỗi), dùng cho endpoint readiness của ứng dụng.
+	//
+	// Trả về:
+	//   - error: tổng hợp các vấn đề, luôn nil khi Config.Readiness.Enabled là false
+	Readiness() error
+
+	// SetErrorHandler đặt hàm nhận lỗi khi một handler không ghi được log entry, áp dụng cho mọi
+	// logger của manager. Mặc định lỗi được ghi ra stderr.
+	//
+	// Tham số:
+	//   - fn: ErrorHandler - hàm nhận lỗi, nil để ghi ra stderr
+	SetErrorHandler(fn ErrorHandler)
+
+	// ErrorCount trả về tổng số lần một handler không ghi được log entry.
+	//
+	// Trả về:
+	//   - uint64: số lỗi ghi log
+	ErrorCount() uint64
+
+	// SetMetricsObserver đặt observer nhận các sự kiện ghi log của mọi logger của manager.
+	//
+	// Tham số:
+	//   - o: MetricsObserver - observer nhận sự kiện, nil để tắt
+	SetMetricsObserver(o MetricsObserver)
+
+	// SetClock thay thế nguồn thời điểm của các entry do mọi logger ghi, dùng để cố định thời gian
+	// trong test.
+	//
+	// Tham số:
+	//   - clock: Clock - nguồn thời điểm, nil để dùng handler.SystemClock
+	SetClock(clock Clock)
+
+	// Stats trả về ảnh chụp các số liệu nội bộ: số entry, số lỗi và số liệu theo handler.
+	//
+	// Trả về:
+	//   - Stats: số liệu tại thời điểm gọi
+	Stats() Stats
+
+	// Handlers trả về tên các handler đã đăng ký, đã sắp xếp.
+	//
+	// Trả về:
+	//   - []HandlerType: tên các handler, kể cả stack
+	Handlers() []HandlerType
+
+	// RotateAll xoay vòng hoặc mở lại mọi file log do manager quản lý (xem handler.Rotator).
+	//
+	// Trả về:
+	//   - error: tổng hợp lỗi của các handler xoay vòng thất bại
+	RotateAll() error
+
+	// FlushAll ghi hết bộ đệm của mọi handler triển khai handler.Flusher.
+	//
+	// Trả về:
+	//   - error: tổng hợp lỗi của các handler ghi thất bại
+	FlushAll() error
+
+	// SetHandlerLevel đặt cấp độ tối thiểu riêng của handler triển khai handler.Leveler.
+	//
+	// Tham số:
+	//   - handlerType: HandlerType - tên handler đã đăng ký
+	//   - level: handler.Level - cấp độ tối thiểu mới
+	//
+	// Trả về:
+	//   - error: lỗi nếu handler chưa được đăng ký hoặc không triển khai handler.Leveler
+	SetHandlerLevel(handlerType HandlerType, level handler.Level) error
+
+	// Level trả về cấp độ log chung của manager (Config.Level).
+	//
+	// Trả về:
+	//   - handler.Level: cấp độ log chung hiện tại
+	Level() handler.Level
+
+	// SetLevel thay đổi cấp độ log chung và áp dụng ngay cho các logger không có cấp độ riêng.
+	//
+	// Tham số:
+	//   - level: handler.Level - cấp độ log chung mới
+	//
+	// Trả về:
+	//   - error: lỗi nếu cấp độ không hợp lệ
+	SetLevel(level handler.Level) error
+
 	// Close đóng tất cả các handlers và giải phóng tài nguyên.
 	//
+	// Tương đương Stop(context.Background()).
+	//
 	// Trả về:
 	//   - error: một lỗi nếu việc đóng handlers thất bại
 	Close() error
@@ -79,16 +309,35 @@ type Manager interface {
 //   - Thiết lập cấp độ log toàn cục
 //   - Quản lý danh sách loggers đã tạo
 type manager struct {
-	config   *Config                         // Cấu hình manager
-	handlers map[HandlerType]handler.Handler // Map các handlers theo loại
-	loggers  map[string]Logger               // Map các loggers đã tạo theo context
-	mu       sync.RWMutex                    // Mutex để đảm bảo thread-safety
+	config        *Config                         // Cấu hình manager
+	handlers      map[HandlerType]handler.Handler // Map các handlers theo loại
+	loggers       map[string]Logger               // Map các loggers đã tạo theo context
+	external      map[HandlerType]bool            // Các handler thuộc sở hữu bên ngoài, không được đóng
+	elevated      map[string]*elevation           // Các context đang được nâng cấp độ log tạm thời
+	stack         handler.Handler                 // Stack handler do manager tạo, không giữ tài nguyên riêng
+	wrapped       map[HandlerType]handler.Handler // Handler gốc của các handler được manager bọc (async, delivery) khi thêm qua AddHandler
+	sampler       *handler.Sampler                // Sampler dùng chung của các logger theo Config.Sampling (nil = tắt)
+	redactor      *handler.Redactor               // Redactor dùng chung của các logger theo Config.Redaction (nil = tắt)
+	pseudonymizer *handler.Pseudonymizer          // Pseudonymizer dùng chung của các logger theo Config.Pseudonymization (nil = tắt)
+	services      []namedService                  // Các service chạy nền theo thứ tự đăng ký
+	running       bool                            // Manager đã được Start và chưa Stop
+	timers        sync.WaitGroup                  // Các timer khôi phục cấp độ đang chờ hoặc đang chạy
+	hooks         []Hook                          // Các hook dùng chung của mọi logger, chỉ được thay thế (không sửa tại chỗ)
+	metadata      Hook                            // Hook gắn metadata của máy chủ và tiến trình theo Config.Metadata (nil = tắt)
+	sequence      atomic.Uint64                   // Số thứ tự của entry cuối cùng, dùng chung qua các lần thay đổi Config.Metadata
+	clock         Clock                           // Nguồn thời điểm của entry cho mọi logger (nil = time.Now)
+	contextFields []ContextField                  // Các field lấy từ context đăng ký qua AddContextField và AddContextExtractor, chỉ được thay thế
+	staticFields  []Field                         // Các field cố định đăng ký qua WithFields, chỉ được thay thế
+	errors        *errorReporter                  // Xử lý và đếm lỗi ghi log, dùng chung với các logger
+	metrics       *metricsReporter                // Observer nhận sự kiện ghi log, dùng chung với các logger
+	mu            sync.RWMutex                    // Mutex để đảm bảo thread-safety
 }
 
 // NewManager tạo và trả về một instance manager mới với cấu hình được chỉ định.
 //
 // Hàm này khởi tạo một manager với cấu hình được cung cấp. Config là bắt buộc
-// và phải được cung cấp để xác định handlers nào sẽ được khởi tạo.
+// và phải được cung cấp để xác định handlers nào sẽ được khởi tạo. NewManager panic
+// khi không thể khởi tạo handler (VD: không thể mở file log); dùng New để nhận lỗi.
 //
 // Tham số:
 //   - config: *Config - cấu hình cho manager (bắt buộc, không thể nil)
@@ -105,56 +354,201 @@ type manager struct {
 //	manager := log.NewManager(config)
 //	logger := manager.GetLogger("UserService")
 func NewManager(config *Config) Manager {
+	m, err := New(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return m
+}
+
+// New tạo một manager mới với cấu hình được chỉ định, trả về lỗi thay vì panic.
+//
+// Chỉ các handler được bật (hoặc thuộc stack, được channel tham chiếu) mới được tạo, nên
+// New(DefaultConfig()) tạo manager chỉ ghi ra console mà không cần đường dẫn file.
+//
+// Tham số:
+//   - config: *Config - cấu hình cho manager
+//
+// Trả về:
+//   - Manager: manager đã được khởi tạo
+//   - error: lỗi nếu config là nil hoặc không thể mở một file log; các handler đã mở được đóng lại
+//
+// Ví dụ:
+//
+//	manager, err := log.New(config)
+//	if err != nil {
+//	    return fmt.Errorf("khởi tạo logging: %w", err)
+//	}
+//	defer manager.Close()
+func New(config *Config) (Manager, error) {
 	if config == nil {
-		panic("config cannot be nil")
+		return nil, errors.New("config cannot be nil")
 	}
 
 	m := &manager{
-		config:   config,
-		handlers: make(map[HandlerType]handler.Handler),
-		loggers:  make(map[string]Logger),
+		config:        config,
+		handlers:      make(map[HandlerType]handler.Handler),
+		loggers:       make(map[string]Logger),
+		external:      make(map[HandlerType]bool),
+		elevated:      make(map[string]*elevation),
+		wrapped:       make(map[HandlerType]handler.Handler),
+		sampler:       newSampler(config),
+		redactor:      newRedactor(config),
+		pseudonymizer: newPseudonymizer(config),
+		errors:        &errorReporter{},
+		metrics:       &metricsReporter{},
 	}
 
+	m.metadata = metadataHook(config.Metadata, &m.sequence)
+
 	// Khởi tạo handlers theo cấu hình
-	m.initializeHandlers()
+	if err := m.initializeHandlers(); err != nil {
+		for _, h := range m.handlers {
+			h.Close()
+		}
+		return nil, err
+	}
 
-	return m
+	return m, nil
 }
 
 // AddHandler thêm một handler mới vào manager.
 //
 // Method này đăng ký một handler với loại đã cho. Nếu một handler với cùng loại
-// đã tồn tại, nó sẽ bị thay thế và handler cũ sẽ được đóng. Method này là thread-safe.
+// đã tồn tại, nó sẽ bị thay thế và handler cũ sẽ được đóng, trừ khi handler cũ
+// được đánh dấu WithExternalOwnership. Method này là thread-safe.
 // Handler mới cũng sẽ được thêm vào tất cả loggers đã tồn tại.
+// Nếu Config.Async có thiết lập cho handlerType, handler được bọc trong handler.AsyncHandler.
 //
 // Tham số:
 //   - handlerType: HandlerType - loại handler (console, file, stack)
 //   - handler: handler.Handler - triển khai handler cần thêm
+//   - opts: ...HandlerOption - tùy chọn quản lý handler (VD: WithExternalOwnership)
 //
 // Ví dụ:
 //
 //	// Thêm một file handler
 //	fileHandler, _ := handler.NewFileHandler("app.log", 10*1024*1024)
 //	manager.AddHandler(HandlerTypeFile, fileHandler)
-func (m *manager) AddHandler(handlerType HandlerType, handler handler.Handler) {
+//
+//	// Thêm một handler do bên gọi quản lý vòng đời
+//	manager.AddHandler("audit", auditHandler, log.WithExternalOwnership())
+func (m *manager) AddHandler(handlerType HandlerType, handler handler.Handler, opts ...HandlerOption) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	m.addHandler(handlerType, handler, applyHandlerOptions(opts))
+}
+
+// AddNamedHandler đăng ký thêm một handler với tên riêng, VD: nhiều file hoặc nhiều sink mạng
+// cùng lúc.
+//
+// Khác với AddHandler, tên đã được đăng ký không bị thay thế. Handler được gắn vào mọi logger
+// (kể cả logger tạo sau) trừ logger thuộc channel khác, và có thể được tham chiếu theo tên trong
+// Stack.Include, Routing, Channels, Async, Delivery và các bộ lọc. Method này là thread-safe.
+//
+// Tham số:
+//   - name: string - tên handler, không được là console, file, stack hoặc bắt đầu bằng "channel.", "file.";
+//     rỗng để dùng tên do handler tự đặt (xem handler.Namer)
+//   - h: handler.Handler - triển khai handler cần thêm
+//   - opts: ...HandlerOption - tùy chọn quản lý handler (VD: WithExternalOwnership)
+//
+// Trả về:
+//   - error: lỗi nếu tên không hợp lệ, đã được đăng ký hoặc h là nil
+//
+// Ví dụ:
+//
+//	auditFile, _ := handler.NewFileHandler("storage/logs/audit.log", 0)
+//	if err := manager.AddNamedHandler("audit-file", auditFile); err != nil {
+//	    return err
+//	}
+func (m *manager) AddNamedHandler(name string, h handler.Handler, opts ...HandlerOption) error {
+	if h == nil {
+		return errors.New("handler cannot be nil")
+	}
+	if name == "" {
+		name = handler.Name(h)
+	}
+	handlerType := HandlerType(name)
+	if strings.TrimSpace(name) == "" || !isCustomHandler(handlerType) {
+		return fmt.Errorf("invalid handler name %q: must be non-empty and not console, file, stack, channel.* or file.*", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.handlers[handlerType]; exists {
+		return fmt.Errorf("handler %q is already registered", name)
+	}
+	m.addHandler(handlerType, h, applyHandlerOptions(opts))
+	return nil
+}
+
+// addHandler đăng ký handler và gắn nó vào stack hoặc các logger. Phải được gọi khi đang giữ m.mu.
+func (m *manager) addHandler(handlerType HandlerType, handler handler.Handler, options handlerOptions) {
 	// Nếu handler cũ cùng loại tồn tại, đóng lại để tránh leak resource
-	if old, ok := m.handlers[handlerType]; ok {
-		old.Close()
+	if old, ok := m.handlers[handlerType]; ok && old != handler {
+		if m.wrapped[handlerType] == handler {
+			// Handler được thêm lại chỉ cần dừng bản bọc cũ, không đóng chính nó
+			releaseWrappers(old, handler)
+		} else {
+			m.closeOwned(handlerType, old)
+		}
+	}
+	if original, ok := m.wrappedOriginal(handler); ok {
+		// Bản bọc do manager tạo (VD: lấy qua GetHandler) được thêm lại: không bọc lần nữa
+		m.wrapped[handlerType] = original
+	} else if wrapped := wrapHandler(m.config, handlerType, handler); wrapped != handler {
+		m.wrapped[handlerType] = handler
+		handler = wrapped
+	} else {
+		delete(m.wrapped, handlerType)
 	}
 	m.handlers[handlerType] = handler
+	linkFallbacks(m.config, m.handlers)
+	linkErrors(m.errors, m.handlers)
+	if options.external {
+		m.external[handlerType] = true
+	} else {
+		delete(m.external, handlerType)
+	}
+
+	// Handler thuộc stack chỉ được ghi qua stack để tránh log bị trùng lặp
+	if m.inStack(handlerType) {
+		m.rebuildStack()
+		return
+	}
+
+	// Thêm handler vào tất cả loggers đã tồn tại mà không đóng handler cũ lần nữa;
+	// logger thuộc channel chỉ nhận các handler của channel
+	for context, lg := range m.loggers {
+		if !routesTo(m.config, context, handlerType) {
+			continue
+		}
+		if l, ok := lg.(*logger); ok {
+			l.attachHandler(handlerType, handler)
+		} else {
+			lg.AddHandler(handlerType, handler)
+		}
+	}
+}
 
-	// Thêm handler vào tất cả loggers đã tồn tại
-	for _, logger := range m.loggers {
-		logger.AddHandler(handlerType, handler)
+// wrappedOriginal trả về handler gốc nếu h là bản bọc manager đã tạo trong addHandler. Phải được
+// gọi khi đang giữ m.mu.
+func (m *manager) wrappedOriginal(h handler.Handler) (handler.Handler, bool) {
+	for handlerType, original := range m.wrapped {
+		if m.handlers[handlerType] == h {
+			return original, true
+		}
 	}
+	return nil, false
 }
 
 // RemoveHandler xóa một handler khỏi manager theo loại.
 //
 // Handler sẽ được đóng đúng cách trước khi xóa để đảm bảo tất cả các tài nguyên
-// được giải phóng. Method này là thread-safe.
+// được giải phóng, trừ khi handler được đánh dấu WithExternalOwnership.
+// Method này là thread-safe.
 //
 // Tham số:
 //   - handlerType: HandlerType - loại handler cần xóa
@@ -169,16 +563,78 @@ func (m *manager) RemoveHandler(handlerType HandlerType) {
 	defer m.mu.Unlock()
 	// Đóng và xóa handler nếu nó tồn tại
 	if handler, ok := m.handlers[handlerType]; ok {
-		handler.Close()
+		m.closeOwned(handlerType, handler)
 		delete(m.handlers, handlerType)
+		delete(m.external, handlerType)
+		delete(m.wrapped, handlerType)
+		linkFallbacks(m.config, m.handlers)
+
+		// Xóa handler khỏi tất cả loggers đã tồn tại, handler đã được đóng ở trên
+		for _, lg := range m.loggers {
+			if l, ok := lg.(*logger); ok {
+				l.detachHandler(handlerType)
+			} else {
+				lg.RemoveHandler(handlerType)
+			}
+		}
 
-		// Xóa handler khỏi tất cả loggers đã tồn tại
-		for _, logger := range m.loggers {
-			logger.RemoveHandler(handlerType)
+		if m.inStack(handlerType) {
+			m.rebuildStack()
 		}
 	}
 }
 
+// inStack kiểm tra một handler có được ghi qua stack do manager tạo hay không.
+//
+// Method này phải được gọi khi đang giữ lock của manager.
+func (m *manager) inStack(handlerType HandlerType) bool {
+	return handlerType != HandlerTypeStack && m.config.Stack.Enabled &&
+		m.stack != nil && m.handlers[HandlerTypeStack] == m.stack &&
+		m.config.Stack.Contains(handlerType)
+}
+
+// rebuildStack tạo lại stack handler từ các handler hiện tại và gắn nó vào các logger
+// đang sử dụng stack cũ. Stack cũ không được đóng vì các handler con vẫn đang được sử dụng.
+//
+// Method này phải được gọi khi đang giữ lock của manager.
+func (m *manager) rebuildStack() {
+	old := m.stack
+	m.stack = newStackHandler(m.config, m.handlers)
+	m.handlers[HandlerTypeStack] = m.stack
+
+	for _, lg := range m.loggers {
+		if l, ok := lg.(*logger); ok && l.GetHandler(HandlerTypeStack) == old {
+			l.attachHandler(HandlerTypeStack, m.stack)
+		}
+	}
+}
+
+// closeOwned đóng handler nếu nó thuộc quyền sở hữu của manager.
+//
+// Với handler thuộc sở hữu bên ngoài được manager bọc trong AsyncHandler, chỉ bản bọc
+// được dừng. Method này phải được gọi khi đang giữ lock của manager.
+//
+// Tham số:
+//   - handlerType: HandlerType - loại handler
+//   - h: handler.Handler - handler cần đóng
+//
+// Trả về:
+//   - error: lỗi từ handler.Close, hoặc nil nếu handler được sở hữu bên ngoài
+func (m *manager) closeOwned(handlerType HandlerType, h handler.Handler) error {
+	// Stack do manager tạo không giữ tài nguyên riêng; đóng nó sẽ đóng cả các handler con
+	// vốn được quản lý riêng lẻ (và có thể thuộc sở hữu bên ngoài)
+	if h == nil || (m.stack != nil && h == m.stack) {
+		return nil
+	}
+	if m.external[handlerType] {
+		if original := m.wrapped[handlerType]; original != nil {
+			releaseWrappers(h, original)
+		}
+		return nil
+	}
+	return h.Close()
+}
+
 // GetHandler trả về một handler đã đăng ký theo loại.
 //
 // Method này trả về một handler theo loại đã cho hoặc nil nếu không tìm thấy.
@@ -201,6 +657,29 @@ func (m *manager) GetHandler(handlerType HandlerType) handler.Handler {
 	return m.handlers[handlerType]
 }
 
+// Handlers trả về tên các handler đã đăng ký với manager, đã sắp xếp. Method này là
+// thread-safe.
+//
+// Trả về:
+//   - []HandlerType: tên các handler, kể cả stack
+//
+// Ví dụ:
+//
+//	for _, name := range manager.Handlers() {
+//	    fmt.Println(name, handler.Counters(manager.GetHandler(name)).BytesWritten)
+//	}
+func (m *manager) Handlers() []HandlerType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	types := make([]HandlerType, 0, len(m.handlers))
+	for handlerType := range m.handlers {
+		types = append(types, handlerType)
+	}
+	slices.Sort(types)
+	return types
+}
+
 // SetHandler thiết lập handler cho logger cụ thể.
 //
 // Method này thiết lập handler cho logger đã có sẵn.
@@ -218,10 +697,14 @@ func (m *manager) SetHandler(loggerContext string, handlerType HandlerType) {
 	defer m.mu.Unlock()
 
 	// Tìm logger theo context
-	if logger, exists := m.loggers[loggerContext]; exists {
+	if lg, exists := m.loggers[loggerContext]; exists {
 		// Tìm handler theo loại
 		if handler, ok := m.handlers[handlerType]; ok {
-			logger.AddHandler(handlerType, handler)
+			if l, ok := lg.(*logger); ok {
+				l.attachHandler(handlerType, handler)
+			} else {
+				lg.AddHandler(handlerType, handler)
+			}
 		}
 	}
 }
@@ -252,34 +735,34 @@ func (m *manager) GetLogger(context string) Logger {
 	}
 
 	// Tạo logger mới
-	logger := NewLogger(context)
+	var opts []LoggerOption
+	if m.config.EnableCaller {
+		opts = append(opts, WithCallerSkip(m.config.CallerSkip))
+	}
+	if m.config.RawMessages {
+		opts = append(opts, WithRawMessages())
+	}
+	opts = append(opts, WithFieldLimits(m.config.fieldLimits()), WithSampler(m.sampler), WithHooks(m.loggerHooks()...),
+		WithRetention(m.config.Retention.ClassFor(context)),
+		WithRedactor(m.redactor, m.config.Redaction.excluded()...), WithPseudonymizer(m.pseudonymizer), WithContextFields(m.loggerContextFields(m.config)...),
+		WithFields(m.loggerStaticFields(m.config)...), WithClock(m.clock),
+		withErrorReporter(m.errors), withMetricsReporter(m.metrics))
+	logger := NewLogger(context, opts...)
 
-	// Thiết lập Level từ config
-	logger.SetMinLevel(m.config.Level)
+	// Thiết lập Level từ config (hoặc Level của channel chứa context)
+	logger.SetMinLevel(levelOf(m.config, context))
 
-	// Bước 1: Luôn thêm Stack Handler nếu được enable
-	if m.config.Stack.Enabled {
-		if stackHandler := m.handlers[HandlerTypeStack]; stackHandler != nil {
-			logger.AddHandler(HandlerTypeStack, stackHandler)
+	// Chỉ gắn các handler theo cấu hình (và channel của context) để tránh log bị trùng lặp
+	// giữa stack và handler riêng lẻ
+	for _, handlerType := range loggerRoute(m.config, context) {
+		if h := m.handlers[handlerType]; h != nil {
+			logger.AddHandler(handlerType, h)
 		}
 	}
-
-	// Bước 2: Chỉ thêm individual handlers khi cần thiết
-	// Console: Chỉ thêm khi Stack không enable HOẶC Stack không có console
-	if !m.config.Stack.Enabled || (m.config.Console.Enabled && !m.config.Stack.Handlers.Console) {
-		if m.config.Console.Enabled {
-			if consoleHandler := m.handlers[HandlerTypeConsole]; consoleHandler != nil {
-				logger.AddHandler(HandlerTypeConsole, consoleHandler)
-			}
-		}
-	}
-
-	// File: Chỉ thêm khi Stack không enable HOẶC Stack không có file
-	if !m.config.Stack.Enabled || (m.config.File.Enabled && !m.config.Stack.Handlers.File) {
-		if m.config.File.Enabled {
-			if fileHandler := m.handlers[HandlerTypeFile]; fileHandler != nil {
-				logger.AddHandler(HandlerTypeFile, fileHandler)
-			}
+	// Handler tùy chỉnh đã đăng ký được gắn như với các logger đã tồn tại trong AddHandler
+	for handlerType, h := range m.handlers {
+		if isCustomHandler(handlerType) && !m.inStack(handlerType) && routesTo(m.config, context, handlerType) {
+			logger.AddHandler(handlerType, h)
 		}
 	}
 
@@ -289,10 +772,92 @@ func (m *manager) GetLogger(context string) Logger {
 	return logger
 }
 
+// Loggers trả về context của các logger đã được tạo qua GetLogger, đã sắp xếp.
+// Method này là thread-safe.
+//
+// Trả về:
+//   - []string: danh sách context
+//
+// Ví dụ:
+//
+//	for _, context := range manager.Loggers() {
+//	    fmt.Println(context)
+//	}
+func (m *manager) Loggers() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	contexts := make([]string, 0, len(m.loggers))
+	for context := range m.loggers {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// RemoveLogger xóa logger của context khỏi manager để được thu hồi bộ nhớ, dùng cho ứng dụng
+// chạy lâu tạo nhiều context ngắn hạn (VD: mỗi job một context). Method này là thread-safe.
+//
+// Handler dùng chung không bị đóng. Lần nâng cấp độ tạm thời đang chờ của context bị hủy.
+// Logger đã được lấy trước đó vẫn ghi log được nhưng không còn được ApplyConfig, AddHandler
+// hoặc AddHook cập nhật; GetLogger sau đó tạo logger mới.
+//
+// Tham số:
+//   - context: string - context của logger cần xóa
+//
+// Trả về:
+//   - bool: false nếu chưa có logger nào với context này
+//
+// Ví dụ:
+//
+//	jobLogger := manager.GetLogger("Job-" + jobID)
+//	defer manager.RemoveLogger("Job-" + jobID)
+func (m *manager) RemoveLogger(context string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.loggers[context]; !ok {
+		return false
+	}
+	m.removeLogger(context)
+	return true
+}
+
+// Reset xóa tất cả logger khỏi manager, như gọi RemoveLogger với mọi context. Handler, hook và
+// service không bị ảnh hưởng. Method này là thread-safe.
+//
+// Ví dụ:
+//
+//	// Giữa các test dùng chung một manager
+//	manager.Reset()
+func (m *manager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for context := range m.loggers {
+		m.removeLogger(context)
+	}
+}
+
+// removeLogger xóa logger của context và hủy lần nâng cấp độ đang chờ. Phải được gọi khi
+// đang giữ m.mu.
+func (m *manager) removeLogger(context string) {
+	if e := m.elevated[context]; e != nil {
+		if e.timer.Stop() {
+			m.timers.Done()
+		}
+		delete(m.elevated, context)
+	}
+	delete(m.loggers, context)
+}
+
 // Close đóng tất cả các handlers đã đăng ký và giải phóng tài nguyên của chúng.
 //
+// Các handler được đánh dấu WithExternalOwnership sẽ không bị đóng.
 // Method này nên được gọi khi ứng dụng đang đóng để đảm bảo
 // tất cả các file log được đóng đúng cách và tài nguyên được giải phóng.
+// Close tương đương Stop(context.Background()): các service chạy nền cũng được dừng và
+// Close chỉ trả về khi mọi goroutine nền đã kết thúc.
 //
 // Trả về:
 //   - error: lỗi đầu tiên gặp phải khi đóng handler, hoặc nil nếu tất cả đều đóng thành công
@@ -303,21 +868,49 @@ func (m *manager) GetLogger(context string) Logger {
 //	    fmt.Fprintf(os.Stderr, "Lỗi khi đóng manager: %v\n", err)
 //	}
 func (m *manager) Close() error {
+	return m.Stop(context.Background())
+}
+
+// closeHandlers hủy các lần nâng cấp độ đang chờ và đóng tất cả handler thuộc sở hữu của manager.
+//
+// Trả về:
+//   - error: lỗi đầu tiên gặp phải khi đóng handler
+func (m *manager) closeHandlers() error {
 	// Tạo một bản sao của map handlers để giảm thiểu thời gian giữ lock
 	m.mu.Lock()
 	handlersCopy := make(map[HandlerType]handler.Handler, len(m.handlers))
 	for k, v := range m.handlers {
 		handlersCopy[k] = v
 	}
+	external := m.external
+	wrapped := m.wrapped
+	stack := m.stack
 	// Xóa tất cả handlers để tránh sử dụng sau khi đóng
 	m.handlers = make(map[HandlerType]handler.Handler)
+	m.external = make(map[HandlerType]bool)
+	m.wrapped = make(map[HandlerType]handler.Handler)
+	// Hủy các lần nâng cấp độ log đang chờ khôi phục
+	for context, e := range m.elevated {
+		if e.timer.Stop() {
+			m.timers.Done()
+		}
+		delete(m.elevated, context)
+	}
 	m.mu.Unlock()
 
 	// Đóng từng handler, theo dõi lỗi đầu tiên
 	var firstErr error
 	for handlerType, handler := range handlersCopy {
-		// Bỏ qua handler nil
-		if handler == nil {
+		// Bỏ qua handler nil, handler thuộc sở hữu bên ngoài và stack do manager tạo
+		// (các handler con của stack được đóng riêng lẻ)
+		if handler == nil || (stack != nil && handler == stack) {
+			continue
+		}
+		if external[handlerType] {
+			// Chỉ dừng các bản bọc do manager tạo, handler bên trong thuộc bên gọi
+			if original := wrapped[handlerType]; original != nil {
+				releaseWrappers(handler, original)
+			}
 			continue
 		}
 		if err := handler.Close(); err != nil && firstErr == nil {
@@ -327,31 +920,826 @@ func (m *manager) Close() error {
 	return firstErr
 }
 
-// initializeHandlers khởi tạo tất cả 3 handlers bắt buộc.
+// ValidateConfig kiểm tra một cấu hình mới và báo cáo các thay đổi sẽ xảy ra.
+//
+// Method này tương đương với ApplyConfig(config, true): cấu hình chỉ được kiểm tra bằng
+// Config.ValidateSyntax nên không tạo file thử quyền ghi trong các thư mục log. Method này là
+// thread-safe.
+//
+// Tham số:
+//   - config: *Config - cấu hình mới cần kiểm tra
+//
+// Trả về:
+//   - *ConfigDiff: báo cáo các handler, route và level sẽ thay đổi
+//   - error: lỗi nếu cấu hình không hợp lệ
+//
+// Ví dụ:
 //
-// Method này luôn tạo đầy đủ 3 handlers: console, file và stack theo config.
-func (m *manager) initializeHandlers() {
-	// Bắt buộc khởi tạo Console Handler
-	consoleHandler := handler.NewConsoleHandler(m.config.Console.Colored)
-	m.handlers[HandlerTypeConsole] = consoleHandler
+//	diff, err := manager.ValidateConfig(newConfig)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(diff)
+func (m *manager) ValidateConfig(config *Config) (*ConfigDiff, error) {
+	return m.ApplyConfig(config, true)
+}
 
-	fileHandler, err := handler.NewFileHandler(m.config.File.Path, m.config.File.MaxSize)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to create file handler: %v", err))
+// ApplyConfig áp dụng một cấu hình mới cho manager và tất cả loggers đã tạo.
+//
+// Chỉ các handler có cấu hình thay đổi mới được tạo lại; các handler không đổi
+// được giữ nguyên. Handler cũ bị thay thế sẽ được đóng sau khi tất cả loggers
+// đã chuyển sang handler mới. Các handler tùy chỉnh thêm qua AddHandler không bị ảnh hưởng.
+// Khi dryRun là true, cấu hình chỉ được kiểm tra bằng Config.ValidateSyntax để xem trước không
+// truy cập filesystem; ngược lại Config.Validate cũng kiểm tra các thư mục log. Method này là
+// thread-safe.
+//
+// Tham số:
+//   - config: *Config - cấu hình mới cần áp dụng
+//   - dryRun: bool - chỉ báo cáo thay đổi, không áp dụng
+//
+// Trả về:
+//   - *ConfigDiff: báo cáo các handler, route và level đã (hoặc sẽ) thay đổi
+//   - error: lỗi nếu cấu hình không hợp lệ hoặc không thể tạo handler mới
+//
+// Ví dụ:
+//
+//	// Xem trước tác động của hot-reload
+//	diff, _ := manager.ApplyConfig(newConfig, true)
+//	fmt.Println(diff)
+//
+//	// Áp dụng thực sự
+//	if _, err := manager.ApplyConfig(newConfig, false); err != nil {
+//	    return err
+//	}
+func (m *manager) ApplyConfig(config *Config, dryRun bool) (*ConfigDiff, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	validate := config.Validate
+	if dryRun {
+		validate = config.ValidateSyntax
+	}
+	if err := validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	diff := m.diffConfig(config)
+	diff.DryRun = dryRun
+	if dryRun || !diff.HasChanges() {
+		return diff, nil
+	}
+
+	// Tạo các handler mới trước khi thay đổi trạng thái để lỗi không để lại manager dở dang
+	handlers := make(map[HandlerType]handler.Handler, len(m.handlers))
+	for k, v := range m.handlers {
+		handlers[k] = v
+	}
+	var replaced, created []handler.Handler
+	var newStack *handler.StackHandler
+	for _, change := range diff.Handlers {
+		if change.Type == HandlerTypeFile {
+			if old := handlers[HandlerTypeFile]; old != nil && !m.external[HandlerTypeFile] {
+				replaced = append(replaced, old)
+			}
+			delete(handlers, HandlerTypeFile)
+			if change.Action == HandlerActionRemove {
+				continue
+			}
+			fileHandler, err := newMainFileHandler(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create file handler: %w", err)
+			}
+			created = append(created, fileHandler)
+			handlers[HandlerTypeFile] = wrapHandler(config, HandlerTypeFile, fileHandler)
+		}
+	}
+	for _, change := range diff.Handlers {
+		name, isChannel := strings.CutPrefix(string(change.Type), channelHandlerPrefix)
+		if !isChannel {
+			continue
+		}
+		if old := handlers[change.Type]; old != nil && !m.external[change.Type] {
+			replaced = append(replaced, old)
+		}
+		delete(handlers, change.Type)
+		if change.Action == HandlerActionRemove {
+			continue
+		}
+		channelFile, err := newChannelFile(config, config.Channels[name])
+		if err != nil {
+			// Đóng các file vừa được tạo để lỗi không để lại file handler bị rò rỉ
+			for _, h := range created {
+				h.Close()
+			}
+			return nil, fmt.Errorf("failed to create file handler for channel %s: %w", name, err)
+		}
+		created = append(created, channelFile)
+		handlers[change.Type] = wrapHandler(config, change.Type, channelFile)
+	}
+	for _, change := range diff.Handlers {
+		name, isFile := strings.CutPrefix(string(change.Type), fileHandlerPrefix)
+		if !isFile {
+			continue
+		}
+		if old := handlers[change.Type]; old != nil && !m.external[change.Type] {
+			replaced = append(replaced, old)
+		}
+		delete(handlers, change.Type)
+		if change.Action == HandlerActionRemove {
+			continue
+		}
+		outputFile, err := newFileOutput(config, config.Files[name])
+		if err != nil {
+			for _, h := range created {
+				h.Close()
+			}
+			return nil, fmt.Errorf("failed to create file handler for file %s: %w", name, err)
+		}
+		created = append(created, outputFile)
+		handlers[change.Type] = wrapHandler(config, change.Type, outputFile)
+	}
+	for _, change := range diff.Handlers {
+		switch change.Type {
+		case HandlerTypeConsole:
+			if old := handlers[HandlerTypeConsole]; old != nil && !m.external[HandlerTypeConsole] {
+				replaced = append(replaced, old)
+			}
+			delete(handlers, HandlerTypeConsole)
+			if change.Action != HandlerActionRemove {
+				handlers[HandlerTypeConsole] = wrapHandler(config, HandlerTypeConsole, newConsoleHandler(config))
+			}
+		case HandlerTypeStack:
+			// Stack cũ không giữ tài nguyên riêng; không đóng nó vì Close sẽ đóng cả các handler con
+			// có thể vẫn đang được tái sử dụng
+			newStack = newStackHandler(config, handlers)
+			handlers[HandlerTypeStack] = newStack
+		}
+	}
+
+	oldConfig := m.config
+	if oldConfig.Sampling != config.Sampling {
+		// Bộ đếm được đặt lại khi cấu hình lấy mẫu thay đổi
+		m.sampler = newSampler(config)
+	}
+	m.redactor = newRedactor(config)
+	if !pseudonymizationEqual(oldConfig.Pseudonymization, config.Pseudonymization) {
+		m.pseudonymizer = newPseudonymizer(config)
+	}
+	if oldConfig.Metadata != config.Metadata {
+		m.metadata = metadataHook(config.Metadata, &m.sequence)
+	}
+	oldInclude := m.config.Stack.Include
+	m.config = config
+	m.handlers = handlers
+	linkFallbacks(config, handlers)
+	linkErrors(m.errors, handlers)
+	if newStack != nil {
+		m.stack = newStack
+	}
+	for _, change := range diff.Handlers {
+		delete(m.external, change.Type)
+		delete(m.wrapped, change.Type)
+	}
+
+	// Handler tùy chỉnh được tham chiếu bởi Stack.Include được ghi qua stack,
+	// các handler vừa bị loại khỏi stack được gắn trực tiếp trở lại
+	managed := []HandlerType{HandlerTypeConsole, HandlerTypeFile, HandlerTypeStack}
+	managed = append(append(managed, fileOutputTypes(oldConfig)...), fileOutputTypes(config)...)
+	custom := make(map[HandlerType]handler.Handler)
+	for _, name := range append(append([]string(nil), oldInclude...), config.Stack.Include...) {
+		handlerType := HandlerType(name)
+		if handlerType == HandlerTypeConsole || handlerType == HandlerTypeFile || handlerType == HandlerTypeStack {
+			continue
+		}
+		managed = append(managed, handlerType)
+		if h := handlers[handlerType]; h != nil && !(config.Stack.Enabled && config.Stack.Contains(handlerType)) {
+			custom[handlerType] = h
+		}
+	}
+
+	// File riêng và handler tùy chỉnh của các channel (cũ và mới) được gắn lại theo channel
+	// của từng logger
+	var channelManaged []HandlerType
+	for _, c := range []*Config{oldConfig, config} {
+		for name, channel := range c.Channels {
+			for _, handlerType := range channelTypes(name, channel) {
+				if channelOnly(c, handlerType) {
+					channelManaged = append(channelManaged, handlerType)
+				}
+			}
+		}
+		for _, context := range c.Contexts {
+			for _, h := range context.Handlers {
+				if channelOnly(c, HandlerType(h)) {
+					channelManaged = append(channelManaged, HandlerType(h))
+				}
+			}
+		}
+	}
+
+	// Cập nhật tất cả loggers đã tồn tại theo cấu hình mới
+	contextFields := m.loggerContextFields(config)
+	staticFields := m.loggerStaticFields(config)
+	hooks := m.loggerHooks()
+	for context, lg := range m.loggers {
+		// Context đang được nâng cấp độ tạm thời sẽ khôi phục về cấp độ mới khi hết hạn
+		if e := m.elevated[context]; e != nil {
+			e.previous = levelOf(config, context)
+		} else {
+			lg.SetMinLevel(levelOf(config, context))
+		}
+		if l, ok := lg.(*logger); ok {
+			l.setCaller(config.EnableCaller, config.CallerSkip)
+			l.setRawMessages(config.RawMessages)
+			l.setFieldLimits(config.fieldLimits())
+			l.setSampler(m.sampler)
+			l.setRetention(config.Retention.ClassFor(context))
+			l.setRedactor(m.redactor, config.Redaction.excluded())
+			l.setPseudonymizer(m.pseudonymizer)
+			l.setContextFields(contextFields)
+			l.setStaticFields(staticFields)
+			l.setHooks(hooks)
+			types := append(append([]HandlerType(nil), managed...), channelManaged...)
+			routed := make(map[HandlerType]handler.Handler, len(custom))
+			if dedicated, ok := dedicatedRoute(config, context); ok {
+				// Logger thuộc channel (hoặc có handler riêng trong Config.Contexts) chỉ ghi đến
+				// các handler đó
+				for handlerType := range handlers {
+					types = append(types, handlerType)
+				}
+				for _, handlerType := range dedicated {
+					if h := handlers[handlerType]; h != nil {
+						routed[handlerType] = h
+					}
+				}
+				l.resetHandlers(types, routed)
+				continue
+			}
+
+			// Logger vừa rời channel, hoặc handler không còn chỉ dành cho channel, được gắn lại
+			// các handler tùy chỉnh dùng chung
+			_, wasDedicated := dedicatedRoute(oldConfig, context)
+			for handlerType, h := range handlers {
+				if !isCustomHandler(handlerType) || !routesTo(config, context, handlerType) ||
+					(config.Stack.Enabled && config.Stack.Contains(handlerType)) {
+					continue
+				}
+				if wasDedicated || containsType(channelManaged, handlerType) {
+					routed[handlerType] = h
+				}
+			}
+			for handlerType, h := range custom {
+				routed[handlerType] = h
+			}
+			for _, handlerType := range routeTypes(config) {
+				if h := handlers[handlerType]; h != nil {
+					routed[handlerType] = h
+				}
+			}
+			l.resetHandlers(types, routed)
+		}
+	}
+
+	for _, old := range replaced {
+		old.Close()
+	}
+
+	return diff, nil
+}
+
+// diffConfig tính toán báo cáo thay đổi giữa cấu hình hiện tại và cấu hình mới.
+//
+// Method này phải được gọi khi đang giữ lock của manager.
+//
+// Tham số:
+//   - config: *Config - cấu hình mới
+//
+// Trả về:
+//   - *ConfigDiff: báo cáo thay đổi
+func (m *manager) diffConfig(config *Config) *ConfigDiff {
+	old := m.config
+	diff := &ConfigDiff{Fields: diffFields(old, config)}
+
+	consoleChanged := old.Console.Colored != config.Console.Colored || old.Console.ForceColor != config.Console.ForceColor ||
+		old.Console.Colors != config.Console.Colors ||
+		strings.Join(old.Console.GroupBy, ",") != strings.Join(config.Console.GroupBy, ",") ||
+		old.Console.OmitTimestamp != config.Console.OmitTimestamp ||
+		old.Console.StderrLevel != config.Console.StderrLevel || old.Console.Output != config.Console.Output ||
+		wrapperChanged(old, config, HandlerTypeConsole)
+	// Cảnh báo tăng trưởng, bảo vệ dung lượng, nén, giới hạn file sao lưu, fsync, chữ ký, mã hóa
+	// và thông tin SIEM áp dụng cho cả file chính và file của các channel
+	fileOptionsChanged := old.File.GrowthAlert != config.File.GrowthAlert || old.File.DiskGuard != config.File.DiskGuard ||
+		old.File.Compression != config.File.Compression ||
+		old.File.MaxBackups != config.File.MaxBackups || old.File.MaxAge != config.File.MaxAge ||
+		old.File.Sync != config.File.Sync || old.File.SyncOnLevel != config.File.SyncOnLevel ||
+		old.File.Signing != config.File.Signing || old.File.Encryption != config.File.Encryption || old.SIEM != config.SIEM
+	fileChanged := old.File.Path != config.File.Path || old.File.MaxSize != config.File.MaxSize || old.File.Format != config.File.Format ||
+		fileOptionsChanged || wrapperChanged(old, config, HandlerTypeFile)
+	consoleAction := handlerAction(old, config, HandlerTypeConsole, consoleChanged)
+	fileAction := handlerAction(old, config, HandlerTypeFile, fileChanged)
+	stackChanged := consoleAction != "" || fileAction != "" || !equalTypes(old.Stack.Members(), config.Stack.Members()) ||
+		!maps.Equal(old.Stack.Levels, config.Stack.Levels) || old.Stack.Mode != config.Stack.Mode ||
+		old.Stack.Concurrency != config.Stack.Concurrency
+
+	// Stack được tạo lại khi một file trong Config.Files thuộc stack được tạo lại
+	var files []HandlerChange
+	for _, name := range unionKeys(old.Files, config.Files) {
+		o, oldOK := old.Files[name]
+		n, newOK := config.Files[name]
+		handlerType := FileHandlerType(name)
+		switch {
+		case !oldOK:
+			files = append(files, HandlerChange{Type: handlerType, Action: HandlerActionCreate})
+		case !newOK:
+			files = append(files, HandlerChange{Type: handlerType, Action: HandlerActionRemove})
+		case o != n || fileOptionsChanged || wrapperChanged(old, config, handlerType):
+			files = append(files, HandlerChange{Type: handlerType, Action: HandlerActionRecreate})
+			stackChanged = stackChanged || config.Stack.Contains(handlerType)
+		}
+	}
+
+	if consoleAction != "" {
+		diff.Handlers = append(diff.Handlers, HandlerChange{Type: HandlerTypeConsole, Action: consoleAction})
+	}
+	if fileAction != "" {
+		diff.Handlers = append(diff.Handlers, HandlerChange{Type: HandlerTypeFile, Action: fileAction})
+	}
+	if stackChanged {
+		diff.Handlers = append(diff.Handlers, HandlerChange{Type: HandlerTypeStack, Action: HandlerActionRecreate})
+	}
+	for _, name := range unionKeys(old.Channels, config.Channels) {
+		o, n := old.Channels[name], config.Channels[name]
+		handlerType := ChannelHandlerType(name)
+		switch {
+		case o.Path == "" && n.Path != "":
+			diff.Handlers = append(diff.Handlers, HandlerChange{Type: handlerType, Action: HandlerActionCreate})
+		case o.Path != "" && n.Path == "":
+			diff.Handlers = append(diff.Handlers, HandlerChange{Type: handlerType, Action: HandlerActionRemove})
+		case o.Path != "" && (o.Path != n.Path || o.MaxSize != n.MaxSize || o.Format != n.Format || fileOptionsChanged || wrapperChanged(old, config, handlerType)):
+			diff.Handlers = append(diff.Handlers, HandlerChange{Type: handlerType, Action: HandlerActionRecreate})
+		}
+	}
+	diff.Handlers = append(diff.Handlers, files...)
+
+	contexts := make([]string, 0, len(m.loggers))
+	for context := range m.loggers {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+
+	for _, context := range contexts {
+		oldRoute, newRoute := loggerRoute(old, context), loggerRoute(config, context)
+		if !equalTypes(oldRoute, newRoute) {
+			diff.Routes = append(diff.Routes, RouteChange{Context: context, Old: oldRoute, New: newRoute})
+		}
+		if levelOf(old, context) != levelOf(config, context) {
+			diff.Levels = append(diff.Levels, context)
+		}
+	}
+
+	return diff
+}
+
+// handlerAction trả về thay đổi của console hoặc file handler giữa hai cấu hình: tạo khi handler
+// bắt đầu được dùng, xóa khi không còn được dùng (xem Config.uses), tạo lại khi thiết lập thay đổi.
+//
+// Trả về:
+//   - HandlerAction: thay đổi của handler, rỗng nếu không thay đổi
+func handlerAction(old, new *Config, handlerType HandlerType, changed bool) HandlerAction {
+	oldUsed, newUsed := old.uses(handlerType), new.uses(handlerType)
+	switch {
+	case !oldUsed && newUsed:
+		return HandlerActionCreate
+	case oldUsed && !newUsed:
+		return HandlerActionRemove
+	case newUsed && changed:
+		return HandlerActionRecreate
+	}
+	return ""
+}
+
+// routeTypes trả về danh sách các handler do cấu hình quản lý mà một logger mới
+// sẽ được gắn vào theo cấu hình đã cho.
+//
+// Logic này phải khớp với GetLogger để báo cáo route chính xác.
+//
+// Tham số:
+//   - config: *Config - cấu hình cần tính toán
+//
+// Trả về:
+//   - []HandlerType: danh sách handler theo thứ tự stack, console, file, rồi các file trong Config.Files
+func routeTypes(config *Config) []HandlerType {
+	var types []HandlerType
+
+	if config.Stack.Enabled {
+		types = append(types, HandlerTypeStack)
+	}
+	if config.Console.Enabled && (!config.Stack.Enabled || !config.Stack.Contains(HandlerTypeConsole)) {
+		types = append(types, HandlerTypeConsole)
+	}
+	if config.File.Enabled && (!config.Stack.Enabled || !config.Stack.Contains(HandlerTypeFile)) {
+		types = append(types, HandlerTypeFile)
+	}
+	for _, handlerType := range fileOutputTypes(config) {
+		if (!config.Stack.Enabled || !config.Stack.Contains(handlerType)) && !channelOnly(config, handlerType) {
+			types = append(types, handlerType)
+		}
 	}
 
-	m.handlers[HandlerTypeFile] = fileHandler
+	return types
+}
+
+// initializeHandlers khởi tạo các handler theo cấu hình.
+//
+// Console và file handler chỉ được tạo khi được dùng (xem Config.uses); file của các channel, các
+// file trong Config.Files và stack handler luôn được tạo. Khi có lỗi, các handler đã được tạo
+// vẫn nằm trong m.handlers để bên gọi đóng.
+//
+// Trả về:
+//   - error: lỗi nếu không thể mở một file log
+func (m *manager) initializeHandlers() error {
+	if m.config.uses(HandlerTypeConsole) {
+		consoleHandler := newConsoleHandler(m.config)
+		m.handlers[HandlerTypeConsole] = wrapHandler(m.config, HandlerTypeConsole, consoleHandler)
+	}
+
+	if m.config.uses(HandlerTypeFile) {
+		fileHandler, err := newMainFileHandler(m.config)
+		if err != nil {
+			return fmt.Errorf("failed to create file handler: %w", err)
+		}
+		m.handlers[HandlerTypeFile] = wrapHandler(m.config, HandlerTypeFile, fileHandler)
+	}
+
+	// Khởi tạo file riêng của các channel
+	for name, channel := range m.config.Channels {
+		if channel.Path == "" {
+			continue
+		}
+		channelFile, err := newChannelFile(m.config, channel)
+		if err != nil {
+			return fmt.Errorf("failed to create file handler for channel %s: %w", name, err)
+		}
+		handlerType := ChannelHandlerType(name)
+		m.handlers[handlerType] = wrapHandler(m.config, handlerType, channelFile)
+	}
+
+	// Khởi tạo các file bổ sung theo Config.Files
+	for name, output := range m.config.Files {
+		outputFile, err := newFileOutput(m.config, output)
+		if err != nil {
+			return fmt.Errorf("failed to create file handler for file %s: %w", name, err)
+		}
+		handlerType := FileHandlerType(name)
+		m.handlers[handlerType] = wrapHandler(m.config, handlerType, outputFile)
+	}
+
+	linkFallbacks(m.config, m.handlers)
+	linkErrors(m.errors, m.handlers)
+
 	// Khởi tạo Stack Handler với cấu hình
+	m.stack = newStackHandler(m.config, m.handlers)
+	m.handlers[HandlerTypeStack] = m.stack
+	return nil
+}
+
+// newConsoleHandler tạo console handler theo cấu hình.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập console
+//
+// Trả về:
+//   - *handler.ConsoleHandler: console handler đã được cấu hình
+func newConsoleHandler(config *Config) *handler.ConsoleHandler {
+	console := handler.NewConsoleHandler(config.Console.Colored)
+	if len(config.Console.GroupBy) > 0 {
+		console.SetGroupBy(config.Console.GroupBy...)
+	}
+	console.SetOmitTimestamp(config.Console.OmitTimestamp)
+	if config.Console.StderrLevel != "" {
+		// Cấu hình đã được Validate nên cấp độ hợp lệ
+		level, _ := handler.ParseLevel(config.Console.StderrLevel)
+		console.SetStderrLevel(level)
+	}
+	if w, ok := handler.LookupConsoleWriter(config.Console.Output); ok {
+		console.SetOutput(w, w)
+	}
+	if config.Console.Colored && !config.Console.ForceColor {
+		console.SetColorMode(handler.ColorAuto)
+	}
+	if config.Console.Colors != (ColorsConfig{}) {
+		console.SetColorScheme(config.Console.Colors.scheme())
+	}
+	return console
+}
+
+// newMainFileHandler tạo file handler chính theo Config.File, gồm cả định dạng dòng log.
+//
+// Tham số:
+//   - config: *Config - cấu hình của manager
+//
+// Trả về:
+//   - *handler.FileHandler: file handler đã được cấu hình
+//   - error: lỗi nếu không thể mở file hoặc không lấy được khóa mã hóa
+func newMainFileHandler(config *Config) (*handler.FileHandler, error) {
+	fileHandler, err := newFileHandler(config, config.File.Path, config.File.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	// Cấu hình đã được Validate nên định dạng hợp lệ
+	format, _ := handler.ParseFormat(config.File.Format)
+	fileHandler.SetFormat(format)
+	return fileHandler, nil
+}
+
+// newFileHandler tạo file handler với cảnh báo tốc độ tăng trưởng, bảo vệ dung lượng đĩa, codec
+// nén, giới hạn file sao lưu, fsync, chữ ký HMAC và mã hóa theo cấu hình.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập chung của các file log
+//   - path: string - đường dẫn file log
+//   - maxSize: int64 - kích thước tối đa trước khi xoay vòng
+//
+// Trả về:
+//   - *handler.FileHandler: file handler đã được cấu hình
+//   - error: lỗi nếu không thể mở file hoặc không lấy được khóa mã hóa
+func newFileHandler(config *Config, path string, maxSize int64) (*handler.FileHandler, error) {
+	fileHandler, err := handler.NewFileHandler(path, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	fileHandler.SetGrowthAlert(handler.GrowthAlert{
+		MaxRate: config.File.GrowthAlert.MaxRate,
+		Period:  config.File.GrowthAlert.Period,
+	})
+	fileHandler.SetDiskGuard(handler.DiskGuard{
+		MinFree:    config.File.DiskGuard.MinFree,
+		MaxDirSize: config.File.DiskGuard.MaxDirSize,
+		Interval:   config.File.DiskGuard.Interval,
+	})
+	if codec, ok := handler.LookupCodec(config.File.Compression); ok {
+		fileHandler.SetCompression(codec)
+	}
+	fileHandler.SetBackupRetention(handler.BackupRetention{
+		MaxBackups: config.File.MaxBackups,
+		MaxAge:     time.Duration(config.File.MaxAge) * 24 * time.Hour,
+	})
+	if config.File.SyncOnLevel != "" {
+		// Cấu hình đã được Validate nên cấp độ hợp lệ
+		level, _ := handler.ParseLevel(config.File.SyncOnLevel)
+		fileHandler.SetSyncLevel(level)
+	} else if config.File.Sync {
+		fileHandler.SetSyncLevel(handler.DebugLevel)
+	}
+	fileHandler.SetDevice(handler.Device{Vendor: config.SIEM.Vendor, Product: config.SIEM.Product, Version: config.SIEM.Version})
+	if config.File.Signing.Enabled() {
+		fileHandler.SetSigning(handler.Signing{
+			Key:      []byte(config.File.Signing.Key),
+			Records:  config.File.Signing.Records,
+			Segments: config.File.Signing.Segments,
+		})
+	}
+	if config.File.Encryption.Enabled() {
+		key, err := config.File.Encryption.source().load()
+		if err == nil {
+			err = fileHandler.SetEncryption(key)
+		}
+		if err != nil {
+			fileHandler.Close()
+			return nil, err
+		}
+	}
+	return fileHandler, nil
+}
+
+// newStackHandler tạo stack handler chỉ chứa các handler con được bật trong cấu hình.
+//
+// Handler được tham chiếu trong Stack.Include nhưng chưa được đăng ký sẽ bị bỏ qua. Mỗi handler
+// con được đặt tên theo HandlerType (VD: "console", "file.audit") để liệt kê qua
+// StackHandler.Children.
+//
+// Tham số:
+//   - config: *Config - cấu hình xác định các handler con
+//   - handlers: map[HandlerType]handler.Handler - các handler đã đăng ký, tra cứu theo tên
+//
+// Trả về:
+//   - *handler.StackHandler: stack handler đã được cấu hình
+func newStackHandler(config *Config, handlers map[HandlerType]handler.Handler) *handler.StackHandler {
 	stackHandler := handler.NewStackHandler()
+	if mode, err := handler.ParseDispatchMode(config.Stack.Mode); err == nil {
+		stackHandler.SetMode(mode)
+	}
+	stackHandler.SetConcurrency(config.Stack.Concurrency)
+
+	// Chỉ thêm handlers vào stack khi được cấu hình và đã được đăng ký. Members không trùng lặp
+	// nên AddChild không trả về lỗi
+	for _, member := range config.Stack.Members() {
+		if h := handlers[member]; h != nil {
+			_ = stackHandler.AddChild(string(member), h)
+			if level, err := handler.ParseLevel(config.Stack.Levels[string(member)]); err == nil {
+				_ = stackHandler.SetChildLevel(string(member), level)
+			}
+		}
+	}
+
+	return stackHandler
+}
+
+// newSampler tạo sampler dùng chung theo Config.Sampling.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập lấy mẫu
+//
+// Trả về:
+//   - *handler.Sampler: sampler mới, hoặc nil nếu lấy mẫu bị tắt
+func newSampler(config *Config) *handler.Sampler {
+	if !config.Sampling.Enabled() {
+		return nil
+	}
+	return handler.NewSampler(config.Sampling.options())
+}
+
+// wrapHandler bọc h theo thiết lập Delivery và Async của handlerType trong cấu hình.
+//
+// Delivery được ưu tiên và dùng số worker, hàng đợi từ Async (nếu có) cho giai đoạn bất
+// đồng bộ. Stack không được bọc; handler của package handler đã là wrapper (VD: LevelHandler)
+// vẫn được bọc như mọi handler khác. addHandler dùng m.wrapped để không bọc lại bản bọc do
+// chính manager tạo.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập async và delivery
+//   - handlerType: HandlerType - tên của handler
+//   - h: handler.Handler - handler cần bọc
+//
+// Trả về:
+//   - handler.Handler: handler đã được bọc, hoặc chính h nếu không cần bọc
+func wrapHandler(config *Config, handlerType HandlerType, h handler.Handler) handler.Handler {
+	if h == nil || handlerType == HandlerTypeStack {
+		return h
+	}
+
+	// Middleware bọc sát handler để các bộ lọc và hàng đợi bên ngoài áp dụng như với handler gốc.
+	// Cấu hình đã được Validate nên mọi middleware đã được đăng ký
+	if names := config.Middleware[string(handlerType)]; len(names) > 0 {
+		chain := make([]handler.Middleware, 0, len(names))
+		for _, name := range names {
+			if middleware, ok := handler.LookupMiddleware(name); ok {
+				chain = append(chain, middleware)
+			}
+		}
+		h = handler.Chain(chain...)(h)
+	}
+	// Gộp dòng sát handler, sau khi bộ lọc field đã định dạng lại thông điệp
+	if fold, ok := config.Fold[string(handlerType)]; ok {
+		// Cấu hình đã được Validate nên mode hợp lệ
+		mode, _ := handler.ParseFoldMode(fold)
+		h = handler.NewFoldHandler(h, handler.FoldOptions{Mode: mode, Limits: config.fieldLimits()})
+	}
+	// Lọc field trước khi đưa vào hàng đợi để entry được lưu trữ tạm đã được lọc
+	if filter, ok := config.FieldFilters[string(handlerType)]; ok {
+		h = handler.NewFieldFilterHandler(h, handler.FieldFilter{
+			Allow:  filter.Allow,
+			Deny:   filter.Deny,
+			Limits: config.fieldLimits(),
+		})
+	}
+	// Lọc entry và định tuyến bọc ngoài cùng để điều kiện được đánh giá trên đầy đủ field và
+	// entry bị bỏ không chiếm chỗ trong hàng đợi
+	if filter, ok := config.Filters[string(handlerType)]; ok {
+		// Cấu hình đã được Validate nên các điều kiện biên dịch được
+		if records, err := filter.filter(); err == nil {
+			h = handler.NewRecordFilterHandler(h, records)
+		}
+	}
+	if routes, ok := routingFilter(config, handlerType); ok {
+		h = handler.NewRecordFilterHandler(h, routes)
+	}
+	if levels, ok := fileOutputFilter(config, handlerType); ok {
+		h = handler.NewRecordFilterHandler(h, levels)
+	}
+	// Thử lại trước khi chuyển sang dự phòng; entry bị từ chối khi mạch mở cũng được chuyển
+	if retry, ok := config.Retry[string(handlerType)]; ok {
+		h = handler.NewCircuitHandler(h, retry.policy())
+	}
+	// Handler dự phòng được gắn sau bởi linkFallbacks vì có thể chưa được tạo; đặt trong hàng
+	// đợi để lỗi ghi của worker cũng được chuyển sang dự phòng
+	if fallback, ok := config.Fallback[string(handlerType)]; ok {
+		h = handler.NewFallbackHandler(h, nil, fallback.After)
+	}
 
-	// Chỉ thêm handlers vào stack khi được cấu hình
-	if m.config.Stack.Handlers.Console {
-		stackHandler.AddHandler(consoleHandler)
+	async, hasAsync := config.Async[string(handlerType)]
+	if delivery, ok := config.Delivery[string(handlerType)]; ok {
+		// Cấu hình đã được Validate nên mode hợp lệ và có đủ tùy chọn bắt buộc
+		mode, _ := handler.ParseDeliveryMode(delivery.Mode)
+		wrapped, err := handler.NewDeliveryHandler(h, mode, handler.DeliveryOptions{
+			Workers:    async.Workers,
+			QueueSize:  async.QueueSize,
+			SpillPath:  delivery.SpillPath,
+			Retries:    delivery.Retries,
+			RetryDelay: delivery.RetryDelay,
+		})
+		if err == nil {
+			return wrapped
+		}
 	}
+	if hasAsync {
+		return handler.NewAsyncHandler(h, async.Workers, async.QueueSize)
+	}
+	return h
+}
 
-	if m.config.Stack.Handlers.File {
-		stackHandler.AddHandler(fileHandler)
+// linkFallbacks gắn handler dự phòng theo Config.Fallback vào các FallbackHandler do
+// wrapHandler tạo. Phải được gọi lại mỗi khi handler nguồn hoặc handler dự phòng thay đổi;
+// handler dự phòng chưa được đăng ký được gắn là nil (entry lỗi không được chuyển tiếp).
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa Fallback
+//   - handlers: map[HandlerType]handler.Handler - các handler đã đăng ký
+func linkFallbacks(config *Config, handlers map[HandlerType]handler.Handler) {
+	for name, fallback := range config.Fallback {
+		for h := handlers[HandlerType(name)]; h != nil; {
+			if f, ok := h.(*handler.FallbackHandler); ok {
+				f.SetFallback(handlers[HandlerType(fallback.Handler)])
+				break
+			}
+			u, ok := h.(interface{ Unwrap() handler.Handler })
+			if !ok {
+				break
+			}
+			h = u.Unwrap()
+		}
 	}
+}
+
+// linkErrors gửi lỗi ghi của các AsyncHandler trong chuỗi wrapper của mỗi handler đến reporter,
+// như lỗi của handler ghi đồng bộ, để lỗi trên worker cũng đến ErrorHandler và ErrorCount. Phải
+// được gọi lại mỗi khi handler được thêm hoặc tạo lại.
+//
+// Tham số:
+//   - reporter: *errorReporter - nơi nhận lỗi
+//   - handlers: map[HandlerType]handler.Handler - các handler đã đăng ký
+func linkErrors(reporter *errorReporter, handlers map[HandlerType]handler.Handler) {
+	for handlerType, h := range handlers {
+		// Handler con của stack được liên kết theo tên của chính nó
+		if handlerType == HandlerTypeStack {
+			continue
+		}
+		for h != nil {
+			if a, ok := h.(*handler.AsyncHandler); ok {
+				a.SetErrorHandler(func(err error) { reporter.report(handlerType, err) })
+			}
+			u, ok := h.(interface{ Unwrap() handler.Handler })
+			if !ok {
+				break
+			}
+			h = u.Unwrap()
+		}
+	}
+}
+
+// releaseWrappers dừng các AsyncHandler trong chuỗi wrapper từ h đến original mà không
+// đóng original. Dùng cho handler thuộc sở hữu bên ngoài được manager bọc.
+func releaseWrappers(h, original handler.Handler) {
+	for h != nil && h != original {
+		if a, ok := h.(*handler.AsyncHandler); ok {
+			a.Stop()
+		}
+		u, ok := h.(interface{ Unwrap() handler.Handler })
+		if !ok {
+			return
+		}
+		h = u.Unwrap()
+	}
+}
 
-	m.handlers[HandlerTypeStack] = stackHandler
+// wrapperChanged kiểm tra thiết lập async, delivery, dự phòng, thử lại, lọc field, gộp dòng, lọc entry, định tuyến hoặc middleware của một handler có thay đổi
+// giữa hai cấu hình hay không.
+func wrapperChanged(old, new *Config, handlerType HandlerType) bool {
+	o, oldOK := old.Async[string(handlerType)]
+	n, newOK := new.Async[string(handlerType)]
+	od, oldDelivery := old.Delivery[string(handlerType)]
+	nd, newDelivery := new.Delivery[string(handlerType)]
+	of, oldFilter := old.FieldFilters[string(handlerType)]
+	nf, newFilter := new.FieldFilters[string(handlerType)]
+	// Bộ lọc định dạng lại field theo giới hạn của logger nên phải được tạo lại khi giới hạn thay đổi
+	filterChanged := oldFilter != newFilter || of.String() != nf.String() ||
+		(newFilter && old.fieldLimits() != new.fieldLimits())
+	oo, oldFold := old.Fold[string(handlerType)]
+	no, newFold := new.Fold[string(handlerType)]
+	// Gộp dòng cũng định dạng lại field theo giới hạn của logger
+	foldChanged := oldFold != newFold || oo != no || (newFold && old.fieldLimits() != new.fieldLimits())
+	or, oldRecords := old.Filters[string(handlerType)]
+	nr, newRecords := new.Filters[string(handlerType)]
+	recordsChanged := oldRecords != newRecords || or.String() != nr.String() ||
+		routingRules(old, handlerType) != routingRules(new, handlerType)
+	ob, oldFallback := old.Fallback[string(handlerType)]
+	nb, newFallback := new.Fallback[string(handlerType)]
+	ot, oldRetry := old.Retry[string(handlerType)]
+	nt, newRetry := new.Retry[string(handlerType)]
+	return oldOK != newOK || o != n || oldDelivery != newDelivery || od != nd || oldFallback != newFallback || ob != nb ||
+		oldRetry != newRetry || ot != nt || filterChanged || foldChanged || recordsChanged ||
+		!slices.Equal(old.Middleware[string(handlerType)], new.Middleware[string(handlerType)])
 }
@@ -1,8 +1,16 @@
 package log
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"go.fork.vn/log/handler"
 )
@@ -81,11 +89,73 @@ func TestNewManager(t *testing.T) {
 	if defaultManager.config.Level != handler.InfoLevel {
 		t.Errorf("Manager mới không đặt config.Level mặc định là InfoLevel, got %v", defaultManager.config.Level)
 	}
-	if len(defaultManager.handlers) != 3 { // Console, File, Stack handlers luôn được khởi tạo
+	if len(defaultManager.handlers) != 3 { // Console, File, Stack handlers đều được bật trong cấu hình test
 		t.Errorf("Manager mới không có đúng số handlers, got %d handlers", len(defaultManager.handlers))
 	}
 }
 
+func TestNew_DefaultConfig(t *testing.T) {
+	m, err := New(DefaultConfig())
+	if err != nil {
+		t.Fatalf("New(DefaultConfig()) error = %v", err)
+	}
+	defer m.Close()
+
+	if m.GetHandler(HandlerTypeConsole) == nil {
+		t.Error("Console handler được bật nên phải được tạo")
+	}
+	if m.GetHandler(HandlerTypeFile) != nil {
+		t.Error("File handler bị tắt không nên được tạo")
+	}
+	m.GetLogger("App").Info("khởi động không cần file log")
+}
+
+func TestNew_FileOpenError(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = filepath.Join(t.TempDir(), "missing", "app.log")
+
+	m, err := New(config)
+	if err == nil || m != nil || !strings.Contains(err.Error(), "failed to create file handler") {
+		t.Fatalf("New() nên trả về lỗi khi không thể mở file log, got %v", err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("NewManager() nên panic khi không thể mở file log")
+		}
+	}()
+	NewManager(config)
+}
+
+func TestManager_ApplyConfig_FileEnabled(t *testing.T) {
+	config := createTestConfig()
+	config.Stack.Enabled = false
+	config.File.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	m := NewManager(config)
+	defer m.Close()
+	logger := m.GetLogger("Order")
+	if m.GetHandler(HandlerTypeFile) != nil {
+		t.Fatal("File handler bị tắt không nên được tạo")
+	}
+
+	enabled := *config
+	enabled.File.Enabled = true
+	diff, err := m.ApplyConfig(&enabled, false)
+	if err != nil || !strings.Contains(diff.String(), "handler file: create") {
+		t.Fatalf("Bật file nên tạo file handler, got %v %q", err, diff.String())
+	}
+	logger.Info("written to file")
+	if data, _ := os.ReadFile(config.File.Path); !strings.Contains(string(data), "written to file") {
+		t.Errorf("Logger đã tồn tại nên ghi vào file vừa được bật, got %q", data)
+	}
+
+	diff, err = m.ApplyConfig(config, false)
+	if err != nil || !strings.Contains(diff.String(), "handler file: remove") || m.GetHandler(HandlerTypeFile) != nil {
+		t.Errorf("Tắt file nên xóa file handler, got %v %q", err, diff.String())
+	}
+	logger.Info("console only")
+}
+
 func TestManager_AddHandler(t *testing.T) {
 	config := createTestConfig()
 	m := NewManager(config)
@@ -279,6 +349,59 @@ func TestManager_ConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestManager_ConcurrentReconfigure(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	m := NewManager(config)
+	defer m.Close()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; ; j++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				logger := m.GetLogger("Service" + strconv.Itoa((id+j)%8))
+				logger.Info("message %d", j)
+				logger.Error("failed", Int("attempt", j))
+			}
+		}(i)
+	}
+	for i := 0; i < 100; i++ {
+		updated := *config
+		updated.Level = handler.Level(i % 3)
+		updated.EnableCaller = i%2 == 0
+		if _, err := m.ApplyConfig(&updated, false); err != nil {
+			t.Fatalf("ApplyConfig() error = %v", err)
+		}
+		if err := m.AddNamedHandler("sink", &discardHandler{}); err != nil {
+			t.Fatalf("AddNamedHandler() error = %v", err)
+		}
+		m.ElevateLevel("Service1", handler.DebugLevel, time.Millisecond)
+		m.RemoveHandler("sink")
+	}
+	close(stop)
+	wg.Wait()
+
+	// Handler đăng ký trước lời gọi log nhận entry của lời gọi đó, kể cả với logger đã tồn tại
+	sink := &discardHandler{}
+	if err := m.AddNamedHandler("sink", sink); err != nil {
+		t.Fatalf("AddNamedHandler() error = %v", err)
+	}
+	m.GetLogger("Service0").Error("after")
+	if sink.calls.Load() != 1 {
+		t.Errorf("Lời gọi log sau AddNamedHandler nên đến handler mới, got %d", sink.calls.Load())
+	}
+}
+
 // Benchmarks
 
 func BenchmarkManager_GetLogger(b *testing.B) {
@@ -335,3 +458,875 @@ func BenchmarkManager_ConcurrentGetLogger(b *testing.B) {
 		}
 	})
 }
+
+func TestManager_ValidateConfig(t *testing.T) {
+	config := createTestConfig()
+	m := NewManager(config)
+	defer m.Close()
+	m.GetLogger("TestService")
+
+	newConfig := createTestConfig()
+	newConfig.Level = handler.ErrorLevel
+	newConfig.Stack.Enabled = false
+
+	diff, err := m.ValidateConfig(newConfig)
+	if err != nil {
+		t.Fatalf("ValidateConfig() trả về lỗi: %v", err)
+	}
+	if !diff.DryRun {
+		t.Error("ValidateConfig() nên trả về báo cáo dry-run")
+	}
+	if len(diff.Levels) != 1 || diff.Levels[0] != "TestService" {
+		t.Errorf("ValidateConfig() nên báo cáo level thay đổi cho TestService, got %v", diff.Levels)
+	}
+	if len(diff.Routes) != 1 {
+		t.Errorf("ValidateConfig() nên báo cáo route thay đổi, got %v", diff.Routes)
+	}
+
+	// Cấu hình hiện tại không bị thay đổi
+	if m.(*manager).config != config {
+		t.Error("ValidateConfig() không được thay đổi cấu hình của manager")
+	}
+
+	invalid := createTestConfig()
+	invalid.Level = handler.Level(99)
+	if _, err := m.ValidateConfig(invalid); err == nil {
+		t.Error("ValidateConfig() nên trả về lỗi với cấu hình không hợp lệ")
+	}
+
+	// Dry run chỉ kiểm tra cú pháp, không truy cập thư mục log
+	missing := createTestConfig()
+	missing.File.Path = filepath.Join(t.TempDir(), "missing", "app.log")
+	if _, err := m.ValidateConfig(missing); err != nil {
+		t.Errorf("ValidateConfig() không nên kiểm tra thư mục log, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(missing.File.Path)); !os.IsNotExist(err) {
+		t.Errorf("ValidateConfig() không được tạo thư mục log, got %v", err)
+	}
+	if _, err := m.ApplyConfig(missing, false); err == nil {
+		t.Error("ApplyConfig() nên kiểm tra thư mục log khi áp dụng")
+	}
+}
+
+func TestManager_ApplyConfig(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = t.TempDir() + "/old.log"
+	m := NewManager(config)
+	defer m.Close()
+
+	lg := m.GetLogger("TestService")
+	oldConsole := m.GetHandler(HandlerTypeConsole)
+
+	newConfig := createTestConfig()
+	newConfig.File.Path = t.TempDir() + "/new.log"
+	newConfig.Level = handler.DebugLevel
+
+	diff, err := m.ApplyConfig(newConfig, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() trả về lỗi: %v", err)
+	}
+	if diff.DryRun {
+		t.Error("ApplyConfig(false) không nên trả về báo cáo dry-run")
+	}
+
+	var recreated []HandlerType
+	for _, change := range diff.Handlers {
+		recreated = append(recreated, change.Type)
+	}
+	if !equalTypes(recreated, []HandlerType{HandlerTypeFile, HandlerTypeStack}) {
+		t.Errorf("ApplyConfig() nên tạo lại file và stack handler, got %v", recreated)
+	}
+
+	// Console handler không đổi nên được giữ nguyên
+	if m.GetHandler(HandlerTypeConsole) != oldConsole {
+		t.Error("ApplyConfig() không nên tạo lại console handler không đổi")
+	}
+
+	// Logger đã tồn tại được cập nhật level và handler mới
+	l := lg.(*logger)
+	if l.getMinLevel() != handler.DebugLevel {
+		t.Errorf("Logger không được cập nhật level, got %v", l.getMinLevel())
+	}
+	if lg.GetHandler(HandlerTypeStack) != m.GetHandler(HandlerTypeStack) {
+		t.Error("Logger không được chuyển sang stack handler mới")
+	}
+}
+
+func TestManager_ExternalOwnership(t *testing.T) {
+	config := createTestConfig()
+	m := NewManager(config)
+	m.GetLogger("TestService")
+
+	external := &MockHandler{}
+	m.AddHandler(TestHandlerType, external, WithExternalOwnership())
+
+	// Thay thế handler thuộc sở hữu bên ngoài không được đóng nó
+	owned := &MockHandler{}
+	m.AddHandler(TestHandlerType, owned)
+	if external.CloseCalled {
+		t.Error("AddHandler không được đóng handler thuộc sở hữu bên ngoài khi thay thế")
+	}
+
+	// Handler do manager sở hữu vẫn bị đóng khi thay thế, và chỉ đóng một lần
+	m.AddHandler(TestHandlerType, external, WithExternalOwnership())
+	if !owned.CloseCalled {
+		t.Error("AddHandler nên đóng handler do manager sở hữu khi thay thế")
+	}
+
+	m.RemoveHandler(TestHandlerType)
+	if external.CloseCalled {
+		t.Error("RemoveHandler không được đóng handler thuộc sở hữu bên ngoài")
+	}
+
+	m.AddHandler(TestHandlerType, external, WithExternalOwnership())
+	if err := m.Close(); err != nil {
+		t.Errorf("Close() trả về lỗi: %v", err)
+	}
+	if external.CloseCalled {
+		t.Error("Close() không được đóng handler thuộc sở hữu bên ngoài")
+	}
+}
+
+// countingHandler đếm số lần Close được gọi
+type countingHandler struct {
+	MockHandler
+	closes int
+}
+
+func (c *countingHandler) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestManager_RemoveHandler_ClosesOnce(t *testing.T) {
+	config := createTestConfig()
+	m := NewManager(config)
+	m.GetLogger("ServiceA")
+	m.GetLogger("ServiceB")
+
+	h := &countingHandler{}
+	m.AddHandler(TestHandlerType, h)
+	m.RemoveHandler(TestHandlerType)
+
+	if h.closes != 1 {
+		t.Errorf("RemoveHandler nên đóng handler đúng một lần, got %d", h.closes)
+	}
+}
+
+// newStackIncludeManager tạo manager có stack gồm file handler và handler tùy chỉnh "custom".
+func newStackIncludeManager(t *testing.T) *manager {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Handlers.Console = false
+	config.Stack.Include = []string{"custom"}
+	config.File.Path = t.TempDir() + "/stack.log"
+
+	m := NewManager(config).(*manager)
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func TestManager_StackInclude(t *testing.T) {
+	m := newStackIncludeManager(t)
+	lg := m.GetLogger("Service")
+
+	custom := &MockHandler{}
+	m.AddHandler("custom", custom)
+
+	if lg.GetHandler("custom") != nil {
+		t.Error("Handler thuộc stack không nên được gắn trực tiếp vào logger")
+	}
+	if lg.GetHandler(HandlerTypeStack) != m.GetHandler(HandlerTypeStack) {
+		t.Error("Logger nên được gắn stack mới sau khi handler thuộc stack được thêm")
+	}
+
+	lg.Info("through stack")
+	if !custom.LogCalled || !strings.Contains(custom.LogMessage, "through stack") {
+		t.Errorf("Handler tùy chỉnh nên nhận log qua stack, got %q", custom.LogMessage)
+	}
+
+	// Logger mới cũng ghi đến handler tùy chỉnh qua stack
+	custom.LogCalled = false
+	m.GetLogger("Other").Info("new logger")
+	if !custom.LogCalled {
+		t.Error("Logger tạo sau AddHandler nên ghi đến handler tùy chỉnh qua stack")
+	}
+
+	m.RemoveHandler("custom")
+	custom.LogCalled = false
+	lg.Info("after remove")
+	if custom.LogCalled {
+		t.Error("Handler đã gỡ bỏ không nên nhận log qua stack")
+	}
+}
+
+func TestManager_StackInclude_ExternalNotClosed(t *testing.T) {
+	m := newStackIncludeManager(t)
+
+	custom := &MockHandler{}
+	m.AddHandler("custom", custom, WithExternalOwnership())
+	m.Close()
+
+	if custom.CloseCalled {
+		t.Error("Close() không được đóng handler thuộc sở hữu bên ngoài kể cả khi nó thuộc stack")
+	}
+}
+
+func TestManager_ApplyConfig_StackInclude(t *testing.T) {
+	m := newStackIncludeManager(t)
+	lg := m.GetLogger("Service")
+
+	audit := &MockHandler{}
+	m.AddHandler("audit", audit)
+	if lg.GetHandler("audit") != audit {
+		t.Fatal("Handler không thuộc stack nên được gắn trực tiếp vào logger")
+	}
+
+	config := *m.config
+	config.Stack.Include = []string{"custom", "audit"}
+	diff, err := m.ApplyConfig(&config, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), `field stack.include: "custom" -> "custom,audit"`) {
+		t.Errorf("Diff nên báo cáo thay đổi stack.include, got %q", diff.String())
+	}
+	if lg.GetHandler("audit") != nil {
+		t.Error("Handler vừa được thêm vào stack nên được gỡ khỏi logger")
+	}
+
+	lg.Info("audited")
+	if !strings.Contains(audit.LogMessage, "audited") {
+		t.Errorf("Handler nên nhận log qua stack mới, got %q", audit.LogMessage)
+	}
+}
+
+func TestManager_ApplyConfig_StackLevels(t *testing.T) {
+	m := newStackIncludeManager(t)
+	lg := m.GetLogger("Service")
+
+	custom := &MockHandler{}
+	m.AddHandler("custom", custom)
+
+	config := *m.config
+	config.Stack.Levels = map[string]string{"custom": "error"}
+	diff, err := m.ApplyConfig(&config, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), `field stack.levels.custom: "" -> "error"`) {
+		t.Errorf("Diff nên báo cáo thay đổi stack.levels, got %q", diff.String())
+	}
+	if level, ok := m.stack.(*handler.StackHandler).ChildLevel("custom"); !ok || level != handler.ErrorLevel {
+		t.Errorf("Handler con nên có cấp độ error, got %v (%v)", level, ok)
+	}
+
+	lg.Warning("below")
+	if custom.LogCalled {
+		t.Error("Handler con không nên nhận entry dưới cấp độ riêng của nó")
+	}
+	lg.Error("failed")
+	if !strings.Contains(custom.LogMessage, "failed") {
+		t.Errorf("Handler con nên nhận entry từ cấp độ riêng trở lên, got %q", custom.LogMessage)
+	}
+}
+
+func TestManager_StackMode(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Handlers.Console = false
+	config.Stack.Include = []string{"primary", "backup"}
+	config.Stack.Handlers.File = false
+	config.File.Enabled = false
+	config.Stack.Mode = "failover"
+	config.Stack.Concurrency = 2
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	primary := &MockHandler{ShouldError: true}
+	backup := &MockHandler{}
+	m.AddHandler("primary", primary)
+	m.AddHandler("backup", backup)
+
+	stack, ok := m.GetHandler(HandlerTypeStack).(*handler.StackHandler)
+	if !ok || stack.Mode() != handler.Failover {
+		t.Fatalf("Stack nên dùng chế độ failover, got %T", m.GetHandler(HandlerTypeStack))
+	}
+	if stack.Concurrency() != 2 {
+		t.Errorf("Stack nên ghi song song tối đa 2 lần, got %d", stack.Concurrency())
+	}
+
+	m.GetLogger("Service").Info("failover")
+	if !primary.LogCalled || !strings.Contains(backup.LogMessage, "failover") {
+		t.Error("Handler dự phòng nên nhận entry khi handler chính thất bại")
+	}
+}
+
+func TestManager_Middleware(t *testing.T) {
+	handler.RegisterMiddleware("test_retry", handler.Retry(handler.RetryPolicy{Retries: 1}))
+
+	config := createTestConfig()
+	config.Middleware = map[string][]string{"custom": {"test_retry"}}
+	m := NewManager(config)
+	defer m.Close()
+
+	custom := &MockHandler{}
+	m.AddHandler("custom", custom)
+	wrapped, ok := m.GetHandler("custom").(*handler.CircuitHandler)
+	if !ok || wrapped.Unwrap() != custom {
+		t.Fatalf("Handler nên được bọc bằng middleware theo Config.Middleware, got %T", m.GetHandler("custom"))
+	}
+
+	m.GetLogger("Service").Info("through middleware")
+	if !strings.Contains(custom.LogMessage, "through middleware") {
+		t.Errorf("Handler bên trong nên nhận entry qua middleware, got %q", custom.LogMessage)
+	}
+}
+
+func TestManager_Fold(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.RawMessages = true
+	config.Fold = map[string]string{"custom": "escape"}
+	m := NewManager(config)
+	defer m.Close()
+
+	custom := &entryHandler{}
+	m.AddHandler("custom", custom)
+	if _, ok := m.GetHandler("custom").(*handler.FoldHandler); !ok {
+		t.Fatalf("Handler nên được bọc theo Config.Fold, got %T", m.GetHandler("custom"))
+	}
+	m.GetLogger("Worker").Error("panic: boom\ngoroutine 1")
+	if got := custom.entry.Message; got != `[Worker] panic: boom\ngoroutine 1` {
+		t.Errorf("Thông điệp nên được gộp thành một dòng, got %q", got)
+	}
+
+	updated := *config
+	updated.Fold = nil
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "fold.custom") {
+		t.Errorf("Diff nên liệt kê thay đổi của fold, got %q", diff.String())
+	}
+}
+
+func TestManager_Async(t *testing.T) {
+	config := createTestConfig()
+	config.Async = map[string]AsyncConfig{
+		"file":   {Workers: 1, QueueSize: 16},
+		"custom": {Workers: 2, QueueSize: 8},
+	}
+	m := NewManager(config)
+
+	if _, ok := m.GetHandler(HandlerTypeFile).(*handler.AsyncHandler); !ok {
+		t.Errorf("File handler nên được bọc trong AsyncHandler, got %T", m.GetHandler(HandlerTypeFile))
+	}
+	if _, ok := m.GetHandler(HandlerTypeConsole).(*handler.AsyncHandler); ok {
+		t.Error("Console handler không có thiết lập async nên được ghi đồng bộ")
+	}
+
+	remote := m.GetLogger("Remote")
+	external := &countingHandler{}
+	m.AddHandler("custom", external, WithExternalOwnership())
+	async, ok := m.GetHandler("custom").(*handler.AsyncHandler)
+	if !ok || async.Unwrap() != external {
+		t.Fatalf("Handler tùy chỉnh nên được bọc theo Config.Async, got %T", m.GetHandler("custom"))
+	}
+
+	remote.Info("shipped")
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !external.LogCalled {
+		t.Error("Close() nên ghi hết hàng đợi của handler bất đồng bộ")
+	}
+	if external.closes != 0 {
+		t.Errorf("Handler thuộc sở hữu bên ngoài không nên bị đóng, got %d", external.closes)
+	}
+
+	invalid := *config
+	invalid.Async = map[string]AsyncConfig{"stack": {Workers: 1}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Validate() nên từ chối async cho stack")
+	}
+	invalid.Async = map[string]AsyncConfig{"file": {Workers: -1}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Validate() nên từ chối số worker âm")
+	}
+}
+
+func TestManager_Async_ReAddAndApplyConfig(t *testing.T) {
+	config := createTestConfig()
+	config.Async = map[string]AsyncConfig{"custom": {}}
+	m := NewManager(config)
+	defer m.Close()
+
+	h := &countingHandler{}
+	m.AddHandler("custom", h)
+	m.AddHandler("custom", h)
+	if h.closes != 0 {
+		t.Errorf("Thêm lại cùng handler không nên đóng nó, got %d", h.closes)
+	}
+	m.RemoveHandler("custom")
+	if h.closes != 1 {
+		t.Errorf("RemoveHandler nên đóng handler được bọc đúng một lần, got %d", h.closes)
+	}
+
+	updated := *config
+	updated.Async = map[string]AsyncConfig{"custom": {}, "console": {Workers: 2}}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "async.console") {
+		t.Errorf("Diff nên báo cáo thay đổi async.console, got %q", diff.String())
+	}
+	if _, ok := m.GetHandler(HandlerTypeConsole).(*handler.AsyncHandler); !ok {
+		t.Errorf("ApplyConfig nên tạo lại console handler bất đồng bộ, got %T", m.GetHandler(HandlerTypeConsole))
+	}
+}
+
+func TestManager_Async_WrapsHandlerWrappers(t *testing.T) {
+	config := createTestConfig()
+	config.Async = map[string]AsyncConfig{"custom": {Workers: 1, QueueSize: 8}}
+	m := NewManager(config)
+	defer m.Close()
+
+	level := handler.NewLevelHandler(&countingHandler{}, handler.InfoLevel)
+	m.AddHandler("custom", level)
+	async, ok := m.GetHandler("custom").(*handler.AsyncHandler)
+	if !ok || async.Unwrap() != level {
+		t.Fatalf("Handler là wrapper của package handler vẫn nên được bọc theo Config.Async, got %T", m.GetHandler("custom"))
+	}
+
+	m.AddHandler("custom", async)
+	if m.GetHandler("custom") != async {
+		t.Errorf("Thêm lại bản bọc do manager tạo không nên bọc lần nữa, got %T", m.GetHandler("custom"))
+	}
+}
+
+func TestManager_Delivery(t *testing.T) {
+	config := createTestConfig()
+	config.Delivery = map[string]DeliveryConfig{
+		"file":   {Mode: "guaranteed", Retries: 1},
+		"remote": {Mode: "at_least_once", SpillPath: filepath.Join(t.TempDir(), "remote.spill")},
+	}
+	config.Async = map[string]AsyncConfig{"remote": {Workers: 4, QueueSize: 100}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+
+	if _, ok := m.GetHandler(HandlerTypeFile).(*handler.GuaranteedHandler); !ok {
+		t.Errorf("File handler nên được bọc theo chế độ guaranteed, got %T", m.GetHandler(HandlerTypeFile))
+	}
+	remote := &countingHandler{}
+	m.AddHandler("remote", remote, WithExternalOwnership())
+	async, ok := m.GetHandler("remote").(*handler.AsyncHandler)
+	if !ok {
+		t.Fatalf("at_least_once nên tạo AsyncHandler, got %T", m.GetHandler("remote"))
+	}
+	if _, ok := async.Unwrap().(*handler.SpillHandler); !ok {
+		t.Errorf("at_least_once nên lưu entry thất bại qua SpillHandler, got %T", async.Unwrap())
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if remote.closes != 0 {
+		t.Errorf("Handler thuộc sở hữu bên ngoài không nên bị đóng, got %d", remote.closes)
+	}
+
+	tests := map[string]DeliveryConfig{
+		"mode không hợp lệ":    {Mode: "exactly_once"},
+		"thiếu spill_path":     {Mode: "at_least_once"},
+		"guaranteed kèm async": {Mode: "guaranteed"},
+		"retries âm":           {Mode: "best_effort", Retries: -1},
+	}
+	for name, delivery := range tests {
+		invalid := *createTestConfig()
+		invalid.Async = map[string]AsyncConfig{"remote": {}}
+		invalid.Delivery = map[string]DeliveryConfig{"remote": delivery}
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate() nên từ chối %s", name)
+		}
+	}
+}
+
+func TestManager_Fallback(t *testing.T) {
+	config := newChannelTestConfig(t)
+	config.Fallback = map[string]FallbackConfig{"remote": {Handler: "spill", After: 2}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	remote, spill := &MockHandler{ShouldError: true}, &recordingHandler{}
+	m.AddHandler("remote", remote)
+	m.AddHandler("spill", spill)
+	l := m.GetLogger("Order")
+	l.Error("first")
+	l.Error("second")
+	if spill.contains("first") || !spill.contains("second") {
+		t.Errorf("Handler dự phòng chỉ nên nhận entry sau 2 lần thất bại liên tiếp, got %v", spill.messages)
+	}
+	if err := handler.Health(m.GetHandler("remote")); err == nil {
+		t.Error("Health() nên báo handler remote đang dùng dự phòng")
+	}
+
+	// Handler dự phòng được thay thế cũng được gắn lại
+	replacement := &recordingHandler{}
+	m.AddHandler("spill", replacement)
+	l.Error("third")
+	if !replacement.contains("third") {
+		t.Error("Entry lỗi nên được chuyển đến handler dự phòng mới")
+	}
+
+	tests := map[string]FallbackConfig{
+		"thiếu handler":       {},
+		"dự phòng chính nó":   {Handler: "remote"},
+		"dự phòng sang stack": {Handler: "stack"},
+		"after âm":            {Handler: "console", After: -1},
+	}
+	for name, fallback := range tests {
+		invalid := *createTestConfig()
+		invalid.Fallback = map[string]FallbackConfig{"remote": fallback}
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate() nên từ chối %s", name)
+		}
+	}
+	chained := *createTestConfig()
+	chained.Fallback = map[string]FallbackConfig{"remote": {Handler: "console"}, "console": {Handler: "file"}}
+	if err := chained.Validate(); err == nil {
+		t.Error("Validate() nên từ chối handler dự phòng có dự phòng riêng")
+	}
+}
+
+func TestManager_Retry(t *testing.T) {
+	config := newChannelTestConfig(t)
+	config.Retry = map[string]RetryConfig{"remote": {FailureThreshold: 2, OpenTimeout: time.Hour}}
+	config.Fallback = map[string]FallbackConfig{"remote": {Handler: "spill"}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	remote, spill := &MockHandler{ShouldError: true}, &recordingHandler{}
+	m.AddHandler("remote", remote)
+	m.AddHandler("spill", spill)
+	l := m.GetLogger("Order")
+	for _, message := range []string{"first", "second", "third"} {
+		l.Error(message)
+	}
+	stats, ok := handler.Circuit(m.GetHandler("remote"))
+	if !ok || stats.State != handler.CircuitOpen || stats.Rejected != 1 {
+		t.Errorf("Mạch của remote nên mở sau 2 entry thất bại, got %+v", stats)
+	}
+	if !spill.contains("third") {
+		t.Error("Entry bị từ chối khi mạch mở nên được chuyển sang handler dự phòng")
+	}
+
+	invalid := *createTestConfig()
+	invalid.Retry = map[string]RetryConfig{"remote": {Retries: -1}}
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "retry.remote") {
+		t.Errorf("Validate() nên từ chối retries âm, got %v", err)
+	}
+}
+
+func TestManager_FieldFilters(t *testing.T) {
+	config := createTestConfig()
+	config.FieldFilters = map[string]FieldFilterConfig{"console": {Deny: []string{"user_agent"}}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	if _, ok := m.GetHandler(HandlerTypeConsole).(*handler.FieldFilterHandler); !ok {
+		t.Errorf("Console handler nên được bọc theo Config.FieldFilters, got %T", m.GetHandler(HandlerTypeConsole))
+	}
+	if _, ok := m.GetHandler(HandlerTypeFile).(*handler.FieldFilterHandler); ok {
+		t.Error("File handler không có bộ lọc nên nhận đủ field")
+	}
+
+	l := m.GetLogger("HTTP")
+	console, file := &entryHandler{}, &entryHandler{}
+	m.AddHandler("console", console)
+	m.AddHandler("file", file)
+	l.Info("request", String("path", "/"), String("user_agent", "curl/8.0"))
+	if console.entry == nil || console.entry.Message != "[HTTP] request path=/" {
+		t.Errorf("Console nên bỏ field user_agent, got %v", console.entry)
+	}
+	if file.entry == nil || file.entry.Message != "[HTTP] request path=/ user_agent=curl/8.0" {
+		t.Errorf("File nên giữ đầy đủ field, got %v", file.entry)
+	}
+
+	updated := *config
+	updated.FieldFilters = map[string]FieldFilterConfig{"console": {Allow: []string{"request_*"}}}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "field_filters.console") || !strings.Contains(diff.String(), "handler console: recreate") {
+		t.Errorf("Thay đổi bộ lọc nên tạo lại console handler, got %q", diff.String())
+	}
+
+	for name, filters := range map[string]map[string]FieldFilterConfig{
+		"bộ lọc cho stack": {"stack": {Deny: []string{"x"}}},
+		"mẫu không hợp lệ": {"console": {Deny: []string{"[a-"}}},
+	} {
+		invalid := *createTestConfig()
+		invalid.FieldFilters = filters
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate() nên từ chối %s", name)
+		}
+	}
+}
+
+func TestManager_Filters(t *testing.T) {
+	config := createTestConfig()
+	config.Filters = map[string]RecordFilterConfig{
+		"console": {Exclude: []RecordRuleConfig{{Contexts: []string{"grpc*"}, Fields: []string{"status<500"}}}},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	if _, ok := m.GetHandler(HandlerTypeConsole).(*handler.RecordFilterHandler); !ok {
+		t.Errorf("Console handler nên được bọc theo Config.Filters, got %T", m.GetHandler(HandlerTypeConsole))
+	}
+
+	console, file := &entryHandler{}, &entryHandler{}
+	m.AddHandler("console", console)
+	m.AddHandler("file", file)
+	m.GetLogger("grpc.client").Info("call", Int("status", 0))
+	if console.entry != nil {
+		t.Errorf("Console nên bỏ log grpc thành công, got %v", console.entry)
+	}
+	if file.entry == nil {
+		t.Error("File không có bộ lọc nên nhận mọi entry")
+	}
+	m.GetLogger("grpc.client").Error("call", Int("status", 503))
+	if console.entry == nil || console.entry.Message != "[grpc.client] call status=503" {
+		t.Errorf("Console nên ghi log grpc lỗi, got %v", console.entry)
+	}
+
+	updated := *config
+	updated.Filters = map[string]RecordFilterConfig{"console": {Exclude: []RecordRuleConfig{{Message: "health"}}}}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "filters.console") || !strings.Contains(diff.String(), "handler console: recreate") {
+		t.Errorf("Thay đổi bộ lọc nên tạo lại console handler, got %q", diff.String())
+	}
+
+	for name, filters := range map[string]map[string]RecordFilterConfig{
+		"bộ lọc cho stack":       {"stack": {Exclude: []RecordRuleConfig{{Message: "x"}}}},
+		"regex không hợp lệ":     {"console": {Exclude: []RecordRuleConfig{{Message: "(a"}}}},
+		"context không hợp lệ":   {"console": {Include: []RecordRuleConfig{{Contexts: []string{"[a-"}}}}},
+		"điều kiện không hợp lệ": {"console": {Exclude: []RecordRuleConfig{{Fields: []string{"=500"}}}}},
+	} {
+		invalid := *createTestConfig()
+		invalid.Filters = filters
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate() nên từ chối %s", name)
+		}
+	}
+}
+
+func TestManager_AddNamedHandler(t *testing.T) {
+	config := createTestConfig()
+	config.Stack.Include = []string{"loki-eu"}
+	config.Routing = []RoutingRuleConfig{{Match: RecordRuleConfig{Level: "error"}, Handlers: []string{"errors-file"}}}
+	m := NewManager(config)
+	defer m.Close()
+
+	before := m.GetLogger("Before")
+	dir := t.TempDir()
+	audit, err := handler.NewFileHandler(filepath.Join(dir, "audit.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	errorsFile, err := handler.NewFileHandler(filepath.Join(dir, "errors.log"), 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	loki := &entryHandler{}
+	for name, h := range map[string]handler.Handler{"audit-file": audit, "errors-file": errorsFile, "loki-eu": loki} {
+		if err := m.AddNamedHandler(name, h); err != nil {
+			t.Fatalf("AddNamedHandler(%q) error = %v", name, err)
+		}
+	}
+	after := m.GetLogger("After")
+
+	before.Info("from before")
+	after.Error("from after")
+	auditData, _ := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if !strings.Contains(string(auditData), "[Before] from before") || !strings.Contains(string(auditData), "[After] from after") {
+		t.Errorf("Handler có tên nên được gắn vào logger tạo trước và sau khi đăng ký, got %q", auditData)
+	}
+	errorsData, _ := os.ReadFile(filepath.Join(dir, "errors.log"))
+	if strings.Contains(string(errorsData), "from before") || !strings.Contains(string(errorsData), "[After] from after") {
+		t.Errorf("Routing nên chỉ gửi entry error đến errors-file, got %q", errorsData)
+	}
+	if loki.entry == nil || loki.entry.Message != "[After] from after" {
+		t.Errorf("Handler có tên trong Stack.Include nên nhận entry qua stack, got %v", loki.entry)
+	}
+
+	if err := m.AddNamedHandler("audit-file", &entryHandler{}); err == nil {
+		t.Error("AddNamedHandler() nên từ chối tên đã được đăng ký")
+	}
+	for _, name := range []string{"", "console", "stack", "channel.audit"} {
+		if err := m.AddNamedHandler(name, &entryHandler{}); err == nil {
+			t.Errorf("AddNamedHandler(%q) nên từ chối tên dành riêng", name)
+		}
+	}
+}
+
+func TestManager_Sampling(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.Sampling = SamplingConfig{Initial: 1, Thereafter: 0, Tick: time.Hour}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	worker := m.GetLogger("Worker")
+	api := m.GetLogger("API")
+	h := &discardHandler{}
+	m.AddHandler(TestHandlerType, h)
+
+	for i := 0; i < 5; i++ {
+		worker.Info("tick")
+		api.Info("tick")
+	}
+	if got := h.calls.Load(); got != 2 {
+		t.Errorf("Mỗi context nên được lấy mẫu riêng, got %d entry", got)
+	}
+
+	// Tắt lấy mẫu qua ApplyConfig
+	updated := *config
+	updated.Sampling = SamplingConfig{}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "sampling") {
+		t.Errorf("Diff nên liệt kê thay đổi của sampling, got %q", diff.String())
+	}
+	worker.Info("tick")
+	if got := h.calls.Load(); got != 3 {
+		t.Errorf("Tắt lấy mẫu nên áp dụng cho logger đã tồn tại, got %d entry", got)
+	}
+
+	invalid := *createTestConfig()
+	invalid.Sampling = SamplingConfig{Initial: 10, Thereafter: -1}
+	err = invalid.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "sampling" {
+		t.Errorf("Validate() nên từ chối sampling âm, got %v", err)
+	}
+}
+
+func TestManager_LoggerRegistry(t *testing.T) {
+	m, payment, rec := newElevateTestManager(t)
+	m.GetLogger("Job-2")
+	m.GetLogger("Job-1")
+
+	if got := strings.Join(m.Loggers(), ","); got != "Job-1,Job-2,Payment" {
+		t.Errorf("Loggers() = %q, want các context đã sắp xếp", got)
+	}
+
+	if err := m.ElevateLevel("Job-1", handler.DebugLevel, time.Hour); err != nil {
+		t.Fatalf("ElevateLevel() error = %v", err)
+	}
+	if !m.RemoveLogger("Job-1") {
+		t.Error("RemoveLogger() nên trả về true với logger đã tồn tại")
+	}
+	if m.RemoveLogger("Job-1") {
+		t.Error("RemoveLogger() nên trả về false với logger đã bị xóa")
+	}
+	if m.elevated["Job-1"] != nil {
+		t.Error("RemoveLogger() nên hủy lần nâng cấp độ đang chờ của context")
+	}
+
+	m.Reset()
+	if len(m.Loggers()) != 0 {
+		t.Errorf("Reset() nên xóa tất cả logger, got %v", m.Loggers())
+	}
+	if m.GetLogger("Payment") == Logger(payment) {
+		t.Error("GetLogger() sau Reset nên tạo logger mới")
+	}
+	payment.Error("still writes")
+	if !rec.contains("still writes") {
+		t.Error("Logger đã bị xóa vẫn nên ghi được vào handler dùng chung")
+	}
+}
+
+func TestManager_FileSigning(t *testing.T) {
+	key := "0123456789abcdef0123456789abcdef"
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "audit.log")
+	config.File.Signing = SigningConfig{Key: key, Records: true}
+	m := NewManager(config)
+
+	m.GetLogger("Audit").Info("role granted")
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(config.File.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSuffix(string(data), "\n")
+	if !handler.VerifyLine([]byte(key), []byte(line)) {
+		t.Errorf("Dòng log nên có chữ ký hợp lệ, got %q", line)
+	}
+}
+
+func TestManager_FileEncryption(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	handler.RegisterKeyProvider("test-manager-kms", func() ([]byte, error) { return key, nil })
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "secret.log")
+	config.File.Encryption = EncryptionConfig{KeyProvider: "test-manager-kms"}
+	m := NewManager(config)
+
+	m.GetLogger("Patient").Info("record viewed")
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(config.File.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), handler.EncryptedPrefix) {
+		t.Fatalf("File log nên được mã hóa, got %q", data)
+	}
+	plain, err := handler.NewDecryptReader(bytes.NewReader(data), key, handler.DecryptOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(plain)
+	if err != nil || !strings.Contains(string(out), "[Patient] record viewed") {
+		t.Errorf("Bản rõ không đúng, got %q, %v", out, err)
+	}
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// Các key của field được gắn bởi MetadataHook.
+const (
+	FieldHostname    = "hostname"     // Tên máy chủ chạy tiến trình
+	FieldPID         = "pid"          // Process ID
+	FieldGoroutineID = "goroutine_id" // ID của goroutine ghi log
+	FieldSequence    = "seq"          // Số thứ tự tăng dần của entry
+)
+
+// MetadataConfig chọn các thông tin về máy chủ và tiến trình được gắn vào mọi entry, để phân biệt
+// nguồn gốc log khi nhiều instance cùng ghi về một nơi.
+type MetadataConfig struct {
+	// Hostname gắn tên máy chủ (os.Hostname) vào field "hostname"
+	Hostname bool `mapstructure:"hostname" yaml:"hostname" json:"hostname"`
+
+	// PID gắn process ID vào field "pid"
+	PID bool `mapstructure:"pid" yaml:"pid" json:"pid"`
+
+	// GoroutineID gắn ID của goroutine ghi log vào field "goroutine_id". ID được đọc từ
+	// runtime.Stack trên mỗi entry nên chỉ nên bật khi debug
+	GoroutineID bool `mapstructure:"goroutine_id" yaml:"goroutine_id" json:"goroutine_id"`
+
+	// Build gắn phiên bản, commit và thời điểm build đọc từ ReadBuildInfo vào các field "version",
+	// "commit" và "build_date"
+	Build bool `mapstructure:"build" yaml:"build" json:"build"`
+
+	// Sequence gắn số thứ tự tăng dần (bắt đầu từ 1) vào field "seq", để sắp xếp đúng các entry
+	// có cùng timestamp (VD: khi thời gian bị cố định trong test, hoặc độ phân giải của timestamp
+	// quá thấp)
+	Sequence bool `mapstructure:"sequence" yaml:"sequence" json:"sequence"`
+}
+
+// Enabled kiểm tra có ít nhất một thông tin được gắn hay không.
+//
+// Trả về:
+//   - bool: true nếu Hostname, PID, GoroutineID, Build hoặc Sequence được bật
+func (c MetadataConfig) Enabled() bool {
+	return c.Hostname || c.PID || c.GoroutineID || c.Build || c.Sequence
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "hostname=true pid=true goroutine_id=false build=true sequence=false".
+func (c MetadataConfig) String() string {
+	return "hostname=" + strconv.FormatBool(c.Hostname) + " pid=" + strconv.FormatBool(c.PID) +
+		" goroutine_id=" + strconv.FormatBool(c.GoroutineID) + " build=" + strconv.FormatBool(c.Build) +
+		" sequence=" + strconv.FormatBool(c.Sequence)
+}
+
+// MetadataHook tạo một Hook gắn tên máy chủ, process ID, goroutine ID và thông tin build vào entry
+// theo cấu hình. Tên máy chủ, process ID và thông tin build được đọc một lần khi tạo hook.
+//
+// Tham số:
+//   - config: MetadataConfig - các thông tin cần gắn
+//
+// Trả về:
+//   - Hook: hook gắn metadata, nil nếu config không bật thông tin nào
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Worker", log.WithHooks(log.MetadataHook(log.MetadataConfig{Hostname: true, PID: true})))
+//	logger.Info("Job started") // [Worker] Job started hostname=web-1 pid=4242
+func MetadataHook(config MetadataConfig) Hook {
+	return metadataHook(config, new(atomic.Uint64))
+}
+
+// metadataHook tạo hook của MetadataHook với bộ đếm số thứ tự sequence, để manager giữ số thứ tự
+// tăng dần khi hook được tạo lại.
+func metadataHook(config MetadataConfig, sequence *atomic.Uint64) Hook {
+	if !config.Enabled() {
+		return nil
+	}
+
+	var static []Field
+	if config.Hostname {
+		if hostname, err := os.Hostname(); err == nil {
+			static = append(static, String(FieldHostname, hostname))
+		}
+	}
+	if config.PID {
+		static = append(static, Int(FieldPID, os.Getpid()))
+	}
+	if config.Build {
+		static = append(static, ReadBuildInfo().Fields()...)
+	}
+
+	return func(entry *Entry) error {
+		entry.Fields = append(entry.Fields, static...)
+		if config.GoroutineID {
+			entry.Fields = append(entry.Fields, Uint64(FieldGoroutineID, goroutineID()))
+		}
+		if config.Sequence {
+			entry.Fields = append(entry.Fields, Uint64(FieldSequence, sequence.Add(1)))
+		}
+		return nil
+	}
+}
+
+// goroutineID trả về ID của goroutine hiện tại, đọc từ dòng đầu "goroutine 42 [running]:" của
+// runtime.Stack. Trả về 0 nếu không đọc được.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// loggerHooks trả về các hook của logger: hook gắn metadata theo cấu hình (nếu bật) rồi các hook
+// đăng ký qua AddHook. Phải được gọi khi đang giữ m.mu.
+func (m *manager) loggerHooks() []Hook {
+	if m.metadata == nil {
+		return m.hooks
+	}
+	return append([]Hook{m.metadata}, m.hooks...)
+}
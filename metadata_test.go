@@ -0,0 +1,77 @@
+package log
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestManager_Metadata(t *testing.T) {
+	config := createTestConfig()
+	config.Metadata = MetadataConfig{PID: true, GoroutineID: true}
+	m := NewManager(config)
+	defer m.Close()
+
+	l := m.GetLogger("Worker")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("started")
+	want := "[Worker] started pid=" + strconv.Itoa(os.Getpid()) + " goroutine_id="
+	if got := h.entry.Message; !strings.HasPrefix(got, want) || strings.HasSuffix(got, "goroutine_id=0") {
+		t.Errorf("Entry nên được gắn pid và goroutine_id, got %q", got)
+	}
+
+	updated := *config
+	updated.Metadata = MetadataConfig{}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "metadata") {
+		t.Errorf("Diff nên liệt kê thay đổi của metadata, got %q", diff.String())
+	}
+	l.Info("stopped")
+	if got := h.entry.Message; got != "[Worker] stopped" {
+		t.Errorf("Tắt metadata nên áp dụng cho logger đã tồn tại, got %q", got)
+	}
+}
+
+func TestMetadataHook(t *testing.T) {
+	if MetadataHook(MetadataConfig{}) != nil {
+		t.Error("MetadataHook() không bật thông tin nào nên trả về nil")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("os.Hostname() error = %v", err)
+	}
+	l := NewLogger("API", WithHooks(MetadataHook(MetadataConfig{Hostname: true})))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("ok")
+	if got := h.entry.Message; got != "[API] ok hostname="+hostname {
+		t.Errorf("Entry nên được gắn hostname, got %q", got)
+	}
+}
+
+func TestBuildInfoHook(t *testing.T) {
+	if BuildInfoHook(BuildInfo{}) != nil {
+		t.Error("BuildInfoHook() với BuildInfo rỗng nên trả về nil")
+	}
+
+	l := NewLogger("App", WithHooks(BuildInfoHook(BuildInfo{Version: "v1.4.2", Commit: "9f2c1e0"})))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("started")
+	if got := h.entry.Message; got != "[App] started version=v1.4.2 commit=9f2c1e0" {
+		t.Errorf("Entry nên được gắn version và commit, got %q", got)
+	}
+	l.Info("migrated", String(FieldVersion, "v2"))
+	if got := h.entry.Message; got != "[App] migrated version=v2 commit=9f2c1e0" {
+		t.Errorf("Field cùng key của entry nên được ưu tiên, got %q", got)
+	}
+}
@@ -0,0 +1,120 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// MetricsObserver nhận các sự kiện của pipeline ghi log để xuất ra hệ thống giám sát (VD:
+// collector của package promlog). Các method được gọi đồng bộ trên goroutine ghi log, đồng
+// thời từ nhiều goroutine, nên phải nhanh và thread-safe.
+type MetricsObserver interface {
+	// ObserveEntry được gọi một lần cho mỗi entry được gửi đến các handler, sau hook và lấy mẫu.
+	//
+	// Tham số:
+	//   - context: string - context của logger
+	//   - level: handler.Level - cấp độ của entry
+	ObserveEntry(context string, level handler.Level)
+
+	// ObserveWrite được gọi sau mỗi lần gửi entry đến một handler. Với handler bất đồng bộ,
+	// duration là thời gian đưa entry vào hàng đợi.
+	//
+	// Tham số:
+	//   - handlerType: HandlerType - tên handler
+	//   - duration: time.Duration - thời gian ghi
+	//   - err: error - lỗi ghi, nil nếu thành công
+	ObserveWrite(handlerType HandlerType, duration time.Duration, err error)
+}
+
+// metricsReporter đếm entry và lần ghi theo handler, và giữ MetricsObserver dùng chung giữa
+// manager và các logger của nó.
+type metricsReporter struct {
+	observer atomic.Pointer[MetricsObserver] // nil = không thu thập metrics
+	entries  atomic.Uint64                   // Tổng số entry được gửi đến handler
+	writes   sync.Map                        // HandlerType -> *writeCounter
+}
+
+// writeCounter đếm số lần ghi và số lần ghi thất bại của một handler.
+type writeCounter struct {
+	writes atomic.Uint64
+	errors atomic.Uint64
+}
+
+// counter trả về bộ đếm của handlerType, tạo mới nếu chưa có.
+func (r *metricsReporter) counter(handlerType HandlerType) *writeCounter {
+	if c, ok := r.writes.Load(handlerType); ok {
+		return c.(*writeCounter)
+	}
+	c, _ := r.writes.LoadOrStore(handlerType, &writeCounter{})
+	return c.(*writeCounter)
+}
+
+// write đếm một lần ghi đến handlerType.
+func (r *metricsReporter) write(handlerType HandlerType, err error) {
+	c := r.counter(handlerType)
+	c.writes.Add(1)
+	if err != nil {
+		c.errors.Add(1)
+	}
+}
+
+// load trả về observer hiện tại, nil nếu chưa đặt.
+func (r *metricsReporter) load() MetricsObserver {
+	if o := r.observer.Load(); o != nil {
+		return *o
+	}
+	return nil
+}
+
+// set thay thế observer, nil để tắt.
+func (r *metricsReporter) set(o MetricsObserver) {
+	if o == nil {
+		r.observer.Store(nil)
+		return
+	}
+	r.observer.Store(&o)
+}
+
+// WithMetricsObserver đặt observer nhận các sự kiện ghi log của logger. Logger do Manager tạo
+// dùng observer của Manager.SetMetricsObserver.
+//
+// Tham số:
+//   - o: MetricsObserver - observer nhận sự kiện, nil để tắt
+//
+// Trả về:
+//   - LoggerOption: tùy chọn đặt observer
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Worker", log.WithMetricsObserver(collector))
+func WithMetricsObserver(o MetricsObserver) LoggerOption {
+	return func(l *logger) {
+		l.metrics = &metricsReporter{}
+		l.metrics.set(o)
+	}
+}
+
+// withMetricsReporter dùng reporter của manager cho logger.
+func withMetricsReporter(r *metricsReporter) LoggerOption {
+	return func(l *logger) {
+		l.metrics = r
+	}
+}
+
+// SetMetricsObserver đặt observer nhận các sự kiện ghi log của mọi logger của manager (kể cả
+// logger đã tạo). Mặc định không có observer và thời gian ghi không được đo; các bộ đếm của
+// Stats vẫn được cập nhật. Method này là thread-safe.
+//
+// Tham số:
+//   - o: MetricsObserver - observer nhận sự kiện, nil để tắt
+//
+// Ví dụ:
+//
+//	collector := promlog.NewCollector(manager) // gọi SetMetricsObserver
+//	prometheus.MustRegister(collector)
+func (m *manager) SetMetricsObserver(o MetricsObserver) {
+	m.metrics.set(o)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.fork.vn/log"
+)
+
+// maxRequestIDLength là độ dài tối đa của request ID nhận từ client; ID dài hơn được thay
+// bằng ID mới.
+const maxRequestIDLength = 128
+
+// RequestID tạo một middleware net/http gán request ID cho mỗi request: ID được lấy từ header
+// của request hoặc tạo mới bằng log.NewRequestID, lưu vào context bằng log.WithRequestID và
+// trả lại cho client qua cùng header. Middleware đăng ký field request_id với manager, nên mọi
+// logger của manager gắn ID vào entry được ghi qua các method *Context với context của request.
+//
+// Access log của New ghi ID mà RequestID đã chọn khi RequestID nằm bên trong New.
+//
+// Tham số:
+//   - manager: log.Manager - manager cần gắn request ID vào entry
+//   - header: string - header chứa request ID (rỗng để dùng "X-Request-ID")
+//
+// Trả về:
+//   - func(http.Handler) http.Handler: middleware bọc một http.Handler
+//
+// Ví dụ:
+//
+//	handler := middleware.New(manager, nil)(middleware.RequestID(manager, "")(mux))
+//
+//	// Trong handler
+//	logger.InfoContext(r.Context(), "Order created") // [Order] Order created request_id=...
+func RequestID(manager log.Manager, header string) func(http.Handler) http.Handler {
+	if manager == nil {
+		panic("manager cannot be nil")
+	}
+	if header == "" {
+		header = "X-Request-ID"
+	}
+	manager.AddContextField(log.ContextKey(log.FieldRequestID), log.FieldRequestID)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if !validRequestID(id) {
+				id = log.NewRequestID()
+			}
+			w.Header().Set(header, id)
+			next.ServeHTTP(w, r.WithContext(log.WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID cho biết request ID nhận từ client có thể được dùng lại: không rỗng, không
+// quá maxRequestIDLength và chỉ gồm ký tự ASCII in được, để không thể chèn dòng giả vào log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
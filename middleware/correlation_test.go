@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log"
+)
+
+func TestRequestID(t *testing.T) {
+	m, capture := newTestManager(t)
+	orders := m.GetLogger("Order")
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orders.InfoContext(r.Context(), "created")
+	})
+	// Đăng ký hai lần không được gắn request_id hai lần
+	RequestID(m, "")
+	h := New(m, nil)(RequestID(m, "")(app))
+	m.AddHandler("capture", capture)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Request-ID"); got != "abc-123" {
+		t.Errorf("RequestID() nên trả lại ID của client, got %q", got)
+	}
+	if capture.Len() != 2 || capture.Entries()[0].Message != "[Order] created request_id=abc-123" {
+		t.Fatalf("Logger nên gắn request ID từ context đúng một lần, got %v", capture.Entries())
+	}
+
+	capture.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-ID", "forged\nline")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	id := rec.Header().Get("X-Request-ID")
+	if len(id) != 32 {
+		t.Fatalf("RequestID() nên tạo ID mới thay cho ID không hợp lệ, got %q", id)
+	}
+	if capture.Entries()[0].Message != "[Order] created request_id="+id {
+		t.Errorf("Logger nên gắn ID được tạo mới, got %q", capture.Entries()[0].Message)
+	}
+	if !strings.Contains(capture.Entries()[1].Message, "request_id="+id) {
+		t.Errorf("Access log nên ghi ID được tạo mới, got %q", capture.Entries()[1].Message)
+	}
+}
+
+func TestRequestID_CustomHeader(t *testing.T) {
+	m, _ := newTestManager(t)
+	var got string
+	h := RequestID(m, "X-Correlation-ID")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = log.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Correlation-ID", "corr-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got != "corr-1" || rec.Header().Get("X-Correlation-ID") != "corr-1" {
+		t.Errorf("RequestID() nên dùng header đã cấu hình, got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+// Package echolog cung cấp adapter cho phép Echo sử dụng log.Manager làm access logger và error logger.
+//
+// Lỗi trả về bởi handler được chuyển đến HTTPErrorHandler của Echo trước khi ghi log,
+// nhờ đó access log ghi nhận đúng status code cuối cùng cùng với lỗi trong field error.
+//
+// Ví dụ:
+//
+//	e := echo.New()
+//	e.Use(echolog.New(manager, nil))
+//	e.Use(echomiddleware.Recover())
+package echolog
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/middleware"
+)
+
+// New tạo một middleware Echo ghi access log cho mỗi request.
+//
+// Config.Skip được áp dụng trên c.Request(). Lỗi trả về bởi handler được xử lý qua
+// c.Error và không được trả tiếp cho các middleware bên ngoài, giống middleware
+// Logger của Echo. Các middleware recover nên được đăng ký sau New để panic được
+// ghi nhận như lỗi của request.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger cho access log
+//   - config: *middleware.Config - cấu hình access log (nil để dùng middleware.DefaultConfig)
+//
+// Trả về:
+//   - echo.MiddlewareFunc: middleware ghi access log
+//
+// Ví dụ:
+//
+//	e.Use(echolog.New(manager, middleware.DefaultConfig()))
+func New(manager log.Manager, config *middleware.Config) echo.MiddlewareFunc {
+	recorder := middleware.NewRecorder(manager, config)
+	config = recorder.Config()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skip != nil && config.Skip(c.Request()) {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			res := c.Response()
+			req := config.NewRequest(c.Request())
+			req.Status = res.Status
+			req.Bytes = res.Size
+			req.Latency = time.Since(start)
+			req.Err = err
+			recorder.Record(req)
+
+			return nil
+		}
+	}
+}
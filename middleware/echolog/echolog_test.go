@@ -0,0 +1,94 @@
+package echolog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+	"go.fork.vn/log/middleware"
+)
+
+// newTestEcho tạo echo instance với access log dùng manager cho testing.
+func newTestEcho(t *testing.T, config *middleware.Config) (*echo.Echo, *handler.MemoryHandler) {
+	logConfig := log.DefaultConfig()
+	logConfig.Console.Enabled = false
+	logConfig.File.Enabled = true
+	logConfig.File.Path = t.TempDir() + "/app.log"
+
+	m := log.NewManager(logConfig)
+	t.Cleanup(func() { m.Close() })
+
+	e := echo.New()
+	e.Use(New(m, config))
+
+	capture := handler.NewMemoryHandler()
+	m.AddHandler("capture", capture)
+	return e, capture
+}
+
+func TestNew_LogsRequest(t *testing.T) {
+	e, capture := newTestEcho(t, nil)
+	e.POST("/users", func(c echo.Context) error {
+		return c.String(http.StatusCreated, "hello")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Request-ID", "abc-123")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	for _, want := range []string{"[HTTP] HTTP request", "method=POST", "path=/users", "status=201", "bytes=5", "remote_ip=10.0.0.1", "request_id=abc-123"} {
+		if !strings.Contains(capture.Entries()[0].Message, want) {
+			t.Errorf("Log entry thiếu %q, got %q", want, capture.Entries()[0].Message)
+		}
+	}
+	if capture.Entries()[0].Level != handler.InfoLevel {
+		t.Errorf("Status 201 nên log ở InfoLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestNew_HandlesReturnedError(t *testing.T) {
+	e, capture := newTestEcho(t, nil)
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Lỗi nên được xử lý bởi HTTPErrorHandler, got status %d", w.Code)
+	}
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	if capture.Entries()[0].Level != handler.WarningLevel {
+		t.Errorf("Status 404 nên log ở WarningLevel, got %v", capture.Entries()[0].Level)
+	}
+	if !strings.Contains(capture.Entries()[0].Message, "status=404") || !strings.Contains(capture.Entries()[0].Message, "error=") {
+		t.Errorf("Log entry nên chứa status cuối cùng và lỗi, got %q", capture.Entries()[0].Message)
+	}
+}
+
+func TestNew_Skip(t *testing.T) {
+	config := middleware.DefaultConfig()
+	config.Skip = func(r *http.Request) bool { return r.URL.Path == "/health" }
+
+	e, capture := newTestEcho(t, config)
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if capture.Len() != 0 {
+		t.Errorf("Request bị skip không nên được ghi log, got %v", capture.Entries())
+	}
+}
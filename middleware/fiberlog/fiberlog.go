@@ -0,0 +1,103 @@
+// Package fiberlog cung cấp adapter cho phép Fiber sử dụng log.Manager làm access logger và error logger.
+//
+// Fiber không dựa trên net/http nên Config.Skip của middleware.Config không được sử dụng;
+// thay vào đó Config.Next theo quy ước của các middleware Fiber. Lỗi trả về bởi
+// handler được chuyển đến ErrorHandler của ứng dụng trước khi ghi log.
+//
+// Ví dụ:
+//
+//	app := fiber.New()
+//	app.Use(fiberlog.New(manager, nil))
+//	app.Use(recover.New())
+package fiberlog
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/middleware"
+)
+
+// Config định nghĩa cấu hình cho adapter Fiber.
+type Config struct {
+	// Middleware cấu hình access log dùng chung với middleware net/http.
+	// Trường Skip của cấu hình này không được sử dụng.
+	Middleware *middleware.Config
+
+	// Next bỏ qua việc ghi log cho request khi trả về true (VD: health check)
+	Next func(c *fiber.Ctx) bool
+}
+
+// DefaultConfig trả về cấu hình mặc định cho adapter Fiber.
+//
+// Trả về:
+//   - *Config: cấu hình với Middleware là middleware.DefaultConfig()
+func DefaultConfig() *Config {
+	return &Config{
+		Middleware: middleware.DefaultConfig(),
+	}
+}
+
+// New tạo một middleware Fiber ghi access log cho mỗi request.
+//
+// Lỗi trả về bởi handler được xử lý qua ErrorHandler của ứng dụng và không được trả
+// tiếp cho các middleware bên ngoài, giống middleware logger của Fiber.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger cho access log
+//   - config: *Config - cấu hình adapter (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - fiber.Handler: middleware ghi access log
+//
+// Ví dụ:
+//
+//	config := fiberlog.DefaultConfig()
+//	config.Next = func(c *fiber.Ctx) bool { return c.Path() == "/health" }
+//	app.Use(fiberlog.New(manager, config))
+func New(manager log.Manager, config *Config) fiber.Handler {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	recorder := middleware.NewRecorder(manager, config.Middleware)
+	mwConfig := recorder.Config()
+
+	return func(c *fiber.Ctx) error {
+		if config.Next != nil && config.Next(c) {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			if herr := c.App().Config().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// Chuỗi của fiber trỏ vào buffer của fasthttp, được dùng lại sau khi handler trả về, nên
+		// phải được sao chép trước khi nằm trong field của entry (VD: trong hàng đợi async)
+		header := func(key string) string { return utils.CopyString(c.Get(key)) }
+		req := &middleware.Request{
+			Method:    utils.CopyString(c.Method()),
+			Path:      utils.CopyString(c.Path()),
+			Status:    c.Response().StatusCode(),
+			Latency:   time.Since(start),
+			Bytes:     int64(len(c.Response().Body())),
+			RemoteIP:  mwConfig.ClientIP(c.Context().RemoteAddr().String(), header),
+			UserAgent: header(fiber.HeaderUserAgent),
+			Referer:   header(fiber.HeaderReferer),
+			Proto:     string(c.Request().Header.Protocol()),
+			Err:       err,
+		}
+		if mwConfig.RequestIDHeader != "" {
+			req.RequestID = header(mwConfig.RequestIDHeader)
+		}
+		recorder.Record(req)
+
+		return nil
+	}
+}
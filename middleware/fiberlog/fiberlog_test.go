@@ -0,0 +1,154 @@
+package fiberlog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// newTestApp tạo fiber app với access log dùng manager cho testing.
+func newTestApp(t *testing.T, config *Config) (*fiber.App, *handler.MemoryHandler) {
+	logConfig := log.DefaultConfig()
+	logConfig.Console.Enabled = false
+	logConfig.File.Enabled = true
+	logConfig.File.Path = t.TempDir() + "/app.log"
+
+	m := log.NewManager(logConfig)
+	t.Cleanup(func() { m.Close() })
+
+	app := fiber.New()
+	app.Use(New(m, config))
+
+	capture := handler.NewMemoryHandler()
+	m.AddHandler("capture", capture)
+	return app, capture
+}
+
+func TestNew_LogsRequest(t *testing.T) {
+	app, capture := newTestApp(t, nil)
+	app.Post("/users", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).SendString("hello")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	for _, want := range []string{"[HTTP] HTTP request", "method=POST", "path=/users", "status=201", "bytes=5", "request_id=abc-123"} {
+		if !strings.Contains(capture.Entries()[0].Message, want) {
+			t.Errorf("Log entry thiếu %q, got %q", want, capture.Entries()[0].Message)
+		}
+	}
+	if capture.Entries()[0].Level != handler.InfoLevel {
+		t.Errorf("Status 201 nên log ở InfoLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestNew_HandlesReturnedError(t *testing.T) {
+	app, capture := newTestApp(t, nil)
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusBadGateway, "upstream failed")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadGateway {
+		t.Errorf("Lỗi nên được xử lý bởi ErrorHandler, got status %d", resp.StatusCode)
+	}
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	if capture.Entries()[0].Level != handler.ErrorLevel {
+		t.Errorf("Status 502 nên log ở ErrorLevel, got %v", capture.Entries()[0].Level)
+	}
+	if !strings.Contains(capture.Entries()[0].Message, `error="upstream failed"`) {
+		t.Errorf("Log entry nên chứa lỗi trả về, got %q", capture.Entries()[0].Message)
+	}
+}
+
+func TestNew_Next(t *testing.T) {
+	config := DefaultConfig()
+	config.Next = func(c *fiber.Ctx) bool { return c.Path() == "/health" }
+
+	app, capture := newTestApp(t, config)
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/health", nil)); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if capture.Len() != 0 {
+		t.Errorf("Request bị bỏ qua bởi Next không nên được ghi log, got %v", capture.Entries())
+	}
+}
+
+// gateHandler giữ các entry cho đến khi gate được mở, giả lập hàng đợi bất đồng bộ chưa được xử lý
+type gateHandler struct {
+	*handler.MemoryHandler
+	gate chan struct{}
+}
+
+func (h *gateHandler) LogEntry(entry *handler.Entry) error {
+	<-h.gate
+	return h.MemoryHandler.LogEntry(entry)
+}
+
+func TestNew_AsyncHandlerKeepsRequestValues(t *testing.T) {
+	gated := &gateHandler{MemoryHandler: handler.NewMemoryHandler(), gate: make(chan struct{})}
+	async := handler.NewAsyncHandler(gated, 1, 64)
+	t.Cleanup(func() { async.Close() })
+	app := fiber.New()
+	app.Use(New(logManager(t, async), nil))
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	const requests = 20
+	for i := 0; i < requests; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/%03d", i), nil)
+		req.Header.Set("X-Request-ID", fmt.Sprintf("req-%03d", i))
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("app.Test() error = %v", err)
+		}
+	}
+	close(gated.gate)
+	async.Close()
+
+	entries := gated.Entries()
+	if len(entries) != requests {
+		t.Fatalf("Handler bất đồng bộ nên nhận %d entry, got %d", requests, len(entries))
+	}
+	for i, entry := range entries {
+		want := map[string]string{"path": fmt.Sprintf("/orders/%03d", i), "request_id": fmt.Sprintf("req-%03d", i), "method": "GET"}
+		for _, f := range entry.Fields {
+			if v, ok := want[f.Key]; ok && fmt.Sprint(f.Interface()) != v {
+				t.Errorf("Entry %d: field %s = %q sau khi request kết thúc, want %q", i, f.Key, f.Interface(), v)
+			}
+		}
+	}
+}
+
+// logManager tạo manager chỉ ghi qua h cho logger của access log.
+func logManager(t *testing.T, h handler.Handler) log.Manager {
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	config.File.Enabled = true
+	config.File.Path = t.TempDir() + "/app.log"
+	m := log.NewManager(config)
+	t.Cleanup(func() { m.Close() })
+	m.AddHandler("async", h, log.WithExternalOwnership())
+	return m
+}
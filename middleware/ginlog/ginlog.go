@@ -0,0 +1,104 @@
+// Package ginlog cung cấp adapter cho phép Gin sử dụng log.Manager làm access logger và error logger.
+//
+// New ghi access log cho mỗi request với cùng tập field và quy tắc chọn cấp độ như
+// middleware net/http; lỗi được đính kèm qua c.Error được ghi trong field error.
+// Recovery thay thế gin.Recovery, ghi panic cùng stack trace thông qua logger.
+//
+// Ví dụ:
+//
+//	router := gin.New()
+//	router.Use(ginlog.New(manager, nil), ginlog.Recovery(manager, nil))
+package ginlog
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/middleware"
+)
+
+// New tạo một middleware Gin ghi access log cho mỗi request.
+//
+// Config.Skip được áp dụng trên c.Request. Lỗi cuối cùng trong c.Errors được ghi
+// vào field error khi field này được cấu hình.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger cho access log
+//   - config: *middleware.Config - cấu hình access log (nil để dùng middleware.DefaultConfig)
+//
+// Trả về:
+//   - gin.HandlerFunc: middleware ghi access log
+//
+// Ví dụ:
+//
+//	router.Use(ginlog.New(manager, middleware.DefaultConfig()))
+func New(manager log.Manager, config *middleware.Config) gin.HandlerFunc {
+	recorder := middleware.NewRecorder(manager, config)
+	config = recorder.Config()
+
+	return func(c *gin.Context) {
+		if config.Skip != nil && config.Skip(c.Request) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		req := config.NewRequest(c.Request)
+		req.Status = c.Writer.Status()
+		req.Latency = time.Since(start)
+		if size := c.Writer.Size(); size > 0 {
+			req.Bytes = int64(size)
+		}
+		if last := c.Errors.Last(); last != nil {
+			req.Err = last.Err
+		}
+		recorder.Record(req)
+	}
+}
+
+// Recovery tạo một middleware Gin phục hồi từ panic và ghi panic qua logger.
+//
+// Panic được ghi ở cấp Error kèm method, path, giá trị panic và stack trace, sau đó
+// request bị hủy với status 500. Middleware này nên được đăng ký sau New để access
+// log ghi nhận status 500 của request bị panic.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger
+//   - config: *middleware.Config - cấu hình cung cấp context của logger (nil để dùng middleware.DefaultConfig)
+//
+// Trả về:
+//   - gin.HandlerFunc: middleware phục hồi panic
+func Recovery(manager log.Manager, config *middleware.Config) gin.HandlerFunc {
+	if manager == nil {
+		panic("manager cannot be nil")
+	}
+	if config == nil {
+		config = middleware.DefaultConfig()
+	}
+	logger := manager.GetLogger(config.Context)
+
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				logger.Error("panic recovered",
+					log.Any(string(middleware.FieldMethod), c.Request.Method),
+					log.Any(string(middleware.FieldPath), c.Request.URL.Path),
+					log.Any("panic", fmt.Sprint(rec)),
+					log.Any("stack", string(debug.Stack())),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
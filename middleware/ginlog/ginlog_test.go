@@ -0,0 +1,101 @@
+package ginlog
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// newTestRouter tạo gin router với access log và recovery dùng manager cho testing.
+func newTestRouter(t *testing.T) (*gin.Engine, *handler.MemoryHandler) {
+	gin.SetMode(gin.TestMode)
+
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	config.File.Enabled = true
+	config.File.Path = t.TempDir() + "/app.log"
+
+	m := log.NewManager(config)
+	t.Cleanup(func() { m.Close() })
+
+	router := gin.New()
+	router.Use(New(m, nil), Recovery(m, nil))
+
+	capture := handler.NewMemoryHandler()
+	m.AddHandler("capture", capture)
+	return router, capture
+}
+
+func TestNew_LogsRequest(t *testing.T) {
+	router, capture := newTestRouter(t)
+	router.GET("/users", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Request-ID", "abc-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	for _, want := range []string{"[HTTP] HTTP request", "method=GET", "path=/users", "status=200", "bytes=5", "remote_ip=10.0.0.1", "request_id=abc-123"} {
+		if !strings.Contains(capture.Entries()[0].Message, want) {
+			t.Errorf("Log entry thiếu %q, got %q", want, capture.Entries()[0].Message)
+		}
+	}
+	if capture.Entries()[0].Level != handler.InfoLevel {
+		t.Errorf("Status 200 nên log ở InfoLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestNew_LogsContextError(t *testing.T) {
+	router, capture := newTestRouter(t)
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("db down"))
+		c.Status(http.StatusServiceUnavailable)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	if capture.Entries()[0].Level != handler.ErrorLevel {
+		t.Errorf("Status 503 nên log ở ErrorLevel, got %v", capture.Entries()[0].Level)
+	}
+	if !strings.Contains(capture.Entries()[0].Message, `error="db down"`) {
+		t.Errorf("Log entry nên chứa lỗi của c.Errors, got %q", capture.Entries()[0].Message)
+	}
+}
+
+func TestRecovery_LogsPanic(t *testing.T) {
+	router, capture := newTestRouter(t)
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Request bị panic nên trả về 500, got %d", w.Code)
+	}
+	if capture.Len() != 2 {
+		t.Fatalf("Nên ghi 1 log panic và 1 access log, got %v", capture.Entries())
+	}
+	if !strings.Contains(capture.Entries()[0].Message, "panic=boom") || !strings.Contains(capture.Entries()[0].Message, "stack=") {
+		t.Errorf("Log panic nên chứa giá trị panic và stack trace, got %q", capture.Entries()[0].Message)
+	}
+	if !strings.Contains(capture.Entries()[1].Message, "status=500") {
+		t.Errorf("Access log nên ghi nhận status 500, got %q", capture.Entries()[1].Message)
+	}
+}
@@ -0,0 +1,450 @@
+// Package middleware cung cấp các middleware net/http ghi access log thông qua log.Manager.
+//
+// Middleware ghi lại method, path, status, latency, số byte phản hồi, IP client
+// và request ID của mỗi request dưới dạng các field có cấu trúc. Cấp độ log được
+// chọn theo status code: 5xx là Error, 4xx là Warning, còn lại là Info.
+//
+// Ví dụ:
+//
+//	manager := log.NewManager(config)
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", middleware.New(manager, nil)(mux))
+//
+// Các adapter cho Gin, Echo và Fiber nằm trong các package con ginlog, echolog
+// và fiberlog, dùng chung Config và Recorder của package này.
+package middleware
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"go.fork.vn/log"
+)
+
+// Field định danh một field có thể được ghi bởi middleware.
+type Field string
+
+// Các field được hỗ trợ bởi middleware.
+const (
+	FieldMethod    Field = "method"     // HTTP method của request
+	FieldPath      Field = "path"       // Đường dẫn URL của request
+	FieldStatus    Field = "status"     // Status code của response
+	FieldLatency   Field = "latency"    // Thời gian xử lý request
+	FieldBytes     Field = "bytes"      // Số byte đã ghi vào response body
+	FieldRemoteIP  Field = "remote_ip"  // Địa chỉ IP của client
+	FieldRequestID Field = "request_id" // Request ID lấy từ header
+	FieldUserAgent Field = "user_agent" // User-Agent của client
+	FieldReferer   Field = "referer"    // Referer của request
+	FieldProto     Field = "proto"      // Phiên bản giao thức của request (VD: HTTP/1.1)
+	FieldError     Field = "error"      // Lỗi trả về bởi handler (chỉ có ở các adapter framework)
+)
+
+// DefaultFields là tập field được ghi khi Config.Fields rỗng.
+var DefaultFields = []Field{
+	FieldMethod,
+	FieldPath,
+	FieldStatus,
+	FieldLatency,
+	FieldBytes,
+	FieldRemoteIP,
+	FieldRequestID,
+	FieldError,
+}
+
+// CombinedFields là tập field cần cho định dạng access log "common" và "combined" của file
+// handler (handler.CommonLogFormat, handler.CombinedLogFormat).
+//
+// Ví dụ:
+//
+//	config := middleware.DefaultConfig()
+//	config.Fields = middleware.CombinedFields
+//	// channels.access.format: combined
+//	// 203.0.113.7 - - [01/Mar/2024:12:00:00 +0000] "GET /orders HTTP/1.1" 200 512 "-" "curl/8.5.0"
+var CombinedFields = []Field{
+	FieldRemoteIP,
+	FieldMethod,
+	FieldPath,
+	FieldProto,
+	FieldStatus,
+	FieldBytes,
+	FieldReferer,
+	FieldUserAgent,
+	FieldLatency,
+	FieldRequestID,
+}
+
+// Config định nghĩa cấu hình cho HTTP logging middleware.
+type Config struct {
+	// Context context của logger dùng để ghi access log
+	Context string
+
+	// Message thông điệp của mỗi bản ghi access log
+	Message string
+
+	// Fields tập field được ghi, theo thứ tự khai báo
+	Fields []Field
+
+	// RequestIDHeader header chứa request ID
+	RequestIDHeader string
+
+	// TrustProxyHeaders lấy IP client từ X-Forwarded-For/X-Real-IP khi được bật.
+	// Chỉ nên bật khi ứng dụng chạy sau một reverse proxy tin cậy.
+	TrustProxyHeaders bool
+
+	// TrustedProxies các dải địa chỉ của reverse proxy tin cậy khi TrustProxyHeaders được bật.
+	// Rỗng = chỉ tin cậy kết nối trực tiếp là một proxy; khi được đặt, proxy header chỉ được đọc
+	// nếu kết nối đến từ một proxy tin cậy
+	TrustedProxies []netip.Prefix
+
+	// Skip cho phép bỏ qua việc ghi log cho một số request (VD: health check)
+	Skip func(r *http.Request) bool
+}
+
+// DefaultConfig trả về cấu hình mặc định cho middleware.
+//
+// Cấu hình mặc định sử dụng:
+//   - Context: "HTTP" (log.AccessContext, thuộc channel "access")
+//   - Message: "HTTP request"
+//   - Fields: DefaultFields
+//   - RequestIDHeader: "X-Request-ID"
+//
+// Trả về:
+//   - *Config: cấu hình mặc định
+func DefaultConfig() *Config {
+	return &Config{
+		Context:         log.AccessContext,
+		Message:         "HTTP request",
+		Fields:          DefaultFields,
+		RequestIDHeader: "X-Request-ID",
+	}
+}
+
+// New tạo một middleware net/http ghi access log cho mỗi request.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger cho access log
+//   - config: *Config - cấu hình middleware (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - func(http.Handler) http.Handler: middleware bọc một http.Handler
+//
+// Ví dụ:
+//
+//	config := middleware.DefaultConfig()
+//	config.Fields = []middleware.Field{middleware.FieldMethod, middleware.FieldPath, middleware.FieldStatus}
+//	handler := middleware.New(manager, config)(mux)
+func New(manager log.Manager, config *Config) func(http.Handler) http.Handler {
+	recorder := NewRecorder(manager, config)
+	config = recorder.Config()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.Skip != nil && config.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			req := config.NewRequest(r)
+			if id := rw.Header().Get(config.RequestIDHeader); id != "" && config.RequestIDHeader != "" {
+				// ID được RequestID chấp nhận hoặc tạo mới nằm trong header của response
+				req.RequestID = id
+			}
+			req.Status = rw.status
+			req.Bytes = rw.bytes
+			req.Latency = time.Since(start)
+			recorder.Record(req)
+		})
+	}
+}
+
+// Request mô tả một request đã được xử lý xong, độc lập với framework HTTP.
+//
+// Request cho phép các adapter framework (ginlog, echolog, fiberlog) ghi access log
+// với cùng tập field và quy tắc chọn cấp độ như middleware net/http.
+type Request struct {
+	Method    string        // HTTP method của request
+	Path      string        // Đường dẫn URL của request
+	Status    int           // Status code của response
+	Latency   time.Duration // Thời gian xử lý request
+	Bytes     int64         // Số byte đã ghi vào response body
+	RemoteIP  string        // Địa chỉ IP của client
+	RequestID string        // Request ID lấy từ header (rỗng nếu không có)
+	UserAgent string        // User-Agent của client (rỗng nếu không có)
+	Referer   string        // Referer của request (rỗng nếu không có)
+	Proto     string        // Phiên bản giao thức của request (VD: HTTP/1.1)
+	Err       error         // Lỗi trả về bởi handler của framework (nil nếu không có)
+}
+
+// NewRequest tạo Request từ một *http.Request, điền sẵn các field lấy từ request.
+//
+// Các field thuộc về response (Status, Bytes, Latency, Err) cần được điền bởi người gọi.
+//
+// Tham số:
+//   - r: *http.Request - request đang được xử lý
+//
+// Trả về:
+//   - *Request: request với Method, Path, RemoteIP, RequestID, UserAgent, Referer và Proto đã được điền
+func (c *Config) NewRequest(r *http.Request) *Request {
+	req := &Request{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Status:    http.StatusOK,
+		RemoteIP:  c.ClientIP(r.RemoteAddr, r.Header.Get),
+		UserAgent: r.UserAgent(),
+		Referer:   r.Referer(),
+		Proto:     r.Proto,
+	}
+	if c.RequestIDHeader != "" {
+		req.RequestID = r.Header.Get(c.RequestIDHeader)
+	}
+	return req
+}
+
+// ClientIP trả về IP của client, ưu tiên các proxy header khi được tin cậy.
+//
+// X-Forwarded-For được đọc từ phải sang trái và địa chỉ đầu tiên không thuộc TrustedProxies được
+// chọn, vì client có thể tự thêm các địa chỉ giả vào đầu header; khi TrustedProxies rỗng, địa chỉ
+// cuối cùng (do proxy trực tiếp thêm vào) được chọn.
+//
+// Tham số:
+//   - remoteAddr: string - địa chỉ của kết nối, dạng host:port hoặc host
+//   - header: func(string) string - hàm đọc giá trị header của request
+//
+// Trả về:
+//   - string: IP của client
+//
+// Ví dụ:
+//
+//	config.TrustProxyHeaders = true
+//	config.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+//	// RemoteAddr 10.0.0.2:443, X-Forwarded-For: 1.2.3.4, 203.0.113.7, 10.0.0.1
+//	ip := config.ClientIP(r.RemoteAddr, r.Header.Get) // "203.0.113.7"
+func (c *Config) ClientIP(remoteAddr string, header func(string) string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if !c.TrustProxyHeaders || len(c.TrustedProxies) > 0 && !c.trustedProxy(host) {
+		return host
+	}
+
+	if xff := header("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if i == 0 || !c.trustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+	if ip := header("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return host
+}
+
+// trustedProxy kiểm tra ip có thuộc một dải trong TrustedProxies hay không.
+func (c *Config) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range c.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP trả về IP của client cho một *http.Request.
+func (c *Config) remoteIP(r *http.Request) string {
+	return c.ClientIP(r.RemoteAddr, r.Header.Get)
+}
+
+// Recorder ghi access log cho các Request đã xử lý xong theo một Config.
+//
+// Recorder là phần dùng chung giữa middleware net/http và các adapter framework.
+// Recorder an toàn khi được sử dụng đồng thời từ nhiều goroutine.
+type Recorder struct {
+	logger log.Logger
+	config *Config
+	fields []Field
+}
+
+// NewRecorder tạo Recorder ghi access log thông qua manager.
+//
+// Tham số:
+//   - manager: log.Manager - manager cung cấp logger cho access log
+//   - config: *Config - cấu hình access log (nil để dùng DefaultConfig)
+//
+// Trả về:
+//   - *Recorder: recorder đã được khởi tạo
+func NewRecorder(manager log.Manager, config *Config) *Recorder {
+	if manager == nil {
+		panic("manager cannot be nil")
+	}
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	fields := config.Fields
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+
+	return &Recorder{
+		logger: manager.GetLogger(config.Context),
+		config: config,
+		fields: fields,
+	}
+}
+
+// Config trả về cấu hình mà recorder đang sử dụng.
+//
+// Trả về:
+//   - *Config: cấu hình của recorder, không bao giờ nil
+func (rec *Recorder) Config() *Config {
+	return rec.config
+}
+
+// Record ghi một bản ghi access log cho request.
+//
+// Cấp độ log được chọn theo status code: 5xx là Error, 4xx là Warning, còn lại là Info.
+// Request có Err nhưng status code không báo lỗi (dưới 400) cũng được ghi ở cấp Error
+// để lỗi không bị che khuất bởi một response thành công.
+//
+// Tham số:
+//   - req: *Request - request đã xử lý xong
+func (rec *Recorder) Record(req *Request) {
+	args := make([]interface{}, 0, len(rec.fields))
+	for _, field := range rec.fields {
+		if f, ok := req.field(field); ok {
+			args = append(args, f)
+		}
+	}
+
+	switch {
+	case req.Status >= http.StatusInternalServerError:
+		rec.logger.Error(rec.config.Message, args...)
+	case req.Status >= http.StatusBadRequest:
+		rec.logger.Warning(rec.config.Message, args...)
+	case req.Err != nil:
+		rec.logger.Error(rec.config.Message, args...)
+	default:
+		rec.logger.Info(rec.config.Message, args...)
+	}
+}
+
+// field tạo log field tương ứng cho request.
+//
+// Trả về:
+//   - log.Field: field đã được tạo
+//   - bool: false nếu field không có giá trị (VD: request không có request ID)
+func (req *Request) field(name Field) (log.Field, bool) {
+	switch name {
+	case FieldMethod:
+		return log.Any(string(name), req.Method), true
+	case FieldPath:
+		return log.Any(string(name), req.Path), true
+	case FieldStatus:
+		return log.Any(string(name), req.Status), true
+	case FieldLatency:
+		return log.Any(string(name), req.Latency), true
+	case FieldBytes:
+		return log.Any(string(name), req.Bytes), true
+	case FieldRemoteIP:
+		return log.Any(string(name), req.RemoteIP), true
+	case FieldRequestID:
+		if req.RequestID != "" {
+			return log.Any(string(name), req.RequestID), true
+		}
+	case FieldUserAgent:
+		if req.UserAgent != "" {
+			return log.Any(string(name), req.UserAgent), true
+		}
+	case FieldReferer:
+		if req.Referer != "" {
+			return log.Any(string(name), req.Referer), true
+		}
+	case FieldProto:
+		if req.Proto != "" {
+			return log.Any(string(name), req.Proto), true
+		}
+	case FieldError:
+		if req.Err != nil {
+			return log.Any(string(name), req.Err), true
+		}
+	}
+	return log.Field{}, false
+}
+
+// responseWriter bọc http.ResponseWriter để ghi nhận status code và số byte đã ghi.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader ghi nhận status code trước khi chuyển tiếp.
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write ghi nhận số byte đã ghi vào response body.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush chuyển tiếp đến http.Flusher của writer gốc (VD: streaming, Server-Sent Events), không
+// làm gì nếu writer gốc không hỗ trợ.
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Hijack chuyển tiếp đến http.Hijacker của writer gốc (VD: WebSocket).
+//
+// Trả về:
+//   - net.Conn: kết nối đã được chiếm quyền
+//   - *bufio.ReadWriter: buffer đọc/ghi của kết nối
+//   - error: http.ErrNotSupported nếu writer gốc không hỗ trợ Hijack
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !w.wroteHeader {
+		// Kết nối đã chuyển giao thức (VD: 101 Switching Protocols)
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap trả về http.ResponseWriter gốc để http.ResponseController có thể
+// truy cập các tính năng khác của writer gốc.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// newTestManager tạo manager cho testing. Handler được thêm qua AddHandler chỉ
+// được gắn vào các logger đã tồn tại, vì vậy capture handler được thêm sau khi tạo middleware.
+func newTestManager(t *testing.T) (log.Manager, *handler.MemoryHandler) {
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	config.File.Enabled = true
+	config.File.Path = t.TempDir() + "/app.log"
+
+	m := log.NewManager(config)
+	t.Cleanup(func() { m.Close() })
+
+	return m, handler.NewMemoryHandler()
+}
+
+func TestNew_LogsRequest(t *testing.T) {
+	m, capture := newTestManager(t)
+
+	h := New(m, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+	m.AddHandler("capture", capture)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Request-ID", "abc-123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	msg := capture.Entries()[0].Message
+	for _, want := range []string{"[HTTP] HTTP request", "method=POST", "path=/users", "status=201", "bytes=5", "remote_ip=10.0.0.1", "request_id=abc-123", "latency="} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Log entry thiếu %q, got %q", want, msg)
+		}
+	}
+	if capture.Entries()[0].Level != handler.InfoLevel {
+		t.Errorf("Status 201 nên log ở InfoLevel, got %v", capture.Entries()[0].Level)
+	}
+}
+
+func TestNew_LevelByStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   handler.Level
+	}{
+		{http.StatusOK, handler.InfoLevel},
+		{http.StatusNotFound, handler.WarningLevel},
+		{http.StatusInternalServerError, handler.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		m, capture := newTestManager(t)
+		h := New(m, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		m.AddHandler("capture", capture)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if capture.Len() != 1 || capture.Entries()[0].Level != tt.want {
+			t.Errorf("Status %d nên log ở %v, got %v", tt.status, tt.want, capture.Entries())
+		}
+	}
+}
+
+func TestNew_CustomFieldsAndSkip(t *testing.T) {
+	m, capture := newTestManager(t)
+
+	config := DefaultConfig()
+	config.Fields = []Field{FieldMethod, FieldStatus}
+	config.TrustProxyHeaders = true
+	config.Skip = func(r *http.Request) bool { return r.URL.Path == "/health" }
+
+	h := New(m, config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	m.AddHandler("capture", capture)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if capture.Len() != 0 {
+		t.Fatalf("Request bị skip không nên được ghi log, got %v", capture.Entries())
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	if capture.Len() != 1 {
+		t.Fatalf("Middleware nên ghi 1 log entry, got %d", capture.Len())
+	}
+	if strings.Contains(capture.Entries()[0].Message, "path=") {
+		t.Errorf("Field path không được cấu hình nên không được ghi, got %q", capture.Entries()[0].Message)
+	}
+}
+
+func TestConfig_RemoteIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.10:5555"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	config := DefaultConfig()
+	if ip := config.remoteIP(req); ip != "192.168.1.10" {
+		t.Errorf("remoteIP() không tin cậy proxy nên dùng RemoteAddr, got %q", ip)
+	}
+
+	config.TrustProxyHeaders = true
+	if ip := config.remoteIP(req); ip != "10.0.0.1" {
+		t.Errorf("remoteIP() không khai báo TrustedProxies nên dùng địa chỉ cuối của X-Forwarded-For, got %q", ip)
+	}
+
+	// Địa chỉ giả do client tự thêm vào đầu X-Forwarded-For không được chọn
+	config.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.7, 10.0.0.1")
+	if ip := config.remoteIP(req); ip != "203.0.113.7" {
+		t.Errorf("remoteIP() nên dùng địa chỉ phải nhất không thuộc TrustedProxies, got %q", ip)
+	}
+
+	req.RemoteAddr = "198.51.100.9:5555"
+	if ip := config.remoteIP(req); ip != "198.51.100.9" {
+		t.Errorf("remoteIP() nên bỏ qua proxy header khi kết nối không đến từ proxy tin cậy, got %q", ip)
+	}
+}
+
+// hijackRecorder là httptest.ResponseRecorder hỗ trợ http.Hijacker.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+func TestNew_ForwardsFlushAndHijack(t *testing.T) {
+	m, _ := newTestManager(t)
+	var flushOK, hijackOK bool
+	h := New(m, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flushOK = w.(http.Flusher)
+		if hijacker, ok := w.(http.Hijacker); ok {
+			_, _, err := hijacker.Hijack()
+			hijackOK = err == nil
+		}
+	}))
+
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if !flushOK || !hijackOK || !rec.hijacked {
+		t.Errorf("Writer được bọc nên hỗ trợ Flush và Hijack của writer gốc, flush=%v hijack=%v", flushOK, rec.hijacked)
+	}
+
+	// Writer gốc không hỗ trợ Hijack thì Hijack trả về http.ErrNotSupported
+	rw := &responseWriter{ResponseWriter: httptest.NewRecorder()}
+	if _, _, err := rw.Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Hijack() error = %v, want http.ErrNotSupported", err)
+	}
+	rw.Flush()
+	if !rw.ResponseWriter.(*httptest.ResponseRecorder).Flushed {
+		t.Error("Flush() nên được chuyển đến writer gốc")
+	}
+}
+
+func TestRecorder_ErrorLevel(t *testing.T) {
+	m, capture := newTestManager(t)
+	recorder := NewRecorder(m, nil)
+	m.AddHandler("capture", capture)
+
+	recorder.Record(&Request{Method: http.MethodGet, Path: "/", Status: http.StatusOK, Err: errors.New("write failed")})
+
+	if capture.Len() != 1 || capture.Entries()[0].Level != handler.ErrorLevel {
+		t.Fatalf("Request có lỗi nên log ở ErrorLevel, got %v", capture.Entries())
+	}
+	if !strings.Contains(capture.Entries()[0].Message, `error="write failed"`) {
+		t.Errorf("Log entry nên chứa field error, got %q", capture.Entries()[0].Message)
+	}
+}
+
+func TestNew_CombinedLogFormat(t *testing.T) {
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	config.File.Path = t.TempDir() + "/app.log"
+	path := t.TempDir() + "/access.log"
+	config.Channels = map[string]log.ChannelConfig{
+		log.ChannelAccess: {Contexts: []string{log.AccessContext}, Path: path, Format: "combined"},
+	}
+	m := log.NewManager(config)
+
+	mwConfig := DefaultConfig()
+	mwConfig.Fields = CombinedFields
+	h := New(m, mwConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set("User-Agent", "curl/8.5.0")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	m.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(data)
+	if !strings.HasPrefix(line, "203.0.113.7 - - [") ||
+		!strings.HasSuffix(line, `] "GET /orders HTTP/1.1" 200 5 "https://example.com/" "curl/8.5.0"`+"\n") {
+		t.Errorf("Access log nên theo Combined Log Format, got %q", line)
+	}
+}
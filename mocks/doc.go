@@ -0,0 +1,29 @@
+// Package mocks cung cấp mock tương thích mockery (testify/mock) cho log.Logger, log.Manager và
+// handler.Handler, để project sử dụng package log không phải tự viết mock.
+//
+// Các file mock được sinh bởi mockery theo .mockery.yaml ở thư mục gốc; chạy lại mockery sau
+// khi thay đổi interface. Các khai báo bên dưới làm build thất bại khi mock không còn khớp với
+// interface.
+//
+// Ví dụ:
+//
+//	func TestUserService_CreateUser(t *testing.T) {
+//	    logger := mocks.NewMockLogger(t)
+//	    logger.EXPECT().Info("Creating user %s", "alice").Once()
+//	    logger.EXPECT().Info("User created successfully").Once()
+//
+//	    service := &UserService{logger: logger}
+//	    assert.NoError(t, service.CreateUser(&User{Username: "alice"}))
+//	}
+package mocks
+
+import (
+	log "go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+var (
+	_ log.Logger      = (*MockLogger)(nil)
+	_ log.Manager     = (*MockManager)(nil)
+	_ handler.Handler = (*MockHandler)(nil)
+)
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	log "go.fork.vn/log"
 	handler "go.fork.vn/log/handler"
 
@@ -145,6 +148,143 @@ func (_c *MockLogger_Debug_Call) RunAndReturn(run func(string, ...interface{}))
 	return _c
 }
 
+// DebugContext provides a mock function with given fields: ctx, message, args
+func (_m *MockLogger) DebugContext(ctx context.Context, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_DebugContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DebugContext'
+type MockLogger_DebugContext_Call struct {
+	*mock.Call
+}
+
+// DebugContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) DebugContext(ctx interface{}, message interface{}, args ...interface{}) *MockLogger_DebugContext_Call {
+	return &MockLogger_DebugContext_Call{Call: _e.mock.On("DebugContext",
+		append([]interface{}{ctx, message}, args...)...)}
+}
+
+func (_c *MockLogger_DebugContext_Call) Run(run func(ctx context.Context, message string, args ...interface{})) *MockLogger_DebugContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_DebugContext_Call) Return() *MockLogger_DebugContext_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_DebugContext_Call) RunAndReturn(run func(context.Context, string, ...interface{})) *MockLogger_DebugContext_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Debugf provides a mock function with given fields: format, args
+func (_m *MockLogger) Debugf(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_Debugf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Debugf'
+type MockLogger_Debugf_Call struct {
+	*mock.Call
+}
+
+// Debugf is a helper method to define mock.On call
+//   - format string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) Debugf(format interface{}, args ...interface{}) *MockLogger_Debugf_Call {
+	return &MockLogger_Debugf_Call{Call: _e.mock.On("Debugf",
+		append([]interface{}{format}, args...)...)}
+}
+
+func (_c *MockLogger_Debugf_Call) Run(run func(format string, args ...interface{})) *MockLogger_Debugf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Debugf_Call) Return() *MockLogger_Debugf_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Debugf_Call) RunAndReturn(run func(string, ...interface{})) *MockLogger_Debugf_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Dedup provides a mock function with given fields: window
+func (_m *MockLogger) Dedup(window time.Duration) log.Logger {
+	ret := _m.Called(window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Dedup")
+	}
+
+	var r0 log.Logger
+	if rf, ok := ret.Get(0).(func(time.Duration) log.Logger); ok {
+		r0 = rf(window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(log.Logger)
+		}
+	}
+
+	return r0
+}
+
+// MockLogger_Dedup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Dedup'
+type MockLogger_Dedup_Call struct {
+	*mock.Call
+}
+
+// Dedup is a helper method to define mock.On call
+//   - window time.Duration
+func (_e *MockLogger_Expecter) Dedup(window interface{}) *MockLogger_Dedup_Call {
+	return &MockLogger_Dedup_Call{Call: _e.mock.On("Dedup", window)}
+}
+
+func (_c *MockLogger_Dedup_Call) Run(run func(window time.Duration)) *MockLogger_Dedup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockLogger_Dedup_Call) Return(_a0 log.Logger) *MockLogger_Dedup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLogger_Dedup_Call) RunAndReturn(run func(time.Duration) log.Logger) *MockLogger_Dedup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Error provides a mock function with given fields: message, args
 func (_m *MockLogger) Error(message string, args ...interface{}) {
 	var _ca []interface{}
@@ -189,6 +329,143 @@ func (_c *MockLogger_Error_Call) RunAndReturn(run func(string, ...interface{}))
 	return _c
 }
 
+// ErrorContext provides a mock function with given fields: ctx, message, args
+func (_m *MockLogger) ErrorContext(ctx context.Context, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_ErrorContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ErrorContext'
+type MockLogger_ErrorContext_Call struct {
+	*mock.Call
+}
+
+// ErrorContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) ErrorContext(ctx interface{}, message interface{}, args ...interface{}) *MockLogger_ErrorContext_Call {
+	return &MockLogger_ErrorContext_Call{Call: _e.mock.On("ErrorContext",
+		append([]interface{}{ctx, message}, args...)...)}
+}
+
+func (_c *MockLogger_ErrorContext_Call) Run(run func(ctx context.Context, message string, args ...interface{})) *MockLogger_ErrorContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_ErrorContext_Call) Return() *MockLogger_ErrorContext_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_ErrorContext_Call) RunAndReturn(run func(context.Context, string, ...interface{})) *MockLogger_ErrorContext_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Errorf provides a mock function with given fields: format, args
+func (_m *MockLogger) Errorf(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_Errorf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Errorf'
+type MockLogger_Errorf_Call struct {
+	*mock.Call
+}
+
+// Errorf is a helper method to define mock.On call
+//   - format string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) Errorf(format interface{}, args ...interface{}) *MockLogger_Errorf_Call {
+	return &MockLogger_Errorf_Call{Call: _e.mock.On("Errorf",
+		append([]interface{}{format}, args...)...)}
+}
+
+func (_c *MockLogger_Errorf_Call) Run(run func(format string, args ...interface{})) *MockLogger_Errorf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Errorf_Call) Return() *MockLogger_Errorf_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Errorf_Call) RunAndReturn(run func(string, ...interface{})) *MockLogger_Errorf_Call {
+	_c.Run(run)
+	return _c
+}
+
+// EveryN provides a mock function with given fields: n
+func (_m *MockLogger) EveryN(n int) log.Logger {
+	ret := _m.Called(n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EveryN")
+	}
+
+	var r0 log.Logger
+	if rf, ok := ret.Get(0).(func(int) log.Logger); ok {
+		r0 = rf(n)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(log.Logger)
+		}
+	}
+
+	return r0
+}
+
+// MockLogger_EveryN_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EveryN'
+type MockLogger_EveryN_Call struct {
+	*mock.Call
+}
+
+// EveryN is a helper method to define mock.On call
+//   - n int
+func (_e *MockLogger_Expecter) EveryN(n interface{}) *MockLogger_EveryN_Call {
+	return &MockLogger_EveryN_Call{Call: _e.mock.On("EveryN", n)}
+}
+
+func (_c *MockLogger_EveryN_Call) Run(run func(n int)) *MockLogger_EveryN_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockLogger_EveryN_Call) Return(_a0 log.Logger) *MockLogger_EveryN_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLogger_EveryN_Call) RunAndReturn(run func(int) log.Logger) *MockLogger_EveryN_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Fatal provides a mock function with given fields: message, args
 func (_m *MockLogger) Fatal(message string, args ...interface{}) {
 	var _ca []interface{}
@@ -233,6 +510,95 @@ func (_c *MockLogger_Fatal_Call) RunAndReturn(run func(string, ...interface{}))
 	return _c
 }
 
+// FatalContext provides a mock function with given fields: ctx, message, args
+func (_m *MockLogger) FatalContext(ctx context.Context, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_FatalContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FatalContext'
+type MockLogger_FatalContext_Call struct {
+	*mock.Call
+}
+
+// FatalContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) FatalContext(ctx interface{}, message interface{}, args ...interface{}) *MockLogger_FatalContext_Call {
+	return &MockLogger_FatalContext_Call{Call: _e.mock.On("FatalContext",
+		append([]interface{}{ctx, message}, args...)...)}
+}
+
+func (_c *MockLogger_FatalContext_Call) Run(run func(ctx context.Context, message string, args ...interface{})) *MockLogger_FatalContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_FatalContext_Call) Return() *MockLogger_FatalContext_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_FatalContext_Call) RunAndReturn(run func(context.Context, string, ...interface{})) *MockLogger_FatalContext_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Fatalf provides a mock function with given fields: format, args
+func (_m *MockLogger) Fatalf(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_Fatalf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fatalf'
+type MockLogger_Fatalf_Call struct {
+	*mock.Call
+}
+
+// Fatalf is a helper method to define mock.On call
+//   - format string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) Fatalf(format interface{}, args ...interface{}) *MockLogger_Fatalf_Call {
+	return &MockLogger_Fatalf_Call{Call: _e.mock.On("Fatalf",
+		append([]interface{}{format}, args...)...)}
+}
+
+func (_c *MockLogger_Fatalf_Call) Run(run func(format string, args ...interface{})) *MockLogger_Fatalf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Fatalf_Call) Return() *MockLogger_Fatalf_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Fatalf_Call) RunAndReturn(run func(string, ...interface{})) *MockLogger_Fatalf_Call {
+	_c.Run(run)
+	return _c
+}
+
 // GetHandler provides a mock function with given fields: handlerType
 func (_m *MockLogger) GetHandler(handlerType log.HandlerType) handler.Handler {
 	ret := _m.Called(handlerType)
@@ -325,6 +691,237 @@ func (_c *MockLogger_Info_Call) RunAndReturn(run func(string, ...interface{})) *
 	return _c
 }
 
+// InfoContext provides a mock function with given fields: ctx, message, args
+func (_m *MockLogger) InfoContext(ctx context.Context, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_InfoContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InfoContext'
+type MockLogger_InfoContext_Call struct {
+	*mock.Call
+}
+
+// InfoContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) InfoContext(ctx interface{}, message interface{}, args ...interface{}) *MockLogger_InfoContext_Call {
+	return &MockLogger_InfoContext_Call{Call: _e.mock.On("InfoContext",
+		append([]interface{}{ctx, message}, args...)...)}
+}
+
+func (_c *MockLogger_InfoContext_Call) Run(run func(ctx context.Context, message string, args ...interface{})) *MockLogger_InfoContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_InfoContext_Call) Return() *MockLogger_InfoContext_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_InfoContext_Call) RunAndReturn(run func(context.Context, string, ...interface{})) *MockLogger_InfoContext_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Infof provides a mock function with given fields: format, args
+func (_m *MockLogger) Infof(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_Infof_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Infof'
+type MockLogger_Infof_Call struct {
+	*mock.Call
+}
+
+// Infof is a helper method to define mock.On call
+//   - format string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) Infof(format interface{}, args ...interface{}) *MockLogger_Infof_Call {
+	return &MockLogger_Infof_Call{Call: _e.mock.On("Infof",
+		append([]interface{}{format}, args...)...)}
+}
+
+func (_c *MockLogger_Infof_Call) Run(run func(format string, args ...interface{})) *MockLogger_Infof_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Infof_Call) Return() *MockLogger_Infof_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Infof_Call) RunAndReturn(run func(string, ...interface{})) *MockLogger_Infof_Call {
+	_c.Run(run)
+	return _c
+}
+
+// LogAt provides a mock function with given fields: t, level, message, args
+func (_m *MockLogger) LogAt(t time.Time, level handler.Level, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, t, level, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_LogAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogAt'
+type MockLogger_LogAt_Call struct {
+	*mock.Call
+}
+
+// LogAt is a helper method to define mock.On call
+//   - t time.Time
+//   - level handler.Level
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) LogAt(t interface{}, level interface{}, message interface{}, args ...interface{}) *MockLogger_LogAt_Call {
+	return &MockLogger_LogAt_Call{Call: _e.mock.On("LogAt",
+		append([]interface{}{t, level, message}, args...)...)}
+}
+
+func (_c *MockLogger_LogAt_Call) Run(run func(t time.Time, level handler.Level, message string, args ...interface{})) *MockLogger_LogAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(time.Time), args[1].(handler.Level), args[2].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_LogAt_Call) Return() *MockLogger_LogAt_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_LogAt_Call) RunAndReturn(run func(time.Time, handler.Level, string, ...interface{})) *MockLogger_LogAt_Call {
+	_c.Run(run)
+	return _c
+}
+
+// LogFields provides a mock function with given fields: level, message, fields
+func (_m *MockLogger) LogFields(level handler.Level, message string, fields ...log.Field) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, level, message)
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_LogFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogFields'
+type MockLogger_LogFields_Call struct {
+	*mock.Call
+}
+
+// LogFields is a helper method to define mock.On call
+//   - level handler.Level
+//   - message string
+//   - fields ...log.Field
+func (_e *MockLogger_Expecter) LogFields(level interface{}, message interface{}, fields ...interface{}) *MockLogger_LogFields_Call {
+	return &MockLogger_LogFields_Call{Call: _e.mock.On("LogFields",
+		append([]interface{}{level, message}, fields...)...)}
+}
+
+func (_c *MockLogger_LogFields_Call) Run(run func(level handler.Level, message string, fields ...log.Field)) *MockLogger_LogFields_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]log.Field, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(log.Field)
+			}
+		}
+		run(args[0].(handler.Level), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_LogFields_Call) Return() *MockLogger_LogFields_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_LogFields_Call) RunAndReturn(run func(handler.Level, string, ...log.Field)) *MockLogger_LogFields_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Once provides a mock function with no fields
+func (_m *MockLogger) Once() log.Logger {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Once")
+	}
+
+	var r0 log.Logger
+	if rf, ok := ret.Get(0).(func() log.Logger); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(log.Logger)
+		}
+	}
+
+	return r0
+}
+
+// MockLogger_Once_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Once'
+type MockLogger_Once_Call struct {
+	*mock.Call
+}
+
+// Once is a helper method to define mock.On call
+func (_e *MockLogger_Expecter) Once() *MockLogger_Once_Call {
+	return &MockLogger_Once_Call{Call: _e.mock.On("Once")}
+}
+
+func (_c *MockLogger_Once_Call) Run(run func()) *MockLogger_Once_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockLogger_Once_Call) Return(_a0 log.Logger) *MockLogger_Once_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLogger_Once_Call) RunAndReturn(run func() log.Logger) *MockLogger_Once_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveHandler provides a mock function with given fields: handlerType
 func (_m *MockLogger) RemoveHandler(handlerType log.HandlerType) {
 	_m.Called(handlerType)
@@ -435,6 +1032,95 @@ func (_c *MockLogger_Warning_Call) RunAndReturn(run func(string, ...interface{})
 	return _c
 }
 
+// WarningContext provides a mock function with given fields: ctx, message, args
+func (_m *MockLogger) WarningContext(ctx context.Context, message string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, message)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_WarningContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WarningContext'
+type MockLogger_WarningContext_Call struct {
+	*mock.Call
+}
+
+// WarningContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) WarningContext(ctx interface{}, message interface{}, args ...interface{}) *MockLogger_WarningContext_Call {
+	return &MockLogger_WarningContext_Call{Call: _e.mock.On("WarningContext",
+		append([]interface{}{ctx, message}, args...)...)}
+}
+
+func (_c *MockLogger_WarningContext_Call) Run(run func(ctx context.Context, message string, args ...interface{})) *MockLogger_WarningContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_WarningContext_Call) Return() *MockLogger_WarningContext_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_WarningContext_Call) RunAndReturn(run func(context.Context, string, ...interface{})) *MockLogger_WarningContext_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Warningf provides a mock function with given fields: format, args
+func (_m *MockLogger) Warningf(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// MockLogger_Warningf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Warningf'
+type MockLogger_Warningf_Call struct {
+	*mock.Call
+}
+
+// Warningf is a helper method to define mock.On call
+//   - format string
+//   - args ...interface{}
+func (_e *MockLogger_Expecter) Warningf(format interface{}, args ...interface{}) *MockLogger_Warningf_Call {
+	return &MockLogger_Warningf_Call{Call: _e.mock.On("Warningf",
+		append([]interface{}{format}, args...)...)}
+}
+
+func (_c *MockLogger_Warningf_Call) Run(run func(format string, args ...interface{})) *MockLogger_Warningf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLogger_Warningf_Call) Return() *MockLogger_Warningf_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockLogger_Warningf_Call) RunAndReturn(run func(string, ...interface{})) *MockLogger_Warningf_Call {
+	_c.Run(run)
+	return _c
+}
+
 // NewMockLogger creates a new instance of MockLogger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockLogger(t interface {
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	log "go.fork.vn/log"
 	handler "go.fork.vn/log/handler"
 
@@ -22,9 +25,83 @@ func (_m *MockManager) EXPECT() *MockManager_Expecter {
 	return &MockManager_Expecter{mock: &_m.Mock}
 }
 
-// AddHandler provides a mock function with given fields: handlerType, _a1
-func (_m *MockManager) AddHandler(handlerType log.HandlerType, _a1 handler.Handler) {
-	_m.Called(handlerType, _a1)
+// AddContextExtractor provides a mock function with given fields: extract
+func (_m *MockManager) AddContextExtractor(extract func(ctx context.Context) []log.Field) {
+	_m.Called(extract)
+}
+
+// MockManager_AddContextExtractor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddContextExtractor'
+type MockManager_AddContextExtractor_Call struct {
+	*mock.Call
+}
+
+// AddContextExtractor is a helper method to define mock.On call
+//   - extract func(ctx context.Context) []log.Field
+func (_e *MockManager_Expecter) AddContextExtractor(extract interface{}) *MockManager_AddContextExtractor_Call {
+	return &MockManager_AddContextExtractor_Call{Call: _e.mock.On("AddContextExtractor", extract)}
+}
+
+func (_c *MockManager_AddContextExtractor_Call) Run(run func(extract func(ctx context.Context) []log.Field)) *MockManager_AddContextExtractor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(func(ctx context.Context) []log.Field))
+	})
+	return _c
+}
+
+func (_c *MockManager_AddContextExtractor_Call) Return() *MockManager_AddContextExtractor_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_AddContextExtractor_Call) RunAndReturn(run func(func(ctx context.Context) []log.Field)) *MockManager_AddContextExtractor_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AddContextField provides a mock function with given fields: key, field
+func (_m *MockManager) AddContextField(key interface{}, field string) {
+	_m.Called(key, field)
+}
+
+// MockManager_AddContextField_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddContextField'
+type MockManager_AddContextField_Call struct {
+	*mock.Call
+}
+
+// AddContextField is a helper method to define mock.On call
+//   - key interface{}
+//   - field string
+func (_e *MockManager_Expecter) AddContextField(key interface{}, field interface{}) *MockManager_AddContextField_Call {
+	return &MockManager_AddContextField_Call{Call: _e.mock.On("AddContextField", key, field)}
+}
+
+func (_c *MockManager_AddContextField_Call) Run(run func(key interface{}, field string)) *MockManager_AddContextField_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(interface{}), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockManager_AddContextField_Call) Return() *MockManager_AddContextField_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_AddContextField_Call) RunAndReturn(run func(interface{}, string)) *MockManager_AddContextField_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AddHandler provides a mock function with given fields: handlerType, _a1, opts
+func (_m *MockManager) AddHandler(handlerType log.HandlerType, _a1 handler.Handler, opts ...log.HandlerOption) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, handlerType, _a1)
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
 }
 
 // MockManager_AddHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddHandler'
@@ -35,13 +112,21 @@ type MockManager_AddHandler_Call struct {
 // AddHandler is a helper method to define mock.On call
 //   - handlerType log.HandlerType
 //   - _a1 handler.Handler
-func (_e *MockManager_Expecter) AddHandler(handlerType interface{}, _a1 interface{}) *MockManager_AddHandler_Call {
-	return &MockManager_AddHandler_Call{Call: _e.mock.On("AddHandler", handlerType, _a1)}
+//   - opts ...log.HandlerOption
+func (_e *MockManager_Expecter) AddHandler(handlerType interface{}, _a1 interface{}, opts ...interface{}) *MockManager_AddHandler_Call {
+	return &MockManager_AddHandler_Call{Call: _e.mock.On("AddHandler",
+		append([]interface{}{handlerType, _a1}, opts...)...)}
 }
 
-func (_c *MockManager_AddHandler_Call) Run(run func(handlerType log.HandlerType, _a1 handler.Handler)) *MockManager_AddHandler_Call {
+func (_c *MockManager_AddHandler_Call) Run(run func(handlerType log.HandlerType, _a1 handler.Handler, opts ...log.HandlerOption)) *MockManager_AddHandler_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(log.HandlerType), args[1].(handler.Handler))
+		variadicArgs := make([]log.HandlerOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(log.HandlerOption)
+			}
+		}
+		run(args[0].(log.HandlerType), args[1].(handler.Handler), variadicArgs...)
 	})
 	return _c
 }
@@ -51,22 +136,62 @@ func (_c *MockManager_AddHandler_Call) Return() *MockManager_AddHandler_Call {
 	return _c
 }
 
-func (_c *MockManager_AddHandler_Call) RunAndReturn(run func(log.HandlerType, handler.Handler)) *MockManager_AddHandler_Call {
+func (_c *MockManager_AddHandler_Call) RunAndReturn(run func(log.HandlerType, handler.Handler, ...log.HandlerOption)) *MockManager_AddHandler_Call {
 	_c.Run(run)
 	return _c
 }
 
-// Close provides a mock function with no fields
-func (_m *MockManager) Close() error {
-	ret := _m.Called()
+// AddHook provides a mock function with given fields: hook
+func (_m *MockManager) AddHook(hook log.Hook) {
+	_m.Called(hook)
+}
+
+// MockManager_AddHook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddHook'
+type MockManager_AddHook_Call struct {
+	*mock.Call
+}
+
+// AddHook is a helper method to define mock.On call
+//   - hook log.Hook
+func (_e *MockManager_Expecter) AddHook(hook interface{}) *MockManager_AddHook_Call {
+	return &MockManager_AddHook_Call{Call: _e.mock.On("AddHook", hook)}
+}
+
+func (_c *MockManager_AddHook_Call) Run(run func(hook log.Hook)) *MockManager_AddHook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.Hook))
+	})
+	return _c
+}
+
+func (_c *MockManager_AddHook_Call) Return() *MockManager_AddHook_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_AddHook_Call) RunAndReturn(run func(log.Hook)) *MockManager_AddHook_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AddNamedHandler provides a mock function with given fields: name, h, opts
+func (_m *MockManager) AddNamedHandler(name string, h handler.Handler, opts ...log.HandlerOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Close")
+		panic("no return value specified for AddNamedHandler")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string, handler.Handler, ...log.HandlerOption) error); ok {
+		r0 = rf(name, h, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -74,92 +199,160 @@ func (_m *MockManager) Close() error {
 	return r0
 }
 
-// MockManager_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
-type MockManager_Close_Call struct {
+// MockManager_AddNamedHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNamedHandler'
+type MockManager_AddNamedHandler_Call struct {
 	*mock.Call
 }
 
-// Close is a helper method to define mock.On call
-func (_e *MockManager_Expecter) Close() *MockManager_Close_Call {
-	return &MockManager_Close_Call{Call: _e.mock.On("Close")}
+// AddNamedHandler is a helper method to define mock.On call
+//   - name string
+//   - h handler.Handler
+//   - opts ...log.HandlerOption
+func (_e *MockManager_Expecter) AddNamedHandler(name interface{}, h interface{}, opts ...interface{}) *MockManager_AddNamedHandler_Call {
+	return &MockManager_AddNamedHandler_Call{Call: _e.mock.On("AddNamedHandler",
+		append([]interface{}{name, h}, opts...)...)}
 }
 
-func (_c *MockManager_Close_Call) Run(run func()) *MockManager_Close_Call {
+func (_c *MockManager_AddNamedHandler_Call) Run(run func(name string, h handler.Handler, opts ...log.HandlerOption)) *MockManager_AddNamedHandler_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		variadicArgs := make([]log.HandlerOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(log.HandlerOption)
+			}
+		}
+		run(args[0].(string), args[1].(handler.Handler), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockManager_Close_Call) Return(_a0 error) *MockManager_Close_Call {
+func (_c *MockManager_AddNamedHandler_Call) Return(_a0 error) *MockManager_AddNamedHandler_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockManager_Close_Call) RunAndReturn(run func() error) *MockManager_Close_Call {
+func (_c *MockManager_AddNamedHandler_Call) RunAndReturn(run func(string, handler.Handler, ...log.HandlerOption) error) *MockManager_AddNamedHandler_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetHandler provides a mock function with given fields: handlerType
-func (_m *MockManager) GetHandler(handlerType log.HandlerType) handler.Handler {
-	ret := _m.Called(handlerType)
+// AddService provides a mock function with given fields: name, service
+func (_m *MockManager) AddService(name string, service log.Service) error {
+	ret := _m.Called(name, service)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetHandler")
+		panic("no return value specified for AddService")
 	}
 
-	var r0 handler.Handler
-	if rf, ok := ret.Get(0).(func(log.HandlerType) handler.Handler); ok {
-		r0 = rf(handlerType)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, log.Service) error); ok {
+		r0 = rf(name, service)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(handler.Handler)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// MockManager_GetHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHandler'
-type MockManager_GetHandler_Call struct {
+// MockManager_AddService_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddService'
+type MockManager_AddService_Call struct {
 	*mock.Call
 }
 
-// GetHandler is a helper method to define mock.On call
-//   - handlerType log.HandlerType
-func (_e *MockManager_Expecter) GetHandler(handlerType interface{}) *MockManager_GetHandler_Call {
-	return &MockManager_GetHandler_Call{Call: _e.mock.On("GetHandler", handlerType)}
+// AddService is a helper method to define mock.On call
+//   - name string
+//   - service log.Service
+func (_e *MockManager_Expecter) AddService(name interface{}, service interface{}) *MockManager_AddService_Call {
+	return &MockManager_AddService_Call{Call: _e.mock.On("AddService", name, service)}
 }
 
-func (_c *MockManager_GetHandler_Call) Run(run func(handlerType log.HandlerType)) *MockManager_GetHandler_Call {
+func (_c *MockManager_AddService_Call) Run(run func(name string, service log.Service)) *MockManager_AddService_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(log.HandlerType))
+		run(args[0].(string), args[1].(log.Service))
 	})
 	return _c
 }
 
-func (_c *MockManager_GetHandler_Call) Return(_a0 handler.Handler) *MockManager_GetHandler_Call {
+func (_c *MockManager_AddService_Call) Return(_a0 error) *MockManager_AddService_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockManager_GetHandler_Call) RunAndReturn(run func(log.HandlerType) handler.Handler) *MockManager_GetHandler_Call {
+func (_c *MockManager_AddService_Call) RunAndReturn(run func(string, log.Service) error) *MockManager_AddService_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetLogger provides a mock function with given fields: context
-func (_m *MockManager) GetLogger(context string) log.Logger {
-	ret := _m.Called(context)
+// ApplyConfig provides a mock function with given fields: config, dryRun
+func (_m *MockManager) ApplyConfig(config *log.Config, dryRun bool) (*log.ConfigDiff, error) {
+	ret := _m.Called(config, dryRun)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLogger")
+		panic("no return value specified for ApplyConfig")
+	}
+
+	var r0 *log.ConfigDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*log.Config, bool) (*log.ConfigDiff, error)); ok {
+		return rf(config, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(*log.Config, bool) *log.ConfigDiff); ok {
+		r0 = rf(config, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*log.ConfigDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*log.Config, bool) error); ok {
+		r1 = rf(config, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockManager_ApplyConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyConfig'
+type MockManager_ApplyConfig_Call struct {
+	*mock.Call
+}
+
+// ApplyConfig is a helper method to define mock.On call
+//   - config *log.Config
+//   - dryRun bool
+func (_e *MockManager_Expecter) ApplyConfig(config interface{}, dryRun interface{}) *MockManager_ApplyConfig_Call {
+	return &MockManager_ApplyConfig_Call{Call: _e.mock.On("ApplyConfig", config, dryRun)}
+}
+
+func (_c *MockManager_ApplyConfig_Call) Run(run func(config *log.Config, dryRun bool)) *MockManager_ApplyConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*log.Config), args[1].(bool))
+	})
+	return _c
+}
+
+func (_c *MockManager_ApplyConfig_Call) Return(_a0 *log.ConfigDiff, _a1 error) *MockManager_ApplyConfig_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockManager_ApplyConfig_Call) RunAndReturn(run func(*log.Config, bool) (*log.ConfigDiff, error)) *MockManager_ApplyConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Channel provides a mock function with given fields: name
+func (_m *MockManager) Channel(name string) log.Logger {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Channel")
 	}
 
 	var r0 log.Logger
 	if rf, ok := ret.Get(0).(func(string) log.Logger); ok {
-		r0 = rf(context)
+		r0 = rf(name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(log.Logger)
@@ -169,97 +362,1116 @@ func (_m *MockManager) GetLogger(context string) log.Logger {
 	return r0
 }
 
-// MockManager_GetLogger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLogger'
-type MockManager_GetLogger_Call struct {
+// MockManager_Channel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Channel'
+type MockManager_Channel_Call struct {
 	*mock.Call
 }
 
-// GetLogger is a helper method to define mock.On call
-//   - context string
-func (_e *MockManager_Expecter) GetLogger(context interface{}) *MockManager_GetLogger_Call {
-	return &MockManager_GetLogger_Call{Call: _e.mock.On("GetLogger", context)}
+// Channel is a helper method to define mock.On call
+//   - name string
+func (_e *MockManager_Expecter) Channel(name interface{}) *MockManager_Channel_Call {
+	return &MockManager_Channel_Call{Call: _e.mock.On("Channel", name)}
 }
 
-func (_c *MockManager_GetLogger_Call) Run(run func(context string)) *MockManager_GetLogger_Call {
+func (_c *MockManager_Channel_Call) Run(run func(name string)) *MockManager_Channel_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockManager_GetLogger_Call) Return(_a0 log.Logger) *MockManager_GetLogger_Call {
+func (_c *MockManager_Channel_Call) Return(_a0 log.Logger) *MockManager_Channel_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockManager_GetLogger_Call) RunAndReturn(run func(string) log.Logger) *MockManager_GetLogger_Call {
+func (_c *MockManager_Channel_Call) RunAndReturn(run func(string) log.Logger) *MockManager_Channel_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RemoveHandler provides a mock function with given fields: handlerType
-func (_m *MockManager) RemoveHandler(handlerType log.HandlerType) {
-	_m.Called(handlerType)
+// Close provides a mock function with no fields
+func (_m *MockManager) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
-// MockManager_RemoveHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveHandler'
-type MockManager_RemoveHandler_Call struct {
+// MockManager_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockManager_Close_Call struct {
 	*mock.Call
 }
 
-// RemoveHandler is a helper method to define mock.On call
-//   - handlerType log.HandlerType
-func (_e *MockManager_Expecter) RemoveHandler(handlerType interface{}) *MockManager_RemoveHandler_Call {
-	return &MockManager_RemoveHandler_Call{Call: _e.mock.On("RemoveHandler", handlerType)}
+// Close is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Close() *MockManager_Close_Call {
+	return &MockManager_Close_Call{Call: _e.mock.On("Close")}
 }
 
-func (_c *MockManager_RemoveHandler_Call) Run(run func(handlerType log.HandlerType)) *MockManager_RemoveHandler_Call {
+func (_c *MockManager_Close_Call) Run(run func()) *MockManager_Close_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(log.HandlerType))
+		run()
 	})
 	return _c
 }
 
-func (_c *MockManager_RemoveHandler_Call) Return() *MockManager_RemoveHandler_Call {
-	_c.Call.Return()
+func (_c *MockManager_Close_Call) Return(_a0 error) *MockManager_Close_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockManager_RemoveHandler_Call) RunAndReturn(run func(log.HandlerType)) *MockManager_RemoveHandler_Call {
-	_c.Run(run)
+func (_c *MockManager_Close_Call) RunAndReturn(run func() error) *MockManager_Close_Call {
+	_c.Call.Return(run)
 	return _c
 }
 
-// SetHandler provides a mock function with given fields: loggerContext, handlerType
-func (_m *MockManager) SetHandler(loggerContext string, handlerType log.HandlerType) {
-	_m.Called(loggerContext, handlerType)
+// ElevateLevel provides a mock function with given fields: _a0, level, duration
+func (_m *MockManager) ElevateLevel(_a0 string, level handler.Level, duration time.Duration) error {
+	ret := _m.Called(_a0, level, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ElevateLevel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, handler.Level, time.Duration) error); ok {
+		r0 = rf(_a0, level, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
-// MockManager_SetHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetHandler'
-type MockManager_SetHandler_Call struct {
+// MockManager_ElevateLevel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ElevateLevel'
+type MockManager_ElevateLevel_Call struct {
 	*mock.Call
 }
 
-// SetHandler is a helper method to define mock.On call
-//   - loggerContext string
-//   - handlerType log.HandlerType
-func (_e *MockManager_Expecter) SetHandler(loggerContext interface{}, handlerType interface{}) *MockManager_SetHandler_Call {
-	return &MockManager_SetHandler_Call{Call: _e.mock.On("SetHandler", loggerContext, handlerType)}
+// ElevateLevel is a helper method to define mock.On call
+//   - _a0 string
+//   - level handler.Level
+//   - duration time.Duration
+func (_e *MockManager_Expecter) ElevateLevel(_a0 interface{}, level interface{}, duration interface{}) *MockManager_ElevateLevel_Call {
+	return &MockManager_ElevateLevel_Call{Call: _e.mock.On("ElevateLevel", _a0, level, duration)}
 }
 
-func (_c *MockManager_SetHandler_Call) Run(run func(loggerContext string, handlerType log.HandlerType)) *MockManager_SetHandler_Call {
+func (_c *MockManager_ElevateLevel_Call) Run(run func(_a0 string, level handler.Level, duration time.Duration)) *MockManager_ElevateLevel_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(log.HandlerType))
+		run(args[0].(string), args[1].(handler.Level), args[2].(time.Duration))
 	})
 	return _c
 }
 
-func (_c *MockManager_SetHandler_Call) Return() *MockManager_SetHandler_Call {
-	_c.Call.Return()
+func (_c *MockManager_ElevateLevel_Call) Return(_a0 error) *MockManager_ElevateLevel_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockManager_SetHandler_Call) RunAndReturn(run func(string, log.HandlerType)) *MockManager_SetHandler_Call {
+func (_c *MockManager_ElevateLevel_Call) RunAndReturn(run func(string, handler.Level, time.Duration) error) *MockManager_ElevateLevel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ErrorCount provides a mock function with no fields
+func (_m *MockManager) ErrorCount() uint64 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ErrorCount")
+	}
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// MockManager_ErrorCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ErrorCount'
+type MockManager_ErrorCount_Call struct {
+	*mock.Call
+}
+
+// ErrorCount is a helper method to define mock.On call
+func (_e *MockManager_Expecter) ErrorCount() *MockManager_ErrorCount_Call {
+	return &MockManager_ErrorCount_Call{Call: _e.mock.On("ErrorCount")}
+}
+
+func (_c *MockManager_ErrorCount_Call) Run(run func()) *MockManager_ErrorCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_ErrorCount_Call) Return(_a0 uint64) *MockManager_ErrorCount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_ErrorCount_Call) RunAndReturn(run func() uint64) *MockManager_ErrorCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FlushAll provides a mock function with no fields
+func (_m *MockManager) FlushAll() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FlushAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_FlushAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FlushAll'
+type MockManager_FlushAll_Call struct {
+	*mock.Call
+}
+
+// FlushAll is a helper method to define mock.On call
+func (_e *MockManager_Expecter) FlushAll() *MockManager_FlushAll_Call {
+	return &MockManager_FlushAll_Call{Call: _e.mock.On("FlushAll")}
+}
+
+func (_c *MockManager_FlushAll_Call) Run(run func()) *MockManager_FlushAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_FlushAll_Call) Return(_a0 error) *MockManager_FlushAll_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_FlushAll_Call) RunAndReturn(run func() error) *MockManager_FlushAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHandler provides a mock function with given fields: handlerType
+func (_m *MockManager) GetHandler(handlerType log.HandlerType) handler.Handler {
+	ret := _m.Called(handlerType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHandler")
+	}
+
+	var r0 handler.Handler
+	if rf, ok := ret.Get(0).(func(log.HandlerType) handler.Handler); ok {
+		r0 = rf(handlerType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(handler.Handler)
+		}
+	}
+
+	return r0
+}
+
+// MockManager_GetHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHandler'
+type MockManager_GetHandler_Call struct {
+	*mock.Call
+}
+
+// GetHandler is a helper method to define mock.On call
+//   - handlerType log.HandlerType
+func (_e *MockManager_Expecter) GetHandler(handlerType interface{}) *MockManager_GetHandler_Call {
+	return &MockManager_GetHandler_Call{Call: _e.mock.On("GetHandler", handlerType)}
+}
+
+func (_c *MockManager_GetHandler_Call) Run(run func(handlerType log.HandlerType)) *MockManager_GetHandler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.HandlerType))
+	})
+	return _c
+}
+
+func (_c *MockManager_GetHandler_Call) Return(_a0 handler.Handler) *MockManager_GetHandler_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_GetHandler_Call) RunAndReturn(run func(log.HandlerType) handler.Handler) *MockManager_GetHandler_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLogger provides a mock function with given fields: _a0
+func (_m *MockManager) GetLogger(_a0 string) log.Logger {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogger")
+	}
+
+	var r0 log.Logger
+	if rf, ok := ret.Get(0).(func(string) log.Logger); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(log.Logger)
+		}
+	}
+
+	return r0
+}
+
+// MockManager_GetLogger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLogger'
+type MockManager_GetLogger_Call struct {
+	*mock.Call
+}
+
+// GetLogger is a helper method to define mock.On call
+//   - _a0 string
+func (_e *MockManager_Expecter) GetLogger(_a0 interface{}) *MockManager_GetLogger_Call {
+	return &MockManager_GetLogger_Call{Call: _e.mock.On("GetLogger", _a0)}
+}
+
+func (_c *MockManager_GetLogger_Call) Run(run func(_a0 string)) *MockManager_GetLogger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockManager_GetLogger_Call) Return(_a0 log.Logger) *MockManager_GetLogger_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_GetLogger_Call) RunAndReturn(run func(string) log.Logger) *MockManager_GetLogger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Handlers provides a mock function with no fields
+func (_m *MockManager) Handlers() []log.HandlerType {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handlers")
+	}
+
+	var r0 []log.HandlerType
+	if rf, ok := ret.Get(0).(func() []log.HandlerType); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]log.HandlerType)
+		}
+	}
+
+	return r0
+}
+
+// MockManager_Handlers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handlers'
+type MockManager_Handlers_Call struct {
+	*mock.Call
+}
+
+// Handlers is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Handlers() *MockManager_Handlers_Call {
+	return &MockManager_Handlers_Call{Call: _e.mock.On("Handlers")}
+}
+
+func (_c *MockManager_Handlers_Call) Run(run func()) *MockManager_Handlers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Handlers_Call) Return(_a0 []log.HandlerType) *MockManager_Handlers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Handlers_Call) RunAndReturn(run func() []log.HandlerType) *MockManager_Handlers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Level provides a mock function with no fields
+func (_m *MockManager) Level() handler.Level {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Level")
+	}
+
+	var r0 handler.Level
+	if rf, ok := ret.Get(0).(func() handler.Level); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(handler.Level)
+	}
+
+	return r0
+}
+
+// MockManager_Level_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Level'
+type MockManager_Level_Call struct {
+	*mock.Call
+}
+
+// Level is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Level() *MockManager_Level_Call {
+	return &MockManager_Level_Call{Call: _e.mock.On("Level")}
+}
+
+func (_c *MockManager_Level_Call) Run(run func()) *MockManager_Level_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Level_Call) Return(_a0 handler.Level) *MockManager_Level_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Level_Call) RunAndReturn(run func() handler.Level) *MockManager_Level_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Loggers provides a mock function with no fields
+func (_m *MockManager) Loggers() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Loggers")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// MockManager_Loggers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Loggers'
+type MockManager_Loggers_Call struct {
+	*mock.Call
+}
+
+// Loggers is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Loggers() *MockManager_Loggers_Call {
+	return &MockManager_Loggers_Call{Call: _e.mock.On("Loggers")}
+}
+
+func (_c *MockManager_Loggers_Call) Run(run func()) *MockManager_Loggers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Loggers_Call) Return(_a0 []string) *MockManager_Loggers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Loggers_Call) RunAndReturn(run func() []string) *MockManager_Loggers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Readiness provides a mock function with no fields
+func (_m *MockManager) Readiness() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Readiness")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_Readiness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Readiness'
+type MockManager_Readiness_Call struct {
+	*mock.Call
+}
+
+// Readiness is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Readiness() *MockManager_Readiness_Call {
+	return &MockManager_Readiness_Call{Call: _e.mock.On("Readiness")}
+}
+
+func (_c *MockManager_Readiness_Call) Run(run func()) *MockManager_Readiness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Readiness_Call) Return(_a0 error) *MockManager_Readiness_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Readiness_Call) RunAndReturn(run func() error) *MockManager_Readiness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveHandler provides a mock function with given fields: handlerType
+func (_m *MockManager) RemoveHandler(handlerType log.HandlerType) {
+	_m.Called(handlerType)
+}
+
+// MockManager_RemoveHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveHandler'
+type MockManager_RemoveHandler_Call struct {
+	*mock.Call
+}
+
+// RemoveHandler is a helper method to define mock.On call
+//   - handlerType log.HandlerType
+func (_e *MockManager_Expecter) RemoveHandler(handlerType interface{}) *MockManager_RemoveHandler_Call {
+	return &MockManager_RemoveHandler_Call{Call: _e.mock.On("RemoveHandler", handlerType)}
+}
+
+func (_c *MockManager_RemoveHandler_Call) Run(run func(handlerType log.HandlerType)) *MockManager_RemoveHandler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.HandlerType))
+	})
+	return _c
+}
+
+func (_c *MockManager_RemoveHandler_Call) Return() *MockManager_RemoveHandler_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_RemoveHandler_Call) RunAndReturn(run func(log.HandlerType)) *MockManager_RemoveHandler_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RemoveLogger provides a mock function with given fields: _a0
+func (_m *MockManager) RemoveLogger(_a0 string) bool {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLogger")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockManager_RemoveLogger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveLogger'
+type MockManager_RemoveLogger_Call struct {
+	*mock.Call
+}
+
+// RemoveLogger is a helper method to define mock.On call
+//   - _a0 string
+func (_e *MockManager_Expecter) RemoveLogger(_a0 interface{}) *MockManager_RemoveLogger_Call {
+	return &MockManager_RemoveLogger_Call{Call: _e.mock.On("RemoveLogger", _a0)}
+}
+
+func (_c *MockManager_RemoveLogger_Call) Run(run func(_a0 string)) *MockManager_RemoveLogger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockManager_RemoveLogger_Call) Return(_a0 bool) *MockManager_RemoveLogger_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_RemoveLogger_Call) RunAndReturn(run func(string) bool) *MockManager_RemoveLogger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reset provides a mock function with no fields
+func (_m *MockManager) Reset() {
+	_m.Called()
+}
+
+// MockManager_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type MockManager_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Reset() *MockManager_Reset_Call {
+	return &MockManager_Reset_Call{Call: _e.mock.On("Reset")}
+}
+
+func (_c *MockManager_Reset_Call) Run(run func()) *MockManager_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Reset_Call) Return() *MockManager_Reset_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_Reset_Call) RunAndReturn(run func()) *MockManager_Reset_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RotateAll provides a mock function with no fields
+func (_m *MockManager) RotateAll() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_RotateAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateAll'
+type MockManager_RotateAll_Call struct {
+	*mock.Call
+}
+
+// RotateAll is a helper method to define mock.On call
+func (_e *MockManager_Expecter) RotateAll() *MockManager_RotateAll_Call {
+	return &MockManager_RotateAll_Call{Call: _e.mock.On("RotateAll")}
+}
+
+func (_c *MockManager_RotateAll_Call) Run(run func()) *MockManager_RotateAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_RotateAll_Call) Return(_a0 error) *MockManager_RotateAll_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_RotateAll_Call) RunAndReturn(run func() error) *MockManager_RotateAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetClock provides a mock function with given fields: clock
+func (_m *MockManager) SetClock(clock log.Clock) {
+	_m.Called(clock)
+}
+
+// MockManager_SetClock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetClock'
+type MockManager_SetClock_Call struct {
+	*mock.Call
+}
+
+// SetClock is a helper method to define mock.On call
+//   - clock log.Clock
+func (_e *MockManager_Expecter) SetClock(clock interface{}) *MockManager_SetClock_Call {
+	return &MockManager_SetClock_Call{Call: _e.mock.On("SetClock", clock)}
+}
+
+func (_c *MockManager_SetClock_Call) Run(run func(clock log.Clock)) *MockManager_SetClock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.Clock))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetClock_Call) Return() *MockManager_SetClock_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_SetClock_Call) RunAndReturn(run func(log.Clock)) *MockManager_SetClock_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetErrorHandler provides a mock function with given fields: fn
+func (_m *MockManager) SetErrorHandler(fn log.ErrorHandler) {
+	_m.Called(fn)
+}
+
+// MockManager_SetErrorHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetErrorHandler'
+type MockManager_SetErrorHandler_Call struct {
+	*mock.Call
+}
+
+// SetErrorHandler is a helper method to define mock.On call
+//   - fn log.ErrorHandler
+func (_e *MockManager_Expecter) SetErrorHandler(fn interface{}) *MockManager_SetErrorHandler_Call {
+	return &MockManager_SetErrorHandler_Call{Call: _e.mock.On("SetErrorHandler", fn)}
+}
+
+func (_c *MockManager_SetErrorHandler_Call) Run(run func(fn log.ErrorHandler)) *MockManager_SetErrorHandler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.ErrorHandler))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetErrorHandler_Call) Return() *MockManager_SetErrorHandler_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_SetErrorHandler_Call) RunAndReturn(run func(log.ErrorHandler)) *MockManager_SetErrorHandler_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetHandler provides a mock function with given fields: loggerContext, handlerType
+func (_m *MockManager) SetHandler(loggerContext string, handlerType log.HandlerType) {
+	_m.Called(loggerContext, handlerType)
+}
+
+// MockManager_SetHandler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetHandler'
+type MockManager_SetHandler_Call struct {
+	*mock.Call
+}
+
+// SetHandler is a helper method to define mock.On call
+//   - loggerContext string
+//   - handlerType log.HandlerType
+func (_e *MockManager_Expecter) SetHandler(loggerContext interface{}, handlerType interface{}) *MockManager_SetHandler_Call {
+	return &MockManager_SetHandler_Call{Call: _e.mock.On("SetHandler", loggerContext, handlerType)}
+}
+
+func (_c *MockManager_SetHandler_Call) Run(run func(loggerContext string, handlerType log.HandlerType)) *MockManager_SetHandler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(log.HandlerType))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetHandler_Call) Return() *MockManager_SetHandler_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_SetHandler_Call) RunAndReturn(run func(string, log.HandlerType)) *MockManager_SetHandler_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetHandlerLevel provides a mock function with given fields: handlerType, level
+func (_m *MockManager) SetHandlerLevel(handlerType log.HandlerType, level handler.Level) error {
+	ret := _m.Called(handlerType, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetHandlerLevel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(log.HandlerType, handler.Level) error); ok {
+		r0 = rf(handlerType, level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_SetHandlerLevel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetHandlerLevel'
+type MockManager_SetHandlerLevel_Call struct {
+	*mock.Call
+}
+
+// SetHandlerLevel is a helper method to define mock.On call
+//   - handlerType log.HandlerType
+//   - level handler.Level
+func (_e *MockManager_Expecter) SetHandlerLevel(handlerType interface{}, level interface{}) *MockManager_SetHandlerLevel_Call {
+	return &MockManager_SetHandlerLevel_Call{Call: _e.mock.On("SetHandlerLevel", handlerType, level)}
+}
+
+func (_c *MockManager_SetHandlerLevel_Call) Run(run func(handlerType log.HandlerType, level handler.Level)) *MockManager_SetHandlerLevel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.HandlerType), args[1].(handler.Level))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetHandlerLevel_Call) Return(_a0 error) *MockManager_SetHandlerLevel_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_SetHandlerLevel_Call) RunAndReturn(run func(log.HandlerType, handler.Level) error) *MockManager_SetHandlerLevel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetLevel provides a mock function with given fields: level
+func (_m *MockManager) SetLevel(level handler.Level) error {
+	ret := _m.Called(level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLevel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(handler.Level) error); ok {
+		r0 = rf(level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_SetLevel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLevel'
+type MockManager_SetLevel_Call struct {
+	*mock.Call
+}
+
+// SetLevel is a helper method to define mock.On call
+//   - level handler.Level
+func (_e *MockManager_Expecter) SetLevel(level interface{}) *MockManager_SetLevel_Call {
+	return &MockManager_SetLevel_Call{Call: _e.mock.On("SetLevel", level)}
+}
+
+func (_c *MockManager_SetLevel_Call) Run(run func(level handler.Level)) *MockManager_SetLevel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(handler.Level))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetLevel_Call) Return(_a0 error) *MockManager_SetLevel_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_SetLevel_Call) RunAndReturn(run func(handler.Level) error) *MockManager_SetLevel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetMetricsObserver provides a mock function with given fields: o
+func (_m *MockManager) SetMetricsObserver(o log.MetricsObserver) {
+	_m.Called(o)
+}
+
+// MockManager_SetMetricsObserver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMetricsObserver'
+type MockManager_SetMetricsObserver_Call struct {
+	*mock.Call
+}
+
+// SetMetricsObserver is a helper method to define mock.On call
+//   - o log.MetricsObserver
+func (_e *MockManager_Expecter) SetMetricsObserver(o interface{}) *MockManager_SetMetricsObserver_Call {
+	return &MockManager_SetMetricsObserver_Call{Call: _e.mock.On("SetMetricsObserver", o)}
+}
+
+func (_c *MockManager_SetMetricsObserver_Call) Run(run func(o log.MetricsObserver)) *MockManager_SetMetricsObserver_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(log.MetricsObserver))
+	})
+	return _c
+}
+
+func (_c *MockManager_SetMetricsObserver_Call) Return() *MockManager_SetMetricsObserver_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_SetMetricsObserver_Call) RunAndReturn(run func(log.MetricsObserver)) *MockManager_SetMetricsObserver_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *MockManager) Start(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type MockManager_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockManager_Expecter) Start(ctx interface{}) *MockManager_Start_Call {
+	return &MockManager_Start_Call{Call: _e.mock.On("Start", ctx)}
+}
+
+func (_c *MockManager_Start_Call) Run(run func(ctx context.Context)) *MockManager_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockManager_Start_Call) Return(_a0 error) *MockManager_Start_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Start_Call) RunAndReturn(run func(context.Context) error) *MockManager_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with no fields
+func (_m *MockManager) Stats() log.Stats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 log.Stats
+	if rf, ok := ret.Get(0).(func() log.Stats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(log.Stats)
+	}
+
+	return r0
+}
+
+// MockManager_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockManager_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+func (_e *MockManager_Expecter) Stats() *MockManager_Stats_Call {
+	return &MockManager_Stats_Call{Call: _e.mock.On("Stats")}
+}
+
+func (_c *MockManager_Stats_Call) Run(run func()) *MockManager_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockManager_Stats_Call) Return(_a0 log.Stats) *MockManager_Stats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Stats_Call) RunAndReturn(run func() log.Stats) *MockManager_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *MockManager) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type MockManager_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockManager_Expecter) Stop(ctx interface{}) *MockManager_Stop_Call {
+	return &MockManager_Stop_Call{Call: _e.mock.On("Stop", ctx)}
+}
+
+func (_c *MockManager_Stop_Call) Run(run func(ctx context.Context)) *MockManager_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockManager_Stop_Call) Return(_a0 error) *MockManager_Stop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_Stop_Call) RunAndReturn(run func(context.Context) error) *MockManager_Stop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateConfig provides a mock function with given fields: config
+func (_m *MockManager) ValidateConfig(config *log.Config) (*log.ConfigDiff, error) {
+	ret := _m.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateConfig")
+	}
+
+	var r0 *log.ConfigDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*log.Config) (*log.ConfigDiff, error)); ok {
+		return rf(config)
+	}
+	if rf, ok := ret.Get(0).(func(*log.Config) *log.ConfigDiff); ok {
+		r0 = rf(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*log.ConfigDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*log.Config) error); ok {
+		r1 = rf(config)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockManager_ValidateConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateConfig'
+type MockManager_ValidateConfig_Call struct {
+	*mock.Call
+}
+
+// ValidateConfig is a helper method to define mock.On call
+//   - config *log.Config
+func (_e *MockManager_Expecter) ValidateConfig(config interface{}) *MockManager_ValidateConfig_Call {
+	return &MockManager_ValidateConfig_Call{Call: _e.mock.On("ValidateConfig", config)}
+}
+
+func (_c *MockManager_ValidateConfig_Call) Run(run func(config *log.Config)) *MockManager_ValidateConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*log.Config))
+	})
+	return _c
+}
+
+func (_c *MockManager_ValidateConfig_Call) Return(_a0 *log.ConfigDiff, _a1 error) *MockManager_ValidateConfig_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockManager_ValidateConfig_Call) RunAndReturn(run func(*log.Config) (*log.ConfigDiff, error)) *MockManager_ValidateConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithFields provides a mock function with given fields: fields
+func (_m *MockManager) WithFields(fields ...log.Field) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// MockManager_WithFields_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithFields'
+type MockManager_WithFields_Call struct {
+	*mock.Call
+}
+
+// WithFields is a helper method to define mock.On call
+//   - fields ...log.Field
+func (_e *MockManager_Expecter) WithFields(fields ...interface{}) *MockManager_WithFields_Call {
+	return &MockManager_WithFields_Call{Call: _e.mock.On("WithFields",
+		append([]interface{}{}, fields...)...)}
+}
+
+func (_c *MockManager_WithFields_Call) Run(run func(fields ...log.Field)) *MockManager_WithFields_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]log.Field, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(log.Field)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockManager_WithFields_Call) Return() *MockManager_WithFields_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockManager_WithFields_Call) RunAndReturn(run func(...log.Field)) *MockManager_WithFields_Call {
 	_c.Run(run)
 	return _c
 }
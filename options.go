@@ -0,0 +1,170 @@
+package log
+
+import "go.fork.vn/log/handler"
+
+// HandlerOption cấu hình cách Manager quản lý một handler được thêm qua AddHandler.
+type HandlerOption func(*handlerOptions)
+
+// handlerOptions chứa các tùy chọn đã được áp dụng cho một handler.
+type handlerOptions struct {
+	external bool // Handler thuộc sở hữu của bên ngoài, Manager không được đóng
+}
+
+// WithExternalOwnership đánh dấu handler thuộc sở hữu của bên gọi.
+//
+// Manager sẽ không đóng handler này khi nó bị thay thế, bị xóa hoặc khi
+// Manager.Close được gọi. Bên gọi chịu trách nhiệm đóng handler khi không còn sử dụng.
+//
+// Trả về:
+//   - HandlerOption: tùy chọn đánh dấu handler được sở hữu bên ngoài
+//
+// Ví dụ:
+//
+//	shared := handler.NewConsoleHandler(true)
+//	manager.AddHandler("shared", shared, log.WithExternalOwnership())
+//	defer shared.Close() // Bên gọi tự đóng handler
+func WithExternalOwnership() HandlerOption {
+	return func(o *handlerOptions) {
+		o.external = true
+	}
+}
+
+// applyHandlerOptions áp dụng các tùy chọn và trả về kết quả.
+func applyHandlerOptions(opts []HandlerOption) handlerOptions {
+	var o handlerOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// LoggerOption cấu hình một logger được tạo qua NewLogger.
+type LoggerOption func(*logger)
+
+// WithCaller bật ghi kèm vị trí gọi log dạng caller=service/user.go:42.
+//
+// Trả về:
+//   - LoggerOption: tùy chọn bật thông tin caller
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("UserService", log.WithCaller())
+func WithCaller() LoggerOption {
+	return func(l *logger) {
+		l.caller = true
+	}
+}
+
+// WithCallerSkip bật thông tin caller và bỏ qua thêm skip stack frame khi xác định vị trí gọi.
+//
+// Dùng khi logger được gọi qua một hàm bọc chung, để caller trỏ đến nơi gọi hàm bọc
+// thay vì chính hàm bọc.
+//
+// Tham số:
+//   - skip: int - số stack frame bổ sung cần bỏ qua
+//
+// Trả về:
+//   - LoggerOption: tùy chọn bật thông tin caller với số frame bỏ qua
+//
+// Ví dụ:
+//
+//	// logError được gọi từ nhiều nơi; caller trỏ đến nơi gọi logError
+//	var errLogger = log.NewLogger("App", log.WithCallerSkip(1))
+//	func logError(err error) { errLogger.Error("operation failed", log.Any("error", err)) }
+func WithCallerSkip(skip int) LoggerOption {
+	return func(l *logger) {
+		l.caller = true
+		l.callerSkip = skip
+	}
+}
+
+// WithLevel thiết lập cấp độ log tối thiểu ban đầu của logger (mặc định InfoLevel).
+//
+// Tham số:
+//   - level: handler.Level - cấp độ log tối thiểu
+//
+// Trả về:
+//   - LoggerOption: tùy chọn thiết lập cấp độ log
+//
+// Ví dụ:
+//
+//	logger := log.Simple(log.WithLevel(handler.DebugLevel))
+func WithLevel(level handler.Level) LoggerOption {
+	return func(l *logger) {
+		l.minLevel.Store(int32(level))
+	}
+}
+
+// WithConsole gắn một console handler vào logger.
+//
+// Với Simple, option này thay thế console handler mặc định (có màu).
+//
+// Tham số:
+//   - colored: bool - bật/tắt màu sắc cho console output
+//
+// Trả về:
+//   - LoggerOption: tùy chọn gắn console handler
+//
+// Ví dụ:
+//
+//	logger := log.Simple(log.WithConsole(false)) // Không màu, phù hợp khi chuyển hướng output
+func WithConsole(colored bool) LoggerOption {
+	return func(l *logger) {
+		l.handlers[HandlerTypeConsole] = handler.NewConsoleHandler(colored)
+	}
+}
+
+// WithFieldLimits giới hạn độ sâu, số phần tử và số field khi ghi field có cấu trúc.
+//
+// Giá trị không dương trong limits dùng giới hạn mặc định tương ứng.
+//
+// Tham số:
+//   - limits: handler.Limits - giới hạn của các field
+//
+// Trả về:
+//   - LoggerOption: tùy chọn giới hạn field
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("API", log.WithFieldLimits(handler.Limits{MaxDepth: 3, MaxFields: 20}))
+func WithFieldLimits(limits handler.Limits) LoggerOption {
+	return func(l *logger) {
+		l.limits = limits
+	}
+}
+
+// WithSampling bật lấy mẫu cho logger với một sampler riêng.
+//
+// Entry được nhóm theo cấp độ và thông điệp trước khi định dạng, nên
+// logger.Info("user %d", id) với các id khác nhau vẫn thuộc cùng một nhóm.
+//
+// Tham số:
+//   - opts: handler.SamplingOptions - cấu hình lấy mẫu
+//
+// Trả về:
+//   - LoggerOption: tùy chọn bật lấy mẫu
+//
+// Ví dụ:
+//
+//	// Mỗi giây ghi 100 entry đầu tiên của mỗi thông điệp, sau đó 1/100
+//	logger := log.NewLogger("Worker", log.WithSampling(handler.SamplingOptions{Initial: 100, Thereafter: 100}))
+func WithSampling(opts handler.SamplingOptions) LoggerOption {
+	return WithSampler(handler.NewSampler(opts))
+}
+
+// WithSampler dùng một sampler có sẵn cho logger, cho phép nhiều logger chia sẻ bộ đếm.
+//
+// Entry của các logger được nhóm riêng theo context nên việc chia sẻ chỉ tiết kiệm bộ nhớ.
+//
+// Tham số:
+//   - sampler: *handler.Sampler - sampler dùng chung (nil để tắt lấy mẫu)
+//
+// Trả về:
+//   - LoggerOption: tùy chọn dùng sampler
+func WithSampler(sampler *handler.Sampler) LoggerOption {
+	return func(l *logger) {
+		l.sampler = sampler
+	}
+}
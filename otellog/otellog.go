@@ -0,0 +1,77 @@
+// Package otellog gắn trace ID và span ID của span OpenTelemetry đang hoạt động trong
+// context.Context vào các entry được ghi qua các method *Context (VD: InfoContext), để liên kết
+// log với trace (VD: Grafana Loki với Tempo).
+//
+// Ví dụ:
+//
+//	otellog.Register(manager)
+//
+//	ctx, span := tracer.Start(r.Context(), "CreateOrder")
+//	defer span.End()
+//	logger.InfoContext(ctx, "Order created")
+//	// Output: [Order] Order created trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7
+package otellog
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go.fork.vn/log"
+)
+
+// Tên các field được gắn vào entry, theo quy ước của OpenTelemetry cho log.
+const (
+	FieldTraceID = "trace_id" // Trace ID dạng hex 32 ký tự
+	FieldSpanID  = "span_id"  // Span ID dạng hex 16 ký tự
+)
+
+// Fields trả về trace ID và span ID của span trong ctx.
+//
+// Tham số:
+//   - ctx: context.Context - context chứa span (VD: từ tracer.Start)
+//
+// Trả về:
+//   - []log.Field: field trace_id và span_id, nil nếu ctx không có span context hợp lệ
+//
+// Ví dụ:
+//
+//	logger.Info("Order created", otellog.Fields(ctx)...)
+func Fields(ctx context.Context) []log.Field {
+	if ctx == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []log.Field{
+		log.String(FieldTraceID, sc.TraceID().String()),
+		log.String(FieldSpanID, sc.SpanID().String()),
+	}
+}
+
+// ContextField trả về ContextField gắn trace ID và span ID, dùng với log.WithContextFields cho
+// logger không do Manager tạo.
+//
+// Trả về:
+//   - log.ContextField: field được tính bằng Fields
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Worker", log.WithContextFields(otellog.ContextField()))
+func ContextField() log.ContextField {
+	return log.ContextField{Extract: Fields}
+}
+
+// Register gắn trace ID và span ID vào entry của mọi logger do m tạo (kể cả logger đã tạo).
+//
+// Tham số:
+//   - m: log.Manager - manager cần đăng ký
+//
+// Ví dụ:
+//
+//	otellog.Register(manager)
+func Register(m log.Manager) {
+	m.AddContextExtractor(Fields)
+}
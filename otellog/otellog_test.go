@@ -0,0 +1,85 @@
+package otellog
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// messageRecorder lưu thông điệp cuối cùng được ghi.
+type messageRecorder struct {
+	message string
+}
+
+func (r *messageRecorder) Log(level handler.Level, message string, args ...interface{}) error {
+	r.message = message
+	return nil
+}
+
+func (r *messageRecorder) Close() error {
+	return nil
+}
+
+// spanContext trả về context chứa một span context hợp lệ.
+func spanContext() context.Context {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+const wantIDs = " trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7"
+
+func TestFields(t *testing.T) {
+	fields := Fields(spanContext())
+	if len(fields) != 2 || fields[0].Key != FieldTraceID || fields[1].Key != FieldSpanID {
+		t.Fatalf("Fields() nên trả về trace_id và span_id, got %v", fields)
+	}
+	if fields := Fields(context.Background()); fields != nil {
+		t.Errorf("Fields() không có span nên trả về nil, got %v", fields)
+	}
+	if fields := Fields(nil); fields != nil {
+		t.Errorf("Fields(nil) nên trả về nil, got %v", fields)
+	}
+}
+
+func TestContextField(t *testing.T) {
+	l := log.NewLogger("Worker", log.WithContextFields(ContextField()))
+	h := &messageRecorder{}
+	l.AddHandler("test", h)
+
+	l.InfoContext(spanContext(), "job done")
+	if h.message != "[Worker] job done"+wantIDs {
+		t.Errorf("InfoContext() nên gắn trace ID và span ID, got %q", h.message)
+	}
+	l.InfoContext(context.Background(), "job done")
+	if h.message != "[Worker] job done" {
+		t.Errorf("InfoContext() không có span không nên gắn field, got %q", h.message)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	m, err := log.New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer m.Close()
+
+	l := m.GetLogger("Order")
+	h := &messageRecorder{}
+	m.AddHandler("test", h)
+
+	Register(m)
+	l.ErrorContext(spanContext(), "failed")
+	if h.message != "[Order] failed"+wantIDs {
+		t.Errorf("Register() nên gắn trace ID và span ID cho logger đã tạo, got %q", h.message)
+	}
+}
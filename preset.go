@@ -0,0 +1,255 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// ProductionConfig trả về cấu hình khuyến nghị cho môi trường production.
+//
+// Cấu hình gồm:
+//   - Level: InfoLevel
+//   - Console không màu và file JSON tại path, ghi qua stack
+//   - File xoay vòng ở 100MB, nén gzip file sao lưu và ghi bất đồng bộ (hàng đợi 4096 entry)
+//   - Cảnh báo khi file tăng hơn 50MB/phút trong 5 phút
+//   - Lấy mẫu: 100 entry giống nhau đầu tiên mỗi giây, sau đó 1/100
+//   - Che giá trị các field password, token, secret và authorization
+//
+// Tham số:
+//   - path: string - đường dẫn file log (VD: "storage/logs/app.log")
+//
+// Trả về:
+//   - *Config: cấu hình production, có thể chỉnh sửa trước khi tạo Manager
+func ProductionConfig(path string) *Config {
+	config := DefaultConfig()
+	config.Console.Colored = false
+	config.File = FileConfig{
+		Enabled:     true,
+		Path:        path,
+		MaxSize:     100 * 1024 * 1024,
+		Format:      "json",
+		GrowthAlert: GrowthAlertConfig{MaxRate: 50 * 1024 * 1024, Period: 5 * time.Minute},
+		Compression: "gzip",
+	}
+	config.Stack = StackConfig{Enabled: true, Handlers: StackHandlers{Console: true, File: true}}
+	config.Async = map[string]AsyncConfig{string(HandlerTypeFile): {Workers: 1, QueueSize: 4096}}
+	config.Sampling = SamplingConfig{Initial: 100, Thereafter: 100, Tick: time.Second}
+	config.Redaction = RedactionConfig{Fields: []string{"password", "token", "secret", "authorization"}}
+	return config
+}
+
+// DevelopmentConfig trả về cấu hình khuyến nghị cho môi trường phát triển.
+//
+// Cấu hình gồm:
+//   - Level: DebugLevel
+//   - Console có màu, không ghi file
+//   - Ghi kèm vị trí gọi log (caller=file:line)
+//
+// Trả về:
+//   - *Config: cấu hình development, có thể chỉnh sửa trước khi tạo Manager
+func DevelopmentConfig() *Config {
+	config := DefaultConfig()
+	config.Level = handler.DebugLevel
+	config.EnableCaller = true
+	config.File.Enabled = false
+	return config
+}
+
+// Merge ghi đè c bằng section key của source, để cấu hình theo môi trường chỉ cần chứa phần khác
+// với cấu hình gốc (VD: một preset). Chỉ các key có mặt trong section được áp dụng, kể cả giá trị
+// zero (false, 0, ""), nên môi trường có thể tắt một tùy chọn hoặc hạ cấp độ xuống debug. Các
+// quy tắc:
+//   - Key có mặt thay thế giá trị của c; section lồng nhau (VD: file) được gộp theo từng key
+//   - Map (VD: async, channels) được gộp theo key: key mới được thêm, key đã có được gộp theo
+//     từng key nếu giá trị là struct
+//   - Slice có mặt thay thế slice của c
+//
+// Section được giải mã hai lần qua UnmarshalKey: một lần vào map để biết key nào có mặt, một lần
+// vào Config để giá trị được chuyển kiểu như khi đọc cấu hình log. c không chia sẻ map hoặc
+// slice với giá trị giải mã.
+//
+// Tham số:
+//   - source: ConfigSource - nguồn cấu hình, VD: config.Manager
+//   - key: string - key của section cấu hình của môi trường
+//
+// Trả về:
+//   - error: lỗi nếu không thể giải mã section
+//
+// Ví dụ:
+//
+//	config := log.ProductionConfig("storage/logs/app.log")
+//	if err := config.Merge(configManager, "log_staging"); err != nil { // chỉ chứa phần khác production
+//	    return err
+//	}
+func (c *Config) Merge(source ConfigSource, key string) error {
+	var present map[string]interface{}
+	if err := source.UnmarshalKey(key, &present); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", key, err)
+	}
+	var override Config
+	if err := source.UnmarshalKey(key, &override); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", key, err)
+	}
+	mergeStruct(reflect.ValueOf(c).Elem(), reflect.ValueOf(&override).Elem(), present)
+	return nil
+}
+
+// mergeStruct gộp các trường của src có key trong present vào dst theo quy tắc của Config.Merge.
+func mergeStruct(dst, src reflect.Value, present map[string]interface{}) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Type().Field(i)
+		raw, ok := presentValue(present, fieldKey(field))
+		if !ok || !dst.Field(i).CanSet() {
+			continue
+		}
+		nested, isMap := rawMap(raw)
+		switch {
+		case field.Type.Kind() == reflect.Struct && isMap:
+			mergeStruct(dst.Field(i), src.Field(i), nested)
+		case field.Type.Kind() == reflect.Map && isMap:
+			mergeMap(dst.Field(i), src.Field(i), nested)
+		default:
+			dst.Field(i).Set(cloneValue(src.Field(i)))
+		}
+	}
+}
+
+// mergeMap gộp map src vào dst theo key; giá trị struct của key đã có trong dst được gộp theo các
+// key có mặt trong present.
+func mergeMap(dst, src reflect.Value, present map[string]interface{}) {
+	merged := reflect.MakeMapWithSize(src.Type(), dst.Len()+src.Len())
+	iter := dst.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+	iter = src.MapRange()
+	for iter.Next() {
+		value := reflect.New(src.Type().Elem()).Elem()
+		old := merged.MapIndex(iter.Key())
+		nested, isMap := rawMap(present[fmt.Sprint(iter.Key().Interface())])
+		if value.Kind() == reflect.Struct && old.IsValid() && isMap {
+			value.Set(old)
+			mergeStruct(value, iter.Value(), nested)
+		} else {
+			value.Set(cloneValue(iter.Value()))
+		}
+		merged.SetMapIndex(iter.Key(), value)
+	}
+	dst.Set(merged)
+}
+
+// fieldKey trả về key cấu hình của trường theo tag mapstructure, hoặc tên trường nếu không có tag.
+func fieldKey(field reflect.StructField) string {
+	if tag, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ","); tag != "" {
+		return tag
+	}
+	return field.Name
+}
+
+// presentValue trả về giá trị của key trong present, không phân biệt hoa thường như khi config
+// manager giải mã.
+func presentValue(present map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := present[key]; ok {
+		return value, true
+	}
+	for k, value := range present {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// rawMap chuyển một section đã giải mã thành map[string]interface{}.
+func rawMap(raw interface{}) (map[string]interface{}, bool) {
+	switch m := raw.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			converted[fmt.Sprint(k)] = v
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// cloneValue trả về bản sao của v với slice và map được sao chép (nông), để Merge không chia sẻ
+// chúng với giá trị giải mã.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// NewProductionManager tạo Manager với cấu hình ProductionConfig trong một lần gọi.
+//
+// Thư mục chứa file log được tạo nếu chưa tồn tại. Bên gọi nên gọi Close khi ứng dụng dừng để
+// ghi hết hàng đợi bất đồng bộ.
+//
+// Tham số:
+//   - path: string - đường dẫn file log
+//
+// Trả về:
+//   - Manager: manager đã được cấu hình
+//   - error: lỗi nếu không thể tạo thư mục log hoặc cấu hình không hợp lệ
+//
+// Ví dụ:
+//
+//	manager, err := log.NewProductionManager("storage/logs/app.log")
+//	if err != nil {
+//	    return err
+//	}
+//	defer manager.Close()
+//	manager.GetLogger("API").Info("Máy chủ đã khởi động")
+func NewProductionManager(path string) (Manager, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	config := ProductionConfig(path)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return New(config)
+}
+
+// NewDevelopmentManager tạo Manager với cấu hình DevelopmentConfig trong một lần gọi.
+//
+// Trả về:
+//   - Manager: manager ghi log debug có màu ra console
+//
+// Ví dụ:
+//
+//	manager := log.NewDevelopmentManager()
+//	defer manager.Close()
+//	manager.GetLogger("Worker").Debug("Đang xử lý job %d", id)
+func NewDevelopmentManager() Manager {
+	return NewManager(DevelopmentConfig())
+}
@@ -0,0 +1,157 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestNewProductionManager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	m, err := NewProductionManager(path)
+	if err != nil {
+		t.Fatalf("NewProductionManager() error = %v", err)
+	}
+	defer m.Close()
+
+	if _, ok := m.GetHandler(HandlerTypeFile).(*handler.AsyncHandler); !ok {
+		t.Errorf("File handler nên được ghi bất đồng bộ, got %T", m.GetHandler(HandlerTypeFile))
+	}
+	if m.GetHandler(HandlerTypeStack) == nil {
+		t.Error("Production nên ghi console và file qua stack")
+	}
+
+	l := m.GetLogger("API")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+	l.Debug("hidden")
+	l.Info("login", String("password", "hunter2"))
+	if h.entry == nil || h.entry.Message != "[API] login password=[REDACTED]" {
+		t.Errorf("Production nên bỏ debug và che field nhạy cảm, got %v", h.entry)
+	}
+
+	m.Close()
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), `{"time":`) {
+		t.Errorf("Production nên ghi file dạng JSON, got %q", data)
+	}
+
+	if _, err := NewProductionManager(""); err == nil {
+		t.Error("NewProductionManager() nên trả về lỗi khi thiếu đường dẫn")
+	}
+}
+
+func TestNewDevelopmentManager(t *testing.T) {
+	if err := DevelopmentConfig().Validate(); err != nil {
+		t.Fatalf("DevelopmentConfig() nên hợp lệ, got %v", err)
+	}
+	if config := DevelopmentConfig(); config.File.Enabled || config.File.Path != "" {
+		t.Errorf("DevelopmentConfig() không nên ghi file, got enabled=%v path=%q", config.File.Enabled, config.File.Path)
+	}
+	m := NewDevelopmentManager()
+	defer m.Close()
+
+	l := m.GetLogger("Worker")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+	want := nextLine()
+	l.Debug("processing")
+	if h.entry == nil || h.entry.Message != "[Worker] processing caller="+want {
+		t.Errorf("Development nên ghi debug kèm caller, got %v", h.entry)
+	}
+}
+
+// mapConfigSource là config manager giả giải mã section từ map qua JSON, như config manager giải
+// mã YAML đã đọc.
+type mapConfigSource map[string]interface{}
+
+func (s mapConfigSource) UnmarshalKey(key string, out interface{}) error {
+	data, err := json.Marshal(s[key])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func TestConfig_Merge(t *testing.T) {
+	base := ProductionConfig("storage/logs/app.log")
+	source := mapConfigSource{"log_staging": map[string]interface{}{
+		"level": int(handler.WarningLevel),
+		"file":  map[string]interface{}{"path": "storage/logs/staging.log"},
+		"async": map[string]interface{}{
+			"file": map[string]interface{}{"queue_size": 100},
+			"loki": map[string]interface{}{"workers": 4},
+		},
+		"channels":  map[string]interface{}{ChannelAccess: map[string]interface{}{"path": "storage/logs/access.log"}},
+		"redaction": map[string]interface{}{"fields": []string{"card"}},
+		"sampling":  map[string]interface{}{"tick": int64(5 * time.Second)},
+	}}
+
+	if err := base.Merge(source, "log_staging"); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if base.Level != handler.WarningLevel || base.File.Path != "storage/logs/staging.log" || base.File.Format != "json" {
+		t.Errorf("Merge() nên ghi đè key có mặt và giữ các trường khác, got %v %q %q", base.Level, base.File.Path, base.File.Format)
+	}
+	if base.Async["file"] != (AsyncConfig{Workers: 1, QueueSize: 100}) || base.Async["loki"].Workers != 4 {
+		t.Errorf("Merge() nên gộp map theo key và gộp struct theo key, got %v", base.Async)
+	}
+	if access := base.Channels[ChannelAccess]; access.Path != "storage/logs/access.log" || len(access.Contexts) != 1 {
+		t.Errorf("Merge() nên giữ contexts của channel access, got %+v", access)
+	}
+	if _, ok := base.Channels[ChannelAudit]; !ok {
+		t.Error("Merge() không nên bỏ key không có trong section")
+	}
+	if len(base.Redaction.Fields) != 1 || base.Sampling.Initial != 100 || base.Sampling.Tick != 5*time.Second {
+		t.Errorf("Merge() nên thay slice và giữ trường không có mặt, got %v %+v", base.Redaction.Fields, base.Sampling)
+	}
+
+	if err := base.Merge(mapConfigSource{}, "missing"); err != nil {
+		t.Fatalf("Merge() với section không tồn tại error = %v", err)
+	}
+	if base.Level != handler.WarningLevel || !base.File.Enabled {
+		t.Error("Merge() với section không tồn tại nên giữ nguyên config")
+	}
+}
+
+func TestConfig_Merge_ZeroValues(t *testing.T) {
+	base := ProductionConfig("storage/logs/app.log")
+	source := mapConfigSource{"log_dev": map[string]interface{}{
+		"level":   int(handler.DebugLevel),
+		"console": map[string]interface{}{"enabled": false},
+		"file":    map[string]interface{}{"enabled": false, "compression": "", "max_size": 0},
+		"stack":   map[string]interface{}{"enabled": false},
+		"async":   map[string]interface{}{"file": map[string]interface{}{"workers": 0}},
+		"redaction": map[string]interface{}{
+			"fields": []string{},
+		},
+	}}
+
+	if err := base.Merge(source, "log_dev"); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if base.Level != handler.DebugLevel {
+		t.Errorf("Merge() nên hạ cấp độ xuống debug, got %v", base.Level)
+	}
+	if base.Console.Enabled || base.File.Enabled || base.Stack.Enabled {
+		t.Errorf("Merge() nên tắt handler khi enabled: false, got console=%v file=%v stack=%v",
+			base.Console.Enabled, base.File.Enabled, base.Stack.Enabled)
+	}
+	if base.File.Compression != "" || base.File.MaxSize != 0 || base.File.Path != "storage/logs/app.log" {
+		t.Errorf("Merge() nên áp dụng giá trị zero có mặt và giữ key không có mặt, got %+v", base.File)
+	}
+	if base.Async["file"] != (AsyncConfig{QueueSize: 4096}) {
+		t.Errorf("Merge() nên áp dụng giá trị zero trong map, got %+v", base.Async["file"])
+	}
+	if len(base.Redaction.Fields) != 0 {
+		t.Errorf("Merge() nên thay bằng slice rỗng có mặt, got %v", base.Redaction.Fields)
+	}
+	if base.Sampling.Initial != 100 || base.Console.Colored {
+		t.Errorf("Merge() không nên đổi section không có mặt, got %+v colored=%v", base.Sampling, base.Console.Colored)
+	}
+}
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"fmt"
+
+	"go.fork.vn/log/handler"
+)
+
+// Các method printf (Debugf, Infof, ...) giữ nguyên ngữ nghĩa định dạng của fmt.Sprintf cho
+// mọi tham số: Field không được tách thành field có cấu trúc mà được định dạng như một tham số
+// bình thường. Chúng là lối thoát khi chuyển các lời gọi cũ sang dạng key-value, để cách diễn
+// giải tham số của mỗi lời gọi luôn rõ ràng.
+
+// Debugf ghi một thông điệp ở cấp độ debug theo ngữ nghĩa printf.
+//
+// Tham số:
+//   - format: string - chuỗi định dạng
+//   - args: ...interface{} - các tham số định dạng
+//
+// Ví dụ:
+//
+//	logger.Debugf("Lần thử kết nối %d đến %s", attempt, serverAddress)
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.logf(handler.DebugLevel, format, args)
+}
+
+// Infof ghi một thông điệp ở cấp độ info theo ngữ nghĩa printf.
+//
+// Tham số:
+//   - format: string - chuỗi định dạng
+//   - args: ...interface{} - các tham số định dạng
+//
+// Ví dụ:
+//
+//	logger.Infof("Máy chủ đã khởi động trên cổng %d", port)
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.logf(handler.InfoLevel, format, args)
+}
+
+// Warningf ghi một thông điệp ở cấp độ warning theo ngữ nghĩa printf.
+//
+// Tham số:
+//   - format: string - chuỗi định dạng
+//   - args: ...interface{} - các tham số định dạng
+//
+// Ví dụ:
+//
+//	logger.Warningf("Sử dụng bộ nhớ cao: %d MB", memoryUsage)
+func (l *logger) Warningf(format string, args ...interface{}) {
+	l.logf(handler.WarningLevel, format, args)
+}
+
+// Errorf ghi một thông điệp ở cấp độ error theo ngữ nghĩa printf.
+//
+// Tham số:
+//   - format: string - chuỗi định dạng
+//   - args: ...interface{} - các tham số định dạng
+//
+// Ví dụ:
+//
+//	logger.Errorf("Xử lý yêu cầu thất bại: %v", err)
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.logf(handler.ErrorLevel, format, args)
+}
+
+// Fatalf ghi một thông điệp ở cấp độ fatal theo ngữ nghĩa printf.
+//
+// Tham số:
+//   - format: string - chuỗi định dạng
+//   - args: ...interface{} - các tham số định dạng
+//
+// Ví dụ:
+//
+//	logger.Fatalf("Kết nối database thất bại: %v", err)
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.logf(handler.FatalLevel, format, args)
+}
+
+// logf ghi một log entry theo ngữ nghĩa printf. Thông điệp chỉ được định dạng khi entry vượt
+// qua cấp độ tối thiểu, có handler chấp nhận cấp độ và không bị lấy mẫu bỏ.
+func (l *logger) logf(level handler.Level, format string, args []interface{}) {
+	if level < l.getMinLevel() {
+		return
+	}
+	snapshot := l.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, l.context, format) {
+		return
+	}
+
+	l.emit(snapshot, snapshot.now(), level, sprintf(format, args), callerFields(l.withCaller(nil, 2)))
+}
+
+// Debugf ghi một thông điệp ở cấp độ debug theo ngữ nghĩa printf nếu thông điệp không bị chặn.
+func (r *repeatLogger) Debugf(format string, args ...interface{}) {
+	r.logf(handler.DebugLevel, format, args)
+}
+
+// Infof ghi một thông điệp ở cấp độ info theo ngữ nghĩa printf nếu thông điệp không bị chặn.
+func (r *repeatLogger) Infof(format string, args ...interface{}) {
+	r.logf(handler.InfoLevel, format, args)
+}
+
+// Warningf ghi một thông điệp ở cấp độ warning theo ngữ nghĩa printf nếu thông điệp không bị chặn.
+func (r *repeatLogger) Warningf(format string, args ...interface{}) {
+	r.logf(handler.WarningLevel, format, args)
+}
+
+// Errorf ghi một thông điệp ở cấp độ error theo ngữ nghĩa printf nếu thông điệp không bị chặn.
+func (r *repeatLogger) Errorf(format string, args ...interface{}) {
+	r.logf(handler.ErrorLevel, format, args)
+}
+
+// Fatalf ghi một thông điệp ở cấp độ fatal theo ngữ nghĩa printf nếu thông điệp không bị chặn.
+func (r *repeatLogger) Fatalf(format string, args ...interface{}) {
+	r.logf(handler.FatalLevel, format, args)
+}
+
+// logf ghi entry theo ngữ nghĩa printf qua logger gốc nếu entry không bị chặn.
+func (r *repeatLogger) logf(level handler.Level, format string, args []interface{}) {
+	snapshot, suppressed, ok := r.allow(level, format)
+	if !ok {
+		return
+	}
+	var fields []Field
+	if suppressed > 0 {
+		fields = append(fields, Uint64(FieldSuppressed, suppressed))
+	}
+	fields = append(fields, callerFields(r.withCaller(nil, 2))...)
+	r.emit(snapshot, snapshot.now(), level, sprintf(format, args), fields)
+}
+
+// sprintf định dạng thông điệp như fmt.Sprintf, tính các giá trị Lazy ngay trước khi định
+// dạng. Khi không có tham số, format được giữ nguyên như Info để "%" không bị hiểu sai.
+func sprintf(format string, args []interface{}) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, resolveLazy(args)...)
+}
+
+// callerFields chuyển kết quả của withCaller (rỗng hoặc một field caller) thành field.
+func callerFields(caller []interface{}) []Field {
+	if len(caller) == 0 {
+		return nil
+	}
+	return []Field{caller[0].(Field)}
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogger_Printf(t *testing.T) {
+	l := NewLogger("Legacy", WithCaller())
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	field := String("user", "alice")
+	want := nextLine()
+	l.Infof("login %v", field)
+	if !strings.HasPrefix(h.entry.Message, "[Legacy] login user=alice caller=") {
+		t.Errorf("Infof() nên định dạng Field như một tham số printf, got %q", h.entry.Message)
+	}
+	if len(h.entry.Fields) != 1 || h.entry.Fields[0].Key != FieldCaller || !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("Infof() chỉ nên gắn field caller trỏ đến nơi gọi %q, got %q %+v", want, h.entry.Message, h.entry.Fields)
+	}
+
+	want = nextLine()
+	l.Warningf("100% done")
+	if h.entry.Message != "[Legacy] 100% done caller="+want {
+		t.Errorf("Warningf() không có tham số nên giữ nguyên chuỗi định dạng, got %q", h.entry.Message)
+	}
+
+	calls := 0
+	l.Debugf("expensive %v", Lazy(func() interface{} { calls++; return 1 }))
+	if calls != 0 {
+		t.Error("Debugf() dưới cấp độ tối thiểu không nên tính giá trị Lazy")
+	}
+}
+
+func TestRepeatLogger_Printf(t *testing.T) {
+	l := NewLogger("Worker")
+	h := &recordingHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	every := l.EveryN(2)
+	for i := 0; i < 3; i++ {
+		every.Errorf("retry %d failed", i)
+	}
+	got := strings.Join(h.messages, "|")
+	if got != "[Worker] retry 0 failed|[Worker] retry 2 failed suppressed=1" {
+		t.Errorf("EveryN().Errorf() nên nhóm theo chuỗi định dạng và gắn suppressed, got %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+// Package promlog xuất metrics của pipeline ghi log qua prometheus.Collector, để có thể cảnh
+// báo khi việc ghi log gặp sự cố (handler lỗi, entry bị bỏ, hàng đợi đầy, ghi chậm).
+//
+// Collector thu thập các metrics sau (tên với namespace mặc định rỗng):
+//   - log_records_total{context,level}: số entry được gửi đến handler
+//   - log_handler_errors_total{handler}: số lần handler ghi thất bại
+//   - log_handler_write_duration_seconds{handler}: thời gian ghi entry đến handler
+//   - log_handler_bytes_written_total{handler}: số byte handler đã ghi (handler.ByteCounter)
+//   - log_handler_dropped_total{handler}: số entry handler đã bỏ (handler.Dropper)
+//   - log_handler_queue_depth{handler}: số entry đang chờ trong hàng đợi (handler.QueueReporter)
+//
+// Ví dụ:
+//
+//	collector := promlog.NewCollector(manager)
+//	prometheus.MustRegister(collector)
+//	http.Handle("/metrics", promhttp.Handler())
+package promlog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// subsystem là tiền tố chung của tên các metrics.
+const subsystem = "log"
+
+// Option cấu hình Collector.
+type Option func(*options)
+
+// options chứa cấu hình của Collector.
+type options struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace đặt namespace cho tên các metrics, VD: "myapp" cho myapp_log_records_total.
+//
+// Tham số:
+//   - namespace: string - namespace của metrics
+//
+// Trả về:
+//   - Option: option cấu hình Collector
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithBuckets đặt các bucket (giây) của histogram thời gian ghi. Mặc định từ 10µs đến khoảng 2.6s.
+//
+// Tham số:
+//   - buckets: []float64 - giới hạn trên của các bucket, tăng dần
+//
+// Trả về:
+//   - Option: option cấu hình Collector
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// Collector thu thập metrics của một log.Manager. Collector triển khai prometheus.Collector và
+// log.MetricsObserver; các bộ đếm của handler được đọc từ manager tại thời điểm scrape.
+type Collector struct {
+	manager  log.Manager
+	records  *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	bytes    *prometheus.Desc
+	dropped  *prometheus.Desc
+	queue    *prometheus.Desc
+}
+
+// NewCollector tạo Collector và đặt nó làm MetricsObserver của m.
+//
+// Tham số:
+//   - m: log.Manager - manager cần thu thập metrics
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - *Collector: collector cần đăng ký với prometheus.Registerer
+//
+// Ví dụ:
+//
+//	prometheus.MustRegister(promlog.NewCollector(manager, promlog.WithNamespace("shop")))
+func NewCollector(m log.Manager, opts ...Option) *Collector {
+	o := options{buckets: prometheus.ExponentialBuckets(0.00001, 4, 10)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	name := func(name string) string {
+		return prometheus.BuildFQName(o.namespace, subsystem, name)
+	}
+	c := &Collector{
+		manager: m,
+		records: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: name("records_total"),
+			Help: "Number of log records sent to handlers.",
+		}, []string{"context", "level"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: name("handler_errors_total"),
+			Help: "Number of failed writes to a log handler.",
+		}, []string{"handler"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    name("handler_write_duration_seconds"),
+			Help:    "Time spent writing a log record to a handler (enqueue time for async handlers).",
+			Buckets: o.buckets,
+		}, []string{"handler"}),
+		bytes: prometheus.NewDesc(name("handler_bytes_written_total"),
+			"Number of bytes written by a log handler.", []string{"handler"}, nil),
+		dropped: prometheus.NewDesc(name("handler_dropped_total"),
+			"Number of log records dropped by a handler (full queue, sampling).", []string{"handler"}, nil),
+		queue: prometheus.NewDesc(name("handler_queue_depth"),
+			"Number of log records waiting in a handler queue.", []string{"handler"}, nil),
+	}
+	m.SetMetricsObserver(c)
+	return c
+}
+
+// ObserveEntry đếm một entry theo context và cấp độ (log.MetricsObserver).
+//
+// Tham số:
+//   - context: string - context của logger
+//   - level: handler.Level - cấp độ của entry
+func (c *Collector) ObserveEntry(context string, level handler.Level) {
+	c.records.WithLabelValues(context, strings.ToLower(level.String())).Inc()
+}
+
+// ObserveWrite ghi nhận thời gian và lỗi của một lần ghi đến handler (log.MetricsObserver).
+//
+// Tham số:
+//   - handlerType: log.HandlerType - tên handler
+//   - duration: time.Duration - thời gian ghi
+//   - err: error - lỗi ghi, nil nếu thành công
+func (c *Collector) ObserveWrite(handlerType log.HandlerType, duration time.Duration, err error) {
+	c.duration.WithLabelValues(string(handlerType)).Observe(duration.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(string(handlerType)).Inc()
+	}
+}
+
+// Describe gửi mô tả của mọi metrics (prometheus.Collector).
+//
+// Tham số:
+//   - ch: chan<- *prometheus.Desc - kênh nhận mô tả
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.records.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	ch <- c.bytes
+	ch <- c.dropped
+	ch <- c.queue
+}
+
+// Collect gửi giá trị hiện tại của mọi metrics (prometheus.Collector). Các bộ đếm của handler
+// được đọc từ các handler đang đăng ký với manager, trừ stack.
+//
+// Tham số:
+//   - ch: chan<- prometheus.Metric - kênh nhận metrics
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.records.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+
+	for _, handlerType := range c.manager.Handlers() {
+		// Các handler con của stack đã được đăng ký riêng với manager
+		if handlerType == log.HandlerTypeStack {
+			continue
+		}
+		h := c.manager.GetHandler(handlerType)
+		if h == nil {
+			continue
+		}
+		counters := handler.Counters(h)
+		name := string(handlerType)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.CounterValue, float64(counters.BytesWritten), name)
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(counters.Dropped), name)
+		ch <- prometheus.MustNewConstMetric(c.queue, prometheus.GaugeValue, float64(counters.QueueDepth), name)
+	}
+}
@@ -0,0 +1,92 @@
+package promlog
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// failingHandler luôn ghi thất bại.
+type failingHandler struct{}
+
+func (failingHandler) Log(level handler.Level, message string, args ...interface{}) error {
+	return errors.New("sink unavailable")
+}
+
+func (failingHandler) Close() error {
+	return nil
+}
+
+// gather thu thập metrics của collector theo tên và nhãn handler hoặc context/level.
+func gather(t *testing.T, c *Collector) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += " " + label.GetName() + "=" + label.GetValue()
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				values[key] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				values[key] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				values[key] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestCollector(t *testing.T) {
+	config := log.DefaultConfig()
+	config.Console.Enabled = false
+	config.File = log.FileConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "app.log")}
+	m, err := log.New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer m.Close()
+	m.SetErrorHandler(func(log.HandlerType, error) {})
+	m.AddHandler("remote", failingHandler{})
+
+	c := NewCollector(m, WithNamespace("shop"))
+	orders := m.GetLogger("Order")
+	orders.Info("created")
+	orders.Error("failed")
+	m.GetLogger("Payment").Info("charged")
+
+	values := gather(t, c)
+	for key, want := range map[string]float64{
+		"shop_log_records_total context=Order level=info":      1,
+		"shop_log_records_total context=Order level=error":     1,
+		"shop_log_records_total context=Payment level=info":    1,
+		"shop_log_handler_errors_total handler=remote":         3,
+		"shop_log_handler_write_duration_seconds handler=file": 3,
+		"shop_log_handler_queue_depth handler=file":            0,
+		"shop_log_handler_dropped_total handler=remote":        0,
+	} {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("%s = %v (có: %v), want %v", key, got, ok, want)
+		}
+	}
+	if values["shop_log_handler_bytes_written_total handler=file"] == 0 {
+		t.Error("Collector nên báo số byte file handler đã ghi")
+	}
+	if _, ok := values["shop_log_handler_bytes_written_total handler=stack"]; ok {
+		t.Error("Collector không nên báo bộ đếm của stack")
+	}
+}
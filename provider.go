@@ -34,6 +34,8 @@ func NewServiceProvider() di.ServiceProvider {
 //   - Unmarshal log configuration từ key "log"
 //   - Tạo log manager với các handlers dựa trên configuration
 //   - Đăng ký manager trong container DI
+//   - Đặt manager làm manager mặc định của các hàm cấp package (xem SetDefault)
+//   - Theo dõi thay đổi của section "log" khi Config.Watch được bật (xem WatchConfig)
 //
 // Nếu không có config hoặc config không hợp lệ, sử dụng default configuration.
 // Handlers được tạo dựa trên cấu hình: console, file, và stack handlers.
@@ -60,7 +62,7 @@ func (p *ServiceProvider) Register(app di.Application) {
 	logConfig := DefaultConfig()
 
 	// Unmarshal log configuration, nếu lỗi thì panic
-	if err := configManager.UnmarshalKey("log", logConfig); err != nil {
+	if err := configManager.UnmarshalKey(ConfigKey, logConfig); err != nil {
 		panic("failed to unmarshal log config: " + err.Error())
 	}
 
@@ -69,11 +71,24 @@ func (p *ServiceProvider) Register(app di.Application) {
 		panic("invalid log config: " + err.Error())
 	}
 
-	// Tạo log manager mới với config
-	manager := NewManager(logConfig)
+	// Tạo log manager mới với config, nếu lỗi thì panic
+	manager, err := New(logConfig)
+	if err != nil {
+		panic("failed to create log manager: " + err.Error())
+	}
 
 	// Đăng ký log manager trong container
 	c.Instance("log", manager) // Dịch vụ logging chung
+
+	// Các hàm cấp package (log.Info, log.L, ...) ghi qua manager đã cấu hình từ đây
+	SetDefault(manager)
+
+	// Áp dụng lại section "log" mỗi khi config manager báo cấu hình thay đổi
+	if logConfig.Watch {
+		if err := WatchConfig(manager, configManager, ConfigKey); err != nil {
+			panic("failed to watch log config: " + err.Error())
+		}
+	}
 }
 
 // Boot thực hiện thiết lập sau đăng ký cho dịch vụ logging.
@@ -68,6 +68,8 @@ func TestServiceProvider_Register(t *testing.T) {
 
 	manager, ok := managerInstance.(Manager)
 	assert.True(t, ok, "Binding 'log' phải là kiểu Manager, nhưng nhận được %T", managerInstance)
+	t.Cleanup(func() { SetDefault(nil) })
+	assert.Equal(t, manager, Default(), "Register phải đặt manager làm manager mặc định")
 
 	// Kiểm tra handlers được thiết lập đúng
 	// Kiểm tra console handler
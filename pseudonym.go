@@ -0,0 +1,130 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// minPseudonymKeyLen là độ dài tối thiểu (bytes) của khóa HMAC trong PseudonymizationConfig.
+const minPseudonymKeyLen = 16
+
+// PseudonymizationConfig định nghĩa cấu hình thay các field định danh bằng pseudonym với khóa
+// luân phiên (xem handler.Pseudonymizer). Chỉ được đặt một trong Key, KeyEnv và KeyProvider.
+//
+// Để luân phiên khóa (VD: mỗi tháng), đổi KeyID cùng khóa rồi gọi Manager.ApplyConfig; hủy khóa
+// của một kỳ để "quên" dữ liệu người dùng trong log của kỳ đó mà không cần ghi lại log.
+type PseudonymizationConfig struct {
+	// Fields tên các field định danh cần thay, không phân biệt hoa thường
+	// (VD: ["user_id", "email", "ip"])
+	Fields []string `mapstructure:"fields" yaml:"fields" json:"fields"`
+
+	// KeyID định danh của khóa hiện tại, ghi trước mỗi pseudonym (VD: "2024-03")
+	KeyID string `mapstructure:"key_id" yaml:"key_id" json:"key_id"`
+
+	// Key khóa HMAC mã hóa base64, ít nhất 16 bytes. Không nên ghi thẳng vào file cấu hình
+	Key string `mapstructure:"key" yaml:"key" json:"key"`
+
+	// KeyEnv tên biến môi trường chứa khóa HMAC mã hóa base64
+	KeyEnv string `mapstructure:"key_env" yaml:"key_env" json:"key_env"`
+
+	// KeyProvider tên KeyProvider đã đăng ký bằng handler.RegisterKeyProvider (VD: KMS)
+	KeyProvider string `mapstructure:"key_provider" yaml:"key_provider" json:"key_provider"`
+}
+
+// Enabled kiểm tra việc thay định danh có được bật hay không.
+//
+// Trả về:
+//   - bool: true nếu có ít nhất một field định danh
+func (p PseudonymizationConfig) Enabled() bool {
+	return len(p.Fields) > 0
+}
+
+// String trả về mô tả ngắn gọn của cấu hình mà không lộ khóa, VD:
+// "fields=user_id,email key_id=2024-03 key_env=LOG_PSEUDONYM_KEY".
+func (p PseudonymizationConfig) String() string {
+	source := EncryptionConfig{Key: p.Key, KeyEnv: p.KeyEnv, KeyProvider: p.KeyProvider}.String()
+	return strings.TrimSpace("fields=" + strings.Join(p.Fields, ",") + " key_id=" + p.KeyID + " " + source)
+}
+
+// source trả về nguồn khóa HMAC của cấu hình.
+func (p PseudonymizationConfig) source() keySource {
+	return keySource{
+		field: "pseudonymization", description: p.String(), key: p.Key, env: p.KeyEnv, provider: p.KeyProvider,
+		check: func(key []byte) string {
+			if len(key) < minPseudonymKeyLen {
+				return fmt.Sprintf("key must be at least %d bytes", minPseudonymKeyLen)
+			}
+			return ""
+		},
+	}
+}
+
+// newPseudonymizer tạo pseudonymizer dùng chung theo Config.Pseudonymization.
+//
+// Khi không lấy được khóa (VD: KeyProvider lỗi), lỗi được ghi ra stderr và pseudonymizer che
+// giá trị của các field định danh thay vì ghi định danh gốc.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập pseudonym
+//
+// Trả về:
+//   - *handler.Pseudonymizer: pseudonymizer mới, hoặc nil nếu việc thay định danh bị tắt
+func newPseudonymizer(config *Config) *handler.Pseudonymizer {
+	if !config.Pseudonymization.Enabled() {
+		return nil
+	}
+	key, err := config.Pseudonymization.source().load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Lỗi khi lấy khóa pseudonym, các field định danh sẽ bị che: %v\n", err)
+	}
+	return handler.NewPseudonymizer(handler.PseudonymOptions{
+		Fields: config.Pseudonymization.Fields,
+		KeyID:  config.Pseudonymization.KeyID,
+		Key:    key,
+	})
+}
+
+// WithPseudonymizer thay giá trị của các field định danh bằng pseudonym trước khi entry được
+// gửi đến mọi handler, kể cả handler nhận entry chưa được che (xem WithRedactor).
+//
+// Tham số:
+//   - pseudonymizer: *handler.Pseudonymizer - pseudonymizer dùng để thay, nil để tắt
+//
+// Trả về:
+//   - LoggerOption: tùy chọn thay định danh
+//
+// Ví dụ:
+//
+//	pseudonymizer := handler.NewPseudonymizer(handler.PseudonymOptions{
+//	    Fields: []string{"user_id"}, KeyID: "2024-03", Key: key,
+//	})
+//	logger := log.NewLogger("Checkout", log.WithPseudonymizer(pseudonymizer))
+//	logger.Info("Order placed", log.String("user_id", "alice"))
+//	// [Checkout] Order placed user_id=2024-03:9f86d081884c7d65
+func WithPseudonymizer(pseudonymizer *handler.Pseudonymizer) LoggerOption {
+	return func(l *logger) {
+		l.pseudonymizer = pseudonymizer
+	}
+}
+
+// setPseudonymizer thay đổi pseudonymizer của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - pseudonymizer: *handler.Pseudonymizer - pseudonymizer mới, nil để tắt
+func (l *logger) setPseudonymizer(pseudonymizer *handler.Pseudonymizer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pseudonymizer = pseudonymizer
+	l.publish()
+}
+
+// pseudonymizationEqual kiểm tra hai cấu hình pseudonym có giống nhau hay không, để ApplyConfig
+// chỉ lấy lại khóa (VD: gọi KMS) khi cấu hình thay đổi.
+func pseudonymizationEqual(a, b PseudonymizationConfig) bool {
+	return strings.Join(a.Fields, ",") == strings.Join(b.Fields, ",") && a.KeyID == b.KeyID &&
+		a.Key == b.Key && a.KeyEnv == b.KeyEnv && a.KeyProvider == b.KeyProvider
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestManager_Pseudonymization(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.Redaction = RedactionConfig{Fields: []string{"password"}, Exclude: []string{"audit"}}
+	config.Pseudonymization = PseudonymizationConfig{Fields: []string{"user_id"}, KeyID: "2024-03", Key: key}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	checkout := m.GetLogger("Checkout")
+	app, audit := &entryHandler{}, &entryHandler{}
+	checkout.AddHandler(TestHandlerType, app)
+	checkout.AddHandler("audit", audit)
+
+	checkout.Info("Order placed", String("user_id", "alice"))
+	first := app.entry.Fields[0].Str
+	if !strings.HasPrefix(first, "2024-03:") || app.entry.Message != "[Checkout] Order placed user_id="+first {
+		t.Errorf("Định danh nên được thay bằng pseudonym, got %q", app.entry.Message)
+	}
+	if strings.Contains(audit.entry.Message, "alice") {
+		t.Errorf("Handler nhận entry chưa che cũng không được nhận định danh gốc, got %q", audit.entry.Message)
+	}
+
+	// Luân phiên khóa qua ApplyConfig
+	updated := *config
+	updated.Pseudonymization.KeyID = "2024-04"
+	updated.Pseudonymization.Key = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210"))
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "pseudonymization") || strings.Contains(diff.String(), updated.Pseudonymization.Key) {
+		t.Errorf("Diff nên liệt kê pseudonymization mà không lộ khóa, got %q", diff.String())
+	}
+	checkout.Info("Order placed", String("user_id", "alice"))
+	if second := app.entry.Fields[0].Str; second == first || !strings.HasPrefix(second, "2024-04:") {
+		t.Errorf("Pseudonym sau khi luân phiên khóa nên dùng khóa mới, got %q", second)
+	}
+}
+
+func TestManager_Pseudonymization_ProviderFailure(t *testing.T) {
+	handler.RegisterKeyProvider("test-failing-kms", func() ([]byte, error) { return nil, errors.New("kms unavailable") })
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.Pseudonymization = PseudonymizationConfig{Fields: []string{"user_id"}, KeyProvider: "test-failing-kms"}
+	m := NewManager(config)
+	defer m.Close()
+
+	logger := m.GetLogger("Checkout")
+	app := &entryHandler{}
+	logger.AddHandler(TestHandlerType, app)
+	logger.Info("Order placed", String("user_id", "alice"))
+	if got := app.entry.Fields[0].Str; got != handler.DefaultRedactMask {
+		t.Errorf("Khi không lấy được khóa, định danh nên bị che, got %q", got)
+	}
+}
+
+func TestConfig_ValidatePseudonymization(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PseudonymizationConfig
+		field  string
+	}{
+		{"missing key", PseudonymizationConfig{Fields: []string{"user_id"}}, "pseudonymization.key"},
+		{"short key", PseudonymizationConfig{Fields: []string{"user_id"}, Key: "c2hvcnQ="}, "pseudonymization.key"},
+		{"unknown provider", PseudonymizationConfig{Fields: []string{"user_id"}, KeyProvider: "missing"}, "pseudonymization.key_provider"},
+		{"several sources", PseudonymizationConfig{Fields: []string{"user_id"}, Key: "c2hvcnQ=", KeyEnv: "X"}, "pseudonymization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Pseudonymization = tt.config
+			var configErr *ConfigError
+			if err := config.Validate(); !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("Validate() error = %v, want field %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Package reader đọc lại các log entry đã được ghi bởi file handler và phát lại chúng qua một handler.
+//
+// Reader phân tích định dạng "2006/01/02 15:04:05 [LEVEL] message" của FileHandler và
+// ConsoleHandler (không màu). Các dòng không bắt đầu bằng timestamp được xem là phần tiếp theo
+// của thông điệp trước đó, nhờ đó các thông điệp nhiều dòng (VD: stack trace) được giữ nguyên.
+//
+// Replay gửi các entry đã đọc đến một handler với timestamp gốc thông qua handler.Dispatch,
+// phù hợp để backfill một hệ thống lưu trữ log mới từ các file log đã lưu trữ.
+//
+// Ví dụ:
+//
+//	r, err := reader.Open("storage/logs/app.log.20240101120000")
+//	if err != nil {
+//	    return err
+//	}
+//	defer r.Close()
+//
+//	n, err := reader.Replay(ctx, r, elasticHandler)
+package reader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// TimeLayout là định dạng timestamp được ghi bởi FileHandler và ConsoleHandler.
+const TimeLayout = handler.TimeLayout
+
+// maxLineSize là kích thước tối đa của một dòng log có thể đọc.
+const maxLineSize = 1024 * 1024
+
+// ParseError mô tả một dòng không thể phân tích thành log entry.
+type ParseError struct {
+	Line int    // Số thứ tự dòng (bắt đầu từ 1)
+	Text string // Nội dung dòng
+	Err  error  // Lỗi phân tích
+}
+
+// Error trả về mô tả lỗi phân tích.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap trả về lỗi phân tích gốc.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Option cấu hình một Reader.
+type Option func(*Reader)
+
+// WithLocation thiết lập múi giờ dùng để diễn giải timestamp.
+//
+// FileHandler ghi timestamp theo giờ địa phương mà không kèm múi giờ; option này cần thiết
+// khi đọc file log được ghi trên máy có múi giờ khác.
+//
+// Tham số:
+//   - loc: *time.Location - múi giờ của timestamp trong file (mặc định time.Local)
+//
+// Trả về:
+//   - Option: option cấu hình Reader
+func WithLocation(loc *time.Location) Option {
+	return func(r *Reader) {
+		if loc != nil {
+			r.loc = loc
+		}
+	}
+}
+
+// WithDecryption giải mã file log do FileHandler ghi với SetEncryption (xem
+// handler.NewDecryptReader). Dòng không giải mã được (sai khóa hoặc dòng bị sửa) làm Next trả về
+// lỗi bọc handler.ErrDecrypt.
+//
+// Tham số:
+//   - key: []byte - khóa AES 16, 24 hoặc 32 byte đã dùng để mã hóa
+//
+// Trả về:
+//   - Option: option cấu hình Reader
+//
+// Ví dụ:
+//
+//	r, err := reader.Open("storage/logs/audit.log.20240101120000.gz", reader.WithDecryption(key))
+func WithDecryption(key []byte) Option {
+	return func(r *Reader) {
+		r.key = key
+	}
+}
+
+// WithPlaintext cho phép file đọc với WithDecryption chứa dòng không mã hóa (VD: được ghi trước
+// khi bật mã hóa), xem handler.DecryptOptions.AllowPlaintext. Mặc định dòng không mã hóa làm Next
+// trả về lỗi bọc handler.ErrDecrypt.
+//
+// Trả về:
+//   - Option: option cấu hình Reader
+func WithPlaintext() Option {
+	return func(r *Reader) {
+		r.plaintext = true
+	}
+}
+
+// Reader đọc tuần tự các log entry từ một io.Reader.
+//
+// Reader không an toàn khi được sử dụng đồng thời từ nhiều goroutine.
+type Reader struct {
+	scanner   *bufio.Scanner
+	closer    io.Closer
+	loc       *time.Location
+	key       []byte // Khóa giải mã (nil = file không mã hóa)
+	plaintext bool   // Chấp nhận dòng không mã hóa khi giải mã
+	line      int
+	pending   *handler.Entry // Entry đã đọc header nhưng chưa trả về
+}
+
+// New tạo Reader đọc log entry từ r.
+//
+// Tham số:
+//   - r: io.Reader - nguồn dữ liệu log
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - *Reader: reader đã được khởi tạo
+func New(r io.Reader, opts ...Option) *Reader {
+	reader := &Reader{
+		loc: time.Local,
+	}
+	for _, opt := range opts {
+		opt(reader)
+	}
+
+	if reader.key != nil {
+		decrypted, err := handler.NewDecryptReader(r, reader.key, handler.DecryptOptions{AllowPlaintext: reader.plaintext})
+		if err != nil {
+			decrypted = errReader{err}
+		}
+		r = decrypted
+	}
+	reader.scanner = bufio.NewScanner(r)
+	reader.scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return reader
+}
+
+// errReader là io.Reader luôn trả về err, để New báo lỗi khóa giải mã qua Next.
+type errReader struct {
+	err error
+}
+
+// Read trả về lỗi đã lưu.
+func (e errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// Open mở một file log để đọc. File sao lưu đã được nén (VD: "app.log.20240101120000.gz")
+// được giải nén tự động theo codec có phần mở rộng tương ứng (xem handler.RegisterCodec).
+//
+// Tham số:
+//   - path: string - đường dẫn đến file log
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - *Reader: reader đọc file, cần được đóng bằng Close
+//   - error: lỗi nếu không thể mở file
+func Open(path string, opts ...Option) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	codec := handler.CodecForPath(path)
+	if codec == nil {
+		r := New(file, opts...)
+		r.closer = file
+		return r, nil
+	}
+
+	decompressed, err := codec.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open %s log file: %w", codec.Name(), err)
+	}
+	r := New(decompressed, opts...)
+	r.closer = closers{decompressed, file}
+	return r, nil
+}
+
+// closers đóng lần lượt nhiều io.Closer và trả về lỗi đầu tiên.
+type closers []io.Closer
+
+// Close đóng tất cả các closer.
+func (c closers) Close() error {
+	var first error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Next đọc log entry tiếp theo.
+//
+// Trả về:
+//   - *handler.Entry: entry tiếp theo
+//   - error: io.EOF khi hết dữ liệu, *ParseError nếu gặp dòng không hợp lệ trước entry đầu tiên,
+//     hoặc lỗi đọc dữ liệu
+func (r *Reader) Next() (*handler.Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := r.scanner.Text()
+
+		entry, err := r.parseHeader(text)
+		if err != nil {
+			// Dòng không có header là phần tiếp theo của thông điệp trước đó
+			if r.pending != nil {
+				r.pending.Message += "\n" + text
+				continue
+			}
+			return nil, &ParseError{Line: r.line, Text: text, Err: err}
+		}
+
+		if r.pending != nil {
+			prev := r.pending
+			r.pending = entry
+			return prev, nil
+		}
+		r.pending = entry
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	if r.pending != nil {
+		entry := r.pending
+		r.pending = nil
+		return entry, nil
+	}
+	return nil, io.EOF
+}
+
+// Close đóng file nguồn nếu Reader được tạo bởi Open.
+//
+// Trả về:
+//   - error: lỗi khi đóng file
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// parseHeader phân tích một dòng có dạng "2006/01/02 15:04:05 [LEVEL] message".
+func (r *Reader) parseHeader(text string) (*handler.Entry, error) {
+	return handler.ParseLine(text, r.loc)
+}
+
+// Replay đọc tất cả entry từ r và phát lại chúng qua h với timestamp gốc.
+//
+// Entry được gửi qua handler.Dispatch, nên timestamp gốc chỉ được giữ nguyên với các handler
+// triển khai handler.EntryHandler. Replay dừng ở lỗi đầu tiên của reader hoặc handler, hoặc
+// khi ctx bị hủy.
+//
+// Tham số:
+//   - ctx: context.Context - context để hủy quá trình phát lại
+//   - r: *Reader - nguồn log entry
+//   - h: handler.Handler - handler nhận các entry
+//
+// Trả về:
+//   - int: số entry đã phát lại thành công
+//   - error: lỗi đầu tiên gặp phải, hoặc nil khi đã đọc hết dữ liệu
+//
+// Ví dụ:
+//
+//	n, err := reader.Replay(ctx, r, handler.NewStackHandler(elasticHandler, archiveHandler))
+//	fmt.Printf("Đã backfill %d entry\n", n)
+func Replay(ctx context.Context, r *Reader, h handler.Handler) (int, error) {
+	count := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		entry, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		if err := handler.Dispatch(h, entry); err != nil {
+			return count, fmt.Errorf("failed to replay entry at %s: %w", entry.Time.Format(TimeLayout), err)
+		}
+		count++
+	}
+}
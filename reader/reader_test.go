@@ -0,0 +1,227 @@
+package reader
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// entryRecorder ghi lại các entry được phát lại
+type entryRecorder struct {
+	entries []*handler.Entry
+	failAt  int
+}
+
+func (e *entryRecorder) Log(level handler.Level, message string, args ...interface{}) error {
+	return e.LogEntry(&handler.Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+func (e *entryRecorder) LogEntry(entry *handler.Entry) error {
+	if e.failAt > 0 && len(e.entries)+1 == e.failAt {
+		return errors.New("sink unavailable")
+	}
+	e.entries = append(e.entries, entry)
+	return nil
+}
+
+func (e *entryRecorder) Close() error {
+	return nil
+}
+
+const sample = `2024/01/02 03:04:05 [INFO] [UserService] User logged in user_id=42
+2024/01/02 03:04:06 [ERROR] [PaymentService] Charge failed
+goroutine 1 [running]:
+main.main()
+2024/01/02 03:04:07 [DEBUG] done
+`
+
+func TestReader_Next(t *testing.T) {
+	r := New(strings.NewReader(sample), WithLocation(time.UTC))
+
+	var entries []*handler.Entry
+	for {
+		entry, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Reader nên đọc 3 entry, got %d", len(entries))
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !entries[0].Time.Equal(want) {
+		t.Errorf("Timestamp sai, got %v, want %v", entries[0].Time, want)
+	}
+	if entries[0].Level != handler.InfoLevel || entries[0].Message != "[UserService] User logged in user_id=42" {
+		t.Errorf("Entry đầu tiên phân tích sai, got %v %q", entries[0].Level, entries[0].Message)
+	}
+	if want := "[PaymentService] Charge failed\ngoroutine 1 [running]:\nmain.main()"; entries[1].Message != want {
+		t.Errorf("Thông điệp nhiều dòng nên được giữ nguyên, got %q", entries[1].Message)
+	}
+	if entries[2].Level != handler.DebugLevel {
+		t.Errorf("Entry cuối cùng phân tích sai level, got %v", entries[2].Level)
+	}
+}
+
+func TestReader_Next_ParseError(t *testing.T) {
+	r := New(strings.NewReader("not a log line\n"))
+
+	_, err := r.Next()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 1 {
+		t.Fatalf("Next() nên trả về ParseError ở dòng 1, got %v", err)
+	}
+}
+
+func TestReplay_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	fh, err := handler.NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileHandler() error = %v", err)
+	}
+	at := time.Date(2023, 6, 7, 8, 9, 10, 0, time.Local)
+	fh.LogEntry(&handler.Entry{Time: at, Level: handler.WarningLevel, Message: "archived"})
+	fh.Close()
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer r.Close()
+
+	sink := &entryRecorder{}
+	n, err := Replay(context.Background(), r, sink)
+	if err != nil || n != 1 {
+		t.Fatalf("Replay() = %d, %v; want 1, nil", n, err)
+	}
+	if !sink.entries[0].Time.Equal(at) || sink.entries[0].Message != "archived" {
+		t.Errorf("Replay() nên giữ timestamp gốc, got %v %q", sink.entries[0].Time, sink.entries[0].Message)
+	}
+}
+
+func TestReplay_StopsOnError(t *testing.T) {
+	sink := &entryRecorder{failAt: 2}
+	n, err := Replay(context.Background(), New(strings.NewReader(sample)), sink)
+	if err == nil || n != 1 {
+		t.Errorf("Replay() nên dừng ở lỗi đầu tiên của handler, got %d, %v", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n, err := Replay(ctx, New(strings.NewReader(sample)), &entryRecorder{}); !errors.Is(err, context.Canceled) || n != 0 {
+		t.Errorf("Replay() nên dừng khi context bị hủy, got %d, %v", n, err)
+	}
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.log")); err == nil {
+		t.Error("Open() nên trả về lỗi với file không tồn tại")
+	}
+}
+
+func TestOpen_Compressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.20240102030407.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(file)
+	_, _ = w.Write([]byte(sample))
+	_ = w.Close()
+	_ = file.Close()
+
+	r, err := Open(path, WithLocation(time.UTC))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer r.Close()
+
+	n := 0
+	for {
+		if _, err := r.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("Open() nên giải nén file gzip, got %d entries", n)
+	}
+}
+
+func TestOpen_WithDecryption(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "secret.log")
+	h, err := handler.NewFileHandler(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetEncryption(key); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Log(handler.InfoLevel, "[Patient] record viewed")
+	_ = h.Log(handler.ErrorLevel, "panic: boom\ngoroutine 1 [running]:")
+	_ = h.Close()
+
+	r, err := Open(path, WithDecryption(key))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer r.Close()
+	var messages []string
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		messages = append(messages, entry.Message)
+	}
+	if len(messages) != 2 || messages[0] != "[Patient] record viewed" || messages[1] != "panic: boom\ngoroutine 1 [running]:" {
+		t.Errorf("WithDecryption() nên giải mã các entry, got %q", messages)
+	}
+
+	wrong, err := Open(path, WithDecryption([]byte("fedcba9876543210")))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer wrong.Close()
+	if _, err := wrong.Next(); !errors.Is(err, handler.ErrDecrypt) {
+		t.Errorf("Next() với khóa sai nên trả về ErrDecrypt, got %v", err)
+	}
+
+	plain := filepath.Join(t.TempDir(), "plain.log")
+	if err := os.WriteFile(plain, []byte("2024/03/01 12:00:00 [INFO] written before encryption\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	strict, err := Open(plain, WithDecryption(key))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer strict.Close()
+	if _, err := strict.Next(); !errors.Is(err, handler.ErrDecrypt) {
+		t.Errorf("Next() nên từ chối dòng không mã hóa, got %v", err)
+	}
+	lenient, err := Open(plain, WithDecryption(key), WithPlaintext())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer lenient.Close()
+	if entry, err := lenient.Next(); err != nil || entry.Message != "written before encryption" {
+		t.Errorf("WithPlaintext() nên giữ nguyên dòng không mã hóa, got %v, %v", entry, err)
+	}
+}
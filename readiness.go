@@ -0,0 +1,108 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"go.fork.vn/log/handler"
+)
+
+// DefaultMaxQueueSaturation là tỷ lệ lấp đầy hàng đợi bất đồng bộ tối đa khi
+// ReadinessConfig.MaxQueueSaturation bằng 0.
+const DefaultMaxQueueSaturation = 0.9
+
+// ReadinessConfig định nghĩa cách Manager.Readiness đánh giá tình trạng của hệ thống log.
+type ReadinessConfig struct {
+	// Enabled cho phép lỗi ghi log làm thất bại kiểm tra readiness. false = Readiness luôn trả về nil
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+
+	// MaxQueueSaturation tỷ lệ lấp đầy tối đa (0-1) của hàng đợi bất đồng bộ trước khi handler
+	// được xem là quá tải. 0 = mặc định (DefaultMaxQueueSaturation)
+	MaxQueueSaturation float64 `mapstructure:"max_queue_saturation" yaml:"max_queue_saturation" json:"max_queue_saturation"`
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "enabled=true max_queue_saturation=0.9".
+func (r ReadinessConfig) String() string {
+	return "enabled=" + strconv.FormatBool(r.Enabled) + " max_queue_saturation=" + strconv.FormatFloat(r.MaxQueueSaturation, 'g', -1, 64)
+}
+
+// validate kiểm tra ngưỡng lấp đầy hàng đợi.
+func (r ReadinessConfig) validate() error {
+	if r.MaxQueueSaturation < 0 || r.MaxQueueSaturation > 1 {
+		return &ConfigError{
+			Field:   "readiness.max_queue_saturation",
+			Value:   strconv.FormatFloat(r.MaxQueueSaturation, 'g', -1, 64),
+			Message: "max_queue_saturation must be between 0 and 1 (0 for default)",
+		}
+	}
+	return nil
+}
+
+// Readiness kiểm tra hệ thống log có đang ghi log bình thường hay không, để gắn vào endpoint
+// readiness của ứng dụng (VD: health check HTTP của Fork framework).
+//
+// Các kiểm tra gồm tình trạng của mọi handler (xem handler.HealthChecker, VD: file log đã đóng,
+// lần ghi gần nhất thất bại, sink từ xa đang spill), độ lấp đầy hàng đợi bất đồng bộ so với
+// Config.Readiness.MaxQueueSaturation, và các service đã đăng ký triển khai Health() error.
+// Khi Config.Readiness.Enabled là false, Readiness luôn trả về nil. Method này là thread-safe.
+//
+// Trả về:
+//   - error: tổng hợp các vấn đề theo tên handler/service, nil nếu hệ thống log sẵn sàng
+//
+// Ví dụ:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//	    if err := manager.Readiness(); err != nil {
+//	        http.Error(w, err.Error(), http.StatusServiceUnavailable)
+//	        return
+//	    }
+//	    w.WriteHeader(http.StatusOK)
+//	})
+func (m *manager) Readiness() error {
+	m.mu.RLock()
+	config := m.config.Readiness
+	handlers := make(map[HandlerType]handler.Handler, len(m.handlers))
+	for handlerType, h := range m.handlers {
+		handlers[handlerType] = h
+	}
+	services := append([]namedService(nil), m.services...)
+	m.mu.RUnlock()
+
+	if !config.Enabled {
+		return nil
+	}
+	threshold := config.MaxQueueSaturation
+	if threshold == 0 {
+		threshold = DefaultMaxQueueSaturation
+	}
+
+	types := make([]HandlerType, 0, len(handlers))
+	for handlerType := range handlers {
+		// Các handler con của stack đã được đăng ký riêng với manager
+		if handlerType != HandlerTypeStack {
+			types = append(types, handlerType)
+		}
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for _, handlerType := range types {
+		h := handlers[handlerType]
+		if err := handler.Health(h); err != nil {
+			errs = append(errs, fmt.Errorf("handler %s: %w", handlerType, err))
+		}
+		if saturation := handler.Saturation(h); saturation >= threshold {
+			errs = append(errs, fmt.Errorf("handler %s: queue %.0f%% full", handlerType, saturation*100))
+		}
+	}
+	for _, s := range services {
+		if checker, ok := s.service.(interface{ Health() error }); ok {
+			if err := checker.Health(); err != nil {
+				errs = append(errs, fmt.Errorf("service %s: %w", s.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
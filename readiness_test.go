@@ -0,0 +1,58 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type healthService struct {
+	err error
+}
+
+func (s *healthService) Start(ctx context.Context) error { return nil }
+func (s *healthService) Stop(ctx context.Context) error  { return nil }
+func (s *healthService) Health() error                   { return s.err }
+
+func TestManager_Readiness(t *testing.T) {
+	config := createTestConfig()
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	m := NewManager(config)
+	defer m.Close()
+
+	if err := m.Readiness(); err != nil {
+		t.Errorf("Readiness() nên trả về nil khi chưa bật, got %v", err)
+	}
+
+	config.Readiness = ReadinessConfig{Enabled: true}
+	if _, err := m.ApplyConfig(config, false); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if err := m.Readiness(); err != nil {
+		t.Errorf("Manager đang hoạt động nên sẵn sàng, got %v", err)
+	}
+
+	// File bị đóng ngoài ý muốn làm thất bại readiness
+	_ = m.GetHandler(HandlerTypeFile).Close()
+	if err := m.Readiness(); err == nil || !strings.Contains(err.Error(), "handler file") {
+		t.Errorf("Readiness() nên báo file handler đã đóng, got %v", err)
+	}
+
+	guard := &healthService{err: errors.New("disk almost full")}
+	if err := m.AddService("disk", guard); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := m.Readiness(); err == nil || !strings.Contains(err.Error(), "service disk: disk almost full") {
+		t.Errorf("Readiness() nên gồm lỗi của service, got %v", err)
+	}
+}
+
+func TestReadinessConfig_Validate(t *testing.T) {
+	config := createTestConfig()
+	config.Readiness.MaxQueueSaturation = 1.5
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "readiness.max_queue_saturation") {
+		t.Errorf("Validate() nên từ chối max_queue_saturation > 1, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package log
+
+import (
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// RedactionConfig định nghĩa cấu hình che dữ liệu nhạy cảm (xem handler.Redactor).
+type RedactionConfig struct {
+	// Fields tên các field có giá trị luôn bị che, không phân biệt hoa thường
+	// (VD: ["password", "token", "authorization"])
+	Fields []string `mapstructure:"fields" yaml:"fields" json:"fields"`
+
+	// Patterns các mẫu dữ liệu nhạy cảm cần che trong thông điệp và giá trị field: tên mẫu có
+	// sẵn ("credit_card", "email", "jwt") hoặc biểu thức chính quy
+	Patterns []string `mapstructure:"patterns" yaml:"patterns" json:"patterns"`
+
+	// Mask chuỗi thay thế dữ liệu bị che. Rỗng = mặc định (handler.DefaultRedactMask)
+	Mask string `mapstructure:"mask" yaml:"mask" json:"mask"`
+
+	// Exclude tên các handler nhận entry chưa được che (VD: sink kiểm toán bảo mật)
+	Exclude []string `mapstructure:"exclude" yaml:"exclude" json:"exclude"`
+}
+
+// Enabled kiểm tra việc che dữ liệu có được bật hay không.
+//
+// Trả về:
+//   - bool: true nếu có ít nhất một field hoặc mẫu cần che
+func (r RedactionConfig) Enabled() bool {
+	return len(r.Fields) > 0 || len(r.Patterns) > 0
+}
+
+// String trả về mô tả ngắn gọn của cấu hình, VD: "fields=password,token patterns=email mask= exclude=audit".
+func (r RedactionConfig) String() string {
+	return "fields=" + strings.Join(r.Fields, ",") + " patterns=" + strings.Join(r.Patterns, ",") +
+		" mask=" + r.Mask + " exclude=" + strings.Join(r.Exclude, ",")
+}
+
+// options chuyển cấu hình thành handler.RedactOptions.
+//
+// Trả về:
+//   - handler.RedactOptions: tùy chọn của Redactor
+//   - error: lỗi nếu một mẫu không phải biểu thức chính quy hợp lệ
+func (r RedactionConfig) options() (handler.RedactOptions, error) {
+	opts := handler.RedactOptions{Fields: r.Fields, Mask: r.Mask}
+	for _, expr := range r.Patterns {
+		pattern, err := handler.ParseRedactPattern(expr)
+		if err != nil {
+			return handler.RedactOptions{}, err
+		}
+		opts.Patterns = append(opts.Patterns, pattern)
+	}
+	return opts, nil
+}
+
+// excluded trả về các handler nhận entry chưa được che.
+func (r RedactionConfig) excluded() []HandlerType {
+	types := make([]HandlerType, len(r.Exclude))
+	for i, name := range r.Exclude {
+		types[i] = HandlerType(name)
+	}
+	return types
+}
+
+// newRedactor tạo redactor dùng chung theo Config.Redaction.
+//
+// Tham số:
+//   - config: *Config - cấu hình chứa thiết lập che dữ liệu
+//
+// Trả về:
+//   - *handler.Redactor: redactor mới, hoặc nil nếu việc che dữ liệu bị tắt hoặc cấu hình
+//     không hợp lệ (đã bị Validate từ chối)
+func newRedactor(config *Config) *handler.Redactor {
+	if !config.Redaction.Enabled() {
+		return nil
+	}
+	opts, err := config.Redaction.options()
+	if err != nil {
+		return nil
+	}
+	return handler.NewRedactor(opts)
+}
+
+// WithRedactor che dữ liệu nhạy cảm trong thông điệp và field trước khi entry được gửi đến
+// handler, trừ các handler trong exclude.
+//
+// Entry gửi đến handler trong exclude giữ nguyên dữ liệu gốc, phù hợp cho sink kiểm toán bảo
+// mật. Handler con của một stack nhận entry giống như stack.
+//
+// Tham số:
+//   - redactor: *handler.Redactor - redactor dùng để che, nil để tắt
+//   - exclude: ...HandlerType - các handler nhận entry chưa được che
+//
+// Trả về:
+//   - LoggerOption: tùy chọn che dữ liệu
+//
+// Ví dụ:
+//
+//	redactor := handler.NewRedactor(handler.RedactOptions{
+//	    Fields:   []string{"password", "token"},
+//	    Patterns: []handler.RedactPattern{handler.RedactEmail},
+//	})
+//	logger := log.NewLogger("Auth", log.WithRedactor(redactor, "audit"))
+func WithRedactor(redactor *handler.Redactor, exclude ...HandlerType) LoggerOption {
+	return func(l *logger) {
+		l.redactor, l.unredacted = redactor, unredactedSet(exclude)
+	}
+}
+
+// setRedactor thay đổi redactor của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - redactor: *handler.Redactor - redactor mới, nil để tắt
+//   - exclude: []HandlerType - các handler nhận entry chưa được che
+func (l *logger) setRedactor(redactor *handler.Redactor, exclude []HandlerType) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.redactor, l.unredacted = redactor, unredactedSet(exclude)
+	l.publish()
+}
+
+// unredactedSet chuyển danh sách handler thành tập để tra cứu khi ghi log.
+func unredactedSet(types []HandlerType) map[HandlerType]bool {
+	if len(types) == 0 {
+		return nil
+	}
+	set := make(map[HandlerType]bool, len(types))
+	for _, handlerType := range types {
+		set[handlerType] = true
+	}
+	return set
+}
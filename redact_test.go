@@ -0,0 +1,61 @@
+package log
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_Redaction(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.Redaction = RedactionConfig{
+		Fields:   []string{"password"},
+		Patterns: []string{"email"},
+		Exclude:  []string{"audit"},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	auth := m.GetLogger("Auth")
+	app, audit := &entryHandler{}, &entryHandler{}
+	auth.AddHandler(TestHandlerType, app)
+	auth.AddHandler("audit", audit)
+
+	auth.Info("login %s", "alice@example.com", String("password", "hunter2"))
+	if got := app.entry.Message; got != "[Auth] login [REDACTED] password=[REDACTED]" {
+		t.Errorf("Dữ liệu nhạy cảm nên được che trước khi đến handler, got %q", got)
+	}
+	if got := audit.entry.Message; got != "[Auth] login alice@example.com password=hunter2" {
+		t.Errorf("Handler trong exclude nên nhận entry chưa che, got %q", got)
+	}
+	if audit.entry.Fields[0].Str != "hunter2" {
+		t.Errorf("Field gửi đến handler trong exclude không nên bị che, got %+v", audit.entry.Fields)
+	}
+
+	updated := *config
+	updated.Redaction = RedactionConfig{}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "redaction") {
+		t.Errorf("Diff nên liệt kê thay đổi của redaction, got %q", diff.String())
+	}
+	auth.Info("login password=hunter2")
+	if got := app.entry.Message; got != "[Auth] login password=hunter2" {
+		t.Errorf("Tắt redaction nên áp dụng cho logger đã tồn tại, got %q", got)
+	}
+
+	invalid := *createTestConfig()
+	invalid.Redaction.Patterns = []string{"[a-"}
+	err = invalid.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "redaction.patterns" {
+		t.Errorf("Validate() nên từ chối mẫu không hợp lệ, got %v", err)
+	}
+}
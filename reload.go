@@ -0,0 +1,132 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ConfigKey là key của section cấu hình log mà ServiceProvider đọc từ config manager.
+const ConfigKey = "log"
+
+// ConfigSource là nguồn cấu hình có thể giải mã một section thành struct, VD: config.Manager
+// của go.fork.vn/config.
+type ConfigSource interface {
+	// UnmarshalKey giải mã section key vào out.
+	//
+	// Tham số:
+	//   - key: string - key của section cấu hình
+	//   - out: interface{} - con trỏ đến struct nhận giá trị
+	//
+	// Trả về:
+	//   - error: lỗi nếu không thể giải mã
+	UnmarshalKey(key string, out interface{}) error
+}
+
+// ReloadConfig đọc lại section key từ source (trên nền DefaultConfig, giống ServiceProvider) và
+// áp dụng cho m qua ApplyConfig: handler thay đổi được tạo lại và các logger đang tồn tại được
+// cập nhật trong một lần giữ khóa của manager. Cấu hình không hợp lệ bị từ chối và manager giữ
+// nguyên cấu hình hiện tại.
+//
+// Tham số:
+//   - m: Manager - manager cần cập nhật
+//   - source: ConfigSource - nguồn cấu hình
+//   - key: string - key của section cấu hình, rỗng để dùng ConfigKey
+//
+// Trả về:
+//   - *ConfigDiff: các thay đổi đã áp dụng
+//   - error: lỗi nếu không đọc được, cấu hình không hợp lệ hoặc không áp dụng được
+//
+// Ví dụ:
+//
+//	diff, err := log.ReloadConfig(manager, configManager, "log")
+//	if err == nil && diff.HasChanges() {
+//	    fmt.Println(diff)
+//	}
+func ReloadConfig(m Manager, source ConfigSource, key string) (*ConfigDiff, error) {
+	if key == "" {
+		key = ConfigKey
+	}
+	config := DefaultConfig()
+	if err := source.UnmarshalKey(key, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal log config: %w", err)
+	}
+	return m.ApplyConfig(config, false)
+}
+
+// WatchConfig đăng ký với source để gọi ReloadConfig mỗi khi cấu hình thay đổi, rồi bật theo dõi
+// file cấu hình nếu source hỗ trợ. source phải có method OnConfigChange nhận một callback (VD:
+// config.Manager của go.fork.vn/config, callback nhận fsnotify.Event); method WatchConfig() của
+// source, nếu có, được gọi sau khi đăng ký.
+//
+// Mỗi lần tải lại có thay đổi được ghi bản ghi kiểm toán "log config reloaded" (với tên các trường
+// thay đổi và số handler được tạo lại) qua logger của AuditContext; lỗi khi tải lại được ghi ra
+// stderr và manager giữ nguyên cấu hình cũ. ServiceProvider gọi WatchConfig khi Config.Watch được
+// bật.
+//
+// Tham số:
+//   - m: Manager - manager cần cập nhật
+//   - source: ConfigSource - nguồn cấu hình có OnConfigChange
+//   - key: string - key của section cấu hình, rỗng để dùng ConfigKey
+//
+// Trả về:
+//   - error: lỗi nếu source không hỗ trợ thông báo thay đổi
+//
+// Ví dụ:
+//
+//	if err := log.WatchConfig(manager, configManager, "log"); err != nil {
+//	    return err
+//	}
+func WatchConfig(m Manager, source ConfigSource, key string) error {
+	if m == nil || source == nil {
+		return errors.New("manager and config source cannot be nil")
+	}
+	reload := func() {
+		diff, err := ReloadConfig(m, source, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Lỗi khi tải lại cấu hình log: %v\n", err)
+			return
+		}
+		if !diff.HasChanges() {
+			return
+		}
+		fields := make([]string, 0, len(diff.Fields))
+		for _, f := range diff.Fields {
+			fields = append(fields, f.Field)
+		}
+		if l, ok := m.GetLogger(AuditContext).(*logger); ok {
+			l.audit("log config reloaded", Any("fields", fields), Any("handlers", len(diff.Handlers)))
+		}
+	}
+	if !onConfigChange(source, reload) {
+		return fmt.Errorf("config source %T does not support change notifications", source)
+	}
+	if w, ok := source.(interface{ WatchConfig() }); ok {
+		w.WatchConfig()
+	}
+	return nil
+}
+
+// onConfigChange đăng ký fn qua method OnConfigChange(func(T)) của source, với T bất kỳ.
+// Callback được dựng bằng reflect để package không phụ thuộc trực tiếp vào kiểu sự kiện của
+// config manager (fsnotify.Event).
+//
+// Trả về:
+//   - bool: false nếu source không có method OnConfigChange với chữ ký phù hợp
+func onConfigChange(source interface{}, fn func()) bool {
+	method := reflect.ValueOf(source).MethodByName("OnConfigChange")
+	if !method.IsValid() {
+		return false
+	}
+	t := method.Type()
+	if t.NumIn() != 1 || t.NumOut() != 0 || t.In(0).Kind() != reflect.Func || t.In(0).NumOut() != 0 {
+		return false
+	}
+	callback := reflect.MakeFunc(t.In(0), func([]reflect.Value) []reflect.Value {
+		fn()
+		return nil
+	})
+	method.Call([]reflect.Value{callback})
+	return true
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+// fakeConfigEvent giống fsnotify.Event mà config manager truyền cho callback.
+type fakeConfigEvent struct {
+	Name string
+}
+
+// fakeConfigSource là config manager giả trả về bản sao của config và ghi nhận callback.
+type fakeConfigSource struct {
+	config   Config
+	err      error
+	onChange func(fakeConfigEvent)
+	watching bool
+}
+
+func (f *fakeConfigSource) UnmarshalKey(key string, out interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	if key != ConfigKey {
+		return errors.New("unexpected key " + key)
+	}
+	*out.(*Config) = f.config
+	return nil
+}
+
+func (f *fakeConfigSource) OnConfigChange(run func(fakeConfigEvent)) { f.onChange = run }
+func (f *fakeConfigSource) WatchConfig()                             { f.watching = true }
+
+func TestReloadConfig(t *testing.T) {
+	config := createTestConfig()
+	m := NewManager(config)
+	defer m.Close()
+	order := m.GetLogger("Order").(*logger)
+
+	source := &fakeConfigSource{config: *config}
+	source.config.Level = handler.DebugLevel
+	diff, err := ReloadConfig(m, source, "")
+	if err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "level") || order.getMinLevel() != handler.DebugLevel {
+		t.Errorf("ReloadConfig() nên áp dụng cấp độ mới cho logger đã tạo, got %v / %q", order.getMinLevel(), diff)
+	}
+
+	source.config.Level = handler.Level(42)
+	if _, err := ReloadConfig(m, source, ""); err == nil || order.getMinLevel() != handler.DebugLevel {
+		t.Errorf("ReloadConfig() nên từ chối cấu hình không hợp lệ và giữ cấu hình cũ, got %v", err)
+	}
+	source.err = errors.New("parse error")
+	if _, err := ReloadConfig(m, source, ""); err == nil {
+		t.Error("ReloadConfig() nên trả về lỗi khi không đọc được cấu hình")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	config := createTestConfig()
+	m := NewManager(config)
+	defer m.Close()
+	mem := handler.NewMemoryHandler()
+	m.AddHandler("memory", mem)
+
+	source := &fakeConfigSource{config: *config}
+	if err := WatchConfig(m, source, ConfigKey); err != nil {
+		t.Fatalf("WatchConfig() error = %v", err)
+	}
+	if source.onChange == nil || !source.watching {
+		t.Fatal("WatchConfig() nên đăng ký OnConfigChange và bật WatchConfig")
+	}
+
+	source.config.Level = handler.WarningLevel
+	source.onChange(fakeConfigEvent{Name: "config/app.yaml"})
+	if m.GetLogger("Order").(*logger).getMinLevel() != handler.WarningLevel {
+		t.Error("Thay đổi cấu hình nên được áp dụng cho logger")
+	}
+	if !mem.Contains("log config reloaded") {
+		t.Errorf("Lần tải lại có thay đổi nên được ghi lại, got %v", mem.Entries())
+	}
+
+	if err := WatchConfig(m, &struct{ ConfigSource }{source}, ConfigKey); err == nil {
+		t.Error("WatchConfig() nên báo lỗi khi source không hỗ trợ thông báo thay đổi")
+	}
+}
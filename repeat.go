@@ -0,0 +1,221 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+// FieldSuppressed là key của field ghi số entry giống nhau đã bị bỏ kể từ lần ghi trước,
+// được gắn bởi logger trả về từ EveryN và Dedup.
+const FieldSuppressed = "suppressed"
+
+// repeatMode xác định cách một repeatLogger chặn các entry lặp lại.
+type repeatMode int
+
+const (
+	repeatOnce  repeatMode = iota // Chỉ ghi lần đầu tiên
+	repeatEvery                   // Ghi lần đầu tiên và mỗi lần thứ N sau đó
+	repeatDedup                   // Ghi tối đa một lần trong mỗi khoảng thời gian
+)
+
+// repeatKey xác định một nhóm entry lặp lại của một logger.
+type repeatKey struct {
+	mode    repeatMode
+	every   int
+	window  time.Duration
+	level   handler.Level
+	message string
+}
+
+// repeatCounter đếm các entry của một nhóm.
+type repeatCounter struct {
+	mu         sync.Mutex
+	count      uint64    // Số entry đã gặp
+	suppressed uint64    // Số entry bị bỏ kể từ lần ghi trước
+	last       time.Time // Thời điểm ghi gần nhất (Dedup)
+}
+
+// repeatLogger là một Logger chặn các entry lặp lại của logger gốc.
+//
+// Trạng thái đếm được lưu trên logger gốc nên các lời gọi như logger.Once().Warning(...) tại
+// cùng một vị trí dùng chung bộ đếm. Entry được nhóm theo cấp độ và thông điệp trước khi định
+// dạng, vì vậy thông điệp nên là chuỗi hằng (chuỗi định dạng) thay vì chuỗi được ghép động.
+type repeatLogger struct {
+	*logger
+	mode   repeatMode
+	every  int
+	window time.Duration
+}
+
+// Once trả về một Logger chỉ ghi mỗi thông điệp (theo cấp độ và thông điệp trước khi định
+// dạng) một lần trong suốt vòng đời của logger.
+//
+// Trả về:
+//   - Logger: logger chỉ ghi lần đầu tiên, dùng chung bộ đếm với các lời gọi Once khác
+//
+// Ví dụ:
+//
+//	logger.Once().Warning("Khóa cấu hình %s đã lỗi thời, dùng %s", "db.host", "database.host")
+func (l *logger) Once() Logger {
+	return &repeatLogger{logger: l, mode: repeatOnce}
+}
+
+// EveryN trả về một Logger ghi lần đầu tiên và mỗi lần thứ n của cùng một thông điệp.
+//
+// Các entry được ghi sau lần đầu tiên kèm field suppressed=N cho biết số entry đã bị bỏ kể
+// từ lần ghi trước.
+//
+// Tham số:
+//   - n: int - chu kỳ ghi; n <= 1 ghi tất cả entry
+//
+// Trả về:
+//   - Logger: logger ghi mỗi lần thứ n
+//
+// Ví dụ:
+//
+//	for {
+//	    if err := conn.Reconnect(); err != nil {
+//	        logger.EveryN(100).Warning("Kết nối lại thất bại: %v", err)
+//	    }
+//	}
+//	// Output: [DB] Kết nối lại thất bại: timeout
+//	//         [DB] Kết nối lại thất bại: timeout suppressed=99
+func (l *logger) EveryN(n int) Logger {
+	return &repeatLogger{logger: l, mode: repeatEvery, every: n}
+}
+
+// Dedup trả về một Logger ghi mỗi thông điệp tối đa một lần trong mỗi khoảng thời gian window.
+//
+// Entry lặp lại trong window bị bỏ; entry đầu tiên sau khi window kết thúc được ghi kèm field
+// suppressed=N, nên vòng lặp lỗi được thu gọn thành các bản ghi tóm tắt định kỳ.
+//
+// Tham số:
+//   - window: time.Duration - khoảng thời gian chặn entry lặp lại; window <= 0 ghi tất cả entry
+//
+// Trả về:
+//   - Logger: logger loại bỏ entry lặp lại theo thời gian
+//
+// Ví dụ:
+//
+//	logger.Dedup(time.Minute).Error("Không thể gửi metric: %v", err)
+func (l *logger) Dedup(window time.Duration) Logger {
+	return &repeatLogger{logger: l, mode: repeatDedup, window: window}
+}
+
+// Debug ghi một thông điệp ở cấp độ debug nếu thông điệp không bị chặn.
+func (r *repeatLogger) Debug(message string, args ...interface{}) {
+	r.log(handler.DebugLevel, message, args...)
+}
+
+// Info ghi một thông điệp ở cấp độ info nếu thông điệp không bị chặn.
+func (r *repeatLogger) Info(message string, args ...interface{}) {
+	r.log(handler.InfoLevel, message, args...)
+}
+
+// Warning ghi một thông điệp ở cấp độ warning nếu thông điệp không bị chặn.
+func (r *repeatLogger) Warning(message string, args ...interface{}) {
+	r.log(handler.WarningLevel, message, args...)
+}
+
+// Error ghi một thông điệp ở cấp độ error nếu thông điệp không bị chặn.
+func (r *repeatLogger) Error(message string, args ...interface{}) {
+	r.log(handler.ErrorLevel, message, args...)
+}
+
+// Fatal ghi một thông điệp ở cấp độ fatal nếu thông điệp không bị chặn.
+func (r *repeatLogger) Fatal(message string, args ...interface{}) {
+	r.log(handler.FatalLevel, message, args...)
+}
+
+// LogAt ghi một thông điệp với thời điểm do bên gọi cung cấp nếu thông điệp không bị chặn.
+func (r *repeatLogger) LogAt(t time.Time, level handler.Level, message string, args ...interface{}) {
+	snapshot, suppressed, ok := r.allow(level, message)
+	if !ok {
+		return
+	}
+	if t.IsZero() {
+		t = snapshot.now()
+	}
+	if suppressed > 0 {
+		args = append(args[:len(args):len(args)], Uint64(FieldSuppressed, suppressed))
+	}
+	r.write(snapshot, t, level, message, r.withCaller(args, 1)...)
+}
+
+// LogFields ghi một thông điệp chỉ với các field có cấu trúc nếu thông điệp không bị chặn.
+func (r *repeatLogger) LogFields(level handler.Level, message string, fields ...Field) {
+	snapshot, suppressed, ok := r.allow(level, message)
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		fields = append(fields[:len(fields):len(fields)], Uint64(FieldSuppressed, suppressed))
+	}
+	if caller := r.withCaller(nil, 1); len(caller) > 0 {
+		fields = append(fields[:len(fields):len(fields)], caller[0].(Field))
+	}
+	r.emit(snapshot, snapshot.now(), level, message, expandFields(fields))
+}
+
+// log ghi entry qua logger gốc nếu entry không bị chặn.
+func (r *repeatLogger) log(level handler.Level, message string, args ...interface{}) {
+	snapshot, suppressed, ok := r.allow(level, message)
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		args = append(args[:len(args):len(args)], Uint64(FieldSuppressed, suppressed))
+	}
+	r.write(snapshot, snapshot.now(), level, message, r.withCaller(args, 2)...)
+}
+
+// allow áp dụng lọc cấp độ, lấy mẫu và chặn lặp lại cho entry.
+//
+// Trả về:
+//   - *loggerSnapshot: snapshot dùng để ghi entry
+//   - uint64: số entry bị bỏ kể từ lần ghi trước (0 với Once)
+//   - bool: true nếu entry được ghi
+func (r *repeatLogger) allow(level handler.Level, message string) (*loggerSnapshot, uint64, bool) {
+	// Entry bị lọc theo cấp độ hoặc lấy mẫu không được tính vào bộ đếm
+	if level < r.getMinLevel() {
+		return nil, 0, false
+	}
+	snapshot := r.accepting(level)
+	if snapshot == nil || !snapshot.sample(level, r.context, message) {
+		return nil, 0, false
+	}
+
+	key := repeatKey{mode: r.mode, every: r.every, window: r.window, level: level, message: message}
+	value, ok := r.repeats.Load(key)
+	if !ok {
+		value, _ = r.repeats.LoadOrStore(key, &repeatCounter{})
+	}
+	counter := value.(*repeatCounter)
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	counter.count++
+	switch r.mode {
+	case repeatOnce:
+		return snapshot, 0, counter.count == 1
+	case repeatEvery:
+		if r.every > 1 && (counter.count-1)%uint64(r.every) != 0 {
+			counter.suppressed++
+			return nil, 0, false
+		}
+	case repeatDedup:
+		now := snapshot.now()
+		if r.window > 0 && !counter.last.IsZero() && now.Sub(counter.last) < r.window {
+			counter.suppressed++
+			return nil, 0, false
+		}
+		counter.last = now
+	}
+
+	suppressed := counter.suppressed
+	counter.suppressed = 0
+	return snapshot, suppressed, true
+}
@@ -0,0 +1,136 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestLogger_Once(t *testing.T) {
+	l := NewLogger("Config")
+	rec := &recordingHandler{}
+	l.AddHandler(TestHandlerType, rec)
+
+	for i := 0; i < 5; i++ {
+		l.Once().Warning("key %s is deprecated", "db.host")
+	}
+	l.Once().Error("key %s is deprecated", "db.host")
+	l.Warning("key %s is deprecated", "db.host")
+
+	if len(rec.messages) != 3 {
+		t.Fatalf("Once() nên ghi mỗi cấp độ và thông điệp một lần, got %v", rec.messages)
+	}
+	if rec.messages[0] != "[Config] key db.host is deprecated" {
+		t.Errorf("Once() ghi sai thông điệp, got %q", rec.messages[0])
+	}
+}
+
+func TestLogger_EveryN(t *testing.T) {
+	l := NewLogger("DB")
+	rec := &recordingHandler{}
+	l.AddHandler(TestHandlerType, rec)
+
+	for i := 0; i < 7; i++ {
+		l.EveryN(3).Warning("reconnect failed: %v", "timeout")
+	}
+
+	want := []string{
+		"[DB] reconnect failed: timeout",
+		"[DB] reconnect failed: timeout suppressed=2",
+		"[DB] reconnect failed: timeout suppressed=2",
+	}
+	if strings.Join(rec.messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("EveryN(3) = %v, want %v", rec.messages, want)
+	}
+
+	// Chu kỳ khác dùng bộ đếm riêng
+	l.EveryN(100).Warning("reconnect failed: %v", "timeout")
+	if len(rec.messages) != 4 {
+		t.Errorf("EveryN với chu kỳ khác nên có bộ đếm riêng, got %v", rec.messages)
+	}
+}
+
+func TestLogger_Dedup(t *testing.T) {
+	l := NewLogger("Metrics")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+	calls := 0
+	count := func() {
+		if h.entry != nil {
+			calls++
+			h.entry = nil
+		}
+	}
+
+	window := 50 * time.Millisecond
+	for i := 0; i < 10; i++ {
+		l.Dedup(window).LogFields(handler.ErrorLevel, "push failed", String("target", "prometheus"))
+		count()
+	}
+	if calls != 1 {
+		t.Fatalf("Dedup() nên chỉ ghi một lần trong window, got %d", calls)
+	}
+
+	time.Sleep(window + 10*time.Millisecond)
+	l.Dedup(window).LogFields(handler.ErrorLevel, "push failed", String("target", "prometheus"))
+	if h.entry == nil || h.entry.Message != "[Metrics] push failed target=prometheus suppressed=9" {
+		t.Errorf("Entry đầu tiên sau window nên kèm số entry bị bỏ, got %v", h.entry)
+	}
+}
+
+func TestLogger_RepeatIgnoresFilteredEntries(t *testing.T) {
+	l := NewLogger("Worker", WithLevel(handler.InfoLevel))
+	rec := &recordingHandler{}
+	l.AddHandler(TestHandlerType, rec)
+
+	l.Once().Debug("started")
+	l.SetMinLevel(handler.DebugLevel)
+	l.Once().Debug("started")
+	if len(rec.messages) != 1 {
+		t.Errorf("Entry bị lọc theo cấp độ không nên được tính vào bộ đếm, got %v", rec.messages)
+	}
+}
+
+func TestLogger_RepeatConcurrent(t *testing.T) {
+	l := NewLogger("Worker")
+	h := &discardHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Once().Info("ready")
+				l.EveryN(10).Info("tick")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := h.calls.Load(); got != 1+80 {
+		t.Errorf("Nên ghi 1 entry Once và 80 entry EveryN(10), got %d", got)
+	}
+}
+
+func TestLogger_RepeatCaller(t *testing.T) {
+	l := NewLogger("App", WithCaller())
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	want := nextLine()
+	l.Once().Info("started")
+	if h.entry == nil || !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("Once() nên ghi kèm vị trí gọi %q, got %v", want, h.entry)
+	}
+
+	want = nextLine()
+	l.EveryN(2).LogAt(time.Time{}, handler.WarningLevel, "late")
+	if !strings.HasSuffix(h.entry.Message, "caller="+want) {
+		t.Errorf("LogAt() nên ghi kèm vị trí gọi %q, got %q", want, h.entry.Message)
+	}
+}
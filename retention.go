@@ -0,0 +1,118 @@
+package log
+
+import (
+	"strings"
+)
+
+// FieldRetention là key của field ghi lớp lưu trữ của entry, để hệ thống lưu trữ log phía sau
+// (VD: Elasticsearch ILM, Loki, S3 lifecycle) áp dụng thời gian lưu trữ khác nhau.
+const FieldRetention = "retention"
+
+// Các lớp lưu trữ thường dùng. Hệ thống lưu trữ phía sau quyết định thời gian lưu trữ thực tế
+// của từng lớp; có thể dùng tên lớp khác không chứa khoảng trắng.
+const (
+	RetentionShort = "short" // Log chẩn đoán, chỉ cần giữ vài ngày
+	RetentionLong  = "long"  // Log nghiệp vụ cần giữ lâu dài
+	RetentionAudit = "audit" // Log kiểm toán phải giữ theo quy định
+)
+
+// Retain tạo field gắn lớp lưu trữ cho một entry, ghi đè lớp lưu trữ của logger.
+//
+// Tham số:
+//   - class: string - lớp lưu trữ (VD: RetentionAudit)
+//
+// Trả về:
+//   - Field: field "retention"
+//
+// Ví dụ:
+//
+//	logger.Info("User %d changed role", userID, log.Retain(log.RetentionAudit))
+func Retain(class string) Field {
+	return String(FieldRetention, class)
+}
+
+// RetentionConfig định nghĩa lớp lưu trữ được gắn vào entry của các logger do Manager tạo.
+type RetentionConfig struct {
+	// Default lớp lưu trữ của các context không có trong Contexts. Rỗng = không gắn
+	Default string `mapstructure:"default" yaml:"default" json:"default"`
+
+	// Contexts lớp lưu trữ theo context của logger (VD: {"Payment": "audit"})
+	Contexts map[string]string `mapstructure:"contexts" yaml:"contexts" json:"contexts"`
+}
+
+// ClassFor trả về lớp lưu trữ của context.
+//
+// Tham số:
+//   - context: string - context của logger
+//
+// Trả về:
+//   - string: lớp lưu trữ, rỗng nếu không gắn
+func (r RetentionConfig) ClassFor(context string) string {
+	if class, ok := r.Contexts[context]; ok {
+		return class
+	}
+	return r.Default
+}
+
+// validate kiểm tra các lớp lưu trữ trong cấu hình.
+func (r RetentionConfig) validate() error {
+	if strings.ContainsAny(r.Default, " \t\r\n") {
+		return &ConfigError{
+			Field:   "retention.default",
+			Value:   r.Default,
+			Message: "retention class must not contain whitespace",
+		}
+	}
+	for context, class := range r.Contexts {
+		if class == "" || strings.ContainsAny(class, " \t\r\n") {
+			return &ConfigError{
+				Field:   "retention.contexts." + context,
+				Value:   class,
+				Message: "retention class must be non-empty and must not contain whitespace",
+			}
+		}
+	}
+	return nil
+}
+
+// WithRetention gắn lớp lưu trữ vào mọi entry của logger, trừ entry đã có field "retention".
+//
+// Tham số:
+//   - class: string - lớp lưu trữ, rỗng để không gắn
+//
+// Trả về:
+//   - LoggerOption: tùy chọn gắn lớp lưu trữ
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Audit", log.WithRetention(log.RetentionAudit))
+func WithRetention(class string) LoggerOption {
+	return func(l *logger) {
+		l.retention = class
+	}
+}
+
+// setRetention thay đổi lớp lưu trữ của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - class: string - lớp lưu trữ mới, rỗng để không gắn
+func (l *logger) setRetention(class string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.retention = class
+	l.publish()
+}
+
+// withRetention thêm field lớp lưu trữ vào cuối fields nếu chưa có.
+func withRetention(fields []Field, class string) []Field {
+	if class == "" {
+		return fields
+	}
+	for _, f := range fields {
+		if f.Key == FieldRetention {
+			return fields
+		}
+	}
+	return append(fields[:len(fields):len(fields)], Retain(class))
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestManager_Retention(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	config.MaxFields = 1
+	config.Retention = RetentionConfig{Default: RetentionShort, Contexts: map[string]string{"Payment": RetentionAudit}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	payment := m.GetLogger("Payment")
+	worker := m.GetLogger("Worker")
+	paymentEntries, workerEntries := &entryHandler{}, &entryHandler{}
+	payment.AddHandler(TestHandlerType, paymentEntries)
+	worker.AddHandler(TestHandlerType, workerEntries)
+
+	payment.Info("charge created", Int("amount", 100), String("currency", "VND"))
+	if got := paymentEntries.entry.Message; got != "[Payment] charge created amount=100 fields_truncated=1 retention=audit" {
+		t.Errorf("Lớp lưu trữ theo context nên được gắn sau khi cắt field, got %q", got)
+	}
+	worker.Info("job done")
+	if got := workerEntries.entry.Message; got != "[Worker] job done retention=short" {
+		t.Errorf("Context không cấu hình nên dùng lớp mặc định, got %q", got)
+	}
+	worker.Info("role changed", Retain(RetentionLong))
+	if got := workerEntries.entry.Message; got != "[Worker] role changed retention=long" {
+		t.Errorf("Retain() nên ghi đè lớp lưu trữ của logger, got %q", got)
+	}
+
+	updated := *config
+	updated.Retention = RetentionConfig{}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "retention.contexts.Payment") {
+		t.Errorf("Diff nên liệt kê thay đổi của retention, got %q", diff.String())
+	}
+	payment.LogFields(handler.InfoLevel, "refund created")
+	if got := paymentEntries.entry.Message; got != "[Payment] refund created" {
+		t.Errorf("Tắt retention nên áp dụng cho logger đã tồn tại, got %q", got)
+	}
+
+	invalid := *createTestConfig()
+	invalid.Retention.Contexts = map[string]string{"Payment": "audit 7y"}
+	err = invalid.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "retention.contexts.Payment" {
+		t.Errorf("Validate() nên từ chối lớp lưu trữ chứa khoảng trắng, got %v", err)
+	}
+}
+
+func TestWithRetention(t *testing.T) {
+	l := NewLogger("Audit", WithRetention(RetentionAudit))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("login user=%s", "alice")
+	if len(h.entry.Fields) != 1 || h.entry.Fields[0].Key != FieldRetention || h.entry.Fields[0].Str != RetentionAudit {
+		t.Errorf("Entry gửi đến handler nên chứa field retention, got %+v", h.entry.Fields)
+	}
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+
+	"go.fork.vn/log/handler"
+)
+
+// RotateAll xoay vòng mọi handler đã đăng ký triển khai handler.Rotator, gồm file log chính,
+// file của các channel và Config.Files, kể cả khi được bọc bởi async hoặc delivery. Entry còn
+// trong hàng đợi bất đồng bộ được ghi vào file mới.
+//
+// File đã bị công cụ bên ngoài đổi tên (VD: logrotate) chỉ được mở lại tại đường dẫn cũ, nên
+// RotateAll dùng được cho cả xoay vòng thủ công lẫn phối hợp với logrotate. Method này là
+// thread-safe.
+//
+// Trả về:
+//   - error: tổng hợp lỗi của các handler xoay vòng thất bại
+//
+// Ví dụ:
+//
+//	if err := manager.RotateAll(); err != nil {
+//	    fmt.Fprintf(os.Stderr, "Không thể xoay vòng file log: %v\n", err)
+//	}
+func (m *manager) RotateAll() error {
+	m.mu.RLock()
+	handlers := make(map[HandlerType]handler.Handler, len(m.handlers))
+	for handlerType, h := range m.handlers {
+		handlers[handlerType] = h
+	}
+	m.mu.RUnlock()
+
+	types := make([]HandlerType, 0, len(handlers))
+	for handlerType := range handlers {
+		// Các handler con của stack đã được đăng ký riêng với manager
+		if handlerType != HandlerTypeStack {
+			types = append(types, handlerType)
+		}
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for _, handlerType := range types {
+		if err := handler.Rotate(handlers[handlerType]); err != nil {
+			errs = append(errs, fmt.Errorf("handler %s: %w", handlerType, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RotateOnSignal gọi m.RotateAll mỗi khi tiến trình nhận một trong các signal, để logrotate
+// (postrotate "kill -HUP") hoặc người vận hành xoay vòng file log mà không khởi động lại
+// service. Lỗi khi xoay vòng được ghi ra stderr.
+//
+// Tham số:
+//   - m: Manager - manager cần xoay vòng
+//   - signals: ...os.Signal - các signal kích hoạt, mặc định SIGHUP
+//
+// Trả về:
+//   - func(): hàm ngừng theo dõi signal
+//
+// Ví dụ:
+//
+//	stop := log.RotateOnSignal(manager)
+//	defer stop()
+func RotateOnSignal(m Manager, signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := m.RotateAll(); err != nil {
+					fmt.Fprintf(os.Stderr, "Lỗi khi xoay vòng file log: %v\n", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestManager_RotateAll(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	appPath, accessPath := config.File.Path, config.Channels[ChannelAccess].Path
+
+	m.GetLogger("Order").Info("before rotation")
+	m.GetLogger(AccessContext).Info("GET /before")
+	// Mô phỏng logrotate đổi tên file access.log trước khi gửi SIGHUP
+	if err := os.Rename(accessPath, accessPath+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RotateAll(); err != nil {
+		t.Fatalf("RotateAll() error = %v", err)
+	}
+	m.GetLogger("Order").Info("after rotation")
+	m.GetLogger(AccessContext).Info("GET /after")
+	m.Close()
+
+	backups, _ := filepath.Glob(appPath + ".*")
+	if len(backups) != 1 || !strings.Contains(readLog(t, backups[0]), "before rotation") {
+		t.Errorf("RotateAll() nên xoay vòng file chính, got %v", backups)
+	}
+	if app := readLog(t, appPath); strings.Contains(app, "before rotation") || !strings.Contains(app, "after rotation") {
+		t.Errorf("File chính mới chỉ nên chứa entry sau khi xoay vòng, got %q", app)
+	}
+	if access := readLog(t, accessPath); access == "" || strings.Contains(access, "GET /before") {
+		t.Errorf("File của channel nên được mở lại sau khi bị đổi tên, got %q", access)
+	}
+}
+
+func TestRotateOnSignal(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	m.GetLogger("Order").Info("before signal")
+
+	stop := RotateOnSignal(m, syscall.SIGHUP)
+	defer stop()
+	process, _ := os.FindProcess(os.Getpid())
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Skipf("Không thể gửi SIGHUP trên nền tảng này: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if backups, _ := filepath.Glob(config.File.Path + ".*"); len(backups) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("SIGHUP nên xoay vòng file log")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	stop() // Gọi lại stop không panic
+}
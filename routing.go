@@ -0,0 +1,96 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+
+	"go.fork.vn/log/handler"
+)
+
+// RoutingRuleConfig định nghĩa một luật định tuyến: entry khớp Match được gửi đến các handler
+// trong Handlers.
+//
+// Handler được nêu trong ít nhất một luật chỉ nhận các entry khớp một trong các luật nêu nó;
+// handler không được nêu trong luật nào vẫn nhận mọi entry như trước.
+type RoutingRuleConfig struct {
+	// Match điều kiện trên cấp độ, context, thông điệp và field của entry
+	Match RecordRuleConfig `mapstructure:"match" yaml:"match" json:"match"`
+
+	// Handlers tên các handler nhận entry khớp (VD: "file", "channel.audit", hoặc handler tùy
+	// chỉnh như "sentry" thêm qua AddHandler)
+	Handlers []string `mapstructure:"handlers" yaml:"handlers" json:"handlers"`
+}
+
+// String trả về mô tả ngắn gọn của luật, VD: "level=error message= contexts= fields= -> sentry".
+func (r RoutingRuleConfig) String() string {
+	return "level=" + r.Match.Level + " message=" + r.Match.Message + " contexts=" + strings.Join(r.Match.Contexts, ",") +
+		" fields=" + strings.Join(r.Match.Fields, ",") + " -> " + strings.Join(r.Handlers, ",")
+}
+
+// routingFilter trả về bộ lọc giới hạn các entry mà handler nhận theo Config.Routing.
+//
+// Tham số:
+//   - config: *Config - cấu hình đã được Validate
+//   - handlerType: HandlerType - tên handler
+//
+// Trả về:
+//   - handler.RecordFilter: bộ lọc chỉ giữ entry khớp một trong các luật nêu handler
+//   - bool: false nếu không có luật nào nêu handler
+func routingFilter(config *Config, handlerType HandlerType) (handler.RecordFilter, bool) {
+	var filter handler.RecordFilter
+	for _, rule := range config.Routing {
+		if !containsString(rule.Handlers, string(handlerType)) {
+			continue
+		}
+		// Cấu hình đã được Validate nên điều kiện biên dịch được
+		if compiled, err := rule.Match.rule(); err == nil {
+			filter.Include = append(filter.Include, compiled)
+		}
+	}
+	return filter, len(filter.Include) > 0
+}
+
+// routingRules trả về mô tả các luật nêu handler, dùng để phát hiện thay đổi giữa hai cấu hình.
+func routingRules(config *Config, handlerType HandlerType) string {
+	var rules []string
+	for _, rule := range config.Routing {
+		if containsString(rule.Handlers, string(handlerType)) {
+			rules = append(rules, rule.String())
+		}
+	}
+	return strings.Join(rules, "; ")
+}
+
+// validateRouting kiểm tra các luật định tuyến.
+//
+// Trả về:
+//   - error: ConfigError nếu luật không có handler, nêu stack hoặc có điều kiện không hợp lệ
+func (c *Config) validateRouting() error {
+	for i, rule := range c.Routing {
+		field := "routing." + strconv.Itoa(i)
+		if len(rule.Handlers) == 0 {
+			return &ConfigError{
+				Field:   field + ".handlers",
+				Value:   rule.String(),
+				Message: "routing rule must name at least one handler",
+			}
+		}
+		for _, name := range rule.Handlers {
+			if name == "" || HandlerType(name) == HandlerTypeStack {
+				return &ConfigError{
+					Field:   field + ".handlers",
+					Value:   name,
+					Message: "routing rules must name handlers other than stack, route to its members instead",
+				}
+			}
+		}
+		if _, err := rule.Match.rule(); err != nil {
+			return &ConfigError{
+				Field:   field + ".match",
+				Value:   rule.String(),
+				Message: err.Error(),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestManager_Routing(t *testing.T) {
+	config := createTestConfig()
+	config.Routing = []RoutingRuleConfig{
+		{Match: RecordRuleConfig{Level: "error"}, Handlers: []string{"sentry"}},
+		{Match: RecordRuleConfig{Contexts: []string{"Audit*"}}, Handlers: []string{"audit", "sentry"}},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	// Handler tùy chỉnh được gắn vào các logger đã tồn tại khi được thêm
+	order, trail := m.GetLogger("Order"), m.GetLogger("AuditTrail")
+	sentry, audit, console := &entryHandler{}, &entryHandler{}, &entryHandler{}
+	m.AddHandler("sentry", sentry)
+	m.AddHandler("audit", audit)
+	m.AddHandler("console", console)
+
+	tests := []struct {
+		context string
+		log     func()
+		sentry  bool
+		audit   bool
+	}{
+		{"Order", func() { order.Info("created") }, false, false},
+		{"Order", func() { order.Error("failed") }, true, false},
+		{"AuditTrail", func() { trail.Info("role changed") }, true, true},
+	}
+	for _, tt := range tests {
+		sentry.entry, audit.entry, console.entry = nil, nil, nil
+		tt.log()
+		if got := sentry.entry != nil; got != tt.sentry {
+			t.Errorf("%s: sentry nhận entry = %v, want %v", tt.context, got, tt.sentry)
+		}
+		if got := audit.entry != nil; got != tt.audit {
+			t.Errorf("%s: audit nhận entry = %v, want %v", tt.context, got, tt.audit)
+		}
+		if console.entry == nil {
+			t.Errorf("%s: console không có luật nên nhận mọi entry", tt.context)
+		}
+	}
+
+	updated := *config
+	updated.Routing = []RoutingRuleConfig{{Match: RecordRuleConfig{Level: "warning"}, Handlers: []string{"console"}}}
+	diff, err := m.ValidateConfig(&updated)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "routing") || !strings.Contains(diff.String(), "handler console: recreate") {
+		t.Errorf("Luật mới cho console nên tạo lại console handler, got %q", diff.String())
+	}
+}
+
+func TestConfig_ValidateRouting(t *testing.T) {
+	for name, rule := range map[string]RoutingRuleConfig{
+		"không có handler":     {Match: RecordRuleConfig{Level: "error"}},
+		"định tuyến đến stack": {Match: RecordRuleConfig{Level: "error"}, Handlers: []string{"stack"}},
+		"cấp độ không hợp lệ":  {Match: RecordRuleConfig{Level: "loud"}, Handlers: []string{"file"}},
+		"regex không hợp lệ":   {Match: RecordRuleConfig{Message: "(a"}, Handlers: []string{"file"}},
+	} {
+		config := createTestConfig()
+		config.Routing = []RoutingRuleConfig{rule}
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "routing.0") {
+			t.Errorf("Validate() nên từ chối luật %s, got %v", name, err)
+		}
+	}
+}
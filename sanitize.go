@@ -0,0 +1,57 @@
+package log
+
+import "go.fork.vn/log/handler"
+
+// WithRawMessages tắt việc thoát ký tự điều khiển trong thông điệp và key của field (xem
+// handler.Sanitize), để ghi nguyên văn nội dung nhiều dòng đáng tin cậy (VD: stack trace, output
+// của lệnh).
+//
+// Mặc định logger thoát '\n', '\r', ký tự điều khiển và byte UTF-8 không hợp lệ trong thông điệp,
+// để dữ liệu từ bên ngoài không tạo được dòng log giả. Giá trị của field luôn được định dạng an
+// toàn (đặt trong nháy kép và thoát) nên không bị ảnh hưởng. Chỉ dùng raw mode khi thông điệp
+// không chứa dữ liệu không tin cậy.
+//
+// Trả về:
+//   - LoggerOption: tùy chọn tắt làm sạch thông điệp
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("Build", log.WithRawMessages())
+//	logger.Info("go vet output:\n%s", output) // giữ nguyên các dòng của output
+func WithRawMessages() LoggerOption {
+	return func(l *logger) {
+		l.raw = true
+	}
+}
+
+// setRawMessages bật/tắt raw mode của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - raw: bool - true để ghi thông điệp và key của field không qua handler.Sanitize
+func (l *logger) setRawMessages(raw bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.raw = raw
+	l.publish()
+}
+
+// sanitizeKeys áp dụng handler.Sanitize cho key của các field. Slice fields có thể thuộc về bên
+// gọi nên được sao chép khi có key cần thoát.
+func sanitizeKeys(fields []Field) []Field {
+	var out []Field
+	for i, f := range fields {
+		key := handler.Sanitize(f.Key)
+		if key == f.Key {
+			continue
+		}
+		if out == nil {
+			out = append([]Field(nil), fields...)
+		}
+		out[i].Key = key
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
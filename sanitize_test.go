@@ -0,0 +1,67 @@
+package log
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogger_SanitizesMessages(t *testing.T) {
+	l := NewLogger("Auth")
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	user := "bob\n2024/03/01 12:00:00 [INFO] [Auth] admin logged in"
+	fields := []Field{String("user\r", user)}
+	l.Warning("login failed for %s", user, fields[0])
+	if got := h.entry.Message; strings.Contains(got, "\n") || !strings.HasPrefix(got, `[Auth] login failed for bob\n2024/03/01`) {
+		t.Errorf("Thông điệp nên được thoát, got %q", got)
+	}
+	if h.entry.Fields[0].Key != `user\r` || h.entry.Fields[0].Str != user {
+		t.Errorf("Chỉ key của field nên được thoát, got %+v", h.entry.Fields[0])
+	}
+	if fields[0].Key != "user\r" {
+		t.Error("Field của bên gọi không được bị sửa")
+	}
+
+	raw := NewLogger("Build", WithRawMessages())
+	raw.AddHandler(TestHandlerType, h)
+	raw.Info("output:\n%s", "ok")
+	if got := h.entry.Message; got != "[Build] output:\nok" {
+		t.Errorf("WithRawMessages() nên ghi nguyên văn, got %q", got)
+	}
+}
+
+func TestManager_RawMessages(t *testing.T) {
+	config := createTestConfig()
+	config.Console.Enabled = false
+	config.Stack.Enabled = false
+	config.File.Path = filepath.Join(t.TempDir(), "app.log")
+	m := NewManager(config).(*manager)
+	defer m.Close()
+
+	build := m.GetLogger("Build")
+	h := &entryHandler{}
+	build.AddHandler(TestHandlerType, h)
+	build.Info("a\nb")
+	if got := h.entry.Message; got != `[Build] a\nb` {
+		t.Errorf("Thông điệp nên được thoát mặc định, got %q", got)
+	}
+
+	updated := *config
+	updated.RawMessages = true
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "raw_messages") {
+		t.Errorf("Diff nên liệt kê thay đổi của raw_messages, got %q", diff.String())
+	}
+	build.Info("a\nb")
+	if got := h.entry.Message; got != "[Build] a\nb" {
+		t.Errorf("RawMessages nên áp dụng cho logger đã tồn tại, got %q", got)
+	}
+	if got := m.GetLogger("Deploy").(*logger); !got.raw {
+		t.Error("Logger mới nên dùng RawMessages của cấu hình")
+	}
+}
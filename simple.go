@@ -0,0 +1,23 @@
+package log
+
+// Simple tạo một Logger độc lập ghi ra console, không cần Manager hay DI container.
+//
+// Simple phù hợp cho các công cụ nhỏ, script và ví dụ cần cùng định dạng và cấp độ log
+// với framework. Logger mặc định ghi ra console có màu ở InfoLevel, không có context;
+// các option được áp dụng sau cấu hình mặc định nên có thể ghi đè nó.
+// Bên gọi nên gọi Close khi kết thúc để giải phóng handler.
+//
+// Tham số:
+//   - opts: ...LoggerOption - tùy chọn của logger (VD: WithLevel, WithConsole, WithCaller)
+//
+// Trả về:
+//   - Logger: logger độc lập đã được gắn console handler
+//
+// Ví dụ:
+//
+//	logger := log.Simple(log.WithLevel(handler.DebugLevel))
+//	defer logger.Close()
+//	logger.Info("Đã xử lý %d file", count)
+func Simple(opts ...LoggerOption) Logger {
+	return NewLogger("", append([]LoggerOption{WithConsole(true)}, opts...)...)
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestSimple(t *testing.T) {
+	l := Simple()
+	defer l.Close()
+
+	if _, ok := l.GetHandler(HandlerTypeConsole).(*handler.ConsoleHandler); !ok {
+		t.Fatal("Simple() nên gắn sẵn console handler")
+	}
+	if level := l.(*logger).getMinLevel(); level != handler.InfoLevel {
+		t.Errorf("Simple() nên dùng InfoLevel mặc định, got %v", level)
+	}
+}
+
+func TestSimple_Options(t *testing.T) {
+	l := Simple(WithLevel(handler.DebugLevel), WithConsole(false), WithCaller())
+	defer l.Close()
+
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+	l.Debug("verbose")
+
+	if h.entry == nil {
+		t.Fatal("WithLevel(DebugLevel) nên cho phép ghi log debug")
+	}
+	if h.entry.Message == "verbose" {
+		t.Errorf("WithCaller nên được áp dụng cho Simple, got %q", h.entry.Message)
+	}
+	if len(l.(*logger).handlers) != 2 {
+		t.Errorf("WithConsole nên thay thế console handler mặc định, got %d handler", len(l.(*logger).handlers))
+	}
+}
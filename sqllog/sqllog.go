@@ -0,0 +1,467 @@
+// Package sqllog cung cấp driver wrapper cho database/sql ghi log mọi câu lệnh SQL qua log.Logger.
+//
+// Mỗi câu lệnh (query, exec, begin, commit, rollback) được ghi kèm thời gian thực thi, lỗi
+// (nếu có) và một trace ID riêng cho câu lệnh, giúp đối chiếu log của ứng dụng với log của
+// database. Tham số của câu lệnh có thể được ẩn bằng WithRedactedArgs khi chúng chứa dữ liệu nhạy cảm.
+//
+// Ví dụ:
+//
+//	logger := manager.GetLogger("SQL")
+//	sql.Register("postgres-logged", sqllog.Wrap(&pq.Driver{}, logger))
+//	db, err := sql.Open("postgres-logged", dsn)
+//
+//	// Hoặc với connector
+//	db := sql.OpenDB(sqllog.WrapConnector(connector, logger, sqllog.WithRedactedArgs()))
+package sqllog
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// Các field được ghi cho mỗi câu lệnh.
+const (
+	FieldOp       = "op"       // Loại thao tác: query, exec, prepare, begin, commit, rollback
+	FieldTraceID  = "trace_id" // Trace ID riêng của câu lệnh
+	FieldQuery    = "query"    // Câu lệnh SQL
+	FieldArgs     = "args"     // Tham số của câu lệnh
+	FieldDuration = "duration" // Thời gian thực thi
+	FieldError    = "error"    // Lỗi trả về bởi driver
+)
+
+// redacted là giá trị thay thế cho tham số khi WithRedactedArgs được bật.
+const redacted = "[REDACTED]"
+
+// Option cấu hình driver wrapper.
+type Option func(*options)
+
+// options chứa cấu hình của driver wrapper.
+type options struct {
+	redactArgs bool
+	level      handler.Level
+}
+
+// WithRedactedArgs thay thế giá trị của mọi tham số bằng "[REDACTED]" trong log.
+//
+// Trả về:
+//   - Option: option cấu hình driver wrapper
+func WithRedactedArgs() Option {
+	return func(o *options) {
+		o.redactArgs = true
+	}
+}
+
+// WithLevel thiết lập cấp độ log cho các câu lệnh thành công (mặc định DebugLevel).
+// Câu lệnh thất bại luôn được ghi ở cấp độ ErrorLevel.
+//
+// Tham số:
+//   - level: handler.Level - cấp độ log cho câu lệnh thành công
+//
+// Trả về:
+//   - Option: option cấu hình driver wrapper
+func WithLevel(level handler.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// logSink ghi log cho các thao tác của driver.
+type logSink struct {
+	logger log.Logger
+	opts   options
+}
+
+// newLogSink tạo logSink từ logger và các option.
+func newLogSink(logger log.Logger, opts []Option) *logSink {
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+
+	s := &logSink{logger: logger, opts: options{level: handler.DebugLevel}}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	return s
+}
+
+// log ghi một thao tác đã hoàn thành. driver.ErrSkip không được xem là lỗi vì
+// database/sql sẽ thử lại thao tác bằng cách khác.
+func (s *logSink) log(op, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	fields := make([]interface{}, 0, 6)
+	fields = append(fields, log.Any(FieldOp, op), log.Any(FieldTraceID, newTraceID()))
+	if query != "" {
+		fields = append(fields, log.Any(FieldQuery, query))
+	}
+	if len(args) > 0 {
+		fields = append(fields, log.Any(FieldArgs, s.formatArgs(args)))
+	}
+	fields = append(fields, log.Any(FieldDuration, time.Since(start)))
+
+	level := s.opts.level
+	if err != nil {
+		fields = append(fields, log.Any(FieldError, err))
+		level = handler.ErrorLevel
+	}
+
+	s.logger.LogAt(time.Time{}, level, "sql "+op, fields...)
+}
+
+// formatArgs chuyển tham số của câu lệnh thành dạng hiển thị, ẩn giá trị nếu được cấu hình.
+func (s *logSink) formatArgs(args []driver.NamedValue) string {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if s.opts.redactArgs {
+			values[i] = redacted
+		} else {
+			values[i] = arg.Value
+		}
+	}
+	return fmt.Sprint(values)
+}
+
+// newTraceID tạo trace ID ngẫu nhiên 16 ký tự hex cho một câu lệnh.
+func newTraceID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "0000000000000000"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Wrap bọc một driver.Driver để ghi log mọi câu lệnh qua logger.
+//
+// Tham số:
+//   - d: driver.Driver - driver gốc
+//   - logger: log.Logger - logger dùng để ghi log câu lệnh
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - driver.Driver: driver đã được bọc, có thể đăng ký bằng sql.Register
+//
+// Ví dụ:
+//
+//	sql.Register("sqlite-logged", sqllog.Wrap(&sqlite3.SQLiteDriver{}, logger, sqllog.WithRedactedArgs()))
+func Wrap(d driver.Driver, logger log.Logger, opts ...Option) driver.Driver {
+	return &wrappedDriver{parent: d, sink: newLogSink(logger, opts)}
+}
+
+// WrapConnector bọc một driver.Connector để ghi log mọi câu lệnh qua logger.
+//
+// Tham số:
+//   - c: driver.Connector - connector gốc
+//   - logger: log.Logger - logger dùng để ghi log câu lệnh
+//   - opts: ...Option - các option cấu hình
+//
+// Trả về:
+//   - driver.Connector: connector đã được bọc, dùng với sql.OpenDB
+func WrapConnector(c driver.Connector, logger log.Logger, opts ...Option) driver.Connector {
+	sink := newLogSink(logger, opts)
+	return &wrappedConnector{parent: c, driver: &wrappedDriver{parent: c.Driver(), sink: sink}, sink: sink}
+}
+
+// wrappedDriver bọc driver.Driver.
+type wrappedDriver struct {
+	parent driver.Driver
+	sink   *logSink
+}
+
+// Open mở kết nối mới từ driver gốc.
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.parent.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{parent: conn, sink: d.sink}, nil
+}
+
+// OpenConnector tạo connector nếu driver gốc hỗ trợ driver.DriverContext.
+func (d *wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	dc, ok := d.parent.(driver.DriverContext)
+	if !ok {
+		return &dsnConnector{name: name, driver: d}, nil
+	}
+	c, err := dc.OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConnector{parent: c, driver: d, sink: d.sink}, nil
+}
+
+// wrappedConnector bọc driver.Connector.
+type wrappedConnector struct {
+	parent driver.Connector
+	driver *wrappedDriver
+	sink   *logSink
+}
+
+// Connect mở kết nối mới từ connector gốc.
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.parent.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{parent: conn, sink: c.sink}, nil
+}
+
+// Driver trả về driver đã được bọc.
+func (c *wrappedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector là connector cho driver gốc không hỗ trợ driver.DriverContext.
+type dsnConnector struct {
+	name   string
+	driver *wrappedDriver
+}
+
+// Connect mở kết nối mới bằng DSN.
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+// Driver trả về driver đã được bọc.
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// wrappedConn bọc driver.Conn và các interface tùy chọn của nó.
+type wrappedConn struct {
+	parent driver.Conn
+	sink   *logSink
+}
+
+// Prepare chuẩn bị câu lệnh trên kết nối gốc.
+func (c *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext chuẩn bị câu lệnh, chỉ ghi log khi thất bại.
+func (c *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+
+	var stmt driver.Stmt
+	var err error
+	if pc, ok := c.parent.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.parent.Prepare(query)
+	}
+	if err != nil {
+		c.sink.log("prepare", query, nil, start, err)
+		return nil, err
+	}
+	return &wrappedStmt{parent: stmt, query: query, sink: c.sink}, nil
+}
+
+// Close đóng kết nối gốc.
+func (c *wrappedConn) Close() error {
+	return c.parent.Close()
+}
+
+// Begin bắt đầu transaction.
+func (c *wrappedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx bắt đầu transaction và ghi log thao tác begin.
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+
+	var tx driver.Tx
+	var err error
+	if bc, ok := c.parent.(driver.ConnBeginTx); ok {
+		tx, err = bc.BeginTx(ctx, opts)
+	} else {
+		// Fallback cho driver không hỗ trợ ConnBeginTx
+		tx, err = c.parent.Begin()
+	}
+	c.sink.log("begin", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedTx{parent: tx, sink: c.sink}, nil
+}
+
+// ExecContext thực thi câu lệnh trực tiếp nếu kết nối gốc hỗ trợ driver.ExecerContext.
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.parent.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := ec.ExecContext(ctx, query, args)
+	c.sink.log("exec", query, args, start, err)
+	return res, err
+}
+
+// QueryContext thực thi truy vấn trực tiếp nếu kết nối gốc hỗ trợ driver.QueryerContext.
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.parent.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.sink.log("query", query, args, start, err)
+	return rows, err
+}
+
+// Ping kiểm tra kết nối nếu kết nối gốc hỗ trợ driver.Pinger.
+func (c *wrappedConn) Ping(ctx context.Context) error {
+	if p, ok := c.parent.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession chuyển tiếp đến kết nối gốc nếu hỗ trợ driver.SessionResetter.
+func (c *wrappedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.parent.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid chuyển tiếp đến kết nối gốc nếu hỗ trợ driver.Validator.
+func (c *wrappedConn) IsValid() bool {
+	if v, ok := c.parent.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue chuyển tiếp đến kết nối gốc nếu hỗ trợ driver.NamedValueChecker.
+func (c *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.parent.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// wrappedStmt bọc driver.Stmt.
+type wrappedStmt struct {
+	parent driver.Stmt
+	query  string
+	sink   *logSink
+}
+
+// Close đóng câu lệnh gốc.
+func (s *wrappedStmt) Close() error {
+	return s.parent.Close()
+}
+
+// NumInput trả về số tham số của câu lệnh gốc.
+func (s *wrappedStmt) NumInput() int {
+	return s.parent.NumInput()
+}
+
+// Exec thực thi câu lệnh với tham số dạng vị trí.
+func (s *wrappedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), toNamedValues(args))
+}
+
+// ExecContext thực thi câu lệnh và ghi log.
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var res driver.Result
+	var err error
+	if ec, ok := s.parent.(driver.StmtExecContext); ok {
+		res, err = ec.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			res, err = s.parent.Exec(values)
+		}
+	}
+	s.sink.log("exec", s.query, args, start, err)
+	return res, err
+}
+
+// Query thực thi truy vấn với tham số dạng vị trí.
+func (s *wrappedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), toNamedValues(args))
+}
+
+// QueryContext thực thi truy vấn và ghi log.
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.parent.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			rows, err = s.parent.Query(values)
+		}
+	}
+	s.sink.log("query", s.query, args, start, err)
+	return rows, err
+}
+
+// CheckNamedValue chuyển tiếp đến câu lệnh gốc nếu hỗ trợ driver.NamedValueChecker.
+func (s *wrappedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.parent.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// wrappedTx bọc driver.Tx.
+type wrappedTx struct {
+	parent driver.Tx
+	sink   *logSink
+}
+
+// Commit commit transaction và ghi log.
+func (t *wrappedTx) Commit() error {
+	start := time.Now()
+	err := t.parent.Commit()
+	t.sink.log("commit", "", nil, start, err)
+	return err
+}
+
+// Rollback rollback transaction và ghi log.
+func (t *wrappedTx) Rollback() error {
+	start := time.Now()
+	err := t.parent.Rollback()
+	t.sink.log("rollback", "", nil, start, err)
+	return err
+}
+
+// toNamedValues chuyển tham số dạng vị trí thành driver.NamedValue.
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// toValues chuyển driver.NamedValue thành tham số dạng vị trí cho driver cũ.
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,171 @@
+package sqllog
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.fork.vn/log"
+	"go.fork.vn/log/handler"
+)
+
+// fakeDriver là driver tối giản chỉ hỗ trợ các interface bắt buộc,
+// buộc database/sql đi qua Prepare và Stmt
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.HasPrefix(query, "BROKEN") {
+		return nil, errors.New("syntax error")
+	}
+	return fakeStmt{query: query}, nil
+}
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeStmt struct{ query string }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "FAIL") {
+		return nil, errors.New("constraint violation")
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+type fakeRows struct{ done bool }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+// newTestDB mở database với driver đã được bọc và trả về capture handler của logger.
+func newTestDB(t *testing.T, opts ...Option) (*sql.DB, *handler.MemoryHandler) {
+	logger := log.NewLogger("SQL")
+	logger.SetMinLevel(handler.DebugLevel)
+	capture := handler.NewMemoryHandler()
+	logger.AddHandler("capture", capture)
+
+	connector, err := Wrap(fakeDriver{}, logger, opts...).(driver.DriverContext).OpenConnector("test")
+	if err != nil {
+		t.Fatalf("OpenConnector() error = %v", err)
+	}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db, capture
+}
+
+func TestWrap_LogsStatements(t *testing.T) {
+	db, capture := newTestDB(t)
+
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "alice"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	rows, err := db.Query("SELECT id FROM users WHERE name = ?", "alice")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	rows.Close()
+
+	if capture.Len() != 2 {
+		t.Fatalf("Nên ghi 2 log entry, got %d: %v", capture.Len(), capture.Entries())
+	}
+	for _, want := range []string{"[SQL] sql exec", "op=exec", `query="INSERT INTO users(name) VALUES (?)"`, "args=[alice]", "duration=", "trace_id="} {
+		if !strings.Contains(capture.Entries()[0].Message, want) {
+			t.Errorf("Log exec thiếu %q, got %q", want, capture.Entries()[0].Message)
+		}
+	}
+	if !strings.Contains(capture.Entries()[1].Message, "op=query") {
+		t.Errorf("Log query thiếu op=query, got %q", capture.Entries()[1].Message)
+	}
+	if capture.Entries()[0].Level != handler.DebugLevel {
+		t.Errorf("Câu lệnh thành công nên log ở DebugLevel, got %v", capture.Entries()[0].Level)
+	}
+
+	traceID := func(msg string) string {
+		_, after, _ := strings.Cut(msg, "trace_id=")
+		id, _, _ := strings.Cut(after, " ")
+		return id
+	}
+	if id := traceID(capture.Entries()[0].Message); len(id) != 16 || id == traceID(capture.Entries()[1].Message) {
+		t.Errorf("Mỗi câu lệnh nên có trace ID riêng, got %q và %q", id, traceID(capture.Entries()[1].Message))
+	}
+}
+
+func TestWrap_LogsErrors(t *testing.T) {
+	db, capture := newTestDB(t)
+
+	if _, err := db.Exec("FAIL INSERT", 1); err == nil {
+		t.Fatal("Exec() nên trả về lỗi của driver")
+	}
+	if _, err := db.Exec("BROKEN SQL"); err == nil {
+		t.Fatal("Exec() nên trả về lỗi prepare của driver")
+	}
+
+	if capture.Len() != 2 {
+		t.Fatalf("Nên ghi 2 log entry, got %d: %v", capture.Len(), capture.Entries())
+	}
+	if capture.Entries()[0].Level != handler.ErrorLevel || !strings.Contains(capture.Entries()[0].Message, `error="constraint violation"`) {
+		t.Errorf("Câu lệnh thất bại nên log ở ErrorLevel kèm lỗi, got %v %q", capture.Entries()[0].Level, capture.Entries()[0].Message)
+	}
+	if !strings.Contains(capture.Entries()[1].Message, "op=prepare") {
+		t.Errorf("Prepare thất bại nên được ghi log, got %q", capture.Entries()[1].Message)
+	}
+}
+
+func TestWrap_RedactedArgsAndTx(t *testing.T) {
+	db, capture := newTestDB(t, WithRedactedArgs(), WithLevel(handler.InfoLevel))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if _, err := tx.Exec("UPDATE users SET password = ?", "s3cret"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	var messages []string
+	for _, entry := range capture.Entries() {
+		messages = append(messages, entry.Message)
+	}
+	all := strings.Join(messages, "\n")
+	if strings.Contains(all, "s3cret") {
+		t.Errorf("Tham số không nên xuất hiện khi bật WithRedactedArgs, got %q", all)
+	}
+	if !strings.Contains(all, "args=[[REDACTED]]") {
+		t.Errorf("Tham số nên được thay bằng [REDACTED], got %q", all)
+	}
+	for _, op := range []string{"op=begin", "op=exec", "op=commit"} {
+		if !strings.Contains(all, op) {
+			t.Errorf("Thiếu log cho %s, got %q", op, all)
+		}
+	}
+	for _, entry := range capture.Entries() {
+		if entry.Level != handler.InfoLevel {
+			t.Errorf("WithLevel nên áp dụng cho câu lệnh thành công, got %v", entry.Level)
+		}
+	}
+}
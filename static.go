@@ -0,0 +1,144 @@
+package log
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// staticFields trả về các field theo Config.Fields, đã sắp xếp theo key.
+func (c *Config) staticFields() []Field {
+	keys := make([]string, 0, len(c.Fields))
+	for key := range c.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, len(keys))
+	for i, key := range keys {
+		fields[i] = String(key, c.Fields[key])
+	}
+	return fields
+}
+
+// fieldsString trả về mô tả ngắn gọn của Config.Fields, VD: "env=prod,service=orders".
+func (c *Config) fieldsString() string {
+	fields := c.staticFields()
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Key + "=" + f.Str
+	}
+	return strings.Join(parts, ",")
+}
+
+// validateFields kiểm tra các key của Config.Fields.
+func (c *Config) validateFields() error {
+	for key, value := range c.Fields {
+		if key == "" || strings.ContainsAny(key, " =\t\r\n") {
+			return &ConfigError{
+				Field:   "fields",
+				Value:   key + "=" + value,
+				Message: "field key must be non-empty and must not contain whitespace or '='",
+			}
+		}
+	}
+	return nil
+}
+
+// WithFields gắn các field cố định vào mọi entry của logger, trừ entry đã có field cùng key.
+//
+// Tham số:
+//   - fields: ...Field - các field cố định (VD: tên service, phiên bản, môi trường)
+//
+// Trả về:
+//   - LoggerOption: tùy chọn gắn field cố định
+//
+// Ví dụ:
+//
+//	logger := log.NewLogger("API", log.WithFields(log.String("service", "orders"), log.String("env", "prod")))
+func WithFields(fields ...Field) LoggerOption {
+	return func(l *logger) {
+		l.staticFields = mergeStaticFields(l.staticFields, fields)
+	}
+}
+
+// setStaticFields thay thế các field cố định của logger. Method này là thread-safe.
+//
+// Tham số:
+//   - fields: []Field - danh sách mới, không được sửa sau khi truyền vào
+func (l *logger) setStaticFields(fields []Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.staticFields = fields
+	l.publish()
+}
+
+// WithFields đăng ký các field cố định cho toàn bộ triển khai (VD: tên service, phiên bản, môi
+// trường, region), được gắn vào mọi entry của mọi logger do manager tạo, kể cả logger tạo sau.
+// Field cùng key với một field đã đăng ký hoặc trong Config.Fields thay thế giá trị cũ; entry đã
+// có field cùng key giữ giá trị của entry. Method này là thread-safe.
+//
+// Tham số:
+//   - fields: ...Field - các field cố định
+//
+// Ví dụ:
+//
+//	manager.WithFields(log.String("service", "orders"), log.String("version", version))
+//	logger.Info("Started") // [App] Started service=orders version=1.4.2
+func (m *manager) WithFields(fields ...Field) {
+	if len(fields) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.staticFields = mergeStaticFields(m.staticFields, fields)
+	static := m.loggerStaticFields(m.config)
+	for _, lg := range m.loggers {
+		if l, ok := lg.(*logger); ok {
+			l.setStaticFields(static)
+		}
+	}
+}
+
+// loggerStaticFields trả về các field cố định của logger theo Config.Fields và các field đăng ký
+// qua WithFields. Phải được gọi khi đang giữ m.mu.
+func (m *manager) loggerStaticFields(config *Config) []Field {
+	return mergeStaticFields(config.staticFields(), m.staticFields)
+}
+
+// mergeStaticFields trả về danh sách mới gồm fields rồi extra; field của extra thay thế field
+// cùng key của fields tại chỗ.
+func mergeStaticFields(fields, extra []Field) []Field {
+	merged := make([]Field, 0, len(fields)+len(extra))
+	merged = append(merged, fields...)
+	for _, f := range extra {
+		if i := fieldIndex(merged, f.Key); i >= 0 {
+			merged[i] = f
+		} else {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// withStaticFields thêm các field cố định chưa có trong fields vào cuối fields.
+func withStaticFields(fields, static []Field) []Field {
+	if len(static) == 0 {
+		return fields
+	}
+	n := len(fields)
+	for _, s := range static {
+		if fieldIndex(fields[:n], s.Key) < 0 {
+			fields = append(fields[:len(fields):len(fields)], s)
+		}
+	}
+	return fields
+}
+
+// fieldIndex trả về vị trí của field đầu tiên có key trong fields, -1 nếu không có.
+func fieldIndex(fields []Field, key string) int {
+	return slices.IndexFunc(fields, func(f Field) bool { return f.Key == key })
+}
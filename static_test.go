@@ -0,0 +1,71 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestManager_WithFields(t *testing.T) {
+	config := createTestConfig()
+	config.MaxFields = 1
+	config.Fields = map[string]string{"service": "orders", "env": "prod"}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	m := NewManager(config)
+	defer m.Close()
+
+	before := m.GetLogger("Order")
+	h := &entryHandler{}
+	before.AddHandler(TestHandlerType, h)
+
+	before.Info("created", Int("order_id", 42), String("currency", "VND"))
+	if got := h.entry.Message; got != "[Order] created order_id=42 fields_truncated=1 env=prod service=orders" {
+		t.Errorf("Field cố định nên được gắn theo thứ tự key sau khi cắt field, got %q", got)
+	}
+
+	m.WithFields(String("version", "1.4.2"), String("env", "staging"))
+	before.Info("created", String("service", "billing"))
+	if got := h.entry.Message; got != "[Order] created service=billing env=staging version=1.4.2" {
+		t.Errorf("WithFields() nên cập nhật logger đã tồn tại và field của entry được ưu tiên, got %q", got)
+	}
+
+	after := m.GetLogger("Worker")
+	w := &entryHandler{}
+	after.AddHandler(TestHandlerType, w)
+	after.Info("started")
+	if got := w.entry.Message; got != "[Worker] started env=staging service=orders version=1.4.2" {
+		t.Errorf("Logger tạo sau nên có field cố định, got %q", got)
+	}
+
+	updated := *config
+	updated.Fields = map[string]string{"service": "orders"}
+	diff, err := m.ApplyConfig(&updated, false)
+	if err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if !strings.Contains(diff.String(), "fields") {
+		t.Errorf("Diff nên liệt kê thay đổi của fields, got %q", diff.String())
+	}
+	after.Info("stopped")
+	if got := w.entry.Message; got != "[Worker] stopped service=orders version=1.4.2 env=staging" {
+		t.Errorf("ApplyConfig() nên giữ field đăng ký qua WithFields, got %q", got)
+	}
+
+	invalid := *createTestConfig()
+	invalid.Fields = map[string]string{"service name": "orders"}
+	if err, ok := invalid.Validate().(*ConfigError); !ok || err.Field != "fields" {
+		t.Errorf("Validate() nên từ chối key chứa khoảng trắng, got %v", err)
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	l := NewLogger("API", WithFields(String("service", "orders")), WithFields(String("service", "billing")))
+	h := &entryHandler{}
+	l.AddHandler(TestHandlerType, h)
+
+	l.Info("ok")
+	if len(h.entry.Fields) != 1 || h.entry.Fields[0].Str != "billing" {
+		t.Errorf("WithFields() sau nên thay thế field cùng key, got %+v", h.entry.Fields)
+	}
+}
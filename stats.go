@@ -0,0 +1,90 @@
+package log
+
+import (
+	"expvar"
+
+	"go.fork.vn/log/handler"
+)
+
+// Stats là ảnh chụp các số liệu nội bộ của manager, dùng để kiểm tra nhanh mà không cần
+// Prometheus (xem Manager.Stats và PublishExpvar).
+type Stats struct {
+	Entries  uint64                       `json:"entries"`  // Tổng số entry được gửi đến handler
+	Errors   uint64                       `json:"errors"`   // Tổng số lần handler ghi thất bại (ErrorCount)
+	Loggers  int                          `json:"loggers"`  // Số logger đã được tạo qua GetLogger
+	Handlers map[HandlerType]HandlerStats `json:"handlers"` // Số liệu theo tên handler đang đăng ký
+}
+
+// HandlerStats là số liệu của một handler. Writes và Errors được đếm theo tên handler mà logger
+// ghi đến, nên handler thuộc stack được đếm dưới tên "stack"; các số liệu còn lại được đọc từ
+// chuỗi handler (xem handler.Counters), bằng 0 với handler không hỗ trợ.
+type HandlerStats struct {
+	Writes       uint64  `json:"writes"`        // Số entry logger đã gửi đến handler
+	Errors       uint64  `json:"errors"`        // Số lần gửi thất bại
+	BytesWritten uint64  `json:"bytes_written"` // Số byte đã ghi
+	Dropped      uint64  `json:"dropped"`       // Số entry bị bỏ (hàng đợi đầy, lấy mẫu)
+	Rotations    uint64  `json:"rotations"`     // Số lần xoay vòng file
+	QueueDepth   int     `json:"queue_depth"`   // Số entry đang chờ trong hàng đợi
+	Saturation   float64 `json:"saturation"`    // Tỷ lệ lấp đầy của hàng đợi, từ 0 đến 1
+}
+
+// Stats trả về ảnh chụp các số liệu nội bộ của manager. Method này là thread-safe.
+//
+// Trả về:
+//   - Stats: số liệu tại thời điểm gọi
+//
+// Ví dụ:
+//
+//	stats := manager.Stats()
+//	for name, h := range stats.Handlers {
+//	    fmt.Printf("%s: %d entry, %d byte, hàng đợi %.0f%%\n", name, h.Writes, h.BytesWritten, h.Saturation*100)
+//	}
+func (m *manager) Stats() Stats {
+	m.mu.RLock()
+	handlers := make(map[HandlerType]handler.Handler, len(m.handlers))
+	for handlerType, h := range m.handlers {
+		handlers[handlerType] = h
+	}
+	loggers := len(m.loggers)
+	m.mu.RUnlock()
+
+	stats := Stats{
+		Entries:  m.metrics.entries.Load(),
+		Errors:   m.errors.count.Load(),
+		Loggers:  loggers,
+		Handlers: make(map[HandlerType]HandlerStats, len(handlers)),
+	}
+	for handlerType, h := range handlers {
+		var hs HandlerStats
+		if c, ok := m.metrics.writes.Load(handlerType); ok {
+			hs.Writes = c.(*writeCounter).writes.Load()
+			hs.Errors = c.(*writeCounter).errors.Load()
+		}
+		// Các handler con của stack đã được đăng ký riêng với manager
+		if handlerType != HandlerTypeStack {
+			counters := handler.Counters(h)
+			hs.BytesWritten, hs.Dropped, hs.Rotations = counters.BytesWritten, counters.Dropped, counters.Rotations
+			hs.QueueDepth, hs.Saturation = counters.QueueDepth, handler.Saturation(h)
+		}
+		stats.Handlers[handlerType] = hs
+	}
+	return stats
+}
+
+// PublishExpvar công bố Stats của m qua expvar với tên đã cho, để xem tại /debug/vars khi
+// package expvar được import vào HTTP server. Stats được tính lại mỗi lần đọc. Như
+// expvar.Publish, hàm panic nếu tên đã được công bố.
+//
+// Tham số:
+//   - name: string - tên biến expvar, VD: "log"
+//   - m: Manager - manager cần công bố
+//
+// Ví dụ:
+//
+//	log.PublishExpvar("log", manager)
+//	http.ListenAndServe(":6060", nil) // GET /debug/vars
+func PublishExpvar(name string, m Manager) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return m.Stats()
+	}))
+}
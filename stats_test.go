@@ -0,0 +1,53 @@
+package log
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestManager_Stats(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	m.SetErrorHandler(func(HandlerType, error) {})
+	m.AddHandler("remote", &MockHandler{ShouldError: true})
+
+	l := m.GetLogger("Order")
+	l.Info("created")
+	l.Error("failed")
+	if err := m.RotateAll(); err != nil {
+		t.Fatalf("RotateAll() error = %v", err)
+	}
+
+	stats := m.Stats()
+	if stats.Entries != 2 || stats.Errors != 2 || stats.Loggers != 1 {
+		t.Errorf("Stats() = entries %d, errors %d, loggers %d, want 2, 2, 1", stats.Entries, stats.Errors, stats.Loggers)
+	}
+	file := stats.Handlers[HandlerTypeFile]
+	if file.Writes != 2 || file.Errors != 0 || file.BytesWritten == 0 || file.Rotations != 1 {
+		t.Errorf("Số liệu của file handler không đúng, got %+v", file)
+	}
+	if remote := stats.Handlers["remote"]; remote.Writes != 2 || remote.Errors != 2 {
+		t.Errorf("Số liệu của remote nên đếm 2 lần ghi thất bại, got %+v", remote)
+	}
+	if access, ok := stats.Handlers[ChannelHandlerType(ChannelAccess)]; !ok || access.Writes != 0 {
+		t.Errorf("Handler chưa được ghi vẫn nên có trong Stats, got %+v", access)
+	}
+}
+
+func TestPublishExpvar(t *testing.T) {
+	config := newChannelTestConfig(t)
+	m := NewManager(config)
+	defer m.Close()
+	m.GetLogger("Order").Info("created")
+
+	PublishExpvar("log_test_stats", m)
+	var stats Stats
+	if err := json.Unmarshal([]byte(expvar.Get("log_test_stats").String()), &stats); err != nil {
+		t.Fatalf("Giá trị expvar nên là JSON của Stats: %v", err)
+	}
+	if stats.Entries != 1 || stats.Handlers[HandlerTypeFile].Writes != 1 {
+		t.Errorf("Stats qua expvar không đúng, got %+v", stats)
+	}
+}
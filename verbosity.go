@@ -0,0 +1,156 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"go.fork.vn/log/handler"
+)
+
+// Level trả về cấp độ log chung của manager (Config.Level), áp dụng cho các context không có
+// cấp độ riêng trong Contexts hoặc Channels. Method này là thread-safe.
+//
+// Trả về:
+//   - handler.Level: cấp độ log chung hiện tại
+func (m *manager) Level() handler.Level {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.Level
+}
+
+// SetLevel thay đổi cấp độ log chung của manager và áp dụng ngay cho mọi logger không có cấp độ
+// riêng trong Contexts hoặc Channels, kể cả logger tạo sau. Context đang được nâng cấp độ tạm
+// thời (xem ElevateLevel) khôi phục về cấp độ mới khi hết hạn. Mỗi lần thay đổi được ghi một bản
+// ghi kiểm toán qua logger của AuditContext. ApplyConfig sau đó đặt lại cấp độ theo cấu hình mới.
+// Method này là thread-safe.
+//
+// Tham số:
+//   - level: handler.Level - cấp độ log chung mới
+//
+// Trả về:
+//   - error: lỗi nếu cấp độ không hợp lệ
+//
+// Ví dụ:
+//
+//	if err := manager.SetLevel(handler.DebugLevel); err != nil {
+//	    return err
+//	}
+func (m *manager) SetLevel(level handler.Level) error {
+	if level < handler.DebugLevel || level > handler.FatalLevel {
+		return fmt.Errorf("invalid log level: %d", level)
+	}
+
+	m.mu.Lock()
+	previous := m.config.Level
+	if previous == level {
+		m.mu.Unlock()
+		return nil
+	}
+	// Sao chép cấu hình để không sửa Config của bên gọi NewManager hoặc ApplyConfig
+	config := *m.config
+	config.Level = level
+	m.config = &config
+	for context, lg := range m.loggers {
+		if e := m.elevated[context]; e != nil {
+			e.previous = levelOf(&config, context)
+		} else {
+			lg.SetMinLevel(levelOf(&config, context))
+		}
+	}
+	m.mu.Unlock()
+
+	if l, ok := m.GetLogger(AuditContext).(*logger); ok {
+		l.audit("log level changed", Any("level", level), Any("previous", previous))
+	}
+	return nil
+}
+
+// moreVerbose trả về cấp độ chi tiết hơn một bậc trong Debug, Info, Warning: Warning trở lên
+// thành Warning rồi Info, Info thành Debug.
+func moreVerbose(level handler.Level) handler.Level {
+	switch {
+	case level > handler.WarningLevel:
+		return handler.WarningLevel
+	case level > handler.DebugLevel:
+		return level - 1
+	default:
+		return handler.DebugLevel
+	}
+}
+
+// lessVerbose trả về cấp độ ít chi tiết hơn một bậc trong Debug, Info, Warning; cấp độ từ
+// Warning trở lên được giữ nguyên.
+func lessVerbose(level handler.Level) handler.Level {
+	if level < handler.WarningLevel {
+		return level + 1
+	}
+	return level
+}
+
+// VerbosityOnSignal thay đổi cấp độ log chung của m mỗi khi tiến trình nhận signal: more giảm
+// cấp độ một bậc (Warning → Info → Debug) để ghi chi tiết hơn, less tăng cấp độ một bậc (Debug →
+// Info → Warning). Người vận hành bật debug log khi điều tra sự cố trên production mà không cần
+// deploy lại, VD: "kill -USR1 <pid>". Mỗi lần thay đổi được ghi lại (xem Manager.SetLevel); lỗi
+// được ghi ra stderr.
+//
+// Signal nil dùng mặc định SIGUSR1 (more) và SIGUSR2 (less). Các signal này không tồn tại trên
+// Windows, nên ở đó chỉ các signal được chỉ định mới được theo dõi.
+//
+// Tham số:
+//   - m: Manager - manager cần thay đổi cấp độ
+//   - more: os.Signal - signal giảm cấp độ, nil để dùng SIGUSR1
+//   - less: os.Signal - signal tăng cấp độ, nil để dùng SIGUSR2
+//
+// Trả về:
+//   - func(): hàm ngừng theo dõi signal
+//
+// Ví dụ:
+//
+//	stop := log.VerbosityOnSignal(manager, nil, nil)
+//	defer stop()
+func VerbosityOnSignal(m Manager, more, less os.Signal) (stop func()) {
+	if more == nil {
+		more = defaultMoreVerboseSignal
+	}
+	if less == nil {
+		less = defaultLessVerboseSignal
+	}
+	var signals []os.Signal
+	for _, sig := range []os.Signal{more, less} {
+		if sig != nil {
+			signals = append(signals, sig)
+		}
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				level := lessVerbose(m.Level())
+				if sig == more {
+					level = moreVerbose(m.Level())
+				}
+				if err := m.SetLevel(level); err != nil {
+					fmt.Fprintf(os.Stderr, "Lỗi khi thay đổi cấp độ log: %v\n", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
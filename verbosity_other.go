@@ -0,0 +1,11 @@
+//go:build !unix
+
+package log
+
+import "os"
+
+// Nền tảng này không có SIGUSR1 và SIGUSR2 nên VerbosityOnSignal không có signal mặc định.
+var (
+	defaultMoreVerboseSignal os.Signal
+	defaultLessVerboseSignal os.Signal
+)
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"testing"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestManager_SetLevel(t *testing.T) {
+	config := createTestConfig()
+	config.Contexts = map[string]ContextConfig{"Payment": {Level: "error"}}
+	m := NewManager(config)
+	defer m.Close()
+
+	mem := handler.NewMemoryHandler()
+	m.AddHandler("memory", mem)
+	order := m.GetLogger("Order")
+	payment := m.GetLogger("Payment")
+
+	if err := m.SetLevel(handler.DebugLevel); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if m.Level() != handler.DebugLevel || config.Level != handler.InfoLevel {
+		t.Errorf("SetLevel() nên đổi cấp độ chung mà không sửa Config của bên gọi, got %v / %v", m.Level(), config.Level)
+	}
+	order.Debug("order debug")
+	payment.Warning("payment warning")
+	m.GetLogger("Later").Debug("later debug")
+	if !mem.Contains("order debug") || !mem.Contains("later debug") {
+		t.Error("SetLevel() nên áp dụng cho logger đã tạo và logger tạo sau")
+	}
+	if mem.Contains("payment warning") {
+		t.Error("Context có cấp độ riêng nên giữ cấp độ của nó")
+	}
+	if !mem.Contains("log level changed") {
+		t.Errorf("SetLevel() nên ghi lại thay đổi cấp độ, got %v", mem.Entries())
+	}
+
+	if err := m.SetLevel(handler.Level(42)); err == nil {
+		t.Error("SetLevel() nên từ chối cấp độ không hợp lệ")
+	}
+}
+
+func TestVerbositySteps(t *testing.T) {
+	tests := []struct {
+		level      handler.Level
+		more, less handler.Level
+	}{
+		{handler.DebugLevel, handler.DebugLevel, handler.InfoLevel},
+		{handler.InfoLevel, handler.DebugLevel, handler.WarningLevel},
+		{handler.WarningLevel, handler.InfoLevel, handler.WarningLevel},
+		{handler.ErrorLevel, handler.WarningLevel, handler.ErrorLevel},
+	}
+	for _, tt := range tests {
+		if got := moreVerbose(tt.level); got != tt.more {
+			t.Errorf("moreVerbose(%v) = %v, want %v", tt.level, got, tt.more)
+		}
+		if got := lessVerbose(tt.level); got != tt.less {
+			t.Errorf("lessVerbose(%v) = %v, want %v", tt.level, got, tt.less)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signal mặc định của VerbosityOnSignal.
+var (
+	defaultMoreVerboseSignal os.Signal = syscall.SIGUSR1
+	defaultLessVerboseSignal os.Signal = syscall.SIGUSR2
+)
@@ -0,0 +1,40 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.fork.vn/log/handler"
+)
+
+func TestVerbosityOnSignal(t *testing.T) {
+	m := NewManager(createTestConfig())
+	defer m.Close()
+
+	stop := VerbosityOnSignal(m, nil, nil)
+	defer stop()
+	process, _ := os.FindProcess(os.Getpid())
+
+	waitLevel := func(sig os.Signal, want handler.Level) {
+		t.Helper()
+		if err := process.Signal(sig); err != nil {
+			t.Fatalf("Signal(%v) error = %v", sig, err)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for m.Level() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("%v nên đổi cấp độ thành %v, got %v", sig, want, m.Level())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitLevel(syscall.SIGUSR1, handler.DebugLevel)
+	waitLevel(syscall.SIGUSR2, handler.InfoLevel)
+	waitLevel(syscall.SIGUSR2, handler.WarningLevel)
+	stop()
+	stop() // Gọi lại stop không panic
+}